@@ -5,17 +5,37 @@ import (
 	"github.com/openshift/osdctl/internal/utils/globalflags"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // NewCmdList implements the list command
 func NewCmdList(streams genericclioptions.IOStreams, client client.Client, globalOpts *globalflags.GlobalOptions) *cobra.Command {
+	byOUOps := newListByOUOptions(streams, client)
 	listCmd := &cobra.Command{
-		Use:               "list",
-		Short:             "List resources",
+		Use:   "list",
+		Short: "List resources",
+		Long: `List resources.
+
+With --by-ou, the AWS accounts of the organization of a payer account are listed per organizational unit, with their
+tags and the claim status of their Account CR in the account pool.`,
+		Example: `#Browse the organization of the payer account
+osdctl account list --by-ou -p osd-staging-2
+
+#Only the accounts under an OU, without fetching the tags
+osdctl account list --by-ou -p osd-staging-2 --ou ou-rs3h-ry0hn2l9 --no-tags`,
 		Args:              cobra.NoArgs,
 		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !byOUOps.byOU {
+				help(cmd, args)
+				return
+			}
+			cmdutil.CheckErr(byOUOps.complete(cmd))
+			cmdutil.CheckErr(byOUOps.run())
+		},
 	}
+	byOUOps.addByOUFlags(listCmd)
 
 	listCmd.AddCommand(newCmdListAccount(streams, client, globalOpts))
 	listCmd.AddCommand(newCmdListAccountClaim(streams, client, globalOpts))
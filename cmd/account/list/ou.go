@@ -0,0 +1,244 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+)
+
+// listByOUOptions defines the struct for running list --by-ou
+type listByOUOptions struct {
+	byOU             bool
+	payerAccount     string
+	parentID         string
+	accountNamespace string
+	noTags           bool
+
+	awsClient awsprovider.Client
+	genericclioptions.IOStreams
+	kubeCli client.Client
+}
+
+func newListByOUOptions(streams genericclioptions.IOStreams, client client.Client) *listByOUOptions {
+	return &listByOUOptions{
+		IOStreams: streams,
+		kubeCli:   client,
+	}
+}
+
+// addByOUFlags adds the flags browsing the AWS Organizations structure of the account pool to the list command
+func (o *listByOUOptions) addByOUFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.byOU, "by-ou", false, "List the AWS accounts of the payer account grouped by organizational unit, with their tags and claim status")
+	cmd.Flags().StringVarP(&o.payerAccount, "payer-account", "p", "", "AWS profile of the payer account owning the organization")
+	cmd.Flags().StringVar(&o.parentID, "ou", "", "Only list the accounts under this organizational unit, defaults to the root of the organization")
+	cmd.Flags().StringVar(&o.accountNamespace, "account-namespace", common.AWSAccountNamespace,
+		"The namespace of the Account CRs the claim status is read from")
+	cmd.Flags().BoolVar(&o.noTags, "no-tags", false, "Don't fetch the tags of the accounts, which takes a request per account")
+}
+
+func (o *listByOUOptions) complete(cmd *cobra.Command) error {
+	if o.payerAccount == "" {
+		return cmdutil.UsageErrorf(cmd, "--payer-account is required with --by-ou")
+	}
+	return nil
+}
+
+// organizationalUnit is an OU of the organization, with the accounts directly under it
+type organizationalUnit struct {
+	ID       string
+	Name     string
+	Path     string
+	Accounts []poolAccount
+}
+
+// poolAccount is an AWS account of the organization, and its Account CR in the account pool if any
+type poolAccount struct {
+	ID     string
+	Name   string
+	Status string
+	Tags   map[string]string
+	CR     *awsv1alpha1.Account
+}
+
+func (o *listByOUOptions) run() error {
+	awsClient, err := awsprovider.NewAwsClient(o.payerAccount, "us-east-1", "")
+	if err != nil {
+		return err
+	}
+	o.awsClient = awsClient
+
+	parentID, parentName := o.parentID, o.parentID
+	if parentID == "" {
+		roots, err := o.awsClient.ListRoots(&organizations.ListRootsInput{})
+		if err != nil {
+			return fmt.Errorf("failed to get the root of the organization: %w", err)
+		}
+		if len(roots.Roots) == 0 {
+			return fmt.Errorf("the organization of %s has no root", o.payerAccount)
+		}
+		parentID, parentName = *roots.Roots[0].Id, "Root"
+	}
+
+	units, err := o.walkOrganizationalUnits(parentID, parentName, parentName)
+	if err != nil {
+		return err
+	}
+	o.attachAccountCRs(units)
+	return o.printOrganizationalUnits(units)
+}
+
+// walkOrganizationalUnits returns the OU and all the OUs below it, depth first
+func (o *listByOUOptions) walkOrganizationalUnits(id string, name string, path string) ([]organizationalUnit, error) {
+	unit := organizationalUnit{ID: id, Name: name, Path: path}
+	accounts, err := o.listAccountsForParent(id)
+	if err != nil {
+		return nil, err
+	}
+	unit.Accounts = accounts
+	units := []organizationalUnit{unit}
+
+	var nextToken *string
+	for {
+		children, err := o.awsClient.ListOrganizationalUnitsForParent(&organizations.ListOrganizationalUnitsForParentInput{
+			ParentId:  &id,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the organizational units of %s: %w", id, err)
+		}
+		for _, child := range children.OrganizationalUnits {
+			childName := *child.Id
+			if child.Name != nil {
+				childName = *child.Name
+			}
+			childUnits, err := o.walkOrganizationalUnits(*child.Id, childName, path+"/"+childName)
+			if err != nil {
+				return nil, err
+			}
+			units = append(units, childUnits...)
+		}
+		if children.NextToken == nil {
+			return units, nil
+		}
+		nextToken = children.NextToken
+	}
+}
+
+func (o *listByOUOptions) listAccountsForParent(parentID string) ([]poolAccount, error) {
+	var accounts []poolAccount
+	var nextToken *string
+	for {
+		output, err := o.awsClient.ListAccountsForParent(&organizations.ListAccountsForParentInput{
+			ParentId:  &parentID,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the accounts of %s: %w", parentID, err)
+		}
+		for _, a := range output.Accounts {
+			account := poolAccount{ID: *a.Id, Status: string(a.Status)}
+			if a.Name != nil {
+				account.Name = *a.Name
+			}
+			if !o.noTags {
+				if account.Tags, err = o.listAccountTags(account.ID); err != nil {
+					return nil, err
+				}
+			}
+			accounts = append(accounts, account)
+		}
+		if output.NextToken == nil {
+			return accounts, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+func (o *listByOUOptions) listAccountTags(accountID string) (map[string]string, error) {
+	tags := map[string]string{}
+	var nextToken *string
+	for {
+		output, err := o.awsClient.ListTagsForResource(&organizations.ListTagsForResourceInput{
+			ResourceId: &accountID,
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the tags of %s: %w", accountID, err)
+		}
+		for _, tag := range output.Tags {
+			tags[*tag.Key] = *tag.Value
+		}
+		if output.NextToken == nil {
+			return tags, nil
+		}
+		nextToken = output.NextToken
+	}
+}
+
+// attachAccountCRs matches the accounts with the Account CRs of the pool, which hold their claim status.
+// The AWS side is still worth listing without a connection to the cluster running the operator.
+func (o *listByOUOptions) attachAccountCRs(units []organizationalUnit) {
+	var accountCRs awsv1alpha1.AccountList
+	if err := o.kubeCli.List(context.TODO(), &accountCRs, &client.ListOptions{Namespace: o.accountNamespace}); err != nil {
+		fmt.Fprintf(o.ErrOut, "WARN: failed to list the Account CRs, the claim status is unknown: %v\n", err)
+		return
+	}
+	byAccountID := make(map[string]*awsv1alpha1.Account, len(accountCRs.Items))
+	for i := range accountCRs.Items {
+		byAccountID[accountCRs.Items[i].Spec.AwsAccountID] = &accountCRs.Items[i]
+	}
+	for i := range units {
+		for j := range units[i].Accounts {
+			units[i].Accounts[j].CR = byAccountID[units[i].Accounts[j].ID]
+		}
+	}
+}
+
+func (o *listByOUOptions) printOrganizationalUnits(units []organizationalUnit) error {
+	for _, unit := range units {
+		fmt.Fprintf(o.Out, "%s (%s): %d accounts\n", unit.Path, unit.ID, len(unit.Accounts))
+		if len(unit.Accounts) == 0 {
+			fmt.Fprintln(o.Out)
+			continue
+		}
+		p := printer.NewTablePrinter(o.Out, 20, 1, 3, ' ')
+		p.AddRow([]string{"AWS ACCOUNT ID", "NAME", "STATUS", "ACCOUNT CR", "STATE", "CLAIMED", "TAGS"})
+		for _, account := range unit.Accounts {
+			crName, state, claimed := "-", "-", "-"
+			if account.CR != nil {
+				crName = account.CR.Name
+				state = account.CR.Status.State
+				claimed = strconv.FormatBool(account.CR.Status.Claimed)
+			}
+			p.AddRow([]string{account.ID, account.Name, account.Status, crName, state, claimed, formatTags(account.Tags)})
+		}
+		if err := p.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out)
+	}
+	return nil
+}
+
+// formatTags formats the tags as key=value pairs sorted by key
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
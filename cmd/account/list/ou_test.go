@@ -0,0 +1,60 @@
+package list
+
+import (
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/golang/mock/gomock"
+
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+)
+
+func TestWalkOrganizationalUnits(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAWSClient := mock.NewMockClient(mockCtrl)
+
+	children := map[string][]organizationTypes.OrganizationalUnit{
+		"r-rs3h":   {{Id: awsSdk.String("ou-pool"), Name: awsSdk.String("pool")}},
+		"ou-pool":  {{Id: awsSdk.String("ou-users"), Name: awsSdk.String("users")}},
+		"ou-users": nil,
+	}
+	accounts := map[string][]organizationTypes.Account{
+		"r-rs3h":   nil,
+		"ou-pool":  {{Id: awsSdk.String("111111111111"), Name: awsSdk.String("osd-creds-mgmt-1"), Status: organizationTypes.AccountStatusActive}},
+		"ou-users": {{Id: awsSdk.String("222222222222"), Name: awsSdk.String("osd-creds-mgmt-2"), Status: organizationTypes.AccountStatusSuspended}},
+	}
+	mockAWSClient.EXPECT().ListOrganizationalUnitsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListOrganizationalUnitsForParentInput) (*organizations.ListOrganizationalUnitsForParentOutput, error) {
+		return &organizations.ListOrganizationalUnitsForParentOutput{OrganizationalUnits: children[*input.ParentId]}, nil
+	}).Times(3)
+	mockAWSClient.EXPECT().ListAccountsForParent(gomock.Any()).DoAndReturn(func(input *organizations.ListAccountsForParentInput) (*organizations.ListAccountsForParentOutput, error) {
+		return &organizations.ListAccountsForParentOutput{Accounts: accounts[*input.ParentId]}, nil
+	}).Times(3)
+	mockAWSClient.EXPECT().ListTagsForResource(gomock.Any()).Return(&organizations.ListTagsForResourceOutput{
+		Tags: []organizationTypes.Tag{{Key: awsSdk.String("owner"), Value: awsSdk.String("tuser")}, {Key: awsSdk.String("claimed"), Value: awsSdk.String("true")}},
+	}, nil).Times(2)
+
+	o := &listByOUOptions{awsClient: mockAWSClient}
+	units, err := o.walkOrganizationalUnits("r-rs3h", "Root", "Root")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPaths := []string{"Root", "Root/pool", "Root/pool/users"}
+	if len(units) != len(wantPaths) {
+		t.Fatalf("walkOrganizationalUnits() returned %d OUs, want %d", len(units), len(wantPaths))
+	}
+	for i, unit := range units {
+		if unit.Path != wantPaths[i] {
+			t.Errorf("OU %d path = %s, want %s", i, unit.Path, wantPaths[i])
+		}
+	}
+	users := units[2].Accounts
+	if len(users) != 1 || users[0].ID != "222222222222" || users[0].Status != "SUSPENDED" {
+		t.Errorf("unexpected accounts of the users OU %+v", users)
+	}
+	if got := formatTags(users[0].Tags); got != "claimed=true,owner=tuser" {
+		t.Errorf("formatTags() = %s, want claimed=true,owner=tuser", got)
+	}
+}
@@ -0,0 +1,175 @@
+package mgmt
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type accountMoveOptions struct {
+	awsClient    awsprovider.Client
+	payerAccount string
+	accountIDs   []string
+	destination  string
+	yes          bool
+
+	genericclioptions.IOStreams
+}
+
+// accountMove is the move of an account from its current parent to the destination OU
+type accountMove struct {
+	accountID string
+	sourceID  string
+	err       error
+}
+
+func newAccountMoveOptions(streams genericclioptions.IOStreams) *accountMoveOptions {
+	return &accountMoveOptions{
+		IOStreams: streams,
+	}
+}
+
+// newCmdAccountMove moves accounts of the organization of a payer account to another organizational unit
+func newCmdAccountMove(streams genericclioptions.IOStreams) *cobra.Command {
+	ops := newAccountMoveOptions(streams)
+	accountMoveCmd := &cobra.Command{
+		Use:               "move",
+		Short:             "Move accounts to another organizational unit",
+		Long:              "Moves AWS accounts of the organization of the payer account to another organizational unit, after confirming the moves",
+		Example:           "osdctl account mgmt move -p osd-staging-2 -i 111111111111 -i 222222222222 --to-ou ou-rs3h-ry0hn2l9",
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete(cmd, args))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+	accountMoveCmd.Flags().StringVarP(&ops.payerAccount, "payer-account", "p", "", "Payer account type")
+	accountMoveCmd.Flags().StringSliceVarP(&ops.accountIDs, "account-id", "i", nil, "AWS account ID to move, can be repeated")
+	accountMoveCmd.Flags().StringVar(&ops.destination, "to-ou", "", "ID of the organizational unit (or root) to move the accounts to")
+	accountMoveCmd.Flags().BoolVarP(&ops.yes, "yes", "y", false, "Move the accounts without asking for confirmation")
+
+	return accountMoveCmd
+}
+
+func (o *accountMoveOptions) complete(cmd *cobra.Command, _ []string) error {
+	if o.payerAccount == "" {
+		return cmdutil.UsageErrorf(cmd, "Payer account was not provided")
+	}
+	if len(o.accountIDs) == 0 {
+		return cmdutil.UsageErrorf(cmd, "No account ID was provided")
+	}
+	if o.destination == "" {
+		return cmdutil.UsageErrorf(cmd, "The destination organizational unit was not provided")
+	}
+	return nil
+}
+
+func (o *accountMoveOptions) run() error {
+	awsClient, err := awsprovider.NewAwsClient(o.payerAccount, "us-east-1", "")
+	if err != nil {
+		return err
+	}
+	o.awsClient = awsClient
+
+	destinationName, err := o.parentName(o.destination)
+	if err != nil {
+		return err
+	}
+
+	moves, err := o.planMoves()
+	if err != nil {
+		return err
+	}
+	if len(moves) == 0 {
+		fmt.Fprintf(o.Out, "All the accounts are already in %s\n", destinationName)
+		return nil
+	}
+
+	p := printer.NewTablePrinter(o.Out, 20, 1, 3, ' ')
+	p.AddRow([]string{"AWS ACCOUNT ID", "FROM", "TO"})
+	for _, move := range moves {
+		sourceName, err := o.parentName(move.sourceID)
+		if err != nil {
+			return err
+		}
+		p.AddRow([]string{move.accountID, sourceName, destinationName})
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+	if !o.yes && !utils.ConfirmPrompt() {
+		return nil
+	}
+
+	failed := o.moveAccounts(moves)
+	for _, move := range moves {
+		if move.err != nil {
+			fmt.Fprintf(o.Out, "Failed to move %s: %v\n", move.accountID, move.err)
+			continue
+		}
+		fmt.Fprintf(o.Out, "Moved %s to %s\n", move.accountID, destinationName)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to move %d accounts", failed)
+	}
+	return nil
+}
+
+// planMoves looks up the current parent of the accounts, the ones already in the destination are left out
+func (o *accountMoveOptions) planMoves() ([]*accountMove, error) {
+	var moves []*accountMove
+	for _, accountID := range o.accountIDs {
+		accountID := accountID
+		parents, err := o.awsClient.ListParents(&organizations.ListParentsInput{ChildId: &accountID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the parent of %s: %w", accountID, err)
+		}
+		if len(parents.Parents) == 0 {
+			return nil, fmt.Errorf("account %s has no parent in the organization", accountID)
+		}
+		sourceID := *parents.Parents[0].Id
+		if sourceID == o.destination {
+			continue
+		}
+		moves = append(moves, &accountMove{accountID: accountID, sourceID: sourceID})
+	}
+	return moves, nil
+}
+
+// moveAccounts moves the accounts one at a time so a failure doesn't stop the others, and returns the
+// number of failures
+func (o *accountMoveOptions) moveAccounts(moves []*accountMove) int {
+	failed := 0
+	for _, move := range moves {
+		_, move.err = o.awsClient.MoveAccount(&organizations.MoveAccountInput{
+			AccountId:           &move.accountID,
+			SourceParentId:      &move.sourceID,
+			DestinationParentId: &o.destination,
+		})
+		if move.err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// parentName returns the name of an OU along with its ID, roots are named after their ID
+func (o *accountMoveOptions) parentName(parentID string) (string, error) {
+	if len(parentID) < 3 || parentID[:3] != "ou-" {
+		return parentID, nil
+	}
+	output, err := o.awsClient.DescribeOrganizationalUnit(&organizations.DescribeOrganizationalUnitInput{OrganizationalUnitId: &parentID})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe the organizational unit %s: %w", parentID, err)
+	}
+	if output.OrganizationalUnit == nil || output.OrganizationalUnit.Name == nil {
+		return parentID, nil
+	}
+	return fmt.Sprintf("%s (%s)", *output.OrganizationalUnit.Name, parentID), nil
+}
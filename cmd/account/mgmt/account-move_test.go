@@ -0,0 +1,55 @@
+package mgmt
+
+import (
+	"fmt"
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/golang/mock/gomock"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+)
+
+func TestMoveAccounts(t *testing.T) {
+	mocks := setupDefaultMocks(t)
+	mockAWSClient := mock.NewMockClient(mocks.mockCtrl)
+
+	parents := map[string]string{
+		"111111111111": "r-rs3h",
+		"222222222222": "ou-rs3h-ry0hn2l9",
+		"333333333333": "ou-rs3h-other",
+	}
+	mockAWSClient.EXPECT().ListParents(gomock.Any()).DoAndReturn(func(input *organizations.ListParentsInput) (*organizations.ListParentsOutput, error) {
+		return &organizations.ListParentsOutput{Parents: []organizationTypes.Parent{{Id: awsSdk.String(parents[*input.ChildId])}}}, nil
+	}).Times(3)
+	mockAWSClient.EXPECT().MoveAccount(gomock.Any()).DoAndReturn(func(input *organizations.MoveAccountInput) (*organizations.MoveAccountOutput, error) {
+		if *input.DestinationParentId != "ou-rs3h-ry0hn2l9" {
+			t.Errorf("unexpected destination %s", *input.DestinationParentId)
+		}
+		if *input.AccountId == "333333333333" {
+			return nil, fmt.Errorf("AccessDeniedException")
+		}
+		return &organizations.MoveAccountOutput{}, nil
+	}).Times(2)
+
+	o := &accountMoveOptions{
+		awsClient:   mockAWSClient,
+		accountIDs:  []string{"111111111111", "222222222222", "333333333333"},
+		destination: "ou-rs3h-ry0hn2l9",
+	}
+	moves, err := o.planMoves()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the account already in the destination is left out
+	if len(moves) != 2 || moves[0].sourceID != "r-rs3h" || moves[1].sourceID != "ou-rs3h-other" {
+		t.Fatalf("unexpected moves %+v", moves)
+	}
+	if failed := o.moveAccounts(moves); failed != 1 {
+		t.Errorf("moveAccounts() = %d failures, want 1", failed)
+	}
+	if moves[0].err != nil || moves[1].err == nil {
+		t.Errorf("unexpected results %v, %v", moves[0].err, moves[1].err)
+	}
+}
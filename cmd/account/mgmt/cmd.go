@@ -21,6 +21,7 @@ func NewCmdMgmt(streams genericclioptions.IOStreams, globalOpts *globalflags.Glo
 	mgmtCmd.AddCommand(newCmdAccountAssign(streams, globalOpts))
 	mgmtCmd.AddCommand(newCmdAccountUnassign(streams))
 	mgmtCmd.AddCommand(newCmdAccountIAM(streams, globalOpts))
+	mgmtCmd.AddCommand(newCmdAccountMove(streams))
 
 	return mgmtCmd
 }
@@ -94,16 +94,34 @@ func (o *rotateSecretOptions) complete(cmd *cobra.Command, args []string) error
 }
 
 func (o *rotateSecretOptions) run() error {
-
-	ctx := context.TODO()
-	var err error
-
 	// This action requires elevation
 	o.kubeCli.Impersonate("backplane-cluster-admin", o.reason, fmt.Sprintf("Elevation required to rotate secrets %s aws-account-cr-name", o.accountCRName))
 
+	return o.rotate(context.TODO(), o.kubeCli)
+}
+
+// RotateSecret rotates the IAM credentials of an Account CR like the rotate-secret command, with an elevated
+// client of the hive shard of the account
+func RotateSecret(hiveClient client.Client, accountCRName string, profile string, updateCcsCreds bool) error {
+	o := &rotateSecretOptions{
+		accountCRName:     accountCRName,
+		profile:           profile,
+		updateCcsCreds:    updateCcsCreds,
+		awsAccountTimeout: awsSdk.Int32(900),
+	}
+	if o.profile == "" {
+		o.profile = "default"
+	}
+	return o.rotate(context.TODO(), hiveClient)
+}
+
+// rotate creates new IAM credentials for the account and syncs them to hive and to the cluster
+func (o *rotateSecretOptions) rotate(ctx context.Context, kubeCli client.Client) error {
+	var err error
+
 	// Get the associated Account CR from the provided name
 	var accountID string
-	account, err := k8s.GetAWSAccount(ctx, o.kubeCli, common.AWSAccountNamespace, o.accountCRName)
+	account, err := k8s.GetAWSAccount(ctx, kubeCli, common.AWSAccountNamespace, o.accountCRName)
 	if err != nil {
 		return err
 	}
@@ -137,7 +155,7 @@ func (o *rotateSecretOptions) run() error {
 	if account.Spec.BYOC {
 		// Get the aws-account-operator configmap
 		cm := &corev1.ConfigMap{}
-		cmErr := o.kubeCli.Get(context.TODO(), types.NamespacedName{Namespace: common.AWSAccountNamespace, Name: common.DefaultConfigMap}, cm)
+		cmErr := kubeCli.Get(context.TODO(), types.NamespacedName{Namespace: common.AWSAccountNamespace, Name: common.DefaultConfigMap}, cm)
 		if cmErr != nil {
 			return fmt.Errorf("there was an error getting the ConfigMap to get the SRE Access Role %s", cmErr)
 		}
@@ -243,13 +261,13 @@ func (o *rotateSecretOptions) run() error {
 	}
 
 	// Update existing osdManagedAdmin secret
-	err = common.UpdateSecret(o.kubeCli, o.accountCRName+"-secret", common.AWSAccountNamespace, newOsdManagedAdminSecretData)
+	err = common.UpdateSecret(kubeCli, o.accountCRName+"-secret", common.AWSAccountNamespace, newOsdManagedAdminSecretData)
 	if err != nil {
 		return err
 	}
 
 	// Update secret in ClusterDeployment's namespace
-	err = common.UpdateSecret(o.kubeCli, "aws", account.Spec.ClaimLinkNamespace, newOsdManagedAdminSecretData)
+	err = common.UpdateSecret(kubeCli, "aws", account.Spec.ClaimLinkNamespace, newOsdManagedAdminSecretData)
 	if err != nil {
 		return err
 	}
@@ -261,7 +279,7 @@ func (o *rotateSecretOptions) run() error {
 		client.InNamespace(account.Spec.ClaimLinkNamespace),
 	}
 
-	err = o.kubeCli.List(ctx, clusterDeployments, listOpts...)
+	err = kubeCli.List(ctx, clusterDeployments, listOpts...)
 	if err != nil {
 		return err
 	}
@@ -301,13 +319,13 @@ func (o *rotateSecretOptions) run() error {
 		},
 	}
 	fmt.Println("Syncing AWS creds down to cluster.")
-	err = o.kubeCli.Create(ctx, syncSet)
+	err = kubeCli.Create(ctx, syncSet)
 	if err != nil {
 		return err
 	}
 
 	fmt.Printf("Watching Cluster Sync Status for deployment...")
-	hiveinternalv1alpha1.AddToScheme(o.kubeCli.Scheme())
+	hiveinternalv1alpha1.AddToScheme(kubeCli.Scheme())
 	searchStatus := &hiveinternalv1alpha1.ClusterSync{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cdName,
@@ -317,7 +335,7 @@ func (o *rotateSecretOptions) run() error {
 	foundStatus := &hiveinternalv1alpha1.ClusterSync{}
 	isSSSynced := false
 	for i := 0; i < 6; i++ {
-		err = o.kubeCli.Get(ctx, client.ObjectKeyFromObject(searchStatus), foundStatus)
+		err = kubeCli.Get(ctx, client.ObjectKeyFromObject(searchStatus), foundStatus)
 		if err != nil {
 			return err
 		}
@@ -344,7 +362,7 @@ func (o *rotateSecretOptions) run() error {
 	}
 
 	// Clean up the SS on hive
-	err = o.kubeCli.Delete(ctx, syncSet)
+	err = kubeCli.Delete(ctx, syncSet)
 	if err != nil {
 		return err
 	}
@@ -370,7 +388,7 @@ func (o *rotateSecretOptions) run() error {
 			}
 
 			// Update byoc secret with new creds
-			err = common.UpdateSecret(o.kubeCli, "byoc", account.Spec.ClaimLinkNamespace, newOsdCcsAdminSecretData)
+			err = common.UpdateSecret(kubeCli, "byoc", account.Spec.ClaimLinkNamespace, newOsdCcsAdminSecretData)
 			if err != nil {
 				return err
 			}
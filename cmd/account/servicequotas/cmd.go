@@ -18,6 +18,8 @@ func NewCmdServiceQuotas(streams genericclioptions.IOStreams) *cobra.Command {
 	}
 
 	baseCmd.AddCommand(newCmdDescribe())
+	baseCmd.AddCommand(newCmdList())
+	baseCmd.AddCommand(newCmdRequest())
 
 	return baseCmd
 }
@@ -0,0 +1,256 @@
+package servicequotas
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// quotaWarnPercent is the usage above which a quota is flagged
+const quotaWarnPercent = 80
+
+// osdQuota is a service quota OSD clusters commonly run into, along with how to measure its usage
+type osdQuota struct {
+	serviceCode string
+	quotaCode   string
+	name        string
+	usage       func(awsprovider.Client) (float64, error)
+}
+
+var osdQuotas = []osdQuota{
+	{"ec2", "L-1216C47A", "Running On-Demand Standard instances (vCPUs)", standardInstancesVCPUs},
+	{"ec2", "L-0263D0A3", "EC2-VPC Elastic IPs", elasticIPs},
+	{"vpc", "L-F678F1CE", "VPCs per Region", vpcs},
+	{"elasticloadbalancing", "L-E9E9831D", "Classic Load Balancers per Region", classicLoadBalancers},
+	{"elasticloadbalancing", "L-53DA6B97", "Application Load Balancers per Region", v2LoadBalancers(elbv2types.LoadBalancerTypeEnumApplication)},
+	{"elasticloadbalancing", "L-69A177A2", "Network Load Balancers per Region", v2LoadBalancers(elbv2types.LoadBalancerTypeEnumNetwork)},
+}
+
+// findOSDQuota returns the OSD quota with the given code, or nil
+func findOSDQuota(quotaCode string) *osdQuota {
+	for i := range osdQuotas {
+		if osdQuotas[i].quotaCode == quotaCode {
+			return &osdQuotas[i]
+		}
+	}
+	return nil
+}
+
+// newCmdList implements servicequotas list
+func newCmdList() *cobra.Command {
+	ops := &listOptions{}
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the usage of the service quotas OSD clusters depend on",
+		Long: `List the applied value and the current usage of the service quotas OSD clusters commonly run into
+(EC2 instances, Elastic IPs, VPCs and load balancers) in the region of the cluster. Quotas used at
+more than 80% are flagged.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	listCmd.Flags().StringVarP(&ops.clusterID, "clusterID", "C", "", "Cluster ID")
+	listCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
+	_ = listCmd.MarkFlagRequired("clusterID")
+
+	return listCmd
+}
+
+type listOptions struct {
+	clusterID  string
+	awsProfile string
+}
+
+func (o *listOptions) run() error {
+	awsClient, err := osdCloud.GenerateAWSClientForCluster(o.awsProfile, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	limits := map[string]map[string]float64{}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"QUOTA", "SERVICE", "CODE", "USED", "LIMIT", "USAGE"})
+	for _, quota := range osdQuotas {
+		if _, ok := limits[quota.serviceCode]; !ok {
+			limits[quota.serviceCode], err = listQuotaValues(awsClient, quota.serviceCode)
+			if err != nil {
+				return fmt.Errorf("failed to list the %s quotas: %w", quota.serviceCode, err)
+			}
+		}
+
+		used, err := quota.usage(awsClient)
+		if err != nil {
+			return fmt.Errorf("failed to get the usage of %s: %w", quota.name, err)
+		}
+		limit, ok := limits[quota.serviceCode][quota.quotaCode]
+		if !ok {
+			table.AddRow([]string{quota.name, quota.serviceCode, quota.quotaCode, formatQuotaValue(used), "-", "-"})
+			continue
+		}
+		table.AddRow([]string{quota.name, quota.serviceCode, quota.quotaCode, formatQuotaValue(used), formatQuotaValue(limit), formatQuotaUsage(used, limit)})
+	}
+	return table.Flush()
+}
+
+// listQuotaValues returns the applied values of the quotas of a service by quota code
+func listQuotaValues(awsClient awsprovider.Client, serviceCode string) (map[string]float64, error) {
+	values := map[string]float64{}
+	input := &servicequotas.ListServiceQuotasInput{ServiceCode: &serviceCode}
+	for {
+		output, err := awsClient.ListServiceQuotas(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, quota := range output.Quotas {
+			if quota.QuotaCode != nil && quota.Value != nil {
+				values[*quota.QuotaCode] = *quota.Value
+			}
+		}
+		input.NextToken = output.NextToken
+		if output.NextToken == nil {
+			return values, nil
+		}
+	}
+}
+
+func formatQuotaValue(value float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", value), ".0")
+}
+
+// formatQuotaUsage returns the percentage of the quota used, flagged when above quotaWarnPercent
+func formatQuotaUsage(used float64, limit float64) string {
+	if limit <= 0 {
+		return "-"
+	}
+	percent := used / limit * 100
+	if percent >= quotaWarnPercent {
+		return fmt.Sprintf("%.0f%% (!)", percent)
+	}
+	return fmt.Sprintf("%.0f%%", percent)
+}
+
+// isStandardInstanceType tells whether the instance type counts against the standard (A, C, D, H, I, M, R,
+// T, Z) instances quota
+func isStandardInstanceType(instanceType string) bool {
+	if instanceType == "" {
+		return false
+	}
+	return strings.ContainsRune("acdhimrtz", rune(instanceType[0]))
+}
+
+// countVCPUs returns the vCPUs of the running standard instances
+func countVCPUs(reservations []ec2types.Reservation) float64 {
+	var vcpus float64
+	for _, reservation := range reservations {
+		for _, instance := range reservation.Instances {
+			if instance.State == nil || instance.State.Name != ec2types.InstanceStateNameRunning {
+				continue
+			}
+			if !isStandardInstanceType(string(instance.InstanceType)) || instance.CpuOptions == nil {
+				continue
+			}
+			cores, threads := int32(0), int32(1)
+			if instance.CpuOptions.CoreCount != nil {
+				cores = *instance.CpuOptions.CoreCount
+			}
+			if instance.CpuOptions.ThreadsPerCore != nil {
+				threads = *instance.CpuOptions.ThreadsPerCore
+			}
+			vcpus += float64(cores * threads)
+		}
+	}
+	return vcpus
+}
+
+func standardInstancesVCPUs(awsClient awsprovider.Client) (float64, error) {
+	var reservations []ec2types.Reservation
+	input := &ec2.DescribeInstancesInput{}
+	for {
+		output, err := awsClient.DescribeInstances(input)
+		if err != nil {
+			return 0, err
+		}
+		reservations = append(reservations, output.Reservations...)
+		input.NextToken = output.NextToken
+		if output.NextToken == nil {
+			return countVCPUs(reservations), nil
+		}
+	}
+}
+
+func elasticIPs(awsClient awsprovider.Client) (float64, error) {
+	output, err := awsClient.DescribeAddresses(&ec2.DescribeAddressesInput{})
+	if err != nil {
+		return 0, err
+	}
+	return float64(len(output.Addresses)), nil
+}
+
+func vpcs(awsClient awsprovider.Client) (float64, error) {
+	count := 0
+	input := &ec2.DescribeVpcsInput{}
+	for {
+		output, err := awsClient.DescribeVpcs(input)
+		if err != nil {
+			return 0, err
+		}
+		count += len(output.Vpcs)
+		input.NextToken = output.NextToken
+		if output.NextToken == nil {
+			return float64(count), nil
+		}
+	}
+}
+
+func classicLoadBalancers(awsClient awsprovider.Client) (float64, error) {
+	count := 0
+	input := &elasticloadbalancing.DescribeLoadBalancersInput{}
+	for {
+		output, err := awsClient.DescribeLoadBalancers(input)
+		if err != nil {
+			return 0, err
+		}
+		count += len(output.LoadBalancerDescriptions)
+		input.Marker = output.NextMarker
+		if output.NextMarker == nil {
+			return float64(count), nil
+		}
+	}
+}
+
+func v2LoadBalancers(loadBalancerType elbv2types.LoadBalancerTypeEnum) func(awsprovider.Client) (float64, error) {
+	return func(awsClient awsprovider.Client) (float64, error) {
+		count := 0
+		input := &elasticloadbalancingv2.DescribeLoadBalancersInput{}
+		for {
+			output, err := awsClient.DescribeV2LoadBalancers(input)
+			if err != nil {
+				return 0, err
+			}
+			for _, loadBalancer := range output.LoadBalancers {
+				if loadBalancer.Type == loadBalancerType {
+					count++
+				}
+			}
+			input.Marker = output.NextMarker
+			if output.NextMarker == nil {
+				return float64(count), nil
+			}
+		}
+	}
+}
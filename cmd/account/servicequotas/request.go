@@ -0,0 +1,181 @@
+package servicequotas
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// justificationTemplates are the built-in justifications of the quota increase requests
+var justificationTemplates = map[string]string{
+	"scale": "The OpenShift Dedicated cluster {{.ClusterID}} running in this account needs to scale out. " +
+		"Please raise {{.QuotaName}} from {{.Current}} to {{.Desired}}, {{.Used}} are currently in use.",
+	"install": "A new OpenShift Dedicated cluster ({{.ClusterID}}) is being installed in this account and needs " +
+		"{{.QuotaName}} to be raised from {{.Current}} to {{.Desired}}.",
+	"upgrade": "The OpenShift Dedicated cluster {{.ClusterID}} surges additional capacity while upgrading. " +
+		"Please raise {{.QuotaName}} from {{.Current}} to {{.Desired}}, {{.Used}} are currently in use.",
+}
+
+// justificationData are the fields available to the justification templates
+type justificationData struct {
+	ClusterID string
+	QuotaName string
+	QuotaCode string
+	Current   string
+	Desired   string
+	Used      string
+}
+
+// newCmdRequest implements servicequotas request
+func newCmdRequest() *cobra.Command {
+	ops := &requestOptions{}
+	requestCmd := &cobra.Command{
+		Use:   "request",
+		Short: "Request a service quota increase",
+		Long: fmt.Sprintf(`File a quota increase request through the Service Quotas API in the cluster's account.
+
+The Service Quotas API doesn't take a justification: the justification is rendered from a template and
+printed along with the ID of the support case AWS opens for the request, to be added to the case when AWS
+asks for it. Built-in templates are %v, a custom one can be passed with --justification using the fields
+{{.ClusterID}}, {{.QuotaName}}, {{.QuotaCode}}, {{.Current}}, {{.Desired}} and {{.Used}}.`, justificationTemplateNames()),
+		Example: `  # Raise the vCPUs of the running standard instances to 512
+  osdctl account servicequotas request -C <cluster-id> --quota-code L-1216C47A --value 512 --template scale`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	requestCmd.Flags().StringVarP(&ops.clusterID, "clusterID", "C", "", "Cluster ID")
+	requestCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
+	requestCmd.Flags().StringVarP(&ops.quotaCode, "quota-code", "q", "", "Code of the quota to raise, see `servicequotas list`")
+	requestCmd.Flags().StringVar(&ops.serviceCode, "service-code", "", "Service of the quota, guessed for the quotas shown by `servicequotas list`")
+	requestCmd.Flags().Float64Var(&ops.value, "value", 0, "Desired value of the quota")
+	requestCmd.Flags().StringVar(&ops.template, "template", "scale", fmt.Sprintf("Justification template, one of %v", justificationTemplateNames()))
+	requestCmd.Flags().StringVar(&ops.justification, "justification", "", "Custom justification template, overrides --template")
+	requestCmd.Flags().BoolVarP(&ops.yes, "yes", "y", false, "Don't ask for confirmation")
+	_ = requestCmd.MarkFlagRequired("clusterID")
+	_ = requestCmd.MarkFlagRequired("quota-code")
+	_ = requestCmd.MarkFlagRequired("value")
+
+	return requestCmd
+}
+
+type requestOptions struct {
+	clusterID     string
+	awsProfile    string
+	quotaCode     string
+	serviceCode   string
+	value         float64
+	template      string
+	justification string
+	yes           bool
+}
+
+func justificationTemplateNames() []string {
+	var names []string
+	for name := range justificationTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// renderJustification renders the custom justification, or the named built-in template
+func renderJustification(name string, custom string, data justificationData) (string, error) {
+	text := custom
+	if text == "" {
+		var ok bool
+		text, ok = justificationTemplates[name]
+		if !ok {
+			return "", fmt.Errorf("unknown justification template '%s', valid templates are %v", name, justificationTemplateNames())
+		}
+	}
+	tmpl, err := template.New("justification").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid justification template: %w", err)
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render the justification: %w", err)
+	}
+	return strings.TrimSpace(rendered.String()), nil
+}
+
+func (o *requestOptions) run() error {
+	quota := findOSDQuota(o.quotaCode)
+	if o.serviceCode == "" {
+		if quota == nil {
+			return fmt.Errorf("--service-code is required for quota %s", o.quotaCode)
+		}
+		o.serviceCode = quota.serviceCode
+	}
+
+	awsClient, err := osdCloud.GenerateAWSClientForCluster(o.awsProfile, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	values, err := listQuotaValues(awsClient, o.serviceCode)
+	if err != nil {
+		return fmt.Errorf("failed to list the %s quotas: %w", o.serviceCode, err)
+	}
+	current, ok := values[o.quotaCode]
+	if !ok {
+		return fmt.Errorf("cannot find ServiceQuota (service:%s quota:%s)", o.serviceCode, o.quotaCode)
+	}
+	if o.value <= current {
+		return fmt.Errorf("the quota is already %s, the desired value must be higher", formatQuotaValue(current))
+	}
+
+	data := justificationData{
+		ClusterID: o.clusterID,
+		QuotaName: o.quotaCode,
+		QuotaCode: o.quotaCode,
+		Current:   formatQuotaValue(current),
+		Desired:   formatQuotaValue(o.value),
+		Used:      "unknown",
+	}
+	if quota != nil {
+		data.QuotaName = quota.name
+		if used, err := quota.usage(awsClient); err == nil {
+			data.Used = formatQuotaValue(used)
+		}
+	}
+	justification, err := renderJustification(o.template, o.justification, data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Requesting %s (%s/%s) to be raised from %s to %s\n", data.QuotaName, o.serviceCode, o.quotaCode, data.Current, data.Desired)
+	fmt.Printf("Justification:\n%s\n", justification)
+	if !o.yes && !utils.ConfirmPrompt() {
+		return nil
+	}
+
+	output, err := awsClient.RequestServiceQuotaIncrease(&servicequotas.RequestServiceQuotaIncreaseInput{
+		ServiceCode:  aws.String(o.serviceCode),
+		QuotaCode:    aws.String(o.quotaCode),
+		DesiredValue: aws.Float64(o.value),
+	})
+	if err != nil {
+		return err
+	}
+	if output.RequestedQuota != nil {
+		fmt.Printf("Request %s is %s\n", aws.ToString(output.RequestedQuota.Id), output.RequestedQuota.Status)
+		if caseID := aws.ToString(output.RequestedQuota.CaseId); caseID != "" {
+			fmt.Printf("AWS support case: %s, add the justification above to it\n", caseID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package servicequotas
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestRenderJustification(t *testing.T) {
+	data := justificationData{ClusterID: "abc", QuotaName: "VPCs per Region", QuotaCode: "L-F678F1CE", Current: "5", Desired: "10", Used: "5"}
+
+	tests := []struct {
+		name     string
+		template string
+		custom   string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "built-in template",
+			template: "install",
+			want:     "A new OpenShift Dedicated cluster (abc) is being installed in this account and needs VPCs per Region to be raised from 5 to 10.",
+		},
+		{
+			name:     "custom template",
+			template: "scale",
+			custom:   "{{.QuotaCode}}: {{.Used}}/{{.Current}} used",
+			want:     "L-F678F1CE: 5/5 used",
+		},
+		{
+			name:     "unknown template",
+			template: "other",
+			wantErr:  true,
+		},
+		{
+			name:    "unknown field",
+			custom:  "{{.Region}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderJustification(tt.template, tt.custom, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderJustification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderJustification() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func instance(instanceType string, state ec2types.InstanceStateName, cores int32) ec2types.Instance {
+	return ec2types.Instance{
+		InstanceType: ec2types.InstanceType(instanceType),
+		State:        &ec2types.InstanceState{Name: state},
+		CpuOptions:   &ec2types.CpuOptions{CoreCount: aws.Int32(cores), ThreadsPerCore: aws.Int32(2)},
+	}
+}
+
+func TestCountVCPUs(t *testing.T) {
+	reservations := []ec2types.Reservation{
+		{Instances: []ec2types.Instance{
+			instance("m5.xlarge", ec2types.InstanceStateNameRunning, 2),
+			instance("r5.2xlarge", ec2types.InstanceStateNameRunning, 4),
+		}},
+		{Instances: []ec2types.Instance{
+			instance("m5.xlarge", ec2types.InstanceStateNameStopped, 2),
+			instance("p3.2xlarge", ec2types.InstanceStateNameRunning, 4),
+		}},
+	}
+	if got := countVCPUs(reservations); got != 12 {
+		t.Errorf("countVCPUs() = %v, want 12", got)
+	}
+}
+
+func TestFormatQuotaUsage(t *testing.T) {
+	if got := formatQuotaUsage(3, 5); got != "60%" {
+		t.Errorf("formatQuotaUsage(3, 5) = %s", got)
+	}
+	if got := formatQuotaUsage(4, 5); got != "80% (!)" {
+		t.Errorf("formatQuotaUsage(4, 5) = %s", got)
+	}
+	if got := formatQuotaUsage(1, 0); got != "-" {
+		t.Errorf("formatQuotaUsage(1, 0) = %s", got)
+	}
+}
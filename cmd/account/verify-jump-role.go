@@ -0,0 +1,477 @@
+package account
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	trustPolicyPass    = "PASS"
+	trustPolicyWarn    = "WARN"
+	trustPolicyFail    = "FAIL"
+	trustPolicyUnknown = "UNKNOWN"
+
+	roleSessionNameKey = "sts:RoleSessionName"
+)
+
+// verifyJumpRoleOptions defines the struct for running the verify-jump-role command
+type verifyJumpRoleOptions struct {
+	clusterID  string
+	awsProfile string
+}
+
+// jumpRoleHop is a role of the assume-role chain to the cluster's AWS account
+type jumpRoleHop struct {
+	Name    string
+	RoleARN string
+}
+
+// jumpRoleHopResult is the outcome of assuming a role of the chain, and what its trust policy allows
+type jumpRoleHopResult struct {
+	Hop jumpRoleHop
+	// Principal is the identity assuming the role, the previous role of the chain or the caller for the first one
+	Principal    string
+	Attempted    bool
+	Assumed      bool
+	AssumeError  string
+	Trust        string
+	TrustDetails string
+}
+
+// newCmdVerifyJumpRole implements the verify-jump-role command which walks the assume-role chain to a cluster's AWS account
+func newCmdVerifyJumpRole() *cobra.Command {
+	ops := &verifyJumpRoleOptions{}
+	verifyJumpRoleCmd := &cobra.Command{
+		Use:   "verify-jump-role <cluster-id>",
+		Short: "Verify each hop of the assume-role chain to the AWS account of a cluster",
+		Long: `Walk the assume-role chain osdctl uses to access the AWS account of a cluster, and pinpoint which hop breaks
+when the access fails.
+
+For CCS clusters the chain is RH-SRE-CCS-Access, RH-Technical-Support-Access in the jump account, then the support
+role of the cluster. For non-CCS clusters it is the OrganizationAccountAccessRole of the cluster's account.
+
+Every role is assumed with the session name osdctl uses, and its trust policy is checked to allow the previous hop
+with that session name when the role can be read.`,
+		Example: `  # Verify the assume-role chain to the AWS account of a cluster
+  osdctl account verify-jump-role <cluster-id> -p <aws-profile>`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	verifyJumpRoleCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
+
+	return verifyJumpRoleCmd
+}
+
+func (o *verifyJumpRoleOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.CloudProvider().ID() != "aws" {
+		return fmt.Errorf("cluster %s is not an AWS cluster", o.clusterID)
+	}
+	region := cluster.Region().ID()
+
+	// Builds the base client using the provided creds (via profile or env vars)
+	awsClient, err := aws.NewAwsClient(o.awsProfile, region, "")
+	if err != nil {
+		return err
+	}
+	callerIdentity, err := awsClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("failed to get the caller identity: %w", err)
+	}
+	callerARN, err := arn.Parse(awsSdk.ToString(callerIdentity.Arn))
+	if err != nil {
+		return err
+	}
+	sessionName, err := osdCloud.GenerateRoleSessionName(awsClient)
+	if err != nil {
+		return fmt.Errorf("failed to generate the session name: %w", err)
+	}
+
+	var hops []jumpRoleHop
+	if cluster.CCS().Enabled() {
+		jumpRoleAccountID, err := osdCloud.GetJumpRoleAccountID(ocmClient)
+		if err != nil {
+			return err
+		}
+		supportRoleARN, err := utils.GetSupportRoleArnForCluster(ocmClient, cluster.ID())
+		if err != nil {
+			return err
+		}
+		hops = []jumpRoleHop{
+			{Name: osdCloud.RhSreCcsAccessRolename, RoleARN: aws.GenerateRoleARN(callerARN.AccountID, osdCloud.RhSreCcsAccessRolename)},
+			{Name: osdCloud.RhTechnicalSupportAccess, RoleARN: aws.GenerateRoleARN(jumpRoleAccountID, osdCloud.RhTechnicalSupportAccess)},
+			{Name: "cluster support role", RoleARN: withPartition(supportRoleARN, callerARN.Partition)},
+		}
+	} else {
+		accountID, err := utils.GetAWSAccountIdForCluster(ocmClient, cluster.ID())
+		if err != nil {
+			return err
+		}
+		hops = []jumpRoleHop{
+			{Name: osdCloud.OrganizationAccountAccessRole, RoleARN: withPartition(aws.GenerateRoleARN(accountID, osdCloud.OrganizationAccountAccessRole), callerARN.Partition)},
+		}
+	}
+
+	newClient := func(credentials *stsTypes.Credentials) (aws.Client, error) {
+		return aws.NewAwsClientWithInput(&aws.ClientInput{
+			AccessKeyID:     *credentials.AccessKeyId,
+			SecretAccessKey: *credentials.SecretAccessKey,
+			SessionToken:    *credentials.SessionToken,
+			Region:          region,
+		})
+	}
+	fmt.Printf("Verifying the assume-role chain to the AWS account of cluster %s as %s with session name %s\n\n", o.clusterID, callerARN.String(), sessionName)
+	results := walkJumpRoleChain(awsClient, newClient, callerARN.String(), sessionName, hops)
+	writeJumpRoleResults(os.Stdout, results)
+
+	for i, result := range results {
+		if !result.Assumed {
+			fmt.Printf("\nThe chain breaks at hop %d, %s: %s\n", i+1, result.Hop.Name, diagnoseJumpRoleHop(result, sessionName))
+			return fmt.Errorf("failed to assume %s", result.Hop.RoleARN)
+		}
+	}
+	fmt.Println("\nEvery role of the chain can be assumed")
+	return nil
+}
+
+// withPartition returns the ARN in the given partition, as the ARNs are generated for the aws partition
+func withPartition(roleARN string, partition string) string {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil || partition == "" {
+		return roleARN
+	}
+	parsed.Partition = partition
+	return parsed.String()
+}
+
+// walkJumpRoleChain assumes the roles of the chain in order, checking the trust policy of each, and stops at the first
+// role which can't be assumed. The roles after it are reported as not attempted.
+func walkJumpRoleChain(client aws.Client, newClient func(*stsTypes.Credentials) (aws.Client, error), callerARN string, sessionName string, hops []jumpRoleHop) []jumpRoleHopResult {
+	results := make([]jumpRoleHopResult, 0, len(hops))
+	principal := callerARN
+	broken := false
+	for _, hop := range hops {
+		result := jumpRoleHopResult{Hop: hop, Principal: principal}
+		principal = hop.RoleARN
+		if broken {
+			results = append(results, result)
+			continue
+		}
+		result.Attempted = true
+
+		var assumedClient aws.Client
+		output, err := client.AssumeRole(&sts.AssumeRoleInput{
+			RoleArn:         awsSdk.String(hop.RoleARN),
+			RoleSessionName: awsSdk.String(sessionName),
+		})
+		if err == nil {
+			assumedClient, err = newClient(output.Credentials)
+		}
+		if err != nil {
+			result.AssumeError = err.Error()
+		} else {
+			result.Assumed = true
+		}
+
+		// The role is read with the previous hop's credentials first, which works within the same account, then with
+		// the role's own credentials
+		document, err := getTrustPolicy(hop.RoleARN, client, assumedClient)
+		if err != nil {
+			result.Trust, result.TrustDetails = trustPolicyUnknown, err.Error()
+		} else {
+			result.Trust, result.TrustDetails = evaluateJumpRoleTrustPolicy(document, trustPolicyPrincipals(result.Principal), sessionName)
+		}
+
+		results = append(results, result)
+		if !result.Assumed {
+			broken = true
+			continue
+		}
+		client = assumedClient
+	}
+	return results
+}
+
+// getTrustPolicy returns the decoded trust policy of the role, read with the first of the clients allowed to
+func getTrustPolicy(roleARN string, clients ...aws.Client) (string, error) {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return "", err
+	}
+	lastErr := fmt.Errorf("no credentials to read the role with")
+	for _, client := range clients {
+		if client == nil {
+			continue
+		}
+		role, err := client.GetRole(&iam.GetRoleInput{RoleName: awsSdk.String(path.Base(parsed.Resource))})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// The policy document is URL encoded
+		return url.QueryUnescape(awsSdk.ToString(role.Role.AssumeRolePolicyDocument))
+	}
+	return "", fmt.Errorf("failed to read the trust policy: %w", lastErr)
+}
+
+// trustPolicyPrincipals returns the principals a trust policy can name to trust the identity: its ARN, the role of an
+// assumed-role session, and its account
+func trustPolicyPrincipals(identityARN string) []string {
+	parsed, err := arn.Parse(identityARN)
+	if err != nil {
+		return []string{identityARN}
+	}
+	principals := []string{identityARN}
+	if parsed.Service == "sts" && strings.HasPrefix(parsed.Resource, "assumed-role/") {
+		role := strings.Split(parsed.Resource, "/")[1]
+		principals = []string{fmt.Sprintf("arn:%s:iam::%s:role/%s", parsed.Partition, parsed.AccountID, role)}
+	}
+	return append(principals, fmt.Sprintf("arn:%s:iam::%s:root", parsed.Partition, parsed.AccountID), parsed.AccountID)
+}
+
+// policyValues is a policy element which can be a single string or a list of strings
+type policyValues []string
+
+func (v *policyValues) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*v = []string{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*v = list
+	return nil
+}
+
+// policyPrincipal is the AWS principal of a statement, which is either "*" or a map of principal types
+type policyPrincipal struct {
+	AWS policyValues
+}
+
+func (p *policyPrincipal) UnmarshalJSON(data []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(data, &wildcard); err == nil {
+		p.AWS = []string{wildcard}
+		return nil
+	}
+	var principal struct {
+		AWS policyValues
+	}
+	if err := json.Unmarshal(data, &principal); err != nil {
+		return err
+	}
+	p.AWS = principal.AWS
+	return nil
+}
+
+type jumpRoleTrustPolicy struct {
+	Statement []struct {
+		Effect    string
+		Action    policyValues
+		Principal policyPrincipal
+		Condition map[string]map[string]policyValues
+	}
+}
+
+// evaluateJumpRoleTrustPolicy checks the trust policy allows one of the principals to assume the role with the session
+// name. Conditions on other keys than the session name can't be verified and are reported as warnings.
+func evaluateJumpRoleTrustPolicy(document string, principals []string, sessionName string) (string, string) {
+	policy := jumpRoleTrustPolicy{}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return trustPolicyFail, fmt.Sprintf("failed to parse the trust policy: %v", err)
+	}
+
+	var otherPrincipals []string
+	var allowedSessionNames []string
+	var allowed, warning string
+	for _, statement := range policy.Statement {
+		if !allowsAssumeRole(statement.Action) {
+			continue
+		}
+		if !matchesPrincipal(statement.Principal.AWS, principals) {
+			if strings.EqualFold(statement.Effect, "Allow") {
+				otherPrincipals = append(otherPrincipals, statement.Principal.AWS...)
+			}
+			continue
+		}
+
+		matched, sessionNames, otherKeys := matchSessionConditions(statement.Condition, sessionName)
+		if strings.EqualFold(statement.Effect, "Deny") {
+			if matched && len(otherKeys) == 0 {
+				return trustPolicyFail, fmt.Sprintf("a statement denies %s with session name %s", principals[0], sessionName)
+			}
+			continue
+		}
+		if !matched {
+			allowedSessionNames = append(allowedSessionNames, sessionNames...)
+			continue
+		}
+		if len(otherKeys) > 0 {
+			warning = fmt.Sprintf("allows %s only if the conditions on %v hold", principals[0], otherKeys)
+			continue
+		}
+		// A later statement can still deny it
+		allowed = fmt.Sprintf("allows %s", principals[0])
+		if len(sessionNames) > 0 {
+			allowed += fmt.Sprintf(" with session name %s", sessionName)
+		}
+	}
+
+	switch {
+	case allowed != "":
+		return trustPolicyPass, allowed
+	case warning != "":
+		return trustPolicyWarn, warning
+	case len(allowedSessionNames) > 0:
+		return trustPolicyFail, fmt.Sprintf("allows the session names %v, not %s", allowedSessionNames, sessionName)
+	case len(otherPrincipals) > 0:
+		details := fmt.Sprintf("trusts %v instead of %s", otherPrincipals, principals[0])
+		for _, principal := range otherPrincipals {
+			// Principals which were deleted are replaced with their unique ID, which recreating them doesn't restore
+			if strings.HasPrefix(principal, "AROA") || strings.HasPrefix(principal, "AIDA") {
+				details += fmt.Sprintf(", %s is a deleted principal the trust policy must be updated for", principal)
+				break
+			}
+		}
+		return trustPolicyFail, details
+	default:
+		return trustPolicyFail, "no statement allows sts:AssumeRole"
+	}
+}
+
+func allowsAssumeRole(actions []string) bool {
+	for _, action := range actions {
+		if action == "*" || strings.EqualFold(action, "sts:*") || strings.EqualFold(action, "sts:AssumeRole") {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPrincipal(policyPrincipals []string, principals []string) bool {
+	for _, policyPrincipal := range policyPrincipals {
+		if policyPrincipal == "*" {
+			return true
+		}
+		for _, principal := range principals {
+			if strings.EqualFold(policyPrincipal, principal) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSessionConditions returns whether the session name satisfies the sts:RoleSessionName conditions, the session
+// names the conditions allow, and the keys of the other conditions
+func matchSessionConditions(conditions map[string]map[string]policyValues, sessionName string) (bool, []string, []string) {
+	matched := true
+	var sessionNames []string
+	var otherKeys []string
+	for operator, keys := range conditions {
+		for key, values := range keys {
+			if !strings.EqualFold(key, roleSessionNameKey) {
+				otherKeys = append(otherKeys, key)
+				continue
+			}
+			sessionNames = append(sessionNames, values...)
+			if !matchStringCondition(operator, values, sessionName) {
+				matched = false
+			}
+		}
+	}
+	return matched, sessionNames, otherKeys
+}
+
+// matchStringCondition evaluates a string condition operator on a single valued key
+func matchStringCondition(operator string, values []string, value string) bool {
+	operator = strings.TrimPrefix(strings.TrimPrefix(operator, "ForAnyValue:"), "ForAllValues:")
+	operator = strings.TrimSuffix(operator, "IfExists")
+
+	negated := false
+	switch operator {
+	case "StringNotEquals", "StringNotEqualsIgnoreCase", "StringNotLike":
+		negated = true
+	}
+	for _, expected := range values {
+		var match bool
+		switch operator {
+		case "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase":
+			match = strings.EqualFold(expected, value)
+		case "StringLike", "StringNotLike":
+			match, _ = path.Match(expected, value)
+		default:
+			match = expected == value
+		}
+		if match {
+			return !negated
+		}
+	}
+	return negated
+}
+
+// diagnoseJumpRoleHop explains why the role of the hop couldn't be assumed
+func diagnoseJumpRoleHop(result jumpRoleHopResult, sessionName string) string {
+	switch result.Trust {
+	case trustPolicyFail:
+		return fmt.Sprintf("the trust policy of %s doesn't allow %s: %s", result.Hop.RoleARN, result.Principal, result.TrustDetails)
+	case trustPolicyPass:
+		return fmt.Sprintf("the trust policy of %s allows it, check the permissions of %s allow sts:AssumeRole on the role and no service control policy denies it (%s)",
+			result.Hop.RoleARN, result.Principal, result.AssumeError)
+	default:
+		return fmt.Sprintf("%s couldn't be assumed (%s), check its trust policy allows %s with session name %s",
+			result.Hop.RoleARN, result.AssumeError, result.Principal, sessionName)
+	}
+}
+
+func writeJumpRoleResults(w io.Writer, results []jumpRoleHopResult) {
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"HOP", "ROLE", "ASSUMED", "TRUST POLICY", "DETAILS"})
+	for i, result := range results {
+		assumed := "yes"
+		switch {
+		case !result.Attempted:
+			assumed = "not attempted"
+		case !result.Assumed:
+			assumed = "no"
+		}
+		table.AddRow([]string{fmt.Sprint(i + 1), result.Hop.RoleARN, assumed, result.Trust, result.TrustDetails})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the assume-role chain: %v\n", err)
+	}
+}
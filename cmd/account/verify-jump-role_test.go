@@ -0,0 +1,115 @@
+package account
+
+import (
+	"errors"
+	"testing"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamTypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/golang/mock/gomock"
+
+	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+)
+
+func TestEvaluateJumpRoleTrustPolicy(t *testing.T) {
+	principals := trustPolicyPrincipals("arn:aws:sts::111111111111:assumed-role/RH-SRE-CCS-Access/RH-SRE-jdoe")
+
+	tests := []struct {
+		name       string
+		document   string
+		wantStatus string
+	}{
+		{
+			name:       "trusts the role with a matching session name",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::111111111111:role/RH-SRE-CCS-Access"},"Condition":{"StringLike":{"sts:RoleSessionName":"RH-SRE-*"}}}]}`,
+			wantStatus: trustPolicyPass,
+		},
+		{
+			name:       "trusts the account",
+			document:   `{"Statement":[{"Effect":"Allow","Action":["sts:AssumeRole","sts:TagSession"],"Principal":{"AWS":["arn:aws:iam::111111111111:root"]}}]}`,
+			wantStatus: trustPolicyPass,
+		},
+		{
+			name:       "session name not allowed",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::111111111111:role/RH-SRE-CCS-Access"},"Condition":{"StringEquals":{"sts:RoleSessionName":["RH-SRE-someone"]}}}]}`,
+			wantStatus: trustPolicyFail,
+		},
+		{
+			name:       "trusts a deleted role",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"AROAEXAMPLEID"}}]}`,
+			wantStatus: trustPolicyFail,
+		},
+		{
+			name:       "denied session name",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":"*"},{"Effect":"Deny","Action":"sts:*","Principal":"*","Condition":{"StringNotLike":{"sts:RoleSessionName":"RH-SRE-admin-*"}}}]}`,
+			wantStatus: trustPolicyFail,
+		},
+		{
+			name:       "condition which can't be verified",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::111111111111:root"},"Condition":{"StringEquals":{"sts:ExternalId":"secret"}}}]}`,
+			wantStatus: trustPolicyWarn,
+		},
+		{
+			name:       "web identity only",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRoleWithWebIdentity","Principal":{"Federated":"arn:aws:iam::111111111111:oidc-provider/example.com"}}]}`,
+			wantStatus: trustPolicyFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, details := evaluateJumpRoleTrustPolicy(tt.document, principals, "RH-SRE-jdoe"); got != tt.wantStatus {
+				t.Errorf("evaluateJumpRoleTrustPolicy() = %s (%s), want %s", got, details, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestWalkJumpRoleChain(t *testing.T) {
+	hops := []jumpRoleHop{
+		{Name: "RH-SRE-CCS-Access", RoleARN: "arn:aws:iam::111111111111:role/RH-SRE-CCS-Access"},
+		{Name: "RH-Technical-Support-Access", RoleARN: "arn:aws:iam::222222222222:role/RH-Technical-Support-Access"},
+		{Name: "cluster support role", RoleARN: "arn:aws:iam::333333333333:role/ManagedOpenShift-Support-abcd"},
+	}
+	credentials := &sts.AssumeRoleOutput{Credentials: &stsTypes.Credentials{
+		AccessKeyId:     awsSdk.String("key"),
+		SecretAccessKey: awsSdk.String("secret"),
+		SessionToken:    awsSdk.String("token"),
+	}}
+	ccsAccessRole := &iam.GetRoleOutput{Role: &iamTypes.Role{AssumeRolePolicyDocument: awsSdk.String(
+		`%7B%22Statement%22%3A%5B%7B%22Effect%22%3A%22Allow%22%2C%22Action%22%3A%22sts%3AAssumeRole%22%2C%22Principal%22%3A%7B%22AWS%22%3A%22arn%3Aaws%3Aiam%3A%3A111111111111%3Aroot%22%7D%7D%5D%7D`,
+	)}}
+
+	ctrl := gomock.NewController(t)
+	caller := mock.NewMockClient(ctrl)
+	ccsAccess := mock.NewMockClient(ctrl)
+	caller.EXPECT().AssumeRole(gomock.Any()).Return(credentials, nil)
+	caller.EXPECT().GetRole(gomock.Any()).Return(ccsAccessRole, nil)
+	ccsAccess.EXPECT().AssumeRole(gomock.Any()).Return(nil, errors.New("AccessDenied"))
+	ccsAccess.EXPECT().GetRole(gomock.Any()).Return(nil, errors.New("AccessDenied"))
+
+	newClient := func(*stsTypes.Credentials) (aws.Client, error) { return ccsAccess, nil }
+	results := walkJumpRoleChain(caller, newClient, "arn:aws:iam::111111111111:user/jdoe", "RH-SRE-jdoe", hops)
+
+	want := []struct {
+		attempted bool
+		assumed   bool
+		trust     string
+		principal string
+	}{
+		{true, true, trustPolicyPass, "arn:aws:iam::111111111111:user/jdoe"},
+		{true, false, trustPolicyUnknown, "arn:aws:iam::111111111111:role/RH-SRE-CCS-Access"},
+		{false, false, "", "arn:aws:iam::222222222222:role/RH-Technical-Support-Access"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("walkJumpRoleChain() returned %d hops, want %d", len(results), len(want))
+	}
+	for i, result := range results {
+		if result.Attempted != want[i].attempted || result.Assumed != want[i].assumed || result.Trust != want[i].trust || result.Principal != want[i].principal {
+			t.Errorf("hop %d = %+v, want %+v", i+1, result, want[i])
+		}
+	}
+}
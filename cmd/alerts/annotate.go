@@ -0,0 +1,127 @@
+package alerts
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/cluster"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// maxNoteLength is the longest note PagerDuty accepts
+const maxNoteLength = 25000
+
+type annotateOptions struct {
+	incidentID  string
+	fromContext string
+	from        string
+	days        int
+}
+
+// NewCmdAnnotate implements the alert annotate command
+func NewCmdAnnotate() *cobra.Command {
+	ops := &annotateOptions{}
+	annotateCmd := &cobra.Command{
+		Use:   "annotate <incident-id> --from-context <cluster-id>",
+		Short: "Add a note with the cluster context to a PagerDuty incident",
+		Long: `Add a note to a PagerDuty incident with the short context summary of a cluster and links to its
+related resources, so the incident record is self-contained for responders who don't use osdctl.
+
+The incident can be given by its ID or its URL.`,
+		Example:           `  osdctl alert annotate Q0ABCDEF12345 --from-context ${CLUSTER_ID}`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.incidentID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	annotateCmd.Flags().StringVar(&ops.fromContext, "from-context", "", "Cluster ID, internal ID or name to collect the context of")
+	annotateCmd.Flags().StringVar(&ops.from, "from", "", fmt.Sprintf("Email of the PagerDuty user posting the note, defaults to the '%s' config value", pagerduty.PagerDutyUserEmailConfigKey))
+	annotateCmd.Flags().IntVarP(&ops.days, "days", "d", 30, "Command will display X days of Error SLs sent to the cluster. Days is set to 30 by default")
+	_ = annotateCmd.MarkFlagRequired("from-context")
+
+	return annotateCmd
+}
+
+func (o *annotateOptions) run() error {
+	incidentID, err := parseIncidentID(o.incidentID)
+	if err != nil {
+		return err
+	}
+	if o.from == "" {
+		o.from = viper.GetString(pagerduty.PagerDutyUserEmailConfigKey)
+	}
+
+	pdProvider, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		Init()
+	if err != nil {
+		return err
+	}
+
+	summary, err := cluster.GetContextSummary(o.fromContext, o.days)
+	if err != nil {
+		return err
+	}
+	for _, err := range summary.Errors {
+		fmt.Printf("Failed to collect part of the context, the note will be incomplete: %v\n", err)
+	}
+
+	if err := pdProvider.AddIncidentNote(incidentID, buildContextNote(o.fromContext, summary, time.Now()), o.from); err != nil {
+		return err
+	}
+	fmt.Printf("Added the context of %s to incident %s\n", o.fromContext, incidentID)
+	return nil
+}
+
+// parseIncidentID accepts an incident ID or an incident URL, e.g. https://redhat.pagerduty.com/incidents/Q0ABCDEF12345
+func parseIncidentID(incident string) (string, error) {
+	if !strings.Contains(incident, "/") {
+		return incident, nil
+	}
+	u, err := url.Parse(incident)
+	if err != nil {
+		return "", fmt.Errorf("invalid incident URL '%s': %w", incident, err)
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[len(segments)-2] != "incidents" {
+		return "", fmt.Errorf("invalid incident URL '%s', expected .../incidents/<incident-id>", incident)
+	}
+	return segments[len(segments)-1], nil
+}
+
+// buildContextNote renders the context summary as a plain text note, links included
+func buildContextNote(clusterID string, summary *cluster.ContextSummary, now time.Time) string {
+	var links strings.Builder
+	if len(summary.Links) > 0 {
+		names := make([]string, 0, len(summary.Links))
+		for name := range summary.Links {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		links.WriteString("\nLinks:\n")
+		for _, name := range names {
+			fmt.Fprintf(&links, "- %s: %s\n", name, summary.Links[name])
+		}
+	}
+
+	header := fmt.Sprintf("Context of cluster %s collected by osdctl on %s\n\n", clusterID, now.UTC().Format(time.RFC3339))
+	body := strings.TrimRight(summary.Summary, "\n") + "\n"
+
+	// Keep the header and the links, which are the most useful to a responder, and truncate the summary
+	const truncated = "...\n(truncated)\n"
+	if room := maxNoteLength - len(header) - links.Len(); len(body) > room {
+		body = body[:max(room-len(truncated), 0)] + truncated
+	}
+	return header + body + links.String()
+}
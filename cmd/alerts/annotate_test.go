@@ -0,0 +1,68 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/cluster"
+)
+
+func TestParseIncidentID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incident string
+		want     string
+		wantErr  bool
+	}{
+		{name: "incident ID", incident: "Q0ABCDEF12345", want: "Q0ABCDEF12345"},
+		{name: "incident URL", incident: "https://redhat.pagerduty.com/incidents/Q0ABCDEF12345", want: "Q0ABCDEF12345"},
+		{name: "incident URL with trailing slash", incident: "https://redhat.pagerduty.com/incidents/Q0ABCDEF12345/", want: "Q0ABCDEF12345"},
+		{name: "not an incident URL", incident: "https://redhat.pagerduty.com/services/PABCDEF", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseIncidentID(tt.incident)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIncidentID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseIncidentID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildContextNote(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	summary := &cluster.ContextSummary{
+		Summary: "Cluster ID: abc\nVersion: 4.14.10\n",
+		Links: map[string]string{
+			"Splunk": "https://splunk.example.com",
+			"OHSS":   "https://issues.example.com",
+		},
+	}
+
+	note := buildContextNote("abc", summary, now)
+	want := `Context of cluster abc collected by osdctl on 2024-03-01T12:00:00Z
+
+Cluster ID: abc
+Version: 4.14.10
+
+Links:
+- OHSS: https://issues.example.com
+- Splunk: https://splunk.example.com
+`
+	if note != want {
+		t.Errorf("buildContextNote() = %q, want %q", note, want)
+	}
+
+	summary.Summary = strings.Repeat("x", 2*maxNoteLength)
+	note = buildContextNote("abc", summary, now)
+	if len(note) > maxNoteLength {
+		t.Errorf("note is %d characters long, want at most %d", len(note), maxNoteLength)
+	}
+	if !strings.Contains(note, "(truncated)") || !strings.HasSuffix(note, "- Splunk: https://splunk.example.com\n") {
+		t.Errorf("expected a truncated summary followed by the links, got %q", note[len(note)-200:])
+	}
+}
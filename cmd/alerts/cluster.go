@@ -0,0 +1,190 @@
+package alerts
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/cmd/cluster"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// clusterDetailKeys are the alert details and labels naming the cluster, most specific first
+var clusterDetailKeys = []string{"cluster_id", "_id", "cluster_uuid", "external_id", "clusterid", "cluster"}
+
+var (
+	// detailLabelRE matches the labels the Alertmanager lists in the firing details, e.g. " - _id = <uuid>"
+	detailLabelRE = regexp.MustCompile(`(?m)^\s*-\s*([\w.]+)\s*=\s*(\S+)\s*$`)
+	// domainRE matches the domain names in the name of a service, which is named after the base domain. The
+	// top-level domain is made of letters, so the suffixes appended to the name aren't part of it.
+	domainRE = regexp.MustCompile(`[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,}\b`)
+)
+
+type clusterOptions struct {
+	incidentID string
+	days       int
+}
+
+// NewCmdCluster implements the alert cluster command
+func NewCmdCluster() *cobra.Command {
+	ops := &clusterOptions{}
+	clusterCmd := &cobra.Command{
+		Use:   "cluster <incident-id>",
+		Short: "Find the cluster of a PagerDuty incident and print its short context",
+		Long: `Find the cluster a PagerDuty incident is about, and print the short context of the cluster.
+
+The cluster is looked up by the identifiers the alerts of the incident carry in their details (cluster_id,
+_id, ...), then by the base domain the service of the incident is named after. The incident can be given by
+its ID or its URL.`,
+		Example:           `  osdctl alert cluster Q0ABCDEF12345`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.incidentID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	clusterCmd.Flags().IntVarP(&ops.days, "days", "d", 30, "Command will display X days of Error SLs sent to the cluster. Days is set to 30 by default")
+
+	return clusterCmd
+}
+
+func (o *clusterOptions) run() error {
+	incidentID, err := parseIncidentID(o.incidentID)
+	if err != nil {
+		return err
+	}
+
+	pdProvider, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		Init()
+	if err != nil {
+		return err
+	}
+	incident, err := pdProvider.GetIncidentDetails(incidentID)
+	if err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	found, how, err := resolveIncidentCluster(connection, incident)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Incident %s (%s) on service %s is about cluster %s (%s), found by %s\n\n", incident.ID, incident.Title, incident.ServiceName, found.Name(), found.ID(), how)
+
+	summary, err := cluster.GetContextSummary(found.ID(), o.days)
+	if err != nil {
+		return err
+	}
+	for _, err := range summary.Errors {
+		fmt.Fprintf(os.Stderr, "Failed to collect part of the context: %v\n", err)
+	}
+	fmt.Print(summary.Summary)
+	return nil
+}
+
+// resolveIncidentCluster returns the cluster of the incident and how it was found: by an identifier in the
+// details of its alerts, else by the base domain in the name of its service
+func resolveIncidentCluster(connection *sdk.Connection, incident *pagerduty.IncidentDetails) (*cmv1.Cluster, string, error) {
+	for _, identifier := range clusterIdentifiersFromDetails(incident.AlertDetails) {
+		if found, err := utils.GetClusterAnyStatus(connection, identifier); err == nil {
+			return found, fmt.Sprintf("the alert detail %s", identifier), nil
+		}
+	}
+
+	for _, domain := range baseDomainCandidates(incident.ServiceName) {
+		response, err := connection.ClustersMgmt().V1().Clusters().List().
+			Search(fmt.Sprintf("dns.base_domain = '%s'", domain)).
+			Size(1).
+			Send()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to look the clusters of base domain %s up: %w", domain, err)
+		}
+		// A shorter domain is too generic to tell the cluster, e.g. a customer's domain shared by its clusters
+		if response.Total() > 1 {
+			break
+		}
+		if response.Total() == 1 {
+			return response.Items().Get(0), fmt.Sprintf("the base domain %s of service %s", domain, incident.ServiceName), nil
+		}
+	}
+	return nil, "", fmt.Errorf("no cluster found for incident %s: its alerts carry no known cluster identifier and no single cluster has the base domain of service '%s'", incident.ID, incident.ServiceName)
+}
+
+// clusterIdentifiersFromDetails returns the cluster identifiers found in the details of the alerts, and in the
+// labels listed by the firing details, in the order of clusterDetailKeys and without duplicates
+func clusterIdentifiersFromDetails(alertDetails []map[string]string) []string {
+	byKey := map[string][]string{}
+	add := func(key string, value string) {
+		key = strings.ToLower(key)
+		value = strings.TrimSpace(value)
+		if value != "" && !contains(byKey[key], value) {
+			byKey[key] = append(byKey[key], value)
+		}
+	}
+	for _, details := range alertDetails {
+		keys := make([]string, 0, len(details))
+		for key := range details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			add(key, details[key])
+			for _, label := range detailLabelRE.FindAllStringSubmatch(details[key], -1) {
+				add(label[1], label[2])
+			}
+		}
+	}
+
+	var identifiers []string
+	for _, key := range clusterDetailKeys {
+		for _, value := range byKey[key] {
+			if !contains(identifiers, value) {
+				identifiers = append(identifiers, value)
+			}
+		}
+	}
+	return identifiers
+}
+
+// baseDomainCandidates returns the domains in the name of the service and their parent domains, longest first.
+// The services are named after the base domain of their cluster, with a prefix or a suffix depending on the
+// integration.
+func baseDomainCandidates(serviceName string) []string {
+	var candidates []string
+	for _, domain := range domainRE.FindAllString(strings.ToLower(serviceName), -1) {
+		labels := strings.Split(domain, ".")
+		for i := 0; i < len(labels)-1; i++ {
+			candidate := strings.Join(labels[i:], ".")
+			if !contains(candidates, candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
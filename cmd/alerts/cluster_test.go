@@ -0,0 +1,73 @@
+package alerts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClusterIdentifiersFromDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		details  []map[string]string
+		expected []string
+	}{
+		{
+			name:     "custom details",
+			details:  []map[string]string{{"Cluster": "payments", "cluster_id": "2a3b4c5d6e7f8g9h0i1j2k3l4m5n6o7p"}},
+			expected: []string{"2a3b4c5d6e7f8g9h0i1j2k3l4m5n6o7p", "payments"},
+		},
+		{
+			name: "labels of the firing alerts",
+			details: []map[string]string{{
+				"firing":     "Labels:\n - alertname = KubeAPIDown\n - _id = 5ed2e8f6-3f1e-4c4e-9d1a-0b1c2d3e4f5a\n - severity = critical\n",
+				"num_firing": "1",
+			}},
+			expected: []string{"5ed2e8f6-3f1e-4c4e-9d1a-0b1c2d3e4f5a"},
+		},
+		{
+			name: "several alerts of the same cluster",
+			details: []map[string]string{
+				{"cluster_id": "2a3b4c5d6e7f8g9h0i1j2k3l4m5n6o7p"},
+				{"cluster_id": "2a3b4c5d6e7f8g9h0i1j2k3l4m5n6o7p", "_id": "5ed2e8f6-3f1e-4c4e-9d1a-0b1c2d3e4f5a"},
+			},
+			expected: []string{"2a3b4c5d6e7f8g9h0i1j2k3l4m5n6o7p", "5ed2e8f6-3f1e-4c4e-9d1a-0b1c2d3e4f5a"},
+		},
+		{
+			name:    "no identifier",
+			details: []map[string]string{{"details": "Disk is full"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if identifiers := clusterIdentifiersFromDetails(tt.details); !reflect.DeepEqual(identifiers, tt.expected) {
+				t.Errorf("clusterIdentifiersFromDetails() = %v, want %v", identifiers, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBaseDomainCandidates(t *testing.T) {
+	tests := []struct {
+		serviceName string
+		expected    []string
+	}{
+		{
+			serviceName: "osd-payments.ab1c.p1.openshiftapps.com-hive-cluster",
+			expected:    []string{"osd-payments.ab1c.p1.openshiftapps.com", "ab1c.p1.openshiftapps.com", "p1.openshiftapps.com", "openshiftapps.com"},
+		},
+		{
+			serviceName: "ab1c.p1.openshiftapps.com",
+			expected:    []string{"ab1c.p1.openshiftapps.com", "p1.openshiftapps.com", "openshiftapps.com"},
+		},
+		{
+			serviceName: "Red Hat Managed Services",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.serviceName, func(t *testing.T) {
+			if candidates := baseDomainCandidates(tt.serviceName); !reflect.DeepEqual(candidates, tt.expected) {
+				t.Errorf("baseDomainCandidates() = %v, want %v", candidates, tt.expected)
+			}
+		})
+	}
+}
@@ -16,6 +16,12 @@ func NewCmdAlerts() *cobra.Command {
 
 	alrtCmd.AddCommand(NewCmdListAlerts())
 	alrtCmd.AddCommand(silence.NewCmdSilence())
+	alrtCmd.AddCommand(NewCmdAnnotate())
+	alrtCmd.AddCommand(NewCmdIncidents())
+	alrtCmd.AddCommand(NewCmdOnCall())
+	alrtCmd.AddCommand(NewCmdStats())
+	alrtCmd.AddCommand(NewCmdRoute())
+	alrtCmd.AddCommand(NewCmdCluster())
 
 	return alrtCmd
 }
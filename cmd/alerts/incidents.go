@@ -0,0 +1,84 @@
+package alerts
+
+import (
+	"fmt"
+	"os"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type incidentsOptions struct {
+	mine         bool
+	statuses     []string
+	limit        int
+	tableOptions printer.TableOptions
+}
+
+// NewCmdIncidents implements the alert incidents command
+func NewCmdIncidents() *cobra.Command {
+	ops := &incidentsOptions{}
+	incidentsCmd := &cobra.Command{
+		Use:   "incidents",
+		Short: "List the open PagerDuty incidents of the team, or the ones assigned to me",
+		Long: fmt.Sprintf(`List the open PagerDuty incidents of the teams configured as '%s', or with --mine the incidents
+assigned to the owner of the PagerDuty token, for a personal work queue view.`, pagerduty.PagerDutyTeamIDsKey),
+		Example: `  # My work queue
+  osdctl alert incidents --mine`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(validateIncidentLimit(ops.limit))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	incidentsCmd.Flags().BoolVar(&ops.mine, "mine", false, "Only list the incidents assigned to me")
+	incidentsCmd.Flags().StringSliceVar(&ops.statuses, "status", []string{"triggered", "acknowledged"}, "Statuses of the incidents to list")
+	incidentsCmd.Flags().IntVar(&ops.limit, "limit", pagerduty.DefaultIncidentLimit, "Maximum number of incidents listed")
+	printer.AddTableFlags(incidentsCmd.Flags(), &ops.tableOptions)
+
+	return incidentsCmd
+}
+
+func (o *incidentsOptions) run() error {
+	pdProvider, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		WithTeamIdList(viper.GetStringSlice(pagerduty.PagerDutyTeamIDsKey)).
+		WithIncidentLimit(o.limit).
+		Init()
+	if err != nil {
+		return err
+	}
+
+	incidents, err := pdProvider.GetIncidents(o.statuses, o.mine)
+	if err != nil {
+		return err
+	}
+	if len(incidents) == 0 {
+		fmt.Println("No incidents found")
+		return nil
+	}
+	return printIncidents(incidents, o.tableOptions)
+}
+
+func printIncidents(incidents []pd.Incident, tableOptions printer.TableOptions) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ').WithTableOptions(tableOptions)
+	table.AddRow([]string{"ID", "Urgency", "Status", "Service", "Title", "Created At", "URL"})
+	for _, incident := range incidents {
+		table.AddRow([]string{incident.ID, incident.Urgency, incident.Status, incident.Service.Summary, incident.Title, incident.CreatedAt, incident.HTMLURL})
+	}
+	return table.Flush()
+}
+
+func validateIncidentLimit(limit int) error {
+	if limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+	return nil
+}
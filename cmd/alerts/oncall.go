@@ -0,0 +1,259 @@
+package alerts
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const onCallTimeFormat = "Mon Jan 02 15:04"
+
+type onCallOptions struct {
+	teamIDs   []string
+	window    time.Duration
+	timezones []string
+}
+
+// onCallShift is a shift of an escalation level with its times parsed. Users on call permanently, directly
+// from the escalation policy, have no start nor end.
+type onCallShift struct {
+	Policy       string
+	Level        uint
+	Schedule     string
+	User         string
+	UserTimezone string
+	Start        time.Time
+	End          time.Time
+	Current      bool
+}
+
+// NewCmdOnCall implements the alert oncall command
+func NewCmdOnCall() *cobra.Command {
+	ops := &onCallOptions{}
+	onCallCmd := &cobra.Command{
+		Use:   "oncall",
+		Short: "Show who is on call for the teams, now and next",
+		Long: fmt.Sprintf(`Show, for every level of the escalation policies of the teams configured as '%s', who is on call
+now and who takes over next, with the shift times in the given timezones and the local time of each
+person, to find the right human during escalations without the PagerDuty web UI.`, pagerduty.PagerDutyTeamIDsKey),
+		Example: `  # On-call of the configured teams, with the shifts in UTC
+  osdctl alert oncall
+
+  # On-call of another team, with the shifts in Prague and Brisbane time
+  osdctl alert oncall --team-ids PXXXXXX --timezone Europe/Prague --timezone Australia/Brisbane`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	onCallCmd.Flags().StringSliceVar(&ops.teamIDs, "team-ids", []string{}, fmt.Sprintf("PagerDuty team IDs, defaults to '%s' of the config", pagerduty.PagerDutyTeamIDsKey))
+	onCallCmd.Flags().DurationVar(&ops.window, "window", 24*time.Hour, "How far ahead the next shifts are looked for")
+	onCallCmd.Flags().StringArrayVar(&ops.timezones, "timezone", []string{"UTC"}, "IANA timezone the shift times are shown in, e.g. Europe/Prague or Local. Can be repeated")
+
+	return onCallCmd
+}
+
+func (o *onCallOptions) run() error {
+	if o.window <= 0 {
+		return fmt.Errorf("--window must be positive")
+	}
+	locations, err := loadLocations(o.timezones)
+	if err != nil {
+		return err
+	}
+	teamIDs := o.teamIDs
+	if len(teamIDs) == 0 {
+		teamIDs = viper.GetStringSlice(pagerduty.PagerDutyTeamIDsKey)
+	}
+	if len(teamIDs) == 0 {
+		return fmt.Errorf("no PagerDuty team, pass --team-ids or set '%s' in the config", pagerduty.PagerDutyTeamIDsKey)
+	}
+
+	pdProvider, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		WithTeamIdList(teamIDs).
+		Init()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	onCalls, err := pdProvider.GetOnCalls(now, now.Add(o.window))
+	if err != nil {
+		return err
+	}
+	shifts := currentAndNextShifts(onCalls, now)
+	if len(shifts) == 0 {
+		fmt.Println("No one is on call for these teams")
+		return nil
+	}
+	return printOnCallShifts(shifts, locations, now)
+}
+
+func loadLocations(timezones []string) ([]*time.Location, error) {
+	locations := make([]*time.Location, 0, len(timezones))
+	for _, timezone := range timezones {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone '%s': %w", timezone, err)
+		}
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+// parseOnCallShift converts an on-call of the PagerDuty API, the start and end are empty for permanent on-calls
+func parseOnCallShift(onCall pd.OnCall) (onCallShift, error) {
+	shift := onCallShift{
+		Policy:       onCall.EscalationPolicy.Summary,
+		Level:        onCall.EscalationLevel,
+		Schedule:     onCall.Schedule.Summary,
+		User:         onCall.User.Summary,
+		UserTimezone: onCall.User.Timezone,
+	}
+	if onCall.User.Name != "" {
+		shift.User = onCall.User.Name
+	}
+	var err error
+	if onCall.Start != "" {
+		if shift.Start, err = time.Parse(time.RFC3339, onCall.Start); err != nil {
+			return shift, err
+		}
+	}
+	if onCall.End != "" {
+		if shift.End, err = time.Parse(time.RFC3339, onCall.End); err != nil {
+			return shift, err
+		}
+	}
+	return shift, nil
+}
+
+func (s onCallShift) covers(t time.Time) bool {
+	return !s.Start.After(t) && (s.End.IsZero() || t.Before(s.End))
+}
+
+// currentAndNextShifts returns, for every schedule of every escalation level, the shift on call at now and
+// the one following it, sorted by policy, level and schedule
+func currentAndNextShifts(onCalls []pd.OnCall, now time.Time) []onCallShift {
+	type levelKey struct {
+		policy   string
+		level    uint
+		schedule string
+	}
+	byLevel := map[levelKey][]onCallShift{}
+	var keys []levelKey
+	for _, onCall := range onCalls {
+		shift, err := parseOnCallShift(onCall)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping the on-call of %s: %v\n", shift.User, err)
+			continue
+		}
+		key := levelKey{onCall.EscalationPolicy.ID, onCall.EscalationLevel, onCall.Schedule.ID}
+		if _, ok := byLevel[key]; !ok {
+			keys = append(keys, key)
+		}
+		byLevel[key] = append(byLevel[key], shift)
+	}
+
+	var shifts []onCallShift
+	for _, key := range keys {
+		levelShifts := byLevel[key]
+		sort.SliceStable(levelShifts, func(i, j int) bool {
+			return levelShifts[i].Start.Before(levelShifts[j].Start)
+		})
+		nextAfter := now
+		for i := range levelShifts {
+			if levelShifts[i].covers(now) {
+				levelShifts[i].Current = true
+				shifts = append(shifts, levelShifts[i])
+				nextAfter = levelShifts[i].End
+				break
+			}
+		}
+		// Nobody takes over from a permanent on-call
+		if nextAfter.IsZero() {
+			continue
+		}
+		for _, shift := range levelShifts {
+			if !shift.Start.Before(nextAfter) {
+				shifts = append(shifts, shift)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(shifts, func(i, j int) bool {
+		a, b := shifts[i], shifts[j]
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		if a.Schedule != b.Schedule {
+			return a.Schedule < b.Schedule
+		}
+		return a.Start.Before(b.Start)
+	})
+	return shifts
+}
+
+// formatShift returns the times of the shift in the location
+func formatShift(shift onCallShift, location *time.Location) string {
+	if shift.Start.IsZero() && shift.End.IsZero() {
+		return "always"
+	}
+	start := shift.Start.In(location).Format(onCallTimeFormat)
+	if shift.End.IsZero() {
+		return "from " + start
+	}
+	return start + " - " + shift.End.In(location).Format(onCallTimeFormat)
+}
+
+// userLocalTime returns the time at now where the user lives, empty when their timezone is unknown
+func userLocalTime(shift onCallShift, now time.Time) string {
+	if shift.UserTimezone == "" {
+		return ""
+	}
+	location, err := time.LoadLocation(shift.UserTimezone)
+	if err != nil {
+		return ""
+	}
+	return now.In(location).Format("15:04 MST")
+}
+
+func printOnCallShifts(shifts []onCallShift, locations []*time.Location, now time.Time) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	header := []string{"POLICY", "LEVEL", "SCHEDULE", "WHEN", "ON CALL", "THEIR TIME"}
+	for _, location := range locations {
+		header = append(header, fmt.Sprintf("SHIFT (%s)", location))
+	}
+	table.AddRow(header)
+	for _, shift := range shifts {
+		when := "next"
+		if shift.Current {
+			when = "now"
+		}
+		schedule := shift.Schedule
+		if schedule == "" {
+			schedule = "-"
+		}
+		row := []string{shift.Policy, fmt.Sprint(shift.Level), schedule, when, shift.User, userLocalTime(shift, now)}
+		for _, location := range locations {
+			row = append(row, formatShift(shift, location))
+		}
+		table.AddRow(row)
+	}
+	return table.Flush()
+}
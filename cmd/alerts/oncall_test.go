@@ -0,0 +1,60 @@
+package alerts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+)
+
+func onCall(policy string, level uint, schedule string, user string, start string, end string) pd.OnCall {
+	onCall := pd.OnCall{EscalationLevel: level, Start: start, End: end}
+	onCall.EscalationPolicy.ID = policy
+	onCall.EscalationPolicy.Summary = policy
+	onCall.Schedule.ID = schedule
+	onCall.Schedule.Summary = schedule
+	onCall.User.Summary = user
+	return onCall
+}
+
+func TestCurrentAndNextShifts(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	onCalls := []pd.OnCall{
+		onCall("SREP", 1, "Primary", "carol", "2024-03-02T00:00:00Z", "2024-03-02T08:00:00Z"),
+		onCall("SREP", 1, "Primary", "bob", "2024-03-01T16:00:00Z", "2024-03-02T00:00:00Z"),
+		onCall("SREP", 1, "Primary", "alice", "2024-03-01T08:00:00Z", "2024-03-01T16:00:00Z"),
+		onCall("SREP", 2, "Secondary", "dave", "2024-03-01T20:00:00Z", "2024-03-02T20:00:00Z"),
+		onCall("SREP", 3, "", "manager", "", ""),
+		onCall("SREP", 2, "Secondary", "erin", "invalid", "2024-03-02T20:00:00Z"),
+	}
+
+	var got []string
+	for _, shift := range currentAndNextShifts(onCalls, now) {
+		got = append(got, shift.User)
+		if shift.Current != (shift.User == "alice" || shift.User == "manager") {
+			t.Errorf("%s Current = %t", shift.User, shift.Current)
+		}
+	}
+	// Nobody is on the secondary schedule now, dave is the next
+	if want := []string{"alice", "bob", "dave", "manager"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("currentAndNextShifts() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatShift(t *testing.T) {
+	prague, err := time.LoadLocation("Europe/Prague")
+	if err != nil {
+		t.Skip(err)
+	}
+	shift := onCallShift{
+		Start: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC),
+	}
+	if got, want := formatShift(shift, prague), "Fri Mar 01 09:00 - Fri Mar 01 17:00"; got != want {
+		t.Errorf("formatShift() = %s, want %s", got, want)
+	}
+	if got, want := formatShift(onCallShift{}, time.UTC), "always"; got != want {
+		t.Errorf("formatShift() = %s, want %s", got, want)
+	}
+}
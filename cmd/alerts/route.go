@@ -0,0 +1,261 @@
+package alerts
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type routeOptions struct {
+	clusterID string
+	alertName string
+	severity  string
+	summary   string
+	fields    map[string]string
+	teamIDs   []string
+}
+
+// alertRoute is where an alert goes on a service and why
+type alertRoute struct {
+	Service string
+	Policy  string
+	Teams   []string
+	Outcome string
+	Urgency string
+	Reasons []string
+}
+
+// NewCmdRoute implements the alert route command
+func NewCmdRoute() *cobra.Command {
+	ops := &routeOptions{}
+	routeCmd := &cobra.Command{
+		Use:   "route <cluster-id> <alert-name>",
+		Short: "Preview which PagerDuty service and escalation policy an alert of a cluster pages",
+		Long: fmt.Sprintf(`Preview where an alert of a cluster would be routed in PagerDuty, and why, without firing it: the
+services named after the base domain of the cluster, their escalation policies and teams compared with the
+teams configured as '%s', and the event orchestration rules of each service applied to the alert.
+
+The alert is described by the fields its conditions test, event.summary and event.severity by default. The
+orchestration conditions testing other fields can't be evaluated until the fields are given with --field.`, pagerduty.PagerDutyTeamIDsKey),
+		Example: `  osdctl alert route <cluster-id> KubeAPIDown
+
+  # A warning, with a custom detail the orchestration rules test
+  osdctl alert route <cluster-id> KubePodCrashLooping --severity warning --field event.custom_details.namespace=openshift-monitoring`,
+		Args:              cobra.ExactArgs(2),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			ops.alertName = args[1]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	routeCmd.Flags().StringVar(&ops.severity, "severity", "critical", "Severity of the alert, one of critical, error, warning or info")
+	routeCmd.Flags().StringVar(&ops.summary, "summary", "", "Summary of the event, defaults to the title of the incidents the clusters create: '<alert-name> <SEVERITY> (1)'")
+	routeCmd.Flags().StringToStringVar(&ops.fields, "field", map[string]string{}, "Other field of the event, as referenced by the orchestration conditions, e.g. event.source=<value>. Can be repeated")
+	routeCmd.Flags().StringSliceVar(&ops.teamIDs, "team-ids", []string{}, fmt.Sprintf("PagerDuty team IDs expected to be paged, defaults to '%s' of the config", pagerduty.PagerDutyTeamIDsKey))
+
+	return routeCmd
+}
+
+func (o *routeOptions) run() error {
+	if !slices.Contains([]string{"critical", "error", "warning", "info"}, o.severity) {
+		return fmt.Errorf("invalid severity '%s', expected one of critical, error, warning or info", o.severity)
+	}
+	teamIDs := o.teamIDs
+	if len(teamIDs) == 0 {
+		teamIDs = viper.GetStringSlice(pagerduty.PagerDutyTeamIDsKey)
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	baseDomain := cluster.DNS().BaseDomain()
+
+	pdProvider, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		WithBaseDomain(baseDomain).
+		Init()
+	if err != nil {
+		return err
+	}
+	services, err := pdProvider.GetClusterServices()
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		return fmt.Errorf("no PagerDuty service is named after %s, the alerts of the cluster don't page anyone", baseDomain)
+	}
+
+	event := alertEvent(o.alertName, o.severity, o.summary, o.fields)
+	fmt.Printf("Routing of %s (%s) for cluster %s (%s)\n", o.alertName, o.severity, cluster.Name(), baseDomain)
+	var routes []alertRoute
+	for _, service := range services {
+		orchestration, err := pdProvider.GetServiceOrchestration(service.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		routes = append(routes, routeAlert(service, orchestration, event, teamIDs))
+	}
+	return printAlertRoutes(routes)
+}
+
+// alertEvent returns the fields of the event PagerDuty receives for the alert, which the orchestration
+// conditions test. The summary defaults to the one of the clusters, the alert name followed by its severity.
+func alertEvent(alertName string, severity string, summary string, fields map[string]string) map[string]string {
+	if summary == "" {
+		summary = fmt.Sprintf("%s %s (1)", alertName, strings.ToUpper(severity))
+	}
+	event := map[string]string{
+		"event.summary":  summary,
+		"event.severity": severity,
+	}
+	for field, value := range fields {
+		event[field] = value
+	}
+	return event
+}
+
+// routeAlert explains what the service does with the event: whether its orchestration lets it page, on
+// which escalation policy, with which urgency, and whether the expected teams own the service
+func routeAlert(service pd.Service, orchestration *pagerduty.ServiceOrchestration, event map[string]string, teamIDs []string) alertRoute {
+	route := alertRoute{
+		Service: service.Name,
+		Policy:  service.EscalationPolicy.Name,
+		Outcome: "pages",
+		Reasons: []string{fmt.Sprintf("service %s (%s) is named after the base domain", service.Name, service.ID)},
+	}
+	if route.Policy == "" {
+		route.Policy = service.EscalationPolicy.Summary
+	}
+
+	var teamMatch []string
+	for _, team := range service.Teams {
+		name := team.Name
+		if name == "" {
+			name = team.Summary
+		}
+		route.Teams = append(route.Teams, name)
+		if slices.Contains(teamIDs, team.ID) {
+			teamMatch = append(teamMatch, name)
+		}
+	}
+	switch {
+	case len(teamIDs) == 0:
+		route.Reasons = append(route.Reasons, "no team configured to compare the teams of the service with")
+	case len(teamMatch) > 0:
+		route.Reasons = append(route.Reasons, fmt.Sprintf("team %s is one of the configured teams", strings.Join(teamMatch, ", ")))
+	default:
+		route.Reasons = append(route.Reasons, fmt.Sprintf("MISROUTED: none of the teams of the service (%s) is one of the configured teams", strings.Join(route.Teams, ", ")))
+	}
+
+	if service.Status == "disabled" {
+		route.Outcome = "dropped"
+		route.Reasons = append(route.Reasons, "the service is disabled, its events are dropped")
+		return route
+	}
+
+	severity := event["event.severity"]
+	switch {
+	case orchestration == nil:
+		route.Reasons = append(route.Reasons, "the event orchestration couldn't be read, its rules weren't applied")
+	case !orchestration.Active:
+		route.Reasons = append(route.Reasons, "the event orchestration of the service isn't active, the legacy event rules, if any, apply instead")
+	default:
+		result := orchestration.Evaluate(event)
+		for _, match := range result.Matches {
+			route.Reasons = append(route.Reasons, fmt.Sprintf("rule '%s' of set %s matched: %s", match.Rule, match.Set, match.Condition))
+		}
+		if result.CatchAll {
+			route.Reasons = append(route.Reasons, "no other rule matched, the catch-all actions apply")
+		}
+		for _, undetermined := range result.Undetermined {
+			route.Reasons = append(route.Reasons, "UNDETERMINED, assumed not to match: "+undetermined)
+		}
+		if len(result.UnknownFields) > 0 {
+			route.Reasons = append(route.Reasons, fmt.Sprintf("pass %s with --field to evaluate all the rules", strings.Join(result.UnknownFields, ", ")))
+		}
+
+		actions := result.Actions
+		switch {
+		case actions.Suppress:
+			route.Outcome = "suppressed"
+			route.Reasons = append(route.Reasons, "the event is suppressed, it creates an alert but no incident")
+		case actions.EventAction == "resolve":
+			route.Outcome = "resolves"
+			route.Reasons = append(route.Reasons, "the event is turned into a resolve event")
+		case actions.Suspend != nil && *actions.Suspend > 0:
+			route.Outcome = fmt.Sprintf("pages after %ds", *actions.Suspend)
+			route.Reasons = append(route.Reasons, fmt.Sprintf("the alert is suspended for %d seconds, and pages if it isn't resolved by then", *actions.Suspend))
+		}
+		if actions.Severity != "" && actions.Severity != severity {
+			route.Reasons = append(route.Reasons, fmt.Sprintf("the severity is changed to %s", actions.Severity))
+			severity = actions.Severity
+		}
+		if actions.Priority != "" {
+			route.Reasons = append(route.Reasons, fmt.Sprintf("the priority is set to %s", actions.Priority))
+		}
+	}
+
+	route.Urgency = incidentUrgency(service.IncidentUrgencyRule, severity)
+	return route
+}
+
+// incidentUrgency returns the urgency of the incidents the service creates for an event of the severity
+func incidentUrgency(rule *pd.IncidentUrgencyRule, severity string) string {
+	if rule == nil {
+		return "high"
+	}
+	switch rule.Type {
+	case "constant":
+		return rule.Urgency
+	case "severity_based":
+		if severity == "critical" || severity == "error" {
+			return "high"
+		}
+		return "low"
+	case "use_support_hours":
+		urgency := func(urgencyType *pd.IncidentUrgencyType) string {
+			if urgencyType == nil {
+				return "?"
+			}
+			return urgencyType.Urgency
+		}
+		return fmt.Sprintf("%s in support hours, %s outside", urgency(rule.DuringSupportHours), urgency(rule.OutsideSupportHours))
+	}
+	return rule.Type
+}
+
+func printAlertRoutes(routes []alertRoute) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"SERVICE", "ESCALATION POLICY", "TEAMS", "OUTCOME", "URGENCY"})
+	for _, route := range routes {
+		table.AddRow([]string{route.Service, route.Policy, strings.Join(route.Teams, ", "), route.Outcome, route.Urgency})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	for _, route := range routes {
+		fmt.Printf("\n%s:\n", route.Service)
+		for _, reason := range route.Reasons {
+			fmt.Printf("  - %s\n", reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package alerts
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const week = 7 * 24 * time.Hour
+
+type statsOptions struct {
+	teamIDs []string
+	since   string
+	top     int
+	limit   int
+	output  string
+}
+
+// alertStats are the statistics of the incidents of an alert
+type alertStats struct {
+	Name      string
+	Incidents int
+	Clusters  int
+	// Incidents created in the last 7 days and in the 7 days before
+	ThisWeek int
+	LastWeek int
+	// Average time to acknowledge and to resolve, zero when unknown
+	MTTA time.Duration
+	MTTR time.Duration
+
+	clusters     map[string]bool
+	acknowledged []time.Duration
+	resolved     []time.Duration
+}
+
+// clusterStats are the statistics of the incidents of a cluster, i.e. of a PagerDuty service
+type clusterStats struct {
+	Service   string
+	Incidents int
+	TopAlert  string
+
+	alerts map[string]int
+}
+
+// NewCmdStats implements the alert stats command
+func NewCmdStats() *cobra.Command {
+	ops := &statsOptions{}
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Summarize the PagerDuty incidents of the teams for the alert review",
+		Long: fmt.Sprintf(`Summarize the PagerDuty incidents of the teams configured as '%s' over a period: the number of
+incidents of every alert with their week-over-week change, the average time to acknowledge (MTTA) and to
+resolve (MTTR) them, and the noisiest clusters.
+
+MTTA and MTTR are estimates: PagerDuty only keeps the acknowledgements of the incidents still open, and a
+resolved incident is assumed to be resolved at its last status change.`, pagerduty.PagerDutyTeamIDsKey),
+		Example: `  # Alert review of the last 30 days
+  osdctl alert stats --since 30d
+
+  # Top 20 noisiest clusters of another team, for a spreadsheet
+  osdctl alert stats --team-ids PXXXXXX --top 20 -o csv > stats.csv`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	statsCmd.Flags().StringSliceVar(&ops.teamIDs, "team-ids", []string{}, fmt.Sprintf("PagerDuty team IDs, defaults to '%s' of the config", pagerduty.PagerDutyTeamIDsKey))
+	statsCmd.Flags().StringVar(&ops.since, "since", "30d", "Period of the incidents, e.g. 30d or 72h")
+	statsCmd.Flags().IntVar(&ops.top, "top", 10, "Number of noisiest clusters listed")
+	statsCmd.Flags().IntVar(&ops.limit, "limit", pagerduty.DefaultIncidentLimit, "Maximum number of incidents summarized, the oldest first")
+	statsCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Output format, one of table or csv")
+
+	return statsCmd
+}
+
+func (o *statsOptions) validate() error {
+	if o.output != "table" && o.output != "csv" {
+		return fmt.Errorf("unknown output format '%s', expected table or csv", o.output)
+	}
+	if err := validateIncidentLimit(o.limit); err != nil {
+		return err
+	}
+	if o.top < 0 {
+		return fmt.Errorf("--top can't be negative")
+	}
+	if _, err := ctUtil.ParseDuration(o.since); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	return nil
+}
+
+func (o *statsOptions) run() error {
+	period, _ := ctUtil.ParseDuration(o.since)
+	teamIDs := o.teamIDs
+	if len(teamIDs) == 0 {
+		teamIDs = viper.GetStringSlice(pagerduty.PagerDutyTeamIDsKey)
+	}
+	if len(teamIDs) == 0 {
+		return fmt.Errorf("no PagerDuty team, pass --team-ids or set '%s' in the config", pagerduty.PagerDutyTeamIDsKey)
+	}
+
+	pdProvider, err := pagerduty.NewClient().
+		WithUserToken(viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)).
+		WithTeamIdList(teamIDs).
+		WithIncidentLimit(o.limit).
+		Init()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	incidents, err := pdProvider.GetTeamIncidents(now.Add(-period), now)
+	if err != nil {
+		return err
+	}
+	if len(incidents) == 0 {
+		fmt.Println("No incidents found")
+		return nil
+	}
+
+	alerts, clusters := computeAlertStats(incidents, now)
+	if o.top < len(clusters) {
+		clusters = clusters[:o.top]
+	}
+	if o.output == "csv" {
+		return printStatsCSV(alerts, clusters)
+	}
+	return printStats(alerts, clusters, len(incidents))
+}
+
+// alertName is the name of the alert of an incident, the first word of its title
+func alertName(incident pd.Incident) string {
+	fields := strings.Fields(incident.Title)
+	if len(fields) == 0 {
+		return "<untitled>"
+	}
+	return fields[0]
+}
+
+func parsePDTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, err == nil
+}
+
+func averageDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+	return total / time.Duration(len(durations))
+}
+
+// computeAlertStats returns the statistics by alert, most frequent first, and by cluster, noisiest first
+func computeAlertStats(incidents []pd.Incident, now time.Time) ([]*alertStats, []*clusterStats) {
+	byAlert := map[string]*alertStats{}
+	byCluster := map[string]*clusterStats{}
+
+	for _, incident := range incidents {
+		name := alertName(incident)
+		alert, ok := byAlert[name]
+		if !ok {
+			alert = &alertStats{Name: name, clusters: map[string]bool{}}
+			byAlert[name] = alert
+		}
+		alert.Incidents++
+
+		service := incident.Service.Summary
+		alert.clusters[service] = true
+		cluster, ok := byCluster[service]
+		if !ok {
+			cluster = &clusterStats{Service: service, alerts: map[string]int{}}
+			byCluster[service] = cluster
+		}
+		cluster.Incidents++
+		cluster.alerts[name]++
+
+		created, ok := parsePDTime(incident.CreatedAt)
+		if !ok {
+			continue
+		}
+		switch age := now.Sub(created); {
+		case age < week:
+			alert.ThisWeek++
+		case age < 2*week:
+			alert.LastWeek++
+		}
+		if len(incident.Acknowledgements) > 0 {
+			if acknowledged, ok := parsePDTime(incident.Acknowledgements[0].At); ok {
+				alert.acknowledged = append(alert.acknowledged, acknowledged.Sub(created))
+			}
+		}
+		if incident.Status == "resolved" {
+			if resolved, ok := parsePDTime(incident.LastStatusChangeAt); ok {
+				alert.resolved = append(alert.resolved, resolved.Sub(created))
+			}
+		}
+	}
+
+	alerts := make([]*alertStats, 0, len(byAlert))
+	for _, alert := range byAlert {
+		alert.Clusters = len(alert.clusters)
+		alert.MTTA = averageDuration(alert.acknowledged)
+		alert.MTTR = averageDuration(alert.resolved)
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Incidents != alerts[j].Incidents {
+			return alerts[i].Incidents > alerts[j].Incidents
+		}
+		return alerts[i].Name < alerts[j].Name
+	})
+
+	clusters := make([]*clusterStats, 0, len(byCluster))
+	for _, cluster := range byCluster {
+		for name, count := range cluster.alerts {
+			if count > cluster.alerts[cluster.TopAlert] || (count == cluster.alerts[cluster.TopAlert] && name < cluster.TopAlert) {
+				cluster.TopAlert = name
+			}
+		}
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Incidents != clusters[j].Incidents {
+			return clusters[i].Incidents > clusters[j].Incidents
+		}
+		return clusters[i].Service < clusters[j].Service
+	})
+
+	return alerts, clusters
+}
+
+// formatDelta returns the week-over-week change, e.g. +3 (+50%)
+func formatDelta(thisWeek int, lastWeek int) string {
+	delta := thisWeek - lastWeek
+	if lastWeek == 0 {
+		return fmt.Sprintf("%+d", delta)
+	}
+	return fmt.Sprintf("%+d (%+.0f%%)", delta, float64(delta)*100/float64(lastWeek))
+}
+
+func formatStatDuration(duration time.Duration) string {
+	if duration == 0 {
+		return "-"
+	}
+	return duration.Round(time.Minute).String()
+}
+
+func printStats(alerts []*alertStats, clusters []*clusterStats, total int) error {
+	fmt.Printf("%d incidents, %d alerts\n\n", total, len(alerts))
+
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"ALERT", "INCIDENTS", "CLUSTERS", "LAST 7D", "PREVIOUS 7D", "DELTA", "MTTA", "MTTR"})
+	for _, alert := range alerts {
+		table.AddRow([]string{
+			alert.Name,
+			strconv.Itoa(alert.Incidents),
+			strconv.Itoa(alert.Clusters),
+			strconv.Itoa(alert.ThisWeek),
+			strconv.Itoa(alert.LastWeek),
+			formatDelta(alert.ThisWeek, alert.LastWeek),
+			formatStatDuration(alert.MTTA),
+			formatStatDuration(alert.MTTR),
+		})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("Noisiest clusters:")
+	table = printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"SERVICE", "INCIDENTS", "TOP ALERT"})
+	for _, cluster := range clusters {
+		table.AddRow([]string{cluster.Service, strconv.Itoa(cluster.Incidents), cluster.TopAlert})
+	}
+	return table.Flush()
+}
+
+// printStatsCSV prints the statistics of the alerts then of the clusters, told apart by the first column
+func printStatsCSV(alerts []*alertStats, clusters []*clusterStats) error {
+	writer := csv.NewWriter(os.Stdout)
+	if err := writer.Write([]string{"TYPE", "NAME", "INCIDENTS", "CLUSTERS", "LAST 7D", "PREVIOUS 7D", "MTTA SECONDS", "MTTR SECONDS", "TOP ALERT"}); err != nil {
+		return err
+	}
+	for _, alert := range alerts {
+		row := []string{
+			"alert",
+			alert.Name,
+			strconv.Itoa(alert.Incidents),
+			strconv.Itoa(alert.Clusters),
+			strconv.Itoa(alert.ThisWeek),
+			strconv.Itoa(alert.LastWeek),
+			strconv.Itoa(int(alert.MTTA.Seconds())),
+			strconv.Itoa(int(alert.MTTR.Seconds())),
+			"",
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	for _, cluster := range clusters {
+		if err := writer.Write([]string{"cluster", cluster.Service, strconv.Itoa(cluster.Incidents), "", "", "", "", "", cluster.TopAlert}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,66 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+)
+
+func statsIncident(title string, service string, created time.Time, status string) pd.Incident {
+	incident := pd.Incident{Title: title, Status: status, CreatedAt: created.Format(time.RFC3339)}
+	incident.Service.Summary = service
+	return incident
+}
+
+func TestComputeAlertStats(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	resolved := statsIncident("ClusterOperatorDown CRITICAL (1)", "osd-a", now.Add(-2*time.Hour), "resolved")
+	resolved.LastStatusChangeAt = now.Add(-time.Hour).Format(time.RFC3339)
+	acknowledged := statsIncident("ClusterOperatorDown CRITICAL (1)", "osd-b", now.Add(-10*24*time.Hour), "acknowledged")
+	acknowledged.Acknowledgements = []pd.Acknowledgement{{At: now.Add(-10*24*time.Hour + 10*time.Minute).Format(time.RFC3339)}}
+	incidents := []pd.Incident{
+		resolved,
+		acknowledged,
+		statsIncident("ClusterOperatorDown CRITICAL (1)", "osd-a", now.Add(-24*time.Hour), "triggered"),
+		statsIncident("KubeNodeNotReady WARNING (1)", "osd-a", now.Add(-20*24*time.Hour), "resolved"),
+	}
+
+	alerts, clusters := computeAlertStats(incidents, now)
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(alerts))
+	}
+	got := alerts[0]
+	if got.Name != "ClusterOperatorDown" || got.Incidents != 3 || got.Clusters != 2 || got.ThisWeek != 2 || got.LastWeek != 1 {
+		t.Errorf("unexpected stats %+v", got)
+	}
+	if got.MTTA != 10*time.Minute || got.MTTR != time.Hour {
+		t.Errorf("MTTA = %v, MTTR = %v", got.MTTA, got.MTTR)
+	}
+	// Resolved without a last status change, the MTTR is unknown
+	if alerts[1].Name != "KubeNodeNotReady" || alerts[1].MTTR != 0 || alerts[1].ThisWeek != 0 || alerts[1].LastWeek != 0 {
+		t.Errorf("unexpected stats %+v", alerts[1])
+	}
+
+	if len(clusters) != 2 || clusters[0].Service != "osd-a" || clusters[0].Incidents != 3 || clusters[0].TopAlert != "ClusterOperatorDown" {
+		t.Errorf("unexpected cluster stats %+v", clusters[0])
+	}
+}
+
+func TestFormatDelta(t *testing.T) {
+	tests := []struct {
+		thisWeek int
+		lastWeek int
+		want     string
+	}{
+		{thisWeek: 3, lastWeek: 2, want: "+1 (+50%)"},
+		{thisWeek: 1, lastWeek: 4, want: "-3 (-75%)"},
+		{thisWeek: 2, lastWeek: 0, want: "+2"},
+		{thisWeek: 0, lastWeek: 0, want: "+0"},
+	}
+	for _, tt := range tests {
+		if got := formatDelta(tt.thisWeek, tt.lastWeek); got != tt.want {
+			t.Errorf("formatDelta(%d, %d) = %s, want %s", tt.thisWeek, tt.lastWeek, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-online/ocm-cli/pkg/arguments"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// pageSize is the number of items requested per page when following the pages of a list
+const pageSize = 100
+
+type apiOptions struct {
+	method   string
+	path     string
+	params   []string
+	bodyFile string
+	noPaging bool
+	yes      bool
+
+	body []byte
+}
+
+func NewCmdApi() *cobra.Command {
+	ops := &apiOptions{}
+	apiCmd := &cobra.Command{
+		Use:   "api <method> <path>",
+		Short: "Send a request to any OCM API endpoint using the current OCM login",
+		Long: `Send a request to any OCM API endpoint, reusing the OCM configuration osdctl already uses, and print the
+JSON response. This is an escape hatch for scripting endpoints osdctl has no command for.
+
+The pages of GET list responses are followed automatically and returned as a single list, unless
+--no-paging is set or a 'page' parameter is passed. Requests other than GET ask for a confirmation.`,
+		Example: `  # Get the add-ons installed on a cluster
+  osdctl api GET /api/clusters_mgmt/v1/clusters/<cluster-id>/addons
+
+  # Search every cluster of an organization
+  osdctl api GET /api/clusters_mgmt/v1/clusters --param "search=organization.id='1a2B3c'"
+
+  # Patch a cluster with a body read from a file, or - for stdin
+  osdctl api PATCH /api/clusters_mgmt/v1/clusters/<cluster-id> --body patch.json`,
+		Args:              cobra.ExactArgs(2),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.method = strings.ToUpper(args[0])
+			ops.path = args[1]
+			cmdutil.CheckErr(ops.complete())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	apiCmd.Flags().StringArrayVar(&ops.params, "param", []string{}, "Query parameter as name=value, can be repeated")
+	apiCmd.Flags().StringVar(&ops.bodyFile, "body", "", "File containing the JSON request body, - reads it from stdin")
+	apiCmd.Flags().BoolVar(&ops.noPaging, "no-paging", false, "Only return the first page of list responses")
+	apiCmd.Flags().BoolVarP(&ops.yes, "yes", "y", false, "Don't ask for a confirmation before sending requests other than GET")
+
+	return apiCmd
+}
+
+func (o *apiOptions) complete() error {
+	switch o.method {
+	case http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+	default:
+		return fmt.Errorf("unsupported method '%s', expected one of GET, POST, PATCH, PUT, DELETE", o.method)
+	}
+
+	if !strings.HasPrefix(o.path, "/api/") {
+		return fmt.Errorf("invalid path '%s', OCM API paths start with /api/", o.path)
+	}
+
+	for _, param := range o.params {
+		if name, _, found := strings.Cut(param, "="); !found || name == "" {
+			return fmt.Errorf("invalid parameter '%s', expected name=value", param)
+		}
+	}
+
+	if o.bodyFile != "" {
+		var err error
+		if o.bodyFile == "-" {
+			o.body, err = io.ReadAll(os.Stdin)
+		} else {
+			o.body, err = os.ReadFile(o.bodyFile)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the request body: %w", err)
+		}
+		if !json.Valid(o.body) {
+			return fmt.Errorf("the request body isn't valid JSON")
+		}
+	}
+
+	return nil
+}
+
+func (o *apiOptions) run() error {
+	if o.method != http.MethodGet && !o.yes {
+		fmt.Printf("Sending %s %s\n", o.method, o.path)
+		if !utils.ConfirmPrompt() {
+			return nil
+		}
+	}
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := ocmClient.Close(); err != nil {
+			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
+		}
+	}()
+
+	var body []byte
+	if o.method == http.MethodGet && !o.noPaging && !o.hasParam("page") {
+		body, err = fetchAllPages(func(page int) ([]byte, error) {
+			return o.send(ocmClient, map[string]string{"page": strconv.Itoa(page), "size": strconv.Itoa(pageSize)})
+		})
+	} else {
+		body, err = o.send(ocmClient, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	return printJSON(body)
+}
+
+func (o *apiOptions) hasParam(name string) bool {
+	for _, param := range o.params {
+		if strings.HasPrefix(param, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// send sends the request with the given extra parameters and returns the response body
+func (o *apiOptions) send(ocmClient *sdk.Connection, extraParams map[string]string) ([]byte, error) {
+	var request *sdk.Request
+	switch o.method {
+	case http.MethodGet:
+		request = ocmClient.Get()
+	case http.MethodPost:
+		request = ocmClient.Post()
+	case http.MethodPatch:
+		request = ocmClient.Patch()
+	case http.MethodPut:
+		request = ocmClient.Put()
+	case http.MethodDelete:
+		request = ocmClient.Delete()
+	}
+
+	if err := arguments.ApplyPathArg(request, o.path); err != nil {
+		return nil, fmt.Errorf("cannot parse API path '%s': %v", o.path, err)
+	}
+	arguments.ApplyParameterFlag(request, o.params)
+	for name, value := range extraParams {
+		request.Parameter(name, value)
+	}
+	if o.body != nil {
+		request.Bytes(o.body)
+	}
+
+	response, err := utils.SendRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status() >= http.StatusBadRequest {
+		return nil, fmt.Errorf("%s %s returned %d: %s", o.method, o.path, response.Status(), string(response.Bytes()))
+	}
+	return response.Bytes(), nil
+}
+
+func printJSON(body []byte) error {
+	if len(body) == 0 {
+		return nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		// Not JSON, print it as it is
+		fmt.Println(string(body))
+		return nil
+	}
+	fmt.Println(out.String())
+	return nil
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// listPage is the part of an OCM list response needed to follow its pages
+type listPage struct {
+	Page  int               `json:"page"`
+	Size  int               `json:"size"`
+	Total int               `json:"total"`
+	Items []json.RawMessage `json:"items"`
+}
+
+// fetchAllPages calls fetch for each page of an OCM list and returns a single list holding the items of
+// every page. Responses that aren't lists are returned as they are.
+func fetchAllPages(fetch func(page int) ([]byte, error)) ([]byte, error) {
+	body, err := fetch(1)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body, nil
+	}
+	if _, isList := fields["items"]; !isList {
+		return body, nil
+	}
+
+	var first listPage
+	if err := json.Unmarshal(body, &first); err != nil {
+		return nil, fmt.Errorf("failed to parse list response: %w", err)
+	}
+
+	items := first.Items
+	lastPageSize := len(first.Items)
+	for page := 2; len(items) < first.Total && lastPageSize > 0; page++ {
+		body, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		var next listPage
+		if err := json.Unmarshal(body, &next); err != nil {
+			return nil, fmt.Errorf("failed to parse page %d: %w", page, err)
+		}
+		items = append(items, next.Items...)
+		lastPageSize = len(next.Items)
+	}
+
+	for key, value := range map[string]interface{}{
+		"items": items,
+		"page":  1,
+		"size":  len(items),
+		"total": len(items),
+	} {
+		if fields[key], err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakePages serves total items, size items per page, in the OCM list format
+func fakePages(total int, size int, calls *int) func(page int) ([]byte, error) {
+	return func(page int) ([]byte, error) {
+		*calls++
+		var items []map[string]string
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			items = append(items, map[string]string{"id": fmt.Sprint(i)})
+		}
+		return json.Marshal(map[string]interface{}{
+			"kind":  "ClusterList",
+			"page":  page,
+			"size":  len(items),
+			"total": total,
+			"items": items,
+		})
+	}
+}
+
+func TestFetchAllPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		size      int
+		wantItems int
+		wantCalls int
+	}{
+		{name: "single page", total: 3, size: 100, wantItems: 3, wantCalls: 1},
+		{name: "several pages", total: 250, size: 100, wantItems: 250, wantCalls: 3},
+		{name: "exact pages", total: 200, size: 100, wantItems: 200, wantCalls: 2},
+		{name: "empty list", total: 0, size: 100, wantItems: 0, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			body, err := fetchAllPages(fakePages(tt.total, tt.size, &calls))
+			if err != nil {
+				t.Fatalf("fetchAllPages() error = %v", err)
+			}
+
+			var result struct {
+				Kind  string            `json:"kind"`
+				Size  int               `json:"size"`
+				Total int               `json:"total"`
+				Items []json.RawMessage `json:"items"`
+			}
+			if err := json.Unmarshal(body, &result); err != nil {
+				t.Fatalf("invalid result %s: %v", body, err)
+			}
+			if len(result.Items) != tt.wantItems || result.Size != tt.wantItems || result.Total != tt.wantItems {
+				t.Errorf("got %d items (size %d, total %d), want %d", len(result.Items), result.Size, result.Total, tt.wantItems)
+			}
+			if result.Kind != "ClusterList" {
+				t.Errorf("kind = %s, want the fields of the first page to be kept", result.Kind)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("fetched %d pages, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestFetchAllPagesNotAList(t *testing.T) {
+	object := []byte(`{"kind":"Cluster","id":"abc"}`)
+	body, err := fetchAllPages(func(page int) ([]byte, error) { return object, nil })
+	if err != nil || string(body) != string(object) {
+		t.Errorf("fetchAllPages() = %s, %v, want the object unchanged", body, err)
+	}
+
+	_, err = fetchAllPages(func(page int) ([]byte, error) { return nil, errors.New("unauthorized") })
+	if err == nil {
+		t.Error("fetchAllPages() should return the fetch error")
+	}
+}
@@ -0,0 +1,82 @@
+package bookmark
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/bookmark"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type addOptions struct {
+	name      string
+	clusterID string
+	force     bool
+}
+
+func newCmdAdd() *cobra.Command {
+	ops := &addOptions{}
+	addCmd := &cobra.Command{
+		Use:   "add <name> <cluster-id>",
+		Short: "Bookmark a cluster under a name",
+		Long: `Bookmark a cluster under a name, which can then be given in place of its ID. The cluster can be given by any
+identifier, it's looked up in OCM and saved by its internal ID.
+
+Names are made of letters, digits, '.', '_' and '-', and can't look like a cluster ID. A bookmark takes precedence
+over a cluster named the same.`,
+		Example: `  osdctl bookmark add prod-payments <cluster-id>
+  osdctl cluster context prod-payments`,
+		Args:              cobra.ExactArgs(2),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.name = args[0]
+			ops.clusterID = args[1]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	addCmd.Flags().BoolVarP(&ops.force, "force", "f", false, "Replace the bookmark of the same name")
+
+	return addCmd
+}
+
+func (o *addOptions) run() error {
+	if err := bookmark.ValidateName(o.name); err != nil {
+		return err
+	}
+	path, err := bookmark.File()
+	if err != nil {
+		return err
+	}
+	bookmarks, err := bookmark.Read(path)
+	if err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	bookmarks, err = bookmark.Add(bookmarks, bookmark.Bookmark{
+		Name:        o.name,
+		ClusterID:   cluster.ID(),
+		ClusterName: cluster.Name(),
+		Added:       time.Now().UTC(),
+	}, o.force)
+	if err != nil {
+		return err
+	}
+	if err := bookmark.Write(path, bookmarks); err != nil {
+		return fmt.Errorf("failed to save the bookmarks %s: %w", path, err)
+	}
+	fmt.Printf("Bookmarked cluster %s (%s) as %s\n", cluster.Name(), cluster.ID(), o.name)
+	return nil
+}
@@ -0,0 +1,23 @@
+package bookmark
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdBookmark returns the bookmark command
+func NewCmdBookmark() *cobra.Command {
+	bookmarkCmd := &cobra.Command{
+		Use:   "bookmark",
+		Short: "Name the clusters you work on, to use the names in place of their IDs",
+		Long: `Save named bookmarks of clusters. A bookmark can then be given to any osdctl command in place of the cluster
+ID, as its cluster argument or as its --cluster-id flag.
+
+The bookmarks are saved in the user cache directory (~/.cache/osdctl/bookmarks.json on Linux).`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	bookmarkCmd.AddCommand(newCmdAdd())
+	bookmarkCmd.AddCommand(newCmdList())
+	bookmarkCmd.AddCommand(newCmdRemove())
+	return bookmarkCmd
+}
@@ -0,0 +1,162 @@
+package bookmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/bookmark"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// statusBatchSize is the number of clusters looked up in OCM at once
+const statusBatchSize = 100
+
+type listOptions struct {
+	noStatus bool
+	output   string
+}
+
+// bookmarkStatus is a bookmark with the current status of its cluster
+type bookmarkStatus struct {
+	bookmark.Bookmark
+	State          string `json:"state,omitempty"`
+	Version        string `json:"version,omitempty"`
+	Cloud          string `json:"cloud,omitempty"`
+	Region         string `json:"region,omitempty"`
+	LimitedSupport int    `json:"limited_support_reasons"`
+}
+
+func newCmdList() *cobra.Command {
+	ops := &listOptions{}
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the bookmarks with the current status of their clusters",
+		Long: `List the bookmarks with the current status of their clusters from OCM: their state, version, cloud and region,
+and how many limited support reasons they have. The clusters which were deleted are shown as not found.`,
+		Example: `  osdctl bookmark list
+
+  # Only the bookmarks, without querying OCM
+  osdctl bookmark list --no-status`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	listCmd.Flags().BoolVar(&ops.noStatus, "no-status", false, "Don't query OCM for the status of the clusters")
+	listCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Valid formats are ['table', 'json']")
+
+	return listCmd
+}
+
+func (o *listOptions) run() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format '%s', valid formats are 'table' and 'json'", o.output)
+	}
+	path, err := bookmark.File()
+	if err != nil {
+		return err
+	}
+	bookmarks, err := bookmark.Read(path)
+	if err != nil {
+		return err
+	}
+
+	statuses := make([]bookmarkStatus, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		statuses = append(statuses, bookmarkStatus{Bookmark: b})
+	}
+	if !o.noStatus && len(bookmarks) > 0 {
+		connection, err := utils.CreateConnection()
+		if err != nil {
+			return err
+		}
+		defer connection.Close()
+		clusters, err := lookupClusters(connection, bookmarks)
+		if err != nil {
+			return err
+		}
+		statuses = withClusterStatus(statuses, clusters)
+	}
+
+	if o.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statuses)
+	}
+	if len(statuses) == 0 {
+		fmt.Println("No bookmark, add one with 'osdctl bookmark add <name> <cluster-id>'")
+		return nil
+	}
+	return printBookmarks(statuses, o.noStatus)
+}
+
+// lookupClusters returns the clusters of the bookmarks which still exist, by ID
+func lookupClusters(connection *sdk.Connection, bookmarks []bookmark.Bookmark) (map[string]*cmv1.Cluster, error) {
+	clusters := map[string]*cmv1.Cluster{}
+	for start := 0; start < len(bookmarks); start += statusBatchSize {
+		end := min(start+statusBatchSize, len(bookmarks))
+		ids := make([]string, 0, end-start)
+		for _, b := range bookmarks[start:end] {
+			ids = append(ids, b.ClusterID)
+		}
+		response, err := connection.ClustersMgmt().V1().Clusters().List().
+			Search(fmt.Sprintf("id in ('%s')", strings.Join(ids, "', '"))).
+			Size(statusBatchSize).
+			Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up the clusters of the bookmarks: %w", err)
+		}
+		for _, cluster := range response.Items().Slice() {
+			clusters[cluster.ID()] = cluster
+		}
+	}
+	return clusters, nil
+}
+
+func withClusterStatus(statuses []bookmarkStatus, clusters map[string]*cmv1.Cluster) []bookmarkStatus {
+	for i, status := range statuses {
+		cluster, ok := clusters[status.ClusterID]
+		if !ok {
+			statuses[i].State = "not found"
+			continue
+		}
+		statuses[i].ClusterName = cluster.Name()
+		statuses[i].State = string(cluster.State())
+		statuses[i].Version = cluster.OpenshiftVersion()
+		statuses[i].Cloud = cluster.CloudProvider().ID()
+		statuses[i].Region = cluster.Region().ID()
+		statuses[i].LimitedSupport = cluster.Status().LimitedSupportReasonCount()
+	}
+	return statuses
+}
+
+func printBookmarks(statuses []bookmarkStatus, noStatus bool) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	if noStatus {
+		table.AddRow([]string{"NAME", "CLUSTER ID", "CLUSTER NAME", "ADDED"})
+		for _, status := range statuses {
+			table.AddRow([]string{status.Name, status.ClusterID, status.ClusterName, status.Added.Format("2006-01-02")})
+		}
+		return table.Flush()
+	}
+	table.AddRow([]string{"NAME", "CLUSTER ID", "CLUSTER NAME", "STATE", "VERSION", "CLOUD", "REGION", "LIMITED SUPPORT"})
+	for _, status := range statuses {
+		limitedSupport := ""
+		if status.State != "not found" {
+			limitedSupport = strconv.Itoa(status.LimitedSupport)
+		}
+		table.AddRow([]string{status.Name, status.ClusterID, status.ClusterName, status.State, status.Version, status.Cloud, status.Region, limitedSupport})
+	}
+	return table.Flush()
+}
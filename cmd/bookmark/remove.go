@@ -0,0 +1,43 @@
+package bookmark
+
+import (
+	"fmt"
+
+	"github.com/openshift/osdctl/pkg/bookmark"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+func newCmdRemove() *cobra.Command {
+	return &cobra.Command{
+		Use:               "remove <name>",
+		Aliases:           []string{"rm"},
+		Short:             "Remove a bookmark",
+		Example:           `  osdctl bookmark remove prod-payments`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(removeBookmark(args[0]))
+		},
+	}
+}
+
+func removeBookmark(name string) error {
+	path, err := bookmark.File()
+	if err != nil {
+		return err
+	}
+	bookmarks, err := bookmark.Read(path)
+	if err != nil {
+		return err
+	}
+	bookmarks, err = bookmark.Remove(bookmarks, name)
+	if err != nil {
+		return err
+	}
+	if err := bookmark.Write(path, bookmarks); err != nil {
+		return fmt.Errorf("failed to save the bookmarks %s: %w", path, err)
+	}
+	fmt.Printf("Removed bookmark %s\n", name)
+	return nil
+}
@@ -0,0 +1,126 @@
+package cases
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/supportcase"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// NewCmdCase returns the case command
+func NewCmdCase() *cobra.Command {
+	caseCmd := &cobra.Command{
+		Use:               "case",
+		Short:             "Red Hat Customer Portal support cases",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	caseCmd.AddCommand(newCmdList())
+	return caseCmd
+}
+
+type listOptions struct {
+	clusterID string
+	account   bool
+	output    string
+}
+
+func newCmdList() *cobra.Command {
+	ops := &listOptions{}
+	listCmd := &cobra.Command{
+		Use:   "list <cluster-id>",
+		Short: "List the open Customer Portal support cases of a cluster",
+		Long: fmt.Sprintf(`List the open support cases filed in the Red Hat Customer Portal for a cluster, through the Case
+Management API. The cases are looked up in the customer account of the organization owning the cluster.
+
+The API is authenticated with a Red Hat API offline token, read from %s or '%s' in the osdctl
+config. It can be generated at %s.`, supportcase.OfflineTokenEnvVar, supportcase.OfflineTokenConfigKey, supportcase.OfflineTokenURL),
+		Example: `  # List the open support cases of a cluster
+  osdctl case list <cluster-id>
+
+  # List every open support case of the customer account owning the cluster
+  osdctl case list <cluster-id> --account`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	listCmd.Flags().BoolVar(&ops.account, "account", false, "List every open case of the customer account, not only the cases of the cluster")
+	listCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Valid formats are ['table', 'json']")
+
+	return listCmd
+}
+
+func (o *listOptions) run() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format '%s', valid formats are 'table' and 'json'", o.output)
+	}
+	caseClient, err := supportcase.NewClient().Init()
+	if err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	cluster, err := utils.GetClusterAnyStatus(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	orgID, err := utils.GetOrgfromClusterID(connection, *cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get the organization of cluster %s: %w", cluster.ID(), err)
+	}
+	accountNumber, err := utils.GetOrgAccountNumber(connection, orgID)
+	if err != nil {
+		return err
+	}
+
+	cases, err := caseClient.GetOpenCases(accountNumber)
+	if err != nil {
+		return err
+	}
+	if !o.account {
+		cases = supportcase.ForCluster(cases, cluster.ID(), cluster.ExternalID())
+	}
+
+	if o.output == "json" {
+		if cases == nil {
+			cases = []supportcase.Case{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(cases)
+	}
+
+	if len(cases) == 0 {
+		fmt.Println("No open support cases")
+		return nil
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CASE", "SEVERITY", "STATUS", "UPDATED", "CLUSTER", "SUMMARY", "URL"})
+	for _, supportCase := range cases {
+		table.AddRow([]string{
+			supportCase.CaseNumber,
+			supportCase.Severity,
+			supportCase.Status,
+			supportCase.LastModifiedDate.Local().Format(time.DateTime),
+			supportCase.ClusterID,
+			supportCase.Summary,
+			supportCase.URL(),
+		})
+	}
+	table.AddRow([]string{})
+	return table.Flush()
+}
@@ -2,6 +2,7 @@ package cloudtrail
 
 import (
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
@@ -187,3 +188,114 @@ func TestPermissonDeniedFilter(t *testing.T) {
 	})
 
 }
+
+func TestResourceHistory(t *testing.T) {
+	roleEvent := `{"eventVersion": "1.08","userIdentity": {"sessionContext": {"sessionIssuer": {"arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-abcd"}}}}`
+	userEvent := `{"eventVersion": "1.08","userIdentity": {"type": "IAMUser", "arn": "arn:aws:iam::123456789012:user/customer-admin"}}`
+	readEvent := `{"eventVersion": "1.08","readOnly": true,"userIdentity": {"type": "IAMUser", "arn": "arn:aws:iam::123456789012:user/customer-admin"}}`
+
+	authorize := "AuthorizeSecurityGroupIngress"
+	revoke := "RevokeSecurityGroupIngress"
+	describe := "DescribeSecurityGroups"
+	first := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	third := second.Add(time.Hour)
+
+	events := []types.Event{
+		{EventName: &authorize, EventTime: &first, CloudTrailEvent: &roleEvent},
+		{EventName: &revoke, EventTime: &second, CloudTrailEvent: &userEvent},
+		{EventName: &revoke, EventTime: &third, CloudTrailEvent: &userEvent},
+		{EventName: &describe, EventTime: &third, CloudTrailEvent: &readEvent},
+	}
+
+	t.Run("Test Filtering Read Only Events", func(t *testing.T) {
+		filtered, err := ctUtil.ApplyFilters(events, isWriteEvent)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(filtered), "Filtered events do not match expected results")
+	})
+
+	t.Run("Test Summarizing By Principal", func(t *testing.T) {
+		summaries := summarizeByPrincipal(events[:3])
+		assert.Equal(t, 2, len(summaries))
+
+		assert.Equal(t, "arn:aws:iam::123456789012:user/customer-admin", summaries[0].Principal)
+		assert.Equal(t, 2, summaries[0].Count)
+		assert.Equal(t, []string{revoke}, summaries[0].EventNames)
+		assert.Equal(t, second, summaries[0].FirstSeen)
+		assert.Equal(t, third, summaries[0].LastSeen)
+
+		assert.Equal(t, "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-abcd", summaries[1].Principal)
+		assert.Equal(t, 1, summaries[1].Count)
+	})
+}
+
+func TestClassifyPrincipal(t *testing.T) {
+	tests := []struct {
+		name         string
+		identityType string
+		principal    string
+		username     string
+		expected     ctUtil.Identity
+	}{
+		{
+			name:      "installer role",
+			principal: "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+			username:  "OCM",
+			expected:  ctUtil.Identity{Category: ctUtil.CategoryInstaller},
+		},
+		{
+			name:      "STS operator role",
+			principal: "arn:aws:iam::123456789012:role/mycluster-a1b2-openshift-machine-api-aws-cloud-credentials",
+			username:  "1700000000000000000",
+			expected:  ctUtil.Identity{Category: ctUtil.CategoryOperator, Name: "machine-api"},
+		},
+		{
+			name:      "non-STS operator user",
+			principal: "arn:aws:iam::123456789012:user/mycluster-x7k2p-openshift-ingress-operator-abcde",
+			username:  "mycluster-x7k2p-openshift-ingress-operator-abcde",
+			expected:  ctUtil.Identity{Category: ctUtil.CategoryOperator, Name: "ingress-operator"},
+		},
+		{
+			name:      "hosted control plane operator",
+			principal: "arn:aws:iam::123456789012:user/test-12345-6-a7b8-kube-system-capa-controller-manager/123456789012",
+			expected:  ctUtil.Identity{Category: ctUtil.CategoryOperator, Name: "capa-controller-manager"},
+		},
+		{
+			name:      "worker role",
+			principal: "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role",
+			username:  "i-0123456789abcdef0",
+			expected:  ctUtil.Identity{Category: ctUtil.CategoryOperator, Name: "worker"},
+		},
+		{
+			name:      "SRE support role",
+			principal: "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-abcd",
+			username:  "jdoe",
+			expected:  ctUtil.Identity{Category: ctUtil.CategorySRE},
+		},
+		{
+			name:      "SRE session of a customer role",
+			principal: "arn:aws:iam::123456789012:role/SomeRole",
+			username:  "RH-SRE-jdoe",
+			expected:  ctUtil.Identity{Category: ctUtil.CategorySRE},
+		},
+		{
+			name:         "AWS service",
+			identityType: "AWSService",
+			username:     "autoscaling.amazonaws.com",
+			expected:     ctUtil.Identity{Category: ctUtil.CategoryAWSService},
+		},
+		{
+			name:      "customer user",
+			principal: "arn:aws:iam::123456789012:user/customer-admin",
+			username:  "customer-admin",
+			expected:  ctUtil.Identity{Category: ctUtil.CategoryCustomer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ctUtil.ClassifyPrincipal(tt.identityType, tt.principal, tt.username))
+		})
+	}
+
+	assert.Equal(t, "operator (machine-api)", ctUtil.Identity{Category: ctUtil.CategoryOperator, Name: "machine-api"}.String())
+}
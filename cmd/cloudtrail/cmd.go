@@ -16,6 +16,8 @@ func NewCloudtrailCmd() *cobra.Command {
 
 	cloudtrailCmd.AddCommand(newCmdWriteEvents())
 	cloudtrailCmd.AddCommand(newCmdPermissionDenied())
+	cloudtrailCmd.AddCommand(newCmdResourceHistory())
+	cloudtrailCmd.AddCommand(newCmdCollect())
 
 	return cloudtrailCmd
 }
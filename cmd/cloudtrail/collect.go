@@ -0,0 +1,313 @@
+package cloudtrail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+	ctAws "github.com/openshift/osdctl/cmd/cloudtrail/pkg/aws"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/signing"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// collectStateFile records the progress of a collection in its output directory, to resume it
+const collectStateFile = "state.json"
+
+type collectOptions struct {
+	ClusterID string
+	StartTime string
+	OutDir    string
+	WriteOnly bool
+	Restart   bool
+	Sign      string
+}
+
+// lookupEventsAPI is the part of the CloudTrail client used by collect, to fake it in tests
+type lookupEventsAPI interface {
+	LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error)
+}
+
+// collectState is the progress of a collection. The query window is frozen on the first run since
+// CloudTrail only accepts a NextToken along with the exact same query.
+type collectState struct {
+	ClusterID string                         `json:"cluster_id"`
+	StartTime time.Time                      `json:"start_time"`
+	EndTime   time.Time                      `json:"end_time"`
+	WriteOnly bool                           `json:"write_only"`
+	Regions   map[string]*regionCollectState `json:"regions"`
+}
+
+type regionCollectState struct {
+	NextToken string `json:"next_token,omitempty"`
+	// Offset is the size of the NDJSON file once the last page was written, anything after it comes
+	// from an interrupted page and is dropped on resume
+	Offset int64 `json:"offset"`
+	Pages  int   `json:"pages"`
+	Events int   `json:"events"`
+	Done   bool  `json:"done"`
+}
+
+func newCmdCollect() *cobra.Command {
+	ops := &collectOptions{}
+	collectCmd := &cobra.Command{
+		Use:   "collect",
+		Short: "Download the CloudTrail events of a cluster to NDJSON files",
+		Long: `Page through the CloudTrail events of the cluster's AWS account and append them to one NDJSON file per
+region in the output directory, one raw CloudTrail record per line, so they can be queried offline with
+jq or duckdb.
+
+The progress is saved after every page: running the same command again resumes an interrupted
+collection where it stopped, with the same time window. Use --restart to start over.
+
+With --sign, the NDJSON files of a complete collection are signed with cosign or gpg, so an export attached
+to a compliance ticket can be verified later.`,
+		Example: `  # Download a week of events
+  osdctl cloudtrail collect --cluster-id <cluster-id> --since 7d --out ./events
+
+  # Query them offline
+  jq -r 'select(.eventName == "TerminateInstances") | .userIdentity.arn' ./events/*.ndjson
+
+  # Download and sign a month of write events for an audit
+  osdctl cloudtrail collect --cluster-id <cluster-id> --since 30d --write-only --out ./audit --sign cosign`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ops.run()
+		},
+	}
+	collectCmd.Flags().StringVarP(&ops.ClusterID, "cluster-id", "C", "", "Cluster ID")
+	collectCmd.Flags().StringVar(&ops.StartTime, "since", "24h", "How far back events are collected, e.g. 12h or 7d")
+	collectCmd.Flags().StringVar(&ops.OutDir, "out", "", "Directory the NDJSON files and the progress are written to")
+	collectCmd.Flags().BoolVar(&ops.WriteOnly, "write-only", false, "Only collect write events")
+	collectCmd.Flags().BoolVar(&ops.Restart, "restart", false, "Discard the progress and the files of a previous collection in the output directory")
+	collectCmd.Flags().StringVar(&ops.Sign, "sign", "", signing.FlagUsage)
+	_ = collectCmd.MarkFlagRequired("cluster-id")
+	_ = collectCmd.MarkFlagRequired("out")
+	return collectCmd
+}
+
+func (o *collectOptions) run() error {
+	if err := utils.IsValidClusterKey(o.ClusterID); err != nil {
+		return err
+	}
+	startTime, err := ctUtil.ParseDurationToUTC(o.StartTime)
+	if err != nil {
+		return err
+	}
+	signer, err := signing.NewSigner(o.Sign)
+	if err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("unable to create connection to ocm: %w", err)
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(connection, o.ClusterID)
+	if err != nil {
+		return err
+	}
+	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
+		return fmt.Errorf("[ERROR] this command is only available for AWS clusters")
+	}
+	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	if err != nil {
+		return err
+	}
+
+	state, err := o.loadState(cluster.ID(), startTime)
+	if err != nil {
+		return err
+	}
+
+	arn, accountId, err := ctAws.Whoami(*sts.NewFromConfig(cfg))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[INFO] Collecting events from %v to %v for AWS Account %v as %v into %v\n", state.StartTime, state.EndTime, accountId, arn, o.OutDir)
+
+	regions := []string{cfg.Region}
+	if cfg.Region != DefaultRegion {
+		regions = append(regions, DefaultRegion)
+	}
+	for _, region := range regions {
+		client := cloudtrail.NewFromConfig(cfg, func(options *cloudtrail.Options) {
+			options.Region = region
+		})
+		if err := collectRegion(client, region, o.OutDir, state); err != nil {
+			return fmt.Errorf("[ERROR] collection of %v interrupted, run the same command again to resume it: %w", region, err)
+		}
+	}
+	if signer != nil {
+		return signCollection(signer, o.OutDir, regions)
+	}
+	return nil
+}
+
+// signCollection signs the NDJSON files of the regions once they're complete
+func signCollection(signer *signing.Signer, outDir string, regions []string) error {
+	for _, region := range regions {
+		path := filepath.Join(outDir, region+".ndjson")
+		signature, err := signer.SignFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[INFO] Signed %v to %v, verify it with: %v\n", path, signature, signer.VerifyCommand(path))
+	}
+	return nil
+}
+
+// loadState returns the state of the collection in the output directory, or a new one
+func (o *collectOptions) loadState(clusterID string, startTime time.Time) (*collectState, error) {
+	if err := os.MkdirAll(o.OutDir, 0o755); err != nil {
+		return nil, err
+	}
+	statePath := filepath.Join(o.OutDir, collectStateFile)
+
+	if o.Restart {
+		// the signatures of the files, if any, go along with them
+		files, err := filepath.Glob(filepath.Join(o.OutDir, "*.ndjson*"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range append(files, statePath) {
+			if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	content, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return &collectState{
+			ClusterID: clusterID,
+			StartTime: startTime,
+			EndTime:   time.Now().UTC(),
+			WriteOnly: o.WriteOnly,
+			Regions:   map[string]*regionCollectState{},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &collectState{}
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, fmt.Errorf("failed to read %v, use --restart to start over: %w", statePath, err)
+	}
+	if state.ClusterID != clusterID || state.WriteOnly != o.WriteOnly {
+		return nil, fmt.Errorf("%v holds a collection of cluster %v (write only: %t), use another directory or --restart", o.OutDir, state.ClusterID, state.WriteOnly)
+	}
+	if state.Regions == nil {
+		state.Regions = map[string]*regionCollectState{}
+	}
+	fmt.Printf("[INFO] Resuming the collection started for %v, --since is ignored\n", state.StartTime)
+	return state, nil
+}
+
+func saveState(outDir string, state *collectState) error {
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename, so an interruption never leaves a truncated state behind
+	tmp := filepath.Join(outDir, collectStateFile+".tmp")
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(outDir, collectStateFile))
+}
+
+// collectRegion appends the pages of events of the region to <region>.ndjson, saving the state after
+// each of them
+func collectRegion(client lookupEventsAPI, region string, outDir string, state *collectState) error {
+	regionState, ok := state.Regions[region]
+	if !ok {
+		regionState = &regionCollectState{}
+		state.Regions[region] = regionState
+	}
+	if regionState.Done {
+		fmt.Printf("[INFO] %v already collected (%d events)\n", region, regionState.Events)
+		return nil
+	}
+
+	file, err := os.OpenFile(filepath.Join(outDir, region+".ndjson"), os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// Drop what an interrupted page may have written after the last saved state
+	if err := file.Truncate(regionState.Offset); err != nil {
+		return err
+	}
+	if _, err := file.Seek(regionState.Offset, 0); err != nil {
+		return err
+	}
+
+	input := &cloudtrail.LookupEventsInput{
+		StartTime: aws.Time(state.StartTime),
+		EndTime:   aws.Time(state.EndTime),
+	}
+	if state.WriteOnly {
+		input.LookupAttributes = []types.LookupAttribute{
+			{AttributeKey: types.LookupAttributeKeyReadOnly, AttributeValue: aws.String("false")},
+		}
+	}
+
+	for {
+		if regionState.NextToken != "" {
+			input.NextToken = aws.String(regionState.NextToken)
+		}
+		output, err := client.LookupEvents(context.TODO(), input)
+		if err != nil {
+			return err
+		}
+
+		var page bytes.Buffer
+		for _, event := range output.Events {
+			if event.CloudTrailEvent == nil {
+				continue
+			}
+			if err := json.Compact(&page, []byte(*event.CloudTrailEvent)); err != nil {
+				return fmt.Errorf("failed to parse event %v: %w", aws.ToString(event.EventId), err)
+			}
+			page.WriteByte('\n')
+		}
+		written, err := file.Write(page.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := file.Sync(); err != nil {
+			return err
+		}
+
+		regionState.Offset += int64(written)
+		regionState.Pages++
+		regionState.Events += len(output.Events)
+		regionState.NextToken = aws.ToString(output.NextToken)
+		regionState.Done = regionState.NextToken == ""
+		if err := saveState(outDir, state); err != nil {
+			return err
+		}
+		fmt.Printf("\r[INFO] %v: %d events in %d pages", region, regionState.Events, regionState.Pages)
+		if regionState.Done {
+			fmt.Println()
+			return nil
+		}
+	}
+}
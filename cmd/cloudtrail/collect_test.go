@@ -0,0 +1,137 @@
+package cloudtrail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLookupEvents serves pages of two events, failing once on the page given by failOn
+type fakeLookupEvents struct {
+	pages  int
+	failOn string
+	tokens []string
+}
+
+func (f *fakeLookupEvents) LookupEvents(_ context.Context, params *cloudtrail.LookupEventsInput, _ ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	token := aws.ToString(params.NextToken)
+	f.tokens = append(f.tokens, token)
+	if token == f.failOn && token != "" {
+		f.failOn = ""
+		return nil, errors.New("throttled")
+	}
+
+	page := 0
+	if token != "" {
+		_, _ = fmt.Sscanf(token, "page-%d", &page)
+	}
+	output := &cloudtrail.LookupEventsOutput{}
+	for i := 0; i < 2; i++ {
+		raw := fmt.Sprintf("{\n  \"eventVersion\": \"1.08\",\n  \"eventID\": \"%d-%d\"\n}", page, i)
+		output.Events = append(output.Events, types.Event{CloudTrailEvent: aws.String(raw)})
+	}
+	if page+1 < f.pages {
+		output.NextToken = aws.String(fmt.Sprintf("page-%d", page+1))
+	}
+	return output, nil
+}
+
+func readLines(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestCollectRegionResume(t *testing.T) {
+	dir := t.TempDir()
+	state := &collectState{
+		ClusterID: "abc",
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now(),
+		Regions:   map[string]*regionCollectState{},
+	}
+	client := &fakeLookupEvents{pages: 3, failOn: "page-2"}
+
+	err := collectRegion(client, "us-east-1", dir, state)
+	assert.Error(t, err)
+	assert.Equal(t, 2, state.Regions["us-east-1"].Pages)
+	assert.False(t, state.Regions["us-east-1"].Done)
+
+	// Simulate a page half written when the previous run was interrupted
+	path := filepath.Join(dir, "us-east-1.ndjson")
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, _ = file.WriteString(`{"eventID": "partial`)
+	_ = file.Close()
+
+	err = collectRegion(client, "us-east-1", dir, state)
+	assert.NoError(t, err)
+	assert.True(t, state.Regions["us-east-1"].Done)
+	assert.Equal(t, []string{"", "page-1", "page-2", "page-2"}, client.tokens)
+
+	lines := readLines(t, path)
+	assert.Equal(t, 6, len(lines))
+	assert.Equal(t, `{"eventVersion":"1.08","eventID":"0-0"}`, lines[0])
+	assert.Equal(t, `{"eventVersion":"1.08","eventID":"2-1"}`, lines[5])
+
+	// A completed region isn't collected again
+	client.tokens = nil
+	assert.NoError(t, collectRegion(client, "us-east-1", dir, state))
+	assert.Empty(t, client.tokens)
+}
+
+func TestCollectLoadState(t *testing.T) {
+	dir := t.TempDir()
+	o := &collectOptions{OutDir: dir}
+	startTime := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	state, err := o.loadState("abc", startTime)
+	assert.NoError(t, err)
+	state.Regions["us-east-1"] = &regionCollectState{NextToken: "page-1", Pages: 1}
+	assert.NoError(t, saveState(dir, state))
+
+	resumed, err := o.loadState("abc", startTime.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, startTime.Equal(resumed.StartTime))
+	assert.Equal(t, "page-1", resumed.Regions["us-east-1"].NextToken)
+
+	_, err = o.loadState("other", startTime)
+	assert.Error(t, err)
+
+	o.Restart = true
+	restarted, err := o.loadState("other", startTime)
+	assert.NoError(t, err)
+	assert.Empty(t, restarted.Regions)
+}
+
+func TestParseDurationDays(t *testing.T) {
+	duration, err := ctUtil.ParseDuration("7d")
+	assert.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, duration)
+
+	duration, err = ctUtil.ParseDuration("90m")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, duration)
+
+	_, err = ctUtil.ParseDuration("xd")
+	assert.Error(t, err)
+}
@@ -17,6 +17,8 @@ import (
 type RawEventDetails struct {
 	EventVersion string `json:"eventVersion"`
 	UserIdentity struct {
+		Type           string `json:"type"`
+		Arn            string `json:"arn"`
 		AccountId      string `json:"accountId"`
 		SessionContext struct {
 			SessionIssuer struct {
@@ -29,6 +31,7 @@ type RawEventDetails struct {
 	EventRegion string `json:"awsRegion"`
 	EventId     string `json:"eventID"`
 	ErrorCode   string `json:"errorCode"`
+	ReadOnly    bool   `json:"readOnly"`
 }
 
 type QueryOptions struct {
@@ -59,6 +62,24 @@ func ExtractUserDetails(cloudTrailEvent *string) (*RawEventDetails, error) {
 	return &res, nil
 }
 
+// EventPrincipal returns the IAM principal behind an event: the role the session was issued for if any,
+// otherwise the identity ARN or the username
+func EventPrincipal(event types.Event) string {
+	raw, err := ExtractUserDetails(event.CloudTrailEvent)
+	if err == nil {
+		if issuer := raw.UserIdentity.SessionContext.SessionIssuer.Arn; issuer != "" {
+			return issuer
+		}
+		if raw.UserIdentity.Arn != "" {
+			return raw.UserIdentity.Arn
+		}
+	}
+	if event.Username != nil {
+		return *event.Username
+	}
+	return "<unknown>"
+}
+
 // whoami retrieves caller identity information
 func Whoami(stsClient sts.Client) (accountArn string, accountId string, err error) {
 	ctx := context.TODO()
@@ -110,3 +131,52 @@ func GetEvents(cloudtailClient *cloudtrail.Client, startTime time.Time, writeOnl
 
 	return alllookupEvents, nil
 }
+
+// GetResourceEvents retrieves the cloudtrail events since the specified time that reference the given
+// resource name or ID (e.g. sg-0123 or an IAM role name)
+func GetResourceEvents(cloudtailClient cloudtrail.LookupEventsAPIClient, startTime time.Time, resourceName string) ([]types.Event, error) {
+	input := cloudtrail.LookupEventsInput{
+		StartTime: &startTime,
+		EndTime:   aws.Time(time.Now()),
+		LookupAttributes: []types.LookupAttribute{
+			{AttributeKey: types.LookupAttributeKeyResourceName,
+				AttributeValue: aws.String(resourceName)},
+		},
+	}
+
+	events := []types.Event{}
+	paginator := cloudtrail.NewLookupEventsPaginator(cloudtailClient, &input)
+	for paginator.HasMorePages() {
+		lookupOutput, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("[WARNING] paginator error: \n%w", err)
+		}
+		events = append(events, lookupOutput.Events...)
+	}
+
+	return events, nil
+}
+
+// GetEventsByName retrieves the cloudtrail events with the given name (e.g. DeleteStack) since the specified time
+func GetEventsByName(cloudtailClient *cloudtrail.Client, startTime time.Time, eventName string) ([]types.Event, error) {
+	input := cloudtrail.LookupEventsInput{
+		StartTime: &startTime,
+		EndTime:   aws.Time(time.Now()),
+		LookupAttributes: []types.LookupAttribute{
+			{AttributeKey: types.LookupAttributeKeyEventName,
+				AttributeValue: aws.String(eventName)},
+		},
+	}
+
+	events := []types.Event{}
+	paginator := cloudtrail.NewLookupEventsPaginator(cloudtailClient, &input)
+	for paginator.HasMorePages() {
+		lookupOutput, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("[WARNING] paginator error: \n%w", err)
+		}
+		events = append(events, lookupOutput.Events...)
+	}
+
+	return events, nil
+}
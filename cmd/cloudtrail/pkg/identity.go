@@ -0,0 +1,100 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	pkg "github.com/openshift/osdctl/cmd/cloudtrail/pkg/aws"
+)
+
+// Categories of the principals behind CloudTrail events
+const (
+	CategoryInstaller  = "installer"
+	CategoryOperator   = "operator"
+	CategorySRE        = "sre"
+	CategoryAWSService = "aws-service"
+	CategoryCustomer   = "customer"
+)
+
+// Identity is the friendly identity of a CloudTrail principal
+type Identity struct {
+	Category string
+	// Name tells the known roles of a category apart, e.g. the operator owning a role
+	Name string
+}
+
+func (i Identity) String() string {
+	if i.Name == "" {
+		return i.Category
+	}
+	return i.Category + " (" + i.Name + ")"
+}
+
+type identityRule struct {
+	category string
+	pattern  *regexp.Regexp
+	// name is expanded with the submatches of pattern
+	name string
+}
+
+// sreSessionRE matches the session names of the roles SREs assume, which carry the SRE's identity
+var sreSessionRE = regexp.MustCompile(`(?i)^RH-SRE-`)
+
+// identityRules are matched in order against the name of the role or user behind an event
+var identityRules = []identityRule{
+	{CategoryInstaller, regexp.MustCompile(`(?i)-Installer-Role$|^osdCcsAdmin$|^osdManagedAdmin$`), ""},
+	{CategorySRE, regexp.MustCompile(`(?i)^ManagedOpenShift-Support-|-Support-Role$|^RH-SRE-|^RH-Technical-Support-Access$|^OrganizationAccountAccessRole$|^osdManagedAdmin-SRE`), ""},
+	{CategoryOperator, regexp.MustCompile(`(?i)-ControlPlane-Role$`), "control-plane"},
+	{CategoryOperator, regexp.MustCompile(`(?i)-Worker-Role$`), "worker"},
+	{CategoryOperator, regexp.MustCompile(`(?i)-openshift-(machine-api|ingress-operator|image-registry|cluster-csi-drivers|cloud-credential-operator|cloud-network-config-controller)`), "$1"},
+	{CategoryOperator, regexp.MustCompile(`(?i)-kube-system-(capa-controller-manager|kube-controller-manager|control-plane-operator|kms-provider)`), "$1"},
+}
+
+// ResolveIdentity returns the friendly identity of the principal behind the event
+func ResolveIdentity(event types.Event) Identity {
+	var identityType, username string
+	if event.Username != nil {
+		username = *event.Username
+	}
+	if raw, err := pkg.ExtractUserDetails(event.CloudTrailEvent); err == nil {
+		identityType = raw.UserIdentity.Type
+	}
+	return ClassifyPrincipal(identityType, pkg.EventPrincipal(event), username)
+}
+
+// ClassifyPrincipal maps a principal ARN (or name) and the username of its session to a known role. Principals
+// that match no known role are customer users and roles.
+func ClassifyPrincipal(identityType string, principal string, username string) Identity {
+	if identityType == "AWSService" || strings.HasSuffix(principal, ".amazonaws.com") || strings.HasSuffix(username, ".amazonaws.com") {
+		return Identity{Category: CategoryAWSService}
+	}
+	if sreSessionRE.MatchString(username) {
+		return Identity{Category: CategorySRE}
+	}
+
+	for _, name := range principalNames(principal) {
+		for _, rule := range identityRules {
+			if match := rule.pattern.FindStringSubmatchIndex(name); match != nil {
+				return Identity{
+					Category: rule.category,
+					Name:     strings.ToLower(string(rule.pattern.ExpandString(nil, rule.name, name, match))),
+				}
+			}
+		}
+	}
+	return Identity{Category: CategoryCustomer}
+}
+
+// principalNames returns the segments of the resource of the principal ARN without its type, e.g. the role and
+// session names of arn:aws:sts::<account>:assumed-role/<role>/<session>, or the principal if it isn't an ARN
+func principalNames(principal string) []string {
+	if !strings.HasPrefix(principal, "arn:") {
+		return []string{principal}
+	}
+	segments := strings.Split(principal[strings.LastIndex(principal, ":")+1:], "/")
+	if len(segments) > 1 {
+		return segments[1:]
+	}
+	return segments
+}
@@ -2,11 +2,14 @@ package pkg
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 	pkg "github.com/openshift/osdctl/cmd/cloudtrail/pkg/aws"
+	"github.com/openshift/osdctl/pkg/printer"
 )
 
 type Filter func(types.Event) (bool, error)
@@ -67,6 +70,7 @@ func PrintEvents(filterEvents []types.Event, printUrl bool, printRaw bool) {
 		if sessionIssuer != "" {
 			eventStringBuilder.WriteString(fmt.Sprintf(" | ARN: %v", sessionIssuer))
 		}
+		eventStringBuilder.WriteString(fmt.Sprintf(" | Category: %v", ResolveIdentity(filterEvents[i])))
 
 		if printUrl && filterEvents[i].CloudTrailEvent != nil {
 			if err == nil {
@@ -80,6 +84,43 @@ func PrintEvents(filterEvents []types.Event, printUrl bool, printRaw bool) {
 	fmt.Println(eventStringBuilder.String())
 }
 
+// PrintEventsTable prints the events as a table whose columns and order can be changed with tableOptions
+func PrintEventsTable(filterEvents []types.Event, printUrl bool, tableOptions printer.TableOptions) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ').WithTableOptions(tableOptions)
+	header := []string{"EVENT", "TIME", "USERNAME", "ARN", "CATEGORY"}
+	if printUrl {
+		header = append(header, "LINK")
+	}
+	table.AddRow(header)
+
+	for i := len(filterEvents) - 1; i >= 0; i-- {
+		event := filterEvents[i]
+		row := []string{"", "", "", "", ResolveIdentity(event).String()}
+		if event.EventName != nil {
+			row[0] = *event.EventName
+		}
+		if event.EventTime != nil {
+			row[1] = event.EventTime.UTC().Format(time.RFC3339)
+		}
+		if event.Username != nil {
+			row[2] = *event.Username
+		}
+		rawEventDetails, err := pkg.ExtractUserDetails(event.CloudTrailEvent)
+		if err == nil {
+			row[3] = rawEventDetails.UserIdentity.SessionContext.SessionIssuer.UserName
+		}
+		if printUrl {
+			link := "<not available>"
+			if err == nil {
+				link = generateLink(*rawEventDetails)
+			}
+			row = append(row, link)
+		}
+		table.AddRow(row)
+	}
+	return table.Flush()
+}
+
 // generateLink generates a hyperlink to aws cloudTrail event.
 func generateLink(raw pkg.RawEventDetails) (url_link string) {
 	str1 := "https://"
@@ -96,9 +137,10 @@ func generateLink(raw pkg.RawEventDetails) (url_link string) {
 }
 
 // parseDurationToUTC parses the given startTime string as a duration and subtracts it from the current UTC time.
+// On top of the time.ParseDuration units, whole days are accepted, e.g. 7d.
 // It returns the resulting time and any parsing error encountered.
 func ParseDurationToUTC(input string) (time.Time, error) {
-	duration, err := time.ParseDuration(input)
+	duration, err := ParseDuration(input)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("unable to parse time duration: %w", err)
 	}
@@ -106,6 +148,18 @@ func ParseDurationToUTC(input string) (time.Time, error) {
 	return time.Now().UTC().Add(-duration), nil
 }
 
+// ParseDuration parses a time.ParseDuration duration or a number of days, e.g. 7d
+func ParseDuration(input string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(input, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", input)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(input)
+}
+
 // Join all individual patterns into a single string separated by the "|" operator
 func MergeRegex(regexlist []string) string {
 	return strings.Join(regexlist, "|")
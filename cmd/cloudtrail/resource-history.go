@@ -0,0 +1,211 @@
+package cloudtrail
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+	ctAws "github.com/openshift/osdctl/cmd/cloudtrail/pkg/aws"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+type resourceHistoryOptions struct {
+	ClusterID  string
+	ResourceID string
+	StartTime  string
+	PrintUrl   bool
+	PrintAll   bool
+
+	TableOptions printer.TableOptions
+}
+
+// principalSummary aggregates the changes a single IAM principal made to a resource
+type principalSummary struct {
+	Principal  string
+	Count      int
+	EventNames []string
+	FirstSeen  time.Time
+	LastSeen   time.Time
+}
+
+func newCmdResourceHistory() *cobra.Command {
+	opts := &resourceHistoryOptions{}
+	resourceHistoryCmd := &cobra.Command{
+		Use:   "resource-history",
+		Short: "Shows who changed an AWS resource and when",
+		Long: `Looks up the CloudTrail events referencing an AWS resource (e.g. a security group, an instance or
+an IAM role) and summarizes which IAM principals changed it and when.
+
+Both the cluster's region and us-east-1 (where global services such as IAM log their events) are checked.`,
+		Example: `  osdctl cloudtrail resource-history --cluster-id ${CLUSTER_ID} --resource-id sg-0123456789abcdef0 --since 72h`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run()
+		},
+	}
+	resourceHistoryCmd.Flags().StringVarP(&opts.ClusterID, "cluster-id", "C", "", "Cluster ID")
+	resourceHistoryCmd.Flags().StringVar(&opts.ResourceID, "resource-id", "", "Name or ID of the AWS resource, e.g. sg-0123456789abcdef0")
+	resourceHistoryCmd.Flags().StringVarP(&opts.StartTime, "since", "", "168h", "Specifies that only events that occur within the specified time are returned. Defaults to 168h (7 days). Valid time units are \"ns\", \"us\" (or \"µs\"), \"ms\", \"s\", \"m\", \"h\".")
+	resourceHistoryCmd.Flags().BoolVarP(&opts.PrintUrl, "url", "u", false, "Generates Url link to cloud console cloudtrail event")
+	resourceHistoryCmd.Flags().BoolVarP(&opts.PrintAll, "all", "A", false, "Include read-only events, by default only changes are shown")
+	printer.AddTableFlags(resourceHistoryCmd.Flags(), &opts.TableOptions)
+	resourceHistoryCmd.MarkFlagRequired("cluster-id")
+	resourceHistoryCmd.MarkFlagRequired("resource-id")
+	return resourceHistoryCmd
+}
+
+// isWriteEvent keeps the events which changed the resource. Unlike write-events, the read-only
+// lookup attribute can't be combined with the resource name one, so this is filtered client side.
+func isWriteEvent(event types.Event) (bool, error) {
+	raw, err := ctAws.ExtractUserDetails(event.CloudTrailEvent)
+	if err != nil {
+		return false, fmt.Errorf("[ERROR] failed to extract raw CloudTrail event details: %w", err)
+	}
+	return !raw.ReadOnly, nil
+}
+
+// summarizeByPrincipal groups events by principal, most recently active principal first
+func summarizeByPrincipal(events []types.Event) []*principalSummary {
+	summaries := map[string]*principalSummary{}
+	eventNames := map[string]map[string]bool{}
+	for _, event := range events {
+		principal := ctAws.EventPrincipal(event)
+		summary, ok := summaries[principal]
+		if !ok {
+			summary = &principalSummary{Principal: principal}
+			summaries[principal] = summary
+			eventNames[principal] = map[string]bool{}
+		}
+		summary.Count++
+
+		if event.EventName != nil && !eventNames[principal][*event.EventName] {
+			eventNames[principal][*event.EventName] = true
+			summary.EventNames = append(summary.EventNames, *event.EventName)
+		}
+		if event.EventTime != nil {
+			if summary.FirstSeen.IsZero() || event.EventTime.Before(summary.FirstSeen) {
+				summary.FirstSeen = *event.EventTime
+			}
+			if event.EventTime.After(summary.LastSeen) {
+				summary.LastSeen = *event.EventTime
+			}
+		}
+	}
+
+	result := make([]*principalSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		sort.Strings(summary.EventNames)
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastSeen.Equal(result[j].LastSeen) {
+			return result[i].LastSeen.After(result[j].LastSeen)
+		}
+		return result[i].Principal < result[j].Principal
+	})
+	return result
+}
+
+func printPrincipalSummary(summaries []*principalSummary, tableOptions printer.TableOptions) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ').WithTableOptions(tableOptions)
+	table.AddRow([]string{"PRINCIPAL", "CATEGORY", "CHANGES", "FIRST", "LAST", "EVENTS"})
+	for _, summary := range summaries {
+		table.AddRow([]string{
+			summary.Principal,
+			ctUtil.ClassifyPrincipal("", summary.Principal, "").String(),
+			fmt.Sprintf("%d", summary.Count),
+			summary.FirstSeen.UTC().Format(time.RFC3339),
+			summary.LastSeen.UTC().Format(time.RFC3339),
+			strings.Join(summary.EventNames, ", "),
+		})
+	}
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("failed to print the principal summary: %w", err)
+	}
+	return nil
+}
+
+func (o *resourceHistoryOptions) run() error {
+	err := utils.IsValidClusterKey(o.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return fmt.Errorf("unable to create connection to ocm: %w", err)
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(connection, o.ClusterID)
+	if err != nil {
+		return err
+	}
+	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
+		return fmt.Errorf("[ERROR] this command is only available for AWS clusters")
+	}
+
+	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	if err != nil {
+		return err
+	}
+
+	startTime, err := ctUtil.ParseDurationToUTC(o.StartTime)
+	if err != nil {
+		return err
+	}
+
+	arn, accountId, err := ctAws.Whoami(*sts.NewFromConfig(cfg))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[INFO] Checking history of %v since %v for AWS Account %v as %v \n", o.ResourceID, startTime, accountId, arn)
+
+	regions := []string{cfg.Region}
+	if cfg.Region != DefaultRegion {
+		regions = append(regions, DefaultRegion)
+	}
+
+	var events []types.Event
+	for _, region := range regions {
+		fmt.Printf("[INFO] Fetching %v Event History...\n", region)
+		client := cloudtrail.NewFromConfig(cfg, func(options *cloudtrail.Options) {
+			options.Region = region
+		})
+		regionEvents, err := ctAws.GetResourceEvents(client, startTime, o.ResourceID)
+		if err != nil {
+			return err
+		}
+		events = append(events, regionEvents...)
+	}
+
+	if !o.PrintAll {
+		events, err = ctUtil.ApplyFilters(events, isWriteEvent)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(events) == 0 {
+		fmt.Printf("No events found for %v since %v\n", o.ResourceID, startTime)
+		return nil
+	}
+
+	// PrintEvents prints the slice in reverse, so sort the oldest event last to print it first
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.ToTime(events[i].EventTime).After(aws.ToTime(events[j].EventTime))
+	})
+	ctUtil.PrintEvents(events, o.PrintUrl, false)
+	fmt.Println()
+
+	return printPrincipalSummary(summarizeByPrincipal(events), o.TableOptions)
+}
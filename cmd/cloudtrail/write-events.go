@@ -14,6 +14,8 @@ import (
 	ctAws "github.com/openshift/osdctl/cmd/cloudtrail/pkg/aws"
 	envConfig "github.com/openshift/osdctl/pkg/envConfig"
 	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/pager"
+	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +29,9 @@ type writeEventsOptions struct {
 	PrintUrl  bool
 	PrintRaw  bool
 	PrintAll  bool
+
+	TableOptions printer.TableOptions
+	Pager        pager.Options
 }
 
 // RawEventDetails struct represents the structure of an AWS raw event
@@ -52,6 +57,7 @@ func newCmdWriteEvents() *cobra.Command {
 		Use:   "write-events",
 		Short: "Prints cloudtrail write events to console with optional filtering",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ops.Pager.Complete(cmd.Flags())
 			return ops.run()
 		},
 	}
@@ -60,6 +66,8 @@ func newCmdWriteEvents() *cobra.Command {
 	listEventsCmd.Flags().BoolVarP(&ops.PrintUrl, "url", "u", false, "Generates Url link to cloud console cloudtrail event")
 	listEventsCmd.Flags().BoolVarP(&ops.PrintRaw, "raw-event", "r", false, "Prints the cloudtrail events to the console in raw json format")
 	listEventsCmd.Flags().BoolVarP(&ops.PrintAll, "all", "A", false, "Prints all cloudtrail write events without filtering")
+	printer.AddTableFlags(listEventsCmd.Flags(), &ops.TableOptions)
+	pager.AddFlags(listEventsCmd.Flags(), &ops.Pager)
 	listEventsCmd.MarkFlagRequired("cluster-id")
 	return listEventsCmd
 }
@@ -139,6 +147,12 @@ func (o *writeEventsOptions) run() error {
 		return err
 	}
 
+	stopPager, err := o.Pager.Start()
+	if err != nil {
+		return err
+	}
+	defer stopPager()
+
 	// FilterAndPrintEvents fetches events and filters them based on a regex string.
 	// It then prints the filtered events.
 
@@ -163,7 +177,9 @@ func (o *writeEventsOptions) run() error {
 		return err
 	}
 
-	ctUtil.PrintEvents(filteredEvents, o.PrintUrl, o.PrintRaw)
+	if err := o.printEvents(filteredEvents); err != nil {
+		return err
+	}
 	fmt.Println("")
 
 	if DefaultRegion != cfg.Region {
@@ -192,8 +208,20 @@ func (o *writeEventsOptions) run() error {
 		if err != nil {
 			return err
 		}
-		ctUtil.PrintEvents(filteredEvents, o.PrintUrl, o.PrintRaw)
+		if err := o.printEvents(filteredEvents); err != nil {
+			return err
+		}
 	}
 
 	return err
 }
+
+// printEvents prints the events as a table when columns or a sort order are requested
+func (o *writeEventsOptions) printEvents(events []types.Event) error {
+	if o.TableOptions.IsSet() && !o.PrintRaw {
+		fmt.Println()
+		return ctUtil.PrintEventsTable(events, o.PrintUrl, o.TableOptions)
+	}
+	ctUtil.PrintEvents(events, o.PrintUrl, o.PrintRaw)
+	return nil
+}
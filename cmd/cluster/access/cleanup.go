@@ -25,20 +25,29 @@ func newCmdCleanup(client *k8s.LazyClient, streams genericclioptions.IOStreams)
 	cleanupCmd := &cobra.Command{
 		Use:               "cleanup <cluster identifier>",
 		Short:             "Drop emergency access to a cluster",
-		Long:              "Relinquish emergency access from the given cluster. If the cluster is PrivateLink, it deletes\nall jump pods in the cluster's namespace (because of this, you must be logged into the hive shard\nwhen dropping access for PrivateLink clusters). For non-PrivateLink clusters, the $KUBECONFIG\nenvironment variable is unset, if applicable.",
-		Args:              cobra.ExactArgs(1),
+		Long:              "Relinquish emergency access from the given cluster. If the cluster is PrivateLink, it deletes\nall jump pods in the cluster's namespace (because of this, you must be logged into the hive shard\nwhen dropping access for PrivateLink clusters). For non-PrivateLink clusters, the $KUBECONFIG\nenvironment variable is unset, if applicable.\n\nWith --management-cluster, it instead sweeps a management cluster for the break-glass artifacts left\nbehind for its hosted clusters: expired or orphaned jump pods, RBAC bindings and break-glass\ncertificate signing requests, and temporary identity providers (named break-glass*), and deletes\nthem in bulk with a report of what was cleaned.",
+		Example:           "  # Sweep a management cluster, only reporting what would be removed\n  osdctl cluster break-glass cleanup --management-cluster <mc-id> --reason OHSS-1234 --dry-run",
+		Args:              cobra.MaximumNArgs(1),
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
-			cmdutil.CheckErr(cleanupCmdComplete(cmd, args))
+			cmdutil.CheckErr(ops.complete(cmd, args))
 			cmdutil.CheckErr(ops.Run(cmd, args))
 		},
 	}
 	cleanupCmd.Flags().StringVar(&ops.reason, "reason", "", "[Mandatory for PrivateLink clusters] The reason for this command, which requires elevation, to be run (usualy an OHSS or PD ticket)")
+	cleanupCmd.Flags().StringVar(&ops.managementCluster, "management-cluster", "", "Sweep the break-glass artifacts of all the clusters hosted on this management cluster")
+	cleanupCmd.Flags().BoolVar(&ops.dryRun, "dry-run", false, "Only report the break-glass artifacts found on the management cluster")
 
 	return cleanupCmd
 }
 
-func cleanupCmdComplete(cmd *cobra.Command, args []string) error {
+func (c *cleanupAccessOptions) complete(cmd *cobra.Command, args []string) error {
+	if c.managementCluster != "" {
+		if len(args) != 0 {
+			return cmdutil.UsageErrorf(cmd, "No cluster identifier is expected along with --management-cluster")
+		}
+		return osdctlutil.IsValidClusterKey(c.managementCluster)
+	}
 	if len(args) != 1 {
 		return cmdutil.UsageErrorf(cmd, "Exactly one cluster identifier was expected")
 	}
@@ -47,7 +56,9 @@ func cleanupCmdComplete(cmd *cobra.Command, args []string) error {
 
 // cleanupAccessOptions contains the objects and information required to drop access to a cluster
 type cleanupAccessOptions struct {
-	reason string
+	reason            string
+	managementCluster string
+	dryRun            bool
 
 	genericclioptions.IOStreams
 	kubeCli *k8s.LazyClient
@@ -86,8 +97,6 @@ func (c *cleanupAccessOptions) Readln() (string, error) {
 
 // Run executes the 'cleanup' access subcommand
 func (c *cleanupAccessOptions) Run(cmd *cobra.Command, args []string) error {
-	clusterIdentifier := args[0]
-
 	conn, err := osdctlutil.CreateConnection()
 	if err != nil {
 		return err
@@ -96,6 +105,11 @@ func (c *cleanupAccessOptions) Run(cmd *cobra.Command, args []string) error {
 		cmdutil.CheckErr(conn.Close())
 	}()
 
+	if c.managementCluster != "" {
+		return c.dropManagementClusterAccess(conn, c.managementCluster)
+	}
+	clusterIdentifier := args[0]
+
 	cluster, err := osdctlutil.GetCluster(conn, clusterIdentifier)
 	if err != nil {
 		return err
@@ -0,0 +1,298 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/printer"
+	osdctlutil "github.com/openshift/osdctl/pkg/utils"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// breakGlassIDPPrefix is the name prefix of the identity providers added temporarily for break-glass access
+	breakGlassIDPPrefix = "break-glass"
+	// breakGlassSignerFragment identifies the signers of the break-glass certificates of hosted control planes,
+	// e.g. hypershift.openshift.io/ocm-production-abc-name.sre-break-glass
+	breakGlassSignerFragment = "break-glass"
+	hypershiftSignerPrefix   = "hypershift.openshift.io/"
+)
+
+// breakGlassArtifact is something left behind by a break-glass access, which can be removed
+type breakGlassArtifact struct {
+	Kind      string
+	Namespace string
+	Name      string
+	ClusterID string
+	Reason    string
+	Result    string
+
+	// object is the kube object to delete, idpID the OCM identity provider for temporary IDPs
+	object kclient.Object
+	idpID  string
+}
+
+func (a breakGlassArtifact) fullName() string {
+	if a.Namespace == "" {
+		return a.Name
+	}
+	return a.Namespace + "/" + a.Name
+}
+
+// hostedClusters maps the IDs of the clusters hosted on a management cluster to their namespaces
+type hostedClusters map[string][]string
+
+// dropManagementClusterAccess removes the break-glass artifacts left on a management cluster and its hosted clusters
+func (c *cleanupAccessOptions) dropManagementClusterAccess(conn *sdk.Connection, managementClusterID string) error {
+	if c.reason == "" {
+		return fmt.Errorf("flag \"reason\" is required to clean up a management cluster")
+	}
+	managementCluster, err := osdctlutil.GetCluster(conn, managementClusterID)
+	if err != nil {
+		return err
+	}
+	kubeCli, _, _, err := common.GetKubeConfigAndClient(managementCluster.ID(), c.reason, "Elevation required to clean up break-glass access on a management cluster")
+	if err != nil {
+		return fmt.Errorf("failed to access management cluster %s: %w", managementCluster.Name(), err)
+	}
+
+	c.Println(fmt.Sprintf("Searching break-glass artifacts on management cluster '%s'", managementCluster.Name()))
+	ctx := context.TODO()
+	hosted, artifacts, err := findBreakGlassArtifacts(ctx, kubeCli, time.Now())
+	if err != nil {
+		return err
+	}
+	idps, err := findTemporaryIDPs(conn, hosted)
+	if err != nil {
+		c.Errorln(fmt.Sprintf("Failed to list the identity providers of the hosted clusters: %v", err))
+	}
+	artifacts = append(artifacts, idps...)
+
+	if len(artifacts) == 0 {
+		c.Println(fmt.Sprintf("No break-glass artifacts found across %d hosted clusters.", len(hosted)))
+		return nil
+	}
+	c.printBreakGlassArtifacts(artifacts, false)
+	if c.dryRun {
+		return nil
+	}
+
+	c.Print(fmt.Sprintf("Delete these %d artifacts? [y/N] ", len(artifacts)))
+	input, err := c.Readln()
+	if err != nil {
+		c.Errorln("Failed to read user input")
+		return err
+	}
+	if !isAffirmative(input) {
+		c.Println("Nothing was deleted.")
+		return nil
+	}
+
+	failures := 0
+	for i := range artifacts {
+		if err := deleteBreakGlassArtifact(ctx, kubeCli, conn, artifacts[i]); err != nil {
+			artifacts[i].Result = fmt.Sprintf("failed: %v", err)
+			failures++
+			continue
+		}
+		artifacts[i].Result = "deleted"
+	}
+	c.Println("")
+	c.printBreakGlassArtifacts(artifacts, true)
+	if failures > 0 {
+		return fmt.Errorf("failed to delete %d of %d break-glass artifacts", failures, len(artifacts))
+	}
+	return nil
+}
+
+// findBreakGlassArtifacts returns the hosted clusters of the management cluster and the break-glass artifacts
+// that are expired or belong to a cluster it no longer hosts
+func findBreakGlassArtifacts(ctx context.Context, kubeCli kclient.Client, now time.Time) (hostedClusters, []breakGlassArtifact, error) {
+	namespaces := corev1.NamespaceList{}
+	if err := kubeCli.List(ctx, &namespaces); err != nil {
+		return nil, nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	hosted := hostedClusters{}
+	existingNamespaces := map[string]bool{}
+	for _, ns := range namespaces.Items {
+		existingNamespaces[ns.Name] = true
+		if clusterID := ns.Labels[hiveNSLabelKey]; clusterID != "" {
+			hosted[clusterID] = append(hosted[clusterID], ns.Name)
+		}
+	}
+
+	var artifacts []breakGlassArtifact
+	lifespan := time.Duration(jumpPodLifespan) * time.Second
+	// Jump pods and the RBAC granted along with them carry the ID of the cluster they give access to
+	labelled := kclient.HasLabels{jumpPodLabelKey}
+	labelledReason := func(clusterID string, created time.Time) string {
+		if _, ok := hosted[clusterID]; !ok {
+			return "cluster no longer hosted here"
+		}
+		if now.Sub(created) > lifespan {
+			return fmt.Sprintf("older than %s", lifespan)
+		}
+		return ""
+	}
+
+	pods := corev1.PodList{}
+	if err := kubeCli.List(ctx, &pods, labelled); err != nil {
+		return nil, nil, fmt.Errorf("failed to list jump pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		clusterID := pod.Labels[jumpPodLabelKey]
+		if reason := labelledReason(clusterID, pod.CreationTimestamp.Time); reason != "" {
+			artifacts = append(artifacts, breakGlassArtifact{Kind: "jump pod", Namespace: pod.Namespace, Name: pod.Name, ClusterID: clusterID, Reason: reason, object: pod})
+		}
+	}
+
+	roleBindings := rbacv1.RoleBindingList{}
+	if err := kubeCli.List(ctx, &roleBindings, labelled); err != nil {
+		return nil, nil, fmt.Errorf("failed to list role bindings: %w", err)
+	}
+	for i := range roleBindings.Items {
+		binding := &roleBindings.Items[i]
+		clusterID := binding.Labels[jumpPodLabelKey]
+		if reason := labelledReason(clusterID, binding.CreationTimestamp.Time); reason != "" {
+			artifacts = append(artifacts, breakGlassArtifact{Kind: "role binding", Namespace: binding.Namespace, Name: binding.Name, ClusterID: clusterID, Reason: reason, object: binding})
+		}
+	}
+
+	clusterRoleBindings := rbacv1.ClusterRoleBindingList{}
+	if err := kubeCli.List(ctx, &clusterRoleBindings, labelled); err != nil {
+		return nil, nil, fmt.Errorf("failed to list cluster role bindings: %w", err)
+	}
+	for i := range clusterRoleBindings.Items {
+		binding := &clusterRoleBindings.Items[i]
+		clusterID := binding.Labels[jumpPodLabelKey]
+		if reason := labelledReason(clusterID, binding.CreationTimestamp.Time); reason != "" {
+			artifacts = append(artifacts, breakGlassArtifact{Kind: "cluster role binding", Name: binding.Name, ClusterID: clusterID, Reason: reason, object: binding})
+		}
+	}
+
+	csrs := certificatesv1.CertificateSigningRequestList{}
+	if err := kubeCli.List(ctx, &csrs); err != nil {
+		return nil, nil, fmt.Errorf("failed to list certificate signing requests: %w", err)
+	}
+	for i := range csrs.Items {
+		csr := &csrs.Items[i]
+		if reason := breakGlassCSRReason(csr, existingNamespaces, now); reason != "" {
+			artifacts = append(artifacts, breakGlassArtifact{Kind: "break-glass CSR", Name: csr.Name, ClusterID: clusterIDOfNamespace(hosted, breakGlassSignerNamespace(csr.Spec.SignerName)), Reason: reason, object: csr})
+		}
+	}
+
+	return hosted, artifacts, nil
+}
+
+// breakGlassSignerNamespace returns the hosted control plane namespace of a break-glass signer, empty for
+// other signers
+func breakGlassSignerNamespace(signerName string) string {
+	if !strings.HasPrefix(signerName, hypershiftSignerPrefix) || !strings.Contains(signerName, breakGlassSignerFragment) {
+		return ""
+	}
+	signer := strings.TrimPrefix(signerName, hypershiftSignerPrefix)
+	if i := strings.LastIndex(signer, "."); i > 0 {
+		return signer[:i]
+	}
+	return ""
+}
+
+// breakGlassCSRReason tells why a break-glass CSR should be removed, empty when it shouldn't or isn't one
+func breakGlassCSRReason(csr *certificatesv1.CertificateSigningRequest, existingNamespaces map[string]bool, now time.Time) string {
+	namespace := breakGlassSignerNamespace(csr.Spec.SignerName)
+	if namespace == "" {
+		return ""
+	}
+	if !existingNamespaces[namespace] {
+		return "hosted control plane namespace is gone"
+	}
+	if csr.Spec.ExpirationSeconds != nil {
+		expiry := csr.CreationTimestamp.Add(time.Duration(*csr.Spec.ExpirationSeconds) * time.Second)
+		if now.After(expiry) {
+			return fmt.Sprintf("credentials expired %s", expiry.UTC().Format(time.RFC3339))
+		}
+	}
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type == certificatesv1.CertificateDenied || condition.Type == certificatesv1.CertificateFailed {
+			return fmt.Sprintf("request %s", strings.ToLower(string(condition.Type)))
+		}
+	}
+	return ""
+}
+
+func clusterIDOfNamespace(hosted hostedClusters, namespace string) string {
+	for clusterID, namespaces := range hosted {
+		for _, ns := range namespaces {
+			if ns == namespace || strings.HasPrefix(namespace, ns+"-") {
+				return clusterID
+			}
+		}
+	}
+	return ""
+}
+
+// findTemporaryIDPs returns the identity providers added for break-glass access to the hosted clusters
+func findTemporaryIDPs(conn *sdk.Connection, hosted hostedClusters) ([]breakGlassArtifact, error) {
+	clusterIDs := make([]string, 0, len(hosted))
+	for clusterID := range hosted {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	sort.Strings(clusterIDs)
+
+	var artifacts []breakGlassArtifact
+	for _, clusterID := range clusterIDs {
+		response, err := conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).IdentityProviders().List().Send()
+		if err != nil {
+			if response != nil && response.Status() == 404 {
+				continue
+			}
+			return artifacts, err
+		}
+		for _, idp := range response.Items().Slice() {
+			if strings.HasPrefix(idp.Name(), breakGlassIDPPrefix) {
+				artifacts = append(artifacts, breakGlassArtifact{Kind: "identity provider", Name: idp.Name(), ClusterID: clusterID, Reason: "temporary identity provider", idpID: idp.ID()})
+			}
+		}
+	}
+	return artifacts, nil
+}
+
+func deleteBreakGlassArtifact(ctx context.Context, kubeCli kclient.Client, conn *sdk.Connection, artifact breakGlassArtifact) error {
+	if artifact.object != nil {
+		if err := kubeCli.Delete(ctx, artifact.object); err != nil && !kerr.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	_, err := conn.ClustersMgmt().V1().Clusters().Cluster(artifact.ClusterID).IdentityProviders().IdentityProvider(artifact.idpID).Delete().Send()
+	return err
+}
+
+func (c *cleanupAccessOptions) printBreakGlassArtifacts(artifacts []breakGlassArtifact, withResult bool) {
+	table := printer.NewTablePrinter(c.Out, 20, 1, 3, ' ')
+	header := []string{"KIND", "NAME", "CLUSTER", "REASON"}
+	if withResult {
+		header = append(header, "RESULT")
+	}
+	table.AddRow(header)
+	for _, artifact := range artifacts {
+		row := []string{artifact.Kind, artifact.fullName(), artifact.ClusterID, artifact.Reason}
+		if withResult {
+			row = append(row, artifact.Result)
+		}
+		table.AddRow(row)
+	}
+	if err := table.Flush(); err != nil {
+		c.Errorln(fmt.Sprintf("Failed to print the break-glass artifacts: %v", err))
+	}
+}
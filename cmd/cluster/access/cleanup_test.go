@@ -7,9 +7,12 @@ import (
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/openshift/osdctl/pkg/k8s"
+	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -130,3 +133,73 @@ func TestCleanupAccessOptions_dropPrivateLinkAccess(t *testing.T) {
 		}
 	}
 }
+
+func TestFindBreakGlassArtifacts(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	recent := metav1.NewTime(now.Add(-time.Hour))
+	old := metav1.NewTime(now.Add(-24 * time.Hour))
+	expiration := int32(3600)
+
+	objs := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ocm-production-hosted", Labels: map[string]string{hiveNSLabelKey: "hosted"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ocm-production-hosted-name", Labels: map[string]string{hiveNSLabelKey: "hosted"}}},
+		// Jump pods
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "recent", Namespace: "ocm-production-hosted", CreationTimestamp: recent, Labels: map[string]string{jumpPodLabelKey: "hosted"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "expired", Namespace: "ocm-production-hosted", CreationTimestamp: old, Labels: map[string]string{jumpPodLabelKey: "hosted"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "orphaned", Namespace: "default", CreationTimestamp: recent, Labels: map[string]string{jumpPodLabelKey: "gone"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default", CreationTimestamp: old}},
+		// RBAC
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "stray", Namespace: "default", CreationTimestamp: recent, Labels: map[string]string{jumpPodLabelKey: "gone"}}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "current", CreationTimestamp: recent, Labels: map[string]string{jumpPodLabelKey: "hosted"}}},
+		// CSRs
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "valid", CreationTimestamp: recent}, Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: "hypershift.openshift.io/ocm-production-hosted-name.sre-break-glass", ExpirationSeconds: &expiration}},
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "expired-csr", CreationTimestamp: old}, Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: "hypershift.openshift.io/ocm-production-hosted-name.sre-break-glass", ExpirationSeconds: &expiration}},
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "orphaned-csr", CreationTimestamp: recent}, Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: "hypershift.openshift.io/ocm-production-gone-name.customer-break-glass"}},
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "kubelet", CreationTimestamp: old}, Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: "kubernetes.io/kubelet-serving"}},
+	}
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{corev1.AddToScheme, rbacv1.AddToScheme, certificatesv1.AddToScheme} {
+		if err := addToScheme(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+
+	hosted, artifacts, err := findBreakGlassArtifacts(context.TODO(), client, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosted) != 1 || len(hosted["hosted"]) != 2 {
+		t.Errorf("unexpected hosted clusters %v", hosted)
+	}
+
+	var got []string
+	for _, artifact := range artifacts {
+		got = append(got, fmt.Sprintf("%s %s %s", artifact.Kind, artifact.fullName(), artifact.ClusterID))
+	}
+	slices.Sort(got)
+	want := []string{
+		"break-glass CSR expired-csr hosted",
+		"break-glass CSR orphaned-csr ",
+		"jump pod default/orphaned gone",
+		"jump pod ocm-production-hosted/expired hosted",
+		"role binding default/stray gone",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findBreakGlassArtifacts() = %v, want %v", got, want)
+	}
+}
+
+func TestBreakGlassSignerNamespace(t *testing.T) {
+	tests := map[string]string{
+		"hypershift.openshift.io/ocm-production-abc-name.sre-break-glass":      "ocm-production-abc-name",
+		"hypershift.openshift.io/ocm-production-abc-name.customer-break-glass": "ocm-production-abc-name",
+		"hypershift.openshift.io/ocm-production-abc-name.kubelet-serving":      "",
+		"kubernetes.io/kube-apiserver-client":                                  "",
+	}
+	for signer, want := range tests {
+		if got := breakGlassSignerNamespace(signer); got != want {
+			t.Errorf("breakGlassSignerNamespace(%s) = %s, want %s", signer, got, want)
+		}
+	}
+}
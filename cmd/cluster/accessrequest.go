@@ -0,0 +1,370 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	accessRequestsAPIPath   = "/api/access_transparency/v1/access_requests"
+	accessProtectionAPIPath = "/api/access_transparency/v1/access_protection"
+
+	accessRequestPending   = "Pending"
+	accessRequestApproved  = "Approved"
+	accessRequestDenied    = "Denied"
+	accessRequestExpired   = "Expired"
+	accessRequestCancelled = "Cancelled"
+)
+
+// accessRequest is an OCM access transparency request for SRE access to a cluster protected by the customer
+type accessRequest struct {
+	ID                    string    `json:"id"`
+	ClusterID             string    `json:"cluster_id"`
+	SubscriptionID        string    `json:"subscription_id"`
+	RequestedBy           string    `json:"requested_by"`
+	Justification         string    `json:"justification"`
+	InternalSupportCaseID string    `json:"internal_support_case_id"`
+	Duration              string    `json:"duration"`
+	Deadline              string    `json:"deadline"`
+	DeadlineAt            time.Time `json:"deadline_at"`
+	CreatedAt             time.Time `json:"created_at"`
+	Status                struct {
+		State     string    `json:"state"`
+		ExpiresAt time.Time `json:"expires_at"`
+	} `json:"status"`
+	Decisions []accessDecision `json:"decisions"`
+}
+
+// accessDecision is the customer's decision on an access request
+type accessDecision struct {
+	Decision      string    `json:"decision"`
+	DecidedBy     string    `json:"decided_by"`
+	Justification string    `json:"justification"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type accessRequestList struct {
+	Items []accessRequest `json:"items"`
+}
+
+func newCmdAccessRequest() *cobra.Command {
+	accessRequestCmd := &cobra.Command{
+		Use:   "access-request",
+		Short: "Request the customer's approval to access a cluster with access protection",
+		Long: `Clusters with access protection enabled require the customer to approve the access of SRE through an
+OCM access request. These commands create the requests, show their status and wait for their approval.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	accessRequestCmd.AddCommand(newCmdAccessRequestCreate())
+	accessRequestCmd.AddCommand(newCmdAccessRequestStatus())
+	accessRequestCmd.AddCommand(newCmdAccessRequestWait())
+	return accessRequestCmd
+}
+
+type accessRequestWaitOptions struct {
+	requestID string
+	timeout   time.Duration
+	interval  time.Duration
+}
+
+func (o *accessRequestWaitOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 12*time.Hour, "How long to wait for the customer's decision")
+	cmd.Flags().DurationVar(&o.interval, "interval", 30*time.Second, "How often OCM is polled")
+}
+
+func (o *accessRequestWaitOptions) validate() error {
+	if o.interval <= 0 || o.timeout <= 0 {
+		return fmt.Errorf("--interval and --timeout must be positive")
+	}
+	return nil
+}
+
+type accessRequestCreateOptions struct {
+	clusterID     string
+	justification string
+	caseID        string
+	duration      time.Duration
+	deadline      time.Duration
+	wait          bool
+	waitOptions   accessRequestWaitOptions
+}
+
+func newCmdAccessRequestCreate() *cobra.Command {
+	ops := &accessRequestCreateOptions{}
+	createCmd := &cobra.Command{
+		Use:   "create <cluster-id>",
+		Short: "Ask the customer to approve the access of SRE to a cluster",
+		Long: `Create an access request for a cluster with access protection enabled. The customer is notified and
+has until --deadline to approve it, the access is then granted for --duration. With --wait, the command
+polls the request until the customer decides, as 'access-request wait' does.`,
+		Example: `  # Request 8 hours of access for an OHSS ticket and wait for the approval
+  osdctl cluster access-request create <cluster-id> --justification "Investigate the failing upgrade" --case-id OHSS-1234 --wait`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	createCmd.Flags().StringVar(&ops.justification, "justification", "", "Why SRE needs to access the cluster, shown to the customer")
+	createCmd.Flags().StringVar(&ops.caseID, "case-id", "", "Internal support case or Jira ticket the access is for")
+	createCmd.Flags().DurationVar(&ops.duration, "duration", 8*time.Hour, "How long the access is granted for once approved")
+	createCmd.Flags().DurationVar(&ops.deadline, "deadline", 8*time.Hour, "How long the customer has to approve the request")
+	createCmd.Flags().BoolVar(&ops.wait, "wait", false, "Wait for the customer's decision")
+	ops.waitOptions.addFlags(createCmd)
+	_ = createCmd.MarkFlagRequired("justification")
+	_ = createCmd.MarkFlagRequired("case-id")
+
+	return createCmd
+}
+
+func (o *accessRequestCreateOptions) validate() error {
+	if o.duration <= 0 || o.deadline <= 0 {
+		return fmt.Errorf("--duration and --deadline must be positive")
+	}
+	return o.waitOptions.validate()
+}
+
+func (o *accessRequestCreateOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	protected, err := accessProtectionEnabled(ocmClient, cluster.ID())
+	if err != nil {
+		return err
+	}
+	if !protected {
+		return fmt.Errorf("cluster %s doesn't have access protection enabled, its access doesn't need the customer's approval", cluster.ID())
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"cluster_id":               cluster.ID(),
+		"subscription_id":          cluster.Subscription().ID(),
+		"justification":            o.justification,
+		"internal_support_case_id": o.caseID,
+		"duration":                 o.duration.String(),
+		"deadline":                 o.deadline.String(),
+	})
+	if err != nil {
+		return err
+	}
+	var request accessRequest
+	if err := sendAccessTransparencyRequest(ocmClient.Post().Path(accessRequestsAPIPath).Bytes(body), &request); err != nil {
+		return fmt.Errorf("failed to create the access request for cluster %s: %w", cluster.ID(), err)
+	}
+	fmt.Printf("Created access request %s for cluster %s, the customer has until %s to approve it\n",
+		request.ID, cluster.ID(), request.DeadlineAt.Local().Format(time.DateTime))
+
+	if !o.wait {
+		fmt.Printf("Wait for the decision with 'osdctl cluster access-request wait %s'\n", request.ID)
+		return nil
+	}
+	o.waitOptions.requestID = request.ID
+	return o.waitOptions.run(ocmClient)
+}
+
+type accessRequestStatusOptions struct {
+	clusterID string
+	output    string
+}
+
+func newCmdAccessRequestStatus() *cobra.Command {
+	ops := &accessRequestStatusOptions{}
+	statusCmd := &cobra.Command{
+		Use:               "status <cluster-id>",
+		Short:             "List the access requests of a cluster and their status",
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+	statusCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Valid formats are ['table', 'json']")
+	return statusCmd
+}
+
+func (o *accessRequestStatusOptions) run() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format '%s', valid formats are 'table' and 'json'", o.output)
+	}
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	var list accessRequestList
+	request := ocmClient.Get().Path(accessRequestsAPIPath).
+		Parameter("search", fmt.Sprintf("cluster_id = '%s'", cluster.ID())).
+		Parameter("orderBy", "created_at desc")
+	if err := sendAccessTransparencyRequest(request, &list); err != nil {
+		return fmt.Errorf("failed to list the access requests of cluster %s: %w", cluster.ID(), err)
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		return list.Items[i].CreatedAt.After(list.Items[j].CreatedAt)
+	})
+
+	if o.output == "json" {
+		if list.Items == nil {
+			list.Items = []accessRequest{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list.Items)
+	}
+	if len(list.Items) == 0 {
+		fmt.Println("No access requests")
+		return nil
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"ID", "STATE", "REQUESTED BY", "CASE", "CREATED", "DETAILS"})
+	for _, request := range list.Items {
+		table.AddRow([]string{
+			request.ID,
+			request.Status.State,
+			request.RequestedBy,
+			request.InternalSupportCaseID,
+			request.CreatedAt.Local().Format(time.DateTime),
+			describeAccessRequest(request),
+		})
+	}
+	table.AddRow([]string{})
+	return table.Flush()
+}
+
+func newCmdAccessRequestWait() *cobra.Command {
+	ops := &accessRequestWaitOptions{}
+	waitCmd := &cobra.Command{
+		Use:   "wait <access-request-id>",
+		Short: "Wait for the customer to approve an access request",
+		Long: `Poll OCM until the customer decides on the access request. The terminal bell rings and the command
+succeeds once the access is granted, it fails when the request is denied, expires or is cancelled.`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.requestID = args[0]
+			cmdutil.CheckErr(ops.validate())
+
+			ocmClient, err := utils.CreateConnection()
+			cmdutil.CheckErr(err)
+			defer ocmClient.Close()
+			cmdutil.CheckErr(ops.run(ocmClient))
+		},
+	}
+	ops.addFlags(waitCmd)
+	return waitCmd
+}
+
+func (o *accessRequestWaitOptions) run(ocmClient *sdk.Connection) error {
+	fmt.Printf("Waiting up to %s for the customer to decide on access request %s\n", o.timeout, o.requestID)
+	deadline := time.Now().Add(o.timeout)
+	lastState := ""
+	for {
+		var request accessRequest
+		if err := sendAccessTransparencyRequest(ocmClient.Get().Path(path.Join(accessRequestsAPIPath, o.requestID)), &request); err != nil {
+			// OCM hiccups shouldn't fail a long wait, the next poll will tell
+			fmt.Fprintf(os.Stderr, "Failed to get the access request from OCM: %v\n", err)
+		} else {
+			if request.Status.State != lastState {
+				fmt.Printf("[%s] state: %s\n", time.Now().UTC().Format(time.RFC3339), request.Status.State)
+				lastState = request.Status.State
+			}
+			granted, err := evaluateAccessRequest(request)
+			if err != nil {
+				return fmt.Errorf("access request %s won't be granted: %w", o.requestID, err)
+			}
+			if granted {
+				// ring the terminal bell, the wait is usually left running in another window
+				fmt.Printf("\aAccess to cluster %s was granted: %s\n", request.ClusterID, describeAccessRequest(request))
+				return nil
+			}
+		}
+
+		if time.Now().Add(o.interval).After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for access request %s to be approved", o.timeout, o.requestID)
+		}
+		time.Sleep(o.interval)
+	}
+}
+
+// evaluateAccessRequest returns whether the access was granted, and an error if it can't be anymore
+func evaluateAccessRequest(request accessRequest) (bool, error) {
+	switch request.Status.State {
+	case accessRequestApproved:
+		return true, nil
+	case accessRequestDenied, accessRequestExpired, accessRequestCancelled:
+		return false, fmt.Errorf("the request is %s: %s", request.Status.State, describeAccessRequest(request))
+	}
+	return false, nil
+}
+
+// describeAccessRequest summarizes the decision on the request, or its deadline while it's pending
+func describeAccessRequest(request accessRequest) string {
+	if request.Status.State == accessRequestPending || request.Status.State == "" {
+		if request.DeadlineAt.IsZero() {
+			return "waiting for the customer"
+		}
+		return fmt.Sprintf("waiting for the customer until %s", request.DeadlineAt.Local().Format(time.DateTime))
+	}
+	if len(request.Decisions) == 0 {
+		return request.Status.State
+	}
+	decision := request.Decisions[len(request.Decisions)-1]
+	description := fmt.Sprintf("%s by %s", decision.Decision, decision.DecidedBy)
+	if decision.Justification != "" {
+		description += fmt.Sprintf(" (%s)", decision.Justification)
+	}
+	if request.Status.State == accessRequestApproved && !request.Status.ExpiresAt.IsZero() {
+		description += fmt.Sprintf(", until %s", request.Status.ExpiresAt.Local().Format(time.DateTime))
+	}
+	return description
+}
+
+// accessProtectionEnabled returns true when the access to the cluster requires the customer's approval
+func accessProtectionEnabled(ocmClient *sdk.Connection, clusterID string) (bool, error) {
+	var protection struct {
+		Enabled bool `json:"enabled"`
+	}
+	request := ocmClient.Get().Path(accessProtectionAPIPath).Parameter("clusterId", clusterID)
+	if err := sendAccessTransparencyRequest(request, &protection); err != nil {
+		return false, fmt.Errorf("failed to get the access protection of cluster %s: %w", clusterID, err)
+	}
+	return protection.Enabled, nil
+}
+
+func sendAccessTransparencyRequest(request *sdk.Request, result interface{}) error {
+	response, err := request.Send()
+	if err != nil {
+		return err
+	}
+	if response.Status() >= http.StatusBadRequest {
+		return fmt.Errorf("%d: %s", response.Status(), response.String())
+	}
+	return json.Unmarshal(response.Bytes(), result)
+}
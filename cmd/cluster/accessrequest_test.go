@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateAccessRequest(t *testing.T) {
+	tests := []struct {
+		state       string
+		wantGranted bool
+		wantErr     bool
+	}{
+		{state: accessRequestPending},
+		{state: ""},
+		{state: accessRequestApproved, wantGranted: true},
+		{state: accessRequestDenied, wantErr: true},
+		{state: accessRequestExpired, wantErr: true},
+		{state: accessRequestCancelled, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			var request accessRequest
+			request.Status.State = tt.state
+			granted, err := evaluateAccessRequest(request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("evaluateAccessRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if granted != tt.wantGranted {
+				t.Errorf("evaluateAccessRequest() = %v, want %v", granted, tt.wantGranted)
+			}
+		})
+	}
+}
+
+func TestDescribeAccessRequest(t *testing.T) {
+	deadline := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	pending := accessRequest{DeadlineAt: deadline}
+	pending.Status.State = accessRequestPending
+
+	denied := accessRequest{}
+	denied.Status.State = accessRequestDenied
+	denied.Decisions = []accessDecision{{Decision: accessRequestDenied, DecidedBy: "admin@example.com", Justification: "Change freeze"}}
+
+	approved := accessRequest{}
+	approved.Status.State = accessRequestApproved
+	approved.Status.ExpiresAt = deadline
+	approved.Decisions = []accessDecision{{Decision: accessRequestApproved, DecidedBy: "admin@example.com"}}
+
+	expired := accessRequest{}
+	expired.Status.State = accessRequestExpired
+
+	tests := []struct {
+		name    string
+		request accessRequest
+		want    string
+	}{
+		{
+			name:    "pending",
+			request: pending,
+			want:    "waiting for the customer until " + deadline.Local().Format(time.DateTime),
+		},
+		{
+			name:    "denied",
+			request: denied,
+			want:    "Denied by admin@example.com (Change freeze)",
+		},
+		{
+			name:    "approved",
+			request: approved,
+			want:    "Approved by admin@example.com, until " + deadline.Local().Format(time.DateTime),
+		},
+		{
+			name:    "expired without a decision",
+			request: expired,
+			want:    accessRequestExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeAccessRequest(tt.request); got != tt.want {
+				t.Errorf("describeAccessRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
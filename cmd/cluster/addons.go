@@ -0,0 +1,229 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	addonStateFailed        = "failed"
+	addonDetailsMaxLength   = 80
+	addonInstallationsPage  = 100
+	addonNotInstalledHealth = "No add-ons installed"
+)
+
+type addonsOptions struct {
+	clusterID  string
+	output     string
+	failedOnly bool
+}
+
+func newCmdAddons() *cobra.Command {
+	ops := &addonsOptions{}
+	addonsCmd := &cobra.Command{
+		Use:   "addons <cluster-id>",
+		Short: "List the add-ons installed on a cluster and their state",
+		Long: `List the add-ons installed on a cluster from OCM with their version and state, followed by the
+reason of the failed installations. Add-on failures often look like cluster problems, so this is worth
+a look before digging into the cluster itself.`,
+		Example: `  # All the add-ons of a cluster
+  osdctl cluster addons <cluster-id>
+
+  # Only the failed installations, as JSON
+  osdctl cluster addons <cluster-id> --failed -o json`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	addonsCmd.Flags().StringVarP(&ops.output, "output", "o", "text", "Output format, one of text or json")
+	addonsCmd.Flags().BoolVar(&ops.failedOnly, "failed", false, "Only list the failed installations")
+
+	return addonsCmd
+}
+
+func (o *addonsOptions) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output format '%s', expected text or json", o.output)
+	}
+	return nil
+}
+
+func (o *addonsOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	installations, err := fetchAddonInstallations(ocmClient, cluster.ID())
+	if err != nil {
+		return err
+	}
+	if o.failedOnly {
+		installations = failedAddonInstallations(installations)
+	}
+
+	if o.output == "json" {
+		out, err := marshalSDKList(func(w io.Writer) error {
+			return cmv1.MarshalAddOnInstallationList(installations, w)
+		})
+		if err != nil {
+			return err
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, out, "", "  "); err != nil {
+			return err
+		}
+		fmt.Println(indented.String())
+		return nil
+	}
+
+	printAddonInstallations(installations)
+	return nil
+}
+
+// fetchAddonInstallations returns the add-ons installed on the cluster, sorted by ID
+func fetchAddonInstallations(ocmClient *sdk.Connection, clusterID string) ([]*cmv1.AddOnInstallation, error) {
+	var installations []*cmv1.AddOnInstallation
+	for page := 1; ; page++ {
+		response, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(clusterID).Addons().List().
+			Page(page).
+			Size(addonInstallationsPage).
+			Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the add-ons of cluster %s: %w", clusterID, err)
+		}
+		installations = append(installations, response.Items().Slice()...)
+		if response.Size() < addonInstallationsPage || len(installations) >= response.Total() {
+			break
+		}
+	}
+	sortAddonInstallations(installations)
+	return installations, nil
+}
+
+func sortAddonInstallations(installations []*cmv1.AddOnInstallation) {
+	sort.SliceStable(installations, func(i, j int) bool {
+		return addonName(installations[i]) < addonName(installations[j])
+	})
+}
+
+func failedAddonInstallations(installations []*cmv1.AddOnInstallation) []*cmv1.AddOnInstallation {
+	var failed []*cmv1.AddOnInstallation
+	for _, installation := range installations {
+		if string(installation.State()) == addonStateFailed {
+			failed = append(failed, installation)
+		}
+	}
+	return failed
+}
+
+// addonName is the ID of the add-on, the installations only link to the add-on itself
+func addonName(installation *cmv1.AddOnInstallation) string {
+	if id := installation.Addon().ID(); id != "" {
+		return id
+	}
+	return installation.ID()
+}
+
+// addonRow returns the columns of an installation in the add-ons table
+func addonRow(installation *cmv1.AddOnInstallation) []string {
+	updated := ""
+	if !installation.UpdatedTimestamp().IsZero() {
+		updated = installation.UpdatedTimestamp().UTC().Format(time.RFC3339)
+	}
+	details := strings.Join(strings.Fields(installation.StateDescription()), " ")
+	if len(details) > addonDetailsMaxLength {
+		details = details[:addonDetailsMaxLength-3] + "..."
+	}
+	return []string{
+		addonName(installation),
+		installation.AddonVersion().ID(),
+		string(installation.State()),
+		updated,
+		details,
+	}
+}
+
+func printAddonInstallations(installations []*cmv1.AddOnInstallation) {
+	if len(installations) == 0 {
+		fmt.Println("None")
+		return
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"ADDON", "VERSION", "STATE", "UPDATED", "DETAILS"})
+	for _, installation := range installations {
+		table.AddRow(addonRow(installation))
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the add-ons: %v\n", err)
+	}
+
+	failed := failedAddonInstallations(installations)
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("Failed installations:")
+	for _, installation := range failed {
+		fmt.Printf("- %s: %s\n", addonName(installation), strings.TrimSpace(installation.StateDescription()))
+	}
+}
+
+// addonHealth summarizes the state of the add-ons in one line, e.g. "3 installed: 2 ready, 1 failed (managed-odh)"
+func addonHealth(installations []*cmv1.AddOnInstallation) string {
+	if len(installations) == 0 {
+		return addonNotInstalledHealth
+	}
+	counts := map[string]int{}
+	for _, installation := range installations {
+		counts[string(installation.State())]++
+	}
+	states := make([]string, 0, len(counts))
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	parts := make([]string, 0, len(states))
+	for _, state := range states {
+		part := fmt.Sprintf("%d %s", counts[state], state)
+		if state == addonStateFailed {
+			var names []string
+			for _, installation := range failedAddonInstallations(installations) {
+				names = append(names, addonName(installation))
+			}
+			part += fmt.Sprintf(" (%s)", strings.Join(names, ", "))
+		}
+		parts = append(parts, part)
+	}
+	return fmt.Sprintf("%d installed: %s", len(installations), strings.Join(parts, ", "))
+}
+
+func printAddonHealth(installations []*cmv1.AddOnInstallation) {
+	fmt.Println(delimiter + "Add-ons")
+	fmt.Println(addonHealth(installations))
+}
@@ -0,0 +1,77 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func addonInstallation(t *testing.T, id string, state string, description string) *cmv1.AddOnInstallation {
+	installation, err := cmv1.NewAddOnInstallation().
+		ID(id).
+		Addon(cmv1.NewAddOn().ID(id)).
+		AddonVersion(cmv1.NewAddOnVersion().ID("1.0.0")).
+		State(cmv1.AddOnInstallationState(state)).
+		StateDescription(description).
+		UpdatedTimestamp(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return installation
+}
+
+func TestAddonHealth(t *testing.T) {
+	tests := []struct {
+		name          string
+		installations []*cmv1.AddOnInstallation
+		want          string
+	}{
+		{
+			name: "no add-ons",
+			want: addonNotInstalledHealth,
+		},
+		{
+			name: "all ready",
+			installations: []*cmv1.AddOnInstallation{
+				addonInstallation(t, "cluster-logging-operator", "ready", ""),
+				addonInstallation(t, "managed-api-service", "ready", ""),
+			},
+			want: "2 installed: 2 ready",
+		},
+		{
+			name: "failed installations",
+			installations: []*cmv1.AddOnInstallation{
+				addonInstallation(t, "managed-odh", addonStateFailed, "CSV failed"),
+				addonInstallation(t, "cluster-logging-operator", "ready", ""),
+				addonInstallation(t, "rhoams", addonStateFailed, "quota exceeded"),
+				addonInstallation(t, "dbaas-operator", "installing", ""),
+			},
+			want: "4 installed: 2 failed (managed-odh, rhoams), 1 installing, 1 ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addonHealth(tt.installations); got != tt.want {
+				t.Errorf("addonHealth() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddonRow(t *testing.T) {
+	installation := addonInstallation(t, "managed-odh", addonStateFailed, "Install plan failed:\n  the CSV managed-odh.v1.0.0 couldn't be installed because of a conflicting operator group")
+	want := []string{
+		"managed-odh",
+		"1.0.0",
+		addonStateFailed,
+		"2024-03-01T10:00:00Z",
+		"Install plan failed: the CSV managed-odh.v1.0.0 couldn't be installed because...",
+	}
+	if got := addonRow(installation); !reflect.DeepEqual(got, want) {
+		t.Errorf("addonRow() = %q, want %q", got, want)
+	}
+}
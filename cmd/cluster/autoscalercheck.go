@@ -0,0 +1,539 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+	ctAws "github.com/openshift/osdctl/cmd/cloudtrail/pkg/aws"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	autoscalerCheckPass = "PASS"
+	autoscalerCheckWarn = "WARN"
+	autoscalerCheckFail = "FAIL"
+
+	machineAPINamespace    = "openshift-machine-api"
+	machineSetLabel        = "machine.openshift.io/cluster-api-machineset"
+	notTriggerScaleUp      = "NotTriggerScaleUp"
+	maxNodeGroupSizeReason = "max node group size reached"
+)
+
+var (
+	clusterAutoscalerGVK     = schema.GroupVersionKind{Group: "autoscaling.openshift.io", Version: "v1", Kind: "ClusterAutoscaler"}
+	machineAutoscalerListGVK = schema.GroupVersionKind{Group: "autoscaling.openshift.io", Version: "v1beta1", Kind: "MachineAutoscalerList"}
+	machineSetListGVK        = schema.GroupVersionKind{Group: "machine.openshift.io", Version: "v1beta1", Kind: "MachineSetList"}
+	machineListGVK           = schema.GroupVersionKind{Group: "machine.openshift.io", Version: "v1beta1", Kind: "MachineList"}
+
+	// scaleUpEventReasons are the events of the cluster autoscaler about scale-ups which didn't happen or failed
+	scaleUpEventReasons = []string{"FailedToScaleUpGroup", "ScaleUpTimedOut", "FailedScaleUp"}
+
+	// cloudCapacityErrors are the errors of RunInstances preventing new machines, with what to do about them
+	cloudCapacityErrors = map[string]string{
+		"InsufficientInstanceCapacity":      "AWS has no capacity for the instance type in the availability zone, retry later or use another instance type or zone",
+		"VcpuLimitExceeded":                 "the vCPU quota of the instance family is reached, request a quota increase",
+		"InstanceLimitExceeded":             "the instance quota is reached, request a quota increase",
+		"MaxSpotInstanceCountExceeded":      "the spot instance quota is reached, request a quota increase",
+		"InsufficientFreeAddressesInSubnet": "the subnet has no free IP address left",
+		"UnauthorizedOperation":             "the installer role can't launch instances, check the SCPs and the role policies",
+		"Unsupported":                       "the instance type isn't offered in the availability zone",
+	}
+)
+
+type autoscalerCheckOptions struct {
+	clusterID string
+	reason    string
+	since     string
+}
+
+type autoscalerCheckResult struct {
+	Check   string
+	Status  string
+	Details string
+}
+
+// cloudCapacityError is a failed RunInstances call of the cluster
+type cloudCapacityError struct {
+	Code    string
+	Message string
+	Count   int
+	Last    time.Time
+}
+
+// autoscalingFacts are what the diagnosis of the pending pods is drawn from
+type autoscalingFacts struct {
+	HasClusterAutoscaler bool
+	AtMaxNodesTotal      bool
+	MaxedMachineSets     []string
+	FailedMachines       int
+	// PendingReasons counts the pending pods per reason the scheduler or the autoscaler gave
+	PendingReasons map[string]int
+	CloudErrors    []cloudCapacityError
+}
+
+func newCmdAutoscalerCheck() *cobra.Command {
+	ops := &autoscalerCheckOptions{}
+	autoscalerCheckCmd := &cobra.Command{
+		Use:   "autoscaler-check <cluster-id>",
+		Short: "Diagnose why a cluster doesn't scale up",
+		Long: `Run the standard checks when customers report that their pods stay pending on an autoscaled cluster:
+  - the ClusterAutoscaler exists and the cluster isn't at its maxNodesTotal limit
+  - every MachineAutoscaler targets an existing machine set, and which machine sets are at their maximum
+  - the machines which failed to be provisioned, and the failed scale-ups reported by the autoscaler
+  - on AWS, the RunInstances calls of the cluster which failed on a capacity or quota error in CloudTrail
+
+The reasons the scheduler and the autoscaler give for the pending pods are then summarized, with the likely
+cause of the pods not being scheduled.`,
+		Example:           `  osdctl cluster autoscaler-check <cluster-id> --since 2d --reason OHSS-1234`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	autoscalerCheckCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this command, which requires elevation to read the machines (e.g. an OHSS ticket)")
+	autoscalerCheckCmd.Flags().StringVar(&ops.since, "since", "1d", "How far back to look for failed instance launches in CloudTrail, at most 90d")
+
+	return autoscalerCheckCmd
+}
+
+func (o *autoscalerCheckOptions) run() error {
+	since, err := ctUtil.ParseDuration(o.since)
+	if err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.Hypershift().Enabled() {
+		return fmt.Errorf("cluster %s has a hosted control plane, its machine pools are autoscaled from the management cluster", cluster.ID())
+	}
+
+	var elevationReasons []string
+	if o.reason != "" {
+		elevationReasons = append(elevationReasons, o.reason, "Checking the cluster autoscaler")
+	}
+	kubeCli, _, clientset, err := common.GetKubeConfigAndClient(cluster.ID(), elevationReasons...)
+	if err != nil {
+		return fmt.Errorf("failed to access cluster %s: %w", cluster.ID(), err)
+	}
+
+	ctx := context.TODO()
+	facts := autoscalingFacts{PendingReasons: map[string]int{}}
+	results := checkClusterAutoscaler(ctx, kubeCli, clientset, &facts)
+	results = append(results, checkMachineAutoscalers(ctx, kubeCli, &facts)...)
+	results = append(results, checkFailedMachines(ctx, kubeCli, &facts))
+	results = append(results, checkScaleUpEvents(ctx, clientset))
+
+	if cluster.CloudProvider().ID() == "aws" {
+		cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to access the AWS account, CloudTrail won't be checked: %v\n", err)
+		} else {
+			events, err := ctAws.GetEventsByName(cloudtrail.NewFromConfig(cfg), time.Now().Add(-since), "RunInstances")
+			if err != nil {
+				results = append(results, autoscalerCheckResult{"Instance launches", autoscalerCheckWarn, fmt.Sprintf("failed to look up CloudTrail: %v", err)})
+			} else {
+				facts.CloudErrors = cloudCapacityErrorsOf(events, cluster.InfraID())
+				results = append(results, evaluateCloudCapacityErrors(facts.CloudErrors, o.since))
+			}
+		}
+	}
+
+	pending, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Pending"})
+	if err != nil {
+		return fmt.Errorf("failed to list the pending pods: %w", err)
+	}
+	notTriggered, err := clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{FieldSelector: "reason=" + notTriggerScaleUp})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list the %s events, the autoscaler's reasons won't be shown: %v\n", notTriggerScaleUp, err)
+		notTriggered = &corev1.EventList{}
+	}
+	unschedulable := summarizePendingPods(pending.Items, notTriggered.Items, facts.PendingReasons)
+
+	fmt.Printf("%sAutoscaling of cluster %s (%s)\n", delimiter, cluster.Name(), cluster.ID())
+	failures := printAutoscalerCheckResults(results)
+	fmt.Println()
+	printPendingReasons(unschedulable, facts.PendingReasons)
+	if unschedulable > 0 {
+		fmt.Println("\nLikely causes:")
+		for _, cause := range diagnoseAutoscaling(facts) {
+			fmt.Printf("  - %s\n", cause)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d autoscaler checks failed", failures)
+	}
+	return nil
+}
+
+// checkClusterAutoscaler checks the ClusterAutoscaler exists and the cluster has room for more nodes
+func checkClusterAutoscaler(ctx context.Context, kubeCli client.Client, clientset *kubernetes.Clientset, facts *autoscalingFacts) []autoscalerCheckResult {
+	const check = "Cluster autoscaler"
+	autoscaler := &unstructured.Unstructured{}
+	autoscaler.SetGroupVersionKind(clusterAutoscalerGVK)
+	if err := kubeCli.Get(ctx, client.ObjectKey{Name: "default"}, autoscaler); err != nil {
+		return []autoscalerCheckResult{{check, autoscalerCheckWarn, fmt.Sprintf("no ClusterAutoscaler 'default', the cluster doesn't scale: %v", err)}}
+	}
+	facts.HasClusterAutoscaler = true
+
+	details := []string{"ClusterAutoscaler 'default' exists"}
+	if enabled, found, _ := unstructured.NestedBool(autoscaler.Object, "spec", "scaleDown", "enabled"); found && !enabled {
+		details = append(details, "scale down disabled")
+	}
+	results := []autoscalerCheckResult{{check, autoscalerCheckPass, strings.Join(details, ", ")}}
+
+	maxNodesTotal, found, _ := unstructured.NestedInt64(autoscaler.Object, "spec", "resourceLimits", "maxNodesTotal")
+	if !found || maxNodesTotal == 0 {
+		return results
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return append(results, autoscalerCheckResult{"Max nodes total", autoscalerCheckWarn, fmt.Sprintf("failed to list the nodes: %v", err)})
+	}
+	results = append(results, evaluateMaxNodesTotal(len(nodes.Items), maxNodesTotal))
+	facts.AtMaxNodesTotal = results[len(results)-1].Status == autoscalerCheckFail
+	return results
+}
+
+// evaluateMaxNodesTotal checks the cluster, control plane included, is below the maxNodesTotal limit
+func evaluateMaxNodesTotal(nodes int, maxNodesTotal int64) autoscalerCheckResult {
+	const check = "Max nodes total"
+	details := fmt.Sprintf("%d of %d nodes", nodes, maxNodesTotal)
+	if int64(nodes) >= maxNodesTotal {
+		return autoscalerCheckResult{check, autoscalerCheckFail, details + ", the autoscaler can't add any node"}
+	}
+	return autoscalerCheckResult{check, autoscalerCheckPass, details}
+}
+
+// checkMachineAutoscalers checks every MachineAutoscaler targets an existing machine set, and reports the
+// machine sets which are at their maximum
+func checkMachineAutoscalers(ctx context.Context, kubeCli client.Client, facts *autoscalingFacts) []autoscalerCheckResult {
+	const check = "Machine autoscalers"
+	autoscalers := &unstructured.UnstructuredList{}
+	autoscalers.SetGroupVersionKind(machineAutoscalerListGVK)
+	if err := kubeCli.List(ctx, autoscalers, client.InNamespace(machineAPINamespace)); err != nil {
+		return []autoscalerCheckResult{{check, autoscalerCheckWarn, fmt.Sprintf("failed to list the MachineAutoscalers: %v", err)}}
+	}
+	if len(autoscalers.Items) == 0 {
+		return []autoscalerCheckResult{{check, autoscalerCheckWarn, "no MachineAutoscaler, no machine set is autoscaled"}}
+	}
+
+	machineSets := &unstructured.UnstructuredList{}
+	machineSets.SetGroupVersionKind(machineSetListGVK)
+	if err := kubeCli.List(ctx, machineSets, client.InNamespace(machineAPINamespace)); err != nil {
+		return []autoscalerCheckResult{{check, autoscalerCheckWarn, fmt.Sprintf("failed to list the machine sets: %v", err)}}
+	}
+	replicas := map[string]int64{}
+	for _, machineSet := range machineSets.Items {
+		count, _, _ := unstructured.NestedInt64(machineSet.Object, "spec", "replicas")
+		replicas[machineSet.GetName()] = count
+	}
+
+	var results []autoscalerCheckResult
+	for _, autoscaler := range autoscalers.Items {
+		target, _, _ := unstructured.NestedString(autoscaler.Object, "spec", "scaleTargetRef", "name")
+		minReplicas, _, _ := unstructured.NestedInt64(autoscaler.Object, "spec", "minReplicas")
+		maxReplicas, _, _ := unstructured.NestedInt64(autoscaler.Object, "spec", "maxReplicas")
+		current, exists := replicas[target]
+		result := evaluateMachineAutoscaler(autoscaler.GetName(), target, exists, current, minReplicas, maxReplicas)
+		if exists && current >= maxReplicas {
+			facts.MaxedMachineSets = append(facts.MaxedMachineSets, target)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func evaluateMachineAutoscaler(name string, target string, exists bool, replicas int64, minReplicas int64, maxReplicas int64) autoscalerCheckResult {
+	check := "Machine autoscaler " + name
+	if !exists {
+		return autoscalerCheckResult{check, autoscalerCheckFail, fmt.Sprintf("targets machine set %s, which doesn't exist", target)}
+	}
+	details := fmt.Sprintf("%s has %d replicas, scaled from %d to %d", target, replicas, minReplicas, maxReplicas)
+	if replicas >= maxReplicas {
+		return autoscalerCheckResult{check, autoscalerCheckWarn, details + ", at its maximum"}
+	}
+	return autoscalerCheckResult{check, autoscalerCheckPass, details}
+}
+
+// checkFailedMachines reports the machines which failed to be provisioned, with the error of the provider
+func checkFailedMachines(ctx context.Context, kubeCli client.Client, facts *autoscalingFacts) autoscalerCheckResult {
+	const check = "Failed machines"
+	machines := &unstructured.UnstructuredList{}
+	machines.SetGroupVersionKind(machineListGVK)
+	if err := kubeCli.List(ctx, machines, client.InNamespace(machineAPINamespace)); err != nil {
+		return autoscalerCheckResult{check, autoscalerCheckWarn, fmt.Sprintf("failed to list the machines: %v", err)}
+	}
+	var failed []string
+	for _, machine := range machines.Items {
+		phase, _, _ := unstructured.NestedString(machine.Object, "status", "phase")
+		if phase != "Failed" {
+			continue
+		}
+		message, _, _ := unstructured.NestedString(machine.Object, "status", "errorMessage")
+		failed = append(failed, fmt.Sprintf("%s (%s): %s", machine.GetName(), machine.GetLabels()[machineSetLabel], message))
+	}
+	facts.FailedMachines = len(failed)
+	if len(failed) > 0 {
+		return autoscalerCheckResult{check, autoscalerCheckFail, strings.Join(failed, "; ")}
+	}
+	return autoscalerCheckResult{check, autoscalerCheckPass, fmt.Sprintf("none of the %d machines failed", len(machines.Items))}
+}
+
+// checkScaleUpEvents reports the scale-ups the autoscaler attempted and which failed or timed out
+func checkScaleUpEvents(ctx context.Context, clientset *kubernetes.Clientset) autoscalerCheckResult {
+	const check = "Scale-ups"
+	var failed []string
+	for _, reason := range scaleUpEventReasons {
+		events, err := clientset.CoreV1().Events(machineAPINamespace).List(ctx, metav1.ListOptions{FieldSelector: "reason=" + reason})
+		if err != nil {
+			return autoscalerCheckResult{check, autoscalerCheckWarn, fmt.Sprintf("failed to list the %s events: %v", reason, err)}
+		}
+		for _, event := range events.Items {
+			failed = append(failed, fmt.Sprintf("%s x%d: %s", event.Reason, event.Count, event.Message))
+		}
+	}
+	if len(failed) > 0 {
+		return autoscalerCheckResult{check, autoscalerCheckFail, strings.Join(failed, "; ")}
+	}
+	return autoscalerCheckResult{check, autoscalerCheckPass, "no failed scale-up reported"}
+}
+
+// cloudCapacityErrorsOf returns the capacity and quota errors of the RunInstances calls launching machines
+// of the cluster, which are tagged with its infra ID, grouped by error code
+func cloudCapacityErrorsOf(events []cttypes.Event, infraID string) []cloudCapacityError {
+	byCode := map[string]*cloudCapacityError{}
+	for _, event := range events {
+		if event.CloudTrailEvent == nil || !strings.Contains(*event.CloudTrailEvent, infraID) {
+			continue
+		}
+		var raw struct {
+			ErrorCode    string `json:"errorCode"`
+			ErrorMessage string `json:"errorMessage"`
+		}
+		if err := json.Unmarshal([]byte(*event.CloudTrailEvent), &raw); err != nil {
+			continue
+		}
+		code := strings.TrimPrefix(strings.TrimPrefix(raw.ErrorCode, "Client."), "Server.")
+		if _, ok := cloudCapacityErrors[code]; !ok {
+			continue
+		}
+		capacityError, ok := byCode[code]
+		if !ok {
+			capacityError = &cloudCapacityError{Code: code}
+			byCode[code] = capacityError
+		}
+		capacityError.Count++
+		if event.EventTime != nil && event.EventTime.After(capacityError.Last) {
+			capacityError.Last = *event.EventTime
+			capacityError.Message = raw.ErrorMessage
+		} else if capacityError.Message == "" {
+			capacityError.Message = raw.ErrorMessage
+		}
+	}
+
+	capacityErrors := make([]cloudCapacityError, 0, len(byCode))
+	for _, capacityError := range byCode {
+		capacityErrors = append(capacityErrors, *capacityError)
+	}
+	sort.Slice(capacityErrors, func(i, j int) bool {
+		return capacityErrors[i].Count > capacityErrors[j].Count || (capacityErrors[i].Count == capacityErrors[j].Count && capacityErrors[i].Code < capacityErrors[j].Code)
+	})
+	return capacityErrors
+}
+
+func evaluateCloudCapacityErrors(capacityErrors []cloudCapacityError, since string) autoscalerCheckResult {
+	const check = "Instance launches"
+	if len(capacityErrors) == 0 {
+		return autoscalerCheckResult{check, autoscalerCheckPass, fmt.Sprintf("no capacity or quota error in the last %s", since)}
+	}
+	details := make([]string, 0, len(capacityErrors))
+	for _, capacityError := range capacityErrors {
+		details = append(details, fmt.Sprintf("%s x%d, last at %s: %s", capacityError.Code, capacityError.Count, capacityError.Last.UTC().Format(time.RFC3339), capacityError.Message))
+	}
+	return autoscalerCheckResult{check, autoscalerCheckFail, strings.Join(details, "; ")}
+}
+
+// summarizePendingPods counts the unschedulable pods per reason given by the scheduler, and by the autoscaler
+// when it didn't trigger a scale-up for them, and returns how many pods are unschedulable. The pending pods
+// which are scheduled, e.g. pulling their images, are left out.
+func summarizePendingPods(pods []corev1.Pod, notTriggered []corev1.Event, reasons map[string]int) int {
+	latest := map[string]corev1.Event{}
+	for _, event := range notTriggered {
+		if event.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+		key := event.InvolvedObject.Namespace + "/" + event.InvolvedObject.Name
+		if previous, ok := latest[key]; !ok || event.LastTimestamp.After(previous.LastTimestamp.Time) {
+			latest[key] = event
+		}
+	}
+
+	unschedulable := 0
+	for _, pod := range pods {
+		var message string
+		scheduled := false
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type != corev1.PodScheduled {
+				continue
+			}
+			scheduled = condition.Status == corev1.ConditionTrue
+			message = condition.Message
+		}
+		if scheduled {
+			continue
+		}
+		unschedulable++
+
+		podReasons := schedulingReasons(message)
+		if len(podReasons) == 0 {
+			podReasons = []string{"not scheduled yet"}
+		}
+		if event, ok := latest[pod.Namespace+"/"+pod.Name]; ok {
+			for _, reason := range schedulingReasons(event.Message) {
+				podReasons = append(podReasons, "autoscaler: "+reason)
+			}
+		}
+		seen := map[string]bool{}
+		for _, reason := range podReasons {
+			if !seen[reason] {
+				seen[reason] = true
+				reasons[reason]++
+			}
+		}
+	}
+	return unschedulable
+}
+
+// schedulingReasons splits the message of the scheduler, e.g. "0/6 nodes are available: 3 Insufficient cpu,
+// 3 node(s) had untolerated taint {...}. preemption: ...", or of the autoscaler, e.g. "pod didn't trigger
+// scale-up: 2 max node group size reached", into its reasons without their node counts
+func schedulingReasons(message string) []string {
+	_, list, found := strings.Cut(message, ": ")
+	if !found {
+		return nil
+	}
+	if before, _, found := strings.Cut(list, ". preemption:"); found {
+		list = before
+	}
+	list = strings.TrimSuffix(strings.TrimSpace(list), ".")
+
+	var reasons []string
+	for _, part := range strings.Split(list, ", ") {
+		part = strings.TrimSpace(part)
+		if count, rest, found := strings.Cut(part, " "); found {
+			if _, err := strconv.Atoi(count); err == nil {
+				part = rest
+			}
+		}
+		if part != "" {
+			reasons = append(reasons, part)
+		}
+	}
+	return reasons
+}
+
+// diagnoseAutoscaling explains why the pending pods aren't scheduled, from the most to the least likely cause
+func diagnoseAutoscaling(facts autoscalingFacts) []string {
+	var causes []string
+	if !facts.HasClusterAutoscaler {
+		causes = append(causes, "the cluster has no ClusterAutoscaler, no node is added for the pending pods")
+	}
+	if facts.AtMaxNodesTotal {
+		causes = append(causes, "the cluster is at the maxNodesTotal of the ClusterAutoscaler, raise it to add nodes")
+	}
+	for _, capacityError := range facts.CloudErrors {
+		causes = append(causes, fmt.Sprintf("scale-ups fail on AWS with %s: %s", capacityError.Code, cloudCapacityErrors[capacityError.Code]))
+	}
+	if facts.FailedMachines > 0 && len(facts.CloudErrors) == 0 {
+		causes = append(causes, fmt.Sprintf("%d machines failed to be provisioned, see their errors above", facts.FailedMachines))
+	}
+	if facts.PendingReasons["autoscaler: "+maxNodeGroupSizeReason] > 0 || len(facts.MaxedMachineSets) > 0 {
+		maxed := "some machine sets"
+		if len(facts.MaxedMachineSets) > 0 {
+			maxed = strings.Join(facts.MaxedMachineSets, ", ")
+		}
+		causes = append(causes, fmt.Sprintf("%s are at the maxReplicas of their MachineAutoscaler, raise it to add nodes", maxed))
+	}
+	for reason := range facts.PendingReasons {
+		if strings.Contains(reason, "node affinity/selector") || strings.Contains(reason, "untolerated taint") {
+			causes = append(causes, "some pods only fit nodes with specific labels or tolerations, check a machine set provides such nodes")
+			break
+		}
+	}
+	if len(causes) == 0 {
+		causes = append(causes, "no autoscaling problem found, the pods may be waiting for nodes being provisioned or for resources no node size provides")
+	}
+	return causes
+}
+
+func printAutoscalerCheckResults(results []autoscalerCheckResult) int {
+	failures := 0
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, result := range results {
+		if result.Status == autoscalerCheckFail {
+			failures++
+		}
+		table.AddRow([]string{result.Check, result.Status, result.Details})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the autoscaler checks: %v\n", err)
+	}
+	return failures
+}
+
+func printPendingReasons(unschedulable int, reasons map[string]int) {
+	if unschedulable == 0 {
+		fmt.Println("No unschedulable pod")
+		return
+	}
+	fmt.Printf("%d unschedulable pods:\n", unschedulable)
+	sorted := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		sorted = append(sorted, reason)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return reasons[sorted[i]] > reasons[sorted[j]] || (reasons[sorted[i]] == reasons[sorted[j]] && sorted[i] < sorted[j])
+	})
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"REASON", "PODS"})
+	for _, reason := range sorted {
+		table.AddRow([]string{reason, strconv.Itoa(reasons[reason])})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the pending pods: %v\n", err)
+	}
+}
@@ -0,0 +1,121 @@
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSchedulingReasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected []string
+	}{
+		{
+			name:     "scheduler",
+			message:  "0/6 nodes are available: 3 Insufficient cpu, 3 node(s) had untolerated taint {node-role.kubernetes.io/master: }. preemption: 0/6 nodes are available: 6 Preemption is not helpful for scheduling.",
+			expected: []string{"Insufficient cpu", "node(s) had untolerated taint {node-role.kubernetes.io/master: }"},
+		},
+		{
+			name:     "autoscaler",
+			message:  "pod didn't trigger scale-up: 2 max node group size reached, 1 node(s) didn't match Pod's node affinity/selector",
+			expected: []string{"max node group size reached", "node(s) didn't match Pod's node affinity/selector"},
+		},
+		{
+			name:     "without counts",
+			message:  "0/3 nodes are available: persistentvolumeclaim \"data\" not found.",
+			expected: []string{"persistentvolumeclaim \"data\" not found"},
+		},
+		{
+			name:    "no reason",
+			message: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reasons := schedulingReasons(tt.message); !reflect.DeepEqual(reasons, tt.expected) {
+				t.Errorf("schedulingReasons() = %q, want %q", reasons, tt.expected)
+			}
+		})
+	}
+}
+
+func runInstancesEvent(infraID string, errorCode string, eventTime time.Time) cttypes.Event {
+	raw := fmt.Sprintf(`{"eventVersion": "1.08", "errorCode": %q, "errorMessage": "failed at %s", "requestParameters": {"tagSpecificationSet": {"items": [{"tags": [{"key": "Name", "value": "%s-worker-a"}]}]}}}`, errorCode, eventTime.Format(time.RFC3339), infraID)
+	return cttypes.Event{EventName: aws.String("RunInstances"), EventTime: aws.Time(eventTime), CloudTrailEvent: aws.String(raw)}
+}
+
+func TestCloudCapacityErrorsOf(t *testing.T) {
+	first := time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+	events := []cttypes.Event{
+		runInstancesEvent("abc-x1y2z", "Client.VcpuLimitExceeded", first),
+		runInstancesEvent("abc-x1y2z", "VcpuLimitExceeded", last),
+		runInstancesEvent("abc-x1y2z", "InsufficientInstanceCapacity", first),
+		// Not a capacity error
+		runInstancesEvent("abc-x1y2z", "InvalidParameterValue", first),
+		// Another cluster of the account
+		runInstancesEvent("def-a1b2c", "InstanceLimitExceeded", first),
+		// Successful launch
+		runInstancesEvent("abc-x1y2z", "", first),
+	}
+
+	expected := []cloudCapacityError{
+		{Code: "VcpuLimitExceeded", Message: "failed at " + last.Format(time.RFC3339), Count: 2, Last: last},
+		{Code: "InsufficientInstanceCapacity", Message: "failed at " + first.Format(time.RFC3339), Count: 1, Last: first},
+	}
+	if capacityErrors := cloudCapacityErrorsOf(events, "abc-x1y2z"); !reflect.DeepEqual(capacityErrors, expected) {
+		t.Errorf("cloudCapacityErrorsOf() = %+v, want %+v", capacityErrors, expected)
+	}
+}
+
+func pendingPod(name string, scheduled corev1.ConditionStatus, message string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodPending,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: scheduled, Message: message}},
+		},
+	}
+}
+
+func TestSummarizePendingPods(t *testing.T) {
+	now := time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC)
+	pods := []corev1.Pod{
+		pendingPod("web-1", corev1.ConditionFalse, "0/6 nodes are available: 3 Insufficient cpu, 3 node(s) had untolerated taint {node-role.kubernetes.io/master: }."),
+		pendingPod("web-2", corev1.ConditionFalse, "0/6 nodes are available: 6 Insufficient cpu."),
+		pendingPod("pulling", corev1.ConditionTrue, ""),
+	}
+	events := []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "app", Name: "web-1"},
+			Message:        "pod didn't trigger scale-up: 1 Insufficient cpu",
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Hour)),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "app", Name: "web-1"},
+			Message:        "pod didn't trigger scale-up: 2 max node group size reached",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+	}
+
+	reasons := map[string]int{}
+	if unschedulable := summarizePendingPods(pods, events, reasons); unschedulable != 2 {
+		t.Errorf("summarizePendingPods() = %d, want 2", unschedulable)
+	}
+	expected := map[string]int{
+		"Insufficient cpu": 2,
+		"node(s) had untolerated taint {node-role.kubernetes.io/master: }": 1,
+		"autoscaler: max node group size reached":                          1,
+	}
+	if !reflect.DeepEqual(reasons, expected) {
+		t.Errorf("summarizePendingPods() reasons = %v, want %v", reasons, expected)
+	}
+}
@@ -0,0 +1,478 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	ccsCheckPass = "PASS"
+	ccsCheckWarn = "WARN"
+	ccsCheckFail = "FAIL"
+
+	ccsAdminUser             = "osdCcsAdmin"
+	defaultAccountRolePrefix = "ManagedOpenShift"
+	assumeRoleAction         = "sts:AssumeRole"
+	// supportRegion is where the support API of the commercial partition is served
+	supportRegion = "us-east-1"
+)
+
+// ccsAccountRoles are the account roles of STS clusters, by name suffix, with the service they trust. The
+// installer and support roles trust Red Hat's AWS account instead of a service.
+var ccsAccountRoles = []struct {
+	Suffix  string
+	Service string
+}{
+	{"Installer-Role", ""},
+	{"Support-Role", ""},
+	{"ControlPlane-Role", "ec2.amazonaws.com"},
+	{"Worker-Role", "ec2.amazonaws.com"},
+}
+
+// ccsServiceLinkedRoles are the service-linked roles the installation needs, by AWS service
+var ccsServiceLinkedRoles = map[string]string{
+	"elasticloadbalancing.amazonaws.com": "AWSServiceRoleForElasticLoadBalancing",
+}
+
+// ccsRequiredActions are a sample of the actions the installation performs, simulated to detect the service
+// control policies of the organization denying them
+var ccsRequiredActions = []string{
+	"ec2:CreateVpc",
+	"ec2:CreateSecurityGroup",
+	"ec2:CreateTags",
+	"ec2:RunInstances",
+	"elasticloadbalancing:CreateLoadBalancer",
+	"iam:PassRole",
+	"route53:CreateHostedZone",
+	"s3:CreateBucket",
+	"servicequotas:GetServiceQuota",
+}
+
+type ccsCheckOptions struct {
+	profile    string
+	region     string
+	rolePrefix string
+	nonSTS     bool
+}
+
+type ccsCheckResult struct {
+	Check       string
+	Status      string
+	Details     string
+	Remediation string
+}
+
+// ccsIAMClient is the part of the IAM API the CCS checks use
+type ccsIAMClient interface {
+	GetRole(context.Context, *iam.GetRoleInput, ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	GetUser(context.Context, *iam.GetUserInput, ...func(*iam.Options)) (*iam.GetUserOutput, error)
+	ListAttachedUserPolicies(context.Context, *iam.ListAttachedUserPoliciesInput, ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error)
+	SimulatePrincipalPolicy(context.Context, *iam.SimulatePrincipalPolicyInput, ...func(*iam.Options)) (*iam.SimulatePrincipalPolicyOutput, error)
+}
+
+func newCmdCcsCheck() *cobra.Command {
+	ops := &ccsCheckOptions{}
+	ccsCheckCmd := &cobra.Command{
+		Use:   "ccs-check",
+		Short: "Validate the prerequisites of a Customer Cloud Subscription AWS account before provisioning",
+		Long: `Validate an AWS account meets the prerequisites of a Customer Cloud Subscription (CCS) cluster before it's
+provisioned, with the credentials of the AWS profile:
+  - the account roles (STS) exist and trust Red Hat's AWS account or EC2, or with --non-sts the osdCcsAdmin
+    user exists with the AdministratorAccess policy
+  - no service control policy of the organization denies the actions of the installation in the region
+  - the account is subscribed to Enterprise support
+  - the service-linked roles the installation needs exist
+
+A checklist is printed, followed by the commands remediating the failed checks.`,
+		Example: `  # Check the account of an STS cluster to be installed in eu-west-1
+  osdctl cluster ccs-check --profile customer --region eu-west-1
+
+  # Check the account of a non-STS cluster
+  osdctl cluster ccs-check --profile customer --region eu-west-1 --non-sts`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	ccsCheckCmd.Flags().StringVarP(&ops.profile, "profile", "p", "", "AWS profile of the CCS account, the default credentials are used if empty")
+	ccsCheckCmd.Flags().StringVar(&ops.region, "region", "", "Region the cluster will be installed in, the region of the profile if empty")
+	ccsCheckCmd.Flags().StringVar(&ops.rolePrefix, "role-prefix", defaultAccountRolePrefix, "Prefix of the account roles")
+	ccsCheckCmd.Flags().BoolVar(&ops.nonSTS, "non-sts", false, "Check the osdCcsAdmin user of non-STS clusters instead of the account roles")
+
+	return ccsCheckCmd
+}
+
+func (o *ccsCheckOptions) run() error {
+	ctx := context.TODO()
+	var loadOptions []func(*config.LoadOptions) error
+	if o.profile != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(o.profile))
+	}
+	if o.region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(o.region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to load the AWS credentials: %w", err)
+	}
+	if cfg.Region == "" {
+		return fmt.Errorf("no region is configured for the profile, set --region")
+	}
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("failed to get the AWS account of the credentials: %w", err)
+	}
+	accountID := aws.ToString(identity.Account)
+	fmt.Printf("Checking the CCS prerequisites of AWS account %s in %s\n\n", accountID, cfg.Region)
+
+	iamClient := iam.NewFromConfig(cfg)
+	var results []ccsCheckResult
+	var installerARN string
+	if o.nonSTS {
+		var result ccsCheckResult
+		installerARN, result = checkCcsAdminUser(iamClient)
+		results = append(results, result)
+	} else {
+		for _, role := range ccsAccountRoles {
+			roleARN, result := checkAccountRole(iamClient, o.rolePrefix+"-"+role.Suffix, accountID, role.Service, o.rolePrefix)
+			if role.Suffix == "Installer-Role" {
+				installerARN = roleARN
+			}
+			results = append(results, result)
+		}
+	}
+	results = append(results, checkRequiredActions(iamClient, installerARN, cfg.Region)...)
+
+	levels, err := describeSeverityLevels(ctx, cfg)
+	results = append(results, evaluateSupportPlan(levels, err))
+
+	for service, roleName := range ccsServiceLinkedRoles {
+		results = append(results, checkServiceLinkedRole(iamClient, service, roleName))
+	}
+
+	failures := printCcsCheckResults(results)
+	if failures > 0 {
+		return fmt.Errorf("%d CCS prerequisites aren't met", failures)
+	}
+	return nil
+}
+
+// checkCcsAdminUser checks the osdCcsAdmin user exists with the AdministratorAccess policy, and returns its ARN
+func checkCcsAdminUser(client ccsIAMClient) (string, ccsCheckResult) {
+	check := fmt.Sprintf("User %s", ccsAdminUser)
+	remediation := fmt.Sprintf(`aws iam create-user --user-name %[1]s
+aws iam attach-user-policy --user-name %[1]s --policy-arn arn:aws:iam::aws:policy/AdministratorAccess`, ccsAdminUser)
+
+	user, err := client.GetUser(context.TODO(), &iam.GetUserInput{UserName: aws.String(ccsAdminUser)})
+	if err != nil {
+		var nse *iamtypes.NoSuchEntityException
+		if errors.As(err, &nse) {
+			return "", ccsCheckResult{check, ccsCheckFail, "the user doesn't exist", remediation}
+		}
+		return "", ccsCheckResult{check, ccsCheckWarn, fmt.Sprintf("failed to get the user: %v", err), ""}
+	}
+	userARN := aws.ToString(user.User.Arn)
+
+	policies, err := client.ListAttachedUserPolicies(context.TODO(), &iam.ListAttachedUserPoliciesInput{UserName: aws.String(ccsAdminUser)})
+	if err != nil {
+		return userARN, ccsCheckResult{check, ccsCheckWarn, fmt.Sprintf("failed to list the policies of the user: %v", err), ""}
+	}
+	for _, policy := range policies.AttachedPolicies {
+		if strings.HasSuffix(aws.ToString(policy.PolicyArn), ":iam::aws:policy/AdministratorAccess") {
+			return userARN, ccsCheckResult{check, ccsCheckPass, "has the AdministratorAccess policy", ""}
+		}
+	}
+	return userARN, ccsCheckResult{check, ccsCheckFail, "the AdministratorAccess policy isn't attached",
+		fmt.Sprintf("aws iam attach-user-policy --user-name %s --policy-arn arn:aws:iam::aws:policy/AdministratorAccess", ccsAdminUser)}
+}
+
+// checkAccountRole checks the account role exists and trusts the service, or another AWS account when service is
+// empty, and returns its ARN
+func checkAccountRole(client ccsIAMClient, roleName string, accountID string, service string, prefix string) (string, ccsCheckResult) {
+	check := fmt.Sprintf("Account role %s", roleName)
+	remediation := fmt.Sprintf("rosa create account-roles --prefix %s --mode auto", prefix)
+
+	role, err := client.GetRole(context.TODO(), &iam.GetRoleInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		var nse *iamtypes.NoSuchEntityException
+		if errors.As(err, &nse) {
+			return "", ccsCheckResult{check, ccsCheckFail, "the role doesn't exist", remediation}
+		}
+		return "", ccsCheckResult{check, ccsCheckWarn, fmt.Sprintf("failed to get the role: %v", err), ""}
+	}
+	roleARN := aws.ToString(role.Role.Arn)
+
+	// The policy document is URL encoded
+	document, err := url.QueryUnescape(aws.ToString(role.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return roleARN, ccsCheckResult{check, ccsCheckFail, fmt.Sprintf("failed to decode the trust policy: %v", err), ""}
+	}
+	status, details := evaluateAccountRoleTrust(document, accountID, service)
+	if status != ccsCheckPass {
+		return roleARN, ccsCheckResult{check, status, details, remediation}
+	}
+	return roleARN, ccsCheckResult{check, status, details, ""}
+}
+
+type accountRoleTrustPolicy struct {
+	Statement []struct {
+		Effect    string
+		Action    stringOrSlice
+		Principal struct {
+			AWS     stringOrSlice
+			Service stringOrSlice
+		}
+	}
+}
+
+// evaluateAccountRoleTrust checks the role can be assumed by the service, or by a principal of another AWS account
+// when service is empty
+func evaluateAccountRoleTrust(document string, accountID string, service string) (string, string) {
+	policy := accountRoleTrustPolicy{}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return ccsCheckFail, fmt.Sprintf("failed to parse the trust policy: %v", err)
+	}
+
+	var external []string
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" || !containsFold(statement.Action, assumeRoleAction) {
+			continue
+		}
+		if service != "" {
+			if containsFold(statement.Principal.Service, service) {
+				return ccsCheckPass, fmt.Sprintf("trusts %s", service)
+			}
+			continue
+		}
+		for _, principal := range statement.Principal.AWS {
+			if principalAccount(principal) != accountID {
+				external = append(external, principal)
+			}
+		}
+	}
+
+	switch {
+	case len(external) > 0:
+		return ccsCheckPass, fmt.Sprintf("trusts %s", strings.Join(external, ", "))
+	case service != "":
+		return ccsCheckFail, fmt.Sprintf("no statement allows %s from %s", assumeRoleAction, service)
+	default:
+		return ccsCheckFail, fmt.Sprintf("no statement allows %s from Red Hat's AWS account", assumeRoleAction)
+	}
+}
+
+// principalAccount returns the AWS account of a principal, given as an ARN or an account ID
+func principalAccount(principal string) string {
+	if parsed, err := arn.Parse(principal); err == nil {
+		return parsed.AccountID
+	}
+	return principal
+}
+
+// checkRequiredActions simulates the actions of the installation for the principal installing the cluster
+func checkRequiredActions(client ccsIAMClient, principalARN string, region string) []ccsCheckResult {
+	const check = "Service control policies"
+	if principalARN == "" {
+		return []ccsCheckResult{{check, ccsCheckWarn, "skipped, the principal installing the cluster is missing", ""}}
+	}
+
+	output, err := client.SimulatePrincipalPolicy(context.TODO(), &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalARN),
+		ActionNames:     ccsRequiredActions,
+		ContextEntries: []iamtypes.ContextEntry{{
+			ContextKeyName:   aws.String("aws:RequestedRegion"),
+			ContextKeyType:   iamtypes.ContextKeyTypeEnumString,
+			ContextKeyValues: []string{region},
+		}},
+	})
+	if err != nil {
+		return []ccsCheckResult{{check, ccsCheckWarn, fmt.Sprintf("failed to simulate the actions of the installation: %v", err), ""}}
+	}
+	return evaluateSimulation(output.EvaluationResults, principalARN)
+}
+
+// evaluateSimulation reports the actions denied by the service control policies of the organization, and the ones
+// the policies of the principal don't allow
+func evaluateSimulation(results []iamtypes.EvaluationResult, principalARN string) []ccsCheckResult {
+	var deniedByOrganization, notAllowed []string
+	for _, result := range results {
+		action := aws.ToString(result.EvalActionName)
+		switch {
+		case result.OrganizationsDecisionDetail != nil && !result.OrganizationsDecisionDetail.AllowedByOrganizations:
+			deniedByOrganization = append(deniedByOrganization, action)
+		case result.EvalDecision != iamtypes.PolicyEvaluationDecisionTypeAllowed:
+			notAllowed = append(notAllowed, action)
+		}
+	}
+
+	var checks []ccsCheckResult
+	if len(deniedByOrganization) > 0 {
+		checks = append(checks, ccsCheckResult{"Service control policies", ccsCheckFail,
+			fmt.Sprintf("denied: %s", strings.Join(deniedByOrganization, ", ")),
+			fmt.Sprintf("Exempt the account from the service control policies denying %s, with the administrators of the organization", strings.Join(deniedByOrganization, ", "))})
+	} else {
+		checks = append(checks, ccsCheckResult{"Service control policies", ccsCheckPass, "the installation's actions aren't denied", ""})
+	}
+	if len(notAllowed) > 0 {
+		checks = append(checks, ccsCheckResult{"Installer permissions", ccsCheckFail,
+			fmt.Sprintf("%s isn't allowed %s", principalARN, strings.Join(notAllowed, ", ")),
+			fmt.Sprintf("Attach the policies of the installer again to %s", principalARN)})
+	}
+	return checks
+}
+
+// supportAPIError is the error the support API returns, e.g. SubscriptionRequiredException for the accounts without
+// Business or Enterprise support
+type supportAPIError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+func (e *supportAPIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code(), e.Message)
+}
+
+// Code returns the type of the error without its namespace
+func (e *supportAPIError) Code() string {
+	return e.Type[strings.LastIndex(e.Type, "#")+1:]
+}
+
+// describeSeverityLevels returns the severity levels support cases of the account can be opened with. osdctl
+// doesn't depend on the SDK's support client, so the request is signed here.
+func describeSeverityLevels(ctx context.Context, cfg aws.Config) ([]string, error) {
+	credentials, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte("{}")
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("https://support.%s.amazonaws.com/", supportRegion), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", "AWSSupport_20130415.DescribeSeverityLevels")
+	payloadHash := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, credentials, request, hex.EncodeToString(payloadHash[:]), "support", supportRegion, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign the support API request: %w", err)
+	}
+
+	response, err := (&http.Client{Timeout: 30 * time.Second}).Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		apiErr := &supportAPIError{}
+		if err := json.Unmarshal(data, apiErr); err != nil || apiErr.Type == "" {
+			return nil, fmt.Errorf("the support API returned %s", response.Status)
+		}
+		return nil, apiErr
+	}
+
+	var output struct {
+		SeverityLevels []struct {
+			Code string `json:"code"`
+		} `json:"severityLevels"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse the severity levels: %w", err)
+	}
+	levels := make([]string, 0, len(output.SeverityLevels))
+	for _, level := range output.SeverityLevels {
+		levels = append(levels, level.Code)
+	}
+	return levels, nil
+}
+
+// evaluateSupportPlan infers the support plan from the severity levels: the critical one comes with Enterprise
+// support only, and accounts with the Basic or Developer plans can't use the support API
+func evaluateSupportPlan(levels []string, err error) ccsCheckResult {
+	const check = "Enterprise support"
+	const remediation = "Subscribe the account to Enterprise support: https://aws.amazon.com/premiumsupport/plans/enterprise/"
+	if err != nil {
+		var apiErr *supportAPIError
+		if errors.As(err, &apiErr) && apiErr.Code() == "SubscriptionRequiredException" {
+			return ccsCheckResult{check, ccsCheckFail, "the account has the Basic or Developer support plan", remediation}
+		}
+		return ccsCheckResult{check, ccsCheckWarn, fmt.Sprintf("failed to check the support plan: %v", err), ""}
+	}
+	if containsFold(levels, "critical") {
+		return ccsCheckResult{check, ccsCheckPass, "critical severity cases can be opened", ""}
+	}
+	return ccsCheckResult{check, ccsCheckWarn, "the account has Business support, critical severity cases need Enterprise support", remediation}
+}
+
+func checkServiceLinkedRole(client ccsIAMClient, service string, roleName string) ccsCheckResult {
+	check := fmt.Sprintf("Service-linked role %s", roleName)
+	if _, err := client.GetRole(context.TODO(), &iam.GetRoleInput{RoleName: aws.String(roleName)}); err != nil {
+		var nse *iamtypes.NoSuchEntityException
+		if errors.As(err, &nse) {
+			return ccsCheckResult{check, ccsCheckFail, "the role doesn't exist", fmt.Sprintf("aws iam create-service-linked-role --aws-service-name %s", service)}
+		}
+		return ccsCheckResult{check, ccsCheckWarn, fmt.Sprintf("failed to get the role: %v", err), ""}
+	}
+	return ccsCheckResult{check, ccsCheckPass, "exists", ""}
+}
+
+// printCcsCheckResults prints the checklist, then the remediation of the checks which didn't pass, and returns
+// the number of failed checks
+func printCcsCheckResults(results []ccsCheckResult) int {
+	failures := 0
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, result := range results {
+		if result.Status == ccsCheckFail {
+			failures++
+		}
+		table.AddRow([]string{result.Check, result.Status, result.Details})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the CCS checks: %v\n", err)
+	}
+
+	header := false
+	for _, result := range results {
+		if result.Status == ccsCheckPass || result.Remediation == "" {
+			continue
+		}
+		if !header {
+			fmt.Println("\nRemediation:")
+			header = true
+		}
+		fmt.Printf("  %s:\n", result.Check)
+		for _, line := range strings.Split(result.Remediation, "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	return failures
+}
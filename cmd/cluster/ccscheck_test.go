@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEvaluateAccountRoleTrust(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		service  string
+		expected string
+	}{
+		{
+			name:     "installer role trusting another account",
+			document: `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":["arn:aws:iam::222222222222:role/RH-Managed-OpenShift-Installer"]}}]}`,
+			expected: ccsCheckPass,
+		},
+		{
+			name:     "installer role trusting the account itself",
+			document: `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"111111111111"}}]}`,
+			expected: ccsCheckFail,
+		},
+		{
+			name:     "worker role trusting EC2",
+			document: `{"Statement":[{"Effect":"Allow","Action":["sts:AssumeRole"],"Principal":{"Service":"ec2.amazonaws.com"}}]}`,
+			service:  "ec2.amazonaws.com",
+			expected: ccsCheckPass,
+		},
+		{
+			name:     "worker role with a deny statement",
+			document: `{"Statement":[{"Effect":"Deny","Action":"sts:AssumeRole","Principal":{"Service":"ec2.amazonaws.com"}}]}`,
+			service:  "ec2.amazonaws.com",
+			expected: ccsCheckFail,
+		},
+		{
+			name:     "invalid document",
+			document: `{"Statement":`,
+			expected: ccsCheckFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, details := evaluateAccountRoleTrust(tt.document, "111111111111", tt.service); status != tt.expected {
+				t.Errorf("evaluateAccountRoleTrust() = %s (%s), want %s", status, details, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvaluateSupportPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		levels   []string
+		err      error
+		expected string
+	}{
+		{
+			name:     "enterprise",
+			levels:   []string{"low", "normal", "high", "urgent", "critical"},
+			expected: ccsCheckPass,
+		},
+		{
+			name:     "business",
+			levels:   []string{"low", "normal", "high", "urgent"},
+			expected: ccsCheckWarn,
+		},
+		{
+			name:     "basic",
+			err:      &supportAPIError{Type: "com.amazonaws.support#SubscriptionRequiredException", Message: "AWS Premium Support Subscription is required"},
+			expected: ccsCheckFail,
+		},
+		{
+			name:     "no access",
+			err:      errors.New("the support API returned 403 Forbidden"),
+			expected: ccsCheckWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := evaluateSupportPlan(tt.levels, tt.err); result.Status != tt.expected {
+				t.Errorf("evaluateSupportPlan() = %+v, want %s", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	encryptionCompliant    = "OK"
+	encryptionNonCompliant = "NON-COMPLIANT"
+	encryptionUnknown      = "UNKNOWN"
+
+	// noBucketEncryptionErrorCode is returned by S3 for buckets without default encryption
+	noBucketEncryptionErrorCode = "ServerSideEncryptionConfigurationNotFoundError"
+)
+
+type checkEncryptionOptions struct {
+	clusterID       string
+	awsProfile      string
+	output          string
+	noClusterAccess bool
+}
+
+// encryptionResult is the encryption state of a resource of the cluster
+type encryptionResult struct {
+	Resource string `json:"resource"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	Details  string `json:"details"`
+}
+
+func newCmdCheckEncryption() *cobra.Command {
+	ops := &checkEncryptionOptions{}
+	checkEncryptionCmd := &cobra.Command{
+		Use:   "check-encryption <cluster-id>",
+		Short: "Check the encryption at rest of an AWS cluster, for security audits",
+		Long: `Check the encryption at rest of an AWS cluster and report the non-compliant resources:
+  - etcd encryption, as requested in OCM and as configured on the cluster's API server
+  - the encryption of the EBS volumes of the cluster, with the KMS key of the cluster if it has one
+  - the default encryption of the S3 buckets of the image registry
+
+The command fails when a resource isn't compliant.`,
+		Example: `  # Report for a customer security audit
+  osdctl cluster check-encryption <cluster-id> -o json > encryption.json`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	checkEncryptionCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS profile")
+	checkEncryptionCmd.Flags().StringVarP(&ops.output, "output", "o", "text", "Output format, one of text or json")
+	checkEncryptionCmd.Flags().BoolVar(&ops.noClusterAccess, "no-cluster-access", false, "Don't read the etcd encryption configured on the cluster, only the one requested in OCM")
+
+	return checkEncryptionCmd
+}
+
+func (o *checkEncryptionOptions) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output format '%s', expected text or json", o.output)
+	}
+	return nil
+}
+
+func (o *checkEncryptionOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
+		return fmt.Errorf("this command is only available for AWS clusters")
+	}
+
+	results := []encryptionResult{checkRequestedEtcdEncryption(cluster)}
+	if !o.noClusterAccess && !cluster.Hypershift().Enabled() {
+		results = append(results, checkClusterEtcdEncryption(cluster.ID()))
+	}
+
+	awsClient, err := osdCloud.GenerateAWSClientForCluster(o.awsProfile, cluster.ID())
+	if err != nil {
+		return err
+	}
+	expectedKey := cluster.AWS().KMSKeyArn()
+	volumeResults, err := checkVolumeEncryption(awsClient, cluster.InfraID(), expectedKey)
+	if err != nil {
+		return err
+	}
+	results = append(results, volumeResults...)
+	bucketResults, err := checkRegistryBucketEncryption(awsClient, cluster.InfraID())
+	if err != nil {
+		return err
+	}
+	results = append(results, bucketResults...)
+
+	nonCompliant := 0
+	for _, result := range results {
+		if result.Status == encryptionNonCompliant {
+			nonCompliant++
+		}
+	}
+
+	if o.output == "json" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	} else {
+		printEncryptionResults(results)
+	}
+	if nonCompliant > 0 {
+		return fmt.Errorf("%d resources aren't encrypted as expected", nonCompliant)
+	}
+	return nil
+}
+
+// checkRequestedEtcdEncryption checks that the cluster was created with etcd encryption
+func checkRequestedEtcdEncryption(cluster *cmv1.Cluster) encryptionResult {
+	result := encryptionResult{Resource: "etcd", Type: "etcd (OCM)"}
+	if cluster.Hypershift().Enabled() {
+		if key := cluster.AWS().EtcdEncryption().KMSKeyARN(); key != "" {
+			result.Status = encryptionCompliant
+			result.Details = fmt.Sprintf("encrypted with %s", key)
+			return result
+		}
+	}
+	if cluster.EtcdEncryption() {
+		result.Status = encryptionCompliant
+		result.Details = "etcd encryption enabled"
+		return result
+	}
+	result.Status = encryptionNonCompliant
+	result.Details = "etcd encryption wasn't requested when the cluster was created"
+	return result
+}
+
+// checkClusterEtcdEncryption reads the encryption type of the API server of the cluster
+func checkClusterEtcdEncryption(clusterID string) encryptionResult {
+	result := encryptionResult{Resource: "apiserver/cluster", Type: "etcd (cluster)"}
+	kubeCli, _, _, err := common.GetKubeConfigAndClient(clusterID)
+	if err != nil {
+		result.Status = encryptionUnknown
+		result.Details = fmt.Sprintf("failed to access the cluster: %v", err)
+		return result
+	}
+
+	apiServer := &unstructured.Unstructured{}
+	apiServer.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "APIServer"})
+	if err := kubeCli.Get(context.TODO(), client.ObjectKey{Name: "cluster"}, apiServer); err != nil {
+		result.Status = encryptionUnknown
+		result.Details = fmt.Sprintf("failed to get the API server configuration: %v", err)
+		return result
+	}
+	encryptionType, _, _ := unstructured.NestedString(apiServer.Object, "spec", "encryption", "type")
+	result.Status, result.Details = evaluateEtcdEncryptionType(encryptionType)
+	return result
+}
+
+// evaluateEtcdEncryptionType tells whether the encryption type of the API server encrypts etcd
+func evaluateEtcdEncryptionType(encryptionType string) (string, string) {
+	switch encryptionType {
+	case "aescbc", "aesgcm":
+		return encryptionCompliant, fmt.Sprintf("encryption type %s", encryptionType)
+	case "", "identity":
+		return encryptionNonCompliant, "etcd isn't encrypted (identity)"
+	}
+	return encryptionUnknown, fmt.Sprintf("unknown encryption type %s", encryptionType)
+}
+
+// checkVolumeEncryption checks the EBS volumes tagged for the cluster
+func checkVolumeEncryption(awsClient awsprovider.Client, infraID string, expectedKey string) ([]encryptionResult, error) {
+	input := &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("tag-key"), Values: []string{"kubernetes.io/cluster/" + infraID}}},
+	}
+	var results []encryptionResult
+	for {
+		output, err := awsClient.DescribeVolumes(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe the volumes of the cluster: %w", err)
+		}
+		for _, volume := range output.Volumes {
+			status, details := evaluateVolumeEncryption(volume, expectedKey)
+			results = append(results, encryptionResult{Resource: aws.ToString(volume.VolumeId), Type: "EBS volume", Status: status, Details: details})
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	if len(results) == 0 {
+		results = append(results, encryptionResult{Resource: "-", Type: "EBS volume", Status: encryptionUnknown, Details: fmt.Sprintf("no volume tagged for %s", infraID)})
+	}
+	return results, nil
+}
+
+// evaluateVolumeEncryption checks that the volume is encrypted, with the expected KMS key if there is one
+func evaluateVolumeEncryption(volume ec2types.Volume, expectedKey string) (string, string) {
+	if !aws.ToBool(volume.Encrypted) {
+		return encryptionNonCompliant, "not encrypted"
+	}
+	key := aws.ToString(volume.KmsKeyId)
+	if expectedKey != "" && key != expectedKey {
+		return encryptionNonCompliant, fmt.Sprintf("encrypted with %s instead of %s", key, expectedKey)
+	}
+	return encryptionCompliant, fmt.Sprintf("encrypted with %s", key)
+}
+
+// checkRegistryBucketEncryption checks the default encryption of the image registry buckets of the cluster
+func checkRegistryBucketEncryption(awsClient awsprovider.Client, infraID string) ([]encryptionResult, error) {
+	buckets, err := awsClient.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the S3 buckets: %w", err)
+	}
+
+	var results []encryptionResult
+	for _, bucket := range buckets.Buckets {
+		name := aws.ToString(bucket.Name)
+		if !strings.HasPrefix(name, infraID+"-image-registry") {
+			continue
+		}
+		result := encryptionResult{Resource: name, Type: "S3 bucket"}
+		output, err := awsClient.GetBucketEncryption(&s3.GetBucketEncryptionInput{Bucket: bucket.Name})
+		var apiErr smithy.APIError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.ErrorCode() == noBucketEncryptionErrorCode:
+			result.Status, result.Details = encryptionNonCompliant, "no default encryption"
+		case err != nil:
+			result.Status, result.Details = encryptionUnknown, fmt.Sprintf("failed to get the encryption: %v", err)
+		default:
+			result.Status, result.Details = evaluateBucketEncryption(output.ServerSideEncryptionConfiguration)
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		results = append(results, encryptionResult{Resource: "-", Type: "S3 bucket", Status: encryptionUnknown, Details: fmt.Sprintf("no image registry bucket found for %s", infraID)})
+	}
+	return results, nil
+}
+
+// evaluateBucketEncryption checks that the default encryption of a bucket encrypts new objects
+func evaluateBucketEncryption(configuration *s3types.ServerSideEncryptionConfiguration) (string, string) {
+	if configuration == nil {
+		return encryptionNonCompliant, "no default encryption"
+	}
+	for _, rule := range configuration.Rules {
+		if rule.ApplyServerSideEncryptionByDefault == nil {
+			continue
+		}
+		details := string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+		if key := aws.ToString(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID); key != "" {
+			details = fmt.Sprintf("%s with %s", details, key)
+		}
+		return encryptionCompliant, details
+	}
+	return encryptionNonCompliant, "no default encryption"
+}
+
+func printEncryptionResults(results []encryptionResult) {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"TYPE", "RESOURCE", "STATUS", "DETAILS"})
+	for _, result := range results {
+		table.AddRow([]string{result.Type, result.Resource, result.Status, result.Details})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the encryption checks: %v\n", err)
+	}
+}
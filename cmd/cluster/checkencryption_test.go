@@ -0,0 +1,78 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestEvaluateVolumeEncryption(t *testing.T) {
+	const clusterKey = "arn:aws:kms:us-east-1:123456789012:key/cluster"
+	tests := []struct {
+		name        string
+		volume      ec2types.Volume
+		expectedKey string
+		want        string
+	}{
+		{
+			name:   "not encrypted",
+			volume: ec2types.Volume{Encrypted: aws.Bool(false)},
+			want:   encryptionNonCompliant,
+		},
+		{
+			name:   "encrypted with the default key",
+			volume: ec2types.Volume{Encrypted: aws.Bool(true), KmsKeyId: aws.String("arn:aws:kms:us-east-1:123456789012:key/default")},
+			want:   encryptionCompliant,
+		},
+		{
+			name:        "encrypted with the cluster key",
+			volume:      ec2types.Volume{Encrypted: aws.Bool(true), KmsKeyId: aws.String(clusterKey)},
+			expectedKey: clusterKey,
+			want:        encryptionCompliant,
+		},
+		{
+			name:        "encrypted with another key",
+			volume:      ec2types.Volume{Encrypted: aws.Bool(true), KmsKeyId: aws.String("arn:aws:kms:us-east-1:123456789012:key/default")},
+			expectedKey: clusterKey,
+			want:        encryptionNonCompliant,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, details := evaluateVolumeEncryption(tt.volume, tt.expectedKey); got != tt.want {
+				t.Errorf("evaluateVolumeEncryption() = %s (%s), want %s", got, details, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateBucketEncryption(t *testing.T) {
+	kms := &s3types.ServerSideEncryptionConfiguration{Rules: []s3types.ServerSideEncryptionRule{{
+		ApplyServerSideEncryptionByDefault: &s3types.ServerSideEncryptionByDefault{SSEAlgorithm: s3types.ServerSideEncryptionAwsKms, KMSMasterKeyID: aws.String("key")},
+	}}}
+	if status, details := evaluateBucketEncryption(kms); status != encryptionCompliant || details != "aws:kms with key" {
+		t.Errorf("evaluateBucketEncryption() = %s, %s", status, details)
+	}
+	if status, _ := evaluateBucketEncryption(&s3types.ServerSideEncryptionConfiguration{}); status != encryptionNonCompliant {
+		t.Errorf("evaluateBucketEncryption() = %s, want %s", status, encryptionNonCompliant)
+	}
+	if status, _ := evaluateBucketEncryption(nil); status != encryptionNonCompliant {
+		t.Errorf("evaluateBucketEncryption() = %s, want %s", status, encryptionNonCompliant)
+	}
+}
+
+func TestEvaluateEtcdEncryptionType(t *testing.T) {
+	for encryptionType, want := range map[string]string{
+		"aescbc":   encryptionCompliant,
+		"aesgcm":   encryptionCompliant,
+		"identity": encryptionNonCompliant,
+		"":         encryptionNonCompliant,
+		"kms":      encryptionUnknown,
+	} {
+		if got, _ := evaluateEtcdEncryptionType(encryptionType); got != want {
+			t.Errorf("evaluateEtcdEncryptionType(%q) = %s, want %s", encryptionType, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/openshift/osdctl/cmd/cluster/access"
 	"github.com/openshift/osdctl/cmd/cluster/dynatrace"
+	"github.com/openshift/osdctl/cmd/cluster/machinepool"
 	"github.com/openshift/osdctl/cmd/cluster/resize"
 	"github.com/openshift/osdctl/cmd/cluster/ssh"
 	"github.com/openshift/osdctl/cmd/cluster/support"
@@ -44,6 +45,32 @@ func NewCmdCluster(streams genericclioptions.IOStreams, client *k8s.LazyClient,
 	clusterCmd.AddCommand(newCmdCleanupLeakedEC2())
 	clusterCmd.AddCommand(newCmdDetachStuckVolume())
 	clusterCmd.AddCommand(ssh.NewCmdSSH())
+	clusterCmd.AddCommand(machinepool.NewCmdMachinePool())
+	clusterCmd.AddCommand(newCmdWaitFor())
+	clusterCmd.AddCommand(newCmdCveReport())
+	clusterCmd.AddCommand(newCmdOidcCheck())
+	clusterCmd.AddCommand(newCmdPostMortem())
+	clusterCmd.AddCommand(newCmdIdpCheck())
+	clusterCmd.AddCommand(newCmdEvents())
+	clusterCmd.AddCommand(newCmdAddons())
+	clusterCmd.AddCommand(newCmdCheckEncryption())
+	clusterCmd.AddCommand(newCmdStorage())
+	clusterCmd.AddCommand(newCmdRotateCredentials())
+	clusterCmd.AddCommand(newCmdDrainNode())
+	clusterCmd.AddCommand(newCmdGCPKeys())
+	clusterCmd.AddCommand(newCmdPauseSyncSet())
+	clusterCmd.AddCommand(newCmdSyncSets())
+	clusterCmd.AddCommand(newCmdHypershift())
+	clusterCmd.AddCommand(newCmdAccessRequest())
+	clusterCmd.AddCommand(newCmdIngressCheck())
+	clusterCmd.AddCommand(newCmdValidateDNS())
+	clusterCmd.AddCommand(newCmdDiff())
+	clusterCmd.AddCommand(newCmdModificationCheck())
+	clusterCmd.AddCommand(newCmdAutoscalerCheck())
+	clusterCmd.AddCommand(newCmdTags())
+	clusterCmd.AddCommand(newCmdCcsCheck())
+	clusterCmd.AddCommand(newCmdQuota())
+	clusterCmd.AddCommand(newCmdSecurityFindings())
 	return clusterCmd
 }
 
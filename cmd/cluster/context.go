@@ -1,14 +1,18 @@
 package cluster
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
@@ -19,13 +23,24 @@ import (
 	"github.com/andygrunwald/go-jira"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/osdctl/cmd/cluster/dynatrace"
+	"github.com/openshift/osdctl/pkg/interrupt"
+	"github.com/openshift/osdctl/pkg/links"
 	"github.com/openshift/osdctl/pkg/osdCloud"
 	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/pager"
 	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/cloudstatus"
 	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/provider/supportcase"
+	"github.com/openshift/osdctl/pkg/provider/telemetry"
+	"github.com/openshift/osdctl/pkg/rawdump"
+	"github.com/openshift/osdctl/pkg/redact"
+	"github.com/openshift/osdctl/pkg/tracing"
 	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -45,6 +60,8 @@ const (
 
 type contextOptions struct {
 	cluster *cmv1.Cluster
+	// ctx is canceled when the command is interrupted, the sections collected so far are printed
+	ctx context.Context
 
 	output            string
 	verbose           bool
@@ -55,71 +72,161 @@ type contextOptions struct {
 	organizationID    string
 	days              int
 	pages             int
+	pdLimit           int
+	jiraLimit         int
+	jiraAll           bool
 	oauthtoken        string
 	usertoken         string
 	infraID           string
 	awsProfile        string
 	jiratoken         string
 	team_ids          []string
+	redact            bool
+	redactTerms       []string
+	pager             pager.Options
+	preset            string
+	alertTableOptions printer.TableOptions
+	wide              bool
+	noGroup           bool
+	browser           []string
+	sectionNames      []string
+	offline           bool
+	dataPath          string
+	rawDir            string
+	traceEndpoint     string
+	traceFile         string
+
+	// Layout of the long output
+	sections         []contextSection
+	template         *template.Template
+	disabledSections map[string]bool
 }
 
+// contextCounts are the numbers of items the short output displays, counted without fetching the items
+type contextCounts struct {
+	ServiceLogs         int `json:"service_logs"`
+	InternalServiceLogs int `json:"internal_service_logs"`
+	JiraIssues          int `json:"jira_issues"`
+}
+
+// contextData is the context of a cluster, its JSON field names are part of the `-o json` output
+// and must not change. Slices are sorted by sortContextData so successive runs can be diffed.
 type contextData struct {
 	// Cluster info
-	ClusterName    string
-	ClusterVersion string
-	ClusterID      string
+	ClusterName    string `json:"cluster_name"`
+	ClusterVersion string `json:"cluster_version"`
+	ClusterID      string `json:"cluster_id"`
 
 	// Current OCM environment (e.g., "production" or "stage")
-	OCMEnv string
+	OCMEnv string `json:"ocm_env"`
 
 	// Dynatrace Environment URL
-	DyntraceEnvURL string
+	DyntraceEnvURL string `json:"dynatrace_env_url"`
+
+	// limited Support Status, oldest first
+	LimitedSupportReasons []*cmv1.LimitedSupportReason `json:"limited_support_reasons"`
+	// Service Logs, newest first
+	ServiceLogs []*v1.LogEntry `json:"service_logs"`
+	// Lifecycle events logged by OCM, newest first (long output only)
+	ClusterEvents []*v1.LogEntry `json:"cluster_events"`
+	// Installed add-ons, by ID (long output only)
+	Addons []*cmv1.AddOnInstallation `json:"addons"`
+	// Hive ClusterSync and its failing SyncSets, when the hive shard is reachable (long output only)
+	ClusterSync *clusterSyncSummary `json:"clustersync,omitempty"`
+	// Numbers of service logs and Jira cards, counted instead of fetched (short output only)
+	Counts *contextCounts `json:"counts,omitempty"`
+
+	// Jira Cards, by key
+	JiraIssues        []jira.Issue `json:"jira_issues"`
+	SupportExceptions []jira.Issue `json:"support_exceptions"`
+	// Number of cards matching, when more than the ones fetched
+	JiraIssuesTotal        int `json:"jira_issues_total,omitempty"`
+	SupportExceptionsTotal int `json:"support_exceptions_total,omitempty"`
+
+	// Open Customer Portal support cases of the cluster, by case number
+	SupportCases []supportcase.Case `json:"support_cases"`
+
+	// PD Alerts, high urgency first
+	PdServiceIDs     []string                                          `json:"pd_service_ids"`
+	PdAlerts         map[string][]pd.Incident                          `json:"pd_alerts"`
+	HistoricalAlerts map[string][]*pagerduty.IncidentOccurrenceTracker `json:"historical_alerts"`
 
-	// limited Support Status
-	LimitedSupportReasons []*cmv1.LimitedSupportReason
-	// Service Logs
-	ServiceLogs []*v1.LogEntry
+	// CloudTrail Logs
+	CloudtrailEvents []*types.Event `json:"cloudtrail_events"`
 
-	// Jira Cards
-	JiraIssues        []jira.Issue
-	SupportExceptions []jira.Issue
+	// Active GuardDuty findings and failed Security Hub checks of the AWS account
+	SecurityFindings *securityFindings `json:"security_findings,omitempty"`
 
-	// PD Alerts
-	pdServiceID      []string
-	PdAlerts         map[string][]pd.Incident
-	HistoricalAlerts map[string][]*pagerduty.IncidentOccurrenceTracker
+	// Cloud provider status events for the cluster's region
+	CloudProviderRegion string               `json:"cloud_provider_region"`
+	CloudProviderEvents []*cloudstatus.Event `json:"cloud_provider_events"`
 
-	// CloudTrail Logs
-	CloudtrailEvents []*types.Event
+	// Error budgets of the availability SLOs, from the telemetry
+	SLOs []telemetry.SLOStatus `json:"slos"`
 
 	// OCM Cluster description
-	Description string
+	Description string `json:"description"`
+
+	// When the data of each source was fetched, by the JSON field of the data
+	FetchedAt map[string]time.Time `json:"fetched_at"`
+	// Sections not collected because their integration isn't configured, with a hint, by section name
+	Skipped map[string]string `json:"skipped,omitempty"`
+
+	// Links printed by the sections, to open them with --browser
+	linkRegistry *links.Registry
 }
 
 // newCmdContext implements the context command to show the current context of a cluster
 func newCmdContext() *cobra.Command {
 	ops := newContextOptions()
 	contextCmd := &cobra.Command{
-		Use:               "context",
-		Short:             "Shows the context of a specified cluster",
-		Args:              cobra.ExactArgs(1),
+		Use:   "context",
+		Short: "Shows the context of a specified cluster",
+		Long: `Shows the context of a specified cluster: its limited support reasons, service logs, Jira issues,
+PagerDuty alerts, cloud provider status events and more.
+
+The cloud provider status events are read from the public status pages, the AWS status RSS feeds of the
+services a cluster depends on and the GCP incidents feed, rather than from the AWS Health API which needs
+credentials for the customer's account.`,
+		Args:              cobra.MaximumNArgs(1),
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
+			ops.ctx = cmd.Context()
 			cmdutil.CheckErr(ops.complete(cmd, args))
 			cmdutil.CheckErr(ops.run())
 		},
 	}
+	interrupt.MarkCancellable(contextCmd)
 
 	contextCmd.Flags().StringVarP(&ops.output, "output", "o", "long", "Valid formats are ['long', 'short', 'json']. Output is set to 'long' by default")
 	contextCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "Cluster ID")
+	contextCmd.Flags().StringVar(&ops.externalClusterID, utils.ExternalClusterIDFlag, "", "Look the cluster up strictly by its external UUID instead of a positional identifier")
 	contextCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile")
 	contextCmd.Flags().BoolVarP(&ops.verbose, "verbose", "", false, "Verbose output")
 	contextCmd.Flags().BoolVar(&ops.full, "full", false, "Run full suite of checks.")
 	contextCmd.Flags().IntVarP(&ops.days, "days", "d", 30, "Command will display X days of Error SLs sent to the cluster. Days is set to 30 by default")
+	contextCmd.Flags().BoolVar(&ops.noGroup, "no-group", false, "List every service log instead of grouping the service logs sent from the same template")
 	contextCmd.Flags().IntVar(&ops.pages, "pages", 40, "Command will display X pages of Cloud Trail logs for the cluster. Pages is set to 40 by default")
 	contextCmd.Flags().StringVar(&ops.oauthtoken, "oauthtoken", "", fmt.Sprintf("Pass in PD oauthtoken directly. If not passed in, by default will read `pd_oauth_token` from ~/.config/%s.\nPD OAuth tokens can be generated by visiting %s", osdctlConfig.ConfigFileName, PagerDutyTokenRegistrationUrl))
 	contextCmd.Flags().StringVar(&ops.usertoken, "usertoken", "", fmt.Sprintf("Pass in PD usertoken directly. If not passed in, by default will read `pd_user_token` from ~/config/%s", osdctlConfig.ConfigFileName))
 	contextCmd.Flags().StringVar(&ops.jiratoken, "jiratoken", "", fmt.Sprintf("Pass in the Jira access token directly. If not passed in, by default will read `jira_token` from ~/.config/%s.\nJira access tokens can be registered by visiting %s/%s", osdctlConfig.ConfigFileName, JiraBaseURL, JiraTokenRegistrationPath))
+	contextCmd.Flags().BoolVar(&ops.redact, redact.RedactFlagName, false, redact.RedactFlagUsage)
+	pager.AddFlags(contextCmd.Flags(), &ops.pager)
+	printer.AddTableFlags(contextCmd.Flags(), &ops.alertTableOptions)
+	contextCmd.Flags().Lookup(printer.ColumnsFlagName).Usage += " (PagerDuty alerts table)"
+	contextCmd.Flags().Lookup(printer.SortByFlagName).Usage += " (PagerDuty alerts table)"
+	contextCmd.Flags().BoolVar(&ops.wide, "wide", false, "Show the status and the URL of the incidents in the PagerDuty alerts table")
+	contextCmd.Flags().StringVar(&ops.preset, contextPresetFlagName, "", fmt.Sprintf("Apply a named set of flags, built-in presets are %v. More presets can be defined as `%s` in ~/.config/%s. Flags passed explicitly take precedence over the preset", contextPresetNames(), contextPresetsConfigKey, osdctlConfig.ConfigFileName))
+	contextCmd.Flags().StringSliceVar(&ops.browser, "browser", []string{}, fmt.Sprintf("Open the links of the given kinds in the default browser, kinds are %v. Use '%s' to open every link or '%s' to pick them", links.Kinds(), links.SelectAll, links.SelectInteractive))
+	contextCmd.Flags().StringSliceVar(&ops.sectionNames, contextSectionsFlagName, []string{}, fmt.Sprintf("Sections of the long output to print, in order, among %v. Can also be defined as `%s` in ~/.config/%s, along with a Go template of the whole output as `%s`", contextSectionNames(), contextSectionsConfigKey, osdctlConfig.ConfigFileName, contextTemplateConfigKey))
+	contextCmd.Flags().BoolVar(&ops.offline, offlineFlagName, false, "Print the context from previously captured data instead of querying the APIs")
+	contextCmd.Flags().StringVar(&ops.dataPath, offlineDataFlagName, "", fmt.Sprintf("With --%s, the '-o json' output of a context, or a directory holding it as %s and/or one <field>.json file per collector (e.g. service_logs.json)", offlineFlagName, offlineContextFile))
+	contextCmd.Flags().StringVar(&ops.rawDir, rawdump.FlagName, "", rawdump.FlagUsage+fmt.Sprintf(", the data of the context is written as %s to be read back with --%s", offlineContextFile, offlineFlagName))
+	contextCmd.Flags().IntVar(&ops.pdLimit, "pd-limit", pagerduty.DefaultIncidentLimit, "Maximum number of PagerDuty incidents listed per service")
+	contextCmd.Flags().IntVar(&ops.jiraLimit, "jira-limit", utils.DefaultJiraIssueLimit, "Maximum number of Jira issues and support exceptions listed, the total number of matching issues is still shown")
+	contextCmd.Flags().BoolVar(&ops.jiraAll, "jira-all", false, "List all the Jira issues and support exceptions, however many there are")
+	contextCmd.Flags().StringVar(&ops.traceEndpoint, tracing.EndpointFlagName, "", fmt.Sprintf("OTLP/HTTP endpoint, e.g. http://localhost:4318, receiving the timing of the collectors as an OpenTelemetry trace. Can also be defined as `%s` in ~/.config/%s", tracing.EndpointConfigKey, osdctlConfig.ConfigFileName))
+	contextCmd.Flags().StringVar(&ops.traceFile, tracing.FileFlagName, "", "File to write the timing of the collectors to, as an OpenTelemetry trace in the OTLP/JSON format")
 	contextCmd.Flags().StringArrayVarP(&ops.team_ids, "team-ids", "t", []string{}, fmt.Sprintf("Pass in PD team IDs directly to filter the PD Alerts by team. Can also be defined as `team_ids` in ~/.config/%s\nWill show all PD Alerts for all PD service IDs if none is defined", osdctlConfig.ConfigFileName))
 	return contextCmd
 }
@@ -129,14 +236,49 @@ func newContextOptions() *contextOptions {
 }
 
 func (o *contextOptions) complete(cmd *cobra.Command, args []string) error {
-	if len(args) != 1 {
-		return cmdutil.UsageErrorf(cmd, "Provide exactly one cluster ID")
+	if o.dataPath != "" && !o.offline {
+		return cmdutil.UsageErrorf(cmd, "--%s is only used with --%s", offlineDataFlagName, offlineFlagName)
+	}
+	if o.rawDir != "" && o.offline {
+		return cmdutil.UsageErrorf(cmd, "--%s can't be used with --%s", rawdump.FlagName, offlineFlagName)
+	}
+	if !o.offline && (len(args) == 1) == (o.externalClusterID != "") {
+		return cmdutil.UsageErrorf(cmd, "Provide exactly one cluster ID or --%s", utils.ExternalClusterIDFlag)
+	}
+
+	if o.preset != "" {
+		if err := applyContextPreset(cmd, o.preset); err != nil {
+			return err
+		}
 	}
 
 	if o.days < 1 {
 		return fmt.Errorf("cannot have a days value lower than 1")
 	}
 
+	if o.pdLimit < 1 {
+		return fmt.Errorf("cannot have a pd-limit value lower than 1")
+	}
+
+	if err := links.ValidateSelection(o.browser); err != nil {
+		return err
+	}
+
+	if err := o.completeLayout(cmd); err != nil {
+		return err
+	}
+	o.pager.Complete(cmd.Flags())
+
+	if o.offline {
+		return o.completeOffline(cmd, args)
+	}
+	// The clients wrap their transports when they are created
+	if o.rawDir != "" {
+		if err := rawdump.Enable(o.rawDir, contextCollectorOf); err != nil {
+			return err
+		}
+	}
+
 	// Create OCM client to talk to cluster API
 	defer utils.StartDelayTracker(o.verbose, "OCM Clusters").End()
 	ocmClient, err := utils.CreateConnection()
@@ -149,16 +291,41 @@ func (o *contextOptions) complete(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	clusters := utils.GetClusters(ocmClient, args)
-	if len(clusters) != 1 {
-		return fmt.Errorf("unexpected number of clusters matched input. Expected 1 got %d", len(clusters))
+	if o.externalClusterID != "" {
+		o.cluster, err = utils.GetClusterByExternalID(ocmClient, o.externalClusterID)
+		if err != nil {
+			return err
+		}
+	} else {
+		clusters := utils.GetClusters(ocmClient, args)
+		if len(clusters) > 1 {
+			return utils.NewAmbiguousClusterError(args[0], clusters)
+		}
+		if len(clusters) != 1 {
+			return fmt.Errorf("unexpected number of clusters matched input. Expected 1 got %d", len(clusters))
+		}
+		o.cluster = clusters[0]
+	}
+
+	o.setCluster(ocmClient, o.cluster)
+
+	if !cmd.Flags().Changed(redact.RedactFlagName) {
+		o.redact = viper.GetBool(redact.RedactConfigKey)
+	}
+	if o.redact {
+		o.redactTerms = utils.GetCustomerTerms(ocmClient, o.clusterID, o.organizationID)
 	}
 
-	o.cluster = clusters[0]
-	o.clusterID = o.cluster.ID()
-	o.externalClusterID = o.cluster.ExternalID()
-	o.baseDomain = o.cluster.DNS().BaseDomain()
-	o.infraID = o.cluster.InfraID()
+	return nil
+}
+
+// setCluster fills in the cluster related options and the credentials not passed as flags
+func (o *contextOptions) setCluster(ocmClient *sdk.Connection, cluster *cmv1.Cluster) {
+	o.cluster = cluster
+	o.clusterID = cluster.ID()
+	o.externalClusterID = cluster.ExternalID()
+	o.baseDomain = cluster.DNS().BaseDomain()
+	o.infraID = cluster.InfraID()
 
 	if o.usertoken == "" {
 		o.usertoken = viper.GetString(pagerduty.PagerDutyUserTokenConfigKey)
@@ -168,15 +335,75 @@ func (o *contextOptions) complete(cmd *cobra.Command, args []string) error {
 		o.oauthtoken = viper.GetString(pagerduty.PagerDutyOauthTokenConfigKey)
 	}
 
-	orgID, err := utils.GetOrgfromClusterID(ocmClient, *o.cluster)
+	orgID, err := utils.GetOrgfromClusterID(ocmClient, *cluster)
 	if err != nil {
-		fmt.Printf("Failed to get Org ID for cluster ID %s - err: %q", o.clusterID, err)
+		fmt.Fprintf(os.Stderr, "Failed to get Org ID for cluster ID %s - err: %q", o.clusterID, err)
 		o.organizationID = ""
 	} else {
 		o.organizationID = orgID
 	}
+}
 
-	return nil
+// GetContextData collects the same data as 'osdctl cluster context -o json' for the given cluster,
+// reading the PagerDuty and Jira credentials from the osdctl config
+func GetContextData(clusterID string, days int) (*contextData, []error) {
+	o, err := newContextOptionsForCluster(clusterID, jsonOutputConfigValue, days)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return o.generateContextData()
+}
+
+// ContextSummary is the short context of a cluster, as shown by 'osdctl cluster context -o short'
+type ContextSummary struct {
+	Summary string
+	// Links to external resources about the cluster, by name
+	Links  map[string]string
+	Errors []error
+}
+
+// GetContextSummary collects the short context of the given cluster
+func GetContextSummary(clusterID string, days int) (*ContextSummary, error) {
+	o, err := newContextOptionsForCluster(clusterID, shortOutputConfigValue, days)
+	if err != nil {
+		return nil, err
+	}
+
+	data, dataErrors := o.generateContextData()
+	if data == nil {
+		return nil, fmt.Errorf("failed to query cluster info: %v", dataErrors)
+	}
+
+	var sb strings.Builder
+	o.writeShortOutput(&sb, data)
+	return &ContextSummary{
+		Summary: sb.String(),
+		Links:   o.otherLinks(data),
+		Errors:  dataErrors,
+	}, nil
+}
+
+func newContextOptionsForCluster(clusterID string, output string, days int) (*contextOptions, error) {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &contextOptions{
+		output: output,
+		days:   days,
+	}
+	o.setCluster(ocmClient, cluster)
+	if err := o.completeDisabledSections(); err != nil {
+		return nil, err
+	}
+	return o, nil
 }
 
 func (o *contextOptions) run() error {
@@ -192,11 +419,28 @@ func (o *contextOptions) run() error {
 		return fmt.Errorf("unknown Output Format: %s", o.output)
 	}
 
-	currentData, dataErrors := o.generateContextData()
+	var currentData *contextData
+	var dataErrors []error
+	if o.offline {
+		data, err := loadOfflineContextData(o.dataPath)
+		if err != nil {
+			return err
+		}
+		currentData = data
+	} else {
+		trace := o.startTrace()
+		currentData, dataErrors = o.generateContextData()
+		o.exportTrace(trace, dataErrors)
+	}
 	if currentData == nil {
 		fmt.Fprintf(os.Stderr, "Failed to query cluster info: %+v", dataErrors)
 		os.Exit(1)
 	}
+	if rawdump.Enabled() {
+		if err := saveRawContext(currentData); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save the context to --%s: %v\n", rawdump.FlagName, err)
+		}
+	}
 
 	if len(dataErrors) > 0 {
 		fmt.Fprintf(os.Stderr, "Encountered Errors during data collection. Displayed data may be incomplete: \n")
@@ -205,45 +449,69 @@ func (o *contextOptions) run() error {
 		}
 	}
 
+	// The pager is started first so the redacted output is paged, and quit before the links are opened
+	stopPager, err := o.pager.Start()
+	if err != nil {
+		return err
+	}
+	restore := func() {}
+	if o.redact {
+		if restore, err = redact.New(o.redactTerms...).Stdout(); err != nil {
+			stopPager()
+			return err
+		}
+	}
 	printFunc(currentData)
+	restore()
+	stopPager()
+
+	if o.interrupted() {
+		return fmt.Errorf("interrupted, the sections still being collected were left out")
+	}
+	if len(o.browser) > 0 {
+		o.openLinks(currentData)
+	}
 
 	return nil
 }
 
-func (o *contextOptions) printLongOutput(data *contextData) {
-	data.printClusterHeader()
-
-	fmt.Println(strings.TrimSpace(data.Description))
-	fmt.Println()
-	utils.PrintLimitedSupportReasons(data.LimitedSupportReasons)
-	fmt.Println()
-	printJIRASupportExceptions(data.SupportExceptions)
-	fmt.Println()
-	utils.PrintServiceLogs(data.ServiceLogs, o.verbose, o.days)
-	fmt.Println()
-	utils.PrintJiraIssues(data.JiraIssues)
-	fmt.Println()
-	utils.PrintPDAlerts(data.PdAlerts, data.pdServiceID)
-	fmt.Println()
-
-	if o.full {
-		printHistoricalPDAlertSummary(data.HistoricalAlerts, data.pdServiceID, o.days)
-		fmt.Println()
+// interrupted returns true if the command was interrupted while collecting the context
+func (o *contextOptions) interrupted() bool {
+	return o.ctx != nil && o.ctx.Err() != nil
+}
 
-		printCloudTrailLogs(data.CloudtrailEvents)
-		fmt.Println()
+// openLinks opens the selected links in the default browser
+func (o *contextOptions) openLinks(data *contextData) {
+	selected, err := data.linkRegistry.Select(o.browser, os.Stdin, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to select the links to open: %v\n", err)
+		return
 	}
+	if len(selected) == 0 {
+		fmt.Fprintf(os.Stderr, "No link of kinds %v to open\n", o.browser)
+		return
+	}
+	for _, err := range links.Open(selected, browser.OpenURL) {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
 
-	// Print other helpful links
-	o.printOtherLinks(data)
-	fmt.Println()
-
-	// Print Dynatrace URL
-	printDynatraceEnvURL(data)
+func (o *contextOptions) printLongOutput(data *contextData) {
+	if o.template != nil {
+		if err := o.renderContextTemplate(os.Stdout, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to render %s: %v\n", contextTemplateConfigKey, err)
+		}
+		return
+	}
+	o.printSections(data)
 }
 
 func (o *contextOptions) printShortOutput(data *contextData) {
-	data.printClusterHeader()
+	o.writeShortOutput(os.Stdout, data)
+}
+
+func (o *contextOptions) writeShortOutput(w io.Writer, data *contextData) {
+	data.writeClusterHeader(w)
 
 	highAlertCount := 0
 	lowAlertCount := 0
@@ -268,14 +536,23 @@ func (o *contextOptions) printShortOutput(data *contextData) {
 		historicalAlertsString = fmt.Sprintf("%d", historicalAlertsCount)
 	}
 
+	numServiceLogs := len(data.ServiceLogs)
 	var numInternalServiceLogs int
 	for _, serviceLog := range data.ServiceLogs {
 		if serviceLog.InternalOnly() {
 			numInternalServiceLogs++
 		}
 	}
+	jiraIssuesString := fmt.Sprintf("%d", len(data.JiraIssues))
+	if data.JiraIssuesTotal > len(data.JiraIssues) {
+		jiraIssuesString = fmt.Sprintf("%d of %d", len(data.JiraIssues), data.JiraIssuesTotal)
+	}
+	if data.Counts != nil {
+		numServiceLogs, numInternalServiceLogs = data.Counts.ServiceLogs, data.Counts.InternalServiceLogs
+		jiraIssuesString = fmt.Sprintf("%d", data.Counts.JiraIssues)
+	}
 
-	table := printer.NewTablePrinter(os.Stdout, 20, 1, 2, ' ')
+	table := printer.NewTablePrinter(w, 20, 1, 2, ' ')
 	table.AddRow([]string{
 		"Version",
 		"Supported?",
@@ -284,18 +561,28 @@ func (o *contextOptions) printShortOutput(data *contextData) {
 		"Current Alerts",
 		fmt.Sprintf("Historical Alerts (last %d d)", o.days),
 	})
+	if o.skipsSection(data, "jira-issues") {
+		jiraIssuesString = "N/A"
+	}
+	alertsString := fmt.Sprintf("H: %d | L: %d", highAlertCount, lowAlertCount)
+	if o.skipsSection(data, "pagerduty-alerts") {
+		alertsString = "N/A"
+	}
 	table.AddRow([]string{
 		data.ClusterVersion,
 		fmt.Sprintf("%t", len(data.LimitedSupportReasons) == 0),
-		fmt.Sprintf("%d (%d internal)", len(data.ServiceLogs), numInternalServiceLogs),
-		fmt.Sprintf("%d", len(data.JiraIssues)),
-		fmt.Sprintf("H: %d | L: %d", highAlertCount, lowAlertCount),
+		fmt.Sprintf("%d (%d internal)", numServiceLogs, numInternalServiceLogs),
+		jiraIssuesString,
+		alertsString,
 		historicalAlertsString,
 	})
 
 	if err := table.Flush(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error printing Short Output: %v\n", err)
 	}
+	for _, hint := range skippedHints(data.Skipped) {
+		fmt.Fprintf(w, "Skipped: %s\n", hint)
+	}
 }
 
 func (o *contextOptions) printJsonOutput(data *contextData) {
@@ -316,10 +603,17 @@ func (o *contextOptions) printJsonOutput(data *contextData) {
 // information. The second return value will *never* be nil, but instead have a
 // length of 0 if no errors occurred
 func (o *contextOptions) generateContextData() (*contextData, []error) {
-	data := &contextData{}
+	data := &contextData{Skipped: o.skippedContextSections()}
 	errors := []error{}
 
 	wg := sync.WaitGroup{}
+	// The collectors run concurrently, they write the data and record their errors under dataMutex
+	var dataMutex sync.Mutex
+	addError := func(err error) {
+		dataMutex.Lock()
+		defer dataMutex.Unlock()
+		errors = append(errors, err)
+	}
 
 	// For PD query dependencies
 	pdwg := sync.WaitGroup{}
@@ -329,10 +623,13 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 		WithOauthToken(o.oauthtoken).
 		WithBaseDomain(o.baseDomain).
 		WithTeamIdList(viper.GetStringSlice(pagerduty.PagerDutyTeamIDsKey)).
+		WithIncidentLimit(o.pdLimit).
 		Init()
 	if err != nil {
 		skipPagerDutyCollection = true
-		errors = append(errors, fmt.Errorf("skipping PagerDuty context collection: %v", err))
+		if !o.skipsSection(data, "pagerduty-alerts") {
+			errors = append(errors, fmt.Errorf("skipping PagerDuty context collection: %v", err))
+		}
 	}
 
 	ocmClient, err := utils.CreateConnection()
@@ -356,43 +653,100 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 	data.ClusterVersion = o.cluster.Version().RawID()
 	data.OCMEnv = utils.GetCurrentOCMEnv(ocmClient)
 
+	data.linkRegistry = links.NewRegistry()
+	data.linkRegistry.Add(links.KindOHSS, "OHSS Cards", fmt.Sprintf("%s/issues/?jql=project%%20%%3D%%20%%22%s%%22%%20and%%20(%%22Cluster%%20ID%%22%%20~%%20%%20%%22%s%%22%%20OR%%20%%22Cluster%%20ID%%22%%20~%%20%%22%s%%22)", utils.GetJiraBaseURL(), url.QueryEscape(utils.JiraProject(utils.JiraOHSSProject)), o.clusterID, o.externalClusterID))
+	data.linkRegistry.Add(links.KindCCX, "CCX dashboard", fmt.Sprintf("https://kraken.psi.redhat.com/clusters/%s", o.externalClusterID))
+	data.linkRegistry.Add(links.KindSplunk, "Splunk Audit Logs", o.buildSplunkURL(data))
+
 	GetLimitedSupport := func() {
 		defer wg.Done()
 		defer utils.StartDelayTracker(o.verbose, "Limited Support reasons").End()
 		limitedSupportReasons, err := utils.GetClusterLimitedSupportReasons(ocmClient, o.clusterID)
 		if err != nil {
-			errors = append(errors, fmt.Errorf("error while getting Limited Support status reasons: %v", err))
-		} else {
-			data.LimitedSupportReasons = append(data.LimitedSupportReasons, limitedSupportReasons...)
+			addError(fmt.Errorf("error while getting Limited Support status reasons: %v", err))
+			return
 		}
+		dataMutex.Lock()
+		data.LimitedSupportReasons = append(data.LimitedSupportReasons, limitedSupportReasons...)
+		dataMutex.Unlock()
+		data.markFetched("limited_support_reasons")
 	}
 
 	GetServiceLogs := func() {
 		defer wg.Done()
 		defer utils.StartDelayTracker(o.verbose, "Service Logs").End()
 		timeToCheckSvcLogs := time.Now().AddDate(0, 0, -o.days)
-		data.ServiceLogs, err = servicelog.GetServiceLogsSince(o.clusterID, timeToCheckSvcLogs, false, false)
+		serviceLogs, err := servicelog.GetServiceLogsSince(o.clusterID, timeToCheckSvcLogs, false, false)
 		if err != nil {
-			errors = append(errors, fmt.Errorf("error while getting the service logs: %v", err))
+			addError(fmt.Errorf("error while getting the service logs: %v", err))
+			return
 		}
+		dataMutex.Lock()
+		data.ServiceLogs = serviceLogs
+		dataMutex.Unlock()
+		data.markFetched("service_logs")
 	}
 
 	GetJiraIssues := func() {
 		defer wg.Done()
 		defer utils.StartDelayTracker(o.verbose, "Jira Issues").End()
-		data.JiraIssues, err = utils.GetJiraIssuesForCluster(o.clusterID, o.externalClusterID)
+		result, err := utils.GetJiraIssuesForCluster(o.clusterID, o.externalClusterID, o.jiraSearchLimit())
 		if err != nil {
-			errors = append(errors, fmt.Errorf("error while getting the open jira tickets: %v", err))
+			addError(fmt.Errorf("error while getting the open jira tickets: %v", err))
+			return
 		}
+		addJiraLinks(data.linkRegistry, result.Issues)
+		dataMutex.Lock()
+		data.JiraIssues = result.Issues
+		if result.Truncated() {
+			data.JiraIssuesTotal = result.Total
+		}
+		dataMutex.Unlock()
+		data.markFetched("jira_issues")
 	}
 
 	GetSupportExceptions := func() {
 		defer wg.Done()
 		defer utils.StartDelayTracker(o.verbose, "Support Exceptions").End()
-		data.SupportExceptions, err = utils.GetJiraSupportExceptionsForOrg(o.organizationID)
+		result, err := utils.GetJiraSupportExceptionsForOrg(o.organizationID, o.jiraSearchLimit())
+		if err != nil {
+			addError(fmt.Errorf("error while getting support exceptions: %v", err))
+			return
+		}
+		addJiraLinks(data.linkRegistry, result.Issues)
+		dataMutex.Lock()
+		data.SupportExceptions = result.Issues
+		if result.Truncated() {
+			data.SupportExceptionsTotal = result.Total
+		}
+		dataMutex.Unlock()
+		data.markFetched("support_exceptions")
+	}
+
+	GetSupportCases := func() {
+		defer wg.Done()
+		defer utils.StartDelayTracker(o.verbose, "Support Cases").End()
+		caseClient, err := supportcase.NewClient().Init()
+		if err != nil {
+			addError(fmt.Errorf("skipping support case collection: %v", err))
+			return
+		}
+		accountNumber, err := utils.GetOrgAccountNumber(ocmClient, o.organizationID)
+		if err != nil {
+			addError(fmt.Errorf("error while getting the customer account number: %v", err))
+			return
+		}
+		cases, err := caseClient.GetOpenCases(accountNumber)
 		if err != nil {
-			errors = append(errors, fmt.Errorf("error while getting support exceptions: %v", err))
+			addError(fmt.Errorf("error while getting the support cases: %v", err))
+			return
 		}
+		supportCases := supportcase.ForCluster(cases, o.clusterID, o.externalClusterID)
+		addSupportCaseLinks(data.linkRegistry, supportCases)
+		dataMutex.Lock()
+		data.SupportCases = supportCases
+		dataMutex.Unlock()
+		data.markFetched("support_cases")
 	}
 
 	GetDynatraceURL := func() {
@@ -400,22 +754,32 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 		defer wg.Done()
 		defer utils.StartDelayTracker(o.verbose, "Dynatrace URL").End()
 
+		setDynatraceURL := func(url string) {
+			dataMutex.Lock()
+			defer dataMutex.Unlock()
+			data.DyntraceEnvURL = url
+		}
+
 		clusterID, _, err := dynatrace.GetManagementCluster(ocmClient, o.cluster)
 		if err != nil {
-			errors = append(errors, err)
-			data.DyntraceEnvURL = err.Error()
+			addError(err)
+			setDynatraceURL(err.Error())
 			return
 		}
-		data.DyntraceEnvURL, err = dynatrace.GetDynatraceURLFromLabel(ocmClient, clusterID)
+		dynatraceURL, err := dynatrace.GetDynatraceURLFromLabel(ocmClient, clusterID)
 		if err != nil {
-			errors = append(errors, fmt.Errorf("error The Dynatrace Environemnt URL could not be determined from Label. Using fallback method%s", err))
+			addError(fmt.Errorf("error The Dynatrace Environemnt URL could not be determined from Label. Using fallback method%s", err))
 			// FallBack method to determine via Cluster Login
-			data.DyntraceEnvURL, err = dynatrace.GetDynatraceURLFromManagementCluster(clusterID)
+			dynatraceURL, err = dynatrace.GetDynatraceURLFromManagementCluster(clusterID)
 			if err != nil {
-				errors = append(errors, fmt.Errorf("error The Dynatrace Environemnt URL could not be determined %s", err))
-				data.DyntraceEnvURL = "the Dynatrace Environemnt URL could not be determined. \nPlease refer the SOP to determine the correct Dyntrace Tenant URL- https://github.com/openshift/ops-sop/tree/master/dynatrace#what-environments-are-there"
+				addError(fmt.Errorf("error The Dynatrace Environemnt URL could not be determined %s", err))
+				setDynatraceURL("the Dynatrace Environemnt URL could not be determined. \nPlease refer the SOP to determine the correct Dyntrace Tenant URL- https://github.com/openshift/ops-sop/tree/master/dynatrace#what-environments-are-there")
+				return
 			}
 		}
+		setDynatraceURL(dynatraceURL)
+		data.linkRegistry.Add(links.KindDynatrace, "Dynatrace Environment", dynatraceURL)
+		data.markFetched("dynatrace_env_url")
 	}
 
 	GetPagerDutyAlerts := func() {
@@ -428,30 +792,134 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 		}
 
 		delayTracker := utils.StartDelayTracker(o.verbose, "PagerDuty Service")
-		data.pdServiceID, err = pdProvider.GetPDServiceIDs()
+		serviceIDs, err := pdProvider.GetPDServiceIDs()
 		if err != nil {
-			errors = append(errors, fmt.Errorf("error getting PD Service ID: %v", err))
+			addError(fmt.Errorf("error getting PD Service ID: %v", err))
+		} else {
+			dataMutex.Lock()
+			// The collector keeps using its slice, the data gets a copy as it is sorted when interrupted
+			data.PdServiceIDs = append([]string{}, serviceIDs...)
+			dataMutex.Unlock()
+			data.markFetched("pd_service_ids")
+		}
+		for _, id := range serviceIDs {
+			data.linkRegistry.Add(links.KindPagerDuty, fmt.Sprintf("PagerDuty Service %s", id), fmt.Sprintf("https://redhat.pagerduty.com/service-directory/%s", id))
 		}
 		delayTracker.End()
 
 		defer utils.StartDelayTracker(o.verbose, "current PagerDuty Alerts").End()
-		data.PdAlerts, err = pdProvider.GetFiringAlertsForCluster(data.pdServiceID)
+		alerts, err := pdProvider.GetFiringAlertsForCluster(serviceIDs)
 		if err != nil {
-			errors = append(errors, fmt.Errorf("error while getting current PD Alerts: %v", err))
+			addError(fmt.Errorf("error while getting current PD Alerts: %v", err))
+			return
+		}
+		dataMutex.Lock()
+		data.PdAlerts = alerts
+		dataMutex.Unlock()
+		data.markFetched("pd_alerts")
+	}
+
+	GetCloudProviderEvents := func() {
+		defer wg.Done()
+		defer utils.StartDelayTracker(o.verbose, "Cloud Provider Status").End()
+		region := o.cluster.Region().ID()
+		// The events of the status feeds which could be read are kept when others fail
+		events, err := cloudstatus.NewClient().GetEvents(o.cluster.CloudProvider().ID(), region)
+		dataMutex.Lock()
+		data.CloudProviderRegion = region
+		data.CloudProviderEvents = events
+		dataMutex.Unlock()
+		if err != nil {
+			addError(fmt.Errorf("error while getting cloud provider status events: %v", err))
+			return
+		}
+		data.markFetched("cloud_provider_events")
+	}
+
+	GetSLOs := func() {
+		defer wg.Done()
+		defer utils.StartDelayTracker(o.verbose, "SLOs").End()
+		telemetryClient, err := telemetry.NewClient().Init()
+		if err != nil {
+			addError(fmt.Errorf("skipping SLO collection: %v", err))
+			return
+		}
+		slos, err := telemetry.ConfiguredSLOs()
+		if err != nil {
+			addError(err)
+			return
 		}
+		statuses := telemetryClient.GetSLOStatuses(slos, o.externalClusterID, time.Duration(o.days)*24*time.Hour, time.Now())
+		dataMutex.Lock()
+		data.SLOs = statuses
+		dataMutex.Unlock()
+		data.markFetched("slos")
 	}
 
+	// The collectors of the sections which are disabled or not configured aren't run. The sections still
+	// being collected when the command is interrupted are left out.
 	var retrievers []func()
+	var pendingMutex sync.Mutex
+	pending := map[string]bool{}
+	addRetriever := func(section string, retriever func()) {
+		if o.skipsSection(data, section) {
+			return
+		}
+		pending[section] = true
+		retrievers = append(retrievers, func() {
+			retriever()
+			pendingMutex.Lock()
+			defer pendingMutex.Unlock()
+			delete(pending, section)
+		})
+	}
+
+	CountServiceLogs := func() {
+		defer wg.Done()
+		defer utils.StartDelayTracker(o.verbose, "Service Log counts").End()
+		total, internal, err := servicelog.CountServiceLogsSince(ocmClient, o.cluster, time.Now().AddDate(0, 0, -o.days))
+		if err != nil {
+			addError(fmt.Errorf("error while counting the service logs: %v", err))
+			return
+		}
+		dataMutex.Lock()
+		data.Counts.ServiceLogs, data.Counts.InternalServiceLogs = total, internal
+		dataMutex.Unlock()
+		data.markFetched("counts")
+	}
+
+	CountJiraIssues := func() {
+		defer wg.Done()
+		defer utils.StartDelayTracker(o.verbose, "Jira Issue count").End()
+		jiraIssues, err := utils.CountJiraIssuesForCluster(o.clusterID, o.externalClusterID)
+		if err != nil {
+			addError(fmt.Errorf("error while counting the open jira tickets: %v", err))
+			return
+		}
+		dataMutex.Lock()
+		data.Counts.JiraIssues = jiraIssues
+		dataMutex.Unlock()
+		data.markFetched("counts")
+	}
 
-	retrievers = append(
-		retrievers,
-		GetLimitedSupport,
-		GetServiceLogs,
-		GetJiraIssues,
-		GetSupportExceptions,
-		GetPagerDutyAlerts,
-		GetDynatraceURL,
-	)
+	addRetriever("limited-support", GetLimitedSupport)
+	if o.output == shortOutputConfigValue {
+		// The short output only shows counts, which are fetched without the items, and leaves out the sections
+		// it doesn't show, so it returns in a few seconds
+		data.Counts = &contextCounts{}
+		addRetriever("service-logs", CountServiceLogs)
+		addRetriever("jira-issues", CountJiraIssues)
+		addRetriever("pagerduty-alerts", GetPagerDutyAlerts)
+	} else {
+		addRetriever("service-logs", GetServiceLogs)
+		addRetriever("jira-issues", GetJiraIssues)
+		addRetriever("support-exceptions", GetSupportExceptions)
+		addRetriever("support-cases", GetSupportCases)
+		addRetriever("pagerduty-alerts", GetPagerDutyAlerts)
+		addRetriever("dynatrace", GetDynatraceURL)
+		addRetriever("cloud-provider-events", GetCloudProviderEvents)
+		addRetriever("slo", GetSLOs)
+	}
 
 	if o.output == longOutputConfigValue {
 
@@ -460,18 +928,69 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 			defer utils.StartDelayTracker(o.verbose, "Cluster Description").End()
 
 			cmd := "ocm describe cluster " + o.clusterID
-			output, err := exec.Command("bash", "-c", cmd).Output()
+			output, err := exec.CommandContext(o.context(), "bash", "-c", cmd).Output()
 			if err != nil {
 				fmt.Fprintln(os.Stderr, string(output))
 				fmt.Fprintln(os.Stderr, err)
 			}
+			dataMutex.Lock()
 			data.Description = string(output)
+			dataMutex.Unlock()
+			if err == nil {
+				data.markFetched("description")
+			}
+		}
+
+		GetClusterEvents := func() {
+			defer wg.Done()
+			defer utils.StartDelayTracker(o.verbose, "Cluster Events").End()
+			clusterEvents, err := servicelog.FetchClusterEvents(ocmClient, o.cluster, time.Now().AddDate(0, 0, -o.days), time.Time{}, contextClusterEvents)
+			if err != nil {
+				addError(fmt.Errorf("error while getting the cluster events: %v", err))
+				return
+			}
+			dataMutex.Lock()
+			data.ClusterEvents = clusterEvents
+			dataMutex.Unlock()
+			data.markFetched("cluster_events")
+		}
+
+		GetAddons := func() {
+			defer wg.Done()
+			defer utils.StartDelayTracker(o.verbose, "Add-ons").End()
+			addons, err := fetchAddonInstallations(ocmClient, o.clusterID)
+			if err != nil {
+				addError(fmt.Errorf("error while getting the add-ons: %v", err))
+				return
+			}
+			dataMutex.Lock()
+			data.Addons = addons
+			dataMutex.Unlock()
+			data.markFetched("addons")
+		}
+
+		GetClusterSync := func() {
+			defer wg.Done()
+			defer utils.StartDelayTracker(o.verbose, "Hive ClusterSync").End()
+			clusterSync, err := fetchClusterSync(o.context(), o.clusterID)
+			if err != nil {
+				addError(fmt.Errorf("skipping ClusterSync collection: %v", err))
+				return
+			}
+			dataMutex.Lock()
+			data.ClusterSync = clusterSync
+			dataMutex.Unlock()
+			data.markFetched("clustersync")
 		}
 
-		retrievers = append(
-			retrievers,
-			GetDescription,
-		)
+		addRetriever("description", GetDescription)
+		addRetriever("cluster-events", GetClusterEvents)
+		addRetriever("addons", GetAddons)
+		// Hive doesn't manage the hosted control plane clusters
+		if o.cluster.Hypershift().Enabled() {
+			data.Skipped["clustersync"] = "hosted control plane clusters aren't managed by hive"
+		}
+		addRetriever("clustersync", GetClusterSync)
 	}
 
 	if o.full {
@@ -479,26 +998,57 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 			pdwg.Wait()
 			defer wg.Done()
 			defer utils.StartDelayTracker(o.verbose, "historical PagerDuty Alerts").End()
-			data.HistoricalAlerts, err = pdProvider.GetHistoricalAlertsForCluster(data.pdServiceID)
+			dataMutex.Lock()
+			serviceIDs := append([]string{}, data.PdServiceIDs...)
+			dataMutex.Unlock()
+			historicalAlerts, err := pdProvider.GetHistoricalAlertsForCluster(serviceIDs)
 			if err != nil {
-				errors = append(errors, fmt.Errorf("error while getting historical PD Alert Data: %v", err))
+				addError(fmt.Errorf("error while getting historical PD Alert Data: %v", err))
+				return
 			}
+			dataMutex.Lock()
+			data.HistoricalAlerts = historicalAlerts
+			dataMutex.Unlock()
+			data.markFetched("historical_alerts")
 		}
 
 		GetCloudTrailLogs := func() {
 			defer wg.Done()
 			defer utils.StartDelayTracker(o.verbose, fmt.Sprintf("past %d pages of Cloudtrail data", o.pages)).End()
-			data.CloudtrailEvents, err = GetCloudTrailLogsForCluster(o.awsProfile, o.clusterID, o.pages)
+			cloudtrailEvents, err := GetCloudTrailLogsForCluster(o.awsProfile, o.clusterID, o.pages)
+			if err != nil {
+				addError(fmt.Errorf("error getting cloudtrail logs for cluster: %v", err))
+				return
+			}
+			dataMutex.Lock()
+			data.CloudtrailEvents = cloudtrailEvents
+			dataMutex.Unlock()
+			data.markFetched("cloudtrail_events")
+		}
+
+		GetSecurityFindings := func() {
+			defer wg.Done()
+			defer utils.StartDelayTracker(o.verbose, "GuardDuty and Security Hub findings").End()
+			securityFindings, err := GetSecurityFindingsForCluster(o.awsProfile, o.clusterID, defaultSecurityFindings)
 			if err != nil {
-				errors = append(errors, fmt.Errorf("error getting cloudtrail logs for cluster: %v", err))
+				addError(fmt.Errorf("skipping security findings collection: %v", err))
+				return
 			}
+			dataMutex.Lock()
+			data.SecurityFindings = securityFindings
+			dataMutex.Unlock()
+			data.markFetched("security_findings")
 		}
 
-		retrievers = append(
-			retrievers,
-			GetHistoricalPagerDutyAlerts,
-			GetCloudTrailLogs,
-		)
+		addRetriever("pagerduty-history", GetHistoricalPagerDutyAlerts)
+		// The short output only shows the number of historical alerts
+		if o.output != shortOutputConfigValue {
+			addRetriever("cloudtrail", GetCloudTrailLogs)
+			if strings.ToUpper(o.cluster.CloudProvider().ID()) != "AWS" {
+				data.Skipped["security-findings"] = "GuardDuty and Security Hub are only read for AWS clusters"
+			}
+			addRetriever("security-findings", GetSecurityFindings)
+		}
 	}
 
 	for _, retriever := range retrievers {
@@ -506,11 +1056,75 @@ func (o *contextOptions) generateContextData() (*contextData, []error) {
 		go retriever()
 	}
 
-	wg.Wait()
+	if !waitForCollectors(o.context(), &wg) {
+		pendingMutex.Lock()
+		defer pendingMutex.Unlock()
+		// The collectors still running write to the data under dataMutex, and don't use what they wrote
+		// afterwards, so the copy taken and sorted under it is consistent
+		dataMutex.Lock()
+		defer dataMutex.Unlock()
+		partial := interruptedContextData(data, pending)
+		sortContextData(partial)
+		sections := make([]string, 0, len(pending))
+		for section := range pending {
+			sections = append(sections, section)
+		}
+		sort.Strings(sections)
+		return partial, append(append([]error{}, errors...), fmt.Errorf("interrupted while collecting %s", strings.Join(sections, ", ")))
+	}
+	sortContextData(data)
 
 	return data, errors
 }
 
+func (o *contextOptions) context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
+
+// waitForCollectors returns true once the collectors are done, or false as soon as the context is canceled
+func waitForCollectors(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// interruptedContextData returns a copy of the data collected so far, the collectors still running keep writing
+// to the original. The pending sections are skipped. The caller holds the lock the collectors write the data with.
+func interruptedContextData(data *contextData, pending map[string]bool) *contextData {
+	fetchedAtMutex.Lock()
+	defer fetchedAtMutex.Unlock()
+
+	partial := *data
+	if data.Counts != nil {
+		// The counts are written field by field
+		counts := *data.Counts
+		partial.Counts = &counts
+	}
+	partial.FetchedAt = map[string]time.Time{}
+	for field, fetchedAt := range data.FetchedAt {
+		partial.FetchedAt[field] = fetchedAt
+	}
+	partial.Skipped = map[string]string{}
+	for section, reason := range data.Skipped {
+		partial.Skipped[section] = reason
+	}
+	for section := range pending {
+		partial.Skipped[section] = "interrupted before it was collected"
+	}
+	return &partial
+}
+
 func GetCloudTrailLogsForCluster(awsProfile string, clusterID string, maxPages int) ([]*types.Event, error) {
 	awsJumpClient, err := osdCloud.GenerateAWSClientForCluster(awsProfile, clusterID)
 	if err != nil {
@@ -529,6 +1143,9 @@ func GetCloudTrailLogsForCluster(awsProfile string, clusterID string, maxPages i
 		}
 
 		foundEvents = append(foundEvents, cloudTrailEvents.Events...)
+		if err := rawdump.WriteJSON("cloudtrail_events", "LookupEvents", cloudTrailEvents); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save the raw CloudTrail events: %v\n", err)
+		}
 
 		// for pagination
 		eventSearchInput.NextToken = cloudTrailEvents.NextToken
@@ -580,13 +1197,28 @@ func printHistoricalPDAlertSummary(incidentCounters map[string][]*pagerduty.Inci
 	}
 }
 
+// jiraSearchLimit returns the number of issues to fetch, 0 for all of them
+func (o *contextOptions) jiraSearchLimit() int {
+	if o.jiraAll {
+		return 0
+	}
+	return o.jiraLimit
+}
+
+// printJiraTotal tells when the listed issues are only part of the matching ones
+func printJiraTotal(shown int, total int) {
+	if total > shown {
+		fmt.Printf("Showing %d of %d issues, use --jira-limit or --jira-all to list more\n", shown, total)
+	}
+}
+
 func printJIRASupportExceptions(issues []jira.Issue) {
 	var name string = "Support Exceptions"
 	fmt.Println(delimiter + name)
 
 	for _, i := range issues {
 		fmt.Printf("[%s](%s/%s): %+v [Status: %s]\n", i.Key, i.Fields.Type.Name, i.Fields.Priority.Name, i.Fields.Summary, i.Fields.Status.Name)
-		fmt.Printf("- Link: %s/browse/%s\n\n", JiraBaseURL, i.Key)
+		fmt.Printf("- Link: %s\n\n", utils.JiraBrowseURL(i.Key))
 	}
 
 	if len(issues) == 0 {
@@ -594,21 +1226,29 @@ func printJIRASupportExceptions(issues []jira.Issue) {
 	}
 }
 
-func (o *contextOptions) printOtherLinks(data *contextData) {
-	var name string = "External resources"
+func printSupportCases(cases []supportcase.Case) {
+	var name string = "Support Cases"
 	fmt.Println(delimiter + name)
 
-	links := map[string]string{
-		"OHSS Cards":        fmt.Sprintf("%s/issues/?jql=project%%20%%3D%%20OHSS%%20and%%20(%%22Cluster%%20ID%%22%%20~%%20%%20%%22%s%%22%%20OR%%20%%22Cluster%%20ID%%22%%20~%%20%%22%s%%22)", JiraBaseURL, o.clusterID, o.externalClusterID),
-		"CCX dashboard":     fmt.Sprintf("https://kraken.psi.redhat.com/clusters/%s", o.externalClusterID),
-		"Splunk Audit Logs": o.buildSplunkURL(data),
+	for _, c := range cases {
+		fmt.Printf("[%s](%s): %s [Status: %s]\n", c.CaseNumber, c.Severity, c.Summary, c.Status)
+		fmt.Printf("- Updated: %s\tLink: %s\n\n", c.LastModifiedDate.Format("2006-01-02 15:04"), c.URL())
 	}
 
-	if data.pdServiceID != nil {
-		for _, id := range data.pdServiceID {
-			links[fmt.Sprintf("PagerDuty Service %s", id)] = fmt.Sprintf("https://redhat.pagerduty.com/service-directory/%s", id)
-		}
+	if len(cases) == 0 {
+		fmt.Println("None")
 	}
+}
+
+func (o *contextOptions) printOtherLinks(data *contextData) {
+	o.writeOtherLinks(os.Stdout, data)
+}
+
+func (o *contextOptions) writeOtherLinks(w io.Writer, data *contextData) {
+	var name string = "External resources"
+	fmt.Fprintln(w, delimiter+name)
+
+	links := o.otherLinks(data)
 
 	// Sort, so it's always a predictable order
 	var keys []string
@@ -617,7 +1257,7 @@ func (o *contextOptions) printOtherLinks(data *contextData) {
 	}
 	sort.Strings(keys)
 
-	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
 	for _, link := range keys {
 		table.AddRow([]string{link, strings.TrimSpace(links[link])})
 	}
@@ -627,6 +1267,31 @@ func (o *contextOptions) printOtherLinks(data *contextData) {
 	}
 }
 
+func (o *contextOptions) otherLinks(data *contextData) map[string]string {
+	otherLinks := map[string]string{}
+	if data.linkRegistry == nil {
+		return otherLinks
+	}
+	for _, link := range data.linkRegistry.Links(links.KindOHSS, links.KindCCX, links.KindSplunk, links.KindPagerDuty) {
+		otherLinks[link.Name] = link.URL
+	}
+	return otherLinks
+}
+
+// addJiraLinks registers the links of the given Jira issues
+func addJiraLinks(registry *links.Registry, issues []jira.Issue) {
+	for _, issue := range issues {
+		registry.Add(links.KindJira, fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary), utils.JiraBrowseURL(issue.Key))
+	}
+}
+
+// addSupportCaseLinks registers the links of the given support cases
+func addSupportCaseLinks(registry *links.Registry, cases []supportcase.Case) {
+	for _, supportCase := range cases {
+		registry.Add(links.KindCase, fmt.Sprintf("Case %s: %s", supportCase.CaseNumber, supportCase.Summary), supportCase.URL())
+	}
+}
+
 func (o *contextOptions) buildSplunkURL(data *contextData) string {
 	// Determine the relevant Splunk URL
 	if o.cluster.Hypershift().Enabled() {
@@ -696,6 +1361,33 @@ func skippableEvent(eventName string) bool {
 	return false
 }
 
+func printCloudProviderEvents(data *contextData) {
+	var name string = fmt.Sprintf("Cloud Provider Status (%s)", data.CloudProviderRegion)
+	fmt.Println(delimiter + name)
+
+	if len(data.CloudProviderEvents) == 0 {
+		fmt.Println("None")
+		return
+	}
+
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"Published", "Service", "Title", "Resolved", "URL"})
+	for _, event := range data.CloudProviderEvents {
+		table.AddRow([]string{
+			event.Published.Format(time.RFC3339),
+			event.Service,
+			event.Title,
+			strconv.FormatBool(event.Resolved),
+			event.URL,
+		})
+	}
+	// Add empty row for readability
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing %s: %v\n", name, err)
+	}
+}
+
 func printDynatraceEnvURL(data *contextData) {
 	var name string = "Dynatrace Environment URL"
 	fmt.Println(delimiter + name)
@@ -703,8 +1395,39 @@ func printDynatraceEnvURL(data *contextData) {
 }
 
 func (data *contextData) printClusterHeader() {
+	data.writeClusterHeader(os.Stdout)
+}
+
+func (data *contextData) writeClusterHeader(w io.Writer) {
 	clusterHeader := fmt.Sprintf("%s -- %s", data.ClusterName, data.ClusterID)
-	fmt.Println(strings.Repeat("=", len(clusterHeader)))
-	fmt.Println(clusterHeader)
-	fmt.Println(strings.Repeat("=", len(clusterHeader)))
+	fmt.Fprintln(w, strings.Repeat("=", len(clusterHeader)))
+	fmt.Fprintln(w, clusterHeader)
+	fmt.Fprintln(w, strings.Repeat("=", len(clusterHeader)))
+}
+
+// startTrace starts recording the timing of the collectors when a trace file or endpoint is configured, the
+// returned root span is nil otherwise
+func (o *contextOptions) startTrace() *tracing.Span {
+	if o.traceEndpoint == "" {
+		o.traceEndpoint = viper.GetString(tracing.EndpointConfigKey)
+	}
+	if o.traceEndpoint == "" && o.traceFile == "" {
+		return nil
+	}
+	return tracing.Start("cluster context", map[string]string{
+		"service.version": utils.Version,
+		"cluster.id":      o.clusterID,
+		"output":          o.output,
+	})
+}
+
+func (o *contextOptions) exportTrace(trace *tracing.Span, dataErrors []error) {
+	if trace == nil {
+		return
+	}
+	trace.SetAttribute("collector.errors", strconv.Itoa(len(dataErrors)))
+	trace.End(nil)
+	if err := tracing.Export(o.traceEndpoint, o.traceFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 }
@@ -0,0 +1,144 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	hiveinternalv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterSyncMessageLength bounds the failure messages printed without --verbose, they can hold whole manifests
+const clusterSyncMessageLength = 300
+
+// clusterSyncSummary is the state of the hive ClusterSync of a cluster, which applies its SyncSets and
+// SelectorSyncSets. A failing ClusterSync is often the root cause of the configuration of a cluster drifting.
+type clusterSyncSummary struct {
+	Hive    string `json:"hive"`
+	Failing bool   `json:"failing"`
+	// Since is when the ClusterSync last went from failing to succeeding, or the other way around
+	Since   time.Time `json:"since"`
+	Message string    `json:"message,omitempty"`
+	// FailingSyncSets are the SyncSets and SelectorSyncSets failing to apply, the longest failing first
+	FailingSyncSets []failingSyncSet `json:"failing_syncsets"`
+}
+
+// failingSyncSet is a SyncSet or SelectorSyncSet that hive fails to apply to the cluster
+type failingSyncSet struct {
+	Name           string    `json:"name"`
+	Kind           string    `json:"kind"`
+	Since          time.Time `json:"since"`
+	FailureMessage string    `json:"failure_message"`
+}
+
+// fetchClusterSync returns the summary of the ClusterSync of the cluster, read from its hive shard
+func fetchClusterSync(ctx context.Context, clusterID string) (*clusterSyncSummary, error) {
+	hive, err := utils.GetHiveCluster(clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the hive shard of the cluster: %w", err)
+	}
+	hiveClient, err := newSyncSetHiveClient(hive.ID())
+	if err != nil {
+		return nil, fmt.Errorf("no access to hive shard %s: %w", hive.Name(), err)
+	}
+	cd, err := clusterDeploymentOf(ctx, hiveClient, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	clusterSync := &hiveinternalv1alpha1.ClusterSync{}
+	if err := hiveClient.Get(ctx, client.ObjectKey{Namespace: cd.Namespace, Name: cd.Name}, clusterSync); err != nil {
+		return nil, fmt.Errorf("failed to get ClusterSync %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	summary := summarizeClusterSync(clusterSync)
+	summary.Hive = hive.Name()
+	return summary, nil
+}
+
+// summarizeClusterSync returns the failing SyncSets and SelectorSyncSets of the ClusterSync and their errors
+func summarizeClusterSync(clusterSync *hiveinternalv1alpha1.ClusterSync) *clusterSyncSummary {
+	summary := &clusterSyncSummary{FailingSyncSets: []failingSyncSet{}}
+	for _, condition := range clusterSync.Status.Conditions {
+		if condition.Type != hiveinternalv1alpha1.ClusterSyncFailed {
+			continue
+		}
+		summary.Failing = condition.Status == corev1.ConditionTrue
+		summary.Since = condition.LastTransitionTime.UTC()
+		summary.Message = condition.Message
+	}
+
+	addFailing := func(kind string, statuses []hiveinternalv1alpha1.SyncStatus) {
+		for _, status := range statuses {
+			if status.Result != hiveinternalv1alpha1.FailureSyncSetResult {
+				continue
+			}
+			summary.FailingSyncSets = append(summary.FailingSyncSets, failingSyncSet{
+				Name:           status.Name,
+				Kind:           kind,
+				Since:          status.LastTransitionTime.UTC(),
+				FailureMessage: status.FailureMessage,
+			})
+		}
+	}
+	addFailing("SelectorSyncSet", clusterSync.Status.SelectorSyncSets)
+	addFailing("SyncSet", clusterSync.Status.SyncSets)
+	sort.SliceStable(summary.FailingSyncSets, func(i, j int) bool {
+		a, b := summary.FailingSyncSets[i], summary.FailingSyncSets[j]
+		if !a.Since.Equal(b.Since) {
+			return a.Since.Before(b.Since)
+		}
+		return a.Name < b.Name
+	})
+	// The condition is only updated on the next sync, the syncsets already tell it's failing
+	if len(summary.FailingSyncSets) > 0 {
+		summary.Failing = true
+	}
+	return summary
+}
+
+func printClusterSync(data *contextData, verbose bool) {
+	writeClusterSync(os.Stdout, data.ClusterSync, verbose)
+}
+
+// writeClusterSync prints the failing SyncSets of the cluster and their errors, the full errors with verbose
+func writeClusterSync(w io.Writer, summary *clusterSyncSummary, verbose bool) {
+	fmt.Fprintln(w, delimiter+"Hive ClusterSync")
+	if summary == nil {
+		fmt.Fprintln(w, "Not collected")
+		return
+	}
+	if !summary.Failing {
+		fmt.Fprintf(w, "All SyncSets applied by hive %s", summary.Hive)
+		if !summary.Since.IsZero() {
+			fmt.Fprintf(w, " since %s", summary.Since.Format(time.RFC3339))
+		}
+		fmt.Fprintln(w)
+		return
+	}
+
+	fmt.Fprintf(w, "Failing on hive %s", summary.Hive)
+	if !summary.Since.IsZero() {
+		fmt.Fprintf(w, " since %s", summary.Since.Format(time.RFC3339))
+	}
+	fmt.Fprintf(w, ": %d SyncSets failing to apply\n", len(summary.FailingSyncSets))
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"KIND", "NAME", "FAILING SINCE", "ERROR"})
+	for _, syncSet := range summary.FailingSyncSets {
+		message := strings.Join(strings.Fields(syncSet.FailureMessage), " ")
+		if !verbose && len(message) > clusterSyncMessageLength {
+			message = message[:clusterSyncMessageLength] + "... (--verbose for the full error)"
+		}
+		table.AddRow([]string{syncSet.Kind, syncSet.Name, syncSet.Since.Format(time.RFC3339), message})
+	}
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the ClusterSync: %v\n", err)
+	}
+}
@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	hiveinternalv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummarizeClusterSync(t *testing.T) {
+	since := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	failed := func(status corev1.ConditionStatus, message string) []hiveinternalv1alpha1.ClusterSyncCondition {
+		return []hiveinternalv1alpha1.ClusterSyncCondition{{
+			Type:               hiveinternalv1alpha1.ClusterSyncFailed,
+			Status:             status,
+			Message:            message,
+			LastTransitionTime: metav1.NewTime(since),
+		}}
+	}
+
+	tests := []struct {
+		name   string
+		status hiveinternalv1alpha1.ClusterSyncStatus
+		want   *clusterSyncSummary
+	}{
+		{
+			name: "all syncsets applied",
+			status: hiveinternalv1alpha1.ClusterSyncStatus{
+				Conditions:       failed(corev1.ConditionFalse, "All SyncSets and SelectorSyncSets have been applied to the cluster"),
+				SelectorSyncSets: []hiveinternalv1alpha1.SyncStatus{{Name: "osd-ingress", Result: hiveinternalv1alpha1.SuccessSyncSetResult}},
+			},
+			want: &clusterSyncSummary{Since: since, Message: "All SyncSets and SelectorSyncSets have been applied to the cluster", FailingSyncSets: []failingSyncSet{}},
+		},
+		{
+			name: "failing syncsets, longest failing first",
+			status: hiveinternalv1alpha1.ClusterSyncStatus{
+				Conditions: failed(corev1.ConditionTrue, "SelectorSyncSet osd-ingress is failing"),
+				SelectorSyncSets: []hiveinternalv1alpha1.SyncStatus{
+					{Name: "osd-ingress", Result: hiveinternalv1alpha1.FailureSyncSetResult, FailureMessage: "admission webhook denied the request", LastTransitionTime: metav1.NewTime(since.Add(time.Hour))},
+					{Name: "osd-logging", Result: hiveinternalv1alpha1.SuccessSyncSetResult},
+				},
+				SyncSets: []hiveinternalv1alpha1.SyncStatus{
+					{Name: "cluster-config", Result: hiveinternalv1alpha1.FailureSyncSetResult, FailureMessage: "forbidden", LastTransitionTime: metav1.NewTime(since)},
+				},
+			},
+			want: &clusterSyncSummary{
+				Failing: true,
+				Since:   since,
+				Message: "SelectorSyncSet osd-ingress is failing",
+				FailingSyncSets: []failingSyncSet{
+					{Name: "cluster-config", Kind: "SyncSet", Since: since, FailureMessage: "forbidden"},
+					{Name: "osd-ingress", Kind: "SelectorSyncSet", Since: since.Add(time.Hour), FailureMessage: "admission webhook denied the request"},
+				},
+			},
+		},
+		{
+			name: "failing syncset ahead of the condition",
+			status: hiveinternalv1alpha1.ClusterSyncStatus{
+				SyncSets: []hiveinternalv1alpha1.SyncStatus{
+					{Name: "cluster-config", Result: hiveinternalv1alpha1.FailureSyncSetResult, FailureMessage: "forbidden", LastTransitionTime: metav1.NewTime(since)},
+				},
+			},
+			want: &clusterSyncSummary{
+				Failing:         true,
+				FailingSyncSets: []failingSyncSet{{Name: "cluster-config", Kind: "SyncSet", Since: since, FailureMessage: "forbidden"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarizeClusterSync(&hiveinternalv1alpha1.ClusterSync{Status: tt.status})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("summarizeClusterSync() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteClusterSyncTruncatesErrors(t *testing.T) {
+	summary := &clusterSyncSummary{
+		Hive:            "hivep01ue1",
+		Failing:         true,
+		FailingSyncSets: []failingSyncSet{{Name: "osd-ingress", Kind: "SelectorSyncSet", FailureMessage: strings.Repeat("error ", 100)}},
+	}
+
+	var short, verbose bytes.Buffer
+	writeClusterSync(&short, summary, false)
+	writeClusterSync(&verbose, summary, true)
+	if !strings.Contains(short.String(), "Failing on hive hivep01ue1: 1 SyncSets failing to apply") || !strings.Contains(short.String(), "(--verbose for the full error)") {
+		t.Errorf("writeClusterSync() =\n%s", short.String())
+	}
+	if strings.Contains(verbose.String(), "--verbose") || !strings.Contains(verbose.String(), strings.TrimSpace(strings.Repeat("error ", 100))) {
+		t.Errorf("writeClusterSync() with verbose =\n%s", verbose.String())
+	}
+}
@@ -0,0 +1,73 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/printer"
+)
+
+// fetchedAtMutex guards the FetchedAt of the context data, recorded by the concurrent collectors
+var fetchedAtMutex sync.Mutex
+
+// markFetched records that the data of the given JSON fields was just fetched
+func (d *contextData) markFetched(fields ...string) {
+	now := time.Now().UTC()
+	fetchedAtMutex.Lock()
+	defer fetchedAtMutex.Unlock()
+	if d.FetchedAt == nil {
+		d.FetchedAt = map[string]time.Time{}
+	}
+	for _, field := range fields {
+		d.FetchedAt[field] = now
+	}
+}
+
+func printFreshness(data *contextData) {
+	writeFreshness(os.Stdout, data, time.Now())
+}
+
+// writeFreshness prints when the data of each source was fetched, so the readers of a shared or offline
+// context know whether e.g. the PagerDuty alerts are live or ten minutes old
+func writeFreshness(w io.Writer, data *contextData, now time.Time) {
+	fmt.Fprintln(w, delimiter+"Data Freshness")
+	if len(data.FetchedAt) == 0 {
+		fmt.Fprintln(w, "Unknown")
+		return
+	}
+
+	fields := make([]string, 0, len(data.FetchedAt))
+	for field := range data.FetchedAt {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"SOURCE", "FETCHED AT", "AGE"})
+	for _, field := range fields {
+		fetchedAt := data.FetchedAt[field]
+		table.AddRow([]string{field, fetchedAt.Local().Format(time.RFC3339), formatAge(now.Sub(fetchedAt))})
+	}
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the data freshness: %v\n", err)
+	}
+}
+
+// formatAge rounds the age of the data to what matters to a reader
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "live"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh%02dm ago", int(age.Hours()), int(age.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{age: 30 * time.Second, want: "live"},
+		{age: 10*time.Minute + 20*time.Second, want: "10m ago"},
+		{age: 2*time.Hour + 5*time.Minute, want: "2h05m ago"},
+		{age: 72 * time.Hour, want: "3d ago"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.want {
+			t.Errorf("formatAge(%s) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestWriteFreshness(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	data := &contextData{}
+	data.markFetched("service_logs", "pd_alerts")
+	data.FetchedAt["pd_alerts"] = now.Add(-10 * time.Minute)
+	data.FetchedAt["service_logs"] = now
+
+	var out bytes.Buffer
+	writeFreshness(&out, data, now)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected the title, the header and 2 sources, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[2], "pd_alerts") || !strings.HasSuffix(strings.TrimSpace(lines[2]), "10m ago") {
+		t.Errorf("unexpected PagerDuty alerts freshness %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[3], "service_logs") || !strings.HasSuffix(strings.TrimSpace(lines[3]), "live") {
+		t.Errorf("unexpected service logs freshness %q", lines[3])
+	}
+
+	out.Reset()
+	writeFreshness(&out, &contextData{}, now)
+	if !strings.Contains(out.String(), "Unknown") {
+		t.Errorf("expected an unknown freshness without fetch times, got %q", out.String())
+	}
+}
@@ -0,0 +1,145 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openshift/osdctl/internal/testutil/fixtures"
+	"github.com/openshift/osdctl/internal/testutil/golden"
+	"github.com/spf13/viper"
+)
+
+// fixtureContextData returns the context of the fixture cluster, as collected by generateContextData
+func fixtureContextData() *contextData {
+	data := &contextData{
+		ClusterName:           fixtures.ClusterName,
+		ClusterVersion:        fixtures.ClusterVersion,
+		ClusterID:             fixtures.ClusterID,
+		OCMEnv:                fixtures.OCMEnv,
+		Description:           "OSD cluster on AWS, 3 compute nodes\n",
+		LimitedSupportReasons: fixtures.LimitedSupportReasons(),
+		ServiceLogs:           fixtures.ServiceLogs(),
+		JiraIssues:            fixtures.JiraIssues(),
+		SupportCases:          fixtures.SupportCases(),
+		PdServiceIDs:          []string{fixtures.PagerDutyServiceID},
+		PdAlerts:              fixtures.PagerDutyIncidents(),
+		Skipped:               map[string]string{},
+	}
+	sortContextData(data)
+	return data
+}
+
+// skipIntegrations marks the sections of the integrations as skipped, as when they're not configured
+func skipIntegrations(data *contextData, names ...string) {
+	for _, integration := range contextIntegrations {
+		for _, name := range names {
+			if integration.name != name {
+				continue
+			}
+			for _, section := range integration.sections {
+				data.Skipped[section] = integration.name + " not configured, " + integration.hint
+			}
+		}
+	}
+}
+
+func TestContextShortOutputGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		skipped []string
+		// counts replace the service logs and the Jira cards, as collected for the short output
+		counts *contextCounts
+	}{
+		{name: "context_short"},
+		{name: "context_short_skipped", skipped: []string{"PagerDuty", "Jira"}},
+		{name: "context_short_counts", counts: &contextCounts{ServiceLogs: 12, InternalServiceLogs: 3, JiraIssues: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := fixtureContextData()
+			skipIntegrations(data, tt.skipped...)
+			if tt.counts != nil {
+				data.ServiceLogs, data.JiraIssues, data.Counts = nil, nil, tt.counts
+			}
+			o := &contextOptions{days: 30}
+
+			var got bytes.Buffer
+			o.writeShortOutput(&got, data)
+			golden.Assert(t, tt.name, got.Bytes())
+		})
+	}
+}
+
+func TestContextLongOutputGolden(t *testing.T) {
+	t.Setenv("JIRA_BASE_URL", "")
+	viper.Reset()
+	defer viper.Reset()
+
+	// the sections not depending on the environment of the user nor on the current time
+	sections, err := selectContextSections([]string{"description", "limited-support", "service-logs", "jira-issues", "support-cases", "pagerduty-alerts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		skipped  []string
+		disabled map[string]bool
+	}{
+		{name: "context_long"},
+		{name: "context_long_skipped", skipped: []string{"PagerDuty"}, disabled: map[string]bool{"support-cases": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := fixtureContextData()
+			skipIntegrations(data, tt.skipped...)
+			o := &contextOptions{days: 30, sections: sections, disabledSections: tt.disabled}
+
+			got := golden.CaptureStdout(t, func() {
+				o.printLongOutput(data)
+			})
+			golden.Assert(t, tt.name, got)
+		})
+	}
+}
+
+func TestContextSLOsGolden(t *testing.T) {
+	var got bytes.Buffer
+	writeSLOs(&got, fixtures.SLOStatuses(), 7)
+	golden.Assert(t, "context_slos", got.Bytes())
+}
+
+func TestContextJSONOutputGolden(t *testing.T) {
+	data := &contextData{
+		ClusterName:    fixtures.ClusterName,
+		ClusterVersion: fixtures.ClusterVersion,
+		ClusterID:      fixtures.ClusterID,
+		OCMEnv:         fixtures.OCMEnv,
+		Description:    "OSD cluster on AWS, 3 compute nodes",
+		JiraIssues:     fixtures.JiraIssues(),
+		SupportCases:   fixtures.SupportCases(),
+		PdServiceIDs:   []string{fixtures.PagerDutyServiceID},
+		FetchedAt: map[string]time.Time{
+			"jira_issues":   fixtures.Now,
+			"support_cases": fixtures.Now,
+		},
+		Skipped: map[string]string{"support-exceptions": "disabled for the test"},
+	}
+
+	got, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden.AssertJSON(t, "context_json", got)
+
+	// the output can be read back
+	var read contextData
+	if err := json.Unmarshal(got, &read); err != nil {
+		t.Fatal(err)
+	}
+	want := fixtures.SupportCases()[0]
+	if len(read.SupportCases) != 1 || read.SupportCases[0].CaseNumber != want.CaseNumber || !read.SupportCases[0].LastModifiedDate.Equal(want.LastModifiedDate) {
+		t.Errorf("the support cases weren't read back: %+v", read.SupportCases)
+	}
+}
@@ -0,0 +1,112 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/provider/supportcase"
+	"github.com/openshift/osdctl/pkg/provider/telemetry"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/viper"
+)
+
+// contextDisabledSectionsConfigKey lists the sections that are never collected nor printed, e.g. those of
+// the integrations the user doesn't have access to
+const contextDisabledSectionsConfigKey = "context_disabled_sections"
+
+// contextIntegration is an optional external service that some sections are collected from
+type contextIntegration struct {
+	name     string
+	sections []string
+	// hint tells how to configure the integration
+	hint       string
+	configured func(o *contextOptions) bool
+}
+
+var contextIntegrations = []contextIntegration{
+	{
+		name:     "PagerDuty",
+		sections: []string{"pagerduty-alerts", "pagerduty-history"},
+		hint:     "set pd_user_token or pd_oauth_token in the osdctl config",
+		configured: func(o *contextOptions) bool {
+			return o.usertoken != "" || o.oauthtoken != ""
+		},
+	},
+	{
+		name:     "Jira",
+		sections: []string{"jira-issues", "support-exceptions"},
+		hint:     "set JIRA_API_TOKEN or jira_token in the osdctl config",
+		configured: func(o *contextOptions) bool {
+			return o.jiratoken != "" || utils.JiraTokenConfigured()
+		},
+	},
+	{
+		name:     "Customer Portal",
+		sections: []string{"support-cases"},
+		hint:     fmt.Sprintf("set %s or %s in the osdctl config", supportcase.OfflineTokenEnvVar, supportcase.OfflineTokenConfigKey),
+		configured: func(o *contextOptions) bool {
+			return supportcase.Configured()
+		},
+	},
+	{
+		name:     "Telemetry",
+		sections: []string{"slo"},
+		hint:     fmt.Sprintf("set %s or %s in the osdctl config", telemetry.TokenEnvVar, telemetry.TokenConfigKey),
+		configured: func(o *contextOptions) bool {
+			return telemetry.Configured()
+		},
+	},
+}
+
+// skippedContextSections returns the sections that can't be collected because their integration isn't
+// configured, with a hint to configure it, by section name
+func (o *contextOptions) skippedContextSections() map[string]string {
+	skipped := map[string]string{}
+	for _, integration := range contextIntegrations {
+		if integration.configured(o) {
+			continue
+		}
+		for _, section := range integration.sections {
+			if o.disabledSections[section] {
+				continue
+			}
+			skipped[section] = fmt.Sprintf("%s not configured, %s", integration.name, integration.hint)
+		}
+	}
+	return skipped
+}
+
+// skipsSection returns true when the section isn't collected, because it's disabled or not configured
+func (o *contextOptions) skipsSection(data *contextData, name string) bool {
+	_, skipped := data.Skipped[name]
+	return skipped || o.disabledSections[name]
+}
+
+// completeDisabledSections reads the sections disabled in the config
+func (o *contextOptions) completeDisabledSections() error {
+	o.disabledSections = map[string]bool{}
+	for _, name := range viper.GetStringSlice(contextDisabledSectionsConfigKey) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := lookupContextSection(name); !ok || name == contextHeaderSection {
+			return fmt.Errorf("unknown section '%s' in %s, expected some of %v", name, contextDisabledSectionsConfigKey, contextSectionNames())
+		}
+		o.disabledSections[name] = true
+	}
+	return nil
+}
+
+// skippedHints returns the distinct reasons the sections were skipped, one line per integration
+func skippedHints(skipped map[string]string) []string {
+	seen := map[string]bool{}
+	var hints []string
+	for _, reason := range skipped {
+		if seen[reason] {
+			continue
+		}
+		seen[reason] = true
+		hints = append(hints, reason)
+	}
+	sort.Strings(hints)
+	return hints
+}
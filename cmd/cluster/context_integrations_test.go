@@ -0,0 +1,125 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/provider/supportcase"
+	"github.com/openshift/osdctl/pkg/provider/telemetry"
+	"github.com/spf13/viper"
+)
+
+func TestSkippedContextSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  contextOptions
+		jira     string
+		token    string
+		disabled map[string]bool
+		want     []string
+	}{
+		{
+			name: "nothing configured",
+			want: []string{"support-exceptions", "jira-issues", "support-cases", "pagerduty-alerts", "slo", "pagerduty-history"},
+		},
+		{
+			name:    "PagerDuty, Jira and the telemetry configured",
+			options: contextOptions{usertoken: "token"},
+			jira:    "token",
+			token:   "token",
+			want:    []string{"support-cases"},
+		},
+		{
+			name:     "disabled sections aren't reported as not configured",
+			options:  contextOptions{oauthtoken: "token"},
+			disabled: map[string]bool{"jira-issues": true, "support-exceptions": true},
+			want:     []string{"support-cases", "slo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			t.Setenv("JIRA_API_TOKEN", tt.jira)
+			t.Setenv(supportcase.OfflineTokenEnvVar, "")
+			t.Setenv(telemetry.TokenEnvVar, tt.token)
+
+			o := tt.options
+			o.disabledSections = tt.disabled
+			skipped := o.skippedContextSections()
+
+			// in the order of the layout
+			var got []string
+			for _, name := range contextSectionNames() {
+				if _, ok := skipped[name]; ok {
+					got = append(got, name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("skippedContextSections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkippedHints(t *testing.T) {
+	skipped := map[string]string{
+		"pagerduty-alerts":  "PagerDuty not configured, set pd_user_token or pd_oauth_token in the osdctl config",
+		"pagerduty-history": "PagerDuty not configured, set pd_user_token or pd_oauth_token in the osdctl config",
+		"jira-issues":       "Jira not configured, set JIRA_API_TOKEN or jira_token in the osdctl config",
+	}
+	want := []string{
+		"Jira not configured, set JIRA_API_TOKEN or jira_token in the osdctl config",
+		"PagerDuty not configured, set pd_user_token or pd_oauth_token in the osdctl config",
+	}
+	if got := skippedHints(skipped); !reflect.DeepEqual(got, want) {
+		t.Errorf("skippedHints() = %v, want %v", got, want)
+	}
+}
+
+func TestPrintSectionSkipped(t *testing.T) {
+	section, _ := lookupContextSection("jira-issues")
+	data := &contextData{Skipped: map[string]string{"jira-issues": "Jira not configured, set JIRA_API_TOKEN or jira_token in the osdctl config"}}
+
+	o := &contextOptions{}
+	got, err := captureStdout(func() { o.printSection(section, data) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := delimiter + "jira-issues: skipped (Jira not configured, set JIRA_API_TOKEN or jira_token in the osdctl config)\n"
+	if got != want {
+		t.Errorf("printSection() = %q, want %q", got, want)
+	}
+
+	o.disabledSections = map[string]bool{"jira-issues": true}
+	got, err = captureStdout(func() { o.printSection(section, data) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("expected a disabled section to print nothing, got %q", got)
+	}
+}
+
+func TestInterruptedContextData(t *testing.T) {
+	data := &contextData{ClusterID: "abc", Skipped: map[string]string{"jira-issues": "Jira not configured"}, Counts: &contextCounts{ServiceLogs: 3}}
+	data.markFetched("service_logs")
+
+	partial := interruptedContextData(data, map[string]bool{"pagerduty-alerts": true})
+	data.markFetched("pd_alerts")
+	data.Counts.JiraIssues = 2
+
+	want := map[string]string{"jira-issues": "Jira not configured", "pagerduty-alerts": "interrupted before it was collected"}
+	if !reflect.DeepEqual(partial.Skipped, want) {
+		t.Errorf("interruptedContextData() skipped %v, want %v", partial.Skipped, want)
+	}
+	if _, ok := partial.FetchedAt["pd_alerts"]; ok || partial.ClusterID != "abc" || len(partial.FetchedAt) != 1 {
+		t.Errorf("interruptedContextData() = %+v, want the data collected before the interruption only", partial)
+	}
+	if len(data.Skipped) != 1 {
+		t.Errorf("interruptedContextData() changed the skipped sections of the original data: %v", data.Skipped)
+	}
+	if partial.Counts.ServiceLogs != 3 || partial.Counts.JiraIssues != 0 {
+		t.Errorf("interruptedContextData() counts = %+v, want the counts collected before the interruption only", partial.Counts)
+	}
+}
@@ -0,0 +1,221 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/andygrunwald/go-jira"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+)
+
+// MarshalJSON marshals the OCM objects with the SDK marshallers, encoding/json only sees their
+// unexported fields and would print them as empty objects
+func (d *contextData) MarshalJSON() ([]byte, error) {
+	limitedSupportReasons, err := marshalSDKList(func(w io.Writer) error {
+		return cmv1.MarshalLimitedSupportReasonList(d.LimitedSupportReasons, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	serviceLogs, err := marshalSDKList(func(w io.Writer) error {
+		return v1.MarshalLogEntryList(d.ServiceLogs, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clusterEvents, err := marshalSDKList(func(w io.Writer) error {
+		return v1.MarshalLogEntryList(d.ClusterEvents, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	addons, err := marshalSDKList(func(w io.Writer) error {
+		return cmv1.MarshalAddOnInstallationList(d.Addons, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The fields of the outer struct take precedence over the embedded ones with the same name
+	type plainContextData contextData
+	return json.Marshal(&struct {
+		*plainContextData
+		LimitedSupportReasons json.RawMessage `json:"limited_support_reasons"`
+		ServiceLogs           json.RawMessage `json:"service_logs"`
+		ClusterEvents         json.RawMessage `json:"cluster_events"`
+		Addons                json.RawMessage `json:"addons"`
+	}{
+		plainContextData:      (*plainContextData)(d),
+		LimitedSupportReasons: limitedSupportReasons,
+		ServiceLogs:           serviceLogs,
+		ClusterEvents:         clusterEvents,
+		Addons:                addons,
+	})
+}
+
+// UnmarshalJSON reads the output of MarshalJSON back, to print the context of a cluster from a capture
+func (d *contextData) UnmarshalJSON(b []byte) error {
+	type plainContextData contextData
+	raw := struct {
+		*plainContextData
+		LimitedSupportReasons json.RawMessage `json:"limited_support_reasons"`
+		ServiceLogs           json.RawMessage `json:"service_logs"`
+		ClusterEvents         json.RawMessage `json:"cluster_events"`
+		Addons                json.RawMessage `json:"addons"`
+	}{
+		plainContextData: (*plainContextData)(d),
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	if hasSDKList(raw.LimitedSupportReasons) {
+		if d.LimitedSupportReasons, err = cmv1.UnmarshalLimitedSupportReasonList([]byte(raw.LimitedSupportReasons)); err != nil {
+			return fmt.Errorf("failed to read limited_support_reasons: %w", err)
+		}
+	}
+	if hasSDKList(raw.ServiceLogs) {
+		if d.ServiceLogs, err = v1.UnmarshalLogEntryList([]byte(raw.ServiceLogs)); err != nil {
+			return fmt.Errorf("failed to read service_logs: %w", err)
+		}
+	}
+	if hasSDKList(raw.ClusterEvents) {
+		if d.ClusterEvents, err = v1.UnmarshalLogEntryList([]byte(raw.ClusterEvents)); err != nil {
+			return fmt.Errorf("failed to read cluster_events: %w", err)
+		}
+	}
+	if hasSDKList(raw.Addons) {
+		if d.Addons, err = cmv1.UnmarshalAddOnInstallationList([]byte(raw.Addons)); err != nil {
+			return fmt.Errorf("failed to read addons: %w", err)
+		}
+	}
+	return nil
+}
+
+// hasSDKList returns false for missing, null and empty lists, so they are read back as nil
+func hasSDKList(raw json.RawMessage) bool {
+	trimmed := string(bytes.TrimSpace(raw))
+	return trimmed != "" && trimmed != "null" && trimmed != "[]"
+}
+
+func marshalSDKList(marshal func(io.Writer) error) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	if err := marshal(&buf); err != nil {
+		return nil, err
+	}
+	if buf.Len() == 0 {
+		return json.RawMessage("[]"), nil
+	}
+	return buf.Bytes(), nil
+}
+
+// sortContextData sorts the collected slices, the collectors return them in whatever order the
+// APIs and the goroutines produced them
+func sortContextData(data *contextData) {
+	sort.SliceStable(data.LimitedSupportReasons, func(i, j int) bool {
+		a, b := data.LimitedSupportReasons[i], data.LimitedSupportReasons[j]
+		if !a.CreationTimestamp().Equal(b.CreationTimestamp()) {
+			return a.CreationTimestamp().Before(b.CreationTimestamp())
+		}
+		return a.ID() < b.ID()
+	})
+	sort.SliceStable(data.ServiceLogs, func(i, j int) bool {
+		a, b := data.ServiceLogs[i], data.ServiceLogs[j]
+		if !a.Timestamp().Equal(b.Timestamp()) {
+			return a.Timestamp().After(b.Timestamp())
+		}
+		return a.ID() < b.ID()
+	})
+	sort.SliceStable(data.ClusterEvents, func(i, j int) bool {
+		return data.ClusterEvents[i].Timestamp().After(data.ClusterEvents[j].Timestamp())
+	})
+	sortAddonInstallations(data.Addons)
+	sortJiraIssues(data.JiraIssues)
+	sortJiraIssues(data.SupportExceptions)
+	sort.SliceStable(data.SupportCases, func(i, j int) bool {
+		return data.SupportCases[i].CaseNumber < data.SupportCases[j].CaseNumber
+	})
+	sort.Strings(data.PdServiceIDs)
+	for _, incidents := range data.PdAlerts {
+		sortIncidents(incidents)
+	}
+	for _, trackers := range data.HistoricalAlerts {
+		sortIncidentTrackers(trackers)
+	}
+	sortCloudTrailEvents(data.CloudtrailEvents)
+	sort.SliceStable(data.CloudProviderEvents, func(i, j int) bool {
+		a, b := data.CloudProviderEvents[i], data.CloudProviderEvents[j]
+		if !a.Published.Equal(b.Published) {
+			return a.Published.Before(b.Published)
+		}
+		return a.Title < b.Title
+	})
+}
+
+func sortJiraIssues(issues []jira.Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Key < issues[j].Key
+	})
+}
+
+func urgencyRank(urgency string) int {
+	switch urgency {
+	case "high":
+		return 0
+	case "low":
+		return 1
+	}
+	return 2
+}
+
+// sortIncidents sorts the incidents by urgency, high first, then by creation time
+func sortIncidents(incidents []pd.Incident) {
+	sort.SliceStable(incidents, func(i, j int) bool {
+		a, b := incidents[i], incidents[j]
+		if urgencyRank(a.Urgency) != urgencyRank(b.Urgency) {
+			return urgencyRank(a.Urgency) < urgencyRank(b.Urgency)
+		}
+		// PagerDuty timestamps are RFC3339 in UTC, so they sort lexically
+		if a.CreatedAt != b.CreatedAt {
+			return a.CreatedAt < b.CreatedAt
+		}
+		return a.ID < b.ID
+	})
+}
+
+// sortIncidentTrackers sorts the historical alerts by occurrences, most frequent first
+func sortIncidentTrackers(trackers []*pagerduty.IncidentOccurrenceTracker) {
+	sort.SliceStable(trackers, func(i, j int) bool {
+		if trackers[i].Count != trackers[j].Count {
+			return trackers[i].Count > trackers[j].Count
+		}
+		return trackers[i].IncidentName < trackers[j].IncidentName
+	})
+}
+
+func sortCloudTrailEvents(events []*types.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.EventTime != nil && b.EventTime != nil && !a.EventTime.Equal(*b.EventTime) {
+			return a.EventTime.Before(*b.EventTime)
+		}
+		return stringValue(a.EventId) < stringValue(b.EventId)
+	})
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,68 @@
+package cluster
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+)
+
+func TestSortIncidents(t *testing.T) {
+	incidents := []pd.Incident{
+		{APIObject: pd.APIObject{ID: "low-old"}, Urgency: "low", CreatedAt: "2024-01-01T00:00:00Z"},
+		{APIObject: pd.APIObject{ID: "high-new"}, Urgency: "high", CreatedAt: "2024-01-03T00:00:00Z"},
+		{APIObject: pd.APIObject{ID: "high-old"}, Urgency: "high", CreatedAt: "2024-01-02T00:00:00Z"},
+	}
+	sortIncidents(incidents)
+
+	var got []string
+	for _, incident := range incidents {
+		got = append(got, incident.ID)
+	}
+	if want := []string{"high-old", "high-new", "low-old"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sortIncidents() = %v, want %v", got, want)
+	}
+}
+
+func TestSortIncidentTrackers(t *testing.T) {
+	trackers := []*pagerduty.IncidentOccurrenceTracker{
+		{IncidentName: "b", Count: 1},
+		{IncidentName: "c", Count: 3},
+		{IncidentName: "a", Count: 1},
+	}
+	sortIncidentTrackers(trackers)
+
+	var got []string
+	for _, tracker := range trackers {
+		got = append(got, tracker.IncidentName)
+	}
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sortIncidentTrackers() = %v, want %v", got, want)
+	}
+}
+
+func TestContextDataJSONFields(t *testing.T) {
+	data := &contextData{
+		ClusterName:  "name",
+		PdServiceIDs: []string{"PABC"},
+	}
+	out, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"cluster_name", "pd_service_ids", "limited_support_reasons", "service_logs", "pd_alerts"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("expected the %s field in %s", key, out)
+		}
+	}
+	if _, ok := fields["ClusterName"]; ok {
+		t.Errorf("unexpected Go field name in %s", out)
+	}
+}
@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/links"
+	"github.com/openshift/osdctl/pkg/redact"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	offlineFlagName     = "offline"
+	offlineDataFlagName = "data"
+	// offlineContextFile holds a whole context, as printed by `-o json`, in a data directory
+	offlineContextFile = "context.json"
+)
+
+// completeOffline validates the options of a context read from captured data, nothing is queried
+func (o *contextOptions) completeOffline(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 || o.externalClusterID != "" {
+		return cmdutil.UsageErrorf(cmd, "No cluster identifier is expected with --%s, the cluster is the one of the data", offlineFlagName)
+	}
+	if o.dataPath == "" {
+		return cmdutil.UsageErrorf(cmd, "--%s is required with --%s", offlineDataFlagName, offlineFlagName)
+	}
+	if _, err := os.Stat(o.dataPath); err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed(redact.RedactFlagName) {
+		o.redact = viper.GetBool(redact.RedactConfigKey)
+	}
+	return nil
+}
+
+// loadOfflineContextData reads a captured context. The data is either the `-o json` output of the context, or a
+// directory holding it as context.json and/or one <field>.json file per collector named after the JSON fields of
+// the context, e.g. service_logs.json. The files of the collectors take precedence over context.json.
+func loadOfflineContextData(path string) (*contextData, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var content []byte
+	if info.IsDir() {
+		content, err = readOfflineDataDir(path)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := &contextData{}
+	if err := json.Unmarshal(content, data); err != nil {
+		return nil, fmt.Errorf("failed to read the context data of %s: %w", path, err)
+	}
+	sortContextData(data)
+	data.linkRegistry = offlineLinkRegistry(data)
+	return data, nil
+}
+
+// readOfflineDataDir merges the files of a data directory into a single context JSON object
+func readOfflineDataDir(dir string) ([]byte, error) {
+	fields := map[string]json.RawMessage{}
+
+	content, err := os.ReadFile(filepath.Join(dir, offlineContextFile))
+	if err == nil {
+		if err := json.Unmarshal(content, &fields); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Join(dir, offlineContextFile), err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		name := filepath.Base(file)
+		if name == offlineContextFile {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(content) {
+			return nil, fmt.Errorf("%s isn't valid JSON", file)
+		}
+		fields[strings.TrimSuffix(name, ".json")] = content
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no context data in %s, expected %s or <field>.json files", dir, offlineContextFile)
+	}
+	return json.Marshal(fields)
+}
+
+// offlineLinkRegistry registers the links that can be rebuilt from the data alone
+func offlineLinkRegistry(data *contextData) *links.Registry {
+	registry := links.NewRegistry()
+	for _, id := range data.PdServiceIDs {
+		registry.Add(links.KindPagerDuty, fmt.Sprintf("PagerDuty Service %s", id), fmt.Sprintf("https://redhat.pagerduty.com/service-directory/%s", id))
+	}
+	addJiraLinks(registry, data.JiraIssues)
+	addJiraLinks(registry, data.SupportExceptions)
+	addSupportCaseLinks(registry, data.SupportCases)
+	if data.DyntraceEnvURL != "" {
+		registry.Add(links.KindDynatrace, "Dynatrace Environment", data.DyntraceEnvURL)
+	}
+	return registry
+}
@@ -0,0 +1,92 @@
+package cluster
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/pkg/links"
+)
+
+func TestContextDataRoundTrip(t *testing.T) {
+	serviceLog, err := v1.NewLogEntry().ID("sl-1").Summary("Cluster upgraded").Timestamp(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reason, err := cmv1.NewLimitedSupportReason().ID("ls-1").Summary("Cluster is unreachable").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := &contextData{
+		ClusterName:           "my-cluster",
+		ClusterID:             "abc",
+		ServiceLogs:           []*v1.LogEntry{serviceLog},
+		LimitedSupportReasons: []*cmv1.LimitedSupportReason{reason},
+		PdServiceIDs:          []string{"PABC"},
+	}
+
+	out, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := &contextData{}
+	if err := json.Unmarshal(out, read); err != nil {
+		t.Fatal(err)
+	}
+	if read.ClusterName != "my-cluster" || len(read.PdServiceIDs) != 1 {
+		t.Errorf("unexpected plain fields %+v", read)
+	}
+	if len(read.ServiceLogs) != 1 || read.ServiceLogs[0].Summary() != "Cluster upgraded" {
+		t.Errorf("unexpected service logs %v", read.ServiceLogs)
+	}
+	if len(read.LimitedSupportReasons) != 1 || read.LimitedSupportReasons[0].Summary() != "Cluster is unreachable" {
+		t.Errorf("unexpected limited support reasons %v", read.LimitedSupportReasons)
+	}
+	if read.ClusterEvents != nil || read.Addons != nil {
+		t.Errorf("empty lists should stay empty")
+	}
+}
+
+func TestLoadOfflineContextData(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		offlineContextFile:    `{"cluster_name": "my-cluster", "cluster_id": "abc", "pd_service_ids": ["POLD"]}`,
+		"pd_service_ids.json": `["PABC"]`,
+		"service_logs.json":   `[{"kind": "ClusterLog", "id": "sl-1", "summary": "Cluster upgraded", "timestamp": "2024-03-01T10:00:00Z"}]`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := loadOfflineContextData(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.ClusterName != "my-cluster" || data.ClusterID != "abc" {
+		t.Errorf("unexpected cluster %s -- %s", data.ClusterName, data.ClusterID)
+	}
+	if len(data.ServiceLogs) != 1 || data.ServiceLogs[0].ID() != "sl-1" {
+		t.Errorf("unexpected service logs %v", data.ServiceLogs)
+	}
+	// The files of the collectors take precedence over context.json
+	pagerDutyLinks := data.linkRegistry.Links(links.KindPagerDuty)
+	if len(pagerDutyLinks) != 1 || pagerDutyLinks[0].URL != "https://redhat.pagerduty.com/service-directory/PABC" {
+		t.Errorf("unexpected PagerDuty links %v", pagerDutyLinks)
+	}
+
+	if _, err := loadOfflineContextData(t.TempDir()); err == nil {
+		t.Errorf("loadOfflineContextData() expected an error for an empty directory")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "jira_issues.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadOfflineContextData(dir); err == nil {
+		t.Errorf("loadOfflineContextData() expected an error for invalid JSON")
+	}
+}
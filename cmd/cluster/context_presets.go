@@ -0,0 +1,97 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	contextPresetFlagName   = "preset"
+	contextPresetsConfigKey = "context_presets"
+)
+
+// builtinContextPresets can be overridden, or completed with new presets, in the osdctl config:
+//
+//	context_presets:
+//	  handoff:
+//	    output: short
+//	    days: 7
+var builtinContextPresets = map[string]map[string]interface{}{
+	// Quick overview when handing a cluster over to the next shift
+	"handoff": {
+		"output": shortOutputConfigValue,
+		"days":   7,
+	},
+	// Everything we know about the cluster, including CloudTrail and the alert history
+	"deep-dive": {
+		"output": longOutputConfigValue,
+		"full":   true,
+		"pages":  10,
+	},
+}
+
+// contextPresets returns the built-in presets merged with the ones of the osdctl config
+func contextPresets() map[string]map[string]interface{} {
+	presets := map[string]map[string]interface{}{}
+	for name, preset := range builtinContextPresets {
+		presets[name] = preset
+	}
+	for name, preset := range viper.GetStringMap(contextPresetsConfigKey) {
+		values, ok := preset.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		presets[strings.ToLower(name)] = values
+	}
+	return presets
+}
+
+func contextPresetNames() []string {
+	var names []string
+	for name := range contextPresets() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyContextPreset sets the flags of the named preset. Flags passed on the command line take precedence.
+func applyContextPreset(cmd *cobra.Command, name string) error {
+	preset, ok := contextPresets()[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown preset '%s', expected one of %v", name, contextPresetNames())
+	}
+
+	flags := make([]string, 0, len(preset))
+	for flag := range preset {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+
+	for _, flag := range flags {
+		if flag == contextPresetFlagName {
+			return fmt.Errorf("preset '%s' can't set --%s", name, contextPresetFlagName)
+		}
+		if cmd.Flags().Lookup(flag) == nil {
+			return fmt.Errorf("preset '%s' sets unknown flag --%s", name, flag)
+		}
+		if cmd.Flags().Changed(flag) {
+			continue
+		}
+
+		values, isList := preset[flag].([]interface{})
+		if !isList {
+			values = []interface{}{preset[flag]}
+		}
+		for _, value := range values {
+			if err := cmd.Flags().Set(flag, fmt.Sprint(value)); err != nil {
+				return fmt.Errorf("preset '%s' has an invalid value for --%s: %w", name, flag, err)
+			}
+		}
+	}
+	return nil
+}
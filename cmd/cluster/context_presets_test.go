@@ -0,0 +1,106 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestApplyContextPreset(t *testing.T) {
+	tests := []struct {
+		name       string
+		preset     string
+		config     map[string]interface{}
+		args       []string
+		wantErr    bool
+		wantOutput string
+		wantDays   int
+		wantFull   bool
+		wantTeams  []string
+	}{
+		{
+			name:       "built-in preset",
+			preset:     "handoff",
+			wantOutput: shortOutputConfigValue,
+			wantDays:   7,
+		},
+		{
+			name:       "explicit flags take precedence",
+			preset:     "handoff",
+			args:       []string{"--days", "3"},
+			wantOutput: shortOutputConfigValue,
+			wantDays:   3,
+		},
+		{
+			name:   "preset from config",
+			preset: "Weekly",
+			config: map[string]interface{}{
+				"weekly": map[string]interface{}{"full": true, "days": 7, "team-ids": []interface{}{"PTEAM1", "PTEAM2"}},
+			},
+			wantOutput: longOutputConfigValue,
+			wantDays:   7,
+			wantFull:   true,
+			wantTeams:  []string{"PTEAM1", "PTEAM2"},
+		},
+		{
+			name:   "config overrides built-in preset",
+			preset: "handoff",
+			config: map[string]interface{}{
+				"handoff": map[string]interface{}{"output": jsonOutputConfigValue},
+			},
+			wantOutput: jsonOutputConfigValue,
+			wantDays:   30,
+		},
+		{
+			name:    "unknown preset",
+			preset:  "nope",
+			wantErr: true,
+		},
+		{
+			name:    "unknown flag",
+			preset:  "broken",
+			config:  map[string]interface{}{"broken": map[string]interface{}{"color": "red"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			preset:  "broken",
+			config:  map[string]interface{}{"broken": map[string]interface{}{"days": "many"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			if tt.config != nil {
+				viper.Set(contextPresetsConfigKey, tt.config)
+			}
+
+			cmd := newCmdContext()
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatalf("failed to parse flags: %v", err)
+			}
+
+			err := applyContextPreset(cmd, tt.preset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyContextPreset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			output, _ := cmd.Flags().GetString("output")
+			days, _ := cmd.Flags().GetInt("days")
+			full, _ := cmd.Flags().GetBool("full")
+			teams, _ := cmd.Flags().GetStringArray("team-ids")
+			if output != tt.wantOutput || days != tt.wantDays || full != tt.wantFull {
+				t.Errorf("got output=%s days=%d full=%t, want output=%s days=%d full=%t", output, days, full, tt.wantOutput, tt.wantDays, tt.wantFull)
+			}
+			if len(teams) != len(tt.wantTeams) {
+				t.Errorf("got team-ids %v, want %v", teams, tt.wantTeams)
+			}
+		})
+	}
+}
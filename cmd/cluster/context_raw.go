@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/rawdump"
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+// contextCollectorOf names the collector of the context which sent the request after the JSON field of its
+// data, e.g. service_logs, from the API and the query. The collectors run concurrently on shared clients, the
+// request itself is all there is to tell them apart.
+func contextCollectorOf(req *http.Request) string {
+	path, query := req.URL.Path, req.URL.Query()
+	switch {
+	case strings.HasPrefix(path, "/api/service_logs/"):
+		if strings.Contains(query.Get("search"), "service_name != 'SREManualAction'") {
+			return "cluster_events"
+		}
+		return "service_logs"
+	case strings.HasSuffix(path, "/limited_support_reasons"):
+		return "limited_support_reasons"
+	case strings.Contains(path, "/addon"):
+		return "addons"
+	case strings.HasPrefix(path, "/api/"):
+		return "ocm"
+	case strings.Contains(path, "/rest/api/") && strings.HasSuffix(path, "/search"):
+		if strings.Contains(query.Get("jql"), fmt.Sprintf(`project = "%s"`, utils.JiraProject(utils.JiraSupportExceptionsProject))) {
+			return "support_exceptions"
+		}
+		return "jira_issues"
+	case strings.Contains(path, "/rest/api/"):
+		return "jira"
+	case path == "/services":
+		return "pd_service_ids"
+	case path == "/incidents" || strings.HasPrefix(path, "/incidents/"):
+		// Only the history lists the resolved incidents
+		if slices.Contains(query["statuses[]"], "resolved") {
+			return "historical_alerts"
+		}
+		return "pd_alerts"
+	}
+	return ""
+}
+
+// saveRawContext writes the data as osdctl interpreted it next to the raw responses, in the format
+// --offline reads back
+func saveRawContext(data *contextData) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(rawdump.Dir(), offlineContextFile), content, 0o600)
+}
@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextCollectorOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{
+			name:     "service logs",
+			url:      "https://api.openshift.com/api/service_logs/v1/clusters/cluster_logs?search=cluster_uuid+%3D+%27abc%27",
+			expected: "service_logs",
+		},
+		{
+			name:     "cluster events",
+			url:      "https://api.openshift.com/api/service_logs/v1/clusters/cluster_logs?search=service_name+%21%3D+%27SREManualAction%27+and+timestamp+%3E%3D+%272024-03-12T00%3A00%3A00Z%27",
+			expected: "cluster_events",
+		},
+		{
+			name:     "limited support reasons",
+			url:      "https://api.openshift.com/api/clusters_mgmt/v1/clusters/abc/limited_support_reasons",
+			expected: "limited_support_reasons",
+		},
+		{
+			name:     "addons",
+			url:      "https://api.openshift.com/api/clusters_mgmt/v1/clusters/abc/addons",
+			expected: "addons",
+		},
+		{
+			name:     "cluster",
+			url:      "https://api.openshift.com/api/clusters_mgmt/v1/clusters/abc",
+			expected: "ocm",
+		},
+		{
+			name:     "support exceptions",
+			url:      "https://issues.redhat.com/rest/api/2/search?jql=project+%3D+%22Support+Exceptions%22+AND+type+%3D+Story",
+			expected: "support_exceptions",
+		},
+		{
+			name:     "jira issues",
+			url:      "https://issues.redhat.com/rest/api/2/search?jql=project+%3D+OHSS",
+			expected: "jira_issues",
+		},
+		{
+			name:     "pagerduty services",
+			url:      "https://api.pagerduty.com/services?query=abc",
+			expected: "pd_service_ids",
+		},
+		{
+			name:     "pagerduty alerts",
+			url:      "https://api.pagerduty.com/incidents?service_ids%5B%5D=P123&statuses%5B%5D=triggered&statuses%5B%5D=acknowledged",
+			expected: "pd_alerts",
+		},
+		{
+			name:     "pagerduty history",
+			url:      "https://api.pagerduty.com/incidents?service_ids%5B%5D=P123&statuses%5B%5D=resolved&statuses%5B%5D=triggered",
+			expected: "historical_alerts",
+		},
+		{
+			name: "unknown",
+			url:  "https://console.redhat.com/api/status",
+			// Anything under /api/ is assumed to be OCM
+			expected: "ocm",
+		},
+		{
+			name: "other",
+			url:  "https://status.redhat.com/index.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if collector := contextCollectorOf(httptest.NewRequest("GET", tt.url, nil)); collector != tt.expected {
+				t.Errorf("contextCollectorOf() = %q, want %q", collector, tt.expected)
+			}
+		})
+	}
+}
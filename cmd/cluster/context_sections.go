@@ -0,0 +1,257 @@
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	contextSectionsFlagName  = "sections"
+	contextSectionsConfigKey = "context_sections"
+	contextTemplateConfigKey = "context_template"
+	// contextHeaderSection is always printed first by the long output, templates can place it anywhere
+	contextHeaderSection = "header"
+)
+
+// contextSection is a part of the long output
+type contextSection struct {
+	name string
+	// fullOnly sections are only printed with --full, unless a template asks for them
+	fullOnly bool
+	print    func(o *contextOptions, data *contextData)
+}
+
+// contextSections is the default layout of the long output, in order
+var contextSections = []contextSection{
+	{name: "description", print: func(o *contextOptions, data *contextData) {
+		fmt.Println(strings.TrimSpace(data.Description))
+	}},
+	{name: "limited-support", print: func(o *contextOptions, data *contextData) {
+		utils.PrintLimitedSupportReasons(data.LimitedSupportReasons)
+	}},
+	{name: "addons", print: func(o *contextOptions, data *contextData) {
+		printAddonHealth(data.Addons)
+	}},
+	{name: "clustersync", print: func(o *contextOptions, data *contextData) {
+		printClusterSync(data, o.verbose)
+	}},
+	{name: "support-exceptions", print: func(o *contextOptions, data *contextData) {
+		printJIRASupportExceptions(data.SupportExceptions)
+		printJiraTotal(len(data.SupportExceptions), data.SupportExceptionsTotal)
+	}},
+	{name: "service-logs", print: func(o *contextOptions, data *contextData) {
+		utils.PrintServiceLogs(data.ServiceLogs, o.verbose, o.noGroup, o.days)
+	}},
+	{name: "cluster-events", print: func(o *contextOptions, data *contextData) {
+		fmt.Println(delimiter + "Latest Cluster Events")
+		printClusterEvents(data.ClusterEvents)
+	}},
+	{name: "jira-issues", print: func(o *contextOptions, data *contextData) {
+		utils.PrintJiraIssues(data.JiraIssues)
+		printJiraTotal(len(data.JiraIssues), data.JiraIssuesTotal)
+	}},
+	{name: "support-cases", print: func(o *contextOptions, data *contextData) {
+		printSupportCases(data.SupportCases)
+	}},
+	{name: "pagerduty-alerts", print: func(o *contextOptions, data *contextData) {
+		utils.PrintPDAlerts(data.PdAlerts, data.PdServiceIDs, o.alertTableOptions, o.wide)
+	}},
+	{name: "cloud-provider-events", print: func(o *contextOptions, data *contextData) {
+		printCloudProviderEvents(data)
+	}},
+	{name: "slo", print: func(o *contextOptions, data *contextData) {
+		printSLOs(data, o.days)
+	}},
+	{name: "pagerduty-history", fullOnly: true, print: func(o *contextOptions, data *contextData) {
+		printHistoricalPDAlertSummary(data.HistoricalAlerts, data.PdServiceIDs, o.days)
+	}},
+	{name: "cloudtrail", fullOnly: true, print: func(o *contextOptions, data *contextData) {
+		printCloudTrailLogs(data.CloudtrailEvents)
+	}},
+	{name: "security-findings", fullOnly: true, print: func(o *contextOptions, data *contextData) {
+		printSecurityFindings(data)
+	}},
+	{name: "links", print: func(o *contextOptions, data *contextData) {
+		o.printOtherLinks(data)
+	}},
+	{name: "dynatrace", print: func(o *contextOptions, data *contextData) {
+		printDynatraceEnvURL(data)
+	}},
+	{name: "freshness", print: func(o *contextOptions, data *contextData) {
+		printFreshness(data)
+	}},
+}
+
+func contextSectionNames() []string {
+	names := make([]string, 0, len(contextSections))
+	for _, section := range contextSections {
+		names = append(names, section.name)
+	}
+	return names
+}
+
+func lookupContextSection(name string) (contextSection, bool) {
+	if name == contextHeaderSection {
+		return contextSection{name: contextHeaderSection, print: func(o *contextOptions, data *contextData) {
+			data.printClusterHeader()
+		}}, true
+	}
+	for _, section := range contextSections {
+		if section.name == name {
+			return section, true
+		}
+	}
+	return contextSection{}, false
+}
+
+// selectContextSections returns the sections to print in the given order, all of them when none is given
+func selectContextSections(names []string) ([]contextSection, error) {
+	if len(names) == 0 {
+		return contextSections, nil
+	}
+	selected := make([]contextSection, 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		section, ok := lookupContextSection(name)
+		if !ok || name == contextHeaderSection {
+			return nil, fmt.Errorf("unknown context section '%s', expected some of %v", name, contextSectionNames())
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		selected = append(selected, section)
+	}
+	return selected, nil
+}
+
+// completeLayout reads the sections and the template of the long output, flags take precedence over the config
+func (o *contextOptions) completeLayout(cmd *cobra.Command) error {
+	names := o.sectionNames
+	if !cmd.Flags().Changed(contextSectionsFlagName) {
+		names = viper.GetStringSlice(contextSectionsConfigKey)
+	}
+	sections, err := selectContextSections(names)
+	if err != nil {
+		return err
+	}
+	o.sections = sections
+	if err := o.completeDisabledSections(); err != nil {
+		return err
+	}
+
+	if path := viper.GetString(contextTemplateConfigKey); path != "" && o.output == longOutputConfigValue {
+		o.template, err = loadContextTemplate(path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadContextTemplate parses the Go template of the long output. Besides the fields of contextData, the
+// template can print any section, e.g. {{ section "service-logs" }}
+func loadContextTemplate(path string) (*template.Template, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s '%s': %w", contextTemplateConfigKey, path, err)
+	}
+	// The section function is bound to the options and the data when rendering
+	tmpl, err := template.New(filepath.Base(path)).Funcs(template.FuncMap{
+		"section": func(string) (string, error) { return "", nil },
+	}).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s '%s': %w", contextTemplateConfigKey, path, err)
+	}
+	return tmpl, nil
+}
+
+// renderContextTemplate executes the template of the long output against the data
+func (o *contextOptions) renderContextTemplate(w io.Writer, data *contextData) error {
+	tmpl := o.template.Funcs(template.FuncMap{
+		"section": func(name string) (string, error) {
+			section, ok := lookupContextSection(name)
+			if !ok {
+				return "", fmt.Errorf("unknown context section '%s', expected %s or one of %v", name, contextHeaderSection, contextSectionNames())
+			}
+			return captureStdout(func() { o.printSection(section, data) })
+		},
+	})
+	return tmpl.Execute(w, data)
+}
+
+// printSections prints the cluster header and the selected sections, separated by blank lines
+func (o *contextOptions) printSections(data *contextData) {
+	sections := o.sections
+	if sections == nil {
+		sections = contextSections
+	}
+
+	// The first section follows the header directly
+	data.printClusterHeader()
+	first := true
+	for _, section := range sections {
+		if (section.fullOnly && !o.full) || o.disabledSections[section.name] {
+			continue
+		}
+		if !first {
+			fmt.Println()
+		}
+		first = false
+		o.printSection(section, data)
+	}
+}
+
+// printSection prints the section, or a line telling why it was skipped. Disabled sections print nothing.
+func (o *contextOptions) printSection(section contextSection, data *contextData) {
+	if o.disabledSections[section.name] {
+		return
+	}
+	if reason, skipped := data.Skipped[section.name]; skipped {
+		fmt.Printf("%s%s: skipped (%s)\n", delimiter, section.name, reason)
+		return
+	}
+	section.print(o, data)
+}
+
+// captureStdout returns what print writes to os.Stdout, since the sections print with fmt.Print*
+func captureStdout(print func()) (string, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	original := os.Stdout
+	os.Stdout = writer
+
+	var captured bytes.Buffer
+	done := make(chan error)
+	go func() {
+		_, err := io.Copy(&captured, reader)
+		done <- err
+	}()
+
+	print()
+
+	os.Stdout = original
+	_ = writer.Close()
+	err = <-done
+	_ = reader.Close()
+	return captured.String(), err
+}
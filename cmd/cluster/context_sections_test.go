@@ -0,0 +1,92 @@
+package cluster
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSelectContextSections(t *testing.T) {
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "default layout",
+			want: contextSectionNames(),
+		},
+		{
+			name:  "re-ordered and dropped",
+			names: []string{"Service-Logs", " limited-support", "service-logs"},
+			want:  []string{"service-logs", "limited-support"},
+		},
+		{
+			name:    "unknown section",
+			names:   []string{"service-logs", "alerts"},
+			wantErr: true,
+		},
+		{
+			name:    "header isn't optional",
+			names:   []string{contextHeaderSection},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections, err := selectContextSections(tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectContextSections() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, section := range sections {
+				got = append(got, section.name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("selectContextSections() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("selectContextSections() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderContextTemplate(t *testing.T) {
+	tmpl, err := loadContextTemplate(writeTemplate(t, "{{ section \"header\" }}Version: {{ .ClusterVersion }}\n{{ section \"description\" }}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := &contextOptions{template: tmpl}
+	data := &contextData{ClusterName: "my-cluster", ClusterID: "abc", ClusterVersion: "4.15.3", Description: "  Cluster is ready\n"}
+	var out bytes.Buffer
+	if err := o.renderContextTemplate(&out, data); err != nil {
+		t.Fatal(err)
+	}
+	want := "=================\nmy-cluster -- abc\n=================\nVersion: 4.15.3\nCluster is ready\n"
+	if out.String() != want {
+		t.Errorf("renderContextTemplate() = %q, want %q", out.String(), want)
+	}
+
+	o.template, err = loadContextTemplate(writeTemplate(t, "{{ section \"alerts\" }}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.renderContextTemplate(&out, data); err == nil {
+		t.Errorf("renderContextTemplate() expected an error for an unknown section")
+	}
+}
+
+func writeTemplate(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "context.tmpl")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
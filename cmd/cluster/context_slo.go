@@ -0,0 +1,58 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/telemetry"
+)
+
+func printSLOs(data *contextData, days int) {
+	writeSLOs(os.Stdout, data.SLOs, days)
+}
+
+// writeSLOs prints the error budgets of the availability SLOs, so the readers know whether an incident is
+// impacting the SLOs of the cluster
+func writeSLOs(w io.Writer, slos []telemetry.SLOStatus, days int) {
+	fmt.Fprintf(w, "%sSLOs in the past %d days\n", delimiter, days)
+	if len(slos) == 0 {
+		fmt.Fprintln(w, "None")
+		return
+	}
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"SLO", "OBJECTIVE", "SLI", "BURN RATE", "BUDGET LEFT", "BURN RATE (LAST 1H)", "STATUS"})
+	for _, slo := range slos {
+		if slo.Error != "" {
+			table.AddRow([]string{slo.Name, formatRatio(slo.Objective), "-", "-", "-", "-", slo.Error})
+			continue
+		}
+		status := "OK"
+		switch {
+		case slo.Impacting():
+			status = "SLO-impacting"
+		case slo.BudgetRemaining <= 0:
+			status = "Budget exhausted"
+		}
+		table.AddRow([]string{
+			slo.Name,
+			formatRatio(slo.Objective),
+			formatRatio(slo.SLI),
+			fmt.Sprintf("%.2fx", slo.BurnRate),
+			formatRatio(slo.BudgetRemaining),
+			fmt.Sprintf("%.2fx", slo.CurrentBurnRate),
+			status,
+		})
+	}
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the SLOs: %v\n", err)
+	}
+}
+
+// formatRatio formats a ratio as a percentage, with enough decimals for objectives such as 99.95%
+func formatRatio(ratio float64) string {
+	return fmt.Sprintf("%.2f%%", ratio*100)
+}
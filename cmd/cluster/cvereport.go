@@ -0,0 +1,170 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/provider/errata"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type cveReportOptions struct {
+	clusterID   string
+	minSeverity string
+	output      string
+	arch        string
+}
+
+// cveReport is the JSON output of the cve-report command
+type cveReport struct {
+	ClusterID  string             `json:"cluster_id"`
+	Version    string             `json:"version"`
+	Channel    string             `json:"channel"`
+	Advisories []*errata.Advisory `json:"advisories"`
+	Counts     map[string]int     `json:"cve_counts"`
+	FixedIn    string             `json:"fixed_in,omitempty"`
+	Summary    string             `json:"summary"`
+}
+
+func newCmdCveReport() *cobra.Command {
+	ops := &cveReportOptions{}
+	cveReportCmd := &cobra.Command{
+		Use:   "cve-report <cluster-id>",
+		Short: "List the security advisories and CVEs fixed in newer z-streams than the cluster's version",
+		Long: `List the security advisories (RHSA) shipped by the releases of the cluster's channel newer than its
+current version in the same minor stream, with the CVEs they fix. This is the exposure the customer
+removes by upgrading to the latest z-stream, and helps when advising an urgent upgrade.
+
+The releases come from the OpenShift upgrade graph and the CVEs from the Red Hat Security Data API.`,
+		Example: `  # Critical and important CVEs a cluster is exposed to
+  osdctl cluster cve-report <cluster-id>
+
+  # Every CVE, as JSON
+  osdctl cluster cve-report <cluster-id> --min-severity low -o json`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	cveReportCmd.Flags().StringVar(&ops.minSeverity, "min-severity", "important", fmt.Sprintf("Only show CVEs of this severity or higher, one of %v", errata.Severities()))
+	cveReportCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Valid formats are ['table', 'json']")
+	cveReportCmd.Flags().StringVar(&ops.arch, "arch", "amd64", "Release architecture of the cluster")
+
+	return cveReportCmd
+}
+
+func (o *cveReportOptions) run() error {
+	if !errata.IsValidSeverity(o.minSeverity) {
+		return fmt.Errorf("unknown severity '%s', expected one of %v", o.minSeverity, errata.Severities())
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("unknown output format '%s'", o.output)
+	}
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	version := cluster.Version().RawID()
+	major, minor, _ := strings.Cut(version, ".")
+	minor, _, _ = strings.Cut(minor, ".")
+	channelGroup := cluster.Version().ChannelGroup()
+	if channelGroup == "" {
+		channelGroup = "stable"
+	}
+	channel := fmt.Sprintf("%s-%s.%s", channelGroup, major, minor)
+
+	advisories, err := errata.NewClient().WithArch(o.arch).GetMissingAdvisories(channel, version)
+	if err != nil {
+		return err
+	}
+
+	report := newCveReport(cluster.ID(), version, channel, advisories, o.minSeverity)
+	if o.output == "json" {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	fmt.Printf("Cluster %s runs %s (channel %s)\n\n", cluster.ID(), version, channel)
+	if len(report.Advisories) > 0 {
+		table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+		table.AddRow([]string{"ADVISORY", "FIXED IN", "SEVERITY", "CVSS3", "CVE", "DESCRIPTION"})
+		for _, advisory := range report.Advisories {
+			for _, cve := range advisory.CVEs {
+				table.AddRow([]string{advisory.ID, advisory.FixedIn, cve.Severity, fmt.Sprintf("%.1f", cve.CVSS3Score), cve.ID, cve.Description})
+			}
+		}
+		table.AddRow([]string{})
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+	fmt.Println(report.Summary)
+	return nil
+}
+
+// newCveReport keeps the CVEs of at least minSeverity, and the advisories fixing any of them
+func newCveReport(clusterID string, version string, channel string, advisories []*errata.Advisory, minSeverity string) *cveReport {
+	report := &cveReport{
+		ClusterID: clusterID,
+		Version:   version,
+		Channel:   channel,
+		Counts:    map[string]int{},
+	}
+
+	seen := map[string]bool{}
+	for _, advisory := range advisories {
+		var cves []*errata.CVE
+		for _, cve := range advisory.CVEs {
+			if errata.SeverityRank(cve.Severity) < errata.SeverityRank(minSeverity) {
+				continue
+			}
+			cves = append(cves, cve)
+			// The same CVE can be fixed in several components, count it once
+			if !seen[cve.ID] {
+				seen[cve.ID] = true
+				report.Counts[cve.Severity]++
+			}
+		}
+		if len(cves) == 0 {
+			continue
+		}
+		report.Advisories = append(report.Advisories, &errata.Advisory{ID: advisory.ID, URL: advisory.URL, FixedIn: advisory.FixedIn, CVEs: cves})
+		// Advisories are sorted by release, the last one fixes everything
+		report.FixedIn = advisory.FixedIn
+	}
+
+	if len(report.Advisories) == 0 {
+		report.Summary = fmt.Sprintf("No known CVE of severity %s or higher is fixed in a newer %s release", minSeverity, channel)
+		return report
+	}
+
+	var counts []string
+	severities := errata.Severities()
+	for i := len(severities) - 1; i >= 0; i-- {
+		if count := report.Counts[severities[i]]; count > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", count, severities[i]))
+		}
+	}
+	report.Summary = fmt.Sprintf("The cluster is exposed to %s CVEs, upgrade to %s or later to fix them", strings.Join(counts, ", "), report.FixedIn)
+	return report
+}
@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/openshift/osdctl/pkg/provider/errata"
+)
+
+func TestNewCveReport(t *testing.T) {
+	advisories := []*errata.Advisory{
+		{ID: "RHSA-1", FixedIn: "4.14.10", CVEs: []*errata.CVE{
+			{ID: "CVE-1", Severity: "important"},
+			{ID: "CVE-2", Severity: "low"},
+		}},
+		{ID: "RHSA-2", FixedIn: "4.14.11", CVEs: []*errata.CVE{
+			{ID: "CVE-3", Severity: "moderate"},
+		}},
+		{ID: "RHSA-3", FixedIn: "4.14.12", CVEs: []*errata.CVE{
+			{ID: "CVE-4", Severity: "critical"},
+			{ID: "CVE-1", Severity: "important"},
+		}},
+	}
+
+	tests := []struct {
+		name           string
+		minSeverity    string
+		wantAdvisories int
+		wantCounts     map[string]int
+		wantFixedIn    string
+		wantSummary    string
+	}{
+		{
+			name:           "important and above",
+			minSeverity:    "important",
+			wantAdvisories: 2,
+			wantCounts:     map[string]int{"critical": 1, "important": 1},
+			wantFixedIn:    "4.14.12",
+			wantSummary:    "The cluster is exposed to 1 critical, 1 important CVEs, upgrade to 4.14.12 or later to fix them",
+		},
+		{
+			name:           "everything",
+			minSeverity:    "low",
+			wantAdvisories: 3,
+			wantCounts:     map[string]int{"critical": 1, "important": 1, "moderate": 1, "low": 1},
+			wantFixedIn:    "4.14.12",
+		},
+		{
+			name:           "nothing critical enough",
+			minSeverity:    "critical",
+			wantAdvisories: 1,
+			wantCounts:     map[string]int{"critical": 1},
+			wantFixedIn:    "4.14.12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := newCveReport("abc", "4.14.9", "stable-4.14", advisories, tt.minSeverity)
+			if len(report.Advisories) != tt.wantAdvisories {
+				t.Errorf("got %d advisories, want %d", len(report.Advisories), tt.wantAdvisories)
+			}
+			if len(report.Counts) != len(tt.wantCounts) {
+				t.Errorf("got counts %v, want %v", report.Counts, tt.wantCounts)
+			}
+			for severity, count := range tt.wantCounts {
+				if report.Counts[severity] != count {
+					t.Errorf("got %d %s CVEs, want %d", report.Counts[severity], severity, count)
+				}
+			}
+			if report.FixedIn != tt.wantFixedIn {
+				t.Errorf("fixed in %s, want %s", report.FixedIn, tt.wantFixedIn)
+			}
+			if tt.wantSummary != "" && report.Summary != tt.wantSummary {
+				t.Errorf("summary = %q, want %q", report.Summary, tt.wantSummary)
+			}
+		})
+	}
+
+	empty := newCveReport("abc", "4.14.12", "stable-4.14", nil, "important")
+	if len(empty.Advisories) != 0 || empty.FixedIn != "" {
+		t.Errorf("expected an empty report, got %+v", empty)
+	}
+}
@@ -0,0 +1,314 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// clusterDiffMissing is the value of a field only one of the clusters has, e.g. an add-on
+	clusterDiffMissing = "<none>"
+	clusterDiffMarker  = "*"
+)
+
+type diffOptions struct {
+	clusterIDs []string
+	output     string
+	all        bool
+}
+
+// clusterConfig is the configuration of a cluster flattened by field, e.g. "network.type" or
+// "machine_pools.worker.instance_type", so two clusters can be compared field by field
+type clusterConfig map[string]string
+
+// clusterDiffEntry is a field of the compared configurations
+type clusterDiffEntry struct {
+	Field   string `json:"field"`
+	A       string `json:"a"`
+	B       string `json:"b"`
+	Differs bool   `json:"differs"`
+}
+
+func newCmdDiff() *cobra.Command {
+	ops := &diffOptions{}
+	diffCmd := &cobra.Command{
+		Use:   "diff <cluster-a> <cluster-b>",
+		Short: "Compare the OCM configuration of two clusters",
+		Long: `Compare the OCM configuration of two clusters: version, cloud and network settings, machine pools (node pools
+of hosted clusters), add-ons and labels. Only the differing fields are listed unless --all is passed, in which case
+the differing fields are marked with a *.
+
+The identifiers of the clusters (IDs, names, domains, subnets...) always differ and are left out.`,
+		Example: `  # Why does it work on cluster A but not on B?
+  osdctl cluster diff <cluster-a> <cluster-b>
+
+  # Staging/production parity, every field
+  osdctl cluster diff <staging-cluster> <production-cluster> --all`,
+		Args:              cobra.ExactArgs(2),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterIDs = args
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	diffCmd.Flags().StringVarP(&ops.output, "output", "o", "text", "Output format, one of text or json")
+	diffCmd.Flags().BoolVar(&ops.all, "all", false, "List the identical fields too")
+
+	return diffCmd
+}
+
+func (o *diffOptions) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output format '%s', expected text or json", o.output)
+	}
+	return nil
+}
+
+func (o *diffOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	var names []string
+	var configs []clusterConfig
+	for _, clusterID := range o.clusterIDs {
+		cluster, err := utils.GetClusterAnyStatus(ocmClient, clusterID)
+		if err != nil {
+			return err
+		}
+		config, err := fetchClusterConfig(ocmClient, cluster)
+		if err != nil {
+			return err
+		}
+		names = append(names, cluster.Name())
+		configs = append(configs, config)
+	}
+	if names[0] == names[1] {
+		names = o.clusterIDs
+	}
+
+	entries := diffClusterConfigs(configs[0], configs[1], o.all)
+	if o.output == "json" {
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	return printClusterDiff(entries, names[0], names[1], o.all)
+}
+
+// fetchClusterConfig returns the configuration of the cluster along with its pools, add-ons and labels
+func fetchClusterConfig(ocmClient *sdk.Connection, cluster *cmv1.Cluster) (clusterConfig, error) {
+	config := clusterConfigOf(cluster)
+	clusterResource := ocmClient.ClustersMgmt().V1().Clusters().Cluster(cluster.ID())
+
+	if cluster.Hypershift().Enabled() {
+		response, err := clusterResource.NodePools().List().Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the node pools of cluster %s: %w", cluster.ID(), err)
+		}
+		for _, pool := range response.Items().Slice() {
+			addNodePoolConfig(config, pool)
+		}
+	} else {
+		response, err := clusterResource.MachinePools().List().Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the machine pools of cluster %s: %w", cluster.ID(), err)
+		}
+		for _, pool := range response.Items().Slice() {
+			addMachinePoolConfig(config, pool)
+		}
+	}
+
+	installations, err := fetchAddonInstallations(ocmClient, cluster.ID())
+	if err != nil {
+		return nil, err
+	}
+	for _, installation := range installations {
+		config["addons."+addonName(installation)] = fmt.Sprintf("%s (%s)", installation.AddonVersion().ID(), installation.State())
+	}
+
+	labels, err := clusterResource.ExternalConfiguration().Labels().List().Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the labels of cluster %s: %w", cluster.ID(), err)
+	}
+	for _, label := range labels.Items().Slice() {
+		config["labels."+label.Key()] = label.Value()
+	}
+	subscriptionLabels, err := ocmClient.AccountsMgmt().V1().Subscriptions().Subscription(cluster.Subscription().ID()).Labels().List().Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the subscription labels of cluster %s: %w", cluster.ID(), err)
+	}
+	for _, label := range subscriptionLabels.Items().Slice() {
+		config["subscription_labels."+label.Key()] = label.Value()
+	}
+	return config, nil
+}
+
+// clusterConfigOf flattens the settings of the cluster itself
+func clusterConfigOf(cluster *cmv1.Cluster) clusterConfig {
+	config := clusterConfig{
+		"version":                          cluster.Version().RawID(),
+		"channel_group":                    cluster.Version().ChannelGroup(),
+		"state":                            string(cluster.State()),
+		"product":                          cluster.Product().ID(),
+		"billing_model":                    string(cluster.BillingModel()),
+		"cloud_provider":                   cluster.CloudProvider().ID(),
+		"region":                           cluster.Region().ID(),
+		"multi_az":                         strconv.FormatBool(cluster.MultiAZ()),
+		"ccs":                              strconv.FormatBool(cluster.CCS().Enabled()),
+		"hypershift":                       strconv.FormatBool(cluster.Hypershift().Enabled()),
+		"fips":                             strconv.FormatBool(cluster.FIPS()),
+		"etcd_encryption":                  strconv.FormatBool(cluster.EtcdEncryption()),
+		"disable_user_workload_monitoring": strconv.FormatBool(cluster.DisableUserWorkloadMonitoring()),
+		"api.listening":                    string(cluster.API().Listening()),
+		"network.type":                     cluster.Network().Type(),
+		"network.machine_cidr":             cluster.Network().MachineCIDR(),
+		"network.service_cidr":             cluster.Network().ServiceCIDR(),
+		"network.pod_cidr":                 cluster.Network().PodCIDR(),
+		"network.host_prefix":              strconv.Itoa(cluster.Network().HostPrefix()),
+		"proxy.http_proxy":                 configuredOrNot(cluster.Proxy().HTTPProxy()),
+		"proxy.https_proxy":                configuredOrNot(cluster.Proxy().HTTPSProxy()),
+		"proxy.no_proxy":                   cluster.Proxy().NoProxy(),
+		"proxy.additional_trust_bundle":    configuredOrNot(cluster.AdditionalTrustBundle()),
+	}
+	if aws, ok := cluster.GetAWS(); ok {
+		config["aws.private_link"] = strconv.FormatBool(aws.PrivateLink())
+		config["aws.sts"] = strconv.FormatBool(aws.STS().Enabled())
+	}
+	return config
+}
+
+func addMachinePoolConfig(config clusterConfig, pool *cmv1.MachinePool) {
+	replicas := strconv.Itoa(pool.Replicas())
+	if autoscaling, ok := pool.GetAutoscaling(); ok {
+		replicas = fmt.Sprintf("%d-%d (autoscaling)", autoscaling.MinReplicas(), autoscaling.MaxReplicas())
+	}
+	taints := make([]string, 0, len(pool.Taints()))
+	for _, taint := range pool.Taints() {
+		taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect()))
+	}
+	addPoolConfig(config, "machine_pools."+pool.ID(), pool.InstanceType(), replicas, strings.Join(pool.AvailabilityZones(), ","), pool.Labels(), taints)
+}
+
+func addNodePoolConfig(config clusterConfig, pool *cmv1.NodePool) {
+	replicas := strconv.Itoa(pool.Replicas())
+	if autoscaling, ok := pool.GetAutoscaling(); ok {
+		replicas = fmt.Sprintf("%d-%d (autoscaling)", autoscaling.MinReplica(), autoscaling.MaxReplica())
+	}
+	taints := make([]string, 0, len(pool.Taints()))
+	for _, taint := range pool.Taints() {
+		taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect()))
+	}
+	prefix := "node_pools." + pool.ID()
+	addPoolConfig(config, prefix, pool.AWSNodePool().InstanceType(), replicas, pool.AvailabilityZone(), pool.Labels(), taints)
+	config[prefix+".version"] = pool.Version().RawID()
+}
+
+// addPoolConfig flattens the settings shared by the machine pools and the node pools
+func addPoolConfig(config clusterConfig, prefix string, instanceType string, replicas string, zones string, labels map[string]string, taints []string) {
+	config[prefix+".instance_type"] = instanceType
+	config[prefix+".replicas"] = replicas
+	config[prefix+".availability_zones"] = zones
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	config[prefix+".labels"] = strings.Join(pairs, ",")
+	sort.Strings(taints)
+	config[prefix+".taints"] = strings.Join(taints, ",")
+}
+
+// configuredOrNot hides values which are secret or differ between any two clusters, only whether they are
+// set matters
+func configuredOrNot(value string) string {
+	if value == "" {
+		return "not configured"
+	}
+	return "configured"
+}
+
+// diffClusterConfigs compares the configurations field by field, sorted by field. Only the differing
+// fields are returned unless all is set.
+func diffClusterConfigs(a clusterConfig, b clusterConfig, all bool) []clusterDiffEntry {
+	fields := make(map[string]bool, len(a))
+	for field := range a {
+		fields[field] = true
+	}
+	for field := range b {
+		fields[field] = true
+	}
+
+	entries := make([]clusterDiffEntry, 0, len(fields))
+	for field := range fields {
+		valueA, okA := a[field]
+		valueB, okB := b[field]
+		if !okA {
+			valueA = clusterDiffMissing
+		}
+		if !okB {
+			valueB = clusterDiffMissing
+		}
+		differs := valueA != valueB
+		if !differs && !all {
+			continue
+		}
+		entries = append(entries, clusterDiffEntry{Field: field, A: valueA, B: valueB, Differs: differs})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Field < entries[j].Field })
+	return entries
+}
+
+func printClusterDiff(entries []clusterDiffEntry, nameA string, nameB string, all bool) error {
+	differences := 0
+	for _, entry := range entries {
+		if entry.Differs {
+			differences++
+		}
+	}
+	if differences == 0 && !all {
+		fmt.Println("The configurations of the clusters are identical")
+		return nil
+	}
+
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	header := []string{"FIELD", nameA, nameB}
+	if all {
+		header = append([]string{""}, header...)
+	}
+	table.AddRow(header)
+	for _, entry := range entries {
+		row := []string{entry.Field, entry.A, entry.B}
+		if all {
+			marker := ""
+			if entry.Differs {
+				marker = clusterDiffMarker
+			}
+			row = append([]string{marker}, row...)
+		}
+		table.AddRow(row)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d differences\n", differences)
+	return nil
+}
@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffClusterConfigs(t *testing.T) {
+	a := clusterConfig{
+		"version":                     "4.15.3",
+		"network.type":                "OVNKubernetes",
+		"machine_pools.worker.labels": "",
+		"addons.managed-odh":          "2.8.0 (ready)",
+	}
+	b := clusterConfig{
+		"version":                      "4.14.9",
+		"network.type":                 "OVNKubernetes",
+		"machine_pools.worker.labels":  "",
+		"machine_pools.infra.replicas": "3",
+	}
+
+	tests := []struct {
+		name string
+		all  bool
+		want []clusterDiffEntry
+	}{
+		{
+			name: "differences only",
+			want: []clusterDiffEntry{
+				{Field: "addons.managed-odh", A: "2.8.0 (ready)", B: clusterDiffMissing, Differs: true},
+				{Field: "machine_pools.infra.replicas", A: clusterDiffMissing, B: "3", Differs: true},
+				{Field: "version", A: "4.15.3", B: "4.14.9", Differs: true},
+			},
+		},
+		{
+			name: "all fields",
+			all:  true,
+			want: []clusterDiffEntry{
+				{Field: "addons.managed-odh", A: "2.8.0 (ready)", B: clusterDiffMissing, Differs: true},
+				{Field: "machine_pools.infra.replicas", A: clusterDiffMissing, B: "3", Differs: true},
+				{Field: "machine_pools.worker.labels", A: "", B: ""},
+				{Field: "network.type", A: "OVNKubernetes", B: "OVNKubernetes"},
+				{Field: "version", A: "4.15.3", B: "4.14.9", Differs: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffClusterConfigs(a, b, tt.all); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffClusterConfigs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddPoolConfig(t *testing.T) {
+	config := clusterConfig{}
+	addPoolConfig(config, "machine_pools.infra", "r5.xlarge", "3", "us-east-1a,us-east-1b",
+		map[string]string{"node-role.kubernetes.io/infra": "", "env": "prod"},
+		[]string{"node-role.kubernetes.io/infra=:NoSchedule", "dedicated=infra:NoExecute"})
+
+	want := clusterConfig{
+		"machine_pools.infra.instance_type":      "r5.xlarge",
+		"machine_pools.infra.replicas":           "3",
+		"machine_pools.infra.availability_zones": "us-east-1a,us-east-1b",
+		"machine_pools.infra.labels":             "env=prod,node-role.kubernetes.io/infra=",
+		"machine_pools.infra.taints":             "dedicated=infra:NoExecute,node-role.kubernetes.io/infra=:NoSchedule",
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("addPoolConfig() = %v, want %v", config, want)
+	}
+}
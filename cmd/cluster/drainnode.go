@@ -0,0 +1,364 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+type drainNodeOptions struct {
+	clusterID string
+	node      string
+	reason    string
+	timeout   time.Duration
+	reboot    bool
+	terminate bool
+	force     bool
+	yes       bool
+}
+
+// drainNodeAWSClient is the subset of the EC2 API used to check and act on the instance backing the node
+type drainNodeAWSClient interface {
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	RebootInstances(ctx context.Context, params *ec2.RebootInstancesInput, optFns ...func(options *ec2.Options)) (*ec2.RebootInstancesOutput, error)
+	TerminateInstances(ctx context.Context, params *ec2.TerminateInstancesInput, optFns ...func(options *ec2.Options)) (*ec2.TerminateInstancesOutput, error)
+}
+
+// blockedEviction is a pod whose eviction is refused by a PodDisruptionBudget
+type blockedEviction struct {
+	pod  string
+	pdbs []string
+}
+
+// unsafeEviction is a pod whose eviction loses data or which nothing recreates, only evicted with --force
+type unsafeEviction struct {
+	pod    string
+	reason string
+}
+
+func newCmdDrainNode() *cobra.Command {
+	ops := &drainNodeOptions{}
+	drainNodeCmd := &cobra.Command{
+		Use:   "drain-node <cluster-id> <node>",
+		Short: "Cordon and drain a node, then check or act on its backing cloud instance",
+		Long: `Cordon and drain a node of a cluster through backplane, like 'oc adm drain --ignore-daemonsets'.
+
+The pods are evicted, so PodDisruptionBudgets are honored: the pods whose eviction is refused are reported
+with the budgets blocking them. The pods not managed by a controller, which nothing recreates, and the pods
+using emptyDir volumes, whose data is lost, are only evicted with --force. Otherwise they are reported and the
+node is left cordoned with them. Once the node is drained the state of its backing instance is checked, and
+with --reboot or --terminate the instance is rebooted or terminated through the cloud provider. Acting on the
+instance is only supported on AWS.`,
+		Example: `  # Drain a node and check its instance
+  osdctl cluster drain-node <cluster-id> ip-10-0-1-2.ec2.internal --reason OHSS-1234
+
+  # Drain a node and reboot its instance
+  osdctl cluster drain-node <cluster-id> ip-10-0-1-2.ec2.internal --reason OHSS-1234 --reboot`,
+		Args:              cobra.ExactArgs(2),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			ops.node = args[1]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	drainNodeCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	drainNodeCmd.Flags().DurationVar(&ops.timeout, "timeout", 5*time.Minute, "How long to wait for the pods to be evicted")
+	drainNodeCmd.Flags().BoolVar(&ops.reboot, "reboot", false, "Reboot the backing instance once the node is drained")
+	drainNodeCmd.Flags().BoolVar(&ops.terminate, "terminate", false, "Terminate the backing instance once the node is drained, the machine-api replaces it")
+	drainNodeCmd.Flags().BoolVar(&ops.force, "force", false, "Also evict the pods not managed by a controller and the pods using emptyDir volumes, losing them or their data")
+	drainNodeCmd.Flags().BoolVarP(&ops.yes, "yes", "y", false, "Skip the confirmation prompt before terminating the instance")
+	_ = drainNodeCmd.MarkFlagRequired("reason")
+	drainNodeCmd.MarkFlagsMutuallyExclusive("reboot", "terminate")
+
+	return drainNodeCmd
+}
+
+func (o *drainNodeOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	o.clusterID = cluster.ID()
+
+	_, _, clientset, err := common.GetKubeConfigAndClient(o.clusterID, o.reason, fmt.Sprintf("Draining node %s", o.node))
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+
+	node, err := clientset.CoreV1().Nodes().Get(ctx, o.node, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", o.node, err)
+	}
+
+	if node.Spec.Unschedulable {
+		fmt.Printf("Node %s is already cordoned\n", o.node)
+	} else {
+		patch := []byte(`{"spec":{"unschedulable":true}}`)
+		if _, err := clientset.CoreV1().Nodes().Patch(ctx, o.node, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to cordon node %s: %w", o.node, err)
+		}
+		printer.PrintlnGreen("Cordoned node", o.node)
+	}
+
+	drained, blocked, unsafe, err := o.drain(ctx, clientset)
+	if err != nil {
+		return err
+	}
+	if !drained {
+		if len(blocked) > 0 {
+			printBlockedEvictions(blocked)
+		}
+		return fmt.Errorf("node %s wasn't drained within %s, the backing instance was left untouched", o.node, o.timeout)
+	}
+	if len(unsafe) > 0 {
+		if !o.force {
+			printUnsafeEvictions("These pods weren't evicted, --force evicts them:", unsafe)
+			return fmt.Errorf("node %s still runs %d pods which weren't evicted, the backing instance was left untouched", o.node, len(unsafe))
+		}
+		printUnsafeEvictions("These pods were evicted with --force:", unsafe)
+	}
+	printer.PrintlnGreen("Drained node", o.node)
+
+	return o.checkInstance(ctx, connection, cluster, node.Spec.ProviderID)
+}
+
+// drain evicts the pods of the node until none is left or the timeout expires, it returns whether the node
+// was drained, the pods whose eviction was still refused by a PodDisruptionBudget at the end, and the pods
+// which are only evicted with --force
+func (o *drainNodeOptions) drain(ctx context.Context, clientset *kubernetes.Clientset) (bool, []blockedEviction, []unsafeEviction, error) {
+	pdbList, err := clientset.PolicyV1().PodDisruptionBudgets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, nil, nil, fmt.Errorf("failed to list the PodDisruptionBudgets: %w", err)
+	}
+
+	var blocked []blockedEviction
+	unsafe := map[string]unsafeEviction{}
+	err = wait.PollImmediate(5*time.Second, o.timeout, func() (bool, error) {
+		podList, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + o.node})
+		if err != nil {
+			return false, err
+		}
+		blocked = nil
+		pods, unsafePods := podsToEvict(podList.Items, o.force)
+		// Remember the pods evicted with --force once they are gone
+		for _, pod := range unsafePods {
+			unsafe[pod.pod] = pod
+		}
+		if len(pods) == 0 {
+			return true, nil
+		}
+
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+			err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+			switch {
+			case err == nil:
+				fmt.Printf("Evicted pod %s/%s\n", pod.Namespace, pod.Name)
+			case apierrors.IsNotFound(err):
+			case apierrors.IsTooManyRequests(err):
+				blocked = append(blocked, blockedEviction{
+					pod:  pod.Namespace + "/" + pod.Name,
+					pdbs: matchingPDBs(pod, pdbList.Items),
+				})
+			default:
+				return false, fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return false, blocked, nil, nil
+	}
+	if err != nil {
+		return false, nil, nil, err
+	}
+	unsafeEvictions := make([]unsafeEviction, 0, len(unsafe))
+	for _, pod := range unsafe {
+		unsafeEvictions = append(unsafeEvictions, pod)
+	}
+	sort.Slice(unsafeEvictions, func(i, j int) bool {
+		return unsafeEvictions[i].pod < unsafeEvictions[j].pod
+	})
+	return true, nil, unsafeEvictions, nil
+}
+
+// podsToEvict returns the pods that block the drain: the DaemonSet and mirror pods aren't evicted and the
+// completed pods don't run anymore. The pods nothing recreates or whose emptyDir data is lost are returned
+// as unsafe, and only evicted with force.
+func podsToEvict(pods []corev1.Pod, force bool) ([]corev1.Pod, []unsafeEviction) {
+	var evict []corev1.Pod
+	var unsafe []unsafeEviction
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, mirror := pod.Annotations[mirrorPodAnnotation]; mirror {
+			continue
+		}
+		controller := metav1.GetControllerOf(&pod)
+		if controller != nil && controller.Kind == "DaemonSet" {
+			continue
+		}
+
+		var reasons []string
+		if controller == nil {
+			reasons = append(reasons, "not managed by a controller, nothing recreates it")
+		}
+		var emptyDirs []string
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				emptyDirs = append(emptyDirs, volume.Name)
+			}
+		}
+		if len(emptyDirs) > 0 {
+			reasons = append(reasons, fmt.Sprintf("the data of its emptyDir volumes %s is lost", strings.Join(emptyDirs, ", ")))
+		}
+		if len(reasons) > 0 {
+			unsafe = append(unsafe, unsafeEviction{pod: pod.Namespace + "/" + pod.Name, reason: strings.Join(reasons, ", ")})
+			if !force {
+				continue
+			}
+		}
+		evict = append(evict, pod)
+	}
+	return evict, unsafe
+}
+
+// matchingPDBs returns the PodDisruptionBudgets of the namespace of the pod selecting it
+func matchingPDBs(pod corev1.Pod, pdbs []policyv1.PodDisruptionBudget) []string {
+	var names []string
+	for _, pdb := range pdbs {
+		if pdb.Namespace != pod.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			names = append(names, fmt.Sprintf("%s (%d disruptions allowed)", pdb.Name, pdb.Status.DisruptionsAllowed))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printBlockedEvictions(blocked []blockedEviction) {
+	fmt.Println("The eviction of these pods was refused by their PodDisruptionBudgets:")
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"POD", "PODDISRUPTIONBUDGETS"})
+	for _, b := range blocked {
+		pdbs := strings.Join(b.pdbs, ", ")
+		if pdbs == "" {
+			pdbs = "unknown"
+		}
+		table.AddRow([]string{b.pod, pdbs})
+	}
+	table.AddRow([]string{})
+	table.Flush()
+}
+
+func printUnsafeEvictions(title string, unsafe []unsafeEviction) {
+	fmt.Println(title)
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"POD", "REASON"})
+	for _, u := range unsafe {
+		table.AddRow([]string{u.pod, u.reason})
+	}
+	table.AddRow([]string{})
+	table.Flush()
+}
+
+// checkInstance prints the state of the instance backing the node and reboots or terminates it if requested
+func (o *drainNodeOptions) checkInstance(ctx context.Context, connection *sdk.Connection, cluster *cmv1.Cluster, providerID string) error {
+	if strings.ToLower(cluster.CloudProvider().ID()) != "aws" {
+		if o.reboot || o.terminate {
+			return fmt.Errorf("acting on the backing instance is only supported on AWS, use backplane to reboot or terminate %s", providerID)
+		}
+		fmt.Printf("Checking the backing instance is only supported on AWS, skipping %s\n", providerID)
+		return nil
+	}
+	instanceID := instanceIDFromProviderID(providerID)
+	if !strings.HasPrefix(instanceID, "i-") {
+		return fmt.Errorf("can't find the instance ID of node %s in its provider ID '%s'", o.node, providerID)
+	}
+
+	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	if err != nil {
+		return err
+	}
+	var awsClient drainNodeAWSClient = ec2.NewFromConfig(cfg)
+
+	output, err := awsClient.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}})
+	if err != nil {
+		return fmt.Errorf("failed to describe instance %s: %w", instanceID, err)
+	}
+	state := "unknown"
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.State != nil {
+				state = string(instance.State.Name)
+			}
+		}
+	}
+	fmt.Printf("Instance %s of node %s is %s\n", instanceID, o.node, state)
+
+	switch {
+	case o.reboot:
+		if _, err := awsClient.RebootInstances(ctx, &ec2.RebootInstancesInput{InstanceIds: []string{instanceID}}); err != nil {
+			return fmt.Errorf("failed to reboot instance %s: %w", instanceID, err)
+		}
+		printer.PrintlnGreen("Requested the reboot of instance", instanceID)
+		fmt.Printf("Uncordon the node once it is Ready again: oc adm uncordon %s\n", o.node)
+	case o.terminate:
+		fmt.Printf("Instance %s will be terminated, the machine-api replaces it with a new node\n", instanceID)
+		if !o.yes && !utils.ConfirmPrompt() {
+			return nil
+		}
+		if _, err := awsClient.TerminateInstances(ctx, &ec2.TerminateInstancesInput{InstanceIds: []string{instanceID}}); err != nil {
+			return fmt.Errorf("failed to terminate instance %s: %w", instanceID, err)
+		}
+		printer.PrintlnGreen("Requested the termination of instance", instanceID)
+	}
+	return nil
+}
+
+// instanceIDFromProviderID returns the instance ID of a provider ID like aws:///us-east-1a/i-0a1b2c3d4e5f6g7h8
+func instanceIDFromProviderID(providerID string) string {
+	return providerID[strings.LastIndex(providerID, "/")+1:]
+}
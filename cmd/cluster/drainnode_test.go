@@ -0,0 +1,110 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodsToEvict(t *testing.T) {
+	controller := true
+	pod := func(name string, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}, Status: corev1.PodStatus{Phase: phase}}
+	}
+	daemonSetPod := pod("dns-default-abcde", corev1.PodRunning)
+	daemonSetPod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "dns-default", Controller: &controller}}
+	mirrorPod := pod("etcd-ip-10-0-1-2", corev1.PodRunning)
+	mirrorPod.Annotations = map[string]string{mirrorPodAnnotation: "abc"}
+	replicaSetPod := pod("router-default-abcde", corev1.PodRunning)
+	replicaSetPod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "router-default", Controller: &controller}}
+	emptyDirPod := pod("prometheus-k8s-0", corev1.PodRunning)
+	emptyDirPod.OwnerReferences = []metav1.OwnerReference{{Kind: "StatefulSet", Name: "prometheus-k8s", Controller: &controller}}
+	emptyDirPod.Spec.Volumes = []corev1.Volume{
+		{Name: "config", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "config"}}},
+		{Name: "db", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+
+	pods := []corev1.Pod{daemonSetPod, mirrorPod, replicaSetPod, emptyDirPod, pod("installer-3", corev1.PodSucceeded), pod("standalone", corev1.PodPending)}
+	unsafe := []unsafeEviction{
+		{pod: "ns/prometheus-k8s-0", reason: "the data of its emptyDir volumes db is lost"},
+		{pod: "ns/standalone", reason: "not managed by a controller, nothing recreates it"},
+	}
+
+	tests := []struct {
+		name       string
+		force      bool
+		wantEvict  []string
+		wantUnsafe []unsafeEviction
+	}{
+		{
+			name:       "unmanaged and emptyDir pods skipped",
+			wantEvict:  []string{"router-default-abcde"},
+			wantUnsafe: unsafe,
+		},
+		{
+			name:       "unmanaged and emptyDir pods evicted with force",
+			force:      true,
+			wantEvict:  []string{"router-default-abcde", "prometheus-k8s-0", "standalone"},
+			wantUnsafe: unsafe,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evict, gotUnsafe := podsToEvict(pods, tt.force)
+			var got []string
+			for _, p := range evict {
+				got = append(got, p.Name)
+			}
+			if !reflect.DeepEqual(got, tt.wantEvict) {
+				t.Errorf("podsToEvict() evicts %v, want %v", got, tt.wantEvict)
+			}
+			if !reflect.DeepEqual(gotUnsafe, tt.wantUnsafe) {
+				t.Errorf("podsToEvict() unsafe = %v, want %v", gotUnsafe, tt.wantUnsafe)
+			}
+		})
+	}
+}
+
+func TestMatchingPDBs(t *testing.T) {
+	pdb := func(namespace string, name string, selector *metav1.LabelSelector, allowed int32) policyv1.PodDisruptionBudget {
+		return policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: selector},
+			Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: allowed},
+		}
+	}
+	pdbs := []policyv1.PodDisruptionBudget{
+		pdb("openshift-ingress", "router-default", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "router"}}, 0),
+		pdb("openshift-ingress", "other", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}}, 1),
+		pdb("openshift-ingress", "empty", &metav1.LabelSelector{}, 1),
+		pdb("openshift-monitoring", "router-default", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "router"}}, 0),
+	}
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-ingress", Name: "router-default-abcde", Labels: map[string]string{"app": "router"}}}
+
+	want := []string{"empty (1 disruptions allowed)", "router-default (0 disruptions allowed)"}
+	if got := matchingPDBs(pod, pdbs); !reflect.DeepEqual(got, want) {
+		t.Errorf("matchingPDBs() = %v, want %v", got, want)
+	}
+}
+
+func TestInstanceIDFromProviderID(t *testing.T) {
+	tests := []struct {
+		name       string
+		providerID string
+		want       string
+	}{
+		{name: "AWS", providerID: "aws:///us-east-1a/i-0a1b2c3d4e5f6a7b8", want: "i-0a1b2c3d4e5f6a7b8"},
+		{name: "GCP", providerID: "gce://project/europe-west4-a/cluster-infra-a-4fbrd", want: "cluster-infra-a-4fbrd"},
+		{name: "Empty", providerID: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := instanceIDFromProviderID(tt.providerID); got != tt.want {
+				t.Errorf("instanceIDFromProviderID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// contextClusterEvents is the number of cluster events shown in the long context output
+	contextClusterEvents         = 5
+	clusterEventSummaryMaxLength = 100
+)
+
+type eventsOptions struct {
+	clusterID string
+	since     time.Duration
+	until     string
+	limit     int
+	output    string
+}
+
+func newCmdEvents() *cobra.Command {
+	ops := &eventsOptions{}
+	eventsCmd := &cobra.Command{
+		Use:   "events <cluster-id>",
+		Short: "List the lifecycle events OCM logged for a cluster",
+		Long: `List the events OCM logged for a cluster along its lifecycle (installation, upgrades, hibernation,
+limited support...), newest first. Those are the service logs posted by the OCM services themselves, the
+service logs sent by SREs are listed by 'osdctl servicelog list'.`,
+		Example: `  # Events of the last 3 days
+  osdctl cluster events <cluster-id> --since 72h
+
+  # Events of the day of an incident
+  osdctl cluster events <cluster-id> --since 720h --until 2024-03-02T00:00:00Z -o json`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	eventsCmd.Flags().DurationVar(&ops.since, "since", 30*24*time.Hour, "How far back events are listed")
+	eventsCmd.Flags().StringVar(&ops.until, "until", "", "List the events until this time (RFC3339), defaults to now")
+	eventsCmd.Flags().IntVar(&ops.limit, "limit", 100, "Maximum number of events to list")
+	eventsCmd.Flags().StringVarP(&ops.output, "output", "o", "text", "Output format, one of text or json")
+
+	return eventsCmd
+}
+
+func (o *eventsOptions) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output format '%s', expected text or json", o.output)
+	}
+	if o.since <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+	if o.limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+	if o.until != "" {
+		if _, err := time.Parse(time.RFC3339, o.until); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	return nil
+}
+
+func (o *eventsOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	var until time.Time
+	if o.until != "" {
+		// Validated already
+		until, _ = time.Parse(time.RFC3339, o.until)
+	}
+	since := time.Now().Add(-o.since)
+	if !until.IsZero() {
+		since = until.Add(-o.since)
+	}
+
+	events, err := servicelog.FetchClusterEvents(ocmClient, cluster, since, until, o.limit)
+	if err != nil {
+		return err
+	}
+
+	if o.output == "json" {
+		out, err := marshalSDKList(func(w io.Writer) error {
+			return v1.MarshalLogEntryList(events, w)
+		})
+		if err != nil {
+			return err
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, out, "", "  "); err != nil {
+			return err
+		}
+		fmt.Println(indented.String())
+		return nil
+	}
+
+	printClusterEvents(events)
+	return nil
+}
+
+// clusterEventRow returns the columns of an event in the events table
+func clusterEventRow(event *v1.LogEntry) []string {
+	summary := event.Summary()
+	// The summaries of some events are generic, the first line of their description tells what happened
+	if description := strings.TrimSpace(strings.SplitN(event.Description(), "\n", 2)[0]); description != "" && description != summary {
+		summary = fmt.Sprintf("%s: %s", summary, description)
+	}
+	if len(summary) > clusterEventSummaryMaxLength {
+		summary = summary[:clusterEventSummaryMaxLength-3] + "..."
+	}
+	return []string{
+		event.Timestamp().UTC().Format(time.RFC3339),
+		string(event.Severity()),
+		string(event.LogType()),
+		event.ServiceName(),
+		summary,
+	}
+}
+
+func printClusterEvents(events []*v1.LogEntry) {
+	if len(events) == 0 {
+		fmt.Println("None")
+		return
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"TIME", "SEVERITY", "TYPE", "SERVICE", "SUMMARY"})
+	for _, event := range events {
+		table.AddRow(clusterEventRow(event))
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the cluster events: %v\n", err)
+	}
+}
@@ -0,0 +1,46 @@
+package cluster
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+func TestClusterEventRow(t *testing.T) {
+	timestamp := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		summary     string
+		description string
+		want        string
+	}{
+		{name: "summary only", summary: "Cluster installed", want: "Cluster installed"},
+		{name: "same description", summary: "Cluster installed", description: "Cluster installed", want: "Cluster installed"},
+		{name: "description first line", summary: "Cluster Upgrade", description: "Upgrade to 4.14.3 started\nmore details", want: "Cluster Upgrade: Upgrade to 4.14.3 started"},
+		{name: "truncated", summary: strings.Repeat("a", 120), want: strings.Repeat("a", 97) + "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := v1.NewLogEntry().
+				Timestamp(timestamp).
+				Severity(v1.Severity("Info")).
+				LogType(v1.LogType("cluster-state-updates")).
+				ServiceName("ClusterManager").
+				Summary(tt.summary).
+				Description(tt.description).
+				Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []string{"2024-03-01T10:00:00Z", string(v1.Severity("Info")), string(v1.LogType("cluster-state-updates")), "ClusterManager", tt.want}
+			if got := clusterEventRow(event); !reflect.DeepEqual(got, want) {
+				t.Errorf("clusterEventRow() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/googleapi"
+	iam "google.golang.org/api/iam/v1"
+	"google.golang.org/api/policyanalyzer/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	gcpUserManagedKey                = "USER_MANAGED"
+	gcpKeyLastAuthenticationActivity = "serviceAccountKeyLastAuthentication"
+	defaultGCPKeyMaxAge              = 90 * 24 * time.Hour
+)
+
+type gcpKeysOptions struct {
+	clusterID string
+	maxAge    time.Duration
+	rotate    bool
+	reason    string
+	timeout   time.Duration
+}
+
+// gcpKey is a user-managed key of a service account of the cluster project
+type gcpKey struct {
+	serviceAccount string
+	id             string
+	name           string
+	created        time.Time
+	lastUsed       time.Time
+	disabled       bool
+	tooOld         bool
+}
+
+func newCmdGCPKeys() *cobra.Command {
+	ops := &gcpKeysOptions{}
+	gcpKeysCmd := &cobra.Command{
+		Use:   "gcp-keys <cluster-id>",
+		Short: "Audit the service account keys of the GCP project of a cluster",
+		Long: `List the user-managed keys of the service accounts of the GCP project of an OSD GCP cluster, with their age
+and when they last authenticated, and flag the keys older than --max-age.
+
+The last authentication times come from the Policy Analyzer API, which only observes the last few months and
+must be enabled in the project; they are left empty otherwise.
+
+With --rotate, the old keys minted by the cloud-credential operator are rotated: the secret of their
+CredentialsRequest is deleted so the operator mints a new key, then the old keys are deleted. The other keys,
+e.g. the osd-managed-admin one owned by the gcp-project-operator, have to be rotated from their owner.
+
+The GCP credentials are read from the application default credentials, e.g. 'gcloud auth application-default login'.`,
+		Example: `  # Audit the keys of a cluster
+  osdctl cluster gcp-keys <cluster-id>
+
+  # Rotate the keys older than 30 days
+  osdctl cluster gcp-keys <cluster-id> --max-age 720h --rotate --reason OHSS-1234`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	gcpKeysCmd.Flags().DurationVar(&ops.maxAge, "max-age", defaultGCPKeyMaxAge, "Flag the keys older than this")
+	gcpKeysCmd.Flags().BoolVar(&ops.rotate, "rotate", false, "Rotate the flagged keys minted by the cloud-credential operator")
+	gcpKeysCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for rotating the keys, which requires elevation (usually an OHSS or PD ticket)")
+	gcpKeysCmd.Flags().DurationVar(&ops.timeout, "timeout", 5*time.Minute, "How long to wait for the cloud-credential operator to mint each new key")
+
+	return gcpKeysCmd
+}
+
+func (o *gcpKeysOptions) run() error {
+	if o.rotate && o.reason == "" {
+		return fmt.Errorf("--reason is required to rotate the keys")
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	o.clusterID = cluster.ID()
+	if cluster.CloudProvider().ID() != "gcp" {
+		return fmt.Errorf("this command is only available for GCP clusters")
+	}
+	project, err := osdCloud.GetGCPProjectID(connection, cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	iamService, err := iam.NewService(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create the GCP IAM client: %w", err)
+	}
+	keys, err := listGCPKeys(ctx, iamService, project)
+	if err != nil {
+		return err
+	}
+
+	lastUsed, err := gcpKeyLastAuthentications(ctx, project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: can't get when the keys were last used, is the Policy Analyzer API enabled in project %s? %v\n", project, err)
+	}
+	auditGCPKeys(keys, lastUsed, o.maxAge, time.Now())
+
+	fmt.Printf("User-managed service account keys of project %s:\n", project)
+	printGCPKeys(keys, lastUsed != nil, time.Now())
+
+	var old []gcpKey
+	for _, key := range keys {
+		if key.tooOld {
+			old = append(old, key)
+		}
+	}
+	fmt.Printf("%d of %d keys are older than %s\n", len(old), len(keys), formatDays(o.maxAge))
+	if !o.rotate || len(old) == 0 {
+		return nil
+	}
+	return o.rotateGCPKeys(ctx, iamService, project, old)
+}
+
+func listGCPKeys(ctx context.Context, iamService *iam.Service, project string) ([]gcpKey, error) {
+	var keys []gcpKey
+	err := iamService.Projects.ServiceAccounts.List("projects/"+project).Pages(ctx, func(page *iam.ListServiceAccountsResponse) error {
+		for _, account := range page.Accounts {
+			accountKeys, err := listServiceAccountKeys(ctx, iamService, account.Email, account.Name)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, accountKeys...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the service accounts of project %s: %w", project, err)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].created.Before(keys[j].created) })
+	return keys, nil
+}
+
+func listServiceAccountKeys(ctx context.Context, iamService *iam.Service, email string, accountName string) ([]gcpKey, error) {
+	response, err := iamService.Projects.ServiceAccounts.Keys.List(accountName).KeyTypes(gcpUserManagedKey).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the keys of service account %s: %w", email, err)
+	}
+	var keys []gcpKey
+	for _, key := range response.Keys {
+		created, _ := time.Parse(time.RFC3339, key.ValidAfterTime)
+		keys = append(keys, gcpKey{
+			serviceAccount: email,
+			id:             gcpKeyID(key.Name),
+			name:           key.Name,
+			created:        created,
+			disabled:       key.Disabled,
+		})
+	}
+	return keys, nil
+}
+
+// gcpKeyLastAuthentications returns when each key of the project last authenticated, by key ID
+func gcpKeyLastAuthentications(ctx context.Context, project string) (map[string]time.Time, error) {
+	analyzer, err := policyanalyzer.NewService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parent := fmt.Sprintf("projects/%s/locations/global/activityTypes/%s", project, gcpKeyLastAuthenticationActivity)
+	lastUsed := map[string]time.Time{}
+	err = analyzer.Projects.Locations.ActivityTypes.Activities.Query(parent).Pages(ctx, func(page *policyanalyzer.GoogleCloudPolicyanalyzerV1QueryActivityResponse) error {
+		for _, activity := range page.Activities {
+			keyID, when, err := parseKeyLastAuthentication(activity.FullResourceName, activity.Activity)
+			if err != nil {
+				continue
+			}
+			lastUsed[keyID] = when
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lastUsed, nil
+}
+
+// parseKeyLastAuthentication returns the key ID and the last authentication time of a
+// serviceAccountKeyLastAuthentication activity, e.g. {"lastAuthenticatedTime": "2024-05-01T07:00:00Z", ...}
+// for //iam.googleapis.com/projects/<project>/serviceAccounts/<email>/keys/<key-id>
+func parseKeyLastAuthentication(fullResourceName string, activity []byte) (string, time.Time, error) {
+	if !strings.Contains(fullResourceName, "/keys/") {
+		return "", time.Time{}, fmt.Errorf("'%s' isn't a service account key", fullResourceName)
+	}
+	var fields struct {
+		LastAuthenticatedTime time.Time `json:"lastAuthenticatedTime"`
+	}
+	if err := json.Unmarshal(activity, &fields); err != nil {
+		return "", time.Time{}, err
+	}
+	return gcpKeyID(fullResourceName), fields.LastAuthenticatedTime, nil
+}
+
+// gcpKeyID returns the ID of a key from its name, projects/<project>/serviceAccounts/<email>/keys/<key-id>
+func gcpKeyID(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// auditGCPKeys sets when the keys were last used and flags the enabled ones older than maxAge
+func auditGCPKeys(keys []gcpKey, lastUsed map[string]time.Time, maxAge time.Duration, now time.Time) {
+	for i := range keys {
+		keys[i].lastUsed = lastUsed[keys[i].id]
+		keys[i].tooOld = !keys[i].disabled && now.Sub(keys[i].created) > maxAge
+	}
+}
+
+func printGCPKeys(keys []gcpKey, lastUsedKnown bool, now time.Time) {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"SERVICE ACCOUNT", "KEY ID", "CREATED", "AGE", "LAST USED", "STATUS"})
+	for _, key := range keys {
+		lastUsed := ""
+		switch {
+		case !key.lastUsed.IsZero():
+			lastUsed = key.lastUsed.Format(time.RFC3339)
+		case lastUsedKnown:
+			lastUsed = "not observed"
+		}
+		status := "OK"
+		switch {
+		case key.disabled:
+			status = "disabled"
+		case key.tooOld:
+			status = "TOO OLD"
+		}
+		table.AddRow([]string{
+			key.serviceAccount,
+			key.id,
+			key.created.Format(time.RFC3339),
+			formatDays(now.Sub(key.created)),
+			lastUsed,
+			status,
+		})
+	}
+	table.AddRow([]string{})
+	table.Flush()
+}
+
+func formatDays(duration time.Duration) string {
+	return fmt.Sprintf("%dd", int(duration.Hours()/24))
+}
+
+// rotateGCPKeys rotates the keys minted by the cloud-credential operator: the secret of their CredentialsRequest
+// is deleted so the operator mints a new key, then the old keys are deleted
+func (o *gcpKeysOptions) rotateGCPKeys(ctx context.Context, iamService *iam.Service, project string, keys []gcpKey) error {
+	kubeCli, _, _, err := common.GetKubeConfigAndClient(o.clusterID, o.reason, fmt.Sprintf("Rotating the GCP service account keys of cluster %s", o.clusterID))
+	if err != nil {
+		return err
+	}
+	requests := &unstructured.UnstructuredList{}
+	requests.SetGroupVersionKind(schema.GroupVersionKind{Group: "cloudcredential.openshift.io", Version: "v1", Kind: "CredentialsRequestList"})
+	if err := kubeCli.List(ctx, requests, client.InNamespace(cloudCredentialOperatorNamespace)); err != nil {
+		return fmt.Errorf("failed to list the CredentialsRequests: %w", err)
+	}
+	secrets := mintedServiceAccountSecrets(requests.Items, project)
+
+	keysByAccount := map[string][]gcpKey{}
+	var accounts []string
+	for _, key := range keys {
+		if _, ok := keysByAccount[key.serviceAccount]; !ok {
+			accounts = append(accounts, key.serviceAccount)
+		}
+		keysByAccount[key.serviceAccount] = append(keysByAccount[key.serviceAccount], key)
+	}
+	sort.Strings(accounts)
+
+	var rotatable []string
+	for _, account := range accounts {
+		secret, ok := secrets[account]
+		if !ok {
+			fmt.Printf("%s isn't minted by the cloud-credential operator, rotate its keys from its owner\n", account)
+			continue
+		}
+		fmt.Printf("%s: delete secret %s so a new key is minted, then delete %d old keys\n", account, secret, len(keysByAccount[account]))
+		rotatable = append(rotatable, account)
+	}
+	if len(rotatable) == 0 || !utils.ConfirmPrompt() {
+		return nil
+	}
+
+	var errs []error
+	for _, account := range rotatable {
+		if err := o.rotateServiceAccount(ctx, kubeCli, iamService, account, secrets[account], keysByAccount[account]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", account, err))
+			continue
+		}
+		printer.PrintlnGreen("Rotated the keys of", account)
+	}
+	return errors.Join(errs...)
+}
+
+func (o *gcpKeysOptions) rotateServiceAccount(ctx context.Context, kubeCli client.Client, iamService *iam.Service, account string, secretKey client.ObjectKey, old []gcpKey) error {
+	accountName := fmt.Sprintf("projects/-/serviceAccounts/%s", account)
+	oldIDs := map[string]bool{}
+	for _, key := range old {
+		oldIDs[key.id] = true
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace}}
+	if err := kubeCli.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete secret %s: %w", secretKey, err)
+	}
+
+	err := wait.PollImmediate(10*time.Second, o.timeout, func() (bool, error) {
+		if err := kubeCli.Get(ctx, secretKey, &corev1.Secret{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		keys, err := listServiceAccountKeys(ctx, iamService, account, accountName)
+		if err != nil {
+			return false, err
+		}
+		for _, key := range keys {
+			if !oldIDs[key.id] {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the cloud-credential operator didn't mint a new key in secret %s within %s, the old keys were kept: %w", secretKey, o.timeout, err)
+	}
+
+	for _, key := range old {
+		_, err := iamService.Projects.ServiceAccounts.Keys.Delete(key.name).Context(ctx).Do()
+		var apiErr *googleapi.Error
+		if err != nil && !(errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound) {
+			return fmt.Errorf("failed to delete key %s: %w", key.id, err)
+		}
+	}
+	return nil
+}
+
+// mintedServiceAccountSecrets returns the secret of the CredentialsRequests by the email of the service account
+// the cloud-credential operator minted for them
+func mintedServiceAccountSecrets(requests []unstructured.Unstructured, project string) map[string]client.ObjectKey {
+	secrets := map[string]client.ObjectKey{}
+	for _, request := range requests {
+		accountID, _, _ := unstructured.NestedString(request.Object, "status", "providerStatus", "serviceAccountID")
+		name, _, _ := unstructured.NestedString(request.Object, "spec", "secretRef", "name")
+		namespace, _, _ := unstructured.NestedString(request.Object, "spec", "secretRef", "namespace")
+		if accountID == "" || name == "" || namespace == "" {
+			continue
+		}
+		secrets[fmt.Sprintf("%s@%s.iam.gserviceaccount.com", accountID, project)] = client.ObjectKey{Namespace: namespace, Name: name}
+	}
+	return secrets
+}
@@ -0,0 +1,97 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestParseKeyLastAuthentication(t *testing.T) {
+	tests := []struct {
+		name             string
+		fullResourceName string
+		activity         string
+		wantKeyID        string
+		wantTime         time.Time
+		wantErr          bool
+	}{
+		{
+			name:             "Key authentication",
+			fullResourceName: "//iam.googleapis.com/projects/project/serviceAccounts/sa@project.iam.gserviceaccount.com/keys/0123abcd",
+			activity:         `{"lastAuthenticatedTime": "2024-05-01T07:00:00Z", "serviceAccountKey": {"serviceAccountId": "123"}}`,
+			wantKeyID:        "0123abcd",
+			wantTime:         time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:             "Not a key",
+			fullResourceName: "//iam.googleapis.com/projects/project/serviceAccounts/sa@project.iam.gserviceaccount.com",
+			activity:         `{"lastAuthenticatedTime": "2024-05-01T07:00:00Z"}`,
+			wantErr:          true,
+		},
+		{
+			name:             "Invalid activity",
+			fullResourceName: "//iam.googleapis.com/projects/project/serviceAccounts/sa@project.iam.gserviceaccount.com/keys/0123abcd",
+			activity:         `{"lastAuthenticatedTime": 1}`,
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyID, when, err := parseKeyLastAuthentication(tt.fullResourceName, []byte(tt.activity))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKeyLastAuthentication() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if keyID != tt.wantKeyID || !when.Equal(tt.wantTime) {
+				t.Errorf("parseKeyLastAuthentication() = %v, %v, want %v, %v", keyID, when, tt.wantKeyID, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestAuditGCPKeys(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	lastUsed := now.Add(-time.Hour)
+	keys := []gcpKey{
+		{id: "recent", created: now.Add(-24 * time.Hour)},
+		{id: "old", created: now.Add(-100 * 24 * time.Hour)},
+		{id: "old-disabled", created: now.Add(-100 * 24 * time.Hour), disabled: true},
+	}
+	auditGCPKeys(keys, map[string]time.Time{"old": lastUsed}, defaultGCPKeyMaxAge, now)
+
+	want := []gcpKey{
+		{id: "recent", created: now.Add(-24 * time.Hour)},
+		{id: "old", created: now.Add(-100 * 24 * time.Hour), lastUsed: lastUsed, tooOld: true},
+		{id: "old-disabled", created: now.Add(-100 * 24 * time.Hour), disabled: true},
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("auditGCPKeys() = %+v, want %+v", keys, want)
+	}
+	if got := formatDays(now.Sub(keys[1].created)); got != "100d" {
+		t.Errorf("formatDays() = %v, want 100d", got)
+	}
+}
+
+func TestMintedServiceAccountSecrets(t *testing.T) {
+	request := func(accountID string, secretName string) unstructured.Unstructured {
+		object := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"secretRef": map[string]interface{}{"name": secretName, "namespace": "openshift-image-registry"}},
+		}}
+		if accountID != "" {
+			object.Object["status"] = map[string]interface{}{"providerStatus": map[string]interface{}{"serviceAccountID": accountID}}
+		}
+		return object
+	}
+	requests := []unstructured.Unstructured{
+		request("cluster-openshift-i-abcde", "installer-cloud-credentials"),
+		request("", "cloud-credentials"),
+	}
+	want := map[string]client.ObjectKey{
+		"cluster-openshift-i-abcde@project.iam.gserviceaccount.com": {Namespace: "openshift-image-registry", Name: "installer-cloud-credentials"},
+	}
+	if got := mintedServiceAccountSecrets(requests, "project"); !reflect.DeepEqual(got, want) {
+		t.Errorf("mintedServiceAccountSecrets() = %v, want %v", got, want)
+	}
+}
@@ -75,19 +75,35 @@ type ClusterHealthCondensedObject struct {
 }
 
 func (o *healthOptions) run() error {
+	healthObject, err := GetClusterHealth(o.clusterID, o.awsProfile, o.verbose)
+	if err != nil {
+		return err
+	}
+
+	healthOutput, err := yaml.Marshal(healthObject)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	fmt.Printf("\n \n")
+	fmt.Println(string(healthOutput))
+
+	return nil
+}
 
+// GetClusterHealth compares the number of running instances of a cluster to the expected number of nodes
+func GetClusterHealth(clusterID string, awsProfile string, verbose bool) (*ClusterHealthCondensedObject, error) {
 	ocmClient, err := utils.CreateConnection()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer ocmClient.Close()
 
-	clusterResp, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(o.clusterID).Get().Send()
+	// Resolves an external ID, name or subscription ID to the cluster, like the other commands
+	cluster, err := utils.GetCluster(ocmClient, clusterID)
 	if err != nil {
-		fmt.Println(err)
-		return err
+		return nil, err
 	}
-	cluster := clusterResp.Body()
+	clusterID = cluster.ID()
 	healthObject := createHealthObject(cluster)
 
 	if cluster.Nodes().AutoscaleCompute().MinReplicas() != 0 {
@@ -109,30 +125,30 @@ func (o *healthOptions) run() error {
 	var ownedLabel string
 	infraID := cluster.InfraID()
 	if cluster.CloudProvider().ID() == "gcp" {
-		clusterHealthClient, err = osdCloud.NewGcpCluster(ocmClient, o.clusterID)
+		clusterHealthClient, err = osdCloud.NewGcpCluster(ocmClient, clusterID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		ownedLabel = "kubernetes-io-cluster-" + infraID
 		defer clusterHealthClient.Close()
 	} else if cluster.CloudProvider().ID() == "aws" {
-		clusterHealthClient, err = osdCloud.NewAwsCluster(ocmClient, o.clusterID, o.awsProfile)
+		clusterHealthClient, err = osdCloud.NewAwsCluster(ocmClient, clusterID, awsProfile)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		ownedLabel = "kubernetes.io/cluster/" + infraID
 		defer clusterHealthClient.Close()
 	} else {
-		return errors.New(fmt.Sprintf("Unknown cloud provider found: %s", cluster.CloudProvider().ID()))
+		return nil, errors.New(fmt.Sprintf("Unknown cloud provider found: %s", cluster.CloudProvider().ID()))
 	}
 	err = clusterHealthClient.Login()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	for _, zone := range clusterHealthClient.GetAZs() {
 		instances, err := clusterHealthClient.GetAllVirtualMachines(zone)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, instance := range instances {
 			name := instance.Name
@@ -145,7 +161,7 @@ func (o *healthOptions) run() error {
 				}
 			}
 			if !belongsToCluster {
-				if o.verbose {
+				if verbose {
 					log.Printf("Skipping a machine not belonging to the cluster: %s\n", name)
 				}
 				continue
@@ -173,17 +189,10 @@ func (o *healthOptions) run() error {
 
 	if err != nil {
 		log.Fatalf("Error getting instances %v", err)
-		return err
+		return nil, err
 	}
 
-	healthOutput, err := yaml.Marshal(&healthObject)
-	if err != nil {
-		log.Fatalf("error: %v", err)
-	}
-	fmt.Printf("\n \n")
-	fmt.Println(string(healthOutput))
-
-	return nil
+	return healthObject, nil
 }
 
 func createHealthObject(cluster *v1.Cluster) *ClusterHealthCondensedObject {
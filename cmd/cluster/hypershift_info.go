@@ -23,6 +23,7 @@ import (
 	"github.com/openshift/osdctl/pkg/osdCloud"
 	"github.com/openshift/osdctl/pkg/osdctlConfig"
 	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/redact"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -942,6 +943,6 @@ func safeDeref(s *string) string {
 
 func verboseLog(msg ...interface{}) {
 	if verbose {
-		fmt.Println(msg...)
+		fmt.Print(redact.Secrets(fmt.Sprintln(msg...)))
 	}
 }
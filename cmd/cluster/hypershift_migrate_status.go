@@ -0,0 +1,491 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/cmd/cluster/dynatrace"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// The hosted clusters are migrated with OADP: backed up by velero on the source management cluster and
+	// restored on the destination one
+	veleroNamespace = "openshift-adp"
+
+	migrationNotStarted   = "not started"
+	migrationSourcePaused = "source paused"
+	migrationBackup       = "backing up"
+	migrationRestore      = "restoring"
+	migrationStarting     = "destination control plane starting"
+	migrationDNSCutover   = "DNS cutover pending"
+	migrationCompleted    = "completed"
+	migrationUnknown      = "unknown"
+
+	dnsSource      = "source"
+	dnsDestination = "destination"
+	dnsUnknown     = "unknown"
+)
+
+var (
+	hostedClusterGVK = schema.GroupVersionKind{Group: "hypershift.openshift.io", Version: "v1beta1", Kind: "HostedCluster"}
+	veleroBackupGVK  = schema.GroupVersionKind{Group: "velero.io", Version: "v1", Kind: "Backup"}
+	veleroRestoreGVK = schema.GroupVersionKind{Group: "velero.io", Version: "v1", Kind: "Restore"}
+
+	// failedVeleroPhases are the final phases of the backups and restores that didn't complete
+	failedVeleroPhases = []string{"Failed", "PartiallyFailed", "FailedValidation"}
+	// apiServices publish the API of the hosted control plane, through a load balancer or the router
+	apiServices = []string{"kube-apiserver", "router"}
+)
+
+// newCmdHypershift implements the hypershift command grouping the hosted cluster utilities
+func newCmdHypershift() *cobra.Command {
+	hypershiftCmd := &cobra.Command{
+		Use:               "hypershift",
+		Short:             "Hosted control plane (HCP) cluster utilities",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	hypershiftCmd.AddCommand(newCmdMigrateStatus())
+	return hypershiftCmd
+}
+
+type migrateStatusOptions struct {
+	clusterID   string
+	source      string
+	destination string
+	reason      string
+}
+
+// migrationSide is the state of the hosted cluster on one of the management clusters
+type migrationSide struct {
+	managementCluster string
+	err               error
+	// namespaces are the HostedCluster and control plane namespaces, when they exist
+	namespaces []string
+
+	hostedClusterFound bool
+	paused             bool
+	available          bool
+	availableMessage   string
+
+	// the velero backup on the source, the restore on the destination
+	veleroName  string
+	veleroPhase string
+
+	podsReady   int
+	podsTotal   int
+	unreadyPods []string
+
+	// apiEndpoints are the load balancer hostnames and IPs publishing the API
+	apiEndpoints []string
+}
+
+func newCmdMigrateStatus() *cobra.Command {
+	ops := &migrateStatusOptions{}
+	migrateStatusCmd := &cobra.Command{
+		Use:   "migrate-status <cluster-id>",
+		Short: "Track the migration of a hosted cluster between management clusters",
+		Long: `Track the migration of a hosted cluster between management clusters and summarize its stage and blockers.
+
+On both management clusters, the HostedCluster (found, paused, available), the velero backup of its namespaces on
+the source and the restore of that backup on the destination (in the ` + veleroNamespace + ` namespace), and the readiness of
+the control plane pods are checked. The API hostname of the cluster is resolved to tell which management cluster
+the DNS points to.
+
+The stages are: not started, source paused, backing up, restoring, destination control plane starting, DNS cutover
+pending and completed.`,
+		Example: `  # Track the migration of a cluster to another management cluster, the source defaults to the
+  # management cluster OCM reports
+  osdctl cluster hypershift migrate-status <cluster-id> --to <management-cluster>`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	migrateStatusCmd.Flags().StringVar(&ops.source, "from", "", "Source management cluster, defaults to the management cluster of the cluster in OCM")
+	migrateStatusCmd.Flags().StringVar(&ops.destination, "to", "", "Destination management cluster")
+	migrateStatusCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for elevating the access to the management clusters, e.g. a ticket, when needed")
+	_ = migrateStatusCmd.MarkFlagRequired("to")
+
+	return migrateStatusCmd
+}
+
+func (o *migrateStatusOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if !cluster.Hypershift().Enabled() {
+		return fmt.Errorf("cluster %s is not a hosted control plane cluster", cluster.ID())
+	}
+
+	var source *v1.Cluster
+	if o.source != "" {
+		source, err = utils.GetCluster(connection, o.source)
+	} else {
+		source, err = utils.GetManagementCluster(cluster.ID())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find the source management cluster: %w", err)
+	}
+	destination, err := utils.GetCluster(connection, o.destination)
+	if err != nil {
+		return fmt.Errorf("failed to find the destination management cluster: %w", err)
+	}
+	if source.ID() == destination.ID() {
+		return fmt.Errorf("OCM already reports %s as the management cluster of %s, pass the previous one with --from", destination.Name(), cluster.ID())
+	}
+
+	// The restores on the destination are those of the backup taken on the source, or of its namespaces
+	sourceSide := o.inspect(cluster, source, veleroBackupGVK, nil, "")
+	destinationSide := o.inspect(cluster, destination, veleroRestoreGVK, sourceSide.namespaces, sourceSide.veleroName)
+
+	dns := apiDNSTarget(cluster.API().URL(), sourceSide.apiEndpoints, destinationSide.apiEndpoints)
+	stage, blockers := summarizeMigration(sourceSide, destinationSide, dns)
+
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"ROLE", "MANAGEMENT CLUSTER", "HOSTEDCLUSTER", "BACKUP/RESTORE", "CONTROL PLANE PODS READY"})
+	for _, side := range []struct {
+		role string
+		side migrationSide
+	}{{"source", sourceSide}, {"destination", destinationSide}} {
+		table.AddRow([]string{side.role, side.side.managementCluster, side.side.hostedClusterState(), side.side.veleroState(), fmt.Sprintf("%d/%d", side.side.podsReady, side.side.podsTotal)})
+	}
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("API DNS points to: %s\n", dns)
+	fmt.Printf("Stage: %s\n", stage)
+	if len(blockers) == 0 {
+		printer.PrintlnGreen("No blockers")
+		return nil
+	}
+	fmt.Println("Blockers:")
+	for _, blocker := range blockers {
+		fmt.Printf("  - %s\n", blocker)
+	}
+	return nil
+}
+
+// inspect reads the state of the hosted cluster on a management cluster. The velero backups or restores are
+// those of the namespaces of the hosted cluster, or of the given backup when it's known.
+func (o *migrateStatusOptions) inspect(cluster *v1.Cluster, managementCluster *v1.Cluster, veleroGVK schema.GroupVersionKind, namespaces []string, backupName string) migrationSide {
+	side := migrationSide{managementCluster: managementCluster.Name()}
+	var reasons []string
+	if o.reason != "" {
+		reasons = append(reasons, o.reason)
+	}
+	kubeCli, _, clientset, err := common.GetKubeConfigAndClient(managementCluster.ID(), reasons...)
+	if err != nil {
+		side.err = fmt.Errorf("failed to access management cluster %s: %w", managementCluster.Name(), err)
+		return side
+	}
+
+	// The namespaces don't exist on the destination before the restore
+	_, hostedClusterNS, hcpNS, err := dynatrace.GetHCPNamespacesFromInternalID(clientset, cluster.ID())
+	if err != nil {
+		hostedClusterNS, hcpNS = "", ""
+	} else {
+		side.namespaces = []string{hostedClusterNS, hcpNS}
+	}
+
+	ctx := context.TODO()
+	if hostedClusterNS != "" {
+		hostedCluster := &unstructured.Unstructured{}
+		hostedCluster.SetGroupVersionKind(hostedClusterGVK)
+		if err := kubeCli.Get(ctx, client.ObjectKey{Namespace: hostedClusterNS, Name: cluster.Name()}, hostedCluster); err == nil {
+			side.hostedClusterFound = true
+			pausedUntil, _, _ := unstructured.NestedString(hostedCluster.Object, "spec", "pausedUntil")
+			side.paused = pausedUntil != ""
+			side.available, side.availableMessage = hostedClusterAvailable(hostedCluster)
+		}
+	}
+
+	side.veleroName, side.veleroPhase, err = latestVeleroObject(ctx, kubeCli, veleroGVK, append(side.namespaces, namespaces...), backupName)
+	if err != nil {
+		side.err = err
+	}
+
+	if hcpNS != "" {
+		side.podsReady, side.podsTotal, side.unreadyPods, err = controlPlanePods(ctx, clientset, hcpNS)
+		if err != nil {
+			side.err = err
+		}
+		side.apiEndpoints = apiEndpoints(ctx, clientset, hcpNS)
+	}
+	return side
+}
+
+// hostedClusterAvailable returns the status and message of the Available condition of the HostedCluster
+func hostedClusterAvailable(hostedCluster *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(hostedCluster.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]interface{})
+		if !ok || condition["type"] != "Available" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		return condition["status"] == "True", message
+	}
+	return false, ""
+}
+
+// latestVeleroObject returns the name and phase of the newest velero backup or restore of the namespaces of
+// the hosted cluster. Restores match the backup by name when it's given, else by the namespaces they restore.
+func latestVeleroObject(ctx context.Context, kubeCli client.Client, gvk schema.GroupVersionKind, namespaces []string, backupName string) (string, string, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := kubeCli.List(ctx, list, client.InNamespace(veleroNamespace)); err != nil {
+		// OADP isn't installed on every management cluster
+		return "", "", nil
+	}
+
+	var matching []unstructured.Unstructured
+	for _, item := range list.Items {
+		if backupName != "" {
+			if name, _, _ := unstructured.NestedString(item.Object, "spec", "backupName"); name == backupName {
+				matching = append(matching, item)
+			}
+			continue
+		}
+		included, _, _ := unstructured.NestedStringSlice(item.Object, "spec", "includedNamespaces")
+		if containsAny(included, namespaces) {
+			matching = append(matching, item)
+		}
+	}
+	if len(matching) == 0 {
+		return "", "", nil
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].GetCreationTimestamp().After(matching[j].GetCreationTimestamp().Time)
+	})
+	phase, _, _ := unstructured.NestedString(matching[0].Object, "status", "phase")
+	return matching[0].GetName(), phase, nil
+}
+
+func containsAny(values []string, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if candidate != "" && value == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// controlPlanePods returns the readiness of the pods of the hosted control plane, completed pods aside
+func controlPlanePods(ctx context.Context, clientset *kubernetes.Clientset, namespace string) (int, int, []string, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to list the pods of %s: %w", namespace, err)
+	}
+	var ready, total int
+	var unready []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		total++
+		if podReady(pod) {
+			ready++
+		} else {
+			unready = append(unready, pod.Name)
+		}
+	}
+	sort.Strings(unready)
+	return ready, total, unready, nil
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// apiEndpoints returns the load balancer hostnames and IPs of the services publishing the API
+func apiEndpoints(ctx context.Context, clientset *kubernetes.Clientset, namespace string) []string {
+	var endpoints []string
+	for _, name := range apiServices {
+		service, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.Hostname != "" {
+				endpoints = append(endpoints, ingress.Hostname)
+			}
+			if ingress.IP != "" {
+				endpoints = append(endpoints, ingress.IP)
+			}
+		}
+	}
+	return endpoints
+}
+
+// apiDNSTarget resolves the API hostname of the cluster and tells which management cluster it points to
+func apiDNSTarget(apiURL string, sourceEndpoints []string, destinationEndpoints []string) string {
+	parsed, err := url.Parse(apiURL)
+	if err != nil || parsed.Hostname() == "" {
+		return dnsUnknown
+	}
+	resolved := resolveAddresses(parsed.Hostname())
+	switch {
+	case len(resolved) == 0:
+		return dnsUnknown
+	case sharesAddress(resolved, destinationEndpoints):
+		return dnsDestination
+	case sharesAddress(resolved, sourceEndpoints):
+		return dnsSource
+	default:
+		return dnsUnknown
+	}
+}
+
+// resolveAddresses returns the canonical name and the addresses of a hostname
+func resolveAddresses(host string) map[string]bool {
+	addresses := map[string]bool{}
+	if cname, err := net.LookupCNAME(host); err == nil {
+		addresses[strings.TrimSuffix(cname, ".")] = true
+	}
+	if ips, err := net.LookupHost(host); err == nil {
+		for _, ip := range ips {
+			addresses[ip] = true
+		}
+	}
+	return addresses
+}
+
+// sharesAddress tells whether one of the endpoints, or one of their addresses, is among the resolved ones
+func sharesAddress(resolved map[string]bool, endpoints []string) bool {
+	for _, endpoint := range endpoints {
+		if resolved[strings.TrimSuffix(endpoint, ".")] {
+			return true
+		}
+		for address := range resolveAddresses(endpoint) {
+			if resolved[address] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s migrationSide) hostedClusterState() string {
+	switch {
+	case s.err != nil && !s.hostedClusterFound:
+		return "unknown"
+	case !s.hostedClusterFound:
+		return "not found"
+	case s.paused:
+		return "paused"
+	case s.available:
+		return "available"
+	default:
+		return "not available"
+	}
+}
+
+func (s migrationSide) veleroState() string {
+	if s.veleroName == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%s (%s)", s.veleroName, s.veleroPhase)
+}
+
+func failedVeleroPhase(phase string) bool {
+	for _, failed := range failedVeleroPhases {
+		if phase == failed {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeMigration returns the stage of the migration and what blocks it
+func summarizeMigration(source migrationSide, destination migrationSide, dns string) (string, []string) {
+	var blockers []string
+	for _, side := range []migrationSide{source, destination} {
+		if side.err != nil {
+			blockers = append(blockers, side.err.Error())
+		}
+	}
+
+	var stage string
+	switch {
+	case !source.hostedClusterFound && !destination.hostedClusterFound:
+		stage = migrationUnknown
+		blockers = append(blockers, "the HostedCluster was found on neither management cluster")
+	case dns == dnsDestination && destination.available:
+		stage = migrationCompleted
+		if source.hostedClusterFound {
+			blockers = append(blockers, fmt.Sprintf("the HostedCluster is still on %s, it has to be removed from the source", source.managementCluster))
+		}
+	case destination.available:
+		stage = migrationDNSCutover
+	case destination.hostedClusterFound:
+		stage = migrationStarting
+	case destination.veleroName != "":
+		stage = migrationRestore
+	case source.veleroName != "":
+		stage = migrationBackup
+	case source.paused:
+		stage = migrationSourcePaused
+	default:
+		stage = migrationNotStarted
+	}
+
+	if failedVeleroPhase(source.veleroPhase) {
+		blockers = append(blockers, fmt.Sprintf("backup %s on %s is %s", source.veleroName, source.managementCluster, source.veleroPhase))
+	}
+	if failedVeleroPhase(destination.veleroPhase) {
+		blockers = append(blockers, fmt.Sprintf("restore %s on %s is %s", destination.veleroName, destination.managementCluster, destination.veleroPhase))
+	}
+	if destination.hostedClusterFound && source.hostedClusterFound && !source.paused {
+		blockers = append(blockers, "the source HostedCluster isn't paused while it's restored on the destination, both management clusters reconcile it")
+	}
+	if destination.hostedClusterFound && !destination.available && destination.availableMessage != "" {
+		blockers = append(blockers, fmt.Sprintf("the destination HostedCluster isn't available: %s", destination.availableMessage))
+	}
+	if destination.hostedClusterFound && len(destination.unreadyPods) > 0 {
+		blockers = append(blockers, fmt.Sprintf("%d control plane pods aren't ready on %s: %s", len(destination.unreadyPods), destination.managementCluster, strings.Join(destination.unreadyPods, ", ")))
+	}
+	if stage != migrationCompleted && len(source.unreadyPods) > 0 {
+		blockers = append(blockers, fmt.Sprintf("%d control plane pods aren't ready on %s: %s", len(source.unreadyPods), source.managementCluster, strings.Join(source.unreadyPods, ", ")))
+	}
+	if dns == dnsUnknown {
+		blockers = append(blockers, "the API hostname doesn't resolve to either management cluster")
+	}
+	return stage, blockers
+}
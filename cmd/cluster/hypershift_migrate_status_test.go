@@ -0,0 +1,96 @@
+package cluster
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeMigration(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       migrationSide
+		destination  migrationSide
+		dns          string
+		wantStage    string
+		wantBlockers []string
+	}{
+		{
+			name:      "Not started",
+			source:    migrationSide{managementCluster: "mc-a", hostedClusterFound: true, available: true},
+			dns:       dnsSource,
+			wantStage: migrationNotStarted,
+		},
+		{
+			name:      "Source paused",
+			source:    migrationSide{managementCluster: "mc-a", hostedClusterFound: true, paused: true},
+			dns:       dnsSource,
+			wantStage: migrationSourcePaused,
+		},
+		{
+			name:         "Backup failed",
+			source:       migrationSide{managementCluster: "mc-a", hostedClusterFound: true, paused: true, veleroName: "hc-backup", veleroPhase: "PartiallyFailed"},
+			dns:          dnsSource,
+			wantStage:    migrationBackup,
+			wantBlockers: []string{"backup hc-backup on mc-a is PartiallyFailed"},
+		},
+		{
+			name:        "Restoring",
+			source:      migrationSide{managementCluster: "mc-a", hostedClusterFound: true, paused: true, veleroName: "hc-backup", veleroPhase: "Completed"},
+			destination: migrationSide{managementCluster: "mc-b", veleroName: "hc-restore", veleroPhase: "InProgress"},
+			dns:         dnsSource,
+			wantStage:   migrationRestore,
+		},
+		{
+			name:        "Destination starting with an unpaused source",
+			source:      migrationSide{managementCluster: "mc-a", hostedClusterFound: true, available: true},
+			destination: migrationSide{managementCluster: "mc-b", hostedClusterFound: true, availableMessage: "waiting for etcd", unreadyPods: []string{"etcd-0"}},
+			dns:         dnsSource,
+			wantStage:   migrationStarting,
+			wantBlockers: []string{
+				"the source HostedCluster isn't paused while it's restored on the destination, both management clusters reconcile it",
+				"the destination HostedCluster isn't available: waiting for etcd",
+				"1 control plane pods aren't ready on mc-b: etcd-0",
+			},
+		},
+		{
+			name:        "DNS cutover pending",
+			source:      migrationSide{managementCluster: "mc-a", hostedClusterFound: true, paused: true},
+			destination: migrationSide{managementCluster: "mc-b", hostedClusterFound: true, available: true},
+			dns:         dnsSource,
+			wantStage:   migrationDNSCutover,
+		},
+		{
+			name:         "Completed with the source left behind",
+			source:       migrationSide{managementCluster: "mc-a", hostedClusterFound: true, paused: true, unreadyPods: []string{"kube-apiserver-0"}},
+			destination:  migrationSide{managementCluster: "mc-b", hostedClusterFound: true, available: true},
+			dns:          dnsDestination,
+			wantStage:    migrationCompleted,
+			wantBlockers: []string{"the HostedCluster is still on mc-a, it has to be removed from the source"},
+		},
+		{
+			name:        "Unreachable management cluster",
+			source:      migrationSide{managementCluster: "mc-a", err: errors.New("failed to access management cluster mc-a")},
+			destination: migrationSide{managementCluster: "mc-b"},
+			dns:         dnsUnknown,
+			wantStage:   migrationUnknown,
+			wantBlockers: []string{
+				"failed to access management cluster mc-a",
+				"the HostedCluster was found on neither management cluster",
+				"the API hostname doesn't resolve to either management cluster",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage, blockers := summarizeMigration(tt.source, tt.destination, tt.dns)
+			if stage != tt.wantStage {
+				t.Errorf("summarizeMigration() stage = %q, want %q", stage, tt.wantStage)
+			}
+			if !reflect.DeepEqual(blockers, tt.wantBlockers) {
+				t.Errorf("summarizeMigration() blockers = %q, want %q", blockers, tt.wantBlockers)
+			}
+		})
+	}
+}
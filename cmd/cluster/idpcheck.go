@@ -0,0 +1,369 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	idpCheckPass = "PASS"
+	idpCheckWarn = "WARN"
+	idpCheckFail = "FAIL"
+
+	oauthNamespace     = "openshift-authentication"
+	oauthLabelSelector = "app=oauth-openshift"
+	// oauthErrorRateWarn is the number of authentication errors per hour above which the check warns
+	oauthErrorRateWarn = 10
+)
+
+// authErrorPatterns classify the oauth-openshift log lines, the first matching pattern wins
+var authErrorPatterns = []struct {
+	category string
+	patterns []string
+}{
+	{"IDP unreachable", []string{"dial tcp", "i/o timeout", "connection refused", "no such host", "context deadline exceeded"}},
+	{"certificate error", []string{"x509:", "certificate signed by unknown authority"}},
+	{"LDAP error", []string{"LDAP Result Code"}},
+	{"invalid credentials", []string{"invalid credentials", "Invalid Credentials", "login failed"}},
+	{"user mapping error", []string{"identity already mapped", "could not create user"}},
+	{"other authentication error", []string{"error authenticating", "authentication error", "AuthenticationError"}},
+}
+
+type idpCheckOptions struct {
+	clusterID string
+	since     time.Duration
+	timeout   time.Duration
+}
+
+type idpCheckResult struct {
+	Check   string
+	Status  string
+	Details string
+}
+
+// idpEndpoint is the host:port an identity provider is reached at, tls is false for plain LDAP
+type idpEndpoint struct {
+	address string
+	tls     bool
+}
+
+func newCmdIdpCheck() *cobra.Command {
+	ops := &idpCheckOptions{}
+	idpCheckCmd := &cobra.Command{
+		Use:     "idp-check <cluster-id>",
+		Aliases: []string{"ldap-check"},
+		Short:   "Diagnose the identity providers of a cluster",
+		Long: `Run the standard checks when customers report login failures:
+  - list the identity providers configured in OCM
+  - check that the identity provider endpoints are reachable (from this machine)
+  - check the health of the oauth-openshift pods, through backplane
+  - count the authentication errors logged by the oauth-openshift pods recently, by category
+
+Hosted control plane clusters run the oauth server on their management cluster, only the first two
+checks are made for them.`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	idpCheckCmd.Flags().DurationVar(&ops.since, "since", time.Hour, "How far back the oauth-openshift logs are searched for authentication errors")
+	idpCheckCmd.Flags().DurationVar(&ops.timeout, "timeout", 5*time.Second, "Timeout of the connections to the identity providers")
+
+	return idpCheckCmd
+}
+
+func (o *idpCheckOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	response, err := connection.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).IdentityProviders().List().Send()
+	if err != nil {
+		return fmt.Errorf("failed to list the identity providers of %s: %w", cluster.ID(), err)
+	}
+	idps := response.Items().Slice()
+
+	fmt.Println(delimiter + "Identity providers")
+	printIdentityProviders(idps)
+	fmt.Println()
+
+	var results []idpCheckResult
+	if len(idps) == 0 {
+		results = append(results, idpCheckResult{"Identity providers", idpCheckWarn, "no identity provider configured in OCM"})
+	}
+	for _, idp := range idps {
+		results = append(results, o.checkIdentityProvider(idp))
+	}
+
+	if cluster.Hypershift().Enabled() {
+		results = append(results, idpCheckResult{"OAuth server", idpCheckWarn, "runs on the management cluster of this hosted control plane, not checked"})
+	} else {
+		_, _, clientset, err := common.GetKubeConfigAndClient(cluster.ID())
+		if err != nil {
+			results = append(results, idpCheckResult{"OAuth server", idpCheckFail, fmt.Sprintf("failed to access the cluster: %v", err)})
+		} else {
+			results = append(results, o.checkOAuthServer(clientset)...)
+		}
+	}
+
+	fmt.Println(delimiter + "Checks")
+	failures := printIdpCheckResults(results)
+	if failures > 0 {
+		return fmt.Errorf("%d identity provider checks failed", failures)
+	}
+	return nil
+}
+
+func printIdentityProviders(idps []*cmv1.IdentityProvider) {
+	if len(idps) == 0 {
+		fmt.Println("None")
+		return
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"NAME", "TYPE", "MAPPING METHOD", "ENDPOINT"})
+	for _, idp := range idps {
+		endpoint := identityProviderURL(idp)
+		if endpoint == "" {
+			endpoint = "-"
+		}
+		table.AddRow([]string{idp.Name(), string(idp.Type()), string(idp.MappingMethod()), endpoint})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the identity providers: %v\n", err)
+	}
+}
+
+// identityProviderURL returns the URL of the service backing the identity provider, empty for htpasswd
+func identityProviderURL(idp *cmv1.IdentityProvider) string {
+	switch idp.Type() {
+	case cmv1.IdentityProviderTypeLDAP:
+		return idp.LDAP().URL()
+	case cmv1.IdentityProviderTypeOpenID:
+		return idp.OpenID().Issuer()
+	case cmv1.IdentityProviderTypeGithub:
+		if hostname := idp.Github().Hostname(); hostname != "" {
+			return "https://" + hostname
+		}
+		return "https://github.com"
+	case cmv1.IdentityProviderTypeGitlab:
+		return idp.Gitlab().URL()
+	case cmv1.IdentityProviderTypeGoogle:
+		return "https://accounts.google.com"
+	}
+	return ""
+}
+
+// parseIdpEndpoint returns the address to connect to for the identity provider URL, e.g.
+// ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid gives ldap.example.com:636
+func parseIdpEndpoint(rawURL string) (idpEndpoint, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return idpEndpoint{}, err
+	}
+	if parsed.Hostname() == "" {
+		return idpEndpoint{}, fmt.Errorf("no host in %s", rawURL)
+	}
+
+	endpoint := idpEndpoint{tls: true}
+	port := parsed.Port()
+	switch strings.ToLower(parsed.Scheme) {
+	case "ldap":
+		endpoint.tls = false
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		if port == "" {
+			port = "636"
+		}
+	case "https":
+		if port == "" {
+			port = "443"
+		}
+	case "http":
+		endpoint.tls = false
+		if port == "" {
+			port = "80"
+		}
+	default:
+		return idpEndpoint{}, fmt.Errorf("unsupported scheme '%s' in %s", parsed.Scheme, rawURL)
+	}
+	endpoint.address = net.JoinHostPort(parsed.Hostname(), port)
+	return endpoint, nil
+}
+
+func (o *idpCheckOptions) checkIdentityProvider(idp *cmv1.IdentityProvider) idpCheckResult {
+	check := fmt.Sprintf("IDP %s reachability", idp.Name())
+	rawURL := identityProviderURL(idp)
+	if rawURL == "" {
+		return idpCheckResult{check, idpCheckPass, fmt.Sprintf("%s needs no external endpoint", idp.Type())}
+	}
+	endpoint, err := parseIdpEndpoint(rawURL)
+	if err != nil {
+		return idpCheckResult{check, idpCheckFail, err.Error()}
+	}
+
+	dialer := &net.Dialer{Timeout: o.timeout}
+	start := time.Now()
+	if endpoint.tls {
+		conn, err := tls.DialWithDialer(dialer, "tcp", endpoint.address, &tls.Config{MinVersion: tls.VersionTLS12})
+		if err != nil {
+			return idpCheckResult{check, idpCheckFail, fmt.Sprintf("%s: %v", endpoint.address, err)}
+		}
+		_ = conn.Close()
+	} else {
+		conn, err := dialer.Dial("tcp", endpoint.address)
+		if err != nil {
+			return idpCheckResult{check, idpCheckFail, fmt.Sprintf("%s: %v", endpoint.address, err)}
+		}
+		_ = conn.Close()
+	}
+	details := fmt.Sprintf("%s reachable in %s", endpoint.address, time.Since(start).Round(time.Millisecond))
+	if !endpoint.tls {
+		return idpCheckResult{check, idpCheckWarn, details + ", without TLS"}
+	}
+	return idpCheckResult{check, idpCheckPass, details}
+}
+
+func (o *idpCheckOptions) checkOAuthServer(clientset *kubernetes.Clientset) []idpCheckResult {
+	pods, err := clientset.CoreV1().Pods(oauthNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: oauthLabelSelector})
+	if err != nil {
+		return []idpCheckResult{{"OAuth pods", idpCheckFail, fmt.Sprintf("failed to list the pods: %v", err)}}
+	}
+	results := []idpCheckResult{evaluateOAuthPods(pods.Items)}
+
+	sinceSeconds := int64(o.since.Seconds())
+	errors := map[string]int{}
+	for _, pod := range pods.Items {
+		logs, err := clientset.CoreV1().Pods(oauthNamespace).GetLogs(pod.Name, &corev1.PodLogOptions{SinceSeconds: &sinceSeconds}).DoRaw(context.TODO())
+		if err != nil {
+			results = append(results, idpCheckResult{"OAuth logs", idpCheckWarn, fmt.Sprintf("failed to get the logs of %s: %v", pod.Name, err)})
+			continue
+		}
+		for category, count := range countAuthErrors(string(logs)) {
+			errors[category] += count
+		}
+	}
+	return append(results, evaluateAuthErrors(errors, o.since))
+}
+
+// evaluateOAuthPods checks that the oauth-openshift pods are running, ready and not restarting
+func evaluateOAuthPods(pods []corev1.Pod) idpCheckResult {
+	if len(pods) == 0 {
+		return idpCheckResult{"OAuth pods", idpCheckFail, fmt.Sprintf("no pod matching %s in %s", oauthLabelSelector, oauthNamespace)}
+	}
+
+	var problems []string
+	for _, pod := range pods {
+		ready := false
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				ready = true
+			}
+		}
+		if !ready {
+			problems = append(problems, fmt.Sprintf("%s is %s and not ready", pod.Name, pod.Status.Phase))
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.RestartCount > 0 {
+				problems = append(problems, fmt.Sprintf("%s/%s restarted %d times", pod.Name, status.Name, status.RestartCount))
+			}
+		}
+	}
+
+	switch {
+	case len(problems) == 0:
+		return idpCheckResult{"OAuth pods", idpCheckPass, fmt.Sprintf("%d pods ready", len(pods))}
+	case len(problems) < len(pods):
+		return idpCheckResult{"OAuth pods", idpCheckWarn, strings.Join(problems, "; ")}
+	}
+	return idpCheckResult{"OAuth pods", idpCheckFail, strings.Join(problems, "; ")}
+}
+
+// countAuthErrors counts the authentication errors in the oauth-openshift logs by category
+func countAuthErrors(logs string) map[string]int {
+	counts := map[string]int{}
+	for _, line := range strings.Split(logs, "\n") {
+		if line == "" {
+			continue
+		}
+		for _, category := range authErrorPatterns {
+			matched := false
+			for _, pattern := range category.patterns {
+				if strings.Contains(line, pattern) {
+					matched = true
+					break
+				}
+			}
+			if matched {
+				counts[category.category]++
+				break
+			}
+		}
+	}
+	return counts
+}
+
+func evaluateAuthErrors(counts map[string]int, since time.Duration) idpCheckResult {
+	check := fmt.Sprintf("Authentication errors (last %s)", since)
+	total := 0
+	var categories []string
+	for category, count := range counts {
+		total += count
+		categories = append(categories, fmt.Sprintf("%s: %d", category, count))
+	}
+	if total == 0 {
+		return idpCheckResult{check, idpCheckPass, "none"}
+	}
+	sort.Strings(categories)
+
+	details := fmt.Sprintf("%d errors (%s)", total, strings.Join(categories, ", "))
+	perHour := float64(total) / since.Hours()
+	if perHour >= oauthErrorRateWarn || counts["IDP unreachable"] > 0 || counts["certificate error"] > 0 {
+		return idpCheckResult{check, idpCheckFail, details}
+	}
+	return idpCheckResult{check, idpCheckWarn, details}
+}
+
+func printIdpCheckResults(results []idpCheckResult) int {
+	failures := 0
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, result := range results {
+		if result.Status == idpCheckFail {
+			failures++
+		}
+		table.AddRow([]string{result.Check, result.Status, result.Details})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the identity provider checks: %v\n", err)
+	}
+	return failures
+}
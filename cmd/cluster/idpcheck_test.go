@@ -0,0 +1,113 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseIdpEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    idpEndpoint
+		wantErr bool
+	}{
+		{name: "ldaps default port", url: "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid", want: idpEndpoint{"ldap.example.com:636", true}},
+		{name: "ldap default port", url: "ldap://ldap.example.com/ou=users?uid", want: idpEndpoint{"ldap.example.com:389", false}},
+		{name: "explicit port", url: "ldaps://10.0.0.1:3269/dc=example", want: idpEndpoint{"10.0.0.1:3269", true}},
+		{name: "openid issuer", url: "https://sso.example.com/auth/realms/osd", want: idpEndpoint{"sso.example.com:443", true}},
+		{name: "unsupported scheme", url: "ftp://example.com", wantErr: true},
+		{name: "no host", url: "ldaps:///dc=example", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseIdpEndpoint(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIdpEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseIdpEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountAuthErrors(t *testing.T) {
+	logs := `I0301 10:00:00.000000 1 log.go:245] starting
+E0301 10:01:00.000000 1 errorpage.go:28] AuthenticationError: LDAP Result Code 49 "Invalid Credentials"
+E0301 10:02:00.000000 1 errorpage.go:28] AuthenticationError: dial tcp 10.0.0.1:636: i/o timeout
+E0301 10:03:00.000000 1 errorpage.go:28] AuthenticationError: x509: certificate signed by unknown authority
+E0301 10:04:00.000000 1 login.go:181] Login failed for user: error authenticating "jdoe"
+`
+	want := map[string]int{
+		"LDAP error":                 1,
+		"IDP unreachable":            1,
+		"certificate error":          1,
+		"other authentication error": 1,
+	}
+	if got := countAuthErrors(logs); !reflect.DeepEqual(got, want) {
+		t.Errorf("countAuthErrors() = %v, want %v", got, want)
+	}
+}
+
+func TestEvaluateAuthErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[string]int
+		want   string
+	}{
+		{name: "no errors", counts: map[string]int{}, want: idpCheckPass},
+		{name: "few invalid credentials", counts: map[string]int{"invalid credentials": 3}, want: idpCheckWarn},
+		{name: "high error rate", counts: map[string]int{"invalid credentials": 30}, want: idpCheckFail},
+		{name: "unreachable IDP", counts: map[string]int{"IDP unreachable": 1}, want: idpCheckFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateAuthErrors(tt.counts, time.Hour); got.Status != tt.want {
+				t.Errorf("evaluateAuthErrors() = %v, want status %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func oauthPod(name string, ready bool, restarts int32) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "oauth-openshift", RestartCount: restarts}},
+		},
+	}
+}
+
+func TestEvaluateOAuthPods(t *testing.T) {
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want string
+	}{
+		{name: "no pods", want: idpCheckFail},
+		{name: "all ready", pods: []corev1.Pod{oauthPod("a", true, 0), oauthPod("b", true, 0), oauthPod("c", true, 0)}, want: idpCheckPass},
+		{name: "one restarting", pods: []corev1.Pod{oauthPod("a", true, 2), oauthPod("b", true, 0), oauthPod("c", true, 0)}, want: idpCheckWarn},
+		{name: "none ready", pods: []corev1.Pod{oauthPod("a", false, 0), oauthPod("b", false, 0)}, want: idpCheckFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateOAuthPods(tt.pods); got.Status != tt.want {
+				t.Errorf("evaluateOAuthPods() = %v, want status %s", got, tt.want)
+			}
+		})
+	}
+}
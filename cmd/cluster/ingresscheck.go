@@ -0,0 +1,423 @@
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ingressCheckPass = "PASS"
+	ingressCheckWarn = "WARN"
+	ingressCheckFail = "FAIL"
+
+	ingressOperatorNamespace = "openshift-ingress-operator"
+	ingressNamespace         = "openshift-ingress"
+	routerDeploymentLabel    = "ingresscontroller.operator.openshift.io/deployment-ingresscontroller"
+	awsLoadBalancerType      = "service.beta.kubernetes.io/aws-load-balancer-type"
+
+	// ingressCertificateWarning is how long before their expiry the certificates are reported
+	ingressCertificateWarning = 14 * 24 * time.Hour
+)
+
+type ingressCheckOptions struct {
+	clusterID string
+	reason    string
+}
+
+type ingressCheckResult struct {
+	Check   string
+	Status  string
+	Details string
+}
+
+// ingressELBClient and ingressELBV2Client are the parts of the AWS load balancer APIs the ingress checks use
+type ingressELBClient interface {
+	DescribeInstanceHealth(context.Context, *elasticloadbalancing.DescribeInstanceHealthInput, ...func(*elasticloadbalancing.Options)) (*elasticloadbalancing.DescribeInstanceHealthOutput, error)
+}
+
+type ingressELBV2Client interface {
+	DescribeLoadBalancers(context.Context, *elasticloadbalancingv2.DescribeLoadBalancersInput, ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
+	DescribeTargetGroups(context.Context, *elasticloadbalancingv2.DescribeTargetGroupsInput, ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetGroupsOutput, error)
+	DescribeTargetHealth(context.Context, *elasticloadbalancingv2.DescribeTargetHealthInput, ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error)
+}
+
+func newCmdIngressCheck() *cobra.Command {
+	ops := &ingressCheckOptions{}
+	ingressCheckCmd := &cobra.Command{
+		Use:   "ingress-check <cluster-id>",
+		Short: "Diagnose the ingress controllers of a cluster",
+		Long: `Run the standard checks when customers report that their applications are unreachable, for the default
+ingress controller and every custom one:
+  - the ingress controller is available and not degraded
+  - the router pods are running and ready
+  - the load balancer of the router service is provisioned and, on AWS, has healthy instances or targets
+  - the wildcard DNS record of the ingress domain resolves to the load balancer (from this machine)
+  - the default certificate isn't expired nor about to, and covers the ingress domain`,
+		Example:           `  osdctl cluster ingress-check <cluster-id> --reason OHSS-1234`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	ingressCheckCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this command, which requires elevation to read the certificates (e.g. an OHSS ticket)")
+
+	return ingressCheckCmd
+}
+
+func (o *ingressCheckOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	var elevationReasons []string
+	if o.reason != "" {
+		elevationReasons = append(elevationReasons, o.reason, "Checking the ingress controllers")
+	}
+	kubeCli, _, clientset, err := common.GetKubeConfigAndClient(cluster.ID(), elevationReasons...)
+	if err != nil {
+		return fmt.Errorf("failed to access cluster %s: %w", cluster.ID(), err)
+	}
+
+	var controllers operatorv1.IngressControllerList
+	if err := kubeCli.List(context.TODO(), &controllers, client.InNamespace(ingressOperatorNamespace)); err != nil {
+		return fmt.Errorf("failed to list the ingress controllers: %w", err)
+	}
+	if len(controllers.Items) == 0 {
+		return fmt.Errorf("cluster %s has no ingress controller in %s", cluster.ID(), ingressOperatorNamespace)
+	}
+
+	var elbClient ingressELBClient
+	var elbv2Client ingressELBV2Client
+	if cluster.CloudProvider().ID() == "aws" {
+		cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to access the AWS account, the load balancers won't be checked: %v\n", err)
+		} else {
+			elbClient = elasticloadbalancing.NewFromConfig(cfg)
+			elbv2Client = elasticloadbalancingv2.NewFromConfig(cfg)
+		}
+	}
+
+	var failures int
+	for _, controller := range controllers.Items {
+		fmt.Printf("%sIngress controller %s (%s)\n", delimiter, controller.Name, controller.Status.Domain)
+		results := o.checkIngressController(clientset, cluster, &controller, elbClient, elbv2Client)
+		failures += printIngressCheckResults(results)
+		fmt.Println()
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d ingress checks failed", failures)
+	}
+	return nil
+}
+
+func (o *ingressCheckOptions) checkIngressController(clientset *kubernetes.Clientset, cluster *cmv1.Cluster, controller *operatorv1.IngressController, elbClient ingressELBClient, elbv2Client ingressELBV2Client) []ingressCheckResult {
+	results := []ingressCheckResult{evaluateIngressConditions(controller.Status.Conditions)}
+
+	pods, err := clientset.CoreV1().Pods(ingressNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", routerDeploymentLabel, controller.Name)})
+	if err != nil {
+		results = append(results, ingressCheckResult{"Router pods", ingressCheckFail, fmt.Sprintf("failed to list the pods: %v", err)})
+	} else {
+		results = append(results, evaluateRouterPods(pods.Items))
+	}
+
+	strategy := controller.Status.EndpointPublishingStrategy
+	if strategy == nil || strategy.Type != operatorv1.LoadBalancerServiceStrategyType {
+		strategyType := "unknown"
+		if strategy != nil {
+			strategyType = string(strategy.Type)
+		}
+		results = append(results, ingressCheckResult{"Load balancer", ingressCheckWarn, fmt.Sprintf("published with the %s strategy, not checked", strategyType)})
+	} else {
+		results = append(results, checkRouterLoadBalancer(clientset, cluster, controller, elbClient, elbv2Client)...)
+	}
+
+	return append(results, checkIngressCertificate(clientset, controller))
+}
+
+// evaluateIngressConditions checks the ingress controller is available and not degraded
+func evaluateIngressConditions(conditions []operatorv1.OperatorCondition) ingressCheckResult {
+	const check = "Ingress controller"
+	available := false
+	var problems []string
+	for _, condition := range conditions {
+		switch condition.Type {
+		case operatorv1.OperatorStatusTypeAvailable:
+			available = condition.Status == operatorv1.ConditionTrue
+			if !available {
+				problems = append(problems, fmt.Sprintf("not available: %s", condition.Message))
+			}
+		case operatorv1.OperatorStatusTypeDegraded:
+			if condition.Status == operatorv1.ConditionTrue {
+				problems = append(problems, fmt.Sprintf("degraded: %s", condition.Message))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return ingressCheckResult{check, ingressCheckFail, strings.Join(problems, "; ")}
+	}
+	if !available {
+		return ingressCheckResult{check, ingressCheckWarn, "no Available condition reported"}
+	}
+	return ingressCheckResult{check, ingressCheckPass, "available"}
+}
+
+// evaluateRouterPods checks the router pods are ready
+func evaluateRouterPods(pods []corev1.Pod) ingressCheckResult {
+	const check = "Router pods"
+	if len(pods) == 0 {
+		return ingressCheckResult{check, ingressCheckFail, "no router pod"}
+	}
+	var problems []string
+	for _, pod := range pods {
+		ready := false
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				ready = true
+			}
+		}
+		if !ready {
+			problems = append(problems, fmt.Sprintf("%s is %s and not ready", pod.Name, pod.Status.Phase))
+		}
+	}
+	switch {
+	case len(problems) == 0:
+		return ingressCheckResult{check, ingressCheckPass, fmt.Sprintf("%d pods ready", len(pods))}
+	case len(problems) < len(pods):
+		return ingressCheckResult{check, ingressCheckWarn, strings.Join(problems, "; ")}
+	}
+	return ingressCheckResult{check, ingressCheckFail, strings.Join(problems, "; ")}
+}
+
+// checkRouterLoadBalancer checks the load balancer of the router service, its health on AWS and the DNS
+// record of the ingress domain
+func checkRouterLoadBalancer(clientset *kubernetes.Clientset, cluster *cmv1.Cluster, controller *operatorv1.IngressController, elbClient ingressELBClient, elbv2Client ingressELBV2Client) []ingressCheckResult {
+	serviceName := "router-" + controller.Name
+	service, err := clientset.CoreV1().Services(ingressNamespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	if err != nil {
+		return []ingressCheckResult{{"Load balancer", ingressCheckFail, fmt.Sprintf("failed to get the service %s: %v", serviceName, err)}}
+	}
+	if len(service.Status.LoadBalancer.Ingress) == 0 {
+		return []ingressCheckResult{{"Load balancer", ingressCheckFail, fmt.Sprintf("no load balancer provisioned for the service %s", serviceName)}}
+	}
+	lbIngress := service.Status.LoadBalancer.Ingress[0]
+	address := lbIngress.Hostname
+	if address == "" {
+		address = lbIngress.IP
+	}
+	results := []ingressCheckResult{{"Load balancer", ingressCheckPass, address}}
+
+	switch {
+	case cluster.CloudProvider().ID() != "aws":
+		results = append(results, ingressCheckResult{"Load balancer health", ingressCheckWarn, fmt.Sprintf("only checked on AWS, not %s", cluster.CloudProvider().ID())})
+	case elbClient == nil || elbv2Client == nil:
+		results = append(results, ingressCheckResult{"Load balancer health", ingressCheckWarn, "the AWS account couldn't be accessed"})
+	case lbIngress.Hostname == "":
+		results = append(results, ingressCheckResult{"Load balancer health", ingressCheckWarn, "the load balancer has no hostname"})
+	case service.Annotations[awsLoadBalancerType] == "nlb":
+		results = append(results, checkNetworkLoadBalancer(elbv2Client, lbIngress.Hostname))
+	default:
+		results = append(results, checkClassicLoadBalancer(elbClient, lbIngress.Hostname))
+	}
+
+	return append(results, checkIngressDNS(controller.Status.Domain, lbIngress))
+}
+
+// loadBalancerName returns the name of an AWS load balancer from its hostname, e.g. a1b2c3 for
+// internal-a1b2c3-1234567890.us-east-1.elb.amazonaws.com or a1b2c3-0123456789abcdef.elb.us-east-1.amazonaws.com
+func loadBalancerName(hostname string) string {
+	label, _, _ := strings.Cut(hostname, ".")
+	label = strings.TrimPrefix(label, "internal-")
+	if i := strings.LastIndex(label, "-"); i > 0 {
+		return label[:i]
+	}
+	return label
+}
+
+func checkClassicLoadBalancer(elbClient ingressELBClient, hostname string) ingressCheckResult {
+	const check = "Load balancer health"
+	name := loadBalancerName(hostname)
+	output, err := elbClient.DescribeInstanceHealth(context.TODO(), &elasticloadbalancing.DescribeInstanceHealthInput{LoadBalancerName: aws.String(name)})
+	if err != nil {
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("failed to get the health of load balancer %s: %v", name, err)}
+	}
+	healthy := 0
+	for _, state := range output.InstanceStates {
+		if aws.ToString(state.State) == "InService" {
+			healthy++
+		}
+	}
+	return evaluateTargetHealth(check, name, healthy, len(output.InstanceStates))
+}
+
+func checkNetworkLoadBalancer(elbv2Client ingressELBV2Client, hostname string) ingressCheckResult {
+	const check = "Load balancer health"
+	name := loadBalancerName(hostname)
+	lbs, err := elbv2Client.DescribeLoadBalancers(context.TODO(), &elasticloadbalancingv2.DescribeLoadBalancersInput{Names: []string{name}})
+	if err != nil {
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("failed to get load balancer %s: %v", name, err)}
+	}
+	if len(lbs.LoadBalancers) == 0 {
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("load balancer %s doesn't exist in the AWS account", name)}
+	}
+	lb := lbs.LoadBalancers[0]
+	if lb.State != nil && lb.State.Code != elbv2types.LoadBalancerStateEnumActive {
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("load balancer %s is %s", name, lb.State.Code)}
+	}
+
+	groups, err := elbv2Client.DescribeTargetGroups(context.TODO(), &elasticloadbalancingv2.DescribeTargetGroupsInput{LoadBalancerArn: lb.LoadBalancerArn})
+	if err != nil {
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("failed to get the target groups of load balancer %s: %v", name, err)}
+	}
+	healthy, total := 0, 0
+	for _, group := range groups.TargetGroups {
+		health, err := elbv2Client.DescribeTargetHealth(context.TODO(), &elasticloadbalancingv2.DescribeTargetHealthInput{TargetGroupArn: group.TargetGroupArn})
+		if err != nil {
+			return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("failed to get the health of target group %s: %v", aws.ToString(group.TargetGroupName), err)}
+		}
+		for _, target := range health.TargetHealthDescriptions {
+			total++
+			if target.TargetHealth != nil && target.TargetHealth.State == elbv2types.TargetHealthStateEnumHealthy {
+				healthy++
+			}
+		}
+	}
+	return evaluateTargetHealth(check, name, healthy, total)
+}
+
+// evaluateTargetHealth fails when no target of the load balancer is healthy, and warns when some aren't
+func evaluateTargetHealth(check string, name string, healthy int, total int) ingressCheckResult {
+	details := fmt.Sprintf("%s: %d of %d targets healthy", name, healthy, total)
+	switch {
+	case total == 0 || healthy == 0:
+		return ingressCheckResult{check, ingressCheckFail, details}
+	case healthy < total:
+		return ingressCheckResult{check, ingressCheckWarn, details}
+	}
+	return ingressCheckResult{check, ingressCheckPass, details}
+}
+
+// checkIngressDNS resolves a random name of the ingress domain, as the wildcard record serves any route
+func checkIngressDNS(domain string, lbIngress corev1.LoadBalancerIngress) ingressCheckResult {
+	const check = "Wildcard DNS"
+	if domain == "" {
+		return ingressCheckResult{check, ingressCheckWarn, "the ingress controller has no domain"}
+	}
+	name := fmt.Sprintf("ingress-check-%s.%s", rand.String(6), domain)
+	recordIPs, err := net.LookupHost(name)
+	if err != nil {
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("*.%s doesn't resolve: %v", domain, err)}
+	}
+	lbIPs := []string{lbIngress.IP}
+	if lbIngress.Hostname != "" {
+		if lbIPs, err = net.LookupHost(lbIngress.Hostname); err != nil {
+			return ingressCheckResult{check, ingressCheckWarn, fmt.Sprintf("the load balancer %s doesn't resolve: %v", lbIngress.Hostname, err)}
+		}
+	}
+	return evaluateIngressDNS(domain, recordIPs, lbIPs)
+}
+
+// evaluateIngressDNS checks the wildcard record points to the load balancer. The addresses of a load
+// balancer rotate, sharing any of them is enough.
+func evaluateIngressDNS(domain string, recordIPs []string, lbIPs []string) ingressCheckResult {
+	const check = "Wildcard DNS"
+	for _, recordIP := range recordIPs {
+		for _, lbIP := range lbIPs {
+			if recordIP == lbIP {
+				return ingressCheckResult{check, ingressCheckPass, fmt.Sprintf("*.%s resolves to the load balancer", domain)}
+			}
+		}
+	}
+	return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("*.%s resolves to %s, not to the load balancer (%s)", domain, strings.Join(recordIPs, ", "), strings.Join(lbIPs, ", "))}
+}
+
+// checkIngressCertificate checks the default certificate of the ingress controller, the one generated by
+// the ingress operator unless a custom one is set
+func checkIngressCertificate(clientset *kubernetes.Clientset, controller *operatorv1.IngressController) ingressCheckResult {
+	const check = "Default certificate"
+	secretName := "router-certs-" + controller.Name
+	if controller.Spec.DefaultCertificate != nil && controller.Spec.DefaultCertificate.Name != "" {
+		secretName = controller.Spec.DefaultCertificate.Name
+	}
+	secret, err := clientset.CoreV1().Secrets(ingressNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return ingressCheckResult{check, ingressCheckWarn, fmt.Sprintf("failed to get the secret %s (pass --reason to elevate): %v", secretName, err)}
+	}
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("the secret %s has no PEM certificate", secretName)}
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("failed to parse the certificate of %s: %v", secretName, err)}
+	}
+	return evaluateIngressCertificate(certificate, controller.Status.Domain, time.Now())
+}
+
+// evaluateIngressCertificate checks the certificate is valid for a while and covers the ingress domain
+func evaluateIngressCertificate(certificate *x509.Certificate, domain string, now time.Time) ingressCheckResult {
+	const check = "Default certificate"
+	expiry := certificate.NotAfter.UTC().Format(time.DateOnly)
+	switch {
+	case now.After(certificate.NotAfter):
+		return ingressCheckResult{check, ingressCheckFail, fmt.Sprintf("expired on %s", expiry)}
+	case now.Add(ingressCertificateWarning).After(certificate.NotAfter):
+		return ingressCheckResult{check, ingressCheckWarn, fmt.Sprintf("expires on %s", expiry)}
+	}
+	if domain != "" {
+		if err := certificate.VerifyHostname("ingress-check." + domain); err != nil {
+			return ingressCheckResult{check, ingressCheckWarn, fmt.Sprintf("doesn't cover *.%s, expires on %s", domain, expiry)}
+		}
+	}
+	return ingressCheckResult{check, ingressCheckPass, fmt.Sprintf("expires on %s", expiry)}
+}
+
+func printIngressCheckResults(results []ingressCheckResult) int {
+	failures := 0
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, result := range results {
+		if result.Status == ingressCheckFail {
+			failures++
+		}
+		table.AddRow([]string{result.Check, result.Status, result.Details})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the ingress checks: %v\n", err)
+	}
+	return failures
+}
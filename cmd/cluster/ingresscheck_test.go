@@ -0,0 +1,118 @@
+package cluster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestLoadBalancerName(t *testing.T) {
+	tests := []struct {
+		hostname string
+		want     string
+	}{
+		{hostname: "a1b2c3d4e5-1234567890.us-east-1.elb.amazonaws.com", want: "a1b2c3d4e5"},
+		{hostname: "internal-a1b2c3d4e5-1234567890.us-east-1.elb.amazonaws.com", want: "a1b2c3d4e5"},
+		{hostname: "a1b2c3d4e5-0123456789abcdef.elb.us-east-1.amazonaws.com", want: "a1b2c3d4e5"},
+		{hostname: "router", want: "router"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			if got := loadBalancerName(tt.hostname); got != tt.want {
+				t.Errorf("loadBalancerName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateTargetHealth(t *testing.T) {
+	tests := []struct {
+		name    string
+		healthy int
+		total   int
+		want    string
+	}{
+		{name: "all healthy", healthy: 3, total: 3, want: ingressCheckPass},
+		{name: "some unhealthy", healthy: 1, total: 3, want: ingressCheckWarn},
+		{name: "none healthy", healthy: 0, total: 3, want: ingressCheckFail},
+		{name: "no targets", want: ingressCheckFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateTargetHealth("Load balancer health", "lb", tt.healthy, tt.total); got.Status != tt.want {
+				t.Errorf("evaluateTargetHealth() = %+v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateIngressDNS(t *testing.T) {
+	tests := []struct {
+		name      string
+		recordIPs []string
+		lbIPs     []string
+		want      string
+	}{
+		{name: "shares an address", recordIPs: []string{"10.0.0.1", "10.0.0.2"}, lbIPs: []string{"10.0.0.2", "10.0.0.3"}, want: ingressCheckPass},
+		{name: "points elsewhere", recordIPs: []string{"10.0.0.1"}, lbIPs: []string{"10.0.0.3"}, want: ingressCheckFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateIngressDNS("apps.example.com", tt.recordIPs, tt.lbIPs); got.Status != tt.want {
+				t.Errorf("evaluateIngressDNS() = %+v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateIngressCertificate(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		dnsNames []string
+		notAfter time.Time
+		want     string
+	}{
+		{name: "valid wildcard", dnsNames: []string{"*.apps.example.com"}, notAfter: now.AddDate(0, 3, 0), want: ingressCheckPass},
+		{name: "expires soon", dnsNames: []string{"*.apps.example.com"}, notAfter: now.AddDate(0, 0, 7), want: ingressCheckWarn},
+		{name: "expired", dnsNames: []string{"*.apps.example.com"}, notAfter: now.AddDate(0, 0, -1), want: ingressCheckFail},
+		{name: "other domain", dnsNames: []string{"*.apps.other.com"}, notAfter: now.AddDate(0, 3, 0), want: ingressCheckWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificate := newTestCertificate(t, tt.dnsNames, now.AddDate(-1, 0, 0), tt.notAfter)
+			if got := evaluateIngressCertificate(certificate, "apps.example.com", now); got.Status != tt.want {
+				t.Errorf("evaluateIngressCertificate() = %+v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestCertificate(t *testing.T, dnsNames []string, notBefore time.Time, notAfter time.Time) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate
+}
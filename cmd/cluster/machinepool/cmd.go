@@ -0,0 +1,23 @@
+package machinepool
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdMachinePool() *cobra.Command {
+	machinePoolCmd := &cobra.Command{
+		Use:     "machinepool",
+		Aliases: []string{"machinepools", "mp"},
+		Short:   "List and manage the machine pools of a classic OSD/ROSA cluster through OCM",
+		Args:    cobra.NoArgs,
+	}
+
+	machinePoolCmd.AddCommand(
+		newCmdList(),
+		newCmdScale(),
+		newCmdCreate(),
+		newCmdDelete(),
+	)
+
+	return machinePoolCmd
+}
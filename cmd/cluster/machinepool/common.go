@@ -0,0 +1,133 @@
+package machinepool
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+// multiAZNodeMultiple is the number of availability zones of a multi-AZ cluster. Machine pools of
+// those clusters spread nodes evenly over all zones, so their node counts must be a multiple of it.
+const multiAZNodeMultiple = 3
+
+// scaling describes the size of a machine pool, either a fixed replica count or autoscaling bounds
+type scaling struct {
+	replicas    int
+	minReplicas int
+	maxReplicas int
+	autoscaling bool
+}
+
+// validate checks the scaling is sane for the given cluster before sending it to OCM,
+// so obviously wrong values don't end up in a half-applied change
+func (s scaling) validate(multiAZ bool) error {
+	counts := map[string]int{"replicas": s.replicas}
+	if s.autoscaling {
+		counts = map[string]int{"min-replicas": s.minReplicas, "max-replicas": s.maxReplicas}
+		if s.maxReplicas < 1 {
+			return fmt.Errorf("max-replicas must be at least 1")
+		}
+		if s.minReplicas > s.maxReplicas {
+			return fmt.Errorf("min-replicas (%d) can't be greater than max-replicas (%d)", s.minReplicas, s.maxReplicas)
+		}
+	}
+
+	for name, count := range counts {
+		if count < 0 {
+			return fmt.Errorf("%s can't be negative", name)
+		}
+		if multiAZ && count%multiAZNodeMultiple != 0 {
+			return fmt.Errorf("%s must be a multiple of %d on multi-AZ clusters, got %d", name, multiAZNodeMultiple, count)
+		}
+	}
+	return nil
+}
+
+// scalingFromFlags builds a scaling out of the --replicas, --min-replicas and --max-replicas flags
+func scalingFromFlags(replicas int, replicasSet bool, minReplicas int, maxReplicas int, autoscalingSet bool) (scaling, error) {
+	switch {
+	case replicasSet && autoscalingSet:
+		return scaling{}, fmt.Errorf("--replicas can't be used together with --min-replicas/--max-replicas")
+	case !replicasSet && !autoscalingSet:
+		return scaling{}, fmt.Errorf("either --replicas or --min-replicas and --max-replicas are required")
+	case autoscalingSet:
+		return scaling{minReplicas: minReplicas, maxReplicas: maxReplicas, autoscaling: true}, nil
+	default:
+		return scaling{replicas: replicas}, nil
+	}
+}
+
+func (s scaling) String() string {
+	if s.autoscaling {
+		return fmt.Sprintf("autoscaling %d-%d", s.minReplicas, s.maxReplicas)
+	}
+	return fmt.Sprintf("%d replicas", s.replicas)
+}
+
+// apply sets the scaling on a machine pool builder
+func (s scaling) apply(builder *cmv1.MachinePoolBuilder) *cmv1.MachinePoolBuilder {
+	if s.autoscaling {
+		return builder.Autoscaling(cmv1.NewMachinePoolAutoscaling().MinReplicas(s.minReplicas).MaxReplicas(s.maxReplicas))
+	}
+	return builder.Replicas(s.replicas)
+}
+
+// getClassicCluster returns the cluster, refusing HCP clusters which use node pools instead of machine pools
+func getClassicCluster(ocmClient *sdk.Connection, clusterID string) (*cmv1.Cluster, error) {
+	cluster, err := utils.GetCluster(ocmClient, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Hypershift().Enabled() {
+		return nil, fmt.Errorf("cluster %s is a hosted control plane cluster, which uses node pools instead of machine pools", cluster.ID())
+	}
+	return cluster, nil
+}
+
+// validateInstanceType checks the instance type is one OCM supports for the cluster's cloud provider
+func validateInstanceType(ocmClient *sdk.Connection, cluster *cmv1.Cluster, instanceType string) error {
+	provider := cluster.CloudProvider().ID()
+	pageSize := 100
+	request := ocmClient.ClustersMgmt().V1().MachineTypes().List().
+		Search(fmt.Sprintf("cloud_provider.id = '%s'", provider)).
+		Size(pageSize)
+
+	for page := 1; ; page++ {
+		response, err := request.Page(page).Send()
+		if err != nil {
+			return fmt.Errorf("failed to list the supported %s instance types: %w", provider, err)
+		}
+		for _, machineType := range response.Items().Slice() {
+			if machineType.ID() == instanceType {
+				return nil
+			}
+		}
+		if response.Size() < pageSize {
+			break
+		}
+	}
+	return fmt.Errorf("instance type '%s' is not supported on %s, see 'ocm list machine-types' for the available ones", instanceType, provider)
+}
+
+// recordAuditTrail leaves an internal service log on the cluster describing the change. A failure
+// is only reported, the change itself already happened.
+func recordAuditTrail(clusterID string, action string, reason string) {
+	message := fmt.Sprintf("%s via osdctl. Reason: %s", action, reason)
+	if err := servicelog.PostInternalServiceLog(clusterID, message); err != nil {
+		fmt.Printf("Failed to record the change in an internal service log, please post one manually: %v\n", err)
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,90 @@
+package machinepool
+
+import (
+	"testing"
+)
+
+func TestScalingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		scaling scaling
+		multiAZ bool
+		wantErr bool
+	}{
+		{
+			name:    "fixed replicas",
+			scaling: scaling{replicas: 2},
+		},
+		{
+			name:    "negative replicas",
+			scaling: scaling{replicas: -1},
+			wantErr: true,
+		},
+		{
+			name:    "multi-AZ replicas must be a multiple of 3",
+			scaling: scaling{replicas: 4},
+			multiAZ: true,
+			wantErr: true,
+		},
+		{
+			name:    "autoscaling",
+			scaling: scaling{minReplicas: 3, maxReplicas: 9, autoscaling: true},
+			multiAZ: true,
+		},
+		{
+			name:    "autoscaling min greater than max",
+			scaling: scaling{minReplicas: 5, maxReplicas: 2, autoscaling: true},
+			wantErr: true,
+		},
+		{
+			name:    "autoscaling max of 0",
+			scaling: scaling{minReplicas: 0, maxReplicas: 0, autoscaling: true},
+			wantErr: true,
+		},
+		{
+			name:    "multi-AZ autoscaling bounds must be multiples of 3",
+			scaling: scaling{minReplicas: 3, maxReplicas: 10, autoscaling: true},
+			multiAZ: true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.scaling.validate(tt.multiAZ); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScalingFromFlags(t *testing.T) {
+	if _, err := scalingFromFlags(3, true, 1, 5, true); err == nil {
+		t.Errorf("scalingFromFlags() expected an error when both replicas and autoscaling are set")
+	}
+	if _, err := scalingFromFlags(0, false, 0, 0, false); err == nil {
+		t.Errorf("scalingFromFlags() expected an error when neither replicas nor autoscaling are set")
+	}
+	if s, err := scalingFromFlags(0, false, 1, 5, true); err != nil || !s.autoscaling || s.minReplicas != 1 || s.maxReplicas != 5 {
+		t.Errorf("scalingFromFlags() = %+v, %v, want autoscaling 1-5", s, err)
+	}
+}
+
+func TestParseTaint(t *testing.T) {
+	tests := []struct {
+		taint   string
+		wantErr bool
+	}{
+		{taint: "node-role.kubernetes.io/infra=:NoSchedule"},
+		{taint: "dedicated=gpu:NoExecute"},
+		{taint: "dedicated=gpu", wantErr: true},
+		{taint: "dedicated=gpu:Sometimes", wantErr: true},
+		{taint: "=gpu:NoSchedule", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.taint, func(t *testing.T) {
+			if _, err := parseTaint(tt.taint); (err != nil) != tt.wantErr {
+				t.Errorf("parseTaint(%s) error = %v, wantErr %v", tt.taint, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package machinepool
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type createOptions struct {
+	clusterID     string
+	machinePoolID string
+	instanceType  string
+	replicas      int
+	minReplicas   int
+	maxReplicas   int
+	labels        map[string]string
+	taints        []string
+	reason        string
+	skipPrompts   bool
+
+	scaling scaling
+}
+
+func newCmdCreate() *cobra.Command {
+	ops := &createOptions{}
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a machine pool",
+		Example: `  osdctl cluster machinepool create -C ${CLUSTER_ID} --machinepool infra-tmp --instance-type r5.xlarge \
+    --replicas 3 --labels node-role.kubernetes.io/infra= --taints node-role.kubernetes.io/infra=:NoSchedule --reason "${OHSS}"`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete(cmd))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	createCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	createCmd.Flags().StringVar(&ops.machinePoolID, "machinepool", "", "ID of the machine pool to create")
+	createCmd.Flags().StringVar(&ops.instanceType, "instance-type", "", "Instance type of the nodes, e.g. m5.xlarge")
+	createCmd.Flags().IntVar(&ops.replicas, "replicas", 0, "Fixed number of nodes")
+	createCmd.Flags().IntVar(&ops.minReplicas, "min-replicas", 0, "Minimum number of nodes when autoscaling")
+	createCmd.Flags().IntVar(&ops.maxReplicas, "max-replicas", 0, "Maximum number of nodes when autoscaling")
+	createCmd.Flags().StringToStringVar(&ops.labels, "labels", map[string]string{}, "Labels of the nodes, e.g. key1=value1,key2=value2")
+	createCmd.Flags().StringSliceVar(&ops.taints, "taints", []string{}, "Taints of the nodes in the key=value:Effect format")
+	createCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this change (usually an OHSS or PD ticket), recorded in an internal service log")
+	createCmd.Flags().BoolVarP(&ops.skipPrompts, "yes", "y", false, "Skip the confirmation prompt")
+	_ = createCmd.MarkFlagRequired("cluster-id")
+	_ = createCmd.MarkFlagRequired("machinepool")
+	_ = createCmd.MarkFlagRequired("instance-type")
+	_ = createCmd.MarkFlagRequired("reason")
+
+	return createCmd
+}
+
+func (o *createOptions) complete(cmd *cobra.Command) error {
+	var err error
+	o.scaling, err = scalingFromFlags(o.replicas, cmd.Flags().Changed("replicas"), o.minReplicas, o.maxReplicas,
+		cmd.Flags().Changed("min-replicas") || cmd.Flags().Changed("max-replicas"))
+	return err
+}
+
+// parseTaint parses a key=value:Effect taint, the value being optional
+func parseTaint(taint string) (*cmv1.TaintBuilder, error) {
+	keyValue, effect, found := strings.Cut(taint, ":")
+	if !found || effect == "" {
+		return nil, fmt.Errorf("invalid taint '%s', expected key=value:Effect", taint)
+	}
+	switch effect {
+	case "NoSchedule", "PreferNoSchedule", "NoExecute":
+	default:
+		return nil, fmt.Errorf("invalid taint effect '%s', expected one of NoSchedule, PreferNoSchedule or NoExecute", effect)
+	}
+	key, value, _ := strings.Cut(keyValue, "=")
+	if key == "" {
+		return nil, fmt.Errorf("invalid taint '%s', the key can't be empty", taint)
+	}
+	return cmv1.NewTaint().Key(key).Value(value).Effect(effect), nil
+}
+
+func (o *createOptions) run() error {
+	var taints []*cmv1.TaintBuilder
+	for _, taint := range o.taints {
+		builder, err := parseTaint(taint)
+		if err != nil {
+			return err
+		}
+		taints = append(taints, builder)
+	}
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := getClassicCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if err := o.scaling.validate(cluster.MultiAZ()); err != nil {
+		return err
+	}
+	if err := validateInstanceType(ocmClient, cluster, o.instanceType); err != nil {
+		return err
+	}
+
+	machinePool, err := o.scaling.apply(cmv1.NewMachinePool().
+		ID(o.machinePoolID).
+		InstanceType(o.instanceType).
+		Labels(o.labels).
+		Taints(taints...)).
+		Build()
+	if err != nil {
+		return fmt.Errorf("failed to build machine pool: %w", err)
+	}
+
+	fmt.Printf("Machine pool %s (%s, %s) will be created on cluster %s\n", o.machinePoolID, o.instanceType, o.scaling, cluster.ID())
+	if !o.skipPrompts && !utils.ConfirmPrompt() {
+		return nil
+	}
+
+	if _, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).MachinePools().Add().Body(machinePool).Send(); err != nil {
+		return fmt.Errorf("failed to create machine pool %s: %w", o.machinePoolID, err)
+	}
+	fmt.Printf("Machine pool %s created\n", o.machinePoolID)
+
+	recordAuditTrail(cluster.ID(), fmt.Sprintf("Machine pool %s created with instance type %s and %s", o.machinePoolID, o.instanceType, o.scaling), o.reason)
+	return nil
+}
@@ -0,0 +1,73 @@
+package machinepool
+
+import (
+	"fmt"
+
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type deleteOptions struct {
+	clusterID     string
+	machinePoolID string
+	reason        string
+	skipPrompts   bool
+}
+
+func newCmdDelete() *cobra.Command {
+	ops := &deleteOptions{}
+	deleteCmd := &cobra.Command{
+		Use:               "delete",
+		Short:             "Delete a machine pool",
+		Example:           `  osdctl cluster machinepool delete -C ${CLUSTER_ID} --machinepool infra-tmp --reason "${OHSS}"`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	deleteCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	deleteCmd.Flags().StringVar(&ops.machinePoolID, "machinepool", "", "ID of the machine pool to delete")
+	deleteCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this change (usually an OHSS or PD ticket), recorded in an internal service log")
+	deleteCmd.Flags().BoolVarP(&ops.skipPrompts, "yes", "y", false, "Skip the confirmation prompt")
+	_ = deleteCmd.MarkFlagRequired("cluster-id")
+	_ = deleteCmd.MarkFlagRequired("machinepool")
+	_ = deleteCmd.MarkFlagRequired("reason")
+
+	return deleteCmd
+}
+
+func (o *deleteOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := getClassicCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	mpClient := ocmClient.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).MachinePools().MachinePool(o.machinePoolID)
+	current, err := mpClient.Get().Send()
+	if err != nil {
+		return fmt.Errorf("failed to get machine pool %s: %w", o.machinePoolID, err)
+	}
+
+	fmt.Printf("Machine pool %s (%s, %s) will be deleted from cluster %s, its nodes will be drained and removed\n",
+		o.machinePoolID, current.Body().InstanceType(), formatReplicas(current.Body()), cluster.ID())
+	if !o.skipPrompts && !utils.ConfirmPrompt() {
+		return nil
+	}
+
+	if _, err := mpClient.Delete().Send(); err != nil {
+		return fmt.Errorf("failed to delete machine pool %s: %w", o.machinePoolID, err)
+	}
+	fmt.Printf("Machine pool %s deleted\n", o.machinePoolID)
+
+	recordAuditTrail(cluster.ID(), fmt.Sprintf("Machine pool %s (%s, %s) deleted", o.machinePoolID, current.Body().InstanceType(), formatReplicas(current.Body())), o.reason)
+	return nil
+}
@@ -0,0 +1,83 @@
+package machinepool
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type listOptions struct {
+	clusterID string
+}
+
+func newCmdList() *cobra.Command {
+	ops := &listOptions{}
+	listCmd := &cobra.Command{
+		Use:               "list",
+		Short:             "List the machine pools of a cluster",
+		Example:           `  osdctl cluster machinepool list -C ${CLUSTER_ID}`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	listCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	_ = listCmd.MarkFlagRequired("cluster-id")
+
+	return listCmd
+}
+
+func (o *listOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := getClassicCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	response, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).MachinePools().List().Send()
+	if err != nil {
+		return fmt.Errorf("failed to list machine pools of cluster %s: %w", cluster.ID(), err)
+	}
+
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"ID", "INSTANCE TYPE", "REPLICAS", "AVAILABILITY ZONES", "LABELS", "TAINTS"})
+	for _, mp := range response.Items().Slice() {
+		table.AddRow([]string{
+			mp.ID(),
+			mp.InstanceType(),
+			formatReplicas(mp),
+			strings.Join(mp.AvailabilityZones(), ","),
+			formatLabels(mp.Labels()),
+			formatTaints(mp.Taints()),
+		})
+	}
+	return table.Flush()
+}
+
+func formatReplicas(mp *cmv1.MachinePool) string {
+	if autoscaling, ok := mp.GetAutoscaling(); ok {
+		return fmt.Sprintf("%d-%d (autoscaling)", autoscaling.MinReplicas(), autoscaling.MaxReplicas())
+	}
+	return fmt.Sprintf("%d", mp.Replicas())
+}
+
+func formatTaints(taints []*cmv1.Taint) string {
+	formatted := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		formatted = append(formatted, fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect()))
+	}
+	return strings.Join(formatted, ",")
+}
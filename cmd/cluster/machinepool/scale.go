@@ -0,0 +1,100 @@
+package machinepool
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type scaleOptions struct {
+	clusterID     string
+	machinePoolID string
+	replicas      int
+	minReplicas   int
+	maxReplicas   int
+	reason        string
+	skipPrompts   bool
+
+	scaling scaling
+}
+
+func newCmdScale() *cobra.Command {
+	ops := &scaleOptions{}
+	scaleCmd := &cobra.Command{
+		Use:   "scale",
+		Short: "Change the replica count or autoscaling bounds of a machine pool",
+		Example: `  # Scale the worker machine pool to 6 nodes
+  osdctl cluster machinepool scale -C ${CLUSTER_ID} --machinepool worker --replicas 6 --reason "${OHSS}"
+
+  # Enable autoscaling between 3 and 9 nodes
+  osdctl cluster machinepool scale -C ${CLUSTER_ID} --machinepool worker --min-replicas 3 --max-replicas 9 --reason "${OHSS}"`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete(cmd))
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	scaleCmd.Flags().StringVarP(&ops.clusterID, "cluster-id", "C", "", "OCM internal/external cluster id or cluster name")
+	scaleCmd.Flags().StringVar(&ops.machinePoolID, "machinepool", "", "ID of the machine pool to scale")
+	scaleCmd.Flags().IntVar(&ops.replicas, "replicas", 0, "Fixed number of nodes, disables autoscaling")
+	scaleCmd.Flags().IntVar(&ops.minReplicas, "min-replicas", 0, "Minimum number of nodes when autoscaling")
+	scaleCmd.Flags().IntVar(&ops.maxReplicas, "max-replicas", 0, "Maximum number of nodes when autoscaling")
+	scaleCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this change (usually an OHSS or PD ticket), recorded in an internal service log")
+	scaleCmd.Flags().BoolVarP(&ops.skipPrompts, "yes", "y", false, "Skip the confirmation prompt")
+	_ = scaleCmd.MarkFlagRequired("cluster-id")
+	_ = scaleCmd.MarkFlagRequired("machinepool")
+	_ = scaleCmd.MarkFlagRequired("reason")
+
+	return scaleCmd
+}
+
+func (o *scaleOptions) complete(cmd *cobra.Command) error {
+	var err error
+	o.scaling, err = scalingFromFlags(o.replicas, cmd.Flags().Changed("replicas"), o.minReplicas, o.maxReplicas,
+		cmd.Flags().Changed("min-replicas") || cmd.Flags().Changed("max-replicas"))
+	return err
+}
+
+func (o *scaleOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := getClassicCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if err := o.scaling.validate(cluster.MultiAZ()); err != nil {
+		return err
+	}
+
+	mpClient := ocmClient.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).MachinePools().MachinePool(o.machinePoolID)
+	current, err := mpClient.Get().Send()
+	if err != nil {
+		return fmt.Errorf("failed to get machine pool %s: %w", o.machinePoolID, err)
+	}
+
+	fmt.Printf("Machine pool %s of cluster %s will be scaled from %s to %s\n", o.machinePoolID, cluster.ID(), formatReplicas(current.Body()), o.scaling)
+	if !o.skipPrompts && !utils.ConfirmPrompt() {
+		return nil
+	}
+
+	update, err := o.scaling.apply(cmv1.NewMachinePool().ID(o.machinePoolID)).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build machine pool update: %w", err)
+	}
+	if _, err := mpClient.Update().Body(update).Send(); err != nil {
+		return fmt.Errorf("failed to scale machine pool %s: %w", o.machinePoolID, err)
+	}
+	fmt.Printf("Machine pool %s scaled to %s\n", o.machinePoolID, o.scaling)
+
+	recordAuditTrail(cluster.ID(), fmt.Sprintf("Machine pool %s scaled from %s to %s", o.machinePoolID, formatReplicas(current.Body()), o.scaling), o.reason)
+	return nil
+}
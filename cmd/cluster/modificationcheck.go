@@ -0,0 +1,477 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+	ctAws "github.com/openshift/osdctl/cmd/cloudtrail/pkg/aws"
+	envConfig "github.com/openshift/osdctl/pkg/envConfig"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// KnownPrincipalsConfigKey lists regular expressions of the principals allowed to change the managed
+	// resources, on top of the built-in ones and the cloudtrail_cmd_lists filters
+	KnownPrincipalsConfigKey = "modification_check_known_principals"
+
+	managedResourceSecurityGroup = "security group"
+	managedResourceIAMRole       = "IAM role"
+	managedResourceLoadBalancer  = "load balancer"
+
+	ownedTagValue = "owned"
+	// cloudTrailLookupsPerSecond is the rate limit of the LookupEvents API, per account and region
+	cloudTrailLookupsPerSecond = 2
+	// iamEventsRegion is where the CloudTrail events of IAM, a global service, are logged
+	iamEventsRegion = "us-east-1"
+	// elbTagsBatchSize is the maximum number of load balancers DescribeTags takes at once
+	elbTagsBatchSize = 20
+)
+
+// knownPrincipals are the principals of the installer, the operators, SRE and AWS itself, which change the
+// managed resources as part of running the cluster
+var knownPrincipals = []string{
+	`ManagedOpenShift-`,
+	`RH-Managed-OpenShift-`,
+	`RH-SRE-`,
+	`OrganizationAccountAccessRole`,
+	`osdManagedAdmin`,
+	`osdCcsAdmin`,
+	`-openshift-`,
+	`AWSServiceRoleFor`,
+	`\.amazonaws\.com$`,
+}
+
+type modificationCheckOptions struct {
+	clusterID string
+	since     string
+}
+
+// managedResource is an AWS resource the installer or the operators created for the cluster
+type managedResource struct {
+	Type string
+	// ID is what CloudTrail references the resource by: the group ID, the role name or the load balancer name
+	ID   string
+	Name string
+	Tags map[string]string
+	// Region is where the CloudTrail events of the resource are logged, us-east-1 for IAM
+	Region string
+}
+
+// modificationFinding is an ownership tag changed, or changes made by a principal outside the known ones
+type modificationFinding struct {
+	Resource  managedResource
+	Problem   string
+	Principal string
+	Events    []string
+	LastSeen  time.Time
+}
+
+// modificationEC2Client, modificationIAMClient, modificationELBClient and modificationELBV2Client are the
+// parts of the AWS APIs the managed resources are listed with
+type modificationEC2Client interface {
+	DescribeSecurityGroups(context.Context, *ec2.DescribeSecurityGroupsInput, ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+}
+
+type modificationIAMClient interface {
+	ListRoles(context.Context, *iam.ListRolesInput, ...func(*iam.Options)) (*iam.ListRolesOutput, error)
+	ListRoleTags(context.Context, *iam.ListRoleTagsInput, ...func(*iam.Options)) (*iam.ListRoleTagsOutput, error)
+}
+
+type modificationELBClient interface {
+	DescribeLoadBalancers(context.Context, *elasticloadbalancing.DescribeLoadBalancersInput, ...func(*elasticloadbalancing.Options)) (*elasticloadbalancing.DescribeLoadBalancersOutput, error)
+	DescribeTags(context.Context, *elasticloadbalancing.DescribeTagsInput, ...func(*elasticloadbalancing.Options)) (*elasticloadbalancing.DescribeTagsOutput, error)
+}
+
+type modificationELBV2Client interface {
+	DescribeLoadBalancers(context.Context, *elasticloadbalancingv2.DescribeLoadBalancersInput, ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
+	DescribeTags(context.Context, *elasticloadbalancingv2.DescribeTagsInput, ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTagsOutput, error)
+}
+
+func newCmdModificationCheck() *cobra.Command {
+	ops := &modificationCheckOptions{}
+	modificationCheckCmd := &cobra.Command{
+		Use:   "modification-check <cluster-id>",
+		Short: "Detect manual modifications of the AWS resources managed for a cluster",
+		Long: fmt.Sprintf(`Detect manual modifications of the AWS resources the installer and the operators manage for a cluster:
+the security groups, the IAM roles and the load balancers tagged with the infra ID of the cluster.
+
+Each resource is checked for its kubernetes.io/cluster/<infra-id>=owned ownership tag, and its CloudTrail history
+is checked for changes made by principals other than the installer, the operators, SRE and AWS. More principals can
+be allowed with regular expressions listed in '%s' of the osdctl config, the cloudtrail_cmd_lists filters are
+allowed too.
+
+When modifications are found, the limited support reason for them is printed, ready to be reviewed and posted.`, KnownPrincipalsConfigKey),
+		Example:           `  osdctl cluster modification-check <cluster-id> --since 30d`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	modificationCheckCmd.Flags().StringVar(&ops.since, "since", "7d", "How far back to look for changes in CloudTrail, at most 90d")
+
+	return modificationCheckCmd
+}
+
+func (o *modificationCheckOptions) run() error {
+	since, err := ctUtil.ParseDuration(o.since)
+	if err != nil {
+		return err
+	}
+
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.CloudProvider().ID() != "aws" {
+		return fmt.Errorf("cluster %s is not an AWS cluster", cluster.ID())
+	}
+	infraID := cluster.InfraID()
+
+	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	if err != nil {
+		return err
+	}
+
+	resources, err := listManagedSecurityGroups(ec2.NewFromConfig(cfg), infraID, cfg.Region)
+	if err != nil {
+		return err
+	}
+	roles, err := listManagedRoles(iam.NewFromConfig(cfg), infraID)
+	if err != nil {
+		return err
+	}
+	resources = append(resources, roles...)
+	loadBalancers, err := listManagedLoadBalancers(elasticloadbalancing.NewFromConfig(cfg), elasticloadbalancingv2.NewFromConfig(cfg), infraID, cfg.Region)
+	if err != nil {
+		return err
+	}
+	resources = append(resources, loadBalancers...)
+	if len(resources) == 0 {
+		return fmt.Errorf("no AWS resource of cluster %s was found, its infra ID is %s", cluster.ID(), infraID)
+	}
+
+	isKnown, err := knownPrincipalMatcher(infraID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Checking %d AWS resources of cluster %s (%s) for changes in the last %s\n\n", len(resources), cluster.ID(), infraID, o.since)
+	var findings []modificationFinding
+	limiter := utils.NewRateLimiter(cloudTrailLookupsPerSecond)
+	cloudTrailClients := map[string]*cloudtrail.Client{}
+	startTime := time.Now().Add(-since)
+	for _, resource := range resources {
+		findings = append(findings, checkOwnershipTag(resource, infraID)...)
+
+		client, ok := cloudTrailClients[resource.Region]
+		if !ok {
+			region := resource.Region
+			client = cloudtrail.NewFromConfig(cfg, func(options *cloudtrail.Options) {
+				options.Region = region
+			})
+			cloudTrailClients[resource.Region] = client
+		}
+		limiter.Wait()
+		events, err := ctAws.GetResourceEvents(client, startTime, resource.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get the CloudTrail events of %s %s: %v\n", resource.Type, resource.ID, err)
+			continue
+		}
+		findings = append(findings, unknownModifications(resource, events, isKnown)...)
+	}
+
+	if len(findings) == 0 {
+		fmt.Printf("No modification found on the %d resources\n", len(resources))
+		return nil
+	}
+	if err := printModificationFindings(findings); err != nil {
+		return err
+	}
+	printLimitedSupportSuggestion(cluster.ID(), findings)
+	return fmt.Errorf("%d modifications found", len(findings))
+}
+
+// listManagedSecurityGroups lists the security groups tagged with the infra ID, or named after it
+func listManagedSecurityGroups(client modificationEC2Client, infraID string, region string) ([]managedResource, error) {
+	groups := map[string]managedResource{}
+	filters := [][]ec2types.Filter{
+		{{Name: aws.String("tag-key"), Values: []string{clusterOwnershipTag(infraID)}}},
+		{{Name: aws.String("group-name"), Values: []string{infraID + "-*"}}},
+	}
+	for _, filter := range filters {
+		paginator := ec2.NewDescribeSecurityGroupsPaginator(client, &ec2.DescribeSecurityGroupsInput{Filters: filter})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err != nil {
+				return nil, fmt.Errorf("failed to list the security groups: %w", err)
+			}
+			for _, group := range page.SecurityGroups {
+				tags := map[string]string{}
+				for _, tag := range group.Tags {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+				groups[aws.ToString(group.GroupId)] = managedResource{
+					Type:   managedResourceSecurityGroup,
+					ID:     aws.ToString(group.GroupId),
+					Name:   aws.ToString(group.GroupName),
+					Tags:   tags,
+					Region: region,
+				}
+			}
+		}
+	}
+
+	resources := make([]managedResource, 0, len(groups))
+	for _, group := range groups {
+		resources = append(resources, group)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].ID < resources[j].ID })
+	return resources, nil
+}
+
+// listManagedRoles lists the IAM roles named after the infra ID, ListRoles doesn't return the tags
+func listManagedRoles(client modificationIAMClient, infraID string) ([]managedResource, error) {
+	var resources []managedResource
+	paginator := iam.NewListRolesPaginator(client, &iam.ListRolesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the IAM roles: %w", err)
+		}
+		for _, role := range page.Roles {
+			name := aws.ToString(role.RoleName)
+			if !strings.HasPrefix(name, infraID+"-") {
+				continue
+			}
+			tags, err := client.ListRoleTags(context.TODO(), &iam.ListRoleTagsInput{RoleName: role.RoleName})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list the tags of IAM role %s: %w", name, err)
+			}
+			resource := managedResource{Type: managedResourceIAMRole, ID: name, Name: name, Tags: map[string]string{}, Region: iamEventsRegion}
+			for _, tag := range tags.Tags {
+				resource.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			resources = append(resources, resource)
+		}
+	}
+	return resources, nil
+}
+
+// listManagedLoadBalancers lists the classic and the network/application load balancers tagged with the
+// infra ID, their names are generated so the tags are the only way to tell they belong to the cluster
+func listManagedLoadBalancers(elbClient modificationELBClient, elbv2Client modificationELBV2Client, infraID string, region string) ([]managedResource, error) {
+	var resources []managedResource
+
+	var classicNames []string
+	classicPaginator := elasticloadbalancing.NewDescribeLoadBalancersPaginator(elbClient, &elasticloadbalancing.DescribeLoadBalancersInput{})
+	for classicPaginator.HasMorePages() {
+		page, err := classicPaginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the classic load balancers: %w", err)
+		}
+		for _, loadBalancer := range page.LoadBalancerDescriptions {
+			classicNames = append(classicNames, aws.ToString(loadBalancer.LoadBalancerName))
+		}
+	}
+	for start := 0; start < len(classicNames); start += elbTagsBatchSize {
+		end := min(start+elbTagsBatchSize, len(classicNames))
+		output, err := elbClient.DescribeTags(context.TODO(), &elasticloadbalancing.DescribeTagsInput{LoadBalancerNames: classicNames[start:end]})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the tags of the classic load balancers: %w", err)
+		}
+		for _, description := range output.TagDescriptions {
+			tags := map[string]string{}
+			for _, tag := range description.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			if _, ok := tags[clusterOwnershipTag(infraID)]; ok {
+				name := aws.ToString(description.LoadBalancerName)
+				resources = append(resources, managedResource{Type: managedResourceLoadBalancer, ID: name, Name: name, Tags: tags, Region: region})
+			}
+		}
+	}
+
+	names := map[string]string{}
+	var arns []string
+	paginator := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(elbv2Client, &elasticloadbalancingv2.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the load balancers: %w", err)
+		}
+		for _, loadBalancer := range page.LoadBalancers {
+			arn := aws.ToString(loadBalancer.LoadBalancerArn)
+			arns = append(arns, arn)
+			names[arn] = aws.ToString(loadBalancer.LoadBalancerName)
+		}
+	}
+	for start := 0; start < len(arns); start += elbTagsBatchSize {
+		end := min(start+elbTagsBatchSize, len(arns))
+		output, err := elbv2Client.DescribeTags(context.TODO(), &elasticloadbalancingv2.DescribeTagsInput{ResourceArns: arns[start:end]})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the tags of the load balancers: %w", err)
+		}
+		for _, description := range output.TagDescriptions {
+			tags := map[string]string{}
+			for _, tag := range description.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			if _, ok := tags[clusterOwnershipTag(infraID)]; ok {
+				arn := aws.ToString(description.ResourceArn)
+				resources = append(resources, managedResource{Type: managedResourceLoadBalancer, ID: arn, Name: names[arn], Tags: tags, Region: region})
+			}
+		}
+	}
+	return resources, nil
+}
+
+func clusterOwnershipTag(infraID string) string {
+	return "kubernetes.io/cluster/" + infraID
+}
+
+// checkOwnershipTag checks the resource still has the ownership tag the installer or the operators set
+func checkOwnershipTag(resource managedResource, infraID string) []modificationFinding {
+	value, ok := resource.Tags[clusterOwnershipTag(infraID)]
+	switch {
+	case !ok:
+		return []modificationFinding{{Resource: resource, Problem: fmt.Sprintf("the %s tag was removed", clusterOwnershipTag(infraID))}}
+	case value != ownedTagValue:
+		return []modificationFinding{{Resource: resource, Problem: fmt.Sprintf("the %s tag is '%s' instead of '%s'", clusterOwnershipTag(infraID), value, ownedTagValue)}}
+	}
+	return nil
+}
+
+// knownPrincipalMatcher returns whether an event was made by a principal allowed to change the managed
+// resources: the built-in ones, the principals of the cluster itself, and the configured ones
+func knownPrincipalMatcher(infraID string) (func(cttypes.Event) bool, error) {
+	patterns := append([]string{regexp.QuoteMeta(infraID)}, knownPrincipals...)
+	patterns = append(patterns, viper.GetStringSlice(KnownPrincipalsConfigKey)...)
+	ignored, err := envConfig.LoadCloudTrailConfig()
+	if err != nil {
+		return nil, err
+	}
+	patterns = append(patterns, ignored...)
+	known, err := regexp.Compile(ctUtil.MergeRegex(patterns))
+	if err != nil {
+		return nil, fmt.Errorf("invalid known principal pattern: %w", err)
+	}
+	return newKnownPrincipalMatcher(known), nil
+}
+
+func newKnownPrincipalMatcher(known *regexp.Regexp) func(cttypes.Event) bool {
+	return func(event cttypes.Event) bool {
+		if raw, err := ctAws.ExtractUserDetails(event.CloudTrailEvent); err == nil && raw.UserIdentity.Type == "AWSService" {
+			return true
+		}
+		return known.MatchString(ctAws.EventPrincipal(event))
+	}
+}
+
+// unknownModifications returns a finding per principal outside the known ones which changed the resource
+func unknownModifications(resource managedResource, events []cttypes.Event, isKnown func(cttypes.Event) bool) []modificationFinding {
+	byPrincipal := map[string]*modificationFinding{}
+	var principals []string
+	for _, event := range events {
+		raw, err := ctAws.ExtractUserDetails(event.CloudTrailEvent)
+		if err != nil || raw.ReadOnly || raw.ErrorCode != "" || isKnown(event) {
+			continue
+		}
+		principal := ctAws.EventPrincipal(event)
+		finding, ok := byPrincipal[principal]
+		if !ok {
+			finding = &modificationFinding{Resource: resource, Problem: "changed outside the known principals", Principal: principal}
+			byPrincipal[principal] = finding
+			principals = append(principals, principal)
+		}
+		if name := aws.ToString(event.EventName); name != "" && !slices.Contains(finding.Events, name) {
+			finding.Events = append(finding.Events, name)
+		}
+		if eventTime := aws.ToTime(event.EventTime); eventTime.After(finding.LastSeen) {
+			finding.LastSeen = eventTime
+		}
+	}
+
+	sort.Strings(principals)
+	findings := make([]modificationFinding, 0, len(principals))
+	for _, principal := range principals {
+		sort.Strings(byPrincipal[principal].Events)
+		findings = append(findings, *byPrincipal[principal])
+	}
+	return findings
+}
+
+func printModificationFindings(findings []modificationFinding) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"TYPE", "RESOURCE", "NAME", "PROBLEM", "PRINCIPAL", "EVENTS", "LAST"})
+	for _, finding := range findings {
+		last := ""
+		if !finding.LastSeen.IsZero() {
+			last = finding.LastSeen.UTC().Format(time.RFC3339)
+		}
+		table.AddRow([]string{
+			finding.Resource.Type,
+			finding.Resource.ID,
+			finding.Resource.Name,
+			finding.Problem,
+			finding.Principal,
+			strings.Join(finding.Events, ","),
+			last,
+		})
+	}
+	return table.Flush()
+}
+
+// printLimitedSupportSuggestion prints the command posting the unsupported modification limited support
+// reason, with the findings as evidence. It's up to the engineer to confirm the changes are unsupported.
+func printLimitedSupportSuggestion(clusterID string, findings []modificationFinding) {
+	var resources, evidence []string
+	for _, finding := range findings {
+		resource := fmt.Sprintf("%s %s", finding.Resource.Type, finding.Resource.Name)
+		if !slices.Contains(resources, resource) {
+			resources = append(resources, resource)
+		}
+		line := fmt.Sprintf("%s %s: %s", finding.Resource.Type, finding.Resource.ID, finding.Problem)
+		if finding.Principal != "" {
+			line += fmt.Sprintf(" by %s (%s)", finding.Principal, strings.Join(finding.Events, ","))
+		}
+		evidence = append(evidence, line)
+	}
+
+	fmt.Printf(`
+If the modifications are confirmed to be unsupported, the cluster can be placed in limited support with:
+
+  osdctl cluster support post %s --misconfiguration cloud \
+    --problem "AWS resources managed by Red Hat for the cluster were modified, which is not supported." \
+    --resolution "Revert the changes made to the following resources: %s" \
+    --evidence %q
+`, clusterID, strings.Join(resources, ", "), strings.Join(evidence, "; "))
+}
@@ -0,0 +1,81 @@
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+)
+
+func TestCheckOwnershipTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		expected []string
+	}{
+		{
+			name: "owned",
+			tags: map[string]string{"kubernetes.io/cluster/abc-x1y2z": "owned"},
+		},
+		{
+			name:     "removed",
+			tags:     map[string]string{"Name": "abc-x1y2z-node"},
+			expected: []string{"the kubernetes.io/cluster/abc-x1y2z tag was removed"},
+		},
+		{
+			name:     "changed",
+			tags:     map[string]string{"kubernetes.io/cluster/abc-x1y2z": "shared"},
+			expected: []string{"the kubernetes.io/cluster/abc-x1y2z tag is 'shared' instead of 'owned'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var problems []string
+			for _, finding := range checkOwnershipTag(managedResource{Tags: tt.tags}, "abc-x1y2z") {
+				problems = append(problems, finding.Problem)
+			}
+			if !reflect.DeepEqual(problems, tt.expected) {
+				t.Errorf("checkOwnershipTag() = %v, want %v", problems, tt.expected)
+			}
+		})
+	}
+}
+
+func modificationEvent(name string, identityType string, issuer string, readOnly bool, eventTime time.Time) cttypes.Event {
+	raw := fmt.Sprintf(`{"eventVersion": "1.08", "readOnly": %t, "userIdentity": {"type": %q, "sessionContext": {"sessionIssuer": {"arn": %q}}}}`, readOnly, identityType, issuer)
+	return cttypes.Event{EventName: aws.String(name), EventTime: aws.Time(eventTime), CloudTrailEvent: aws.String(raw)}
+}
+
+func TestUnknownModifications(t *testing.T) {
+	known := regexp.MustCompile(ctUtil.MergeRegex(append([]string{regexp.QuoteMeta("abc-x1y2z")}, knownPrincipals...)))
+	isKnown := newKnownPrincipalMatcher(known)
+	resource := managedResource{Type: managedResourceSecurityGroup, ID: "sg-0123456789abcdef0"}
+	first := time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+
+	events := []cttypes.Event{
+		modificationEvent("AuthorizeSecurityGroupIngress", "AssumedRole", "arn:aws:iam::123456789012:role/customer-admin", false, first),
+		modificationEvent("RevokeSecurityGroupEgress", "AssumedRole", "arn:aws:iam::123456789012:role/customer-admin", false, last),
+		modificationEvent("AuthorizeSecurityGroupIngress", "AssumedRole", "arn:aws:iam::123456789012:role/customer-admin", false, first),
+		modificationEvent("DescribeSecurityGroups", "AssumedRole", "arn:aws:iam::123456789012:role/customer-admin", true, last),
+		modificationEvent("AuthorizeSecurityGroupIngress", "AssumedRole", "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role", false, last),
+		modificationEvent("AuthorizeSecurityGroupIngress", "AssumedRole", "arn:aws:iam::123456789012:role/abc-x1y2z-master-role", false, last),
+		modificationEvent("ModifyNetworkInterfaceAttribute", "AWSService", "", false, last),
+	}
+
+	expected := []modificationFinding{{
+		Resource:  resource,
+		Problem:   "changed outside the known principals",
+		Principal: "arn:aws:iam::123456789012:role/customer-admin",
+		Events:    []string{"AuthorizeSecurityGroupIngress", "RevokeSecurityGroupEgress"},
+		LastSeen:  last,
+	}}
+	if findings := unknownModifications(resource, events, isKnown); !reflect.DeepEqual(findings, expected) {
+		t.Errorf("unknownModifications() = %+v, want %+v", findings, expected)
+	}
+}
@@ -0,0 +1,375 @@
+package cluster
+
+import (
+	"context"
+	"crypto/sha1" //#nosec G505 -- AWS identifies the OIDC provider certificates by their SHA-1 thumbprint
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	oidcCheckPass = "PASS"
+	oidcCheckWarn = "WARN"
+	oidcCheckFail = "FAIL"
+
+	webIdentityAction = "sts:AssumeRoleWithWebIdentity"
+)
+
+// oidcAudiences are the audiences the OIDC provider must list for the cluster's service account tokens
+var oidcAudiences = []string{"openshift", "sts.amazonaws.com"}
+
+type oidcCheckOptions struct {
+	clusterID string
+}
+
+type oidcCheckResult struct {
+	Check   string
+	Status  string
+	Details string
+}
+
+// oidcIAMClient is the part of the IAM API the OIDC checks use
+type oidcIAMClient interface {
+	GetOpenIDConnectProvider(context.Context, *iam.GetOpenIDConnectProviderInput, ...func(*iam.Options)) (*iam.GetOpenIDConnectProviderOutput, error)
+	GetRole(context.Context, *iam.GetRoleInput, ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+}
+
+func newCmdOidcCheck() *cobra.Command {
+	ops := &oidcCheckOptions{}
+	oidcCheckCmd := &cobra.Command{
+		Use:   "oidc-check <cluster-id>",
+		Short: "Validate the OIDC provider and operator roles of an STS cluster",
+		Long: `Validate the workload identity configuration of an STS cluster (ROSA classic STS or ROSA HCP):
+  - the OIDC issuer serves its discovery document and signing keys
+  - the IAM OIDC provider exists in the customer's account, with the expected audiences and the
+    thumbprint of the issuer's certificate
+  - each operator role exists and trusts the OIDC provider for the operator's service account
+
+Mismatches between these are what make the cloud credentials of the operators fail, e.g. after the
+OIDC provider or the operator roles were recreated by the customer.`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	return oidcCheckCmd
+}
+
+func (o *oidcCheckOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	sts := cluster.AWS().STS()
+	if !sts.Enabled() {
+		return fmt.Errorf("cluster %s is not an STS cluster", cluster.ID())
+	}
+
+	issuerURL := sts.OidcConfig().IssuerUrl()
+	if issuerURL == "" {
+		issuerURL = sts.OIDCEndpointURL()
+	}
+	issuer, err := oidcIssuerID(issuerURL)
+	if err != nil {
+		return err
+	}
+	installerRole, err := arn.Parse(sts.RoleARN())
+	if err != nil {
+		return fmt.Errorf("failed to parse the installer role ARN '%s': %w", sts.RoleARN(), err)
+	}
+	providerARN := fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", installerRole.Partition, installerRole.AccountID, issuer)
+
+	fmt.Printf("Checking OIDC issuer %s of cluster %s\n\n", issuerURL, cluster.ID())
+	results := checkOidcDiscovery(&http.Client{Timeout: 10 * time.Second}, issuerURL)
+
+	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	if err != nil {
+		return err
+	}
+	iamClient := iam.NewFromConfig(cfg)
+
+	thumbprint, err := issuerThumbprint(issuerURL)
+	if err != nil {
+		results = append(results, oidcCheckResult{"Issuer certificate", oidcCheckWarn, err.Error()})
+	}
+	results = append(results, checkOidcProvider(iamClient, providerARN, thumbprint)...)
+
+	for _, role := range sts.OperatorIAMRoles() {
+		serviceAccount := fmt.Sprintf("system:serviceaccount:%s:%s", role.Namespace(), role.ServiceAccount())
+		results = append(results, checkOperatorRole(iamClient, role.RoleARN(), providerARN, issuer, serviceAccount))
+	}
+
+	failures := printOidcCheckResults(results)
+	if failures > 0 {
+		return fmt.Errorf("%d OIDC checks failed", failures)
+	}
+	return nil
+}
+
+func printOidcCheckResults(results []oidcCheckResult) int {
+	failures := 0
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "STATUS", "DETAILS"})
+	for _, result := range results {
+		if result.Status == oidcCheckFail {
+			failures++
+		}
+		table.AddRow([]string{result.Check, result.Status, result.Details})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the OIDC checks: %v\n", err)
+	}
+	return failures
+}
+
+// oidcIssuerID returns the issuer as IAM refers to it: the issuer URL without its scheme
+func oidcIssuerID(issuerURL string) (string, error) {
+	u, err := url.Parse(issuerURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid OIDC issuer URL '%s'", issuerURL)
+	}
+	return strings.TrimSuffix(u.Host+u.Path, "/"), nil
+}
+
+// checkOidcDiscovery checks the issuer serves a discovery document matching its URL and its signing keys
+func checkOidcDiscovery(client *http.Client, issuerURL string) []oidcCheckResult {
+	const discoveryCheck, keysCheck = "Discovery document", "Signing keys"
+
+	discovery := struct {
+		Issuer  string `json:"issuer"`
+		JwksURI string `json:"jwks_uri"`
+	}{}
+	discoveryURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(client, discoveryURL, &discovery); err != nil {
+		return []oidcCheckResult{{discoveryCheck, oidcCheckFail, err.Error()}}
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return []oidcCheckResult{{discoveryCheck, oidcCheckFail, fmt.Sprintf("the document's issuer %s doesn't match %s", discovery.Issuer, issuerURL)}}
+	}
+	results := []oidcCheckResult{{discoveryCheck, oidcCheckPass, discoveryURL}}
+
+	keys := struct {
+		Keys []json.RawMessage `json:"keys"`
+	}{}
+	if err := getJSON(client, discovery.JwksURI, &keys); err != nil {
+		return append(results, oidcCheckResult{keysCheck, oidcCheckFail, err.Error()})
+	}
+	if len(keys.Keys) == 0 {
+		return append(results, oidcCheckResult{keysCheck, oidcCheckFail, fmt.Sprintf("%s has no keys", discovery.JwksURI)})
+	}
+	return append(results, oidcCheckResult{keysCheck, oidcCheckPass, fmt.Sprintf("%d keys at %s", len(keys.Keys), discovery.JwksURI)})
+}
+
+func getJSON(client *http.Client, url string, v interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get %s: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return nil
+}
+
+// issuerThumbprint returns the SHA-1 thumbprint of the top intermediate CA certificate served by the issuer,
+// which is what AWS expects in the thumbprint list of the OIDC provider
+func issuerThumbprint(issuerURL string) (string, error) {
+	u, err := url.Parse(issuerURL)
+	if err != nil {
+		return "", err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", host, &tls.Config{MinVersion: tls.VersionTLS12})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the certificate of %s: %w", host, err)
+	}
+	defer conn.Close()
+
+	certificates := conn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return "", fmt.Errorf("%s served no certificate", host)
+	}
+	sum := sha1.Sum(certificates[len(certificates)-1].Raw) //#nosec G401 -- see the sha1 import
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func checkOidcProvider(client oidcIAMClient, providerARN string, thumbprint string) []oidcCheckResult {
+	const check = "OIDC provider"
+	provider, err := client.GetOpenIDConnectProvider(context.TODO(), &iam.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: aws.String(providerARN)})
+	if err != nil {
+		return []oidcCheckResult{{check, oidcCheckFail, fmt.Sprintf("failed to get %s: %v", providerARN, err)}}
+	}
+	return evaluateOidcProvider(providerARN, provider.ClientIDList, provider.ThumbprintList, thumbprint)
+}
+
+// evaluateOidcProvider checks the audiences and the thumbprints of the OIDC provider
+func evaluateOidcProvider(providerARN string, clientIDs []string, thumbprints []string, thumbprint string) []oidcCheckResult {
+	var missing []string
+	for _, audience := range oidcAudiences {
+		if !containsFold(clientIDs, audience) {
+			missing = append(missing, audience)
+		}
+	}
+	results := []oidcCheckResult{{"OIDC provider", oidcCheckPass, providerARN}}
+	if len(missing) > 0 {
+		results[0] = oidcCheckResult{"OIDC provider", oidcCheckFail, fmt.Sprintf("%s is missing the audiences %v", providerARN, missing)}
+	}
+
+	switch {
+	case thumbprint == "":
+		// The certificate couldn't be fetched, already reported
+	case containsFold(thumbprints, thumbprint):
+		results = append(results, oidcCheckResult{"OIDC provider thumbprint", oidcCheckPass, thumbprint})
+	default:
+		// AWS skips the thumbprint for the CAs it trusts, so this only breaks issuers with other CAs
+		results = append(results, oidcCheckResult{"OIDC provider thumbprint", oidcCheckWarn, fmt.Sprintf("the issuer's thumbprint %s is not in %v", thumbprint, thumbprints)})
+	}
+	return results
+}
+
+func checkOperatorRole(client oidcIAMClient, roleARN string, providerARN string, issuer string, serviceAccount string) oidcCheckResult {
+	check := fmt.Sprintf("Operator role %s", roleARN)
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return oidcCheckResult{check, oidcCheckFail, fmt.Sprintf("invalid role ARN: %v", err)}
+	}
+
+	role, err := client.GetRole(context.TODO(), &iam.GetRoleInput{RoleName: aws.String(path.Base(parsed.Resource))})
+	if err != nil {
+		return oidcCheckResult{check, oidcCheckFail, fmt.Sprintf("failed to get the role: %v", err)}
+	}
+
+	// The policy document is URL encoded
+	document, err := url.QueryUnescape(aws.ToString(role.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return oidcCheckResult{check, oidcCheckFail, fmt.Sprintf("failed to decode the trust policy: %v", err)}
+	}
+	status, details := evaluateTrustPolicy(document, providerARN, issuer, serviceAccount)
+	return oidcCheckResult{check, status, details}
+}
+
+// stringOrSlice is a policy element which can be a single string or a list of strings
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = []string{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+type trustPolicy struct {
+	Statement []struct {
+		Effect    string
+		Action    stringOrSlice
+		Principal struct {
+			Federated stringOrSlice
+		}
+		Condition map[string]map[string]stringOrSlice
+	}
+}
+
+// evaluateTrustPolicy checks the role trusts the OIDC provider for the operator's service account
+func evaluateTrustPolicy(document string, providerARN string, issuer string, serviceAccount string) (string, string) {
+	policy := trustPolicy{}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return oidcCheckFail, fmt.Sprintf("failed to parse the trust policy: %v", err)
+	}
+
+	var otherProviders []string
+	var allowedSubjects []string
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" || !containsFold(statement.Action, webIdentityAction) {
+			continue
+		}
+		if !containsFold(statement.Principal.Federated, providerARN) {
+			otherProviders = append(otherProviders, statement.Principal.Federated...)
+			continue
+		}
+
+		subjectKey := issuer + ":sub"
+		restricted := false
+		for operator, conditions := range statement.Condition {
+			for key, subjects := range conditions {
+				if !strings.EqualFold(key, subjectKey) {
+					continue
+				}
+				restricted = true
+				for _, subject := range subjects {
+					allowedSubjects = append(allowedSubjects, subject)
+					if subject == serviceAccount {
+						return oidcCheckPass, fmt.Sprintf("trusts %s", serviceAccount)
+					}
+					if strings.HasPrefix(operator, "StringLike") {
+						if matched, _ := path.Match(subject, serviceAccount); matched {
+							return oidcCheckPass, fmt.Sprintf("trusts %s", serviceAccount)
+						}
+					}
+				}
+			}
+		}
+		if !restricted {
+			return oidcCheckWarn, "trusts every service account of the cluster"
+		}
+	}
+
+	switch {
+	case len(allowedSubjects) > 0:
+		return oidcCheckFail, fmt.Sprintf("trusts %v instead of %s", allowedSubjects, serviceAccount)
+	case len(otherProviders) > 0:
+		return oidcCheckFail, fmt.Sprintf("trusts the OIDC providers %v instead of %s", otherProviders, providerARN)
+	default:
+		return oidcCheckFail, fmt.Sprintf("no statement allows %s from %s", webIdentityAction, providerARN)
+	}
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOidcIssuerID(t *testing.T) {
+	issuer, err := oidcIssuerID("https://oidc.os1.devshift.org/abc123/")
+	if err != nil || issuer != "oidc.os1.devshift.org/abc123" {
+		t.Errorf("oidcIssuerID() = %v, %v", issuer, err)
+	}
+	if _, err := oidcIssuerID("not a url"); err == nil {
+		t.Error("expected an error for an invalid URL")
+	}
+}
+
+func TestEvaluateTrustPolicy(t *testing.T) {
+	const (
+		providerARN    = "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"
+		issuer         = "oidc.example.com/abc"
+		serviceAccount = "system:serviceaccount:openshift-image-registry:installer-cloud-credentials"
+	)
+
+	tests := []struct {
+		name       string
+		document   string
+		wantStatus string
+	}{
+		{
+			name:       "trusts the service account",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRoleWithWebIdentity","Principal":{"Federated":"` + providerARN + `"},"Condition":{"StringEquals":{"oidc.example.com/abc:sub":["system:serviceaccount:openshift-image-registry:cluster-image-registry-operator","` + serviceAccount + `"]}}}]}`,
+			wantStatus: oidcCheckPass,
+		},
+		{
+			name:       "trusts the service account with a wildcard",
+			document:   `{"Statement":[{"Effect":"Allow","Action":["sts:AssumeRoleWithWebIdentity"],"Principal":{"Federated":["` + providerARN + `"]},"Condition":{"StringLike":{"oidc.example.com/abc:sub":"system:serviceaccount:openshift-image-registry:*"}}}]}`,
+			wantStatus: oidcCheckPass,
+		},
+		{
+			name:       "trusts another service account",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRoleWithWebIdentity","Principal":{"Federated":"` + providerARN + `"},"Condition":{"StringEquals":{"oidc.example.com/abc:sub":"system:serviceaccount:openshift-machine-api:aws-cloud-credentials"}}}]}`,
+			wantStatus: oidcCheckFail,
+		},
+		{
+			name:       "trusts another OIDC provider",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRoleWithWebIdentity","Principal":{"Federated":"arn:aws:iam::123456789012:oidc-provider/oidc.example.com/old"},"Condition":{"StringEquals":{"oidc.example.com/old:sub":"` + serviceAccount + `"}}}]}`,
+			wantStatus: oidcCheckFail,
+		},
+		{
+			name:       "doesn't restrict the service account",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRoleWithWebIdentity","Principal":{"Federated":"` + providerARN + `"}}]}`,
+			wantStatus: oidcCheckWarn,
+		},
+		{
+			name:       "no web identity statement",
+			document:   `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::123456789012:root"}}]}`,
+			wantStatus: oidcCheckFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, details := evaluateTrustPolicy(tt.document, providerARN, issuer, serviceAccount)
+			if status != tt.wantStatus {
+				t.Errorf("evaluateTrustPolicy() = %v (%s), want %v", status, details, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestEvaluateOidcProvider(t *testing.T) {
+	results := evaluateOidcProvider("arn", []string{"openshift", "sts.amazonaws.com"}, []string{"ABCDEF"}, "abcdef")
+	if len(results) != 2 || results[0].Status != oidcCheckPass || results[1].Status != oidcCheckPass {
+		t.Errorf("expected the provider and its thumbprint to pass, got %+v", results)
+	}
+
+	results = evaluateOidcProvider("arn", []string{"openshift"}, []string{"123456"}, "abcdef")
+	if len(results) != 2 || results[0].Status != oidcCheckFail || results[1].Status != oidcCheckWarn {
+		t.Errorf("expected a missing audience and a thumbprint mismatch, got %+v", results)
+	}
+}
+
+func TestCheckOidcDiscovery(t *testing.T) {
+	var issuer string
+	keys := `{"keys":[{"kid":"a"}]}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "jwks_uri": issuer + "/keys.json"})
+	})
+	mux.HandleFunc("/keys.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(keys))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	issuer = server.URL
+
+	results := checkOidcDiscovery(server.Client(), server.URL)
+	if len(results) != 2 || results[0].Status != oidcCheckPass || results[1].Status != oidcCheckPass {
+		t.Errorf("expected the discovery checks to pass, got %+v", results)
+	}
+
+	keys = `{"keys":[]}`
+	results = checkOidcDiscovery(server.Client(), server.URL)
+	if len(results) != 2 || results[1].Status != oidcCheckFail {
+		t.Errorf("expected the signing keys check to fail, got %+v", results)
+	}
+
+	results = checkOidcDiscovery(server.Client(), server.URL+"/other")
+	if len(results) != 1 || results[0].Status != oidcCheckFail {
+		t.Errorf("expected the discovery check to fail, got %+v", results)
+	}
+}
@@ -0,0 +1,413 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveinternalv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// syncSetPauseAnnotation on a ClusterDeployment stops hive from applying the SyncSets and SelectorSyncSets
+	syncSetPauseAnnotation = "hive.openshift.io/syncset-pause"
+	// the pauses made by osdctl record when they expire, why and by whom they were made
+	syncSetPauseExpiresAnnotation = "osdctl.openshift.io/syncset-pause-expires"
+	syncSetPauseReasonAnnotation  = "osdctl.openshift.io/syncset-pause-reason"
+	syncSetPauseByAnnotation      = "osdctl.openshift.io/syncset-pause-by"
+
+	clusterIDLabel      = "api.openshift.com/id"
+	maxSyncSetPause     = 24 * time.Hour
+	defaultSyncSetPause = 2 * time.Hour
+)
+
+// syncSetPause is the pause state of the SyncSets of a ClusterDeployment
+type syncSetPause struct {
+	paused bool
+	// expires is zero for the pauses made by hand on hive, those never expire
+	expires time.Time
+	reason  string
+	by      string
+}
+
+func (p syncSetPause) expired(now time.Time) bool {
+	return p.paused && !p.expires.IsZero() && now.After(p.expires)
+}
+
+func (p syncSetPause) String() string {
+	if !p.paused {
+		return "not paused"
+	}
+	if p.expires.IsZero() {
+		return "paused by hand on hive, no expiry"
+	}
+	return fmt.Sprintf("paused by %s until %s: %s", p.by, p.expires.Format(time.RFC3339), p.reason)
+}
+
+type pauseSyncSetOptions struct {
+	clusterID string
+	hiveID    string
+	reason    string
+	duration  time.Duration
+	resume    bool
+	expire    bool
+}
+
+func newCmdPauseSyncSet() *cobra.Command {
+	ops := &pauseSyncSetOptions{}
+	pauseSyncSetCmd := &cobra.Command{
+		Use:   "pause-syncset [cluster-id]",
+		Short: "Temporarily pause the SyncSets of a cluster on hive",
+		Long: `Pause hive from applying the SyncSets and SelectorSyncSets to a cluster, e.g. while testing a change to a
+managed resource, instead of editing the ClusterDeployment by hand. The pause requires a reason, is recorded with
+who made it and expires after --duration (at most 24h).
+
+Hive doesn't expire the pauses itself: 'osdctl cluster syncsets' reports the expired ones, --resume lifts the
+pause of a cluster and --expire lifts all the expired pauses of a hive shard, e.g. from a scheduled job.`,
+		Example: `  # Pause the SyncSets of a cluster for 2 hours
+  osdctl cluster pause-syncset <cluster-id> --reason OHSS-1234 --duration 2h
+
+  # Resume them
+  osdctl cluster pause-syncset <cluster-id> --reason OHSS-1234 --resume
+
+  # Resume the expired pauses of a hive shard
+  osdctl cluster pause-syncset --expire --hive <hive-cluster-id> --reason OHSS-1234`,
+		Args:              cobra.MaximumNArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 1 {
+				ops.clusterID = args[0]
+			}
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	pauseSyncSetCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for the pause, which requires elevation (usually an OHSS or PD ticket)")
+	pauseSyncSetCmd.Flags().DurationVar(&ops.duration, "duration", defaultSyncSetPause, "How long the pause lasts, at most 24h")
+	pauseSyncSetCmd.Flags().BoolVar(&ops.resume, "resume", false, "Resume the SyncSets of the cluster")
+	pauseSyncSetCmd.Flags().BoolVar(&ops.expire, "expire", false, "Resume the SyncSets of the clusters of the --hive shard whose pause expired")
+	pauseSyncSetCmd.Flags().StringVar(&ops.hiveID, "hive", "", "The hive shard to expire the pauses of, with --expire")
+	_ = pauseSyncSetCmd.MarkFlagRequired("reason")
+	pauseSyncSetCmd.MarkFlagsMutuallyExclusive("resume", "expire")
+
+	return pauseSyncSetCmd
+}
+
+func (o *pauseSyncSetOptions) validate() error {
+	if o.expire {
+		if o.clusterID != "" || o.hiveID == "" {
+			return fmt.Errorf("--expire takes a --hive shard instead of a cluster")
+		}
+		return nil
+	}
+	if o.clusterID == "" {
+		return fmt.Errorf("the cluster ID is required")
+	}
+	if o.duration <= 0 || o.duration > maxSyncSetPause {
+		return fmt.Errorf("the pause duration must be between 0 and %s", maxSyncSetPause)
+	}
+	return nil
+}
+
+func (o *pauseSyncSetOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	if o.expire {
+		hive, err := utils.GetClusterAnyStatus(connection, o.hiveID)
+		if err != nil {
+			return err
+		}
+		hiveClient, err := newSyncSetHiveClient(hive.ID(), o.reason, "Expiring the SyncSet pauses of the hive shard")
+		if err != nil {
+			return err
+		}
+		return expireSyncSetPauses(context.TODO(), hiveClient, time.Now())
+	}
+
+	cluster, err := utils.GetClusterAnyStatus(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	hive, err := utils.GetHiveCluster(cluster.ID())
+	if err != nil {
+		return err
+	}
+	action := "Pausing"
+	if o.resume {
+		action = "Resuming"
+	}
+	hiveClient, err := newSyncSetHiveClient(hive.ID(), o.reason, fmt.Sprintf("%s the SyncSets of cluster %s", action, cluster.ID()))
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	cd, err := clusterDeploymentOf(ctx, hiveClient, cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	if o.resume {
+		if err := resumeSyncSets(ctx, hiveClient, cd); err != nil {
+			return err
+		}
+		fmt.Printf("Resumed the SyncSets of cluster %s, run 'osdctl cluster resync' to apply them now\n", cluster.ID())
+		return nil
+	}
+
+	by := "unknown"
+	if account, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send(); err == nil {
+		by = account.Body().Username()
+	}
+	patch := client.MergeFrom(cd.DeepCopy())
+	annotations := cd.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for key, value := range pauseAnnotations(o.duration, o.reason, by, time.Now()) {
+		annotations[key] = value
+	}
+	cd.SetAnnotations(annotations)
+	if err := hiveClient.Patch(ctx, cd, patch); err != nil {
+		return fmt.Errorf("failed to pause the SyncSets of ClusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	fmt.Printf("SyncSets of cluster %s %s\n", cluster.ID(), pauseOf(cd))
+	return nil
+}
+
+func newSyncSetHiveClient(hiveID string, reasons ...string) (client.Client, error) {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{hivev1.AddToScheme, hiveinternalv1alpha1.AddToScheme} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+	if len(reasons) == 0 {
+		return k8s.New(hiveID, client.Options{Scheme: scheme})
+	}
+	return k8s.NewAsBackplaneClusterAdmin(hiveID, client.Options{Scheme: scheme}, reasons...)
+}
+
+func clusterDeploymentOf(ctx context.Context, hiveClient client.Client, clusterID string) (*hivev1.ClusterDeployment, error) {
+	cds := &hivev1.ClusterDeploymentList{}
+	if err := hiveClient.List(ctx, cds, client.MatchingLabels{clusterIDLabel: clusterID}); err != nil {
+		return nil, fmt.Errorf("failed to list the ClusterDeployments: %w", err)
+	}
+	if len(cds.Items) != 1 {
+		return nil, fmt.Errorf("expected 1 ClusterDeployment with label %s=%s, found %d", clusterIDLabel, clusterID, len(cds.Items))
+	}
+	return &cds.Items[0], nil
+}
+
+// pauseAnnotations returns the annotations pausing the SyncSets for the duration
+func pauseAnnotations(duration time.Duration, reason string, by string, now time.Time) map[string]string {
+	return map[string]string{
+		syncSetPauseAnnotation:        "true",
+		syncSetPauseExpiresAnnotation: now.Add(duration).UTC().Format(time.RFC3339),
+		syncSetPauseReasonAnnotation:  reason,
+		syncSetPauseByAnnotation:      by,
+	}
+}
+
+func pauseOf(cd *hivev1.ClusterDeployment) syncSetPause {
+	annotations := cd.GetAnnotations()
+	pause := syncSetPause{
+		paused: annotations[syncSetPauseAnnotation] == "true",
+		reason: annotations[syncSetPauseReasonAnnotation],
+		by:     annotations[syncSetPauseByAnnotation],
+	}
+	pause.expires, _ = time.Parse(time.RFC3339, annotations[syncSetPauseExpiresAnnotation])
+	return pause
+}
+
+func resumeSyncSets(ctx context.Context, hiveClient client.Client, cd *hivev1.ClusterDeployment) error {
+	patch := client.MergeFrom(cd.DeepCopy())
+	annotations := cd.GetAnnotations()
+	for _, key := range []string{syncSetPauseAnnotation, syncSetPauseExpiresAnnotation, syncSetPauseReasonAnnotation, syncSetPauseByAnnotation} {
+		delete(annotations, key)
+	}
+	cd.SetAnnotations(annotations)
+	if err := hiveClient.Patch(ctx, cd, patch); err != nil {
+		return fmt.Errorf("failed to resume the SyncSets of ClusterDeployment %s/%s: %w", cd.Namespace, cd.Name, err)
+	}
+	return nil
+}
+
+// expireSyncSetPauses resumes the SyncSets of the ClusterDeployments of the hive shard whose pause expired,
+// the pauses made by hand are left alone
+func expireSyncSetPauses(ctx context.Context, hiveClient client.Client, now time.Time) error {
+	cds := &hivev1.ClusterDeploymentList{}
+	if err := hiveClient.List(ctx, cds); err != nil {
+		return fmt.Errorf("failed to list the ClusterDeployments: %w", err)
+	}
+	expired := 0
+	for i := range cds.Items {
+		cd := &cds.Items[i]
+		pause := pauseOf(cd)
+		if !pause.expired(now) {
+			continue
+		}
+		if err := resumeSyncSets(ctx, hiveClient, cd); err != nil {
+			return err
+		}
+		expired++
+		fmt.Printf("Resumed the SyncSets of cluster %s, %s\n", cd.Labels[clusterIDLabel], pause)
+	}
+	fmt.Printf("Resumed %d expired pauses\n", expired)
+	return nil
+}
+
+type syncSetsOptions struct {
+	clusterID string
+}
+
+func newCmdSyncSets() *cobra.Command {
+	ops := &syncSetsOptions{}
+	syncSetsCmd := &cobra.Command{
+		Use:   "syncsets <cluster-id>",
+		Short: "List the SyncSets and SelectorSyncSets applied to a cluster and their pause",
+		Long: `List the SyncSets referencing the ClusterDeployment of a cluster and the SelectorSyncSets selecting it, with
+their apply mode and the result of their last sync, and whether they are paused, by whom, why and until when.`,
+		Example:           `  osdctl cluster syncsets <cluster-id>`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+	return syncSetsCmd
+}
+
+func (o *syncSetsOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	hive, err := utils.GetHiveCluster(cluster.ID())
+	if err != nil {
+		return err
+	}
+	hiveClient, err := newSyncSetHiveClient(hive.ID())
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	cd, err := clusterDeploymentOf(ctx, hiveClient, cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	syncSets := &hivev1.SyncSetList{}
+	if err := hiveClient.List(ctx, syncSets, client.InNamespace(cd.Namespace)); err != nil {
+		return fmt.Errorf("failed to list the SyncSets: %w", err)
+	}
+	selectorSyncSets := &hivev1.SelectorSyncSetList{}
+	if err := hiveClient.List(ctx, selectorSyncSets); err != nil {
+		return fmt.Errorf("failed to list the SelectorSyncSets: %w", err)
+	}
+	results := map[string]string{}
+	clusterSync := &hiveinternalv1alpha1.ClusterSync{}
+	if err := hiveClient.Get(ctx, client.ObjectKey{Namespace: cd.Namespace, Name: cd.Name}, clusterSync); err == nil {
+		results = syncResults(clusterSync)
+	}
+
+	pause := pauseOf(cd)
+	fmt.Printf("SyncSets of cluster %s: %s\n", cluster.ID(), pause)
+	if pause.expired(time.Now()) {
+		fmt.Println("The pause expired, resume it with 'osdctl cluster pause-syncset --resume'")
+	}
+	fmt.Println()
+
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"KIND", "NAME", "APPLY MODE", "RESOURCES", "PATCHES", "LAST SYNC"})
+	for _, syncSet := range syncSetsReferencing(syncSets.Items, cd.Name) {
+		table.AddRow(syncSetRow("SyncSet", syncSet.Name, syncSet.Spec.SyncSetCommonSpec, results["SyncSet/"+syncSet.Name]))
+	}
+	for _, selectorSyncSet := range selectorSyncSetsSelecting(selectorSyncSets.Items, cd.Labels) {
+		table.AddRow(syncSetRow("SelectorSyncSet", selectorSyncSet.Name, selectorSyncSet.Spec.SyncSetCommonSpec, results["SelectorSyncSet/"+selectorSyncSet.Name]))
+	}
+	table.AddRow([]string{})
+	return table.Flush()
+}
+
+func syncSetRow(kind string, name string, spec hivev1.SyncSetCommonSpec, result string) []string {
+	applyMode := string(spec.ResourceApplyMode)
+	if applyMode == "" {
+		applyMode = string(hivev1.UpsertResourceApplyMode)
+	}
+	if result == "" {
+		result = "unknown"
+	}
+	return []string{kind, name, applyMode, fmt.Sprint(len(spec.Resources)), fmt.Sprint(len(spec.Patches)), result}
+}
+
+// syncSetsReferencing returns the SyncSets referencing the ClusterDeployment, sorted by name
+func syncSetsReferencing(syncSets []hivev1.SyncSet, cdName string) []hivev1.SyncSet {
+	var referencing []hivev1.SyncSet
+	for _, syncSet := range syncSets {
+		for _, ref := range syncSet.Spec.ClusterDeploymentRefs {
+			if ref.Name == cdName {
+				referencing = append(referencing, syncSet)
+				break
+			}
+		}
+	}
+	sort.Slice(referencing, func(i, j int) bool { return referencing[i].Name < referencing[j].Name })
+	return referencing
+}
+
+// selectorSyncSetsSelecting returns the SelectorSyncSets whose selector matches the labels of the
+// ClusterDeployment, sorted by name
+func selectorSyncSetsSelecting(selectorSyncSets []hivev1.SelectorSyncSet, cdLabels map[string]string) []hivev1.SelectorSyncSet {
+	var selecting []hivev1.SelectorSyncSet
+	for _, selectorSyncSet := range selectorSyncSets {
+		selector, err := metav1.LabelSelectorAsSelector(&selectorSyncSet.Spec.ClusterDeploymentSelector)
+		if err != nil || !selector.Matches(labels.Set(cdLabels)) {
+			continue
+		}
+		selecting = append(selecting, selectorSyncSet)
+	}
+	sort.Slice(selecting, func(i, j int) bool { return selecting[i].Name < selecting[j].Name })
+	return selecting
+}
+
+// syncResults returns the result of the last sync of each SyncSet and SelectorSyncSet, by kind/name
+func syncResults(clusterSync *hiveinternalv1alpha1.ClusterSync) map[string]string {
+	results := map[string]string{}
+	add := func(kind string, statuses []hiveinternalv1alpha1.SyncStatus) {
+		for _, status := range statuses {
+			result := string(status.Result)
+			if status.FailureMessage != "" {
+				result += ": " + strings.SplitN(status.FailureMessage, "\n", 2)[0]
+			}
+			results[kind+"/"+status.Name] = result
+		}
+	}
+	add("SyncSet", clusterSync.Status.SyncSets)
+	add("SelectorSyncSet", clusterSync.Status.SelectorSyncSets)
+	return results
+}
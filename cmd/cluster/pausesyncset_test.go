@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveinternalv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncSetPause(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantPaused  bool
+		wantExpired bool
+	}{
+		{
+			name: "Not paused",
+		},
+		{
+			name:        "Paused by osdctl",
+			annotations: pauseAnnotations(time.Hour, "OHSS-1", "user", now),
+			wantPaused:  true,
+		},
+		{
+			name:        "Pause expired",
+			annotations: pauseAnnotations(time.Hour, "OHSS-1", "user", now.Add(-2*time.Hour)),
+			wantPaused:  true,
+			wantExpired: true,
+		},
+		{
+			name:        "Paused by hand never expires",
+			annotations: map[string]string{syncSetPauseAnnotation: "true"},
+			wantPaused:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cd := &hivev1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			pause := pauseOf(cd)
+			if pause.paused != tt.wantPaused || pause.expired(now) != tt.wantExpired {
+				t.Errorf("pauseOf() = %+v, expired %v, want paused %v, expired %v", pause, pause.expired(now), tt.wantPaused, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestSyncSetsApplyingToCluster(t *testing.T) {
+	syncSets := []hivev1.SyncSet{
+		{ObjectMeta: metav1.ObjectMeta{Name: "other"}, Spec: hivev1.SyncSetSpec{ClusterDeploymentRefs: []corev1.LocalObjectReference{{Name: "other-cd"}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: hivev1.SyncSetSpec{ClusterDeploymentRefs: []corev1.LocalObjectReference{{Name: "other-cd"}, {Name: "cd"}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: hivev1.SyncSetSpec{ClusterDeploymentRefs: []corev1.LocalObjectReference{{Name: "cd"}}}},
+	}
+	var names []string
+	for _, syncSet := range syncSetsReferencing(syncSets, "cd") {
+		names = append(names, syncSet.Name)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("syncSetsReferencing() = %v, want %v", names, want)
+	}
+
+	selectorSyncSet := func(name string, selector metav1.LabelSelector) hivev1.SelectorSyncSet {
+		return hivev1.SelectorSyncSet{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: hivev1.SelectorSyncSetSpec{ClusterDeploymentSelector: selector}}
+	}
+	selectorSyncSets := []hivev1.SelectorSyncSet{
+		selectorSyncSet("managed", metav1.LabelSelector{MatchLabels: map[string]string{"api.openshift.com/managed": "true"}}),
+		selectorSyncSet("gcp", metav1.LabelSelector{MatchLabels: map[string]string{"hive.openshift.io/cluster-platform": "gcp"}}),
+		selectorSyncSet("not-opted-out", metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "ext-managed.openshift.io/opt-out", Operator: metav1.LabelSelectorOpDoesNotExist},
+		}}),
+	}
+	cdLabels := map[string]string{"api.openshift.com/managed": "true", "hive.openshift.io/cluster-platform": "aws"}
+	names = nil
+	for _, selectorSyncSet := range selectorSyncSetsSelecting(selectorSyncSets, cdLabels) {
+		names = append(names, selectorSyncSet.Name)
+	}
+	if want := []string{"managed", "not-opted-out"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("selectorSyncSetsSelecting() = %v, want %v", names, want)
+	}
+}
+
+func TestSyncResults(t *testing.T) {
+	clusterSync := &hiveinternalv1alpha1.ClusterSync{Status: hiveinternalv1alpha1.ClusterSyncStatus{
+		SyncSets: []hiveinternalv1alpha1.SyncStatus{{Name: "a", Result: hiveinternalv1alpha1.SuccessSyncSetResult}},
+		SelectorSyncSets: []hiveinternalv1alpha1.SyncStatus{
+			{Name: "b", Result: hiveinternalv1alpha1.FailureSyncSetResult, FailureMessage: "failed to apply\ndetails"},
+		},
+	}}
+	want := map[string]string{
+		"SyncSet/a":         "Success",
+		"SelectorSyncSet/b": "Failure: failed to apply",
+	}
+	if got := syncResults(clusterSync); !reflect.DeepEqual(got, want) {
+		t.Errorf("syncResults() = %v, want %v", got, want)
+	}
+}
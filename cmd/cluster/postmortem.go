@@ -0,0 +1,437 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	ctAws "github.com/openshift/osdctl/cmd/cloudtrail/pkg/aws"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// deletionEventNames are the CloudTrail events which tear down the infrastructure of a cluster
+var deletionEventNames = []string{
+	"DeleteStack",
+	"TerminateInstances",
+	"DeleteVpc",
+	"DeleteLoadBalancer",
+	"DeleteNatGateway",
+}
+
+// automationPrincipals are the (lowercase) fragments of the IAM principals used by the OSD/ROSA
+// installer and machine-api, deletions by anything else are reported as findings
+var automationPrincipals = []string{
+	"osdmanagedadmin",
+	"installer-role",
+	"openshift-machine-api",
+	"organizationaccountaccessrole",
+	"managedopenshift-support",
+	"rh-sre-",
+}
+
+// telemetryGap is how long a cluster can stay silent before its deprovision without being reported
+const telemetryGap = time.Hour
+
+type postMortemOptions struct {
+	externalID  string
+	since       time.Duration
+	serviceLogs int
+	logLines    int
+	output      string
+}
+
+type timelineEntry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Event  string    `json:"event"`
+}
+
+type postMortemReport struct {
+	ExternalID         string          `json:"external_id"`
+	ClusterID          string          `json:"cluster_id,omitempty"`
+	Name               string          `json:"name,omitempty"`
+	SubscriptionStatus string          `json:"subscription_status,omitempty"`
+	ClusterState       string          `json:"cluster_state,omitempty"`
+	Findings           []string        `json:"findings"`
+	Timeline           []timelineEntry `json:"timeline"`
+	UninstallLog       string          `json:"uninstall_log,omitempty"`
+	Errors             []string        `json:"errors,omitempty"`
+
+	lastTelemetry   time.Time
+	deprovisionedAt time.Time
+	deletions       []deletionEvent
+}
+
+// deletionEvent is a CloudTrail deletion along with the principal which made it
+type deletionEvent struct {
+	Time      time.Time
+	EventName string
+	Principal string
+}
+
+func newCmdPostMortem() *cobra.Command {
+	ops := &postMortemOptions{}
+	postMortemCmd := &cobra.Command{
+		Use:   "post-mortem <external-id>",
+		Short: "Reconstruct why and how a cluster was deleted",
+		Long: `Assemble a single report explaining the deletion of a cluster from everything which outlives it:
+
+  - the OCM subscription and, while it still exists, the cluster record and its uninstall log
+  - the ClusterDeprovision and the uninstall jobs on the hive shard
+  - the CloudTrail DeleteStack, TerminateInstances, DeleteVpc... events of the cluster's account
+  - the last service logs sent to the cluster
+
+Deletions made by a principal which isn't part of the OSD/ROSA automation and a cluster which stopped
+reporting telemetry well before being deprovisioned are called out as findings. Sources which can't
+be reached anymore are listed at the end of the report rather than failing it.`,
+		Example: `  # Find out what happened to a cluster which disappeared
+  osdctl cluster post-mortem <external-id>
+
+  # Look further back in CloudTrail and save the report
+  osdctl cluster post-mortem <external-id> --since 336h -o json > post-mortem.json`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.externalID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	postMortemCmd.Flags().DurationVar(&ops.since, "since", 7*24*time.Hour, "How far back CloudTrail is searched for deletion events")
+	postMortemCmd.Flags().IntVar(&ops.serviceLogs, "service-logs", 10, "Number of final service logs to include")
+	postMortemCmd.Flags().IntVar(&ops.logLines, "log-lines", 30, "Number of trailing uninstall log lines to include")
+	postMortemCmd.Flags().StringVarP(&ops.output, "output", "o", "text", "Output format, one of text or json")
+
+	return postMortemCmd
+}
+
+func (o *postMortemOptions) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output format '%s', expected text or json", o.output)
+	}
+	if o.since <= 0 {
+		return fmt.Errorf("--since must be positive")
+	}
+	return nil
+}
+
+func (o *postMortemOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	subscription, err := utils.GetSubscription(ocmClient, o.externalID)
+	if err != nil {
+		return fmt.Errorf("failed to get the subscription of %s: %w", o.externalID, err)
+	}
+
+	report := &postMortemReport{ExternalID: o.externalID}
+	report.addSubscription(subscription.ClusterID(), subscription.DisplayName(), string(subscription.Status()),
+		subscription.CreatedAt(), subscription.UpdatedAt(), subscription.LastTelemetryDate(), subscription.Creator().Username())
+
+	cluster := o.collectCluster(ocmClient, report)
+	if report.ClusterID != "" {
+		o.collectHive(report)
+	}
+	if cluster != nil {
+		o.collectCloudTrail(ocmClient, cluster, report)
+	} else {
+		report.addError("cloudtrail", fmt.Errorf("the cluster record is gone from OCM, its AWS account can't be resolved"))
+	}
+	o.collectServiceLogs(ocmClient, report)
+
+	report.finalize()
+	if o.output == "json" {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	report.print()
+	return nil
+}
+
+func (r *postMortemReport) add(when time.Time, source string, format string, args ...interface{}) {
+	if when.IsZero() {
+		return
+	}
+	r.Timeline = append(r.Timeline, timelineEntry{Time: when.UTC(), Source: source, Event: fmt.Sprintf(format, args...)})
+}
+
+func (r *postMortemReport) addError(source string, err error) {
+	r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", source, err))
+}
+
+func (r *postMortemReport) addSubscription(clusterID, name, status string, created, updated, lastTelemetry time.Time, creator string) {
+	r.ClusterID = clusterID
+	r.Name = name
+	r.SubscriptionStatus = status
+	r.lastTelemetry = lastTelemetry
+
+	if creator != "" {
+		r.add(created, "ocm", "Subscription created by %s", creator)
+	} else {
+		r.add(created, "ocm", "Subscription created")
+	}
+	r.add(lastTelemetry, "ocm", "Last telemetry received from the cluster")
+	r.add(updated, "ocm", "Subscription last updated, status is %s", status)
+	if status == "Deprovisioned" || status == "Archived" {
+		r.deprovisionedAt = updated
+	}
+}
+
+// collectCluster returns the OCM cluster, or nil once the clusters service has forgotten about it
+func (o *postMortemOptions) collectCluster(ocmClient *sdk.Connection, report *postMortemReport) *cmv1.Cluster {
+	if report.ClusterID == "" {
+		report.ClusterState = "never registered in the clusters service"
+		return nil
+	}
+	cluster, found, err := getClusterByID(ocmClient, report.ClusterID)
+	if err != nil {
+		report.addError("ocm", err)
+		return nil
+	}
+	if !found {
+		report.ClusterState = "removed from the clusters service"
+		return nil
+	}
+
+	report.ClusterState = string(cluster.State())
+	report.add(cluster.CreationTimestamp(), "ocm", "Cluster created in the clusters service")
+
+	response, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(report.ClusterID).Logs().Uninstall().Get().Send()
+	if err != nil {
+		report.addError("uninstall log", err)
+	} else {
+		report.UninstallLog = tailLines(response.Body().Content(), o.logLines)
+	}
+	return cluster
+}
+
+func (o *postMortemOptions) collectHive(report *postMortemReport) {
+	hiveClient, err := newHiveClient(report.ClusterID)
+	if err != nil {
+		report.addError("hive", err)
+		return
+	}
+
+	var deprovisions hivev1.ClusterDeprovisionList
+	if err := hiveClient.List(context.TODO(), &deprovisions, client.MatchingLabels{"api.openshift.com/id": report.ClusterID}); err != nil {
+		report.addError("hive", err)
+		return
+	}
+	if len(deprovisions.Items) == 0 {
+		report.addError("hive", fmt.Errorf("no ClusterDeprovision left for the cluster"))
+		return
+	}
+
+	for _, deprovision := range deprovisions.Items {
+		report.add(deprovision.CreationTimestamp.Time, "hive", "ClusterDeprovision %s/%s created", deprovision.Namespace, deprovision.Name)
+		for _, condition := range deprovision.Status.Conditions {
+			report.add(condition.LastTransitionTime.Time, "hive", "ClusterDeprovision %s=%s %s: %s", condition.Type, condition.Status, condition.Reason, strings.TrimSpace(condition.Message))
+		}
+		if deprovision.Status.Completed {
+			report.add(deprovision.CreationTimestamp.Time, "hive", "ClusterDeprovision %s is completed", deprovision.Name)
+		}
+
+		var jobs batchv1.JobList
+		if err := hiveClient.List(context.TODO(), &jobs, client.InNamespace(deprovision.Namespace), client.MatchingLabels{"hive.openshift.io/uninstall": "true"}); err != nil {
+			report.addError("hive", err)
+			continue
+		}
+		for _, job := range jobs.Items {
+			if job.Status.StartTime != nil {
+				report.add(job.Status.StartTime.Time, "hive", "Uninstall job %s started", job.Name)
+			}
+			if job.Status.CompletionTime != nil {
+				report.add(job.Status.CompletionTime.Time, "hive", "Uninstall job %s succeeded", job.Name)
+			}
+			if job.Status.Failed > 0 {
+				report.add(job.CreationTimestamp.Time, "hive", "Uninstall job %s failed %d time(s)", job.Name, job.Status.Failed)
+			}
+		}
+	}
+}
+
+func (o *postMortemOptions) collectCloudTrail(ocmClient *sdk.Connection, cluster *cmv1.Cluster, report *postMortemReport) {
+	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
+		report.addError("cloudtrail", fmt.Errorf("only available for AWS clusters"))
+		return
+	}
+	cfg, err := osdCloud.CreateAWSV2Config(ocmClient, cluster)
+	if err != nil {
+		report.addError("cloudtrail", err)
+		return
+	}
+
+	ctClient := cloudtrail.NewFromConfig(cfg)
+	startTime := time.Now().UTC().Add(-o.since)
+	for _, eventName := range deletionEventNames {
+		events, err := ctAws.GetEventsByName(ctClient, startTime, eventName)
+		if err != nil {
+			report.addError("cloudtrail", err)
+			continue
+		}
+		for _, event := range events {
+			report.addDeletion(event)
+		}
+	}
+}
+
+func (r *postMortemReport) addDeletion(event types.Event) {
+	if event.EventTime == nil || event.EventName == nil {
+		return
+	}
+	deletion := deletionEvent{Time: *event.EventTime, EventName: *event.EventName, Principal: cloudTrailPrincipal(event)}
+	r.deletions = append(r.deletions, deletion)
+
+	var resources []string
+	for _, resource := range event.Resources {
+		if resource.ResourceName != nil {
+			resources = append(resources, *resource.ResourceName)
+		}
+	}
+	if len(resources) > 0 {
+		r.add(deletion.Time, "cloudtrail", "%s of %s by %s", deletion.EventName, strings.Join(resources, ", "), deletion.Principal)
+	} else {
+		r.add(deletion.Time, "cloudtrail", "%s by %s", deletion.EventName, deletion.Principal)
+	}
+}
+
+func cloudTrailPrincipal(event types.Event) string {
+	raw, err := ctAws.ExtractUserDetails(event.CloudTrailEvent)
+	if err == nil {
+		if issuer := raw.UserIdentity.SessionContext.SessionIssuer.Arn; issuer != "" {
+			return issuer
+		}
+		if raw.UserIdentity.Arn != "" {
+			return raw.UserIdentity.Arn
+		}
+	}
+	if event.Username != nil {
+		return *event.Username
+	}
+	return "<unknown>"
+}
+
+func isAutomationPrincipal(principal string) bool {
+	principal = strings.ToLower(principal)
+	for _, fragment := range automationPrincipals {
+		if strings.Contains(principal, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *postMortemOptions) collectServiceLogs(ocmClient *sdk.Connection, report *postMortemReport) {
+	response, err := ocmClient.ServiceLogs().V1().Clusters().ClusterLogs().List().
+		Parameter("cluster_uuid", report.ExternalID).
+		Parameter("orderBy", "timestamp desc").
+		Size(o.serviceLogs).
+		Send()
+	if err != nil {
+		report.addError("service logs", err)
+		return
+	}
+	for _, entry := range response.Items().Slice() {
+		report.add(entry.Timestamp(), "servicelog", "[%s] %s (%s)", entry.Severity(), entry.Summary(), entry.ServiceName())
+	}
+}
+
+// finalize sorts the timeline and derives the findings from what was collected
+func (r *postMortemReport) finalize() {
+	sort.SliceStable(r.Timeline, func(i, j int) bool {
+		return r.Timeline[i].Time.Before(r.Timeline[j].Time)
+	})
+
+	r.Findings = []string{}
+	sort.SliceStable(r.deletions, func(i, j int) bool {
+		return r.deletions[i].Time.Before(r.deletions[j].Time)
+	})
+	for _, deletion := range r.deletions {
+		if !isAutomationPrincipal(deletion.Principal) {
+			r.Findings = append(r.Findings, fmt.Sprintf("%s at %s was made by %s, which isn't part of the OSD/ROSA automation",
+				deletion.EventName, deletion.Time.UTC().Format(time.RFC3339), deletion.Principal))
+		}
+	}
+	if !r.lastTelemetry.IsZero() && !r.deprovisionedAt.IsZero() {
+		if gap := r.deprovisionedAt.Sub(r.lastTelemetry); gap > telemetryGap {
+			r.Findings = append(r.Findings, fmt.Sprintf("The cluster stopped reporting telemetry %s before being deprovisioned, its infrastructure may have been removed outside of OCM",
+				gap.Round(time.Minute)))
+		}
+	}
+}
+
+func (r *postMortemReport) print() {
+	fmt.Printf("Post-mortem of cluster %s (external ID %s", r.Name, r.ExternalID)
+	if r.ClusterID != "" {
+		fmt.Printf(", ID %s", r.ClusterID)
+	}
+	fmt.Println(")")
+	fmt.Printf("Subscription status: %s\n", r.SubscriptionStatus)
+	fmt.Printf("Cluster state:       %s\n", r.ClusterState)
+
+	fmt.Println()
+	fmt.Println(delimiter + "Findings")
+	if len(r.Findings) == 0 {
+		fmt.Println("Nothing unusual found")
+	}
+	for _, finding := range r.Findings {
+		fmt.Printf("- %s\n", finding)
+	}
+
+	fmt.Println()
+	fmt.Println(delimiter + "Timeline")
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"TIME", "SOURCE", "EVENT"})
+	for _, entry := range r.Timeline {
+		table.AddRow([]string{entry.Time.Format(time.RFC3339), entry.Source, entry.Event})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print the timeline: %v\n", err)
+	}
+
+	if r.UninstallLog != "" {
+		fmt.Println()
+		fmt.Println(delimiter + "Uninstall log")
+		fmt.Println(r.UninstallLog)
+	}
+
+	if len(r.Errors) > 0 {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Some sources couldn't be collected:")
+		for _, err := range r.Errors {
+			fmt.Fprintf(os.Stderr, "- %s\n", err)
+		}
+	}
+}
+
+// tailLines returns the last n lines of content
+func tailLines(content string, n int) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,123 @@
+package cluster
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestIsAutomationPrincipal(t *testing.T) {
+	tests := []struct {
+		principal string
+		want      bool
+	}{
+		{"arn:aws:iam::123456789012:user/osdManagedAdmin-abcd", true},
+		{"arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role", true},
+		{"arn:aws:iam::123456789012:role/mycluster-x1y2-openshift-machine-api-aws-cloud-credentials", true},
+		{"arn:aws:iam::123456789012:role/OrganizationAccountAccessRole", true},
+		{"arn:aws:iam::123456789012:user/jdoe", false},
+		{"<unknown>", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.principal, func(t *testing.T) {
+			if got := isAutomationPrincipal(tt.principal); got != tt.want {
+				t.Errorf("isAutomationPrincipal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func deletionCloudTrailEvent(name string, at time.Time, arn string) types.Event {
+	raw := `{"eventVersion": "1.08","userIdentity": {"arn": "` + arn + `"}}`
+	return types.Event{
+		EventName:       aws.String(name),
+		EventTime:       aws.Time(at),
+		CloudTrailEvent: aws.String(raw),
+		Resources:       []types.Resource{{ResourceName: aws.String("i-0123")}},
+	}
+}
+
+func TestPostMortemReportFinalize(t *testing.T) {
+	created := time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+	lastTelemetry := time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		updated      time.Time
+		status       string
+		events       []types.Event
+		wantFindings []string
+		wantTimeline int
+	}{
+		{
+			name:    "deprovisioned by the automation",
+			updated: lastTelemetry.Add(10 * time.Minute),
+			status:  "Deprovisioned",
+			events: []types.Event{
+				deletionCloudTrailEvent("TerminateInstances", lastTelemetry.Add(5*time.Minute), "arn:aws:iam::123456789012:user/osdManagedAdmin-abcd"),
+			},
+			wantTimeline: 4,
+		},
+		{
+			name:    "infrastructure removed by a user",
+			updated: lastTelemetry.Add(48 * time.Hour),
+			status:  "Deprovisioned",
+			events: []types.Event{
+				deletionCloudTrailEvent("TerminateInstances", lastTelemetry.Add(-time.Minute), "arn:aws:iam::123456789012:user/jdoe"),
+			},
+			wantFindings: []string{
+				"TerminateInstances at 2024-02-01T08:59:00Z was made by arn:aws:iam::123456789012:user/jdoe",
+				"stopped reporting telemetry 48h0m0s before being deprovisioned",
+			},
+			wantTimeline: 4,
+		},
+		{
+			name:         "telemetry gap on an active cluster isn't a finding",
+			updated:      lastTelemetry.Add(48 * time.Hour),
+			status:       "Active",
+			wantTimeline: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &postMortemReport{ExternalID: "ext"}
+			report.addSubscription("id", "name", tt.status, created, tt.updated, lastTelemetry, "")
+			for _, event := range tt.events {
+				report.addDeletion(event)
+			}
+			report.finalize()
+
+			if len(report.Findings) != len(tt.wantFindings) {
+				t.Fatalf("expected %d findings, got %v", len(tt.wantFindings), report.Findings)
+			}
+			for i, want := range tt.wantFindings {
+				if !strings.Contains(report.Findings[i], want) {
+					t.Errorf("finding %d = %q, expected it to contain %q", i, report.Findings[i], want)
+				}
+			}
+			if len(report.Timeline) != tt.wantTimeline {
+				t.Fatalf("expected %d timeline entries, got %v", tt.wantTimeline, report.Timeline)
+			}
+			for i := 1; i < len(report.Timeline); i++ {
+				if report.Timeline[i].Time.Before(report.Timeline[i-1].Time) {
+					t.Errorf("timeline isn't sorted: %v", report.Timeline)
+				}
+			}
+		})
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	content := "one\ntwo\nthree\n"
+	if got := tailLines(content, 2); got != "two\nthree" {
+		t.Errorf("tailLines() = %q", got)
+	}
+	if got := tailLines(content, 0); got != "one\ntwo\nthree" {
+		t.Errorf("tailLines() = %q", got)
+	}
+}
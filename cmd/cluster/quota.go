@@ -0,0 +1,375 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// computeNodeResourceType is the resource type the quota of the compute nodes is counted in
+	computeNodeResourceType = "compute.node"
+	// anyQuotaValue matches any value of a related resource of a quota
+	anyQuotaValue = "any"
+	// quotaCostPageSize is the page size the quota cost of an organization is listed with
+	quotaCostPageSize = 100
+	// machineTypesPageSize is enough for the machine types of a cloud provider in one page
+	machineTypesPageSize = 500
+)
+
+type quotaOptions struct {
+	clusterID     string
+	machinePoolID string
+	replicas      int
+}
+
+// nodeQuota is an OCM quota of an organization, with the resources it covers
+type nodeQuota struct {
+	QuotaID   string
+	Allowed   int
+	Consumed  int
+	Resources []quotaResource
+}
+
+// quotaResource is a resource a quota covers and what one of it costs
+type quotaResource struct {
+	ResourceType string
+	ResourceName string
+	Product      string
+	BYOC         string
+	BillingModel string
+	Cost         int
+}
+
+// quotaCluster is what the quota of the nodes of a cluster depends on
+type quotaCluster struct {
+	Product      string
+	BYOC         string
+	BillingModel string
+}
+
+// computePool is a machine pool or a node pool of a cluster
+type computePool struct {
+	ID           string
+	InstanceType string
+	// Replicas are the nodes of the pool, its minimum when it autoscales
+	Replicas    int
+	Autoscaling bool
+	MaxReplicas int
+}
+
+// scaleUp is a number of nodes a pool may be scaled up by
+type scaleUp struct {
+	Pool         string
+	InstanceType string
+	QuotaName    string
+	Nodes        int
+	// Planned is the scale-up given with --replicas, the others are what the autoscaling allows
+	Planned bool
+}
+
+func newCmdQuota() *cobra.Command {
+	ops := &quotaOptions{}
+	quotaCmd := &cobra.Command{
+		Use:   "quota <cluster-id>",
+		Short: "Show the OCM quota of the organization of a cluster and what the cluster consumes",
+		Long: `Show the quota cost of the organization owning the cluster, the compute nodes of the cluster by instance
+type with the quota they are counted in, and warn when scaling a pool up would exceed the quota of the
+subscription.
+
+The scale-ups checked are the one given with --machinepool and --replicas, and the growth the autoscaling of the
+pools allows.`,
+		Example: `  # Show the quota of the organization and the nodes of the cluster
+  osdctl cluster quota <cluster-id>
+
+  # Check the quota allows scaling the worker machine pool to 12 nodes
+  osdctl cluster quota <cluster-id> --machinepool worker --replicas 12`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	quotaCmd.Flags().StringVar(&ops.machinePoolID, "machinepool", "", "Machine pool or node pool of a planned scale-up")
+	quotaCmd.Flags().IntVar(&ops.replicas, "replicas", 0, "Number of nodes the machine pool is planned to be scaled to")
+
+	return quotaCmd
+}
+
+func (o *quotaOptions) validate() error {
+	if (o.machinePoolID == "") != (o.replicas == 0) {
+		return fmt.Errorf("--machinepool and --replicas go together")
+	}
+	if o.replicas < 0 {
+		return fmt.Errorf("--replicas must be positive")
+	}
+	return nil
+}
+
+func (o *quotaOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	orgID, err := utils.GetOrgfromClusterID(ocmClient, *cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get the organization of cluster %s: %w", cluster.ID(), err)
+	}
+
+	quotas, err := fetchNodeQuotas(ocmClient, orgID)
+	if err != nil {
+		return err
+	}
+	pools, err := fetchComputePools(ocmClient, cluster)
+	if err != nil {
+		return err
+	}
+	quotaNames, err := fetchQuotaNames(ocmClient, cluster.CloudProvider().ID())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Quota cost of organization %s\n", orgID)
+	if err := printQuotaCosts(quotas); err != nil {
+		return err
+	}
+
+	target := quotaClusterOf(cluster)
+	fmt.Printf("\nCompute nodes of cluster %s (%s, %s, %s)\n", cluster.ID(), target.Product, target.BYOC, target.BillingModel)
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"POOL", "INSTANCE TYPE", "QUOTA NAME", "NODES", "QUOTA", "COST/NODE"})
+	for _, pool := range pools {
+		nodes := strconv.Itoa(pool.Replicas)
+		if pool.Autoscaling {
+			nodes = fmt.Sprintf("%d-%d (autoscaling)", pool.Replicas, pool.MaxReplicas)
+		}
+		quotaID, cost := "<none>", ""
+		if quota, resource := matchNodeQuota(quotas, target, quotaNames[pool.InstanceType]); quota != nil {
+			quotaID, cost = quota.QuotaID, strconv.Itoa(resource.Cost)
+		}
+		table.AddRow([]string{pool.ID, pool.InstanceType, quotaNames[pool.InstanceType], nodes, quotaID, cost})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	scaleUps, err := pendingScaleUps(pools, o.machinePoolID, o.replicas, quotaNames)
+	if err != nil {
+		return err
+	}
+	warnings := evaluateScaleUps(quotas, target, scaleUps)
+	fmt.Println()
+	if len(warnings) == 0 {
+		fmt.Println("The quota of the organization covers the scale-ups of the cluster")
+		return nil
+	}
+	for _, warning := range warnings {
+		fmt.Printf("WARN: %s\n", warning)
+	}
+	return nil
+}
+
+// fetchNodeQuotas returns the quota cost of the organization, with the resources each quota covers
+func fetchNodeQuotas(ocmClient *sdk.Connection, orgID string) ([]nodeQuota, error) {
+	request := ocmClient.AccountsMgmt().V1().Organizations().Organization(orgID).QuotaCost().List().
+		Parameter("fetchRelatedResources", true).
+		Size(quotaCostPageSize)
+	response, err := request.Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the quota cost of organization %s: %w", orgID, err)
+	}
+	costs := response.Items().Slice()
+	for response.Size() >= quotaCostPageSize {
+		request.Page(response.Page() + 1)
+		response, err = request.Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the quota cost of organization %s: %w", orgID, err)
+		}
+		costs = append(costs, response.Items().Slice()...)
+	}
+
+	var quotas []nodeQuota
+	for _, cost := range costs {
+		quota := nodeQuota{QuotaID: cost.QuotaID(), Allowed: cost.Allowed(), Consumed: cost.Consumed()}
+		for _, resource := range cost.RelatedResources() {
+			quota.Resources = append(quota.Resources, quotaResource{
+				ResourceType: resource.ResourceType(),
+				ResourceName: resource.ResourceName(),
+				Product:      resource.Product(),
+				BYOC:         resource.BYOC(),
+				BillingModel: resource.BillingModel(),
+				Cost:         resource.Cost(),
+			})
+		}
+		quotas = append(quotas, quota)
+	}
+	return quotas, nil
+}
+
+// fetchComputePools returns the machine pools of a classic cluster, or the node pools of a hosted control
+// plane cluster
+func fetchComputePools(ocmClient *sdk.Connection, cluster *cmv1.Cluster) ([]computePool, error) {
+	clusterResource := ocmClient.ClustersMgmt().V1().Clusters().Cluster(cluster.ID())
+	var pools []computePool
+	if cluster.Hypershift().Enabled() {
+		response, err := clusterResource.NodePools().List().Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the node pools of cluster %s: %w", cluster.ID(), err)
+		}
+		for _, pool := range response.Items().Slice() {
+			computed := computePool{ID: pool.ID(), InstanceType: pool.AWSNodePool().InstanceType(), Replicas: pool.Replicas()}
+			if autoscaling, ok := pool.GetAutoscaling(); ok {
+				computed.Autoscaling, computed.Replicas, computed.MaxReplicas = true, autoscaling.MinReplica(), autoscaling.MaxReplica()
+			}
+			pools = append(pools, computed)
+		}
+		return pools, nil
+	}
+
+	response, err := clusterResource.MachinePools().List().Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the machine pools of cluster %s: %w", cluster.ID(), err)
+	}
+	for _, pool := range response.Items().Slice() {
+		computed := computePool{ID: pool.ID(), InstanceType: pool.InstanceType(), Replicas: pool.Replicas()}
+		if autoscaling, ok := pool.GetAutoscaling(); ok {
+			computed.Autoscaling, computed.Replicas, computed.MaxReplicas = true, autoscaling.MinReplicas(), autoscaling.MaxReplicas()
+		}
+		pools = append(pools, computed)
+	}
+	return pools, nil
+}
+
+// fetchQuotaNames returns the generic names of the machine types of the cloud provider, which the quotas
+// of the compute nodes are named by, by instance type
+func fetchQuotaNames(ocmClient *sdk.Connection, cloudProvider string) (map[string]string, error) {
+	response, err := ocmClient.ClustersMgmt().V1().MachineTypes().List().
+		Search(fmt.Sprintf("cloud_provider.id = '%s'", cloudProvider)).
+		Size(machineTypesPageSize).
+		Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the machine types of %s: %w", cloudProvider, err)
+	}
+	names := map[string]string{}
+	response.Items().Each(func(machineType *cmv1.MachineType) bool {
+		names[machineType.ID()] = machineType.GenericName()
+		return true
+	})
+	return names, nil
+}
+
+func quotaClusterOf(cluster *cmv1.Cluster) quotaCluster {
+	byoc := "rhinfra"
+	if cluster.CCS().Enabled() {
+		byoc = "byoc"
+	}
+	return quotaCluster{Product: cluster.Product().ID(), BYOC: byoc, BillingModel: string(cluster.BillingModel())}
+}
+
+// printQuotaCosts prints the quotas the organization has or consumes
+func printQuotaCosts(quotas []nodeQuota) error {
+	sorted := append([]nodeQuota{}, quotas...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].QuotaID < sorted[j].QuotaID })
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"QUOTA", "CONSUMED", "ALLOWED", "AVAILABLE"})
+	for _, quota := range sorted {
+		if quota.Allowed == 0 && quota.Consumed == 0 {
+			continue
+		}
+		table.AddRow([]string{quota.QuotaID, strconv.Itoa(quota.Consumed), strconv.Itoa(quota.Allowed), strconv.Itoa(quota.Allowed - quota.Consumed)})
+	}
+	return table.Flush()
+}
+
+// matchNodeQuota returns the quota the compute nodes of the quota name are counted in for the cluster, and
+// the resource of the quota they match, nil when no quota covers them
+func matchNodeQuota(quotas []nodeQuota, cluster quotaCluster, quotaName string) (*nodeQuota, *quotaResource) {
+	for i := range quotas {
+		for j := range quotas[i].Resources {
+			resource := &quotas[i].Resources[j]
+			if resource.ResourceType == computeNodeResourceType &&
+				matchesQuotaValue(resource.ResourceName, quotaName) &&
+				matchesQuotaValue(resource.Product, cluster.Product) &&
+				matchesQuotaValue(resource.BYOC, cluster.BYOC) &&
+				matchesQuotaValue(resource.BillingModel, cluster.BillingModel) {
+				return &quotas[i], resource
+			}
+		}
+	}
+	return nil, nil
+}
+
+func matchesQuotaValue(quotaValue string, value string) bool {
+	return quotaValue == anyQuotaValue || strings.EqualFold(quotaValue, value)
+}
+
+// pendingScaleUps returns the planned scale-up of the machine pool, if any, and the growth the autoscaling
+// of the other pools allows
+func pendingScaleUps(pools []computePool, machinePoolID string, replicas int, quotaNames map[string]string) ([]scaleUp, error) {
+	var scaleUps []scaleUp
+	found := machinePoolID == ""
+	for _, pool := range pools {
+		if pool.ID == machinePoolID {
+			found = true
+			if replicas > pool.Replicas {
+				scaleUps = append(scaleUps, scaleUp{Pool: pool.ID, InstanceType: pool.InstanceType, QuotaName: quotaNames[pool.InstanceType], Nodes: replicas - pool.Replicas, Planned: true})
+			}
+			continue
+		}
+		if pool.Autoscaling && pool.MaxReplicas > pool.Replicas {
+			scaleUps = append(scaleUps, scaleUp{Pool: pool.ID, InstanceType: pool.InstanceType, QuotaName: quotaNames[pool.InstanceType], Nodes: pool.MaxReplicas - pool.Replicas})
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("the cluster has no machine pool or node pool %s", machinePoolID)
+	}
+	return scaleUps, nil
+}
+
+// evaluateScaleUps returns a warning for each scale-up the quota doesn't cover. The scale-ups sharing a quota
+// are added up, as they draw from the same quota.
+func evaluateScaleUps(quotas []nodeQuota, cluster quotaCluster, scaleUps []scaleUp) []string {
+	var warnings []string
+	needed := map[string]int{}
+	for _, up := range scaleUps {
+		quota, resource := matchNodeQuota(quotas, cluster, up.QuotaName)
+		if quota == nil {
+			warnings = append(warnings, fmt.Sprintf("no quota of the organization covers the %s nodes (%s) of pool %s", up.InstanceType, up.QuotaName, up.Pool))
+			continue
+		}
+		// The quotas without cost, e.g. billed through a marketplace, have no limit
+		if resource.Cost == 0 {
+			continue
+		}
+		needed[quota.QuotaID] += up.Nodes * resource.Cost
+		available := quota.Allowed - quota.Consumed - needed[quota.QuotaID]
+		if available >= 0 {
+			continue
+		}
+		kind := "the autoscaling of pool " + up.Pool + " up to its maximum"
+		if up.Planned {
+			kind = "scaling pool " + up.Pool + " up"
+		}
+		warnings = append(warnings, fmt.Sprintf("%s (%d %s nodes) exceeds quota %s by %d: %d consumed of %d allowed",
+			kind, up.Nodes, up.InstanceType, quota.QuotaID, -available, quota.Consumed, quota.Allowed))
+	}
+	return warnings
+}
@@ -0,0 +1,80 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateScaleUps(t *testing.T) {
+	quotas := []nodeQuota{
+		{
+			QuotaID:  "compute.node|cpu|osd|byoc",
+			Allowed:  20,
+			Consumed: 12,
+			Resources: []quotaResource{
+				{ResourceType: "compute.node", ResourceName: "cpu", Product: "OSD", BYOC: "byoc", BillingModel: "standard", Cost: 4},
+			},
+		},
+		{
+			QuotaID:  "compute.node|gpu|any|any",
+			Allowed:  0,
+			Consumed: 0,
+			Resources: []quotaResource{
+				{ResourceType: "compute.node", ResourceName: "gpu", Product: "any", BYOC: "any", BillingModel: "any", Cost: 0},
+			},
+		},
+	}
+	cluster := quotaCluster{Product: "osd", BYOC: "byoc", BillingModel: "standard"}
+	quotaNames := map[string]string{"m5.xlarge": "cpu", "g4dn.xlarge": "gpu"}
+	pools := []computePool{
+		{ID: "worker", InstanceType: "m5.xlarge", Replicas: 3},
+		{ID: "infra", InstanceType: "m5.xlarge", Replicas: 2, Autoscaling: true, MaxReplicas: 3},
+		{ID: "gpu", InstanceType: "g4dn.xlarge", Replicas: 1, Autoscaling: true, MaxReplicas: 4},
+	}
+
+	tests := []struct {
+		name          string
+		cluster       quotaCluster
+		machinePoolID string
+		replicas      int
+		want          []string
+	}{
+		{
+			name:    "autoscaling within the quota",
+			cluster: cluster,
+		},
+		{
+			name:          "planned scale-up exceeding the quota with the autoscaling",
+			cluster:       cluster,
+			machinePoolID: "worker",
+			replicas:      5,
+			want:          []string{"the autoscaling of pool infra up to its maximum (1 m5.xlarge nodes) exceeds quota compute.node|cpu|osd|byoc by 4: 12 consumed of 20 allowed"},
+		},
+		{
+			name:          "scale-down",
+			cluster:       cluster,
+			machinePoolID: "worker",
+			replicas:      2,
+		},
+		{
+			name:    "no quota for the cluster",
+			cluster: quotaCluster{Product: "osd", BYOC: "rhinfra", BillingModel: "standard"},
+			want:    []string{"no quota of the organization covers the m5.xlarge nodes (cpu) of pool infra"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scaleUps, err := pendingScaleUps(pools, tt.machinePoolID, tt.replicas, quotaNames)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := evaluateScaleUps(quotas, tt.cluster, scaleUps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evaluateScaleUps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := pendingScaleUps(pools, "missing", 5, quotaNames); err == nil {
+		t.Error("pendingScaleUps() expected an error for an unknown pool")
+	}
+}
@@ -0,0 +1,268 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	awsv1alpha1 "github.com/openshift/aws-account-operator/api/v1alpha1"
+	hiveapiv1 "github.com/openshift/hive/apis/hive/v1"
+	hiveinternalv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/osdctl/cmd/account"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	cloudCredentialOperator          = "cloud-credential"
+	cloudCredentialOperatorNamespace = "openshift-cloud-credential-operator"
+	credentialsProvisionFailure      = "CredentialsProvisionFailure"
+)
+
+type rotateCredentialsOptions struct {
+	clusterID      string
+	awsProfile     string
+	reason         string
+	updateCcsCreds bool
+	dryRun         bool
+	timeout        time.Duration
+}
+
+func newCmdRotateCredentials() *cobra.Command {
+	ops := &rotateCredentialsOptions{}
+	rotateCredentialsCmd := &cobra.Command{
+		Use:   "rotate-credentials <cluster-id>",
+		Short: "Rotate the IAM credentials of an AWS cluster and verify the cloud-credential operator",
+		Long: `Rotate the credentials of an AWS cluster, step by step:
+  1. find the Account CR of the cluster on its hive shard
+  2. create new osdManagedAdmin (and with --ccs osdCcsAdmin) access keys and update the secrets on hive
+  3. sync the new credentials to the cluster with a SyncSet
+  4. wait for the cloud-credential operator and the CredentialsRequests of the cluster to be healthy
+
+STS clusters have no long-lived IAM user credentials: their operator roles get short-lived tokens from the
+OIDC provider, so only the health of the cloud-credential operator is checked.
+
+Use --dry-run to print the plan without changing anything. The previous access keys stay active, the
+command prints how to remove them, or how to roll back, once the cluster is healthy.`,
+		Example: `  # Print what would be rotated
+  osdctl cluster rotate-credentials <cluster-id> --reason OHSS-1234 --dry-run
+
+  # Rotate the osdManagedAdmin and osdCcsAdmin credentials of a CCS cluster
+  osdctl cluster rotate-credentials <cluster-id> --reason OHSS-1234 --ccs`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	rotateCredentialsCmd.Flags().StringVarP(&ops.awsProfile, "aws-profile", "p", "", "AWS profile used to assume the roles of the account")
+	rotateCredentialsCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this command, which requires elevation, to be run (usually an OHSS or PD ticket)")
+	rotateCredentialsCmd.Flags().BoolVar(&ops.updateCcsCreds, "ccs", false, "Also rotate the osdCcsAdmin credentials of CCS clusters. Use caution.")
+	rotateCredentialsCmd.Flags().BoolVar(&ops.dryRun, "dry-run", false, "Print the rotation plan without changing anything")
+	rotateCredentialsCmd.Flags().DurationVar(&ops.timeout, "timeout", 10*time.Minute, "How long to wait for the cloud-credential operator to be healthy after the rotation")
+	_ = rotateCredentialsCmd.MarkFlagRequired("reason")
+
+	return rotateCredentialsCmd
+}
+
+func (o *rotateCredentialsOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	o.clusterID = cluster.ID()
+	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
+		return fmt.Errorf("this command is only available for AWS clusters")
+	}
+	if cluster.Hypershift().Enabled() {
+		return fmt.Errorf("the credentials of hosted control plane clusters are managed from the management cluster, this command only supports classic clusters")
+	}
+
+	if cluster.AWS().STS().Enabled() {
+		printSTSRotationNotes(cluster)
+		if o.dryRun {
+			return nil
+		}
+		return o.verifyCloudCredentialOperator()
+	}
+
+	hive, err := utils.GetHiveCluster(cluster.ID())
+	if err != nil {
+		return err
+	}
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{corev1.AddToScheme, awsv1alpha1.AddToScheme, hiveapiv1.AddToScheme, hiveinternalv1alpha1.AddToScheme} {
+		if err := addToScheme(scheme); err != nil {
+			return err
+		}
+	}
+	hiveClient, err := k8s.NewAsBackplaneClusterAdmin(hive.ID(), client.Options{Scheme: scheme}, o.reason, fmt.Sprintf("Rotating the credentials of cluster %s", cluster.ID()))
+	if err != nil {
+		return err
+	}
+
+	accountClaim, err := k8s.GetAccountClaimFromClusterID(context.TODO(), hiveClient, cluster.ID())
+	if err != nil {
+		return err
+	}
+	if accountClaim == nil {
+		return fmt.Errorf("no AccountClaim found for cluster %s on hive %s", cluster.ID(), hive.Name())
+	}
+	accountCRName := accountClaim.Spec.AccountLink
+	if accountCRName == "" {
+		return fmt.Errorf("the AccountClaim %s/%s isn't linked to an Account", accountClaim.Namespace, accountClaim.Name)
+	}
+
+	for i, step := range rotationPlan(cluster, hive.Name(), accountCRName, o.updateCcsCreds) {
+		fmt.Printf("%d. %s\n", i+1, step)
+	}
+	if o.dryRun {
+		fmt.Println("Dry run, nothing was changed")
+		return nil
+	}
+	if !utils.ConfirmPrompt() {
+		return nil
+	}
+
+	if err := account.RotateSecret(hiveClient, accountCRName, o.awsProfile, o.updateCcsCreds); err != nil {
+		return fmt.Errorf("failed to rotate the credentials of Account %s: %w\n%s", accountCRName, err, rollbackHints(accountCRName, false))
+	}
+	if err := o.verifyCloudCredentialOperator(); err != nil {
+		return fmt.Errorf("%w\n%s", err, rollbackHints(accountCRName, true))
+	}
+	fmt.Println(cleanupHints(o.updateCcsCreds))
+	return nil
+}
+
+// rotationPlan returns the steps of the rotation of the IAM credentials of a non-STS cluster
+func rotationPlan(cluster *cmv1.Cluster, hiveName string, accountCRName string, updateCcsCreds bool) []string {
+	users := common.OSDManagedAdminIAM
+	if updateCcsCreds && cluster.CCS().Enabled() {
+		users += " and osdCcsAdmin"
+	}
+	plan := []string{
+		fmt.Sprintf("Create new %s access keys in the AWS account of Account %s/%s on hive %s", users, common.AWSAccountNamespace, accountCRName, hiveName),
+		fmt.Sprintf("Update the %s-secret secret and the aws secret of the cluster namespace on hive", accountCRName),
+		"Sync the aws secret to kube-system/aws-creds on the cluster with a temporary SyncSet",
+		fmt.Sprintf("Wait for the %s operator and the CredentialsRequests of the cluster to be healthy", cloudCredentialOperator),
+	}
+	if updateCcsCreds && !cluster.CCS().Enabled() {
+		plan = append(plan, "Skip osdCcsAdmin, the cluster isn't CCS")
+	}
+	return plan
+}
+
+func printSTSRotationNotes(cluster *cmv1.Cluster) {
+	fmt.Printf("Cluster %s uses STS: its operator roles get short-lived tokens from the OIDC provider and have no long-lived credentials to rotate.\n", cluster.ID())
+	fmt.Printf("Run 'osdctl cluster oidc-check %s' if the operator roles can't be assumed.\n", cluster.ID())
+}
+
+// rollbackHints explains how to recover from a failed rotation. The previous access keys are never deleted,
+// so the previous credentials can be put back while they are still active.
+func rollbackHints(accountCRName string, synced bool) string {
+	hints := []string{
+		"The previous access keys are still active, to roll back:",
+		fmt.Sprintf("  - put the previous keys back in %s/%s-secret and in the aws secret of the cluster namespace on hive", common.AWSAccountNamespace, accountCRName),
+	}
+	if synced {
+		hints = append(hints, "  - sync them to the cluster again, e.g. with 'osdctl account rotate-secret' or a hive resync")
+	}
+	hints = append(hints, "  - delete the new access keys with 'aws iam delete-access-key' once the previous ones work again")
+	return strings.Join(hints, "\n")
+}
+
+func cleanupHints(updateCcsCreds bool) string {
+	users := common.OSDManagedAdminIAM
+	if updateCcsCreds {
+		users += " and osdCcsAdmin"
+	}
+	return fmt.Sprintf("The previous access keys of %s are still active: delete them with 'aws iam delete-access-key' once nothing uses them anymore", users)
+}
+
+// verifyCloudCredentialOperator waits for the cloud-credential operator and the CredentialsRequests of the
+// cluster to report no problem
+func (o *rotateCredentialsOptions) verifyCloudCredentialOperator() error {
+	kubeCli, _, _, err := common.GetKubeConfigAndClient(o.clusterID)
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+
+	fmt.Printf("Waiting up to %s for the %s operator to be healthy", o.timeout, cloudCredentialOperator)
+	var problems []string
+	pollErr := wait.PollImmediate(20*time.Second, o.timeout, func() (bool, error) {
+		var err error
+		problems, err = cloudCredentialProblems(ctx, kubeCli)
+		if err != nil {
+			return false, err
+		}
+		fmt.Print(".")
+		return len(problems) == 0, nil
+	})
+	fmt.Println()
+	if err := pollErr; err != nil {
+		if len(problems) > 0 {
+			return fmt.Errorf("the %s operator isn't healthy:\n  - %s", cloudCredentialOperator, strings.Join(problems, "\n  - "))
+		}
+		return err
+	}
+	fmt.Printf("The %s operator and the CredentialsRequests are healthy\n", cloudCredentialOperator)
+	return nil
+}
+
+// cloudCredentialProblems returns the problems reported by the cloud-credential ClusterOperator and the
+// CredentialsRequests failing to provision their credentials
+func cloudCredentialProblems(ctx context.Context, kubeCli client.Client) ([]string, error) {
+	operator := &unstructured.Unstructured{}
+	operator.SetGroupVersionKind(schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "ClusterOperator"})
+	if err := kubeCli.Get(ctx, client.ObjectKey{Name: cloudCredentialOperator}, operator); err != nil {
+		return nil, fmt.Errorf("failed to get the %s ClusterOperator: %w", cloudCredentialOperator, err)
+	}
+	problems := operatorConditionProblems(operator)
+
+	requests := &unstructured.UnstructuredList{}
+	requests.SetGroupVersionKind(schema.GroupVersionKind{Group: "cloudcredential.openshift.io", Version: "v1", Kind: "CredentialsRequestList"})
+	if err := kubeCli.List(ctx, requests, client.InNamespace(cloudCredentialOperatorNamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the CredentialsRequests: %w", err)
+	}
+	return append(problems, credentialsRequestFailures(requests.Items)...), nil
+}
+
+// credentialsRequestFailures returns the CredentialsRequests which failed to provision their credentials
+func credentialsRequestFailures(requests []unstructured.Unstructured) []string {
+	var failures []string
+	for _, request := range requests {
+		conditions, _, _ := unstructured.NestedSlice(request.Object, "status", "conditions")
+		for _, condition := range conditions {
+			fields, ok := condition.(map[string]interface{})
+			if !ok || fields["type"] != credentialsProvisionFailure || fields["status"] != "True" {
+				continue
+			}
+			failure := fmt.Sprintf("CredentialsRequest %s", request.GetName())
+			if message, _ := fields["message"].(string); message != "" {
+				failure += ": " + message
+			}
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
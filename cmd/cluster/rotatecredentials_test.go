@@ -0,0 +1,44 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestOperatorConditionProblems(t *testing.T) {
+	operator := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "False", "message": "credentials are missing"},
+				map[string]interface{}{"type": "Degraded", "status": "False"},
+				map[string]interface{}{"type": "AWSEBSDriverControllerServiceControllerDegraded", "status": "True"},
+				map[string]interface{}{"type": "Progressing", "status": "True"},
+			},
+		},
+	}}
+	want := []string{"Available: credentials are missing", "AWSEBSDriverControllerServiceControllerDegraded"}
+	if got := operatorConditionProblems(operator); !reflect.DeepEqual(got, want) {
+		t.Errorf("operatorConditionProblems() = %v, want %v", got, want)
+	}
+}
+
+func TestCredentialsRequestFailures(t *testing.T) {
+	request := func(name string, conditions ...interface{}) unstructured.Unstructured {
+		object := unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"conditions": conditions},
+		}}
+		object.SetName(name)
+		return object
+	}
+	requests := []unstructured.Unstructured{
+		request("openshift-machine-api-aws"),
+		request("openshift-image-registry", map[string]interface{}{"type": credentialsProvisionFailure, "status": "True", "message": "InvalidClientTokenId"}),
+		request("openshift-ingress", map[string]interface{}{"type": credentialsProvisionFailure, "status": "False"}),
+	}
+	want := []string{"CredentialsRequest openshift-image-registry: InvalidClientTokenId"}
+	if got := credentialsRequestFailures(requests); !reflect.DeepEqual(got, want) {
+		t.Errorf("credentialsRequestFailures() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,399 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/guardduty"
+	guarddutytypes "github.com/aws/aws-sdk-go-v2/service/guardduty/types"
+	"github.com/aws/aws-sdk-go-v2/service/securityhub"
+	securityhubtypes "github.com/aws/aws-sdk-go-v2/service/securityhub/types"
+	"github.com/aws/smithy-go"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/rawdump"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	sourceGuardDuty   = "GuardDuty"
+	sourceSecurityHub = "SecurityHub"
+
+	// defaultSecurityFindings is how many findings of each source are collected by default
+	defaultSecurityFindings = 50
+	// guardDutyFindingsPageSize is the most findings GuardDuty returns at once
+	guardDutyFindingsPageSize = 50
+	// securityHubFindingsPageSize is the most findings Security Hub returns at once
+	securityHubFindingsPageSize = 100
+
+	// securityHubNotEnabledErrorCode is returned by Security Hub when the account isn't subscribed to it
+	securityHubNotEnabledErrorCode = "InvalidAccessException"
+)
+
+type securityFindingsOptions struct {
+	clusterID   string
+	awsProfile  string
+	output      string
+	maxFindings int
+}
+
+// securityFindings are the active findings of the security services of the AWS account of a cluster
+type securityFindings struct {
+	Findings []securityFinding `json:"findings"`
+	// Notes tell which sources couldn't be read, e.g. because the service isn't enabled in the account
+	Notes []string `json:"notes,omitempty"`
+}
+
+// securityFinding is an active GuardDuty finding or a failed Security Hub check
+type securityFinding struct {
+	Source string `json:"source"`
+	// Severity is CRITICAL, HIGH, MEDIUM, LOW or INFORMATIONAL
+	Severity string `json:"severity"`
+	// Score is the severity from 0 to 100, GuardDuty's from 0 to 10 being scaled
+	Score    float64   `json:"score"`
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	Resource string    `json:"resource"`
+	Count    int       `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+func newCmdSecurityFindings() *cobra.Command {
+	ops := &securityFindingsOptions{}
+	securityFindingsCmd := &cobra.Command{
+		Use:   "security-findings <cluster-id>",
+		Short: "Show the active GuardDuty findings and the failed Security Hub checks of the AWS account of a cluster",
+		Long: `Show the active GuardDuty findings and the failed Security Hub checks of the AWS account of a cluster, read
+with the jump role, the most severe first.
+
+A service that isn't enabled in the account, or in the region of the cluster, is reported and skipped.`,
+		Example: `  # Security findings of a cluster for a security incident
+  osdctl cluster security-findings <cluster-id> -p <aws-profile>`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	securityFindingsCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS profile")
+	securityFindingsCmd.Flags().StringVarP(&ops.output, "output", "o", "text", "Output format, one of text or json")
+	securityFindingsCmd.Flags().IntVar(&ops.maxFindings, "max", defaultSecurityFindings, "Maximum number of findings collected from each service")
+
+	return securityFindingsCmd
+}
+
+func (o *securityFindingsOptions) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output format '%s', expected text or json", o.output)
+	}
+	if o.maxFindings < 1 {
+		return fmt.Errorf("--max must be at least 1")
+	}
+	return nil
+}
+
+func (o *securityFindingsOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
+		return fmt.Errorf("this command is only available for AWS clusters")
+	}
+
+	findings, err := GetSecurityFindingsForCluster(o.awsProfile, cluster.ID(), o.maxFindings)
+	if err != nil {
+		return err
+	}
+	if o.output == "json" {
+		out, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	writeSecurityFindings(os.Stdout, findings)
+	return nil
+}
+
+// GetSecurityFindingsForCluster collects the security findings of the AWS account of the cluster with the jump role
+func GetSecurityFindingsForCluster(awsProfile string, clusterID string, maxFindings int) (*securityFindings, error) {
+	awsJumpClient, err := osdCloud.GenerateAWSClientForCluster(awsProfile, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return collectSecurityFindings(awsJumpClient, maxFindings), nil
+}
+
+// collectSecurityFindings collects the findings of each service, a service failing only adds a note
+func collectSecurityFindings(awsClient awsprovider.Client, maxFindings int) *securityFindings {
+	findings := &securityFindings{Findings: []securityFinding{}}
+
+	guardDutyFindings, note, err := fetchGuardDutyFindings(awsClient, maxFindings)
+	switch {
+	case err != nil:
+		findings.Notes = append(findings.Notes, fmt.Sprintf("failed to get the GuardDuty findings: %v", err))
+	case note != "":
+		findings.Notes = append(findings.Notes, note)
+	}
+	findings.Findings = append(findings.Findings, guardDutyFindings...)
+
+	securityHubFindings, note, err := fetchSecurityHubFindings(awsClient, maxFindings)
+	switch {
+	case err != nil:
+		findings.Notes = append(findings.Notes, fmt.Sprintf("failed to get the Security Hub findings: %v", err))
+	case note != "":
+		findings.Notes = append(findings.Notes, note)
+	}
+	findings.Findings = append(findings.Findings, securityHubFindings...)
+
+	sortSecurityFindings(findings.Findings)
+	return findings
+}
+
+// fetchGuardDutyFindings returns the findings of the GuardDuty detectors of the region which aren't archived, the
+// most severe first. The note tells when GuardDuty isn't enabled.
+func fetchGuardDutyFindings(awsClient awsprovider.Client, maxFindings int) ([]securityFinding, string, error) {
+	detectors, err := awsClient.ListDetectors(&guardduty.ListDetectorsInput{})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(detectors.DetectorIds) == 0 {
+		return nil, "GuardDuty isn't enabled in the region of the cluster", nil
+	}
+
+	var findings []securityFinding
+	for _, detectorID := range detectors.DetectorIds {
+		input := &guardduty.ListFindingsInput{
+			DetectorId: aws.String(detectorID),
+			FindingCriteria: &guarddutytypes.FindingCriteria{
+				Criterion: map[string]guarddutytypes.Condition{
+					"service.archived": {Equals: []string{"false"}},
+				},
+			},
+			SortCriteria: &guarddutytypes.SortCriteria{AttributeName: aws.String("severity"), OrderBy: guarddutytypes.OrderByDesc},
+			MaxResults:   aws.Int32(guardDutyFindingsPageSize),
+		}
+		for len(findings) < maxFindings {
+			page, err := awsClient.ListFindings(input)
+			if err != nil {
+				return nil, "", err
+			}
+			ids := page.FindingIds
+			if remaining := maxFindings - len(findings); len(ids) > remaining {
+				ids = ids[:remaining]
+			}
+			if len(ids) > 0 {
+				output, err := awsClient.GetFindings(&guardduty.GetFindingsInput{DetectorId: aws.String(detectorID), FindingIds: ids})
+				if err != nil {
+					return nil, "", err
+				}
+				if err := rawdump.WriteJSON("security_findings", "GuardDutyGetFindings", output); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to save the raw GuardDuty findings: %v\n", err)
+				}
+				for _, finding := range output.Findings {
+					findings = append(findings, guardDutyFinding(finding))
+				}
+			}
+			if page.NextToken == nil || aws.ToString(page.NextToken) == "" {
+				break
+			}
+			input.NextToken = page.NextToken
+		}
+	}
+	return findings, "", nil
+}
+
+// guardDutyFinding converts a GuardDuty finding, whose severity goes from 0 to 10
+func guardDutyFinding(finding guarddutytypes.Finding) securityFinding {
+	score := aws.ToFloat64(finding.Severity) * 10
+	converted := securityFinding{
+		Source:   sourceGuardDuty,
+		Severity: severityLabel(score),
+		Score:    score,
+		Type:     aws.ToString(finding.Type),
+		Title:    aws.ToString(finding.Title),
+		Count:    1,
+		LastSeen: parseFindingTime(aws.ToString(finding.UpdatedAt)),
+	}
+	if finding.Resource != nil {
+		converted.Resource = aws.ToString(finding.Resource.ResourceType)
+		if finding.Resource.InstanceDetails != nil && finding.Resource.InstanceDetails.InstanceId != nil {
+			converted.Resource = aws.ToString(finding.Resource.InstanceDetails.InstanceId)
+		} else if finding.Resource.AccessKeyDetails != nil && finding.Resource.AccessKeyDetails.UserName != nil {
+			converted.Resource = aws.ToString(finding.Resource.AccessKeyDetails.UserName)
+		}
+	}
+	if finding.Service != nil {
+		if finding.Service.Count != nil {
+			converted.Count = int(aws.ToInt32(finding.Service.Count))
+		}
+		if lastSeen := parseFindingTime(aws.ToString(finding.Service.EventLastSeen)); !lastSeen.IsZero() {
+			converted.LastSeen = lastSeen
+		}
+	}
+	return converted
+}
+
+// fetchSecurityHubFindings returns the active failed checks of Security Hub which weren't resolved or suppressed,
+// the most severe first. The note tells when Security Hub isn't enabled.
+func fetchSecurityHubFindings(awsClient awsprovider.Client, maxFindings int) ([]securityFinding, string, error) {
+	equals := func(values ...string) []securityhubtypes.StringFilter {
+		filters := make([]securityhubtypes.StringFilter, 0, len(values))
+		for _, value := range values {
+			filters = append(filters, securityhubtypes.StringFilter{Comparison: securityhubtypes.StringFilterComparisonEquals, Value: aws.String(value)})
+		}
+		return filters
+	}
+	input := &securityhub.GetFindingsInput{
+		Filters: &securityhubtypes.AwsSecurityFindingFilters{
+			RecordState:      equals("ACTIVE"),
+			ComplianceStatus: equals("FAILED"),
+			WorkflowStatus:   equals("NEW", "NOTIFIED"),
+		},
+		SortCriteria: []securityhubtypes.SortCriterion{{Field: aws.String("SeverityNormalized"), SortOrder: securityhubtypes.SortOrderDescending}},
+	}
+
+	var findings []securityFinding
+	for len(findings) < maxFindings {
+		pageSize := maxFindings - len(findings)
+		if pageSize > securityHubFindingsPageSize {
+			pageSize = securityHubFindingsPageSize
+		}
+		input.MaxResults = aws.Int32(int32(pageSize))
+		output, err := awsClient.GetSecurityHubFindings(input)
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == securityHubNotEnabledErrorCode {
+			return nil, "Security Hub isn't enabled in the region of the cluster", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if err := rawdump.WriteJSON("security_findings", "SecurityHubGetFindings", output); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save the raw Security Hub findings: %v\n", err)
+		}
+		for _, finding := range output.Findings {
+			findings = append(findings, securityHubFinding(finding))
+		}
+		if output.NextToken == nil || aws.ToString(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return findings, "", nil
+}
+
+// securityHubFinding converts a Security Hub finding, whose normalized severity goes from 0 to 100
+func securityHubFinding(finding securityhubtypes.AwsSecurityFinding) securityFinding {
+	converted := securityFinding{
+		Source:   sourceSecurityHub,
+		Type:     aws.ToString(finding.GeneratorId),
+		Title:    aws.ToString(finding.Title),
+		Count:    1,
+		LastSeen: parseFindingTime(aws.ToString(finding.UpdatedAt)),
+	}
+	if finding.Severity != nil {
+		converted.Score = float64(aws.ToInt32(finding.Severity.Normalized))
+		converted.Severity = string(finding.Severity.Label)
+	}
+	if converted.Severity == "" {
+		converted.Severity = severityLabel(converted.Score)
+	}
+	if len(finding.Types) > 0 {
+		converted.Type = finding.Types[0]
+	}
+	if len(finding.Resources) > 0 {
+		converted.Resource = aws.ToString(finding.Resources[0].Id)
+	}
+	return converted
+}
+
+// severityLabel returns the label of a severity from 0 to 100, with the ranges of Security Hub
+func severityLabel(score float64) string {
+	switch {
+	case score >= 90:
+		return "CRITICAL"
+	case score >= 70:
+		return "HIGH"
+	case score >= 40:
+		return "MEDIUM"
+	case score >= 1:
+		return "LOW"
+	default:
+		return "INFORMATIONAL"
+	}
+}
+
+// parseFindingTime parses the ISO 8601 timestamps of the findings, a zero time when it can't be parsed
+func parseFindingTime(value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed.UTC()
+}
+
+// sortSecurityFindings sorts the findings the most severe first, then the most recent first
+func sortSecurityFindings(findings []securityFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Score != findings[j].Score {
+			return findings[i].Score > findings[j].Score
+		}
+		return findings[i].LastSeen.After(findings[j].LastSeen)
+	})
+}
+
+func printSecurityFindings(data *contextData) {
+	writeSecurityFindings(os.Stdout, data.SecurityFindings)
+}
+
+func writeSecurityFindings(w io.Writer, findings *securityFindings) {
+	var name string = "Security Findings (GuardDuty and Security Hub)"
+	fmt.Fprintln(w, delimiter+name)
+	if findings == nil {
+		fmt.Fprintln(w, "Not collected")
+		return
+	}
+	for _, note := range findings.Notes {
+		fmt.Fprintf(w, "Note: %s\n", note)
+	}
+	if len(findings.Findings) == 0 {
+		fmt.Fprintln(w, "None")
+		return
+	}
+
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{"SEVERITY", "SOURCE", "TYPE", "RESOURCE", "COUNT", "LAST SEEN", "TITLE"})
+	for _, finding := range findings.Findings {
+		lastSeen := ""
+		if !finding.LastSeen.IsZero() {
+			lastSeen = finding.LastSeen.Format(time.RFC3339)
+		}
+		table.AddRow([]string{finding.Severity, finding.Source, finding.Type, finding.Resource, fmt.Sprint(finding.Count), lastSeen, finding.Title})
+	}
+	// Add empty row for readability
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing %s: %v\n", name, err)
+	}
+}
@@ -0,0 +1,125 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/guardduty"
+	guarddutytypes "github.com/aws/aws-sdk-go-v2/service/guardduty/types"
+	"github.com/aws/aws-sdk-go-v2/service/securityhub"
+	securityhubtypes "github.com/aws/aws-sdk-go-v2/service/securityhub/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+
+	"github.com/openshift/osdctl/pkg/provider/aws/mock"
+)
+
+func TestCollectSecurityFindings(t *testing.T) {
+	guardDutyFindings := &guardduty.GetFindingsOutput{Findings: []guarddutytypes.Finding{{
+		Severity:  awsSdk.Float64(8),
+		Type:      awsSdk.String("CryptoCurrency:EC2/BitcoinTool.B!DNS"),
+		Title:     awsSdk.String("EC2 instance is querying a domain name associated with Bitcoin-related activity"),
+		UpdatedAt: awsSdk.String("2024-05-01T10:00:00.000Z"),
+		Resource: &guarddutytypes.Resource{
+			ResourceType:    awsSdk.String("Instance"),
+			InstanceDetails: &guarddutytypes.InstanceDetails{InstanceId: awsSdk.String("i-0123456789abcdef0")},
+		},
+		Service: &guarddutytypes.Service{Count: awsSdk.Int32(12), EventLastSeen: awsSdk.String("2024-05-01T11:00:00.000Z")},
+	}}}
+	securityHubFindings := &securityhub.GetFindingsOutput{Findings: []securityhubtypes.AwsSecurityFinding{
+		{
+			GeneratorId: awsSdk.String("aws-foundational-security-best-practices/v/1.0.0/EC2.19"),
+			Title:       awsSdk.String("Security groups should not allow unrestricted access to ports with high risk"),
+			Severity:    &securityhubtypes.Severity{Label: securityhubtypes.SeverityLabelCritical, Normalized: awsSdk.Int32(90)},
+			Resources:   []securityhubtypes.Resource{{Id: awsSdk.String("arn:aws:ec2:us-east-1:111111111111:security-group/sg-0123")}},
+			UpdatedAt:   awsSdk.String("2024-05-01T09:00:00Z"),
+		},
+		{
+			GeneratorId: awsSdk.String("aws-foundational-security-best-practices/v/1.0.0/S3.8"),
+			Title:       awsSdk.String("S3 general purpose buckets should block public access"),
+			Severity:    &securityhubtypes.Severity{Label: securityhubtypes.SeverityLabelHigh, Normalized: awsSdk.Int32(70)},
+			UpdatedAt:   awsSdk.String("2024-05-01T12:00:00Z"),
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		setup     func(client *mock.MockClient)
+		wantTypes []string
+		wantNotes int
+	}{
+		{
+			name: "findings of both services, the most severe first",
+			setup: func(client *mock.MockClient) {
+				client.EXPECT().ListDetectors(gomock.Any()).Return(&guardduty.ListDetectorsOutput{DetectorIds: []string{"detector"}}, nil)
+				client.EXPECT().ListFindings(gomock.Any()).Return(&guardduty.ListFindingsOutput{FindingIds: []string{"finding"}}, nil)
+				client.EXPECT().GetFindings(gomock.Any()).Return(guardDutyFindings, nil)
+				client.EXPECT().GetSecurityHubFindings(gomock.Any()).Return(securityHubFindings, nil)
+			},
+			wantTypes: []string{
+				"aws-foundational-security-best-practices/v/1.0.0/EC2.19",
+				"CryptoCurrency:EC2/BitcoinTool.B!DNS",
+				"aws-foundational-security-best-practices/v/1.0.0/S3.8",
+			},
+		},
+		{
+			name: "services not enabled",
+			setup: func(client *mock.MockClient) {
+				client.EXPECT().ListDetectors(gomock.Any()).Return(&guardduty.ListDetectorsOutput{}, nil)
+				client.EXPECT().GetSecurityHubFindings(gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: securityHubNotEnabledErrorCode})
+			},
+			wantNotes: 2,
+		},
+		{
+			name: "a failing service doesn't hide the other",
+			setup: func(client *mock.MockClient) {
+				client.EXPECT().ListDetectors(gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "AccessDeniedException"})
+				client.EXPECT().GetSecurityHubFindings(gomock.Any()).Return(securityHubFindings, nil)
+			},
+			wantTypes: []string{
+				"aws-foundational-security-best-practices/v/1.0.0/EC2.19",
+				"aws-foundational-security-best-practices/v/1.0.0/S3.8",
+			},
+			wantNotes: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := mock.NewMockClient(gomock.NewController(t))
+			tt.setup(client)
+
+			got := collectSecurityFindings(client, defaultSecurityFindings)
+			if len(got.Notes) != tt.wantNotes {
+				t.Errorf("collectSecurityFindings() notes = %v, want %d", got.Notes, tt.wantNotes)
+			}
+			if len(got.Findings) != len(tt.wantTypes) {
+				t.Fatalf("collectSecurityFindings() = %+v, want %d findings", got.Findings, len(tt.wantTypes))
+			}
+			for i, finding := range got.Findings {
+				if finding.Type != tt.wantTypes[i] {
+					t.Errorf("finding %d type = %s, want %s", i, finding.Type, tt.wantTypes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGuardDutyFinding(t *testing.T) {
+	got := guardDutyFinding(guarddutytypes.Finding{
+		Severity:  awsSdk.Float64(5),
+		UpdatedAt: awsSdk.String("2024-05-01T10:00:00.000Z"),
+		Resource:  &guarddutytypes.Resource{ResourceType: awsSdk.String("AccessKey"), AccessKeyDetails: &guarddutytypes.AccessKeyDetails{UserName: awsSdk.String("osdManagedAdmin")}},
+	})
+	want := securityFinding{
+		Source:   sourceGuardDuty,
+		Severity: "MEDIUM",
+		Score:    50,
+		Resource: "osdManagedAdmin",
+		Count:    1,
+		LastSeen: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if got != want {
+		t.Errorf("guardDutyFinding() = %+v, want %+v", got, want)
+	}
+}
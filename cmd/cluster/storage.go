@@ -0,0 +1,523 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	awsEBSCSIDriver = "ebs.csi.aws.com"
+	// cloudVolumeMissing is the cloud state of the volumes of PVs that don't exist in the cloud account
+	cloudVolumeMissing = "missing"
+)
+
+type storageOptions struct {
+	clusterID  string
+	awsProfile string
+	reason     string
+	threshold  int
+	noCloud    bool
+}
+
+// kubeletSummary is the part of the stats summary of a kubelet holding the usage of the volumes of the pods
+type kubeletSummary struct {
+	Pods []struct {
+		Volumes []struct {
+			Name          string  `json:"name"`
+			CapacityBytes *uint64 `json:"capacityBytes"`
+			UsedBytes     *uint64 `json:"usedBytes"`
+			PVCRef        *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// pvcUsage is the disk usage of a PVC as seen by the kubelet mounting it
+type pvcUsage struct {
+	Namespace     string
+	Name          string
+	UsedBytes     uint64
+	CapacityBytes uint64
+}
+
+func (u pvcUsage) percent() float64 {
+	if u.CapacityBytes == 0 {
+		return 0
+	}
+	return float64(u.UsedBytes) * 100 / float64(u.CapacityBytes)
+}
+
+// csiDriverStatus is the health of a CSI driver: where it's registered and what its operator reports
+type csiDriverStatus struct {
+	Driver     string
+	Registered int
+	Nodes      int
+	Problems   []string
+}
+
+func (s csiDriverStatus) healthy() bool {
+	return s.Registered == s.Nodes && len(s.Problems) == 0
+}
+
+func newCmdStorage() *cobra.Command {
+	ops := &storageOptions{}
+	storageCmd := &cobra.Command{
+		Use:   "storage <cluster-id>",
+		Short: "Report the PVCs nearing capacity, the unhealthy PVs and the health of the CSI drivers",
+		Long: `Report the storage problems of a cluster in one go:
+  - the PVCs whose usage, read from the kubelets, is above the threshold
+  - the PVs in the Released or Failed phase
+  - the CSI drivers not registered on every node or reported degraded by their operator
+  - on AWS, the state of the EBS volumes backing the PVs, and the volumes of the cluster without a PV
+
+Reading the usage from the kubelets may require elevation, pass --reason when it's denied.`,
+		Example: `  # Storage report of a cluster, flagging the PVCs above 90%
+  osdctl cluster storage <cluster-id> --threshold 90
+
+  # Same, elevating to read the usage from the kubelets
+  osdctl cluster storage <cluster-id> --reason OHSS-1234`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	storageCmd.Flags().IntVar(&ops.threshold, "threshold", 80, "Usage, in percent, above which a PVC is reported")
+	storageCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS profile")
+	storageCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for elevating, usually an OHSS or PD ticket, when reading the usage from the kubelets is denied")
+	storageCmd.Flags().BoolVar(&ops.noCloud, "no-cloud", false, "Don't correlate the PVs with the volumes of the cloud provider")
+
+	return storageCmd
+}
+
+func (o *storageOptions) validate() error {
+	if o.threshold < 0 || o.threshold > 100 {
+		return fmt.Errorf("--threshold must be between 0 and 100")
+	}
+	return nil
+}
+
+func (o *storageOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	var elevationReasons []string
+	if o.reason != "" {
+		elevationReasons = []string{o.reason, "Reading the storage usage of the cluster"}
+	}
+	kubeCli, _, clientset, err := common.GetKubeConfigAndClient(cluster.ID(), elevationReasons...)
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list the nodes: %w", err)
+	}
+	pvs, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list the persistent volumes: %w", err)
+	}
+
+	fmt.Printf("%sPVCs above %d%% of their capacity\n", delimiter, o.threshold)
+	usages := fetchPVCUsage(ctx, clientset, nodes.Items)
+	printPVCUsage(pvcsNearCapacity(usages, o.threshold), pvcVolumes(pvs.Items))
+
+	var cloudVolumes map[string]ec2types.Volume
+	if !o.noCloud && strings.ToUpper(cluster.CloudProvider().ID()) == "AWS" {
+		awsClient, err := osdCloud.GenerateAWSClientForCluster(o.awsProfile, cluster.ID())
+		if err != nil {
+			return err
+		}
+		cloudVolumes, err = fetchClusterVolumes(awsClient, cluster.InfraID())
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(delimiter + "PVs in the Released or Failed phase")
+	printUnhealthyPersistentVolumes(unhealthyPersistentVolumes(pvs.Items), cloudVolumes)
+
+	fmt.Println()
+	fmt.Println(delimiter + "CSI drivers")
+	statuses, err := fetchCSIDriverStatuses(ctx, kubeCli, clientset, len(nodes.Items))
+	if err != nil {
+		return err
+	}
+	printCSIDriverStatuses(statuses)
+
+	if cloudVolumes != nil {
+		fmt.Println()
+		fmt.Println(delimiter + "EBS volumes out of sync with the PVs")
+		printCloudVolumeMismatches(cloudVolumeMismatches(pvs.Items, cloudVolumes))
+	}
+	return nil
+}
+
+// fetchPVCUsage reads the usage of the PVCs from the stats summary of the kubelet of every node, the nodes
+// whose kubelet can't be read are skipped with a warning
+func fetchPVCUsage(ctx context.Context, clientset kubernetes.Interface, nodes []corev1.Node) []pvcUsage {
+	var usages []pvcUsage
+	for _, node := range nodes {
+		raw, err := clientset.CoreV1().RESTClient().Get().
+			Resource("nodes").Name(node.Name).SubResource("proxy").Suffix("stats/summary").
+			DoRaw(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping the volumes of node %s, failed to read its kubelet stats: %v\n", node.Name, err)
+			continue
+		}
+		nodeUsages, err := parseKubeletSummary(raw)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping the volumes of node %s: %v\n", node.Name, err)
+			continue
+		}
+		usages = append(usages, nodeUsages...)
+	}
+	return usages
+}
+
+// parseKubeletSummary returns the usage of the PVCs mounted by the pods of a kubelet stats summary
+func parseKubeletSummary(raw []byte) ([]pvcUsage, error) {
+	var summary kubeletSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse the kubelet stats summary: %w", err)
+	}
+	var usages []pvcUsage
+	for _, pod := range summary.Pods {
+		for _, volume := range pod.Volumes {
+			if volume.PVCRef == nil || volume.CapacityBytes == nil || volume.UsedBytes == nil {
+				continue
+			}
+			usages = append(usages, pvcUsage{
+				Namespace:     volume.PVCRef.Namespace,
+				Name:          volume.PVCRef.Name,
+				UsedBytes:     *volume.UsedBytes,
+				CapacityBytes: *volume.CapacityBytes,
+			})
+		}
+	}
+	return usages, nil
+}
+
+// pvcsNearCapacity returns the PVCs used above the threshold, fullest first. A PVC mounted by several pods
+// is only reported once.
+func pvcsNearCapacity(usages []pvcUsage, threshold int) []pvcUsage {
+	seen := map[string]bool{}
+	var near []pvcUsage
+	for _, usage := range usages {
+		key := usage.Namespace + "/" + usage.Name
+		if seen[key] || usage.percent() < float64(threshold) {
+			continue
+		}
+		seen[key] = true
+		near = append(near, usage)
+	}
+	sort.SliceStable(near, func(i, j int) bool {
+		if near[i].percent() != near[j].percent() {
+			return near[i].percent() > near[j].percent()
+		}
+		return near[i].Namespace+"/"+near[i].Name < near[j].Namespace+"/"+near[j].Name
+	})
+	return near
+}
+
+// pvcVolumes maps the PVCs, as namespace/name, to the PVs they're bound to
+func pvcVolumes(pvs []corev1.PersistentVolume) map[string]string {
+	volumes := map[string]string{}
+	for _, pv := range pvs {
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			volumes[ref.Namespace+"/"+ref.Name] = pv.Name
+		}
+	}
+	return volumes
+}
+
+func unhealthyPersistentVolumes(pvs []corev1.PersistentVolume) []corev1.PersistentVolume {
+	var unhealthy []corev1.PersistentVolume
+	for _, pv := range pvs {
+		if pv.Status.Phase == corev1.VolumeReleased || pv.Status.Phase == corev1.VolumeFailed {
+			unhealthy = append(unhealthy, pv)
+		}
+	}
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return unhealthy[i].Name < unhealthy[j].Name
+	})
+	return unhealthy
+}
+
+// ebsVolumeID returns the ID of the EBS volume of the PV, empty when it isn't backed by EBS
+func ebsVolumeID(pv corev1.PersistentVolume) string {
+	if csi := pv.Spec.CSI; csi != nil && csi.Driver == awsEBSCSIDriver {
+		return csi.VolumeHandle
+	}
+	// In-tree volumes are referenced as aws://<zone>/<volume-id> or just <volume-id>
+	if ebs := pv.Spec.AWSElasticBlockStore; ebs != nil {
+		return ebs.VolumeID[strings.LastIndex(ebs.VolumeID, "/")+1:]
+	}
+	return ""
+}
+
+// fetchClusterVolumes returns the EBS volumes tagged for the cluster by ID
+func fetchClusterVolumes(awsClient awsprovider.Client, infraID string) (map[string]ec2types.Volume, error) {
+	input := &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("tag-key"), Values: []string{"kubernetes.io/cluster/" + infraID}}},
+	}
+	volumes := map[string]ec2types.Volume{}
+	for {
+		output, err := awsClient.DescribeVolumes(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe the volumes of the cluster: %w", err)
+		}
+		for _, volume := range output.Volumes {
+			volumes[aws.ToString(volume.VolumeId)] = volume
+		}
+		if output.NextToken == nil {
+			return volumes, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// cloudVolumeState returns the state of the EBS volume of the PV, empty when it isn't backed by EBS
+func cloudVolumeState(pv corev1.PersistentVolume, cloudVolumes map[string]ec2types.Volume) string {
+	volumeID := ebsVolumeID(pv)
+	if volumeID == "" {
+		return ""
+	}
+	volume, ok := cloudVolumes[volumeID]
+	if !ok {
+		return cloudVolumeMissing
+	}
+	return string(volume.State)
+}
+
+// cloudVolumeMismatch is an EBS volume whose state doesn't match the PVs of the cluster
+type cloudVolumeMismatch struct {
+	VolumeID string
+	PV       string
+	State    string
+	Problem  string
+}
+
+// cloudVolumeMismatches returns the bound PVs whose EBS volume is missing or in error, and the available
+// volumes of the cluster which no PV refers to
+func cloudVolumeMismatches(pvs []corev1.PersistentVolume, cloudVolumes map[string]ec2types.Volume) []cloudVolumeMismatch {
+	var mismatches []cloudVolumeMismatch
+	referenced := map[string]bool{}
+	for _, pv := range pvs {
+		volumeID := ebsVolumeID(pv)
+		if volumeID == "" {
+			continue
+		}
+		referenced[volumeID] = true
+		if pv.Status.Phase != corev1.VolumeBound {
+			continue
+		}
+		switch state := cloudVolumeState(pv, cloudVolumes); state {
+		case cloudVolumeMissing:
+			mismatches = append(mismatches, cloudVolumeMismatch{VolumeID: volumeID, PV: pv.Name, State: state, Problem: "the volume of a bound PV doesn't exist"})
+		case string(ec2types.VolumeStateError):
+			mismatches = append(mismatches, cloudVolumeMismatch{VolumeID: volumeID, PV: pv.Name, State: state, Problem: "the volume of a bound PV is in error"})
+		}
+	}
+	for volumeID, volume := range cloudVolumes {
+		if !referenced[volumeID] && volume.State == ec2types.VolumeStateAvailable {
+			mismatches = append(mismatches, cloudVolumeMismatch{VolumeID: volumeID, PV: "-", State: string(volume.State), Problem: "no PV uses this volume"})
+		}
+	}
+	sort.SliceStable(mismatches, func(i, j int) bool {
+		return mismatches[i].VolumeID < mismatches[j].VolumeID
+	})
+	return mismatches
+}
+
+// fetchCSIDriverStatuses returns the health of the CSI drivers of the cluster, sorted by name
+func fetchCSIDriverStatuses(ctx context.Context, kubeCli client.Client, clientset kubernetes.Interface, nodeCount int) ([]csiDriverStatus, error) {
+	drivers, err := clientset.StorageV1().CSIDrivers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the CSI drivers: %w", err)
+	}
+	csiNodes, err := clientset.StorageV1().CSINodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the CSI nodes: %w", err)
+	}
+	statuses := csiDriverStatuses(drivers.Items, csiNodes.Items, nodeCount)
+	for i := range statuses {
+		// Only the drivers managed by the storage operator have a ClusterCSIDriver
+		operator := &unstructured.Unstructured{}
+		operator.SetGroupVersionKind(schema.GroupVersionKind{Group: "operator.openshift.io", Version: "v1", Kind: "ClusterCSIDriver"})
+		if err := kubeCli.Get(ctx, client.ObjectKey{Name: statuses[i].Driver}, operator); err != nil {
+			continue
+		}
+		statuses[i].Problems = operatorConditionProblems(operator)
+	}
+	return statuses, nil
+}
+
+// csiDriverStatuses counts the nodes every driver is registered on
+func csiDriverStatuses(drivers []storagev1.CSIDriver, csiNodes []storagev1.CSINode, nodeCount int) []csiDriverStatus {
+	registered := map[string]int{}
+	for _, csiNode := range csiNodes {
+		for _, driver := range csiNode.Spec.Drivers {
+			registered[driver.Name]++
+		}
+	}
+	statuses := make([]csiDriverStatus, 0, len(drivers))
+	for _, driver := range drivers {
+		statuses = append(statuses, csiDriverStatus{Driver: driver.Name, Registered: registered[driver.Name], Nodes: nodeCount})
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Driver < statuses[j].Driver
+	})
+	return statuses
+}
+
+// operatorConditionProblems returns the conditions of an operator resource, e.g. a ClusterCSIDriver or a
+// ClusterOperator, reporting a problem: the degraded ones and the unavailable ones
+func operatorConditionProblems(operator *unstructured.Unstructured) []string {
+	conditions, _, _ := unstructured.NestedSlice(operator.Object, "status", "conditions")
+	var problems []string
+	for _, condition := range conditions {
+		fields, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _ := fields["type"].(string)
+		status, _ := fields["status"].(string)
+		if (strings.HasSuffix(conditionType, "Degraded") && status == string(metav1.ConditionTrue)) ||
+			(strings.HasSuffix(conditionType, "Available") && status == string(metav1.ConditionFalse)) {
+			problem := conditionType
+			if message, _ := fields["message"].(string); message != "" {
+				problem += ": " + message
+			}
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+func formatBytes(bytes uint64) string {
+	const gib = 1 << 30
+	if bytes >= gib {
+		return fmt.Sprintf("%.1fGiB", float64(bytes)/gib)
+	}
+	return fmt.Sprintf("%.1fMiB", float64(bytes)/(1<<20))
+}
+
+func printPVCUsage(usages []pvcUsage, volumes map[string]string) {
+	if len(usages) == 0 {
+		fmt.Println("None")
+		return
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"NAMESPACE", "PVC", "PV", "USED", "CAPACITY", "USAGE"})
+	for _, usage := range usages {
+		table.AddRow([]string{
+			usage.Namespace,
+			usage.Name,
+			volumes[usage.Namespace+"/"+usage.Name],
+			formatBytes(usage.UsedBytes),
+			formatBytes(usage.CapacityBytes),
+			fmt.Sprintf("%.0f%%", usage.percent()),
+		})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the PVCs: %v\n", err)
+	}
+}
+
+func printUnhealthyPersistentVolumes(pvs []corev1.PersistentVolume, cloudVolumes map[string]ec2types.Volume) {
+	if len(pvs) == 0 {
+		fmt.Println("None")
+		return
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"PV", "PHASE", "CLAIM", "RECLAIM POLICY", "VOLUME", "CLOUD STATE"})
+	for _, pv := range pvs {
+		claim := "-"
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			claim = ref.Namespace + "/" + ref.Name
+		}
+		cloudState := "-"
+		if cloudVolumes != nil {
+			if state := cloudVolumeState(pv, cloudVolumes); state != "" {
+				cloudState = state
+			}
+		}
+		table.AddRow([]string{pv.Name, string(pv.Status.Phase), claim, string(pv.Spec.PersistentVolumeReclaimPolicy), ebsVolumeID(pv), cloudState})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the PVs: %v\n", err)
+	}
+}
+
+func printCSIDriverStatuses(statuses []csiDriverStatus) {
+	if len(statuses) == 0 {
+		fmt.Println("None")
+		return
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"DRIVER", "NODES", "HEALTHY", "PROBLEMS"})
+	for _, status := range statuses {
+		problems := strings.Join(status.Problems, "; ")
+		if problems == "" {
+			problems = "-"
+		}
+		table.AddRow([]string{status.Driver, fmt.Sprintf("%d/%d", status.Registered, status.Nodes), fmt.Sprint(status.healthy()), problems})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the CSI drivers: %v\n", err)
+	}
+}
+
+func printCloudVolumeMismatches(mismatches []cloudVolumeMismatch) {
+	if len(mismatches) == 0 {
+		fmt.Println("None")
+		return
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"VOLUME", "PV", "STATE", "PROBLEM"})
+	for _, mismatch := range mismatches {
+		table.AddRow([]string{mismatch.VolumeID, mismatch.PV, mismatch.State, mismatch.Problem})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the volumes: %v\n", err)
+	}
+}
@@ -0,0 +1,105 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseKubeletSummary(t *testing.T) {
+	raw := []byte(`{"pods": [
+		{"volume": [
+			{"name": "data", "capacityBytes": 100, "usedBytes": 95, "pvcRef": {"name": "prometheus-data", "namespace": "openshift-monitoring"}},
+			{"name": "kube-api-access", "capacityBytes": 100, "usedBytes": 1}
+		]},
+		{"volume": [
+			{"name": "data", "capacityBytes": 100, "usedBytes": 95, "pvcRef": {"name": "prometheus-data", "namespace": "openshift-monitoring"}},
+			{"name": "logs", "capacityBytes": 100, "usedBytes": 50, "pvcRef": {"name": "logs", "namespace": "app"}}
+		]}
+	]}`)
+	usages, err := parseKubeletSummary(raw)
+	if err != nil {
+		t.Fatalf("parseKubeletSummary() error = %v", err)
+	}
+	if len(usages) != 3 {
+		t.Fatalf("parseKubeletSummary() = %v, want the 3 volumes with a PVC", usages)
+	}
+
+	near := pvcsNearCapacity(usages, 80)
+	want := []pvcUsage{{Namespace: "openshift-monitoring", Name: "prometheus-data", UsedBytes: 95, CapacityBytes: 100}}
+	if !reflect.DeepEqual(near, want) {
+		t.Errorf("pvcsNearCapacity() = %v, want %v", near, want)
+	}
+
+	if _, err := parseKubeletSummary([]byte("not json")); err == nil {
+		t.Errorf("parseKubeletSummary() expected an error")
+	}
+}
+
+func TestEBSVolumeID(t *testing.T) {
+	tests := []struct {
+		name   string
+		source corev1.PersistentVolumeSource
+		want   string
+	}{
+		{
+			name:   "CSI volume",
+			source: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: awsEBSCSIDriver, VolumeHandle: "vol-1"}},
+			want:   "vol-1",
+		},
+		{
+			name:   "in-tree volume",
+			source: corev1.PersistentVolumeSource{AWSElasticBlockStore: &corev1.AWSElasticBlockStoreVolumeSource{VolumeID: "aws://us-east-1a/vol-2"}},
+			want:   "vol-2",
+		},
+		{
+			name:   "other CSI driver",
+			source: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: "efs.csi.aws.com", VolumeHandle: "fs-1"}},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pv := corev1.PersistentVolume{Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: tt.source}}
+			if got := ebsVolumeID(pv); got != tt.want {
+				t.Errorf("ebsVolumeID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloudVolumeMismatches(t *testing.T) {
+	pv := func(name string, volumeID string, phase corev1.PersistentVolumePhase) corev1.PersistentVolume {
+		return corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: awsEBSCSIDriver, VolumeHandle: volumeID},
+			}},
+			Status: corev1.PersistentVolumeStatus{Phase: phase},
+		}
+	}
+	pvs := []corev1.PersistentVolume{
+		pv("healthy", "vol-1", corev1.VolumeBound),
+		pv("deleted", "vol-2", corev1.VolumeBound),
+		pv("broken", "vol-3", corev1.VolumeBound),
+		pv("released", "vol-4", corev1.VolumeReleased),
+	}
+	cloudVolumes := map[string]ec2types.Volume{
+		"vol-1": {State: ec2types.VolumeStateInUse},
+		"vol-3": {State: ec2types.VolumeStateError},
+		"vol-4": {State: ec2types.VolumeStateAvailable},
+		"vol-5": {State: ec2types.VolumeStateAvailable},
+	}
+
+	var got []string
+	for _, mismatch := range cloudVolumeMismatches(pvs, cloudVolumes) {
+		got = append(got, mismatch.VolumeID+" "+mismatch.State)
+	}
+	want := []string{"vol-2 missing", "vol-3 error", "vol-5 available"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cloudVolumeMismatches() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,174 @@
+package support
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// RemovalActionsConfigKey adds removal actions to the built-in ones, taking precedence over them, e.g.
+	//
+	//	limited_support_actions:
+	//	  - name: custom-ingress
+	//	    match: second ingress controller
+	//	    owner: Customer
+	//	    criteria: Remove the additional ingress controller
+	//	    links: [https://example.com/sop.md]
+	RemovalActionsConfigKey = "limited_support_actions"
+
+	// ownerCustomer and ownerSRE are who has to act for a limited support reason to be removed
+	ownerCustomer = "Customer"
+	ownerSRE      = "SRE"
+
+	// checkClusterReporting checks the cluster is ready and reports its telemetry again
+	checkClusterReporting = "cluster-reporting"
+	// telemetryFreshness is how recent the telemetry of a cluster reporting again must be
+	telemetryFreshness = 6 * time.Hour
+)
+
+// RemovalAction is what has to be done for a limited support reason to be removed
+type RemovalAction struct {
+	Name string `json:"name" mapstructure:"name"`
+	// Match is a case-insensitive regular expression matched against the summary and the details of the reason
+	Match    string `json:"match" mapstructure:"match"`
+	Owner    string `json:"owner" mapstructure:"owner"`
+	Criteria string `json:"criteria" mapstructure:"criteria"`
+	// Verify is a command helping to verify the criteria, with <cluster-id> replaced
+	Verify string `json:"verify,omitempty" mapstructure:"verify"`
+	// Links are the SOPs and the customer notifications of the reason
+	Links []string `json:"links,omitempty" mapstructure:"links"`
+	// Check is the automated check of the criteria run by --check-removal, if any
+	Check string `json:"check,omitempty" mapstructure:"check"`
+
+	re *regexp.Regexp
+}
+
+// removalActions are the known limited support reasons, from the most to the least specific
+var removalActions = []RemovalAction{
+	{
+		Name:     "export-control",
+		Match:    `export control`,
+		Owner:    ownerCustomer,
+		Criteria: "Have the export control compliance hold of the Red Hat account lifted, SRE can't lift it",
+		Links:    []string{"https://github.com/openshift/ops-sop/blob/master/v4/alerts/UpgradeConfigSyncFailureOver4HrSRE.md#user-banneddisabled-due-to-export-control-compliance"},
+	},
+	{
+		Name:     "blocked-egress",
+		Match:    `egress|firewall|blocked.*(url|domain|endpoint|network)`,
+		Owner:    ownerCustomer,
+		Criteria: "Allow the cluster to reach the required endpoints again through the firewall or the proxy",
+		Verify:   "osdctl network verify-egress --cluster-id <cluster-id>",
+		Links:    []string{"https://github.com/openshift/managed-notifications/blob/master/osd/required_network_egresses_are_blocked.json"},
+	},
+	{
+		Name:     "invalid-permissions",
+		Match:    `\b(iam|permissions?|sts|roles?|polic(y|ies))\b.*\b(missing|invalid|modified|deleted|removed)\b|\b(missing|invalid|modified|deleted|removed)\b.*\b(iam|permissions?|sts|roles?|polic(y|ies))\b`,
+		Owner:    ownerCustomer,
+		Criteria: "Restore the IAM roles, policies and users of the cluster as they were created",
+		Verify:   "osdctl cluster ccs-check --profile <customer-aws-profile>",
+		Links:    []string{"https://github.com/openshift/managed-notifications/blob/master/osd/aws/ROSA_AWS_invalid_permissions.json"},
+	},
+	{
+		Name:     "pull-secret",
+		Match:    `pull.?secret`,
+		Owner:    ownerCustomer,
+		Criteria: "Update the pull secret of the cluster with the one of the cluster owner",
+		Links:    []string{"https://github.com/openshift/managed-notifications/blob/master/osd/update_pull_secret.json"},
+	},
+	{
+		Name:     "not-reporting",
+		Match:    `telemetry|not (checking in|reporting)|unable to (be )?monitor|unreachable|stopped|shut ?down|powered off`,
+		Owner:    ownerCustomer,
+		Criteria: "Start the instances of the cluster and restore its connectivity, until it reports its telemetry again",
+		Check:    checkClusterReporting,
+	},
+	{
+		Name:     "unsupported-cloud-configuration",
+		Match:    regexp.QuoteMeta(LimitedSupportSummaryCloud),
+		Owner:    ownerCustomer,
+		Criteria: "Revert the changes to the cloud resources of the cluster described in the details",
+		Verify:   "osdctl cluster modification-check <cluster-id>",
+	},
+	{
+		Name:     "unsupported-cluster-configuration",
+		Match:    regexp.QuoteMeta(LimitedSupportSummaryCluster),
+		Owner:    ownerCustomer,
+		Criteria: "Apply the resolution described in the details, SRE then verifies the configuration on the cluster",
+	},
+}
+
+// configuredRemovalActions returns the removal actions of the config followed by the built-in ones
+func configuredRemovalActions() ([]RemovalAction, error) {
+	var actions []RemovalAction
+	if viper.IsSet(RemovalActionsConfigKey) {
+		if err := viper.UnmarshalKey(RemovalActionsConfigKey, &actions); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", RemovalActionsConfigKey, err)
+		}
+		for _, action := range actions {
+			if action.Name == "" || action.Match == "" || action.Criteria == "" {
+				return nil, fmt.Errorf("invalid removal action %+v in %s, a name, a match and criteria are required", action, RemovalActionsConfigKey)
+			}
+			if action.Check != "" && action.Check != checkClusterReporting {
+				return nil, fmt.Errorf("unknown check '%s' of removal action %s in %s, the only check is %s", action.Check, action.Name, RemovalActionsConfigKey, checkClusterReporting)
+			}
+		}
+	}
+	return compileRemovalActions(append(actions, removalActions...))
+}
+
+func compileRemovalActions(actions []RemovalAction) ([]RemovalAction, error) {
+	compiled := make([]RemovalAction, 0, len(actions))
+	for _, action := range actions {
+		re, err := regexp.Compile("(?i)" + action.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match of removal action %s: %w", action.Name, err)
+		}
+		action.re = re
+		if action.Owner == "" {
+			action.Owner = ownerSRE
+		}
+		compiled = append(compiled, action)
+	}
+	return compiled, nil
+}
+
+// matchRemovalAction returns the first action matching the summary or the details of a reason, nil when the
+// reason isn't a known one
+func matchRemovalAction(actions []RemovalAction, summary string, details string) *RemovalAction {
+	for i := range actions {
+		if actions[i].re.MatchString(summary) || actions[i].re.MatchString(details) {
+			return &actions[i]
+		}
+	}
+	return nil
+}
+
+// removalEvidence is the state of the cluster the automated checks of the removal criteria look at
+type removalEvidence struct {
+	ClusterID     string
+	State         string
+	LastTelemetry time.Time
+}
+
+// checkRemoval returns whether the criteria of the action appear to be satisfied, and why. checked is false
+// when the action has no automated check.
+func checkRemoval(action *RemovalAction, evidence removalEvidence, now time.Time) (checked bool, satisfied bool, detail string) {
+	switch action.Check {
+	case checkClusterReporting:
+		if evidence.State != "ready" {
+			return true, false, fmt.Sprintf("the cluster is %s, not ready", evidence.State)
+		}
+		if evidence.LastTelemetry.IsZero() {
+			return true, false, "the cluster never reported its telemetry"
+		}
+		age := now.Sub(evidence.LastTelemetry).Round(time.Minute)
+		if age > telemetryFreshness {
+			return true, false, fmt.Sprintf("the cluster last reported its telemetry %s ago", age)
+		}
+		return true, true, fmt.Sprintf("the cluster is ready and reported its telemetry %s ago", age)
+	}
+	return false, false, "no automated check, verify the criteria by hand"
+}
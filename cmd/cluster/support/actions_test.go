@@ -0,0 +1,120 @@
+package support
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchRemovalAction(t *testing.T) {
+	actions, err := compileRemovalActions(append([]RemovalAction{
+		{Name: "custom-ingress", Match: "second ingress controller", Criteria: "Remove the additional ingress controller"},
+	}, removalActions...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		summary string
+		details string
+		want    string
+	}{
+		{
+			name:    "configured action ahead of the built-in ones",
+			summary: LimitedSupportSummaryCluster,
+			details: "A second ingress controller was created. Please remove it.",
+			want:    "custom-ingress",
+		},
+		{
+			name:    "matched on the details",
+			summary: LimitedSupportSummaryCloud,
+			details: "Your cluster requires you to take action because Red Hat is not able to access the infrastructure with the provided credentials. The IAM role was deleted.",
+			want:    "invalid-permissions",
+		},
+		{
+			name:    "case insensitive",
+			summary: "Cluster is not reporting TELEMETRY",
+			want:    "not-reporting",
+		},
+		{
+			name:    "generic summary",
+			summary: LimitedSupportSummaryCloud,
+			details: "A security group of the cluster was modified.",
+			want:    "unsupported-cloud-configuration",
+		},
+		{
+			name:    "unknown reason",
+			summary: "Cluster is in Limited Support",
+			details: "Something else happened.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchRemovalAction(actions, tt.summary, tt.details)
+			if got == nil {
+				if tt.want != "" {
+					t.Errorf("matchRemovalAction() = nil, want %s", tt.want)
+				}
+				return
+			}
+			if got.Name != tt.want {
+				t.Errorf("matchRemovalAction() = %s, want %s", got.Name, tt.want)
+			}
+			if got.Owner == "" {
+				t.Errorf("matchRemovalAction() returned %s without an owner", got.Name)
+			}
+		})
+	}
+}
+
+func TestCheckRemoval(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	reporting := &RemovalAction{Name: "not-reporting", Check: checkClusterReporting}
+
+	tests := []struct {
+		name          string
+		action        *RemovalAction
+		evidence      removalEvidence
+		wantChecked   bool
+		wantSatisfied bool
+	}{
+		{
+			name:          "ready and reporting",
+			action:        reporting,
+			evidence:      removalEvidence{State: "ready", LastTelemetry: now.Add(-time.Hour)},
+			wantChecked:   true,
+			wantSatisfied: true,
+		},
+		{
+			name:        "telemetry too old",
+			action:      reporting,
+			evidence:    removalEvidence{State: "ready", LastTelemetry: now.Add(-24 * time.Hour)},
+			wantChecked: true,
+		},
+		{
+			name:        "never reported",
+			action:      reporting,
+			evidence:    removalEvidence{State: "ready"},
+			wantChecked: true,
+		},
+		{
+			name:        "hibernating",
+			action:      reporting,
+			evidence:    removalEvidence{State: "hibernating", LastTelemetry: now},
+			wantChecked: true,
+		},
+		{
+			name:     "no automated check",
+			action:   &RemovalAction{Name: "pull-secret"},
+			evidence: removalEvidence{State: "ready", LastTelemetry: now},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checked, satisfied, detail := checkRemoval(tt.action, tt.evidence, now)
+			if checked != tt.wantChecked || satisfied != tt.wantSatisfied {
+				t.Errorf("checkRemoval() = %v, %v (%s), want %v, %v", checked, satisfied, detail, tt.wantChecked, tt.wantSatisfied)
+			}
+		})
+	}
+}
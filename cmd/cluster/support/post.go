@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -20,6 +19,7 @@ import (
 	"github.com/openshift/osdctl/internal/utils"
 	ctlutil "github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 const (
@@ -44,6 +44,7 @@ type Post struct {
 	Problem          string
 	Resolution       string
 	Evidence         string
+	TemplateChecksum string
 	cluster          *cmv1.Cluster
 }
 
@@ -87,6 +88,7 @@ The cluster has a second failing ingress controller, which is not supported and
 
 	// Define required flags
 	postCmd.Flags().StringVarP(&p.Template, "template", "t", "", "Message template file or URL")
+	postCmd.Flags().StringVar(&p.TemplateChecksum, utils.TemplateChecksumFlagName, "", fmt.Sprintf("Expected sha256 of the template downloaded from a URL. Templates can also be pinned as '<sha256> <url>' entries of '%s' in the config, and signed with the keys of '%s'", utils.TemplatePinsConfigKey, utils.TemplateSigningKeysConfigKey))
 	postCmd.Flags().StringArrayVarP(&p.TemplateParams, "param", "p", p.TemplateParams, "Specify a key-value pair (eg. -p FOO=BAR) to set/override a parameter value in the template.")
 	postCmd.Flags().Var(&p.Misconfiguration, MisconfigurationFlag, "The type of misconfiguration responsible for the cluster being placed into limited support. Valid values are `cloud` or `cluster`.")
 	postCmd.Flags().StringVar(&p.Problem, ProblemFlag, "", "Complete sentence(s) describing the problem responsible for the cluster being placed into limited support. Will form the limited support message with the contents of --resolution appended")
@@ -325,11 +327,11 @@ func (p *Post) readTemplate() *TemplateFile {
 func (p *Post) accessFile(filePath string) ([]byte, error) {
 
 	if utils.IsValidUrl(filePath) {
-		urlPage, _ := url.Parse(filePath)
-		if err := utils.IsOnline(*urlPage); err != nil {
-			return nil, fmt.Errorf("host %q is not accessible", filePath)
-		}
-		return utils.CurlThis(urlPage.String())
+		return utils.FetchVerifiedTemplate(filePath, utils.TemplateVerification{
+			Checksum:    p.TemplateChecksum,
+			Pins:        viper.GetStringSlice(utils.TemplatePinsConfigKey),
+			SigningKeys: viper.GetStringSlice(utils.TemplateSigningKeysConfigKey),
+		})
 	}
 
 	filePath = filepath.Clean(filePath)
@@ -2,10 +2,15 @@ package support
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/osdctl/internal/utils/globalflags"
 	"github.com/openshift/osdctl/pkg/printer"
+	ctlutil "github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
@@ -13,9 +18,10 @@ import (
 )
 
 type statusOptions struct {
-	output    string
-	verbose   bool
-	clusterID string
+	output       string
+	verbose      bool
+	clusterID    string
+	checkRemoval bool
 
 	genericclioptions.IOStreams
 	GlobalOptions *globalflags.GlobalOptions
@@ -25,8 +31,20 @@ type statusOptions struct {
 func newCmdstatus(streams genericclioptions.IOStreams, globalOpts *globalflags.GlobalOptions) *cobra.Command {
 	ops := newStatusOptions(streams, globalOpts)
 	statusCmd := &cobra.Command{
-		Use:               "status",
-		Short:             "Shows the support status of a specified cluster",
+		Use:   "status",
+		Short: "Shows the support status of a specified cluster",
+		Long: fmt.Sprintf(`Shows the limited support reasons of a cluster, and next to each of the known ones what the customer or
+SRE must do for it to be removed, how to verify it and the SOPs or customer notifications of the reason.
+
+With --check-removal, the criteria which can be checked from OCM are checked against the current state of the
+cluster, and the command removing the reasons whose criteria appear satisfied is printed.
+
+The known reasons can be extended as '%s' in the config.`, RemovalActionsConfigKey),
+		Example: `  # Show the limited support reasons of a cluster and what their removal takes
+  osdctl cluster support status <cluster-id>
+
+  # Check whether the removal criteria now appear satisfied
+  osdctl cluster support status <cluster-id> --check-removal`,
 		Args:              cobra.ExactArgs(1),
 		DisableAutoGenTag: true,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -35,6 +53,7 @@ func newCmdstatus(streams genericclioptions.IOStreams, globalOpts *globalflags.G
 		},
 	}
 	statusCmd.Flags().BoolVarP(&ops.verbose, "verbose", "", false, "Verbose output")
+	statusCmd.Flags().BoolVar(&ops.checkRemoval, "check-removal", false, "Check whether the removal criteria of the limited support reasons appear satisfied")
 
 	return statusCmd
 }
@@ -84,5 +103,79 @@ func (o *statusOptions) run() error {
 		return err
 	}
 
+	actions, err := configuredRemovalActions()
+	if err != nil {
+		return err
+	}
+	var evidence *removalEvidence
+	if o.checkRemoval {
+		if evidence, err = getRemovalEvidence(o.clusterID); err != nil {
+			return err
+		}
+	}
+	printRemovalActions(os.Stdout, o.clusterID, clusterLimitedSupportReasons, actions, evidence, time.Now())
+
 	return nil
 }
+
+// getRemovalEvidence returns the state of the cluster the automated checks of the removal criteria look at
+func getRemovalEvidence(clusterID string) (*removalEvidence, error) {
+	connection, err := ctlutil.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	cluster, err := ctlutil.GetCluster(connection, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("can't retrieve cluster: %w", err)
+	}
+	subscription, err := ctlutil.GetSubscription(connection, cluster.ID())
+	if err != nil {
+		return nil, err
+	}
+	return &removalEvidence{
+		ClusterID:     cluster.ID(),
+		State:         string(cluster.State()),
+		LastTelemetry: subscription.LastTelemetryDate(),
+	}, nil
+}
+
+// printRemovalActions prints what the removal of each reason takes, and whether it appears satisfied when there's
+// evidence to check it against
+func printRemovalActions(w io.Writer, clusterID string, reasons []*cmv1.LimitedSupportReason, actions []RemovalAction, evidence *removalEvidence, now time.Time) {
+	if evidence != nil && evidence.ClusterID != "" {
+		clusterID = evidence.ClusterID
+	}
+	var satisfied []string
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "Removal of %s (%s)\n", reason.ID(), reason.Summary())
+		action := matchRemovalAction(actions, reason.Summary(), reason.Details())
+		if action == nil {
+			fmt.Fprintf(w, "  Not a known reason, see its details for what the customer was asked to do\n\n")
+			continue
+		}
+		fmt.Fprintf(w, "  Action (%s): %s\n", action.Owner, action.Criteria)
+		if action.Verify != "" {
+			fmt.Fprintf(w, "  Verify: %s\n", strings.ReplaceAll(action.Verify, "<cluster-id>", clusterID))
+		}
+		for _, link := range action.Links {
+			fmt.Fprintf(w, "  See: %s\n", link)
+		}
+		if evidence != nil {
+			checked, ok, detail := checkRemoval(action, *evidence, now)
+			status := "unknown"
+			if checked && ok {
+				status = "satisfied"
+				satisfied = append(satisfied, reason.ID())
+			} else if checked {
+				status = "not satisfied"
+			}
+			fmt.Fprintf(w, "  Removal criteria: %s, %s\n", status, detail)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, id := range satisfied {
+		fmt.Fprintf(w, "The criteria of %s appear satisfied, remove it with:\n  osdctl cluster support delete %s --limited-support-reason-id %s\n", id, clusterID, id)
+	}
+}
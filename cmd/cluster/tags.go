@@ -0,0 +1,362 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	managedResourceInstance = "instance"
+	managedResourceVolume   = "volume"
+
+	redHatManagedTag = "red-hat-managed"
+	// ec2FilterValuesBatchSize is the maximum number of values of an EC2 filter
+	ec2FilterValuesBatchSize = 200
+)
+
+type tagsVerifyOptions struct {
+	clusterID string
+	fix       bool
+	yes       bool
+}
+
+// tagFinding is a required tag missing from a resource, or set to another value than the expected one
+type tagFinding struct {
+	Resource managedResource
+	Key      string
+	Expected string
+	Actual   string
+	Missing  bool
+}
+
+// missingTags are the required tags to apply to a resource
+type missingTags struct {
+	Resource managedResource
+	Tags     map[string]string
+}
+
+// tagsEC2Client is the part of the EC2 API the tags checks use
+type tagsEC2Client interface {
+	modificationEC2Client
+	DescribeInstances(context.Context, *ec2.DescribeInstancesInput, ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeVolumes(context.Context, *ec2.DescribeVolumesInput, ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	CreateTags(context.Context, *ec2.CreateTagsInput, ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
+}
+
+func newCmdTags() *cobra.Command {
+	tagsCmd := &cobra.Command{
+		Use:               "tags",
+		Short:             "Audit the tags of the cloud resources of a cluster",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	tagsCmd.AddCommand(newCmdTagsVerify())
+	return tagsCmd
+}
+
+func newCmdTagsVerify() *cobra.Command {
+	ops := &tagsVerifyOptions{}
+	verifyCmd := &cobra.Command{
+		Use:   "verify <cluster-id>",
+		Short: "Check the required tags of the AWS resources of a cluster, and re-apply the missing ones",
+		Long: fmt.Sprintf(`Check the AWS resources of a cluster carry the tags the cloud provider integration and the cost attribution
+rely on: kubernetes.io/cluster/<infra-id>=owned and %s=true.
+
+The instances, their volumes and the security groups named after the infra ID or tagged with it, and the load
+balancers tagged with it, are checked. With --fix, the missing tags are applied again after confirmation. Tags
+set to another value are only reported, as the resource may be shared on purpose.`, redHatManagedTag),
+		Example: `  osdctl cluster tags verify <cluster-id>
+
+  # Re-apply the missing tags
+  osdctl cluster tags verify <cluster-id> --fix`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	verifyCmd.Flags().BoolVar(&ops.fix, "fix", false, "Apply the missing tags")
+	verifyCmd.Flags().BoolVarP(&ops.yes, "yes", "y", false, "Apply the missing tags without asking for confirmation")
+
+	return verifyCmd
+}
+
+func (o *tagsVerifyOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if cluster.CloudProvider().ID() != "aws" {
+		return fmt.Errorf("cluster %s is not an AWS cluster", cluster.ID())
+	}
+	infraID := cluster.InfraID()
+
+	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	if err != nil {
+		return err
+	}
+	ec2Client := ec2.NewFromConfig(cfg)
+	elbClient := elasticloadbalancing.NewFromConfig(cfg)
+	elbv2Client := elasticloadbalancingv2.NewFromConfig(cfg)
+
+	instances, err := listManagedInstances(ec2Client, infraID, cfg.Region)
+	if err != nil {
+		return err
+	}
+	volumes, err := listManagedVolumes(ec2Client, infraID, instances, cfg.Region)
+	if err != nil {
+		return err
+	}
+	securityGroups, err := listManagedSecurityGroups(ec2Client, infraID, cfg.Region)
+	if err != nil {
+		return err
+	}
+	loadBalancers, err := listManagedLoadBalancers(elbClient, elbv2Client, infraID, cfg.Region)
+	if err != nil {
+		return err
+	}
+	resources := append(append(append(instances, volumes...), securityGroups...), loadBalancers...)
+	if len(resources) == 0 {
+		return fmt.Errorf("no AWS resource of cluster %s was found, its infra ID is %s", cluster.ID(), infraID)
+	}
+
+	var findings []tagFinding
+	for _, resource := range resources {
+		findings = append(findings, checkRequiredTags(resource, requiredTags(infraID))...)
+	}
+	fmt.Printf("Checked the tags of %d AWS resources of cluster %s (%s)\n", len(resources), cluster.ID(), infraID)
+	if len(findings) == 0 {
+		fmt.Println("All the required tags are set")
+		return nil
+	}
+	if err := printTagFindings(findings); err != nil {
+		return err
+	}
+
+	missing := missingTagsByResource(findings)
+	if !o.fix {
+		if len(missing) > 0 {
+			fmt.Printf("\nRun again with --fix to apply the missing tags to %d resources\n", len(missing))
+		}
+		return fmt.Errorf("%d tags missing or unexpected", len(findings))
+	}
+	if len(missing) == 0 {
+		return fmt.Errorf("no tag is missing, the %d unexpected values have to be reviewed manually", len(findings))
+	}
+
+	fmt.Printf("\nThe missing tags will be applied to %d resources in account %s.\n", len(missing), cluster.AWS().AccountID())
+	if !o.yes && !utils.ConfirmPrompt() {
+		return nil
+	}
+	failures := applyMissingTags(ec2Client, elbClient, elbv2Client, missing)
+	if failures > 0 {
+		return fmt.Errorf("failed to tag %d resources", failures)
+	}
+	fmt.Printf("Tagged %d resources\n", len(missing))
+	return nil
+}
+
+// requiredTags are the tags every managed resource of the cluster carries, with their values
+func requiredTags(infraID string) map[string]string {
+	return map[string]string{
+		clusterOwnershipTag(infraID): ownedTagValue,
+		redHatManagedTag:             "true",
+	}
+}
+
+// checkRequiredTags returns the required tags the resource is missing or has another value for, by key
+func checkRequiredTags(resource managedResource, required map[string]string) []tagFinding {
+	keys := make([]string, 0, len(required))
+	for key := range required {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var findings []tagFinding
+	for _, key := range keys {
+		value, ok := resource.Tags[key]
+		switch {
+		case !ok:
+			findings = append(findings, tagFinding{Resource: resource, Key: key, Expected: required[key], Missing: true})
+		case value != required[key]:
+			findings = append(findings, tagFinding{Resource: resource, Key: key, Expected: required[key], Actual: value})
+		}
+	}
+	return findings
+}
+
+// missingTagsByResource groups the missing tags by resource, the resources in the order of the findings
+func missingTagsByResource(findings []tagFinding) []missingTags {
+	var grouped []missingTags
+	index := map[string]int{}
+	for _, finding := range findings {
+		if !finding.Missing {
+			continue
+		}
+		key := finding.Resource.Type + "/" + finding.Resource.ID
+		i, ok := index[key]
+		if !ok {
+			i = len(grouped)
+			index[key] = i
+			grouped = append(grouped, missingTags{Resource: finding.Resource, Tags: map[string]string{}})
+		}
+		grouped[i].Tags[finding.Key] = finding.Expected
+	}
+	return grouped
+}
+
+// applyMissingTags tags every resource with its missing tags, and returns the number of resources which failed
+// to be tagged
+func applyMissingTags(ec2Client tagsEC2Client, elbClient *elasticloadbalancing.Client, elbv2Client *elasticloadbalancingv2.Client, missing []missingTags) int {
+	failures := 0
+	for _, m := range missing {
+		resource := m.Resource
+		keys := make([]string, 0, len(m.Tags))
+		for key := range m.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var err error
+		switch {
+		case resource.Type == managedResourceLoadBalancer && strings.HasPrefix(resource.ID, "arn:"):
+			tags := make([]elbv2types.Tag, 0, len(keys))
+			for _, key := range keys {
+				tags = append(tags, elbv2types.Tag{Key: aws.String(key), Value: aws.String(m.Tags[key])})
+			}
+			_, err = elbv2Client.AddTags(context.TODO(), &elasticloadbalancingv2.AddTagsInput{ResourceArns: []string{resource.ID}, Tags: tags})
+		case resource.Type == managedResourceLoadBalancer:
+			tags := make([]elbtypes.Tag, 0, len(keys))
+			for _, key := range keys {
+				tags = append(tags, elbtypes.Tag{Key: aws.String(key), Value: aws.String(m.Tags[key])})
+			}
+			_, err = elbClient.AddTags(context.TODO(), &elasticloadbalancing.AddTagsInput{LoadBalancerNames: []string{resource.ID}, Tags: tags})
+		default:
+			tags := make([]ec2types.Tag, 0, len(keys))
+			for _, key := range keys {
+				tags = append(tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(m.Tags[key])})
+			}
+			_, err = ec2Client.CreateTags(context.TODO(), &ec2.CreateTagsInput{Resources: []string{resource.ID}, Tags: tags})
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to tag %s %s: %v\n", resource.Type, resource.ID, err)
+			failures++
+			continue
+		}
+		fmt.Printf("Tagged %s %s with %s\n", resource.Type, resource.ID, strings.Join(keys, ", "))
+	}
+	return failures
+}
+
+// listManagedInstances lists the instances tagged with the infra ID, or named after it, which aren't terminated
+func listManagedInstances(client tagsEC2Client, infraID string, region string) ([]managedResource, error) {
+	instances := map[string]managedResource{}
+	states := ec2types.Filter{Name: aws.String("instance-state-name"), Values: []string{"pending", "running", "stopping", "stopped"}}
+	filters := [][]ec2types.Filter{
+		{{Name: aws.String("tag-key"), Values: []string{clusterOwnershipTag(infraID)}}, states},
+		{{Name: aws.String("tag:Name"), Values: []string{infraID + "-*"}}, states},
+	}
+	for _, filter := range filters {
+		paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{Filters: filter})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err != nil {
+				return nil, fmt.Errorf("failed to list the instances: %w", err)
+			}
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					resource := managedResource{Type: managedResourceInstance, ID: aws.ToString(instance.InstanceId), Tags: ec2Tags(instance.Tags), Region: region}
+					resource.Name = resource.Tags["Name"]
+					instances[resource.ID] = resource
+				}
+			}
+		}
+	}
+	return sortedResources(instances), nil
+}
+
+// listManagedVolumes lists the volumes tagged with the infra ID, or attached to the instances of the cluster
+func listManagedVolumes(client tagsEC2Client, infraID string, instances []managedResource, region string) ([]managedResource, error) {
+	filters := [][]ec2types.Filter{
+		{{Name: aws.String("tag-key"), Values: []string{clusterOwnershipTag(infraID)}}},
+	}
+	for start := 0; start < len(instances); start += ec2FilterValuesBatchSize {
+		end := min(start+ec2FilterValuesBatchSize, len(instances))
+		ids := make([]string, 0, end-start)
+		for _, instance := range instances[start:end] {
+			ids = append(ids, instance.ID)
+		}
+		filters = append(filters, []ec2types.Filter{{Name: aws.String("attachment.instance-id"), Values: ids}})
+	}
+
+	volumes := map[string]managedResource{}
+	for _, filter := range filters {
+		paginator := ec2.NewDescribeVolumesPaginator(client, &ec2.DescribeVolumesInput{Filters: filter})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.TODO())
+			if err != nil {
+				return nil, fmt.Errorf("failed to list the volumes: %w", err)
+			}
+			for _, volume := range page.Volumes {
+				resource := managedResource{Type: managedResourceVolume, ID: aws.ToString(volume.VolumeId), Tags: ec2Tags(volume.Tags), Region: region}
+				resource.Name = resource.Tags["Name"]
+				volumes[resource.ID] = resource
+			}
+		}
+	}
+	return sortedResources(volumes), nil
+}
+
+func ec2Tags(tags []ec2types.Tag) map[string]string {
+	values := map[string]string{}
+	for _, tag := range tags {
+		values[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return values
+}
+
+func sortedResources(byID map[string]managedResource) []managedResource {
+	resources := make([]managedResource, 0, len(byID))
+	for _, resource := range byID {
+		resources = append(resources, resource)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].ID < resources[j].ID })
+	return resources
+}
+
+func printTagFindings(findings []tagFinding) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"TYPE", "RESOURCE", "NAME", "TAG", "PROBLEM"})
+	for _, finding := range findings {
+		problem := fmt.Sprintf("missing, expected '%s'", finding.Expected)
+		if !finding.Missing {
+			problem = fmt.Sprintf("'%s' instead of '%s'", finding.Actual, finding.Expected)
+		}
+		table.AddRow([]string{finding.Resource.Type, finding.Resource.ID, finding.Resource.Name, finding.Key, problem})
+	}
+	return table.Flush()
+}
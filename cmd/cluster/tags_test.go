@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckRequiredTags(t *testing.T) {
+	required := requiredTags("abc-x1y2z")
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		expected []string
+	}{
+		{
+			name: "tagged",
+			tags: map[string]string{"kubernetes.io/cluster/abc-x1y2z": "owned", "red-hat-managed": "true"},
+		},
+		{
+			name:     "missing",
+			tags:     map[string]string{"Name": "abc-x1y2z-worker-a"},
+			expected: []string{"kubernetes.io/cluster/abc-x1y2z missing", "red-hat-managed missing"},
+		},
+		{
+			name:     "unexpected value",
+			tags:     map[string]string{"kubernetes.io/cluster/abc-x1y2z": "shared"},
+			expected: []string{"kubernetes.io/cluster/abc-x1y2z=shared", "red-hat-managed missing"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var problems []string
+			for _, finding := range checkRequiredTags(managedResource{Tags: tt.tags}, required) {
+				if finding.Missing {
+					problems = append(problems, finding.Key+" missing")
+				} else {
+					problems = append(problems, finding.Key+"="+finding.Actual)
+				}
+			}
+			if !reflect.DeepEqual(problems, tt.expected) {
+				t.Errorf("checkRequiredTags() = %v, want %v", problems, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMissingTagsByResource(t *testing.T) {
+	instance := managedResource{Type: managedResourceInstance, ID: "i-0123456789abcdef0"}
+	group := managedResource{Type: managedResourceSecurityGroup, ID: "sg-0123456789abcdef0"}
+	findings := []tagFinding{
+		{Resource: instance, Key: "kubernetes.io/cluster/abc-x1y2z", Expected: "owned", Missing: true},
+		{Resource: group, Key: "kubernetes.io/cluster/abc-x1y2z", Expected: "owned", Actual: "shared"},
+		{Resource: instance, Key: "red-hat-managed", Expected: "true", Missing: true},
+		{Resource: group, Key: "red-hat-managed", Expected: "true", Missing: true},
+	}
+
+	expected := []missingTags{
+		{Resource: instance, Tags: map[string]string{"kubernetes.io/cluster/abc-x1y2z": "owned", "red-hat-managed": "true"}},
+		{Resource: group, Tags: map[string]string{"red-hat-managed": "true"}},
+	}
+	if missing := missingTagsByResource(findings); !reflect.DeepEqual(missing, expected) {
+		t.Errorf("missingTagsByResource() = %+v, want %+v", missing, expected)
+	}
+}
@@ -0,0 +1,478 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	gcpdns "google.golang.org/api/dns/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	dnsCheckPass = "PASS"
+	dnsCheckWarn = "WARN"
+	dnsCheckFail = "FAIL"
+
+	// systemResolver is the resolver of this machine, the other resolvers are queried directly
+	systemResolver = "system"
+)
+
+var defaultDNSResolvers = []string{systemResolver, "8.8.8.8", "1.1.1.1"}
+
+type validateDNSOptions struct {
+	clusterID string
+	resolvers []string
+	timeout   time.Duration
+}
+
+type dnsCheckResult struct {
+	Name    string
+	Source  string
+	Status  string
+	Details string
+}
+
+// cloudDNSRecord is the record of a name in the DNS zones of the cluster account
+type cloudDNSRecord struct {
+	Zone string
+	// Targets are the load balancers the record is an alias of, on AWS
+	Targets []string
+	// Addresses are the addresses of the A records, or those of the targets once resolved
+	Addresses []string
+}
+
+// dnsRoute53Client and dnsELBClient are the parts of the AWS APIs validate-dns uses
+type dnsRoute53Client interface {
+	ListHostedZones(context.Context, *route53.ListHostedZonesInput, ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error)
+	ListResourceRecordSets(context.Context, *route53.ListResourceRecordSetsInput, ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error)
+}
+
+type dnsELBClient interface {
+	DescribeLoadBalancers(context.Context, *elasticloadbalancing.DescribeLoadBalancersInput, ...func(*elasticloadbalancing.Options)) (*elasticloadbalancing.DescribeLoadBalancersOutput, error)
+}
+
+type dnsELBV2Client interface {
+	DescribeLoadBalancers(context.Context, *elasticloadbalancingv2.DescribeLoadBalancersInput, ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
+}
+
+func newCmdValidateDNS() *cobra.Command {
+	ops := &validateDNSOptions{}
+	validateDNSCmd := &cobra.Command{
+		Use:   "validate-dns <cluster-id>",
+		Short: "Check the API and ingress names of a cluster resolve to its load balancers",
+		Long: `Resolve api.<domain> and *.apps.<domain> of the cluster from several resolvers, and compare the answers with
+the records of the DNS zones in the cluster account (Route53 on AWS, Cloud DNS on GCP) and with the addresses
+of the load balancers they point to. Missing records, records pointing to deleted load balancers and
+resolvers returning other addresses are reported, as they're a frequent cause of unreachable clusters.
+
+The names of private clusters only resolve from their network, the public resolvers are expected to fail.`,
+		Example: `  osdctl cluster validate-dns <cluster-id>
+
+  # Query specific resolvers, e.g. the customer's
+  osdctl cluster validate-dns <cluster-id> --resolvers system,10.0.0.2,9.9.9.9`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	validateDNSCmd.Flags().StringSliceVar(&ops.resolvers, "resolvers", defaultDNSResolvers, fmt.Sprintf("Resolvers to query, '%s' for the resolver of this machine or the address of a DNS server", systemResolver))
+	validateDNSCmd.Flags().DurationVar(&ops.timeout, "timeout", 5*time.Second, "Timeout of each query")
+
+	return validateDNSCmd
+}
+
+func (o *validateDNSOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	cluster, err := utils.GetCluster(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+	apiName, appsDomain, err := clusterDNSNames(cluster.API().URL())
+	if err != nil {
+		return err
+	}
+	private := cluster.API().Listening() == cmv1.ListeningMethodInternal
+	// The wildcard record serves any name of the apps domain
+	names := []string{apiName, "*." + appsDomain}
+	queries := map[string]string{
+		apiName:           apiName,
+		"*." + appsDomain: fmt.Sprintf("validate-dns-%s.%s", rand.String(6), appsDomain),
+	}
+
+	records, recordResults := o.cloudRecords(connection, cluster, names)
+
+	var results []dnsCheckResult
+	for _, name := range names {
+		results = append(results, recordResults[name])
+		for _, resolver := range o.resolvers {
+			addresses, err := o.resolve(resolver, queries[name])
+			results = append(results, evaluateDNSResolution(name, resolver, addresses, err, records[name], private))
+		}
+	}
+
+	fmt.Printf("%sDNS of %s (%s)\n", delimiter, cluster.Name(), cluster.ID())
+	if failures := printDNSCheckResults(results); failures > 0 {
+		return fmt.Errorf("%d DNS check(s) failed", failures)
+	}
+	return nil
+}
+
+// clusterDNSNames returns the API name and the apps domain of the cluster, from its API URL
+func clusterDNSNames(apiURL string) (string, string, error) {
+	parsed, err := url.Parse(apiURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "", "", fmt.Errorf("invalid API URL '%s'", apiURL)
+	}
+	apiName := parsed.Hostname()
+	domain, found := strings.CutPrefix(apiName, "api.")
+	if !found {
+		return "", "", fmt.Errorf("the API name %s doesn't start with api.", apiName)
+	}
+	return apiName, "apps." + domain, nil
+}
+
+// cloudRecords looks up the names in the DNS zones of the cluster account. Each name gets a result, a
+// warning when the zones can't be read.
+func (o *validateDNSOptions) cloudRecords(connection *sdk.Connection, cluster *cmv1.Cluster, names []string) (map[string]*cloudDNSRecord, map[string]dnsCheckResult) {
+	var records map[string]*cloudDNSRecord
+	var loadBalancers map[string]bool
+	var err error
+	source := "cloud DNS"
+	switch cluster.CloudProvider().ID() {
+	case "aws":
+		source = "Route53"
+		records, loadBalancers, err = awsDNSRecords(connection, cluster, names)
+	case "gcp":
+		source = "Cloud DNS"
+		records, err = gcpDNSRecords(connection, cluster, names)
+	default:
+		err = fmt.Errorf("the records are only read on AWS and GCP, not %s", cluster.CloudProvider().ID())
+	}
+
+	results := map[string]dnsCheckResult{}
+	for _, name := range names {
+		if err != nil {
+			results[name] = dnsCheckResult{name, source, dnsCheckWarn, fmt.Sprintf("the records couldn't be read: %v", err)}
+			continue
+		}
+		record := records[name]
+		if record != nil {
+			record.Addresses = append(record.Addresses, resolveTargets(record.Targets)...)
+		}
+		results[name] = evaluateDNSRecord(name, source, record, loadBalancers)
+	}
+	return records, results
+}
+
+// awsDNSRecords returns the Route53 records of the names and the DNS names of the load balancers of
+// the account
+func awsDNSRecords(connection *sdk.Connection, cluster *cmv1.Cluster, names []string) (map[string]*cloudDNSRecord, map[string]bool, error) {
+	cfg, err := osdCloud.CreateAWSV2Config(connection, cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, err := route53Records(route53.NewFromConfig(cfg), names)
+	if err != nil {
+		return nil, nil, err
+	}
+	loadBalancers, err := awsLoadBalancerNames(elasticloadbalancing.NewFromConfig(cfg), elasticloadbalancingv2.NewFromConfig(cfg))
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, loadBalancers, nil
+}
+
+// route53Records looks up the names in the hosted zones of their domains, public and private
+func route53Records(client dnsRoute53Client, names []string) (map[string]*cloudDNSRecord, error) {
+	var zones []route53types.HostedZone
+	input := &route53.ListHostedZonesInput{}
+	for {
+		output, err := client.ListHostedZones(context.TODO(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the hosted zones: %w", err)
+		}
+		zones = append(zones, output.HostedZones...)
+		if !output.IsTruncated {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	records := map[string]*cloudDNSRecord{}
+	for _, zone := range zones {
+		zoneName := strings.TrimSuffix(aws.ToString(zone.Name), ".")
+		var zoneNames []string
+		for _, name := range names {
+			if name == zoneName || strings.HasSuffix(name, "."+zoneName) {
+				zoneNames = append(zoneNames, name)
+			}
+		}
+		if len(zoneNames) == 0 {
+			continue
+		}
+
+		recordInput := &route53.ListResourceRecordSetsInput{HostedZoneId: zone.Id}
+		for {
+			output, err := client.ListResourceRecordSets(context.TODO(), recordInput)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list the records of %s: %w", zoneName, err)
+			}
+			for _, recordSet := range output.ResourceRecordSets {
+				for _, name := range zoneNames {
+					if route53RecordName(aws.ToString(recordSet.Name)) == name && (recordSet.Type == route53types.RRTypeA || recordSet.Type == route53types.RRTypeCname) {
+						records[name] = mergeRoute53Record(records[name], zoneName, recordSet)
+					}
+				}
+			}
+			if !output.IsTruncated {
+				break
+			}
+			recordInput.StartRecordName = output.NextRecordName
+			recordInput.StartRecordType = output.NextRecordType
+			recordInput.StartRecordIdentifier = output.NextRecordIdentifier
+		}
+	}
+	return records, nil
+}
+
+// route53RecordName returns the name of a Route53 record as queried, Route53 returns the names fully
+// qualified with the wildcards escaped
+func route53RecordName(name string) string {
+	return strings.Replace(strings.TrimSuffix(name, "."), `\052`, "*", 1)
+}
+
+// mergeRoute53Record adds the record set to the record of the name, which can be in several zones
+func mergeRoute53Record(record *cloudDNSRecord, zone string, recordSet route53types.ResourceRecordSet) *cloudDNSRecord {
+	if record == nil {
+		record = &cloudDNSRecord{Zone: zone}
+	} else if !strings.Contains(record.Zone, zone) {
+		record.Zone += ", " + zone
+	}
+	if recordSet.AliasTarget != nil {
+		record.Targets = append(record.Targets, loadBalancerDNSName(aws.ToString(recordSet.AliasTarget.DNSName)))
+	}
+	for _, value := range recordSet.ResourceRecords {
+		if recordSet.Type == route53types.RRTypeCname {
+			record.Targets = append(record.Targets, loadBalancerDNSName(aws.ToString(value.Value)))
+		} else {
+			record.Addresses = append(record.Addresses, aws.ToString(value.Value))
+		}
+	}
+	return record
+}
+
+// loadBalancerDNSName returns the DNS name of a load balancer from an alias target, e.g.
+// dualstack.a1b2c3-1234.us-east-1.elb.amazonaws.com.
+func loadBalancerDNSName(target string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(target, "."), "dualstack."))
+}
+
+// awsLoadBalancerNames returns the DNS names of the classic and the network load balancers of the account
+func awsLoadBalancerNames(elbClient dnsELBClient, elbv2Client dnsELBV2Client) (map[string]bool, error) {
+	names := map[string]bool{}
+	elbInput := &elasticloadbalancing.DescribeLoadBalancersInput{}
+	for {
+		output, err := elbClient.DescribeLoadBalancers(context.TODO(), elbInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the classic load balancers: %w", err)
+		}
+		for _, loadBalancer := range output.LoadBalancerDescriptions {
+			names[loadBalancerDNSName(aws.ToString(loadBalancer.DNSName))] = true
+		}
+		if output.NextMarker == nil {
+			break
+		}
+		elbInput.Marker = output.NextMarker
+	}
+	elbv2Input := &elasticloadbalancingv2.DescribeLoadBalancersInput{}
+	for {
+		output, err := elbv2Client.DescribeLoadBalancers(context.TODO(), elbv2Input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the load balancers: %w", err)
+		}
+		for _, loadBalancer := range output.LoadBalancers {
+			names[loadBalancerDNSName(aws.ToString(loadBalancer.DNSName))] = true
+		}
+		if output.NextMarker == nil {
+			break
+		}
+		elbv2Input.Marker = output.NextMarker
+	}
+	return names, nil
+}
+
+// gcpDNSRecords returns the Cloud DNS records of the names in the project of the cluster, with the
+// application default credentials
+func gcpDNSRecords(connection *sdk.Connection, cluster *cmv1.Cluster, names []string) (map[string]*cloudDNSRecord, error) {
+	projectID, err := osdCloud.GetGCPProjectID(connection, cluster.ID())
+	if err != nil {
+		return nil, err
+	}
+	service, err := gcpdns.NewService(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	zones, err := service.ManagedZones.List(projectID).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the managed zones of %s: %w", projectID, err)
+	}
+
+	records := map[string]*cloudDNSRecord{}
+	for _, zone := range zones.ManagedZones {
+		zoneName := strings.TrimSuffix(zone.DnsName, ".")
+		for _, name := range names {
+			if name != zoneName && !strings.HasSuffix(name, "."+zoneName) {
+				continue
+			}
+			recordSets, err := service.ResourceRecordSets.List(projectID, zone.Name).Name(name + ".").Type("A").Do()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list the records of %s: %w", zone.Name, err)
+			}
+			for _, recordSet := range recordSets.Rrsets {
+				if records[name] == nil {
+					records[name] = &cloudDNSRecord{Zone: zoneName}
+				}
+				records[name].Addresses = append(records[name].Addresses, recordSet.Rrdatas...)
+			}
+		}
+	}
+	return records, nil
+}
+
+// resolveTargets returns the addresses of the load balancers, their addresses rotate so they're compared
+// with whatever the resolvers return at the same time
+func resolveTargets(targets []string) []string {
+	var addresses []string
+	for _, target := range targets {
+		targetAddresses, err := net.LookupHost(target)
+		if err != nil {
+			continue
+		}
+		addresses = append(addresses, targetAddresses...)
+	}
+	return addresses
+}
+
+// resolve looks up the name from the resolver
+func (o *validateDNSOptions) resolve(resolver string, name string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+	return newDNSResolver(resolver, o.timeout).LookupHost(ctx, name)
+}
+
+// newDNSResolver returns the resolver of this machine, or one querying the DNS server at the address
+func newDNSResolver(resolver string, timeout time.Duration) *net.Resolver {
+	if resolver == systemResolver {
+		return net.DefaultResolver
+	}
+	address := resolver
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		address = net.JoinHostPort(resolver, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: timeout}
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// evaluateDNSRecord checks the name has a record in the zones of the cluster account and, on AWS, that the
+// load balancers it points to exist
+func evaluateDNSRecord(name string, source string, record *cloudDNSRecord, loadBalancers map[string]bool) dnsCheckResult {
+	if record == nil {
+		return dnsCheckResult{name, source, dnsCheckFail, "no record in the DNS zones of the cluster account"}
+	}
+	var missing []string
+	for _, target := range record.Targets {
+		if loadBalancers != nil && !loadBalancers[target] {
+			missing = append(missing, target)
+		}
+	}
+	if len(missing) > 0 {
+		return dnsCheckResult{name, source, dnsCheckFail, fmt.Sprintf("%s points to %s, not a load balancer of the account", record.Zone, strings.Join(missing, ", "))}
+	}
+	if len(record.Addresses) == 0 {
+		return dnsCheckResult{name, source, dnsCheckWarn, fmt.Sprintf("%s points to %s, which doesn't resolve", record.Zone, strings.Join(record.Targets, ", "))}
+	}
+	details := fmt.Sprintf("%s: %s", record.Zone, strings.Join(sortedAddresses(record.Addresses), ", "))
+	if len(record.Targets) > 0 {
+		details = fmt.Sprintf("%s: %s (%s)", record.Zone, strings.Join(record.Targets, ", "), strings.Join(sortedAddresses(record.Addresses), ", "))
+	}
+	return dnsCheckResult{name, source, dnsCheckPass, details}
+}
+
+// evaluateDNSResolution compares the answer of a resolver with the addresses of the record in the cluster
+// account. The addresses of the load balancers rotate, sharing any of them is enough.
+func evaluateDNSResolution(name string, resolver string, addresses []string, err error, record *cloudDNSRecord, private bool) dnsCheckResult {
+	if err != nil || len(addresses) == 0 {
+		if private && resolver != systemResolver {
+			return dnsCheckResult{name, resolver, dnsCheckWarn, "doesn't resolve, expected for a private cluster"}
+		}
+		if err == nil {
+			err = fmt.Errorf("no address")
+		}
+		return dnsCheckResult{name, resolver, dnsCheckFail, fmt.Sprintf("doesn't resolve: %v", err)}
+	}
+	resolved := strings.Join(sortedAddresses(addresses), ", ")
+	if record == nil || len(record.Addresses) == 0 {
+		return dnsCheckResult{name, resolver, dnsCheckWarn, fmt.Sprintf("%s, no record to compare with", resolved)}
+	}
+	for _, address := range addresses {
+		for _, expected := range record.Addresses {
+			if address == expected {
+				return dnsCheckResult{name, resolver, dnsCheckPass, resolved}
+			}
+		}
+	}
+	return dnsCheckResult{name, resolver, dnsCheckFail, fmt.Sprintf("%s, expected %s", resolved, strings.Join(sortedAddresses(record.Addresses), ", "))}
+}
+
+func sortedAddresses(addresses []string) []string {
+	sorted := append([]string{}, addresses...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func printDNSCheckResults(results []dnsCheckResult) int {
+	failures := 0
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"NAME", "SOURCE", "STATUS", "DETAILS"})
+	for _, result := range results {
+		if result.Status == dnsCheckFail {
+			failures++
+		}
+		table.AddRow([]string{result.Name, result.Source, result.Status, result.Details})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the DNS checks: %v\n", err)
+	}
+	return failures
+}
@@ -0,0 +1,126 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+func TestClusterDNSNames(t *testing.T) {
+	apiName, appsDomain, err := clusterDNSNames("https://api.my-cluster.a1b2.p1.openshiftapps.com:6443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apiName != "api.my-cluster.a1b2.p1.openshiftapps.com" || appsDomain != "apps.my-cluster.a1b2.p1.openshiftapps.com" {
+		t.Errorf("clusterDNSNames() = %s, %s", apiName, appsDomain)
+	}
+	if _, _, err := clusterDNSNames("https://my-cluster.example.com"); err == nil {
+		t.Error("clusterDNSNames() accepted an API name not starting with api.")
+	}
+}
+
+type fakeRoute53 struct {
+	zones   []route53types.HostedZone
+	records map[string][]route53types.ResourceRecordSet
+}
+
+func (f *fakeRoute53) ListHostedZones(context.Context, *route53.ListHostedZonesInput, ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	return &route53.ListHostedZonesOutput{HostedZones: f.zones}, nil
+}
+
+func (f *fakeRoute53) ListResourceRecordSets(_ context.Context, input *route53.ListResourceRecordSetsInput, _ ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+	return &route53.ListResourceRecordSetsOutput{ResourceRecordSets: f.records[aws.ToString(input.HostedZoneId)]}, nil
+}
+
+func TestRoute53Records(t *testing.T) {
+	client := &fakeRoute53{
+		zones: []route53types.HostedZone{
+			{Id: aws.String("Z1"), Name: aws.String("my-cluster.a1b2.p1.openshiftapps.com.")},
+			{Id: aws.String("Z2"), Name: aws.String("other.example.com.")},
+		},
+		records: map[string][]route53types.ResourceRecordSet{
+			"Z1": {
+				{Name: aws.String("api.my-cluster.a1b2.p1.openshiftapps.com."), Type: route53types.RRTypeA, AliasTarget: &route53types.AliasTarget{DNSName: aws.String("dualstack.A1B2-123.elb.us-east-1.amazonaws.com.")}},
+				{Name: aws.String(`\052.apps.my-cluster.a1b2.p1.openshiftapps.com.`), Type: route53types.RRTypeA, ResourceRecords: []route53types.ResourceRecord{{Value: aws.String("203.0.113.10")}}},
+				{Name: aws.String("my-cluster.a1b2.p1.openshiftapps.com."), Type: route53types.RRTypeSoa},
+			},
+		},
+	}
+
+	records, err := route53Records(client, []string{"api.my-cluster.a1b2.p1.openshiftapps.com", "*.apps.my-cluster.a1b2.p1.openshiftapps.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*cloudDNSRecord{
+		"api.my-cluster.a1b2.p1.openshiftapps.com":    {Zone: "my-cluster.a1b2.p1.openshiftapps.com", Targets: []string{"a1b2-123.elb.us-east-1.amazonaws.com"}},
+		"*.apps.my-cluster.a1b2.p1.openshiftapps.com": {Zone: "my-cluster.a1b2.p1.openshiftapps.com", Addresses: []string{"203.0.113.10"}},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("route53Records() = %+v, want %+v", records, want)
+	}
+}
+
+func TestEvaluateDNSRecord(t *testing.T) {
+	loadBalancers := map[string]bool{"a1b2-123.elb.us-east-1.amazonaws.com": true}
+	tests := []struct {
+		name   string
+		record *cloudDNSRecord
+		want   string
+	}{
+		{name: "missing record", want: dnsCheckFail},
+		{
+			name:   "alias of an existing load balancer",
+			record: &cloudDNSRecord{Zone: "example.com", Targets: []string{"a1b2-123.elb.us-east-1.amazonaws.com"}, Addresses: []string{"203.0.113.10"}},
+			want:   dnsCheckPass,
+		},
+		{
+			name:   "alias of a deleted load balancer",
+			record: &cloudDNSRecord{Zone: "example.com", Targets: []string{"old-456.elb.us-east-1.amazonaws.com"}},
+			want:   dnsCheckFail,
+		},
+		{
+			name:   "target not resolving",
+			record: &cloudDNSRecord{Zone: "example.com", Targets: []string{"a1b2-123.elb.us-east-1.amazonaws.com"}},
+			want:   dnsCheckWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateDNSRecord("api.example.com", "Route53", tt.record, loadBalancers); got.Status != tt.want {
+				t.Errorf("evaluateDNSRecord() = %+v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateDNSResolution(t *testing.T) {
+	record := &cloudDNSRecord{Zone: "example.com", Addresses: []string{"203.0.113.10", "203.0.113.11"}}
+	tests := []struct {
+		name      string
+		resolver  string
+		addresses []string
+		err       error
+		record    *cloudDNSRecord
+		private   bool
+		want      string
+	}{
+		{name: "any address of the load balancer", resolver: "8.8.8.8", addresses: []string{"203.0.113.11"}, record: record, want: dnsCheckPass},
+		{name: "other addresses", resolver: "8.8.8.8", addresses: []string{"198.51.100.1"}, record: record, want: dnsCheckFail},
+		{name: "not resolving", resolver: systemResolver, err: errors.New("no such host"), record: record, want: dnsCheckFail},
+		{name: "private cluster from a public resolver", resolver: "1.1.1.1", err: errors.New("no such host"), record: record, private: true, want: dnsCheckWarn},
+		{name: "private cluster from this machine", resolver: systemResolver, err: errors.New("no such host"), record: record, private: true, want: dnsCheckFail},
+		{name: "no record to compare with", resolver: "8.8.8.8", addresses: []string{"203.0.113.10"}, want: dnsCheckWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateDNSResolution("api.example.com", tt.resolver, tt.addresses, tt.err, tt.record, tt.private); got.Status != tt.want {
+				t.Errorf("evaluateDNSResolution() = %+v, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// uninstalledState isn't an OCM state, it's reached once the cluster is gone from OCM
+const uninstalledState = "uninstalled"
+
+var waitForStates = []string{
+	string(cmv1.ClusterStateReady),
+	string(cmv1.ClusterStateInstalling),
+	string(cmv1.ClusterStateUninstalling),
+	string(cmv1.ClusterStateHibernating),
+	string(cmv1.ClusterStateError),
+	uninstalledState,
+}
+
+type waitForOptions struct {
+	clusterID string
+	state     string
+	timeout   time.Duration
+	interval  time.Duration
+	hive      bool
+
+	hiveClient client.Client
+}
+
+func newCmdWaitFor() *cobra.Command {
+	ops := &waitForOptions{}
+	waitForCmd := &cobra.Command{
+		Use:   "wait-for <cluster-id>",
+		Short: "Wait until a cluster reaches a state in OCM",
+		Long: `Poll OCM until the cluster reaches the given state, printing every state change on the way.
+
+The command fails as soon as the cluster goes into the error state (unless waiting for it) or when
+--timeout expires, so it can be used in provisioning pipelines. With --hive, the conditions of the
+cluster's ClusterDeployment on its hive shard are printed too, which explains most install failures.`,
+		Example: `  # Wait for an install to complete
+  osdctl cluster wait-for <cluster-id> --state ready --timeout 90m
+
+  # Wait for a cluster to be deleted
+  osdctl cluster wait-for <cluster-id> --state uninstalled`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	waitForCmd.Flags().StringVar(&ops.state, "state", string(cmv1.ClusterStateReady), fmt.Sprintf("State to wait for, one of %v", waitForStates))
+	waitForCmd.Flags().DurationVar(&ops.timeout, "timeout", 90*time.Minute, "How long to wait before giving up")
+	waitForCmd.Flags().DurationVar(&ops.interval, "interval", 30*time.Second, "How often OCM is polled")
+	waitForCmd.Flags().BoolVar(&ops.hive, "hive", false, "Also print the ClusterDeployment conditions from the hive shard (classic clusters only)")
+
+	return waitForCmd
+}
+
+func (o *waitForOptions) validate() error {
+	for _, state := range waitForStates {
+		if o.state == state {
+			if o.interval <= 0 || o.timeout <= 0 {
+				return fmt.Errorf("--interval and --timeout must be positive")
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown state '%s', expected one of %v", o.state, waitForStates)
+}
+
+// evaluateState returns whether the wait is over, and an error if the target state can't be reached anymore
+func evaluateState(target string, current string, found bool) (bool, error) {
+	if !found {
+		if target == uninstalledState {
+			return true, nil
+		}
+		return false, fmt.Errorf("the cluster doesn't exist in OCM anymore")
+	}
+	if current == target {
+		return true, nil
+	}
+	switch {
+	case current == string(cmv1.ClusterStateError):
+		return false, fmt.Errorf("the cluster is in the error state")
+	case current == string(cmv1.ClusterStateUninstalling) && target != uninstalledState:
+		return false, fmt.Errorf("the cluster is being uninstalled")
+	}
+	return false, nil
+}
+
+func (o *waitForOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetClusterAnyStatus(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	clusterID := cluster.ID()
+
+	if o.hive {
+		if cluster.Hypershift().Enabled() {
+			fmt.Fprintln(os.Stderr, "Hosted control plane clusters have no ClusterDeployment, ignoring --hive")
+			o.hive = false
+		} else if o.hiveClient, err = newHiveClient(clusterID); err != nil {
+			return fmt.Errorf("failed to access the hive shard of %s: %w", clusterID, err)
+		}
+	}
+
+	fmt.Printf("Waiting up to %s for cluster %s (%s) to be %s\n", o.timeout, cluster.Name(), clusterID, o.state)
+	deadline := time.Now().Add(o.timeout)
+	lastProgress := ""
+	lastConditions := map[string]string{}
+	for {
+		cluster, found, err := getClusterByID(ocmClient, clusterID)
+		if err != nil {
+			// OCM hiccups shouldn't fail a long wait, the next poll will tell
+			fmt.Fprintf(os.Stderr, "Failed to get the cluster from OCM: %v\n", err)
+		} else {
+			current := ""
+			if found {
+				current = string(cluster.State())
+			}
+			if progress := describeProgress(cluster, found); progress != lastProgress {
+				fmt.Printf("[%s] %s\n", time.Now().UTC().Format(time.RFC3339), progress)
+				lastProgress = progress
+			}
+			if o.hive && found {
+				lastConditions = o.printHiveConditions(clusterID, lastConditions)
+			}
+
+			done, err := evaluateState(o.state, current, found)
+			if err != nil {
+				// The provision error code is explained when the command exits
+				if found && cluster.Status().ProvisionErrorCode() != "" {
+					err = fmt.Errorf("%w, provision error %s", err, cluster.Status().ProvisionErrorCode())
+				}
+				return fmt.Errorf("cluster %s won't reach the %s state: %w", clusterID, o.state, err)
+			}
+			if done {
+				fmt.Printf("Cluster %s is %s\n", clusterID, o.state)
+				return nil
+			}
+		}
+
+		if time.Now().Add(o.interval).After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for cluster %s to be %s", o.timeout, clusterID, o.state)
+		}
+		time.Sleep(o.interval)
+	}
+}
+
+// getClusterByID returns the cluster, or false if it doesn't exist anymore
+func getClusterByID(ocmClient *sdk.Connection, clusterID string) (*cmv1.Cluster, bool, error) {
+	response, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(clusterID).Get().Send()
+	if response != nil && response.Status() == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return response.Body(), true, nil
+}
+
+func describeProgress(cluster *cmv1.Cluster, found bool) string {
+	if !found {
+		return "cluster not found in OCM"
+	}
+	progress := fmt.Sprintf("state: %s", cluster.State())
+	status := cluster.Status()
+	if description := status.Description(); description != "" {
+		progress += fmt.Sprintf(" (%s)", description)
+	}
+	if code := status.ProvisionErrorCode(); code != "" {
+		progress += fmt.Sprintf(" - provision error %s: %s", code, status.ProvisionErrorMessage())
+	}
+	return progress
+}
+
+func newHiveClient(clusterID string) (client.Client, error) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := batchv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := hivev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	hive, err := utils.GetHiveCluster(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.New(hive.ID(), client.Options{Scheme: scheme})
+}
+
+// printHiveConditions prints the ClusterDeployment conditions which changed since the last poll and
+// returns the current ones
+func (o *waitForOptions) printHiveConditions(clusterID string, last map[string]string) map[string]string {
+	var cdList hivev1.ClusterDeploymentList
+	if err := o.hiveClient.List(context.TODO(), &cdList, client.MatchingLabels{"api.openshift.com/id": clusterID}); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get the ClusterDeployment: %v\n", err)
+		return last
+	}
+	if len(cdList.Items) != 1 {
+		return last
+	}
+
+	current := map[string]string{}
+	var changed []string
+	for _, condition := range cdList.Items[0].Status.Conditions {
+		value := fmt.Sprintf("%s %s", condition.Status, condition.Reason)
+		if condition.Message != "" {
+			value += ": " + strings.TrimSpace(condition.Message)
+		}
+		current[string(condition.Type)] = value
+		if last[string(condition.Type)] != value && condition.Status == corev1.ConditionTrue {
+			changed = append(changed, fmt.Sprintf("  %s: %s", condition.Type, value))
+		}
+	}
+	sort.Strings(changed)
+	for _, line := range changed {
+		fmt.Println(line)
+	}
+	return current
+}
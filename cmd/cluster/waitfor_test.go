@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"testing"
+)
+
+func TestEvaluateState(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		current  string
+		found    bool
+		wantDone bool
+		wantErr  bool
+	}{
+		{name: "reached", target: "ready", current: "ready", found: true, wantDone: true},
+		{name: "still installing", target: "ready", current: "installing", found: true},
+		{name: "install failed", target: "ready", current: "error", found: true, wantErr: true},
+		{name: "waiting for error", target: "error", current: "error", found: true, wantDone: true},
+		{name: "uninstalled while waiting for ready", target: "ready", current: "uninstalling", found: true, wantErr: true},
+		{name: "deleted while waiting for ready", target: "ready", found: false, wantErr: true},
+		{name: "uninstalling", target: uninstalledState, current: "uninstalling", found: true},
+		{name: "uninstalled", target: uninstalledState, found: false, wantDone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, err := evaluateState(tt.target, tt.current, tt.found)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluateState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if done != tt.wantDone {
+				t.Errorf("evaluateState() = %t, want %t", done, tt.wantDone)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strings"
 
@@ -11,20 +12,31 @@ import (
 	gcpv1alpha1 "github.com/openshift/gcp-project-operator/api/v1alpha1"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	hivev1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes/scheme"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/slice"
 
 	"github.com/openshift/osdctl/cmd/aao"
 	"github.com/openshift/osdctl/cmd/account"
 	"github.com/openshift/osdctl/cmd/alerts"
+	"github.com/openshift/osdctl/cmd/api"
+	bookmarkcmd "github.com/openshift/osdctl/cmd/bookmark"
 	"github.com/openshift/osdctl/cmd/capability"
+	"github.com/openshift/osdctl/cmd/cases"
 	"github.com/openshift/osdctl/cmd/cloudtrail"
 	"github.com/openshift/osdctl/cmd/cluster"
+	"github.com/openshift/osdctl/cmd/config"
 	"github.com/openshift/osdctl/cmd/cost"
 	"github.com/openshift/osdctl/cmd/env"
+	"github.com/openshift/osdctl/cmd/explain"
+	"github.com/openshift/osdctl/cmd/fleet"
+	"github.com/openshift/osdctl/cmd/grep"
+	"github.com/openshift/osdctl/cmd/hcp"
+	historycmd "github.com/openshift/osdctl/cmd/history"
 	"github.com/openshift/osdctl/cmd/hive"
 	"github.com/openshift/osdctl/cmd/iampermissions"
 	"github.com/openshift/osdctl/cmd/jira"
@@ -33,12 +45,21 @@ import (
 	"github.com/openshift/osdctl/cmd/network"
 	"github.com/openshift/osdctl/cmd/org"
 	"github.com/openshift/osdctl/cmd/promote"
+	"github.com/openshift/osdctl/cmd/report"
+	"github.com/openshift/osdctl/cmd/serve"
 	"github.com/openshift/osdctl/cmd/servicelog"
 	"github.com/openshift/osdctl/cmd/setup"
 	"github.com/openshift/osdctl/cmd/swarm"
 	"github.com/openshift/osdctl/internal/utils/globalflags"
+	"github.com/openshift/osdctl/pkg/bookmark"
+	"github.com/openshift/osdctl/pkg/guardrails"
+	"github.com/openshift/osdctl/pkg/history"
+	"github.com/openshift/osdctl/pkg/httpdebug"
+	"github.com/openshift/osdctl/pkg/interrupt"
 	"github.com/openshift/osdctl/pkg/k8s"
+	"github.com/openshift/osdctl/pkg/ocmerror"
 	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/redact"
 	"github.com/openshift/osdctl/pkg/utils"
 )
 
@@ -51,6 +72,15 @@ func init() {
 	_ = hivev1alpha1.AddToScheme(scheme.Scheme)
 }
 
+// redactLogs masks the credentials in everything logged through log and logrus, the debug and verbose
+// output included, whatever the command
+func redactLogs() {
+	redact.RegisterConfigSecrets(viper.AllSettings())
+	redact.RegisterEnvSecrets()
+	log.SetOutput(redact.SecretsWriter(os.Stderr))
+	logrus.SetOutput(redact.SecretsWriter(os.Stderr))
+}
+
 // NewCmdRoot represents the base command when called without any subcommands
 func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	globalOpts := &globalflags.GlobalOptions{}
@@ -67,6 +97,27 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 			}
 			viper.Set(aws.NoProxyFlag, noAwsProxy)
 
+			if err := httpdebug.SetMode(globalOpts.DebugHTTP); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			redactLogs()
+			resolveBookmarks(cmd, args)
+			interrupt.Handle(cmd)
+
+			viper.Set(guardrails.ChangeRecordFlag, globalOpts.ChangeRecord)
+			if err := enforceProductionReason(cmd); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			// Viewing the history isn't an action on the cluster worth recording
+			if cmd.Name() != "history" {
+				if err := history.Record(cmd, args); err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "WARN: failed to record the command in the history: %v\n", err)
+				}
+			}
+
 			skipVersionCheck, err := cmd.Flags().GetBool("skip-version-check")
 			if err != nil {
 				fmt.Println("flag --skip-version-check/-S undefined")
@@ -80,6 +131,7 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 		},
 	}
 
+	cmdutil.BehaviorOnFatal(explainFatal)
 	globalflags.AddGlobalFlags(rootCmd, globalOpts)
 	kubeFlags := globalflags.GetFlags(rootCmd)
 
@@ -89,9 +141,18 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	rootCmd.AddCommand(aao.NewCmdAao(kubeClient))
 	rootCmd.AddCommand(account.NewCmdAccount(streams, kubeClient, globalOpts))
 	rootCmd.AddCommand(alerts.NewCmdAlerts())
+	rootCmd.AddCommand(api.NewCmdApi())
+	rootCmd.AddCommand(bookmarkcmd.NewCmdBookmark())
+	rootCmd.AddCommand(cases.NewCmdCase())
 	rootCmd.AddCommand(cloudtrail.NewCloudtrailCmd())
 	rootCmd.AddCommand(cluster.NewCmdCluster(streams, kubeClient, globalOpts))
+	rootCmd.AddCommand(config.NewCmdConfig())
 	rootCmd.AddCommand(env.NewCmdEnv())
+	rootCmd.AddCommand(explain.NewCmdExplain())
+	rootCmd.AddCommand(fleet.NewCmdFleet())
+	rootCmd.AddCommand(grep.NewCmdGrep())
+	rootCmd.AddCommand(hcp.NewCmdHcp())
+	rootCmd.AddCommand(historycmd.NewCmdHistory())
 	rootCmd.AddCommand(hive.NewCmdHive(streams, kubeClient))
 	rootCmd.AddCommand(jira.Cmd)
 	rootCmd.AddCommand(jumphost.NewCmdJumphost())
@@ -99,6 +160,8 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	rootCmd.AddCommand(network.NewCmdNetwork(streams, kubeClient))
 	rootCmd.AddCommand(org.NewCmdOrg())
 	rootCmd.AddCommand(promote.NewCmdPromote())
+	rootCmd.AddCommand(report.NewCmdReport())
+	rootCmd.AddCommand(serve.NewCmdServe())
 	rootCmd.AddCommand(servicelog.NewCmdServiceLog())
 	rootCmd.AddCommand(setup.NewCmdSetup())
 	rootCmd.AddCommand(swarm.Cmd)
@@ -126,6 +189,67 @@ func help(cmd *cobra.Command, _ []string) {
 	}
 }
 
+// explainFatal prints the error a command exits with, as kubectl's CheckErr does, followed by the explanation
+// of the OCM error codes it mentions
+func explainFatal(msg string, code int) {
+	if len(msg) > 0 {
+		if !strings.HasSuffix(msg, "\n") {
+			msg += "\n"
+		}
+		_, _ = fmt.Fprint(os.Stderr, msg)
+		if guidance := ocmerror.Guidance(msg); guidance != "" {
+			_, _ = fmt.Fprint(os.Stderr, "\n"+guidance)
+		}
+	}
+	os.Exit(code)
+}
+
+// resolveBookmarks replaces the bookmark given as the cluster of the command, by its cluster ID flag or its
+// cluster argument, with the ID of the bookmarked cluster
+func resolveBookmarks(cmd *cobra.Command, args []string) {
+	path, err := bookmark.File()
+	if err != nil {
+		return
+	}
+	bookmarks, err := bookmark.Read(path)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "WARN: failed to read the bookmarks: %v\n", err)
+		return
+	}
+	if len(bookmarks) == 0 {
+		return
+	}
+	for _, name := range history.ClusterIDFlags {
+		if flag := cmd.Flags().Lookup(name); flag != nil {
+			if b, ok := bookmark.Lookup(bookmarks, flag.Value.String()); ok {
+				_ = flag.Value.Set(b.ClusterID)
+			}
+		}
+	}
+	if position := history.ClusterArgPosition(cmd); position >= 0 && position < len(args) {
+		if b, ok := bookmark.Lookup(bookmarks, args[position]); ok {
+			args[position] = b.ClusterID
+		}
+	}
+}
+
+// enforceProductionReason applies the production_reason guardrail to the commands taking a --reason
+func enforceProductionReason(cmd *cobra.Command) error {
+	reasonFlag := cmd.Flags().Lookup("reason")
+	if reasonFlag == nil {
+		return nil
+	}
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	return guardrails.EnforceProductionReason(command, reasonFlag.Value.String(), func() (bool, error) {
+		connection, err := utils.CreateConnection()
+		if err != nil {
+			return false, err
+		}
+		defer connection.Close()
+		return utils.GetCurrentOCMEnv(connection) == "production", nil
+	})
+}
+
 // Checks if the version check should be run
 func shouldRunVersionCheck(skipVersionCheckFlag bool, commandName string) bool {
 
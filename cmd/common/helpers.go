@@ -2,10 +2,8 @@ package common
 
 import (
 	"context"
-	"fmt"
 
-	bplogin "github.com/openshift/backplane-cli/cmd/ocm-backplane/login"
-	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/osdctl/pkg/k8s"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -35,16 +33,7 @@ func UpdateSecret(kubeClient client.Client, secretName string, secretNamespace s
 
 // If some elevationReasons are provided, then the config will be elevated with user backplane-cluster-admin
 func GetKubeConfigAndClient(clusterID string, elevationReasons ...string) (client.Client, *rest.Config, *kubernetes.Clientset, error) {
-	bp, err := bpconfig.GetBackplaneConfiguration()
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to load backplane-cli config: %v", err)
-	}
-	var kubeconfig *rest.Config
-	if len(elevationReasons) == 0 {
-		kubeconfig, err = bplogin.GetRestConfig(bp, clusterID)
-	} else {
-		kubeconfig, err = bplogin.GetRestConfigAsUser(bp, clusterID, "backplane-cluster-admin", elevationReasons...)
-	}
+	kubeconfig, err := k8s.NewClientFactory().WithElevationReasons(elevationReasons...).RestConfig(clusterID)
 	if err != nil {
 		return nil, nil, nil, err
 	}
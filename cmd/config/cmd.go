@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCmdConfig implements the config command
+func NewCmdConfig() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Share and inspect the osdctl configuration",
+		Long: `Share and inspect the osdctl configuration.
+
+On top of the user config (~/.config/osdctl), osdctl reads a read-only team config, whose settings are used
+as defaults for the ones missing from the user config. This lets a team maintain its team_ids, JQL templates
+or SOP mappings in a single place. The team config is read from, in order:
+  - the OSDCTL_TEAM_CONFIG environment variable
+  - the team_config key of the user config
+  - /etc/osdctl/config
+It can be a local path or an http(s) URL, e.g. the raw URL of a file in a git repository.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println("Error calling cmd.Help(): ", err.Error())
+				return
+			}
+		},
+	}
+
+	configCmd.AddCommand(newCmdExport())
+	configCmd.AddCommand(newCmdImport())
+	configCmd.AddCommand(newCmdTeam())
+
+	return configCmd
+}
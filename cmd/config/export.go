@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type exportOptions struct {
+	noSecrets  bool
+	outputFile string
+}
+
+func newCmdExport() *cobra.Command {
+	ops := &exportOptions{}
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the user config",
+		Long: `Export the user config as yaml, e.g. to share it with a teammate or to seed a team config.
+The team defaults aren't included. Use --no-secrets to leave the tokens and other credentials out.`,
+		Example: `  # Share your config without your tokens
+  osdctl config export --no-secrets -f osdctl-config.yaml`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	exportCmd.Flags().BoolVar(&ops.noSecrets, "no-secrets", false, "Leave the tokens and other credentials out of the export")
+	exportCmd.Flags().StringVarP(&ops.outputFile, "output-file", "f", "", "File to export the config to, defaults to stdout")
+
+	return exportCmd
+}
+
+func (o *exportOptions) run() error {
+	settings, err := osdctlConfig.ReadUserConfig()
+	if err != nil {
+		return err
+	}
+	if o.noSecrets {
+		settings = osdctlConfig.WithoutSecrets(settings)
+	}
+
+	var out io.Writer = os.Stdout
+	if o.outputFile != "" {
+		// The export can contain credentials
+		f, err := os.OpenFile(o.outputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return writeSettings(out, settings)
+}
+
+func writeSettings(out io.Writer, settings map[string]interface{}) error {
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(settings); err != nil {
+		return fmt.Errorf("failed to encode the config: %w", err)
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type importOptions struct {
+	file      string
+	overwrite bool
+	yes       bool
+}
+
+func newCmdImport() *cobra.Command {
+	ops := &importOptions{}
+	importCmd := &cobra.Command{
+		Use:   "import <file|->",
+		Short: "Import settings into the user config",
+		Long: `Import the settings of a yaml file, e.g. made with 'osdctl config export', into the user config.
+Settings already present in the user config are kept unless --overwrite is given.`,
+		Example: `  osdctl config import osdctl-config.yaml
+
+  # Replace the settings already present
+  osdctl config import osdctl-config.yaml --overwrite`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.file = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	importCmd.Flags().BoolVar(&ops.overwrite, "overwrite", false, "Replace the settings already present in the user config")
+	importCmd.Flags().BoolVarP(&ops.yes, "yes", "y", false, "Don't ask for confirmation")
+
+	return importCmd
+}
+
+func (o *importOptions) run() error {
+	var content []byte
+	var err error
+	if o.file == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(o.file)
+	}
+	if err != nil {
+		return err
+	}
+
+	imported := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &imported); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", o.file, err)
+	}
+
+	current, err := osdctlConfig.ReadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	changes, skipped := planImport(current, imported, o.overwrite)
+	for _, key := range skipped {
+		fmt.Printf("Keeping %s, already set (use --overwrite to replace it)\n", key)
+	}
+	if len(changes) == 0 {
+		fmt.Println("Nothing to import")
+		return nil
+	}
+
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("Setting %s\n", key)
+	}
+
+	if !o.yes && !utils.ConfirmPrompt() {
+		return nil
+	}
+	if err := osdctlConfig.WriteUserConfig(changes); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d settings\n", len(changes))
+	return nil
+}
+
+// planImport returns the imported settings to write, and the keys kept as they're already set
+func planImport(current map[string]interface{}, imported map[string]interface{}, overwrite bool) (map[string]interface{}, []string) {
+	changes := map[string]interface{}{}
+	var skipped []string
+	for key, value := range imported {
+		existing, found := current[key]
+		if found && reflect.DeepEqual(existing, value) {
+			continue
+		}
+		if found && !overwrite {
+			skipped = append(skipped, key)
+			continue
+		}
+		changes[key] = value
+	}
+	sort.Strings(skipped)
+	return changes, skipped
+}
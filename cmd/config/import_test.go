@@ -0,0 +1,47 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanImport(t *testing.T) {
+	current := map[string]interface{}{
+		"jira_token": "abc",
+		"team_ids":   []interface{}{"PTEAM"},
+	}
+	imported := map[string]interface{}{
+		"jira_token":    "def",
+		"team_ids":      []interface{}{"PTEAM"},
+		"pd_user_token": "ghi",
+	}
+
+	tests := []struct {
+		name        string
+		overwrite   bool
+		wantChanges map[string]interface{}
+		wantSkipped []string
+	}{
+		{
+			name:        "keeps existing settings",
+			wantChanges: map[string]interface{}{"pd_user_token": "ghi"},
+			wantSkipped: []string{"jira_token"},
+		},
+		{
+			name:        "overwrites existing settings",
+			overwrite:   true,
+			wantChanges: map[string]interface{}{"pd_user_token": "ghi", "jira_token": "def"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, skipped := planImport(current, imported, tt.overwrite)
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Errorf("changes = %v, want %v", changes, tt.wantChanges)
+			}
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+func newCmdTeam() *cobra.Command {
+	var refresh bool
+	teamCmd := &cobra.Command{
+		Use:   "team",
+		Short: "Show where the team config is read from",
+		Long: `Show where the team config is read from.
+A remote team config is cached for a day, use --refresh to fetch it again on the next run.`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if refresh {
+				cmdutil.CheckErr(osdctlConfig.RefreshTeamConfig())
+				fmt.Println("The team config will be fetched again on the next run")
+				return
+			}
+			if source := osdctlConfig.TeamConfigSource(); source != "" {
+				fmt.Printf("Team config: %s\n", source)
+				return
+			}
+			fmt.Printf("No team config, set %s or the '%s' config key to use one\n", osdctlConfig.TeamConfigEnvVar, osdctlConfig.TeamConfigKey)
+		},
+	}
+
+	teamCmd.Flags().BoolVar(&refresh, "refresh", false, "Drop the cached copy of a remote team config")
+
+	return teamCmd
+}
@@ -0,0 +1,17 @@
+package explain
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdExplain returns the explain command
+func NewCmdExplain() *cobra.Command {
+	explainCmd := &cobra.Command{
+		Use:               "explain",
+		Short:             "Explain the errors returned by the services osdctl talks to",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	explainCmd.AddCommand(newCmdOCMError())
+	return explainCmd
+}
@@ -0,0 +1,62 @@
+package explain
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openshift/osdctl/pkg/ocmerror"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+func newCmdOCMError() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ocm-error [code]",
+		Short: "Explain an OCM error code and what to do about it",
+		Long: fmt.Sprintf(`Explain an error code returned by OCM, e.g. CLUSTERS-MGMT-400 or the OCM3xxx code of a cluster which
+failed to install, with what to do about it and the SOP to follow when one is known. Without a code, the
+known explanations are listed.
+
+The explanations are also printed after the errors of every osdctl command mentioning an OCM error code.
+The API codes are explained by the HTTP status they're named after. Explanations of specific codes, and
+their SOPs, can be added in the osdctl config or the team config as '%s':
+
+  %s:
+    OCM3055:
+      summary: The installer couldn't reach the AWS API
+      guidance: Check the egress of the cluster's VPC
+      sop: https://example.com/sop.md`, ocmerror.GuidanceConfigKey, ocmerror.GuidanceConfigKey),
+		Example: `  osdctl explain ocm-error CLUSTERS-MGMT-400
+
+  # List the known explanations
+  osdctl explain ocm-error`,
+		Args:              cobra.MaximumNArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				cmdutil.CheckErr(printKnownOCMErrors())
+				return
+			}
+			cmdutil.CheckErr(explainOCMError(args[0]))
+		},
+	}
+}
+
+func explainOCMError(code string) error {
+	explanation, ok := ocmerror.Explain(code)
+	if !ok {
+		return fmt.Errorf("no explanation for '%s', add one to '%s' in the osdctl config", code, ocmerror.GuidanceConfigKey)
+	}
+	fmt.Print(ocmerror.Format(explanation))
+	return nil
+}
+
+func printKnownOCMErrors() error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CODE", "SUMMARY", "SOP"})
+	for _, explanation := range ocmerror.Known() {
+		table.AddRow([]string{explanation.Code, explanation.Summary, explanation.SOP})
+	}
+	return table.Flush()
+}
@@ -0,0 +1,17 @@
+package fleet
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmdFleet returns the fleet command
+func NewCmdFleet() *cobra.Command {
+	fleetCmd := &cobra.Command{
+		Use:               "fleet",
+		Short:             "Views over groups of clusters",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+	fleetCmd.AddCommand(newCmdStatus())
+	return fleetCmd
+}
@@ -0,0 +1,352 @@
+package fleet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/printer"
+	pdProvider "github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// StatusMaxConcurrency is the number of clusters queried in parallel by 'fleet status'
+	StatusMaxConcurrency = 10
+
+	groupByRegion  = "region"
+	groupByCloud   = "cloud"
+	groupByProduct = "product"
+	groupByVersion = "version"
+	groupByLabel   = "label:"
+
+	// noGroup is the group of the clusters missing the grouping attribute, e.g. the label
+	noGroup = "<none>"
+)
+
+type statusOptions struct {
+	queries []string
+	groupBy string
+	alerts  bool
+	output  string
+}
+
+// fleetCluster is the health of a cluster of the fleet
+type fleetCluster struct {
+	ID             string
+	Group          string
+	Version        string
+	LimitedSupport bool
+	HighAlerts     int
+	LowAlerts      int
+	Failed         bool
+}
+
+// fleetGroup rolls up the health of the clusters of a group
+type fleetGroup struct {
+	Name           string         `json:"name"`
+	Clusters       int            `json:"clusters"`
+	Versions       map[string]int `json:"versions"`
+	LimitedSupport int            `json:"limited_support"`
+	HighAlerts     int            `json:"high_alerts"`
+	LowAlerts      int            `json:"low_alerts"`
+	Failed         int            `json:"failed"`
+}
+
+func newCmdStatus() *cobra.Command {
+	ops := &statusOptions{}
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Roll up the health of the clusters matching a query, by group",
+		Long: `Group the clusters matching the OCM search queries and roll up their health per group: the distribution of
+their versions, the number of clusters in limited support and the PagerDuty alerts firing for them.
+
+The clusters can be grouped by region, cloud, product, version (major.minor) or by any OCM subscription label
+with label:<key>, e.g. label:sector for the rollout sector the clusters are labelled with. The clusters without
+the label are grouped under ` + noGroup + `.`,
+		Example: `  # Roll up the ready ROSA clusters by region
+  osdctl fleet status -q "product.id = 'rosa'" -q "state = 'ready'"
+
+  # Roll up the clusters of an organization by sector, without the PagerDuty alerts
+  osdctl fleet status -q "organization.id = '<org-id>'" --group-by label:sector --alerts=false`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	statusCmd.Flags().StringArrayVarP(&ops.queries, "query", "q", []string{}, "OCM search query selecting the clusters, repeat it to AND several queries")
+	statusCmd.Flags().StringVar(&ops.groupBy, "group-by", groupByRegion, "Group the clusters by 'region', 'cloud', 'product', 'version' or 'label:<key>'")
+	statusCmd.Flags().BoolVar(&ops.alerts, "alerts", true, "Count the PagerDuty alerts firing for the clusters")
+	statusCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Valid formats are ['table', 'json']")
+
+	return statusCmd
+}
+
+func (o *statusOptions) validate() error {
+	if len(o.queries) == 0 {
+		return fmt.Errorf("select the clusters with at least one --query")
+	}
+	switch o.groupBy {
+	case groupByRegion, groupByCloud, groupByProduct, groupByVersion:
+	default:
+		if !strings.HasPrefix(o.groupBy, groupByLabel) || strings.TrimPrefix(o.groupBy, groupByLabel) == "" {
+			return fmt.Errorf("invalid --group-by '%s', valid values are 'region', 'cloud', 'product', 'version' and 'label:<key>'", o.groupBy)
+		}
+	}
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format '%s', valid formats are 'table' and 'json'", o.output)
+	}
+	return nil
+}
+
+func (o *statusOptions) run() error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	clusters, err := utils.ApplyFilters(connection, o.queries)
+	if err != nil {
+		return fmt.Errorf("failed to search the clusters: %w", err)
+	}
+	if len(clusters) == 0 {
+		fmt.Println("No clusters match the query")
+		return nil
+	}
+
+	clustersByID := make(map[string]*cmv1.Cluster, len(clusters))
+	clusterIDs := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		clustersByID[cluster.ID()] = cluster
+		clusterIDs = append(clusterIDs, cluster.ID())
+	}
+
+	var (
+		mutex  sync.Mutex
+		health = make([]fleetCluster, 0, len(clusters))
+	)
+	fanOutErr := utils.FanOut(clusterIDs, utils.FanOutOptions{
+		MaxConcurrency: StatusMaxConcurrency,
+		Progress:       os.Stderr,
+		Action:         "Fetched the health",
+		Noun:           "clusters",
+	}, func(clusterID string) error {
+		clusterHealth, err := o.clusterHealth(connection, clustersByID[clusterID])
+		mutex.Lock()
+		health = append(health, clusterHealth)
+		mutex.Unlock()
+		return err
+	})
+	if fanOutErr != nil {
+		// the clusters that failed are counted in their group, the roll-up of the others is still printed
+		fmt.Fprintf(os.Stderr, "%v\n", fanOutErr)
+	}
+
+	groups := rollUp(health)
+	if o.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(groups)
+	}
+	return printGroups(groups, o.alerts)
+}
+
+// clusterHealth fetches the health of the cluster. The cluster is returned even when it fails, marked as
+// failed with what could be fetched.
+func (o *statusOptions) clusterHealth(connection *sdk.Connection, cluster *cmv1.Cluster) (fleetCluster, error) {
+	health := fleetCluster{
+		ID:      cluster.ID(),
+		Version: cluster.OpenshiftVersion(),
+	}
+
+	var errs []error
+	group, err := o.clusterGroup(connection, cluster)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	health.Group = group
+
+	limitedSupportReasons, err := utils.GetClusterLimitedSupportReasons(connection, cluster.ID())
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get the limited support reasons: %w", err))
+	}
+	health.LimitedSupport = len(limitedSupportReasons) > 0
+
+	if o.alerts {
+		health.HighAlerts, health.LowAlerts, err = firingAlerts(cluster)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	health.Failed = len(errs) > 0
+	return health, errors.Join(errs...)
+}
+
+// clusterGroup returns the group of the cluster, the labels are only fetched when grouping by label
+func (o *statusOptions) clusterGroup(connection *sdk.Connection, cluster *cmv1.Cluster) (string, error) {
+	labelKey, byLabel := strings.CutPrefix(o.groupBy, groupByLabel)
+	if !byLabel {
+		return groupKey(cluster, o.groupBy), nil
+	}
+
+	response, err := connection.AccountsMgmt().V1().Subscriptions().Subscription(cluster.Subscription().ID()).Labels().List().Send()
+	if err != nil {
+		return noGroup, fmt.Errorf("failed to get the labels: %w", err)
+	}
+	for _, label := range response.Items().Slice() {
+		if label.Key() == labelKey {
+			return label.Value(), nil
+		}
+	}
+	return noGroup, nil
+}
+
+// groupKey returns the group of the cluster for the attributes of the cluster itself
+func groupKey(cluster *cmv1.Cluster, groupBy string) string {
+	var key string
+	switch groupBy {
+	case groupByRegion:
+		key = cluster.Region().ID()
+	case groupByCloud:
+		key = cluster.CloudProvider().ID()
+	case groupByProduct:
+		key = cluster.Product().ID()
+		if cluster.Hypershift().Enabled() {
+			key += " (hcp)"
+		}
+	case groupByVersion:
+		key = minorVersion(cluster.OpenshiftVersion())
+	}
+	if key == "" {
+		return noGroup
+	}
+	return key
+}
+
+// minorVersion returns the major.minor of an OpenShift version, e.g. 4.14 for 4.14.12
+func minorVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// firingAlerts counts the high and low urgency PagerDuty alerts firing for the cluster
+func firingAlerts(cluster *cmv1.Cluster) (high int, low int, err error) {
+	pdClient, err := pdProvider.NewClient().
+		WithBaseDomain(cluster.DNS().BaseDomain()).
+		WithUserToken(viper.GetString(pdProvider.PagerDutyUserTokenConfigKey)).
+		WithOauthToken(viper.GetString(pdProvider.PagerDutyOauthTokenConfigKey)).
+		Init()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to build the PagerDuty client: %w", err)
+	}
+	serviceIDs, err := pdClient.GetPDServiceIDs()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get the PagerDuty services: %w", err)
+	}
+	alerts, err := pdClient.GetFiringAlertsForCluster(serviceIDs)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get the PagerDuty alerts: %w", err)
+	}
+	for _, incidents := range alerts {
+		for _, incident := range incidents {
+			if incident.Urgency == "high" {
+				high++
+			} else {
+				low++
+			}
+		}
+	}
+	return high, low, nil
+}
+
+// rollUp groups the clusters and sums up their health, by group name
+func rollUp(clusters []fleetCluster) []fleetGroup {
+	groupsByName := map[string]*fleetGroup{}
+	for _, cluster := range clusters {
+		group, ok := groupsByName[cluster.Group]
+		if !ok {
+			group = &fleetGroup{Name: cluster.Group, Versions: map[string]int{}}
+			groupsByName[cluster.Group] = group
+		}
+		group.Clusters++
+		if cluster.Version != "" {
+			group.Versions[cluster.Version]++
+		}
+		if cluster.LimitedSupport {
+			group.LimitedSupport++
+		}
+		group.HighAlerts += cluster.HighAlerts
+		group.LowAlerts += cluster.LowAlerts
+		if cluster.Failed {
+			group.Failed++
+		}
+	}
+
+	groups := make([]fleetGroup, 0, len(groupsByName))
+	for _, group := range groupsByName {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// formatVersions returns the versions of a group by decreasing number of clusters, e.g. "4.14.12 x3, 4.15.2 x1"
+func formatVersions(versions map[string]int) string {
+	names := make([]string, 0, len(versions))
+	for version := range versions {
+		names = append(names, version)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if versions[names[i]] != versions[names[j]] {
+			return versions[names[i]] > versions[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	formatted := make([]string, 0, len(names))
+	for _, version := range names {
+		formatted = append(formatted, fmt.Sprintf("%s x%d", version, versions[version]))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func printGroups(groups []fleetGroup, alerts bool) error {
+	header := []string{"GROUP", "CLUSTERS", "LIMITED SUPPORT"}
+	if alerts {
+		header = append(header, "HIGH ALERTS", "LOW ALERTS")
+	}
+	header = append(header, "FAILED", "VERSIONS")
+
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow(header)
+	for _, group := range groups {
+		row := []string{group.Name, strconv.Itoa(group.Clusters), strconv.Itoa(group.LimitedSupport)}
+		if alerts {
+			row = append(row, strconv.Itoa(group.HighAlerts), strconv.Itoa(group.LowAlerts))
+		}
+		row = append(row, strconv.Itoa(group.Failed), formatVersions(group.Versions))
+		table.AddRow(row)
+	}
+	table.AddRow([]string{})
+	return table.Flush()
+}
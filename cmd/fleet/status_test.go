@@ -0,0 +1,92 @@
+package fleet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRollUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		clusters []fleetCluster
+		want     []fleetGroup
+	}{
+		{
+			name: "no clusters",
+			want: []fleetGroup{},
+		},
+		{
+			name: "clusters are summed up by group",
+			clusters: []fleetCluster{
+				{ID: "a", Group: "us-east-1", Version: "4.14.12", LimitedSupport: true, HighAlerts: 2, LowAlerts: 1},
+				{ID: "b", Group: "eu-west-1", Version: "4.15.2"},
+				{ID: "c", Group: "us-east-1", Version: "4.14.12", LowAlerts: 3},
+				{ID: "d", Group: "us-east-1", Version: "4.15.2", Failed: true},
+			},
+			want: []fleetGroup{
+				{Name: "eu-west-1", Clusters: 1, Versions: map[string]int{"4.15.2": 1}},
+				{Name: "us-east-1", Clusters: 3, Versions: map[string]int{"4.14.12": 2, "4.15.2": 1}, LimitedSupport: 1, HighAlerts: 2, LowAlerts: 4, Failed: 1},
+			},
+		},
+		{
+			name: "clusters without a version are counted",
+			clusters: []fleetCluster{
+				{ID: "a", Group: noGroup, Failed: true},
+			},
+			want: []fleetGroup{
+				{Name: noGroup, Clusters: 1, Versions: map[string]int{}, Failed: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollUp(tt.clusters); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rollUp() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions map[string]int
+		want     string
+	}{
+		{
+			name: "no versions",
+			want: "",
+		},
+		{
+			name:     "most common versions first",
+			versions: map[string]int{"4.15.2": 1, "4.14.12": 3, "4.13.30": 1},
+			want:     "4.14.12 x3, 4.13.30 x1, 4.15.2 x1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatVersions(tt.versions); got != tt.want {
+				t.Errorf("formatVersions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinorVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "4.14.12", want: "4.14"},
+		{version: "4.15.0-rc.1", want: "4.15"},
+		{version: "4", want: "4"},
+		{version: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := minorVersion(tt.version); got != tt.want {
+				t.Errorf("minorVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package grep
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/rawdump"
+)
+
+// The sources of the collected artifacts
+const (
+	// SourceCloudTrail are the NDJSON files of 'osdctl cloudtrail collect', one event per line
+	SourceCloudTrail = "cloudtrail"
+	// SourceRaw are the responses saved with --save-raw, as raw/<collector>
+	SourceRaw = "raw"
+	// SourceMustGather are the files of a must-gather
+	SourceMustGather = "must-gather"
+	// SourceFile are the other files
+	SourceFile = "file"
+
+	// cloudTrailStateFile is the state 'osdctl cloudtrail collect' saves along with the events
+	cloudTrailStateFile = "state.json"
+	// mustGatherTimestampFile is written at the root of every must-gather
+	mustGatherTimestampFile = "timestamp"
+	// maxLineSize bounds the lines read, the CloudTrail events can be long
+	maxLineSize = 16 * 1024 * 1024
+)
+
+var (
+	// lineTimeRE matches the timestamps most logs start their lines with, after an optional level or prefix
+	lineTimeRE = regexp.MustCompile(`^\W{0,3}\w{0,8}\W{0,3}(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?)`)
+	// clusterURLRE matches the cluster in the URL of an OCM request
+	clusterURLRE = regexp.MustCompile(`/clusters/([a-zA-Z0-9-]+)`)
+	// clusterVersionIDRE matches the cluster ID of the ClusterVersion of a must-gather
+	clusterVersionIDRE = regexp.MustCompile(`(?m)^\s*clusterID:\s*"?([a-f0-9-]{36})"?\s*$`)
+
+	lineTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999Z0700", "2006-01-02 15:04:05.999999999"}
+)
+
+// Record is a line of a collected artifact
+type Record struct {
+	Source  string    `json:"source"`
+	File    string    `json:"file"`
+	Line    int       `json:"line"`
+	Time    time.Time `json:"time"`
+	Cluster string    `json:"cluster,omitempty"`
+	Text    string    `json:"text"`
+}
+
+// artifact is a file and what is known about its lines before reading it
+type artifact struct {
+	path    string
+	source  string
+	cluster string
+	// time is the time of all the lines of the file, e.g. when a raw response was received
+	time time.Time
+}
+
+// rootInfo is what the marker files of a collection directory tell about the files below it
+type rootInfo struct {
+	source  string
+	cluster string
+	// rawIndex are the entries of the --save-raw index by file, relative to the root
+	rawIndex map[string]rawdump.IndexEntry
+}
+
+// findArtifacts walks the directories and returns their files with their source, in lexical order
+func findArtifacts(paths []string) ([]artifact, error) {
+	roots := map[string]*rootInfo{}
+	var artifacts []artifact
+	for _, path := range paths {
+		// The collection directories are looked up to the searched directory, not above it
+		top := filepath.Clean(path)
+		if info, err := os.Stat(top); err == nil && !info.IsDir() {
+			top = filepath.Dir(top)
+		}
+		err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || skipFile(entry.Name()) {
+				return nil
+			}
+			artifacts = append(artifacts, classify(file, top, roots))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return artifacts, nil
+}
+
+// skipFile returns true for the metadata and signatures of the collections, which aren't evidence
+func skipFile(name string) bool {
+	switch name {
+	case rawdump.IndexFile, cloudTrailStateFile, mustGatherTimestampFile:
+		return true
+	}
+	return strings.HasSuffix(name, ".sig") || strings.HasSuffix(name, ".asc")
+}
+
+// classify returns the artifact of the file, from the collection directory it belongs to. The directories
+// are looked up from the one of the file to the top one.
+func classify(file string, top string, roots map[string]*rootInfo) artifact {
+	a := artifact{path: file, source: SourceFile}
+	name := strings.TrimSuffix(filepath.Base(file), ".gz")
+	for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+		info := rootAt(dir, roots)
+		if info != nil {
+			switch info.source {
+			case SourceRaw:
+				relative, _ := filepath.Rel(dir, file)
+				entry := info.rawIndex[filepath.ToSlash(relative)]
+				a.source = SourceRaw + "/" + strings.SplitN(filepath.ToSlash(relative), "/", 2)[0]
+				a.time = entry.Time
+				if match := clusterURLRE.FindStringSubmatch(entry.URL); match != nil {
+					a.cluster = match[1]
+				}
+				return a
+			case SourceCloudTrail:
+				if strings.HasSuffix(name, ".ndjson") {
+					a.source, a.cluster = SourceCloudTrail, info.cluster
+					return a
+				}
+			case SourceMustGather:
+				a.source, a.cluster = SourceMustGather, info.cluster
+				return a
+			}
+		}
+		if parent := filepath.Dir(dir); parent == dir || dir == top {
+			break
+		}
+	}
+	if strings.HasSuffix(name, ".ndjson") {
+		a.source = SourceCloudTrail
+	}
+	return a
+}
+
+// rootAt returns what the directory is the root of, nil when it's not the root of a collection
+func rootAt(dir string, roots map[string]*rootInfo) *rootInfo {
+	if info, ok := roots[dir]; ok {
+		return info
+	}
+	var info *rootInfo
+	if index, err := readRawIndex(filepath.Join(dir, rawdump.IndexFile)); err == nil {
+		info = &rootInfo{source: SourceRaw, rawIndex: index}
+	} else if content, err := os.ReadFile(filepath.Join(dir, cloudTrailStateFile)); err == nil {
+		var state struct {
+			ClusterID string `json:"cluster_id"`
+		}
+		if json.Unmarshal(content, &state) == nil {
+			info = &rootInfo{source: SourceCloudTrail, cluster: state.ClusterID}
+		}
+	} else if _, err := os.Stat(filepath.Join(dir, mustGatherTimestampFile)); err == nil || strings.HasPrefix(filepath.Base(dir), "must-gather") {
+		info = &rootInfo{source: SourceMustGather, cluster: mustGatherClusterID(dir)}
+	}
+	roots[dir] = info
+	return info
+}
+
+func readRawIndex(path string) (map[string]rawdump.IndexEntry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	index := map[string]rawdump.IndexEntry{}
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		var entry rawdump.IndexEntry
+		if len(bytes.TrimSpace(line)) == 0 || json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		index[filepath.ToSlash(entry.File)] = entry
+	}
+	return index, nil
+}
+
+// mustGatherClusterID returns the cluster ID of the ClusterVersion gathered below the directory, if any
+func mustGatherClusterID(dir string) string {
+	var clusterID string
+	_ = filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if clusterID != "" {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() || !strings.Contains(filepath.ToSlash(file), "clusterversions") {
+			return nil
+		}
+		if content, err := os.ReadFile(file); err == nil {
+			if match := clusterVersionIDRE.FindSubmatch(content); match != nil {
+				clusterID = string(match[1])
+			}
+		}
+		return nil
+	})
+	return clusterID
+}
+
+// readRecords calls fn with each line of the artifact, until it returns false. Binary files are skipped.
+func readRecords(a artifact, fn func(Record) bool) error {
+	file, err := os.Open(a.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(a.path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	buffered := bufio.NewReader(reader)
+	if head, _ := buffered.Peek(8000); bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	scanner := bufio.NewScanner(buffered)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for number := 1; scanner.Scan(); number++ {
+		record := Record{Source: a.source, File: a.path, Line: number, Time: a.time, Cluster: a.cluster, Text: scanner.Text()}
+		if a.source == SourceCloudTrail {
+			record.Time = eventTime(record.Text)
+		} else if record.Time.IsZero() {
+			record.Time = lineTime(record.Text)
+		}
+		if !fn(record) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// eventTime returns the time of a CloudTrail event
+func eventTime(line string) time.Time {
+	var event struct {
+		EventTime time.Time `json:"eventTime"`
+	}
+	_ = json.Unmarshal([]byte(line), &event)
+	return event.EventTime
+}
+
+// lineTime returns the time a log line starts with, zero when it doesn't start with one
+func lineTime(line string) time.Time {
+	match := lineTimeRE.FindStringSubmatch(line)
+	if match == nil {
+		return time.Time{}
+	}
+	for _, layout := range lineTimeLayouts {
+		if t, err := time.Parse(layout, match[1]); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
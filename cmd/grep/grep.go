@@ -0,0 +1,220 @@
+package grep
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type grepOptions struct {
+	pattern    string
+	paths      []string
+	ignoreCase bool
+	since      string
+	until      string
+	sources    []string
+	cluster    string
+	output     string
+}
+
+// filter selects the records of the search
+type filter struct {
+	pattern *regexp.Regexp
+	since   time.Time
+	until   time.Time
+	sources []string
+	cluster string
+}
+
+// NewCmdGrep returns the grep command
+func NewCmdGrep() *cobra.Command {
+	ops := &grepOptions{}
+	grepCmd := &cobra.Command{
+		Use:   "grep <pattern> [directory...]",
+		Short: "Search the artifacts collected during an investigation",
+		Long: `Search the lines matching a regular expression in the artifacts collected during an investigation: the
+responses saved with --save-raw, the CloudTrail events downloaded by 'osdctl cloudtrail collect', must-gathers and
+any other file, gzipped or not. The current directory is searched when no directory is given.
+
+Every line is tagged with its source (raw/<collector>, cloudtrail, must-gather or file), its time and its cluster
+when they are known, so the evidence scattered across the files can be filtered uniformly:
+  - the time of a CloudTrail event is its eventTime, the time of a raw response when it was received, the time
+    of the other lines the timestamp they start with. --since and --until leave out the lines without a time.
+  - the cluster of the CloudTrail events is the one they were collected for, the cluster of a raw response the
+    one in its URL, the cluster of a must-gather the ID of its ClusterVersion. --cluster also matches the lines
+    naming the cluster.`,
+		Example: `  # Search the security group changes across everything collected in ./investigation
+  osdctl grep 'AuthorizeSecurityGroupIngress|sg-0123' ./investigation
+
+  # Search the errors of the last day in the must-gathers and the raw responses of a cluster
+  osdctl grep -i 'error' ./investigation --source must-gather --source raw --since 24h --cluster <cluster-id>`,
+		Args:              cobra.MinimumNArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.pattern = args[0]
+			ops.paths = args[1:]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	grepCmd.Flags().BoolVarP(&ops.ignoreCase, "ignore-case", "i", false, "Match the pattern case-insensitively")
+	grepCmd.Flags().StringVar(&ops.since, "since", "", "Only show the lines from this time on, a duration back from now (e.g. 24h, 7d) or an RFC3339 time")
+	grepCmd.Flags().StringVar(&ops.until, "until", "", "Only show the lines up to this time, a duration back from now (e.g. 1h) or an RFC3339 time")
+	grepCmd.Flags().StringArrayVar(&ops.sources, "source", []string{}, fmt.Sprintf("Only search this source, one of %s, %s (or %s/<collector>), %s and %s. Can be repeated", SourceCloudTrail, SourceRaw, SourceRaw, SourceMustGather, SourceFile))
+	grepCmd.Flags().StringVarP(&ops.cluster, "cluster", "C", "", "Only show the lines of this cluster, or naming it, by the ID the artifacts know it by")
+	grepCmd.Flags().StringVarP(&ops.output, "output", "o", "text", "Valid formats are ['text', 'json']")
+
+	return grepCmd
+}
+
+func (o *grepOptions) run() error {
+	f, err := o.filter(time.Now())
+	if err != nil {
+		return err
+	}
+	if len(o.paths) == 0 {
+		o.paths = []string{"."}
+	}
+	artifacts, err := findArtifacts(o.paths)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	matches := 0
+	for _, a := range artifacts {
+		if !f.matchesSource(a.source) {
+			continue
+		}
+		err := readRecords(a, func(record Record) bool {
+			if !f.matches(record) {
+				return true
+			}
+			matches++
+			if o.output == "json" {
+				return encoder.Encode(newJSONRecord(record)) == nil
+			}
+			fmt.Println(formatRecord(record))
+			return true
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", a.path, err)
+		}
+	}
+	if matches == 0 {
+		fmt.Fprintln(os.Stderr, "No matching lines")
+	}
+	return nil
+}
+
+func (o *grepOptions) filter(now time.Time) (*filter, error) {
+	if o.output != "text" && o.output != "json" {
+		return nil, fmt.Errorf("invalid output format '%s', valid formats are 'text' and 'json'", o.output)
+	}
+	pattern := o.pattern
+	if o.ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	for _, source := range o.sources {
+		switch strings.SplitN(source, "/", 2)[0] {
+		case SourceCloudTrail, SourceRaw, SourceMustGather, SourceFile:
+		default:
+			return nil, fmt.Errorf("unknown source '%s', expected one of %s, %s, %s/<collector>, %s and %s", source, SourceCloudTrail, SourceRaw, SourceRaw, SourceMustGather, SourceFile)
+		}
+	}
+
+	f := &filter{pattern: re, sources: o.sources, cluster: o.cluster}
+	if f.since, err = parseTime(o.since, now); err != nil {
+		return nil, fmt.Errorf("invalid --since: %w", err)
+	}
+	if f.until, err = parseTime(o.until, now); err != nil {
+		return nil, fmt.Errorf("invalid --until: %w", err)
+	}
+	return f, nil
+}
+
+// parseTime parses an RFC3339 time or a duration back from now, zero when the value is empty
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	duration, err := ctUtil.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is neither an RFC3339 time nor a duration", value)
+	}
+	return now.Add(-duration), nil
+}
+
+// matchesSource returns true if the source is searched, raw matches the sources of all the collectors
+func (f *filter) matchesSource(source string) bool {
+	if len(f.sources) == 0 {
+		return true
+	}
+	for _, wanted := range f.sources {
+		if source == wanted || strings.HasPrefix(source, wanted+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *filter) matches(record Record) bool {
+	if !f.matchesSource(record.Source) {
+		return false
+	}
+	if !f.since.IsZero() || !f.until.IsZero() {
+		if record.Time.IsZero() {
+			return false
+		}
+		if !f.since.IsZero() && record.Time.Before(f.since) {
+			return false
+		}
+		if !f.until.IsZero() && record.Time.After(f.until) {
+			return false
+		}
+	}
+	if f.cluster != "" && record.Cluster != f.cluster && !strings.Contains(record.Text, f.cluster) {
+		return false
+	}
+	return f.pattern.MatchString(record.Text)
+}
+
+// jsonRecord leaves the time of the record out when it's unknown
+type jsonRecord struct {
+	Record
+	Time *time.Time `json:"time,omitempty"`
+}
+
+func newJSONRecord(record Record) jsonRecord {
+	out := jsonRecord{Record: record}
+	if !record.Time.IsZero() {
+		out.Time = &record.Time
+	}
+	return out
+}
+
+// formatRecord prints the record as grep does, prefixed with its source, time and cluster
+func formatRecord(record Record) string {
+	tags := []string{record.Source}
+	if !record.Time.IsZero() {
+		tags = append(tags, record.Time.UTC().Format(time.RFC3339))
+	}
+	if record.Cluster != "" {
+		tags = append(tags, record.Cluster)
+	}
+	return fmt.Sprintf("%s:%d: [%s] %s", record.File, record.Line, strings.Join(tags, " "), record.Text)
+}
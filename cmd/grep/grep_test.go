@@ -0,0 +1,114 @@
+package grep
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	clusterUUID := "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"
+
+	writeFile(t, filepath.Join(dir, "raw", "index.jsonl"), `{"sequence":1,"time":"2024-05-01T10:00:00Z","collector":"service-logs","url":"https://api.openshift.com/api/service_logs/v1/clusters/2abc/cluster_logs","file":"service-logs/001-GET-api.json"}`+"\n")
+	writeFile(t, filepath.Join(dir, "raw", "service-logs", "001-GET-api.json"), "{\n  \"summary\": \"Security group sg-0123 modified\"\n}\n")
+	writeFile(t, filepath.Join(dir, "events", "state.json"), `{"cluster_id":"2abc"}`)
+	writeFile(t, filepath.Join(dir, "events", "us-east-1.ndjson"),
+		`{"eventTime":"2024-05-01T09:00:00Z","eventName":"AuthorizeSecurityGroupIngress","requestParameters":{"groupId":"sg-0123"}}`+"\n"+
+			`{"eventTime":"2024-04-01T09:00:00Z","eventName":"RevokeSecurityGroupIngress","requestParameters":{"groupId":"sg-0123"}}`+"\n")
+	writeFile(t, filepath.Join(dir, "must-gather.local.1", "timestamp"), "2024-05-01\n")
+	writeFile(t, filepath.Join(dir, "must-gather.local.1", "cluster-scoped-resources", "config.openshift.io", "clusterversions", "version.yaml"), "spec:\n  clusterID: "+clusterUUID+"\n")
+	writeFile(t, filepath.Join(dir, "must-gather.local.1", "namespaces", "openshift-ingress", "router.log"), "2024-05-01T11:00:00.123Z error: sg-0123 unreachable\nno time sg-0123\n")
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = writer.Write([]byte("I0501 sg-0123 in a gzipped note\n"))
+	_ = writer.Close()
+	writeFile(t, filepath.Join(dir, "notes.txt.gz"), compressed.String())
+
+	search := func(o grepOptions) []string {
+		t.Helper()
+		o.pattern = "sg-0123"
+		if o.output == "" {
+			o.output = "text"
+		}
+		f, err := o.filter(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		artifacts, err := findArtifacts([]string{dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var found []string
+		for _, a := range artifacts {
+			err := readRecords(a, func(record Record) bool {
+				if f.matches(record) {
+					relative, _ := filepath.Rel(dir, record.File)
+					record.File = filepath.ToSlash(relative)
+					found = append(found, formatRecord(record))
+				}
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return found
+	}
+
+	tests := []struct {
+		name    string
+		options grepOptions
+		want    []string
+	}{
+		{
+			name: "every source",
+			want: []string{
+				`events/us-east-1.ndjson:1: [cloudtrail 2024-05-01T09:00:00Z 2abc] {"eventTime":"2024-05-01T09:00:00Z","eventName":"AuthorizeSecurityGroupIngress","requestParameters":{"groupId":"sg-0123"}}`,
+				`events/us-east-1.ndjson:2: [cloudtrail 2024-04-01T09:00:00Z 2abc] {"eventTime":"2024-04-01T09:00:00Z","eventName":"RevokeSecurityGroupIngress","requestParameters":{"groupId":"sg-0123"}}`,
+				"must-gather.local.1/namespaces/openshift-ingress/router.log:1: [must-gather 2024-05-01T11:00:00Z " + clusterUUID + "] 2024-05-01T11:00:00.123Z error: sg-0123 unreachable",
+				"must-gather.local.1/namespaces/openshift-ingress/router.log:2: [must-gather " + clusterUUID + "] no time sg-0123",
+				"notes.txt.gz:1: [file] I0501 sg-0123 in a gzipped note",
+				`raw/service-logs/001-GET-api.json:2: [raw/service-logs 2024-05-01T10:00:00Z 2abc]   "summary": "Security group sg-0123 modified"`,
+			},
+		},
+		{
+			name:    "time range leaves the lines without a time out",
+			options: grepOptions{since: "2d", until: "2024-05-01T10:30:00Z"},
+			want: []string{
+				`events/us-east-1.ndjson:1: [cloudtrail 2024-05-01T09:00:00Z 2abc] {"eventTime":"2024-05-01T09:00:00Z","eventName":"AuthorizeSecurityGroupIngress","requestParameters":{"groupId":"sg-0123"}}`,
+				`raw/service-logs/001-GET-api.json:2: [raw/service-logs 2024-05-01T10:00:00Z 2abc]   "summary": "Security group sg-0123 modified"`,
+			},
+		},
+		{
+			name:    "source and cluster",
+			options: grepOptions{sources: []string{"raw", "must-gather"}, cluster: clusterUUID},
+			want: []string{
+				"must-gather.local.1/namespaces/openshift-ingress/router.log:1: [must-gather 2024-05-01T11:00:00Z " + clusterUUID + "] 2024-05-01T11:00:00.123Z error: sg-0123 unreachable",
+				"must-gather.local.1/namespaces/openshift-ingress/router.log:2: [must-gather " + clusterUUID + "] no time sg-0123",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := search(tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("search() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
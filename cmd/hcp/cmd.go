@@ -0,0 +1,28 @@
+package hcp
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdHcp() *cobra.Command {
+	hcpCmd := &cobra.Command{
+		Use:               "hcp",
+		Short:             "Hosted control plane (HCP) cluster utilities",
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println("Error calling cmd.Help(): ", err.Error())
+				return
+			}
+		},
+	}
+
+	hcpCmd.AddCommand(newCmdKubeconfig())
+	hcpCmd.AddCommand(newCmdCleanupKubeconfig())
+
+	return hcpCmd
+}
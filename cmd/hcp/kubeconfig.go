@@ -0,0 +1,241 @@
+package hcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/cluster/dynatrace"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	kubeconfigTypeAdmin      = "admin"
+	kubeconfigTypeBreakGlass = "break-glass"
+
+	kubeconfigSecretKey = "kubeconfig"
+	// kubeconfigFilePrefix is followed by the cluster ID and the expiry of the file, so expired files can be
+	// found and removed even when the cleanup process didn't run
+	kubeconfigFilePrefix = "osdctl-hcp-"
+	kubeconfigFileSuffix = ".kubeconfig"
+)
+
+type kubeconfigOptions struct {
+	clusterID      string
+	kubeconfigType string
+	reason         string
+	ttl            time.Duration
+	outputDir      string
+}
+
+func newCmdKubeconfig() *cobra.Command {
+	ops := &kubeconfigOptions{}
+	kubeconfigCmd := &cobra.Command{
+		Use:   "kubeconfig <cluster-id>",
+		Short: "Fetch the admin or break-glass kubeconfig of a hosted cluster from its management cluster",
+		Long: `Fetch the kubeconfig of a hosted control plane cluster from its management cluster and write it to a
+file only readable by the current user. The file is removed automatically once --ttl expires.
+
+Kubeconfig types:
+  admin         the '<cluster-name>-admin-kubeconfig' secret of the HostedCluster namespace
+  break-glass   the 'admin-kubeconfig' secret of the hosted control plane namespace, generated by the control
+                plane itself, which still works when the HostedCluster resource is broken
+
+Reading the secrets requires an elevation on the management cluster, hence --reason.`,
+		Example: `  # Fetch the admin kubeconfig for 30 minutes
+  osdctl hcp kubeconfig <cluster-id> --reason OHSS-1234 --ttl 30m`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	kubeconfigCmd.Flags().StringVar(&ops.kubeconfigType, "type", kubeconfigTypeAdmin, fmt.Sprintf("Kubeconfig to fetch, one of [%s, %s]", kubeconfigTypeAdmin, kubeconfigTypeBreakGlass))
+	kubeconfigCmd.Flags().StringVar(&ops.reason, "reason", "", "Reason for elevating on the management cluster, usually a Jira ticket ID")
+	kubeconfigCmd.Flags().DurationVar(&ops.ttl, "ttl", time.Hour, "How long the kubeconfig file is kept before being removed")
+	kubeconfigCmd.Flags().StringVar(&ops.outputDir, "output-dir", os.TempDir(), "Directory the kubeconfig file is written to")
+	_ = kubeconfigCmd.MarkFlagRequired("reason")
+
+	return kubeconfigCmd
+}
+
+func (o *kubeconfigOptions) validate() error {
+	if o.kubeconfigType != kubeconfigTypeAdmin && o.kubeconfigType != kubeconfigTypeBreakGlass {
+		return fmt.Errorf("unknown kubeconfig type '%s', expected one of [%s, %s]", o.kubeconfigType, kubeconfigTypeAdmin, kubeconfigTypeBreakGlass)
+	}
+	if o.ttl <= 0 {
+		return fmt.Errorf("--ttl must be positive")
+	}
+	return nil
+}
+
+func (o *kubeconfigOptions) run() error {
+	// Take the opportunity to remove the kubeconfigs whose cleanup didn't happen, e.g. after a reboot
+	for _, path := range sweepExpiredKubeconfigs(o.outputDir, time.Now()) {
+		fmt.Fprintf(os.Stderr, "Removed expired kubeconfig %s\n", path)
+	}
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if !cluster.Hypershift().Enabled() {
+		return fmt.Errorf("cluster %s is not a hosted control plane cluster", cluster.ID())
+	}
+
+	managementCluster, err := utils.GetManagementCluster(cluster.ID())
+	if err != nil {
+		return fmt.Errorf("failed to find the management cluster of %s: %w", cluster.ID(), err)
+	}
+
+	_, _, clientset, err := common.GetKubeConfigAndClient(managementCluster.ID(), o.reason)
+	if err != nil {
+		return fmt.Errorf("failed to access management cluster %s: %w", managementCluster.Name(), err)
+	}
+
+	_, hostedClusterNS, hcpNS, err := dynatrace.GetHCPNamespacesFromInternalID(clientset, cluster.ID())
+	if err != nil {
+		return err
+	}
+
+	namespace, secretName := hostedClusterNS, fmt.Sprintf("%s-admin-kubeconfig", cluster.Name())
+	if o.kubeconfigType == kubeconfigTypeBreakGlass {
+		namespace, secretName = hcpNS, "admin-kubeconfig"
+	}
+
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s/%s on management cluster %s: %w", namespace, secretName, managementCluster.Name(), err)
+	}
+	kubeconfig, ok := secret.Data[kubeconfigSecretKey]
+	if !ok || len(kubeconfig) == 0 {
+		return fmt.Errorf("secret %s/%s has no '%s' key", namespace, secretName, kubeconfigSecretKey)
+	}
+
+	expiry := time.Now().Add(o.ttl)
+	path, err := writeKubeconfig(o.outputDir, cluster.ID(), expiry, kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	if err := startCleanup(path, o.ttl); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to schedule the removal of %s, remove it manually: %v\n", path, err)
+	}
+
+	fmt.Printf("Wrote the %s kubeconfig of %s (%s) to %s\n", o.kubeconfigType, cluster.Name(), cluster.ID(), path)
+	fmt.Printf("It will be removed at %s\n\n", expiry.Format(time.RFC1123))
+	fmt.Printf("Use it with:\n  export KUBECONFIG=%s\n  oc whoami\n\n", path)
+	fmt.Printf("Or remove it as soon as you are done:\n  rm %s\n", path)
+	return nil
+}
+
+// writeKubeconfig writes the kubeconfig to a new file only readable by the current user
+func writeKubeconfig(dir string, clusterID string, expiry time.Time, kubeconfig []byte) (string, error) {
+	file, err := os.CreateTemp(dir, fmt.Sprintf("%s%s-%d-*%s", kubeconfigFilePrefix, clusterID, expiry.Unix(), kubeconfigFileSuffix))
+	if err != nil {
+		return "", fmt.Errorf("failed to create kubeconfig file: %w", err)
+	}
+	defer file.Close()
+
+	// CreateTemp already uses 0600, but be explicit since the file holds admin credentials
+	if err := file.Chmod(0600); err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to restrict kubeconfig file permissions: %w", err)
+	}
+	if _, err := file.Write(kubeconfig); err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write kubeconfig file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// kubeconfigExpiry returns the expiry encoded in the name of a kubeconfig file written by writeKubeconfig
+func kubeconfigExpiry(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, kubeconfigFilePrefix) || !strings.HasSuffix(name, kubeconfigFileSuffix) {
+		return time.Time{}, false
+	}
+	// osdctl-hcp-<cluster-id>-<expiry>-<random>.kubeconfig
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(name, kubeconfigFilePrefix), kubeconfigFileSuffix), "-")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// sweepExpiredKubeconfigs removes the kubeconfig files of dir which expired before now and returns their paths
+func sweepExpiredKubeconfigs(dir string, now time.Time) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var removed []string
+	for _, entry := range entries {
+		expiry, ok := kubeconfigExpiry(entry.Name())
+		if !ok || entry.IsDir() || expiry.After(now) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := os.Remove(path); err == nil {
+			removed = append(removed, path)
+		}
+	}
+	return removed
+}
+
+// startCleanup starts a detached osdctl process removing the kubeconfig once the ttl expired
+func startCleanup(path string, ttl time.Duration) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(executable, "hcp", "cleanup-kubeconfig", path, "--after", ttl.String())
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+func newCmdCleanupKubeconfig() *cobra.Command {
+	var after time.Duration
+	cleanupCmd := &cobra.Command{
+		Use:               "cleanup-kubeconfig <path>",
+		Short:             "Remove a kubeconfig written by 'osdctl hcp kubeconfig' after a delay",
+		Hidden:            true,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, ok := kubeconfigExpiry(filepath.Base(args[0])); !ok {
+				cmdutil.CheckErr(fmt.Errorf("%s isn't a kubeconfig written by osdctl hcp kubeconfig", args[0]))
+			}
+			time.Sleep(after)
+			if err := os.Remove(args[0]); err != nil && !os.IsNotExist(err) {
+				cmdutil.CheckErr(err)
+			}
+		},
+	}
+	cleanupCmd.Flags().DurationVar(&after, "after", time.Hour, "Delay before removing the kubeconfig")
+	return cleanupCmd
+}
@@ -0,0 +1,70 @@
+package hcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKubeconfigExpiry(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileName   string
+		wantExpiry int64
+		wantOk     bool
+	}{
+		{name: "written by osdctl", fileName: "osdctl-hcp-2abc3def-1700000000-123456.kubeconfig", wantExpiry: 1700000000, wantOk: true},
+		{name: "other file", fileName: "kubeconfig", wantOk: false},
+		{name: "invalid expiry", fileName: "osdctl-hcp-2abc3def-soon-123456.kubeconfig", wantOk: false},
+		{name: "missing suffix", fileName: "osdctl-hcp-2abc3def-1700000000-123456", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiry, ok := kubeconfigExpiry(tt.fileName)
+			if ok != tt.wantOk {
+				t.Fatalf("kubeconfigExpiry() ok = %t, want %t", ok, tt.wantOk)
+			}
+			if ok && expiry.Unix() != tt.wantExpiry {
+				t.Errorf("kubeconfigExpiry() = %d, want %d", expiry.Unix(), tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestWriteAndSweepKubeconfigs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	expired, err := writeKubeconfig(dir, "2abc3def", now.Add(-time.Minute), []byte("expired"))
+	if err != nil {
+		t.Fatalf("writeKubeconfig() error = %v", err)
+	}
+	valid, err := writeKubeconfig(dir, "2abc3def", now.Add(time.Hour), []byte("valid"))
+	if err != nil {
+		t.Fatalf("writeKubeconfig() error = %v", err)
+	}
+	unrelated := filepath.Join(dir, "config")
+	if err := os.WriteFile(unrelated, []byte("keep"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("kubeconfig permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	removed := sweepExpiredKubeconfigs(dir, now)
+	if len(removed) != 1 || removed[0] != expired {
+		t.Errorf("sweepExpiredKubeconfigs() removed %v, want [%s]", removed, expired)
+	}
+	for _, path := range []string{valid, unrelated} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should have been kept: %v", path, err)
+		}
+	}
+}
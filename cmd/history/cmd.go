@@ -0,0 +1,123 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/history"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type historyOptions struct {
+	clusterID string
+	since     time.Duration
+	output    string
+}
+
+// NewCmdHistory returns the history command
+func NewCmdHistory() *cobra.Command {
+	ops := &historyOptions{}
+	historyCmd := &cobra.Command{
+		Use:   "history [cluster-id]",
+		Short: "Show the osdctl commands previously run against a cluster",
+		Long: `Show the osdctl commands run on this machine against a cluster: when, by whom, with which flags, reason
+and AWS profile. The flag values aren't recorded as some are credentials, only their names are.
+
+The commands are recorded in the user cache directory (~/.cache/osdctl/history.jsonl on Linux) and kept for
+90 days, set '` + history.DisabledConfigKey + `: true' in the osdctl config to stop recording them.`,
+		Example: `  # Show what was run against a cluster in the last day
+  osdctl history <cluster-id> --since 24h
+
+  # Show every recorded command
+  osdctl history`,
+		Args:              cobra.MaximumNArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 1 {
+				ops.clusterID = args[0]
+			}
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	historyCmd.Flags().DurationVar(&ops.since, "since", 0, "Only show the commands run within this duration, e.g. 24h")
+	historyCmd.Flags().StringVarP(&ops.output, "output", "o", "table", "Valid formats are ['table', 'json']")
+
+	return historyCmd
+}
+
+func (o *historyOptions) run() error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("invalid output format '%s', valid formats are 'table' and 'json'", o.output)
+	}
+
+	path, err := history.File()
+	if err != nil {
+		return err
+	}
+	entries, err := history.Read(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the history %s: %w", path, err)
+	}
+
+	var since time.Time
+	if o.since > 0 {
+		since = time.Now().Add(-o.since)
+	}
+	var clusterIDs []string
+	if o.clusterID != "" {
+		clusterIDs = o.clusterIdentifiers()
+	}
+	entries = history.Filter(entries, clusterIDs, since)
+
+	if o.output == "json" {
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No recorded commands")
+		return nil
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"TIME", "USER", "CLUSTER", "COMMAND", "FLAGS", "REASON", "PROFILE"})
+	for _, entry := range entries {
+		table.AddRow([]string{
+			entry.Time.Local().Format(time.RFC3339),
+			entry.User,
+			entry.ClusterID,
+			strings.TrimSpace(entry.Command + " " + strings.Join(entry.Args, " ")),
+			strings.Join(entry.Flags, " "),
+			entry.Reason,
+			entry.Profile,
+		})
+	}
+	table.AddRow([]string{})
+	return table.Flush()
+}
+
+// clusterIdentifiers returns the ID, external ID and name of the cluster, as the commands may have been run
+// with any of them. Only the given identifier is returned when OCM can't be reached.
+func (o *historyOptions) clusterIdentifiers() []string {
+	identifiers := []string{o.clusterID}
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return identifiers
+	}
+	defer connection.Close()
+	cluster, err := utils.GetClusterAnyStatus(connection, o.clusterID)
+	if err != nil {
+		return identifiers
+	}
+	return append(identifiers, cluster.ID(), cluster.ExternalID(), cluster.Name())
+}
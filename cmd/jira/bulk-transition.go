@@ -0,0 +1,322 @@
+package jira
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultBulkTransitionLimit = 100
+
+	bulkTransitionDone    = "DONE"
+	bulkTransitionSkipped = "SKIPPED"
+	bulkTransitionFailed  = "FAILED"
+)
+
+var bulkTransitionCmd = &cobra.Command{
+	Use:   "bulk-transition",
+	Short: "Transitions all the issues matching a JQL query, with a templated comment",
+	Long: `Transitions all the issues matching a JQL query to a status, e.g. to close out the dozens of identical OHSS cards
+generated during a mass incident. The issues and the comment rendered for each of them are previewed before anything is changed.
+
+The comment is a Go template, given inline or read from a file with @<path>. It is rendered for each issue with:
+  {{.Key}}, {{.Summary}}, {{.Status}}, {{.Assignee}}, {{.URL}} and the values of --var as {{.Vars.<name>}}
+
+The comment is added as part of the transition, so an issue is never left commented but not transitioned.`,
+	Example: `#Resolve the cards of a mass incident
+osdctl jira bulk-transition --jql 'project = OHSS AND summary ~ "ClusterMonitoringErrorBudgetBurn" AND resolution = Unresolved' \
+  --to Resolved --resolution Done \
+  --comment-template 'Closing {{.Key}}, caused by the incident {{.Vars.incident}} which is now resolved.' --var incident=ITN-2024-00042
+
+#Preview only
+osdctl jira bulk-transition --jql 'project = OHSS AND labels = mass-event' --to Closed --dry-run
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jql, _ := cmd.Flags().GetString("jql")
+		to, _ := cmd.Flags().GetString("to")
+		resolution, _ := cmd.Flags().GetString("resolution")
+		commentTemplate, _ := cmd.Flags().GetString("comment-template")
+		vars, _ := cmd.Flags().GetStringToString("var")
+		limit, _ := cmd.Flags().GetInt("limit")
+		all, _ := cmd.Flags().GetBool("all")
+		if all {
+			limit = 0
+		}
+		yes, _ := cmd.Flags().GetBool("yes")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if strings.TrimSpace(jql) == "" {
+			return fmt.Errorf("--jql can't be empty")
+		}
+		comment, err := parseCommentTemplate(commentTemplate)
+		if err != nil {
+			return err
+		}
+
+		jiraClient, err := utils.GetJiraClient()
+		if err != nil {
+			return fmt.Errorf("failed to get Jira client: %w", err)
+		}
+		result, err := utils.SearchJiraIssues(jiraClient.Issue, jql, nil, limit)
+		if err != nil {
+			return fmt.Errorf("failed to search for jira issues: %w", err)
+		}
+		if len(result.Issues) == 0 {
+			fmt.Println("No issues found")
+			return nil
+		}
+
+		transitions, err := planBulkTransitions(jiraClient.Issue, result.Issues, to, comment, vars)
+		if err != nil {
+			return err
+		}
+		if err := printBulkTransitionPlan(transitions, to); err != nil {
+			return err
+		}
+		if result.Truncated() {
+			fmt.Printf("\nOnly the first %d of the %d issues matching the query are transitioned, raise --limit or use --all to include more\n", len(result.Issues), result.Total)
+		}
+		if dryRun {
+			return nil
+		}
+		if !yes {
+			fmt.Printf("\n%d issues will be transitioned to %s. ", countPlanned(transitions), to)
+			if !utils.ConfirmPrompt() {
+				return nil
+			}
+		}
+
+		runBulkTransitions(jiraClient.Issue, transitions, resolution)
+		failed, err := printBulkTransitionResults(transitions)
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d issues failed to transition", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	bulkTransitionCmd.Flags().String("jql", "", "JQL query of the issues to transition")
+	bulkTransitionCmd.Flags().String("to", "", "Name of the transition, or of the status to transition the issues to")
+	bulkTransitionCmd.Flags().String("resolution", "", "Resolution to set with the transition, e.g. Done, when the workflow requires one")
+	bulkTransitionCmd.Flags().String("comment-template", "", "Go template of the comment added with the transition, or @<path> of a file holding it")
+	bulkTransitionCmd.Flags().StringToString("var", nil, "Values available to the comment template as {{.Vars.<name>}}, e.g. --var incident=ITN-2024-00042")
+	bulkTransitionCmd.Flags().Int("limit", defaultBulkTransitionLimit, "Maximum number of issues to transition")
+	bulkTransitionCmd.Flags().Bool("all", false, "Transition all the issues matching the query, however many there are")
+	bulkTransitionCmd.Flags().BoolP("yes", "y", false, "Transition the issues without asking for confirmation")
+	bulkTransitionCmd.Flags().Bool("dry-run", false, "Only preview the transitions")
+	_ = bulkTransitionCmd.MarkFlagRequired("jql")
+	_ = bulkTransitionCmd.MarkFlagRequired("to")
+}
+
+// bulkTransitionIssueService is the part of the Jira issue service used to transition the issues
+type bulkTransitionIssueService interface {
+	GetTransitions(id string) ([]jira.Transition, *jira.Response, error)
+	DoTransitionWithPayload(ticketID, payload interface{}) (*jira.Response, error)
+}
+
+// commentTemplateData is what the comment template is rendered with
+type commentTemplateData struct {
+	Key      string
+	Summary  string
+	Status   string
+	Assignee string
+	URL      string
+	Vars     map[string]string
+}
+
+// bulkTransition is the transition of a single issue, and its outcome
+type bulkTransition struct {
+	Issue        jira.Issue
+	TransitionID string
+	Comment      string
+	Result       string
+	Details      string
+}
+
+// parseCommentTemplate parses the template of the comment, read from a file when prefixed with @
+func parseCommentTemplate(value string) (*template.Template, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the comment template: %w", err)
+		}
+		value = string(content)
+	}
+	tmpl, err := template.New("comment").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comment template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderComment renders the comment of an issue, an empty comment is returned without a template
+func renderComment(tmpl *template.Template, issue jira.Issue, vars map[string]string) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	data := commentTemplateData{Key: issue.Key, URL: utils.JiraBrowseURL(issue.Key), Vars: vars}
+	if issue.Fields != nil {
+		data.Summary = issue.Fields.Summary
+		data.Status = issueStatus(issue)
+		if issue.Fields.Assignee != nil {
+			data.Assignee = issue.Fields.Assignee.DisplayName
+		}
+	}
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+	var comment bytes.Buffer
+	if err := tmpl.Execute(&comment, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(comment.String()), nil
+}
+
+// matchTransition finds the transition named after the target, or leading to the status named after it
+func matchTransition(transitions []jira.Transition, to string) (jira.Transition, bool) {
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.Name, to) {
+			return transition, true
+		}
+	}
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.To.Name, to) {
+			return transition, true
+		}
+	}
+	return jira.Transition{}, false
+}
+
+// planBulkTransitions renders the comment and looks up the transition of each issue. The issues which are
+// already in the target status, or which can't be transitioned to it, are skipped.
+func planBulkTransitions(service bulkTransitionIssueService, issues []jira.Issue, to string, tmpl *template.Template, vars map[string]string) ([]*bulkTransition, error) {
+	transitions := make([]*bulkTransition, 0, len(issues))
+	for _, issue := range issues {
+		comment, err := renderComment(tmpl, issue, vars)
+		if err != nil {
+			// the template is the same for every issue, it is better fixed before anything is changed
+			return nil, fmt.Errorf("failed to render the comment of %s: %w", issue.Key, err)
+		}
+		transition := &bulkTransition{Issue: issue, Comment: comment}
+		transitions = append(transitions, transition)
+
+		if strings.EqualFold(issueStatus(issue), to) {
+			transition.Result = bulkTransitionSkipped
+			transition.Details = "already " + issueStatus(issue)
+			continue
+		}
+		available, _, err := service.GetTransitions(issue.Key)
+		if err != nil {
+			transition.Result = bulkTransitionSkipped
+			transition.Details = fmt.Sprintf("failed to get the transitions: %v", err)
+			continue
+		}
+		match, ok := matchTransition(available, to)
+		if !ok {
+			transition.Result = bulkTransitionSkipped
+			transition.Details = fmt.Sprintf("no transition to %s from %s", to, issueStatus(issue))
+			continue
+		}
+		transition.TransitionID = match.ID
+	}
+	return transitions, nil
+}
+
+// runBulkTransitions transitions the planned issues, one at a time so a failure doesn't stop the others
+func runBulkTransitions(service bulkTransitionIssueService, transitions []*bulkTransition, resolution string) {
+	for _, transition := range transitions {
+		if transition.Result != "" {
+			continue
+		}
+		payload := jira.CreateTransitionPayload{Transition: jira.TransitionPayload{ID: transition.TransitionID}}
+		if transition.Comment != "" {
+			payload.Update.Comment = []jira.TransitionPayloadComment{{Add: jira.TransitionPayloadCommentBody{Body: transition.Comment}}}
+		}
+		if resolution != "" {
+			payload.Fields.Resolution = &jira.Resolution{Name: resolution}
+		}
+		if _, err := service.DoTransitionWithPayload(transition.Issue.Key, payload); err != nil {
+			transition.Result = bulkTransitionFailed
+			transition.Details = err.Error()
+			continue
+		}
+		transition.Result = bulkTransitionDone
+		transition.Details = utils.JiraBrowseURL(transition.Issue.Key)
+	}
+}
+
+func countPlanned(transitions []*bulkTransition) int {
+	planned := 0
+	for _, transition := range transitions {
+		if transition.Result == "" {
+			planned++
+		}
+	}
+	return planned
+}
+
+func issueStatus(issue jira.Issue) string {
+	if issue.Fields == nil || issue.Fields.Status == nil {
+		return ""
+	}
+	return issue.Fields.Status.Name
+}
+
+func printBulkTransitionPlan(transitions []*bulkTransition, to string) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"KEY", "STATUS", "PLAN", "SUMMARY"})
+	for _, transition := range transitions {
+		plan := "-> " + to
+		if transition.Result != "" {
+			plan = transition.Result + ": " + transition.Details
+		}
+		var summary string
+		if transition.Issue.Fields != nil {
+			summary = transition.Issue.Fields.Summary
+		}
+		table.AddRow([]string{transition.Issue.Key, issueStatus(transition.Issue), plan, summary})
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	// The comments only differ by the fields of the issues, the first one is enough to review the template
+	for _, transition := range transitions {
+		if transition.Result == "" && transition.Comment != "" {
+			fmt.Printf("\nComment of %s:\n%s\n", transition.Issue.Key, transition.Comment)
+			break
+		}
+	}
+	return nil
+}
+
+// printBulkTransitionResults prints the outcome of each issue and returns the number of failures
+func printBulkTransitionResults(transitions []*bulkTransition) (int, error) {
+	fmt.Println()
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"KEY", "RESULT", "DETAILS"})
+	failed := 0
+	for _, transition := range transitions {
+		if transition.Result == bulkTransitionFailed {
+			failed++
+		}
+		table.AddRow([]string{transition.Issue.Key, transition.Result, transition.Details})
+	}
+	return failed, table.Flush()
+}
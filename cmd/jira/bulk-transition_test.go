@@ -0,0 +1,128 @@
+package jira
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+type fakeTransitionService struct {
+	transitions map[string][]jira.Transition
+	failing     map[string]bool
+	payloads    map[string]jira.CreateTransitionPayload
+}
+
+func (s *fakeTransitionService) GetTransitions(id string) ([]jira.Transition, *jira.Response, error) {
+	return s.transitions[id], nil, nil
+}
+
+func (s *fakeTransitionService) DoTransitionWithPayload(ticketID, payload interface{}) (*jira.Response, error) {
+	key := ticketID.(string)
+	if s.failing[key] {
+		return nil, fmt.Errorf("field resolution is required")
+	}
+	s.payloads[key] = payload.(jira.CreateTransitionPayload)
+	return nil, nil
+}
+
+func newTestIssue(key string, status string) jira.Issue {
+	return jira.Issue{Key: key, Fields: &jira.IssueFields{Summary: "ClusterMonitoringErrorBudgetBurn", Status: &jira.Status{Name: status}}}
+}
+
+func TestMatchTransition(t *testing.T) {
+	transitions := []jira.Transition{
+		{ID: "11", Name: "Start Progress", To: jira.Status{Name: "In Progress"}},
+		{ID: "21", Name: "Close Issue", To: jira.Status{Name: "Closed"}},
+		{ID: "31", Name: "Resolved", To: jira.Status{Name: "Done"}},
+	}
+	tests := []struct {
+		name   string
+		to     string
+		wantID string
+	}{
+		{name: "transition name", to: "close issue", wantID: "21"},
+		{name: "target status", to: "Closed", wantID: "21"},
+		{name: "transition name before target status", to: "Resolved", wantID: "31"},
+		{name: "no transition", to: "Won't Fix", wantID: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchTransition(transitions, tt.to)
+			if got.ID != tt.wantID || ok != (tt.wantID != "") {
+				t.Errorf("matchTransition() = %v, %v, want %s", got.ID, ok, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestRenderComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		vars     map[string]string
+		want     string
+		wantErr  bool
+	}{
+		{name: "no template", want: ""},
+		{name: "issue fields", template: "{{.Key}} ({{.Summary}}) was {{.Status}}", want: "OHSS-1 (ClusterMonitoringErrorBudgetBurn) was New"},
+		{name: "vars", template: "Caused by {{.Vars.incident}}\n", vars: map[string]string{"incident": "ITN-42"}, want: "Caused by ITN-42"},
+		{name: "missing var", template: "Caused by {{.Vars.incident}}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseCommentTemplate(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := renderComment(tmpl, newTestIssue("OHSS-1", "New"), tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderComment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderComment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkTransitions(t *testing.T) {
+	resolve := []jira.Transition{{ID: "5", Name: "Resolve Issue", To: jira.Status{Name: "Resolved"}}}
+	service := &fakeTransitionService{
+		transitions: map[string][]jira.Transition{"OHSS-1": resolve, "OHSS-2": resolve, "OHSS-4": resolve},
+		failing:     map[string]bool{"OHSS-4": true},
+		payloads:    map[string]jira.CreateTransitionPayload{},
+	}
+	issues := []jira.Issue{
+		newTestIssue("OHSS-1", "New"),
+		newTestIssue("OHSS-2", "In Progress"),
+		newTestIssue("OHSS-3", "Resolved"),
+		newTestIssue("OHSS-4", "New"),
+		newTestIssue("OHSS-5", "Closed"),
+	}
+	tmpl, _ := parseCommentTemplate("Closing {{.Key}}")
+
+	transitions, err := planBulkTransitions(service, issues, "Resolved", tmpl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if planned := countPlanned(transitions); planned != 3 {
+		t.Errorf("countPlanned() = %d, want 3", planned)
+	}
+	runBulkTransitions(service, transitions, "Done")
+
+	wantResults := []string{bulkTransitionDone, bulkTransitionDone, bulkTransitionSkipped, bulkTransitionFailed, bulkTransitionSkipped}
+	for i, transition := range transitions {
+		if transition.Result != wantResults[i] {
+			t.Errorf("%s result = %s (%s), want %s", transition.Issue.Key, transition.Result, transition.Details, wantResults[i])
+		}
+	}
+
+	payload := service.payloads["OHSS-2"]
+	if payload.Transition.ID != "5" || payload.Fields.Resolution == nil || payload.Fields.Resolution.Name != "Done" {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+	if len(payload.Update.Comment) != 1 || payload.Update.Comment[0].Add.Body != "Closing OHSS-2" {
+		t.Errorf("the comment should be added with the transition, got %+v", payload.Update)
+	}
+}
@@ -10,4 +10,7 @@ var Cmd = &cobra.Command{
 
 func init() {
 	Cmd.AddCommand(quickTaskCmd)
+	Cmd.AddCommand(supportExceptionCmd)
+	Cmd.AddCommand(issuesCmd)
+	Cmd.AddCommand(bulkTransitionCmd)
 }
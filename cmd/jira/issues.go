@@ -0,0 +1,121 @@
+package jira
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DefaultIssuesProject = "OHSS"
+	defaultIssuesLimit   = utils.DefaultJiraIssueLimit
+)
+
+var issuesTableOptions printer.TableOptions
+
+var issuesCmd = &cobra.Command{
+	Use:   "issues",
+	Short: "Lists the open issues of a project, or the ones assigned to or reported by me",
+	Long: `Lists the unresolved issues of a project, by default OHSS, most recently updated first.
+With --mine, only the issues assigned to or reported by the owner of the Jira token are listed, for a personal work queue view.`,
+	Example: `#My open OHSS cards
+osdctl jira issues --mine
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, _ := cmd.Flags().GetString("project")
+		mine, _ := cmd.Flags().GetBool("mine")
+		limit, _ := cmd.Flags().GetInt("limit")
+		all, _ := cmd.Flags().GetBool("all")
+		if all {
+			limit = 0
+		}
+
+		jiraClient, err := utils.GetJiraClient()
+		if err != nil {
+			return fmt.Errorf("failed to get Jira client: %w", err)
+		}
+
+		if mine {
+			// The issues are filtered with currentUser(), this only tells which identity the token resolves to
+			user, _, err := jiraClient.User.GetSelf()
+			if err != nil {
+				return fmt.Errorf("failed to get the current Jira user: %w", err)
+			}
+			fmt.Printf("Issues of %s (%s)\n", user.DisplayName, user.Name)
+		}
+
+		result, err := utils.SearchJiraIssues(jiraClient.Issue, issuesJQL(project, mine), nil, limit)
+		if err != nil {
+			return fmt.Errorf("failed to search for jira issues: %w", err)
+		}
+		if len(result.Issues) == 0 {
+			fmt.Println("No issues found")
+			return nil
+		}
+		if err := printIssues(result.Issues); err != nil {
+			return err
+		}
+		printSearchTotal(result)
+		return nil
+	},
+}
+
+func init() {
+	issuesCmd.Flags().String("project", DefaultIssuesProject, "Project to list the issues of")
+	issuesCmd.Flags().Bool("mine", false, "Only list the issues assigned to or reported by me")
+	issuesCmd.Flags().Int("limit", defaultIssuesLimit, "Maximum number of issues to list")
+	issuesCmd.Flags().Bool("all", false, "List all the issues, however many there are")
+	printer.AddTableFlags(issuesCmd.Flags(), &issuesTableOptions)
+}
+
+// issuesJQL builds the query of the unresolved issues of the project
+func issuesJQL(project string, mine bool) string {
+	conditions := []string{fmt.Sprintf("project = %q", project), "resolution = Unresolved"}
+	if mine {
+		conditions = append(conditions, "(assignee = currentUser() OR reporter = currentUser())")
+	}
+	return strings.Join(conditions, " AND ") + " ORDER BY updated DESC"
+}
+
+// printSearchTotal tells when the listed issues are only part of the matching ones
+func printSearchTotal(result utils.JiraSearchResult) {
+	if result.Truncated() {
+		fmt.Printf("\nShowing %d of %d issues, raise --limit or use --all to list more\n", len(result.Issues), result.Total)
+		return
+	}
+	fmt.Printf("\n%d issues\n", result.Total)
+}
+
+func printIssues(issues []jira.Issue) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ').WithTableOptions(issuesTableOptions)
+	table.AddRow([]string{"KEY", "STATUS", "PRIORITY", "ASSIGNEE", "UPDATED", "SUMMARY", "URL"})
+	for _, issue := range issues {
+		var status, priority, assignee string
+		if issue.Fields.Status != nil {
+			status = issue.Fields.Status.Name
+		}
+		if issue.Fields.Priority != nil {
+			priority = issue.Fields.Priority.Name
+		}
+		if issue.Fields.Assignee != nil {
+			assignee = issue.Fields.Assignee.DisplayName
+		}
+		table.AddRow([]string{
+			issue.Key,
+			status,
+			priority,
+			assignee,
+			time.Time(issue.Fields.Updated).Format("2006-01-02 15:04"),
+			issue.Fields.Summary,
+			utils.JiraBrowseURL(issue.Key),
+		})
+	}
+	return table.Flush()
+}
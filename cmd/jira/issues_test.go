@@ -0,0 +1,31 @@
+package jira
+
+import "testing"
+
+func TestIssuesJQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		mine    bool
+		want    string
+	}{
+		{
+			name:    "all unresolved issues",
+			project: "OHSS",
+			want:    `project = "OHSS" AND resolution = Unresolved ORDER BY updated DESC`,
+		},
+		{
+			name:    "mine",
+			project: "OHSS",
+			mine:    true,
+			want:    `project = "OHSS" AND resolution = Unresolved AND (assignee = currentUser() OR reporter = currentUser()) ORDER BY updated DESC`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issuesJQL(tt.project, tt.mine); got != tt.want {
+				t.Errorf("issuesJQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -63,7 +63,7 @@ osdctl jira quick-task "Update command to take new flag" --add-to-sprint
 		if err != nil {
 			return fmt.Errorf("error creating ticket: %w", err)
 		}
-		fmt.Printf("Successfully created ticket:\n%v/browse/%v\n", utils.JiraBaseURL, issue.Key)
+		fmt.Printf("Successfully created ticket:\n%v\n", utils.JiraBrowseURL(issue.Key))
 
 		if addToSprint {
 			err = addTicketToCurrentSprint(jiraClient.Board, jiraClient.Sprint, issue, boardId, teamName)
@@ -91,7 +91,7 @@ func CreateQuickTicket(userService *jira.UserService, issueService *jira.IssueSe
 		summary,
 		DefaultDescription,
 		DefaultTicketType,
-		DefaultProject,
+		utils.JiraProject(DefaultProject),
 		user,
 		user,
 		[]string{teamLabel},
@@ -0,0 +1,207 @@
+package jira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	SupportExceptionProjectName = "Support Exceptions"
+	SupportExceptionTicketType  = "Story"
+
+	// Names of the custom fields of the Support Exceptions project. Their IDs differ between Jira instances,
+	// so they are looked up by name.
+	customerNameField     = "Customer Name"
+	organizationIDField   = "Organization ID"
+	expirationDateField   = "Expiration Date"
+	supportExceptionLabel = "osdctl"
+)
+
+var supportExceptionCmd = &cobra.Command{
+	Use:   "create-support-exception",
+	Short: "Files a Support Exceptions story for an organization",
+	Long: `Files a story in the Support Exceptions project with the custom fields populated from the OCM organization:
+the customer name, the organization ID and the expiration date. A link to the created ticket is printed to the console.`,
+	Example: `#Create a support exception expiring in 90 days
+osdctl jira create-support-exception --org 1a2B3c --reason "Custom ingress controller needed for migration" --expiry 90d
+
+#Print the ticket without creating it
+osdctl jira create-support-exception --org 1a2B3c --reason "..." --expiry 2025-01-31 --dry-run
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orgID, _ := cmd.Flags().GetString("org")
+		reason, _ := cmd.Flags().GetString("reason")
+		expiryFlag, _ := cmd.Flags().GetString("expiry")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if strings.TrimSpace(reason) == "" {
+			return fmt.Errorf("--reason can't be empty")
+		}
+		expiry, err := parseExpiry(expiryFlag, time.Now())
+		if err != nil {
+			return err
+		}
+
+		org, err := getSupportExceptionOrg(orgID)
+		if err != nil {
+			return err
+		}
+
+		jiraClient, err := utils.GetJiraClient()
+		if err != nil {
+			return fmt.Errorf("failed to get Jira client: %w", err)
+		}
+
+		projectKey, err := findProjectKey(jiraClient.Project, utils.JiraProject(SupportExceptionProjectName))
+		if err != nil {
+			return err
+		}
+		fields, _, err := jiraClient.Field.GetList()
+		if err != nil {
+			return fmt.Errorf("failed to list Jira fields: %w", err)
+		}
+
+		issue := newSupportExceptionIssue(projectKey, org, reason, expiry, customFieldIDs(fields))
+		if dryRun {
+			fmt.Printf("Project: %s\nSummary: %s\nFields: %v\n\n%s\n", projectKey, issue.Fields.Summary, issue.Fields.Unknowns, issue.Fields.Description)
+			return nil
+		}
+
+		created, _, err := jiraClient.Issue.Create(issue)
+		if err != nil {
+			return fmt.Errorf("failed to create issue: %w", err)
+		}
+		fmt.Printf("Successfully created support exception:\n%v\n", utils.JiraBrowseURL(created.Key))
+		return nil
+	},
+}
+
+func init() {
+	supportExceptionCmd.Flags().String("org", "", "OCM organization ID the exception is granted to")
+	supportExceptionCmd.Flags().String("reason", "", "Why the exception is needed")
+	supportExceptionCmd.Flags().String("expiry", "90d", "When the exception expires, either a duration in days or weeks (90d, 12w) or a date (YYYY-MM-DD)")
+	supportExceptionCmd.Flags().Bool("dry-run", false, "Print the ticket instead of creating it")
+	_ = supportExceptionCmd.MarkFlagRequired("org")
+	_ = supportExceptionCmd.MarkFlagRequired("reason")
+}
+
+// supportExceptionOrg is the OCM organization data filled in the ticket
+type supportExceptionOrg struct {
+	id           string
+	name         string
+	externalID   string
+	ebsAccountID string
+}
+
+func getSupportExceptionOrg(orgID string) (*supportExceptionOrg, error) {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer ocmClient.Close()
+
+	response, err := ocmClient.AccountsMgmt().V1().Organizations().Organization(orgID).Get().Send()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization %s: %w", orgID, err)
+	}
+	org := response.Body()
+	return &supportExceptionOrg{
+		id:           org.ID(),
+		name:         org.Name(),
+		externalID:   org.ExternalID(),
+		ebsAccountID: org.EbsAccountID(),
+	}, nil
+}
+
+// parseExpiry parses a number of days ("90d"), weeks ("12w") or a date ("2025-01-31")
+func parseExpiry(value string, now time.Time) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		if !date.After(now) {
+			return time.Time{}, fmt.Errorf("expiry %s is in the past", value)
+		}
+		return date, nil
+	}
+
+	days := 1
+	switch {
+	case strings.HasSuffix(value, "d"):
+	case strings.HasSuffix(value, "w"):
+		days = 7
+	default:
+		return time.Time{}, fmt.Errorf("invalid expiry '%s', expected a number of days (90d), weeks (12w) or a date (YYYY-MM-DD)", value)
+	}
+	count, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || count < 1 {
+		return time.Time{}, fmt.Errorf("invalid expiry '%s', expected a number of days (90d), weeks (12w) or a date (YYYY-MM-DD)", value)
+	}
+	return now.AddDate(0, 0, count*days), nil
+}
+
+func findProjectKey(projectService *jira.ProjectService, name string) (string, error) {
+	projects, _, err := projectService.GetList()
+	if err != nil {
+		return "", fmt.Errorf("failed to list Jira projects: %w", err)
+	}
+	for _, project := range *projects {
+		if project.Name == name {
+			return project.Key, nil
+		}
+	}
+	return "", fmt.Errorf("no Jira project named '%s'", name)
+}
+
+// customFieldIDs maps the names of the custom fields to their IDs
+func customFieldIDs(fields []jira.Field) map[string]string {
+	ids := map[string]string{}
+	for _, field := range fields {
+		if field.Custom {
+			ids[field.Name] = field.ID
+		}
+	}
+	return ids
+}
+
+func newSupportExceptionIssue(projectKey string, org *supportExceptionOrg, reason string, expiry time.Time, fieldIDs map[string]string) *jira.Issue {
+	expiryDate := expiry.Format(time.DateOnly)
+
+	customFields := map[string]interface{}{}
+	for name, value := range map[string]string{
+		customerNameField:   org.name,
+		organizationIDField: org.id,
+		expirationDateField: expiryDate,
+	} {
+		if id, ok := fieldIDs[name]; ok {
+			customFields[id] = value
+		}
+	}
+
+	description := fmt.Sprintf(`h3. Organization
+* Name: %s
+* Organization ID: %s
+* External ID: %s
+* EBS account: %s
+
+h3. Reason
+%s
+
+h3. Expiration date
+%s`, org.name, org.id, org.externalID, org.ebsAccountID, reason, expiryDate)
+
+	return &jira.Issue{
+		Fields: &jira.IssueFields{
+			Type:        jira.IssueType{Name: SupportExceptionTicketType},
+			Project:     jira.Project{Key: projectKey},
+			Summary:     fmt.Sprintf("Support exception for %s (%s) until %s", org.name, org.id, expiryDate),
+			Description: description,
+			Labels:      []string{supportExceptionLabel},
+			Unknowns:    customFields,
+		},
+	}
+}
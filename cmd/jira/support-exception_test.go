@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+func TestParseExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "days", value: "90d", want: "2024-04-14"},
+		{name: "weeks", value: "2w", want: "2024-01-29"},
+		{name: "date", value: "2024-06-30", want: "2024-06-30"},
+		{name: "past date", value: "2023-12-31", wantErr: true},
+		{name: "zero days", value: "0d", wantErr: true},
+		{name: "no unit", value: "90", wantErr: true},
+		{name: "unknown unit", value: "3m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExpiry(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExpiry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Format(time.DateOnly) != tt.want {
+				t.Errorf("parseExpiry() = %s, want %s", got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSupportExceptionIssue(t *testing.T) {
+	org := &supportExceptionOrg{id: "1a2B3c", name: "ACME", externalID: "123", ebsAccountID: "456"}
+	fieldIDs := customFieldIDs([]jira.Field{
+		{ID: "customfield_1", Name: customerNameField, Custom: true},
+		{ID: "customfield_2", Name: organizationIDField, Custom: true},
+		{ID: "summary", Name: "Summary"},
+	})
+
+	issue := newSupportExceptionIssue("SE", org, "needed", time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC), fieldIDs)
+
+	if issue.Fields.Project.Key != "SE" || issue.Fields.Type.Name != SupportExceptionTicketType {
+		t.Errorf("unexpected project %s or type %s", issue.Fields.Project.Key, issue.Fields.Type.Name)
+	}
+	if issue.Fields.Unknowns["customfield_1"] != "ACME" || issue.Fields.Unknowns["customfield_2"] != "1a2B3c" {
+		t.Errorf("custom fields not populated from the organization: %v", issue.Fields.Unknowns)
+	}
+	// The expiration date field doesn't exist on this instance, it's only part of the description
+	if len(issue.Fields.Unknowns) != 2 {
+		t.Errorf("expected only the existing custom fields to be set, got %v", issue.Fields.Unknowns)
+	}
+	if issue.Fields.Summary != "Support exception for ACME (1a2B3c) until 2024-04-14" {
+		t.Errorf("unexpected summary %q", issue.Fields.Summary)
+	}
+}
@@ -0,0 +1,439 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	privateCheckWarn = "WARN"
+
+	privateCheckEndpointService = "endpoint service"
+	privateCheckConnection      = "connection"
+	privateCheckEndpoint        = "vpc endpoint"
+	privateCheckDNS             = "dns"
+)
+
+type privateCheckOptions struct {
+	clusterID string
+}
+
+// privateCheckResult is the outcome of checking one resource of the PrivateLink path to the API
+type privateCheckResult struct {
+	Check    string
+	Resource string
+	Status   string
+	Details  string
+}
+
+// privateLinkEC2Client and privateLinkRoute53Client are the parts of the AWS APIs private-check uses
+type privateLinkEC2Client interface {
+	DescribeSubnets(context.Context, *ec2.DescribeSubnetsInput, ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeVpcEndpointServiceConfigurations(context.Context, *ec2.DescribeVpcEndpointServiceConfigurationsInput, ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error)
+	DescribeVpcEndpointConnections(context.Context, *ec2.DescribeVpcEndpointConnectionsInput, ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointConnectionsOutput, error)
+	DescribeVpcEndpoints(context.Context, *ec2.DescribeVpcEndpointsInput, ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error)
+}
+
+type privateLinkRoute53Client interface {
+	ListHostedZones(context.Context, *route53.ListHostedZonesInput, ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error)
+	GetHostedZone(context.Context, *route53.GetHostedZoneInput, ...func(*route53.Options)) (*route53.GetHostedZoneOutput, error)
+	ListResourceRecordSets(context.Context, *route53.ListResourceRecordSetsInput, ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error)
+}
+
+func newCmdPrivateCheck() *cobra.Command {
+	ops := &privateCheckOptions{}
+	privateCheckCmd := &cobra.Command{
+		Use:   "private-check <cluster-id>",
+		Short: "Check the VPC endpoints and the DNS of a PrivateLink cluster",
+		Long: `Check the PrivateLink path to the API of a private cluster, in the cluster account:
+
+  - classic clusters: the VPC endpoint service of the API load balancer is available, and the connection of the
+    endpoint SRE reaches the cluster through is accepted
+  - hosted control planes: the VPC endpoint to the hosted control plane is available in the cluster's VPC
+
+and that the private hosted zones of the cluster are associated with its VPC and hold the API record. An
+endpoint connection left pending acceptance, or rejected, cuts SRE off the cluster.`,
+		Example:           `  osdctl network private-check <cluster-id>`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+	return privateCheckCmd
+}
+
+func (o *privateCheckOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+	if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" || !cluster.AWS().PrivateLink() {
+		return fmt.Errorf("cluster %s is not a PrivateLink cluster", cluster.ID())
+	}
+	apiName, err := apiHostname(cluster.API().URL())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := osdCloud.CreateAWSV2Config(ocmClient, cluster)
+	if err != nil {
+		return err
+	}
+	ec2Client := ec2.NewFromConfig(cfg)
+	route53Client := route53.NewFromConfig(cfg)
+
+	vpcIDs, err := clusterVPCIDs(ec2Client, cluster.AWS().SubnetIDs())
+	if err != nil {
+		return err
+	}
+
+	var results []privateCheckResult
+	var endpointTargets []string
+	zoneNames := []string{apiName}
+	if cluster.Hypershift().Enabled() {
+		endpoints, err := clusterVPCEndpoints(ec2Client, cluster.ID())
+		if err != nil {
+			return err
+		}
+		if len(endpoints) == 0 {
+			results = append(results, privateCheckResult{privateCheckEndpoint, "-", probeFail, "no VPC endpoint tagged for the cluster"})
+		}
+		for _, endpoint := range endpoints {
+			results = append(results, evaluateVPCEndpoint(endpoint, vpcIDs))
+			for _, entry := range endpoint.DnsEntries {
+				endpointTargets = append(endpointTargets, strings.ToLower(aws.ToString(entry.DnsName)))
+			}
+		}
+		// The nodes reach the API through the hypershift.local zone
+		zoneNames = append(zoneNames, fmt.Sprintf("api.%s.hypershift.local", cluster.Name()))
+	} else {
+		services, err := clusterEndpointServices(ec2Client, cluster.ID(), cluster.InfraID())
+		if err != nil {
+			return err
+		}
+		if len(services) == 0 {
+			results = append(results, privateCheckResult{privateCheckEndpointService, "-", probeFail, fmt.Sprintf("no VPC endpoint service tagged for %s", cluster.InfraID())})
+		}
+		for _, service := range services {
+			connections, err := endpointConnections(ec2Client, aws.ToString(service.ServiceId))
+			if err != nil {
+				return err
+			}
+			results = append(results, evaluateEndpointService(service, connections)...)
+		}
+	}
+
+	for _, name := range zoneNames {
+		results = append(results, checkPrivateZones(route53Client, name, vpcIDs, endpointTargets)...)
+	}
+
+	fmt.Printf("PrivateLink of %s (%s)\n", cluster.Name(), cluster.ID())
+	if failures := printPrivateCheckResults(results); failures > 0 {
+		return fmt.Errorf("%d PrivateLink check(s) failed", failures)
+	}
+	return nil
+}
+
+func apiHostname(apiURL string) (string, error) {
+	parsed, err := url.Parse(apiURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "", fmt.Errorf("invalid API URL '%s'", apiURL)
+	}
+	return parsed.Hostname(), nil
+}
+
+// clusterVPCIDs returns the VPCs of the subnets of the cluster, PrivateLink clusters are always installed
+// in existing subnets
+func clusterVPCIDs(client privateLinkEC2Client, subnetIDs []string) ([]string, error) {
+	if len(subnetIDs) == 0 {
+		return nil, fmt.Errorf("the cluster has no subnet")
+	}
+	output, err := client.DescribeSubnets(context.TODO(), &ec2.DescribeSubnetsInput{SubnetIds: subnetIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe the subnets of the cluster: %w", err)
+	}
+	var vpcIDs []string
+	for _, subnet := range output.Subnets {
+		if vpcID := aws.ToString(subnet.VpcId); !contains(vpcIDs, vpcID) {
+			vpcIDs = append(vpcIDs, vpcID)
+		}
+	}
+	return vpcIDs, nil
+}
+
+// clusterEndpointServices returns the VPC endpoint services of the API load balancer of a classic cluster,
+// Hive tags them with the cluster ID or the infra ID depending on its version
+func clusterEndpointServices(client privateLinkEC2Client, clusterID string, infraID string) ([]ec2types.ServiceConfiguration, error) {
+	var services []ec2types.ServiceConfiguration
+	input := &ec2.DescribeVpcEndpointServiceConfigurationsInput{}
+	for {
+		output, err := client.DescribeVpcEndpointServiceConfigurations(context.TODO(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the VPC endpoint services: %w", err)
+		}
+		for _, service := range output.ServiceConfigurations {
+			if tagsMention(service.Tags, clusterID, infraID) {
+				services = append(services, service)
+			}
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return services, nil
+}
+
+func tagsMention(tags []ec2types.Tag, values ...string) bool {
+	for _, tag := range tags {
+		for _, value := range values {
+			if value != "" && (strings.Contains(aws.ToString(tag.Key), value) || aws.ToString(tag.Value) == value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func endpointConnections(client privateLinkEC2Client, serviceID string) ([]ec2types.VpcEndpointConnection, error) {
+	var connections []ec2types.VpcEndpointConnection
+	input := &ec2.DescribeVpcEndpointConnectionsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("service-id"), Values: []string{serviceID}}},
+	}
+	for {
+		output, err := client.DescribeVpcEndpointConnections(context.TODO(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the connections of %s: %w", serviceID, err)
+		}
+		connections = append(connections, output.VpcEndpointConnections...)
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return connections, nil
+}
+
+// clusterVPCEndpoints returns the VPC endpoints to the hosted control plane, as found by hypershift-info
+func clusterVPCEndpoints(client privateLinkEC2Client, clusterID string) ([]ec2types.VpcEndpoint, error) {
+	var endpoints []ec2types.VpcEndpoint
+	input := &ec2.DescribeVpcEndpointsInput{
+		Filters: []ec2types.Filter{{Name: aws.String("tag:kubernetes.io/cluster/" + clusterID), Values: []string{"owned"}}},
+	}
+	for {
+		output, err := client.DescribeVpcEndpoints(context.TODO(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the VPC endpoints: %w", err)
+		}
+		endpoints = append(endpoints, output.VpcEndpoints...)
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	return endpoints, nil
+}
+
+// evaluateEndpointService checks the service accepts connections and that every connection to it, except
+// the deleted ones, is available
+func evaluateEndpointService(service ec2types.ServiceConfiguration, connections []ec2types.VpcEndpointConnection) []privateCheckResult {
+	serviceID := aws.ToString(service.ServiceId)
+	var results []privateCheckResult
+	if service.ServiceState != ec2types.ServiceStateAvailable {
+		results = append(results, privateCheckResult{privateCheckEndpointService, serviceID, probeFail, fmt.Sprintf("%s is %s", aws.ToString(service.ServiceName), service.ServiceState)})
+	} else {
+		results = append(results, privateCheckResult{privateCheckEndpointService, serviceID, probePass, aws.ToString(service.ServiceName)})
+	}
+
+	active := 0
+	for _, connection := range connections {
+		endpointID := aws.ToString(connection.VpcEndpointId)
+		details := fmt.Sprintf("from account %s", aws.ToString(connection.VpcEndpointOwner))
+		switch connection.VpcEndpointState {
+		case ec2types.StateAvailable:
+			active++
+			results = append(results, privateCheckResult{privateCheckConnection, endpointID, probePass, details})
+		case ec2types.StatePendingAcceptance:
+			active++
+			results = append(results, privateCheckResult{privateCheckConnection, endpointID, probeFail, details + ", pending acceptance by the endpoint service"})
+		case ec2types.StateDeleted, ec2types.StateDeleting:
+			continue
+		default:
+			active++
+			results = append(results, privateCheckResult{privateCheckConnection, endpointID, probeFail, fmt.Sprintf("%s, %s", details, connection.VpcEndpointState)})
+		}
+	}
+	if active == 0 {
+		results = append(results, privateCheckResult{privateCheckConnection, serviceID, probeFail, "no endpoint is connected to the service"})
+	}
+	return results
+}
+
+// evaluateVPCEndpoint checks the endpoint is available in a VPC of the cluster
+func evaluateVPCEndpoint(endpoint ec2types.VpcEndpoint, vpcIDs []string) privateCheckResult {
+	endpointID := aws.ToString(endpoint.VpcEndpointId)
+	vpcID := aws.ToString(endpoint.VpcId)
+	switch {
+	case endpoint.State != ec2types.StateAvailable:
+		details := fmt.Sprintf("%s to %s", endpoint.State, aws.ToString(endpoint.ServiceName))
+		if endpoint.State == ec2types.StatePendingAcceptance {
+			details += ", the endpoint service of the hosted control plane didn't accept it"
+		}
+		return privateCheckResult{privateCheckEndpoint, endpointID, probeFail, details}
+	case !contains(vpcIDs, vpcID):
+		return privateCheckResult{privateCheckEndpoint, endpointID, probeFail, fmt.Sprintf("in %s, not in the VPC of the cluster %s", vpcID, strings.Join(vpcIDs, ", "))}
+	}
+	return privateCheckResult{privateCheckEndpoint, endpointID, probePass, fmt.Sprintf("available in %s", vpcID)}
+}
+
+// checkPrivateZones checks the private hosted zones holding the name are associated with the VPC of the
+// cluster and have a record of the name, pointing to the endpoints when there are any
+func checkPrivateZones(client privateLinkRoute53Client, name string, vpcIDs []string, endpointTargets []string) []privateCheckResult {
+	zones, err := privateZonesOf(client, name)
+	if err != nil {
+		return []privateCheckResult{{privateCheckDNS, name, probeFail, err.Error()}}
+	}
+	if len(zones) == 0 {
+		return []privateCheckResult{{privateCheckDNS, name, probeFail, "no private hosted zone holds the name"}}
+	}
+
+	var results []privateCheckResult
+	for _, zone := range zones {
+		zoneName := strings.TrimSuffix(aws.ToString(zone.Name), ".")
+		output, err := client.GetHostedZone(context.TODO(), &route53.GetHostedZoneInput{Id: zone.Id})
+		if err != nil {
+			results = append(results, privateCheckResult{privateCheckDNS, name, probeFail, fmt.Sprintf("failed to get zone %s: %v", zoneName, err)})
+			continue
+		}
+		var associated []string
+		for _, vpc := range output.VPCs {
+			associated = append(associated, aws.ToString(vpc.VPCId))
+		}
+		targets, found, err := recordTargets(client, zone.Id, name)
+		if err != nil {
+			results = append(results, privateCheckResult{privateCheckDNS, name, probeFail, fmt.Sprintf("failed to list the records of %s: %v", zoneName, err)})
+			continue
+		}
+		results = append(results, evaluatePrivateZone(name, zoneName, associated, vpcIDs, found, targets, endpointTargets))
+	}
+	return results
+}
+
+// evaluatePrivateZone checks a private zone serves the name to the VPC of the cluster. The record is
+// expected to point to one of the endpoints, when the API is reached through endpoints of the cluster VPC.
+func evaluatePrivateZone(name string, zoneName string, associated []string, vpcIDs []string, found bool, targets []string, endpointTargets []string) privateCheckResult {
+	var missing []string
+	for _, vpcID := range vpcIDs {
+		if !contains(associated, vpcID) {
+			missing = append(missing, vpcID)
+		}
+	}
+	switch {
+	case len(missing) > 0:
+		return privateCheckResult{privateCheckDNS, name, probeFail, fmt.Sprintf("zone %s isn't associated with %s", zoneName, strings.Join(missing, ", "))}
+	case !found:
+		return privateCheckResult{privateCheckDNS, name, probeFail, fmt.Sprintf("zone %s has no record of the name", zoneName)}
+	case len(endpointTargets) > 0:
+		for _, target := range targets {
+			if contains(endpointTargets, target) {
+				return privateCheckResult{privateCheckDNS, name, probePass, fmt.Sprintf("%s: %s", zoneName, target)}
+			}
+		}
+		if len(targets) > 0 {
+			return privateCheckResult{privateCheckDNS, name, privateCheckWarn, fmt.Sprintf("%s: %s, not a VPC endpoint of the cluster", zoneName, strings.Join(targets, ", "))}
+		}
+	}
+	return privateCheckResult{privateCheckDNS, name, probePass, fmt.Sprintf("%s: %s", zoneName, strings.Join(targets, ", "))}
+}
+
+// privateZonesOf returns the private hosted zones of the domains of the name
+func privateZonesOf(client privateLinkRoute53Client, name string) ([]route53types.HostedZone, error) {
+	var zones []route53types.HostedZone
+	input := &route53.ListHostedZonesInput{}
+	for {
+		output, err := client.ListHostedZones(context.TODO(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the hosted zones: %w", err)
+		}
+		for _, zone := range output.HostedZones {
+			zoneName := strings.TrimSuffix(aws.ToString(zone.Name), ".")
+			if zone.Config != nil && zone.Config.PrivateZone && (name == zoneName || strings.HasSuffix(name, "."+zoneName)) {
+				zones = append(zones, zone)
+			}
+		}
+		if !output.IsTruncated {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+	return zones, nil
+}
+
+// recordTargets returns the alias, CNAME or A targets of the record of the name in the zone
+func recordTargets(client privateLinkRoute53Client, zoneID *string, name string) ([]string, bool, error) {
+	output, err := client.ListResourceRecordSets(context.TODO(), &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    zoneID,
+		StartRecordName: aws.String(name),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	var targets []string
+	found := false
+	for _, recordSet := range output.ResourceRecordSets {
+		if strings.TrimSuffix(aws.ToString(recordSet.Name), ".") != name {
+			continue
+		}
+		if recordSet.Type != route53types.RRTypeA && recordSet.Type != route53types.RRTypeCname {
+			continue
+		}
+		found = true
+		if recordSet.AliasTarget != nil {
+			targets = append(targets, strings.ToLower(strings.TrimSuffix(aws.ToString(recordSet.AliasTarget.DNSName), ".")))
+		}
+		for _, value := range recordSet.ResourceRecords {
+			targets = append(targets, strings.ToLower(strings.TrimSuffix(aws.ToString(value.Value), ".")))
+		}
+	}
+	sort.Strings(targets)
+	return targets, found, nil
+}
+
+func printPrivateCheckResults(results []privateCheckResult) int {
+	failures := 0
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "RESOURCE", "STATUS", "DETAILS"})
+	for _, result := range results {
+		if result.Status == probeFail {
+			failures++
+		}
+		table.AddRow([]string{result.Check, result.Resource, result.Status, result.Details})
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print the results: %v\n", err)
+	}
+	return failures
+}
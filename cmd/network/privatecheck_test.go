@@ -0,0 +1,113 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestEvaluateEndpointService(t *testing.T) {
+	service := ec2types.ServiceConfiguration{
+		ServiceId:    aws.String("vpce-svc-0123"),
+		ServiceName:  aws.String("com.amazonaws.vpce.us-east-1.vpce-svc-0123"),
+		ServiceState: ec2types.ServiceStateAvailable,
+	}
+	connection := func(id string, state ec2types.State) ec2types.VpcEndpointConnection {
+		return ec2types.VpcEndpointConnection{VpcEndpointId: aws.String(id), VpcEndpointOwner: aws.String("123456789012"), VpcEndpointState: state}
+	}
+
+	tests := []struct {
+		name        string
+		connections []ec2types.VpcEndpointConnection
+		expected    []string
+	}{
+		{
+			name:        "accepted",
+			connections: []ec2types.VpcEndpointConnection{connection("vpce-a", ec2types.StateAvailable)},
+			expected:    []string{probePass, probePass},
+		},
+		{
+			name:        "pending acceptance",
+			connections: []ec2types.VpcEndpointConnection{connection("vpce-a", ec2types.StatePendingAcceptance)},
+			expected:    []string{probePass, probeFail},
+		},
+		{
+			name:        "rejected",
+			connections: []ec2types.VpcEndpointConnection{connection("vpce-a", ec2types.StateRejected), connection("vpce-b", ec2types.StateAvailable)},
+			expected:    []string{probePass, probeFail, probePass},
+		},
+		{
+			name:        "only deleted",
+			connections: []ec2types.VpcEndpointConnection{connection("vpce-a", ec2types.StateDeleted)},
+			expected:    []string{probePass, probeFail},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var statuses []string
+			for _, result := range evaluateEndpointService(service, tt.connections) {
+				statuses = append(statuses, result.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.expected) {
+				t.Errorf("evaluateEndpointService() = %v, want %v", statuses, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEvaluatePrivateZone(t *testing.T) {
+	const endpoint = "vpce-0123-abcd.vpce-svc-0456.us-east-1.vpce.amazonaws.com"
+	tests := []struct {
+		name            string
+		associated      []string
+		found           bool
+		targets         []string
+		endpointTargets []string
+		expected        string
+	}{
+		{
+			name:       "classic",
+			associated: []string{"vpc-1"},
+			found:      true,
+			targets:    []string{"internal-abc-int.elb.us-east-1.amazonaws.com"},
+			expected:   probePass,
+		},
+		{
+			name:       "not associated",
+			associated: []string{"vpc-2"},
+			found:      true,
+			expected:   probeFail,
+		},
+		{
+			name:       "no record",
+			associated: []string{"vpc-1", "vpc-2"},
+			expected:   probeFail,
+		},
+		{
+			name:            "points to the endpoint",
+			associated:      []string{"vpc-1"},
+			found:           true,
+			targets:         []string{endpoint},
+			endpointTargets: []string{endpoint},
+			expected:        probePass,
+		},
+		{
+			name:            "points elsewhere",
+			associated:      []string{"vpc-1"},
+			found:           true,
+			targets:         []string{"internal-abc-int.elb.us-east-1.amazonaws.com"},
+			endpointTargets: []string{endpoint},
+			expected:        privateCheckWarn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := evaluatePrivateZone("api.test.abcd.p1.openshiftapps.com", "test.abcd.p1.openshiftapps.com", tt.associated, []string{"vpc-1"}, tt.found, tt.targets, tt.endpointTargets)
+			if result.Status != tt.expected {
+				t.Errorf("evaluatePrivateZone() = %+v, want %s", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package network
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	probeTargetAPI     = "api"
+	probeTargetIngress = "ingress"
+	probeTargetVPCE    = "vpce"
+
+	vantageInternet = "internet"
+	vantageVPC      = "vpc"
+	vantageCluster  = "cluster"
+
+	probePass = "PASS"
+	probeFail = "FAIL"
+	probeSkip = "SKIP"
+)
+
+var (
+	probeTargets = []string{probeTargetAPI, probeTargetIngress, probeTargetVPCE}
+	vantages     = []string{vantageInternet, vantageVPC, vantageCluster}
+	// vpceServices are the regional AWS endpoints private clusters usually reach through VPC endpoints
+	vpceServices = []string{"sts", "ec2", "elasticloadbalancing", "s3"}
+)
+
+type probeOptions struct {
+	clusterID   string
+	target      string
+	from        []string
+	ssmInstance string
+	reason      string
+	timeout     time.Duration
+}
+
+// probeResult is the outcome of probing one endpoint from one vantage point
+type probeResult struct {
+	Status string
+	Detail string
+}
+
+// vantagePoint probes endpoints (host:port) from somewhere on the network path to the cluster
+type vantagePoint interface {
+	probe(endpoints []string) (map[string]probeResult, error)
+}
+
+func newCmdProbe() *cobra.Command {
+	ops := &probeOptions{}
+	probeCmd := &cobra.Command{
+		Use:   "probe <cluster-id>",
+		Short: "Probe the connectivity to a cluster endpoint from several vantage points",
+		Long: `Check whether the endpoints of a cluster can be reached from several points of the network path, to
+find out where traffic is blocked:
+
+  - internet: from this machine, i.e. the public internet
+  - vpc:      from an EC2 instance of the cluster's VPC, through SSM Run Command (needs --ssm-instance)
+  - cluster:  from a pod running on the cluster (needs --reason, the pod is created as backplane-cluster-admin)
+
+Targets are the API server (api), the default ingress (ingress) or the regional AWS endpoints private
+clusters reach through VPC endpoints (vpce). Each endpoint is resolved, connected to and TLS handshaked.`,
+		Example: `  # Check where the API of a private cluster is reachable from
+  osdctl network probe <cluster-id> --target api --ssm-instance i-0123456789abcdef0 --reason OHSS-1234
+
+  # Only check the ingress from the internet
+  osdctl network probe <cluster-id> --target ingress --from internet`,
+		Args:              cobra.ExactArgs(1),
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			ops.clusterID = args[0]
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	probeCmd.Flags().StringVar(&ops.target, "target", probeTargetAPI, fmt.Sprintf("Endpoint to probe, one of %v", probeTargets))
+	probeCmd.Flags().StringSliceVar(&ops.from, "from", vantages, fmt.Sprintf("Vantage points to probe from, any of %v", vantages))
+	probeCmd.Flags().StringVar(&ops.ssmInstance, "ssm-instance", "", "ID of an SSM managed EC2 instance in the cluster's VPC, used as the vpc vantage point")
+	probeCmd.Flags().StringVar(&ops.reason, "reason", "", "The reason for this command, which requires elevation to run the probe pod (usually an OHSS or PD ticket)")
+	probeCmd.Flags().DurationVar(&ops.timeout, "timeout", 3*time.Minute, "How long to wait for the remote vantage points")
+
+	return probeCmd
+}
+
+func (o *probeOptions) validate() error {
+	if !contains(probeTargets, o.target) {
+		return fmt.Errorf("unknown target '%s', expected one of %v", o.target, probeTargets)
+	}
+	for _, from := range o.from {
+		if !contains(vantages, from) {
+			return fmt.Errorf("unknown vantage point '%s', expected any of %v", from, vantages)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *probeOptions) run() error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	endpoints, err := targetEndpoints(o.target, cluster.API().URL(), cluster.Console().URL(), cluster.Region().ID())
+	if err != nil {
+		return err
+	}
+
+	points := map[string]vantagePoint{}
+	skipped := map[string]string{}
+	for _, from := range o.from {
+		switch from {
+		case vantageInternet:
+			points[from] = &localVantage{timeout: 10 * time.Second}
+		case vantageVPC:
+			if o.ssmInstance == "" {
+				skipped[from] = "no --ssm-instance given"
+				continue
+			}
+			if strings.ToUpper(cluster.CloudProvider().ID()) != "AWS" {
+				skipped[from] = "only available for AWS clusters"
+				continue
+			}
+			points[from] = &ssmVantage{ocmClient: ocmClient, cluster: cluster, instanceID: o.ssmInstance, timeout: o.timeout}
+		case vantageCluster:
+			if o.reason == "" {
+				skipped[from] = "no --reason given to run the probe pod"
+				continue
+			}
+			points[from] = &podVantage{clusterID: cluster.ID(), reason: o.reason, timeout: o.timeout}
+		}
+	}
+
+	fmt.Printf("Probing %s of cluster %s from %s\n", o.target, cluster.Name(), strings.Join(o.from, ", "))
+	matrix := map[string]map[string]probeResult{}
+	for _, from := range o.from {
+		point, ok := points[from]
+		if !ok {
+			matrix[from] = allResults(endpoints, probeResult{Status: probeSkip, Detail: skipped[from]})
+			continue
+		}
+		results, err := point.probe(endpoints)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to probe from %s: %v\n", from, err)
+			results = allResults(endpoints, probeResult{Status: probeSkip, Detail: err.Error()})
+		}
+		matrix[from] = results
+	}
+
+	printProbeMatrix(endpoints, o.from, matrix)
+	fmt.Println()
+	for _, line := range diagnoseProbes(endpoints, matrix) {
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// targetEndpoints returns the host:port endpoints to probe for the target
+func targetEndpoints(target string, apiURL string, consoleURL string, region string) ([]string, error) {
+	switch target {
+	case probeTargetAPI:
+		return urlEndpoint(apiURL, "6443")
+	case probeTargetIngress:
+		return urlEndpoint(consoleURL, "443")
+	case probeTargetVPCE:
+		if region == "" {
+			return nil, errors.New("the cluster has no region")
+		}
+		var endpoints []string
+		for _, service := range vpceServices {
+			endpoints = append(endpoints, fmt.Sprintf("%s.%s.amazonaws.com:443", service, region))
+		}
+		return endpoints, nil
+	}
+	return nil, fmt.Errorf("unknown target '%s'", target)
+}
+
+func urlEndpoint(rawURL string, defaultPort string) ([]string, error) {
+	if rawURL == "" {
+		return nil, errors.New("the cluster doesn't expose this endpoint yet")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", rawURL, err)
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	return []string{net.JoinHostPort(parsed.Hostname(), port)}, nil
+}
+
+func allResults(endpoints []string, result probeResult) map[string]probeResult {
+	results := map[string]probeResult{}
+	for _, endpoint := range endpoints {
+		results[endpoint] = result
+	}
+	return results
+}
+
+func printProbeMatrix(endpoints []string, from []string, matrix map[string]map[string]probeResult) {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	header := []string{"ENDPOINT"}
+	for _, vantage := range from {
+		header = append(header, strings.ToUpper(vantage))
+	}
+	table.AddRow(header)
+	for _, endpoint := range endpoints {
+		row := []string{endpoint}
+		for _, vantage := range from {
+			result := matrix[vantage][endpoint]
+			cell := result.Status
+			if result.Detail != "" {
+				cell += " (" + result.Detail + ")"
+			}
+			row = append(row, cell)
+		}
+		table.AddRow(row)
+	}
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print the results: %v\n", err)
+	}
+}
+
+// diagnoseProbes explains where the traffic to each endpoint is blocked, going from the outside in
+func diagnoseProbes(endpoints []string, matrix map[string]map[string]probeResult) []string {
+	var diagnosis []string
+	for _, endpoint := range endpoints {
+		status := func(vantage string) string {
+			result, ok := matrix[vantage][endpoint]
+			if !ok {
+				return probeSkip
+			}
+			return result.Status
+		}
+		internet, vpc, cluster := status(vantageInternet), status(vantageVPC), status(vantageCluster)
+
+		var finding string
+		switch {
+		case internet != probeFail && vpc != probeFail && cluster != probeFail:
+			if internet == probeSkip && vpc == probeSkip && cluster == probeSkip {
+				continue
+			}
+			finding = "reachable from every vantage point probed"
+		case internet == probeFail && vpc == probeFail && cluster == probeFail:
+			finding = "unreachable from everywhere, check that the endpoint is up and its DNS record"
+		case vpc == probeFail && cluster == probeFail:
+			finding = "unreachable from within the VPC, check the security groups, network ACLs and the VPC endpoints/route tables"
+		case internet == probeFail && (vpc == probePass || cluster == probePass):
+			finding = "only reachable from within the VPC, expected for private clusters, otherwise check the load balancer's security group and any firewall in front of it"
+		case cluster == probeFail && vpc == probePass:
+			finding = "reachable from the VPC but not from the cluster, check the cluster proxy, egress firewall and network policies"
+		case vpc == probeFail && cluster == probePass:
+			finding = "reachable from the cluster but not from the SSM instance, check the instance's subnet and security group"
+		default:
+			finding = "partially reachable, see the results above"
+		}
+		diagnosis = append(diagnosis, fmt.Sprintf("%s: %s", endpoint, finding))
+	}
+	return diagnosis
+}
+
+// localVantage probes from the machine running osdctl
+type localVantage struct {
+	timeout time.Duration
+}
+
+func (l *localVantage) probe(endpoints []string) (map[string]probeResult, error) {
+	results := map[string]probeResult{}
+	for _, endpoint := range endpoints {
+		results[endpoint] = l.probeEndpoint(endpoint)
+	}
+	return results, nil
+}
+
+func (l *localVantage) probeEndpoint(endpoint string) probeResult {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return probeResult{Status: probeFail, Detail: err.Error()}
+	}
+	if _, err := net.LookupHost(host); err != nil {
+		return probeResult{Status: probeFail, Detail: "DNS resolution failed"}
+	}
+
+	dialer := &net.Dialer{Timeout: l.timeout}
+	start := time.Now()
+	// Only the reachability is checked, the certificate isn't the point here
+	conn, err := tls.DialWithDialer(dialer, "tcp", endpoint, &tls.Config{InsecureSkipVerify: true}) //#nosec G402 -- reachability check only
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return probeResult{Status: probeFail, Detail: "connection timed out"}
+		}
+		return probeResult{Status: probeFail, Detail: err.Error()}
+	}
+	_ = conn.Close()
+	return probeResult{Status: probePass, Detail: time.Since(start).Round(time.Millisecond).String()}
+}
+
+// probeScript returns a shell script probing the endpoints with curl and printing one
+// "<endpoint> <curl exit code> <http code>" line per endpoint, it's run by the remote vantage points
+func probeScript(endpoints []string) string {
+	sorted := append([]string{}, endpoints...)
+	sort.Strings(sorted)
+	return fmt.Sprintf(`for target in %s; do code=$(curl -sk -o /dev/null -w '%%{http_code}' --connect-timeout 5 --max-time 10 "https://${target}/"); echo "${target} $? ${code}"; done`,
+		strings.Join(sorted, " "))
+}
+
+// curlErrors explains the curl exit codes which matter to a connectivity check
+var curlErrors = map[int]string{
+	6:  "DNS resolution failed",
+	7:  "connection refused",
+	28: "connection timed out",
+	35: "TLS handshake failed",
+	56: "connection reset",
+}
+
+// parseProbeOutput parses the output of probeScript
+func parseProbeOutput(output string, endpoints []string) map[string]probeResult {
+	results := allResults(endpoints, probeResult{Status: probeSkip, Detail: "no result"})
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if _, ok := results[fields[0]]; !ok {
+			continue
+		}
+		exitCode, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		if exitCode == 0 {
+			detail := ""
+			if len(fields) > 2 {
+				detail = "HTTP " + fields[2]
+			}
+			results[fields[0]] = probeResult{Status: probePass, Detail: detail}
+			continue
+		}
+		detail, ok := curlErrors[exitCode]
+		if !ok {
+			detail = fmt.Sprintf("curl exit code %d", exitCode)
+		}
+		results[fields[0]] = probeResult{Status: probeFail, Detail: detail}
+	}
+	return results
+}
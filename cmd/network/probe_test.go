@@ -0,0 +1,116 @@
+package network
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTargetEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "api",
+			target: probeTargetAPI,
+			want:   []string{"api.test.abcd.p1.openshiftapps.com:6443"},
+		},
+		{
+			name:   "ingress",
+			target: probeTargetIngress,
+			want:   []string{"console-openshift-console.apps.test.abcd.p1.openshiftapps.com:443"},
+		},
+		{
+			name:   "vpce",
+			target: probeTargetVPCE,
+			want: []string{
+				"sts.us-east-1.amazonaws.com:443",
+				"ec2.us-east-1.amazonaws.com:443",
+				"elasticloadbalancing.us-east-1.amazonaws.com:443",
+				"s3.us-east-1.amazonaws.com:443",
+			},
+		},
+		{
+			name:    "unknown",
+			target:  "etcd",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := targetEndpoints(tt.target, "https://api.test.abcd.p1.openshiftapps.com:6443",
+				"https://console-openshift-console.apps.test.abcd.p1.openshiftapps.com", "us-east-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("targetEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("targetEndpoints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	endpoints := []string{"a:443", "b:443", "c:443", "d:443"}
+	output := "a:443 0 403\nb:443 28 000\nc:443 99 000\nunrelated line\n"
+
+	want := map[string]probeResult{
+		"a:443": {Status: probePass, Detail: "HTTP 403"},
+		"b:443": {Status: probeFail, Detail: "connection timed out"},
+		"c:443": {Status: probeFail, Detail: "curl exit code 99"},
+		"d:443": {Status: probeSkip, Detail: "no result"},
+	}
+	if got := parseProbeOutput(output, endpoints); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProbeOutput() = %v, want %v", got, want)
+	}
+}
+
+func TestDiagnoseProbes(t *testing.T) {
+	pass := probeResult{Status: probePass}
+	fail := probeResult{Status: probeFail}
+	skip := probeResult{Status: probeSkip}
+
+	tests := []struct {
+		name                   string
+		internet, vpc, cluster probeResult
+		want                   string
+	}{
+		{name: "all pass", internet: pass, vpc: pass, cluster: pass, want: "reachable from every vantage point"},
+		{name: "all fail", internet: fail, vpc: fail, cluster: fail, want: "unreachable from everywhere"},
+		{name: "private", internet: fail, vpc: pass, cluster: pass, want: "only reachable from within the VPC"},
+		{name: "cluster egress", internet: pass, vpc: pass, cluster: fail, want: "not from the cluster"},
+		{name: "vpc blocked", internet: pass, vpc: fail, cluster: fail, want: "unreachable from within the VPC"},
+		{name: "all skipped", internet: skip, vpc: skip, cluster: skip},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matrix := map[string]map[string]probeResult{
+				vantageInternet: {"e:443": tt.internet},
+				vantageVPC:      {"e:443": tt.vpc},
+				vantageCluster:  {"e:443": tt.cluster},
+			}
+			got := diagnoseProbes([]string{"e:443"}, matrix)
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("expected no diagnosis, got %v", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("diagnoseProbes() = %v, expected it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeScript(t *testing.T) {
+	script := probeScript([]string{"b:443", "a:6443"})
+	if !strings.Contains(script, "for target in a:6443 b:443;") || !strings.Contains(script, "%{http_code}") {
+		t.Errorf("unexpected script %s", script)
+	}
+}
@@ -0,0 +1,138 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmTypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/osdCloud"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	probePodPrefix    = "sre-network-probe-"
+	probePodNamespace = "default"
+)
+
+// ssmVantage probes from an EC2 instance of the cluster's VPC, running the probe script with SSM Run Command
+type ssmVantage struct {
+	ocmClient  *sdk.Connection
+	cluster    *cmv1.Cluster
+	instanceID string
+	timeout    time.Duration
+}
+
+func (s *ssmVantage) probe(endpoints []string) (map[string]probeResult, error) {
+	cfg, err := osdCloud.CreateAWSV2Config(s.ocmClient, s.cluster)
+	if err != nil {
+		return nil, err
+	}
+	ssmClient := ssm.NewFromConfig(cfg)
+
+	sent, err := ssmClient.SendCommand(context.TODO(), &ssm.SendCommandInput{
+		InstanceIds:  []string{s.instanceID},
+		DocumentName: aws.String("AWS-RunShellScript"),
+		Comment:      aws.String("osdctl network probe"),
+		Parameters:   map[string][]string{"commands": {probeScript(endpoints)}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the SSM command to %s: %w", s.instanceID, err)
+	}
+	commandID := aws.ToString(sent.Command.CommandId)
+
+	var invocation *ssm.GetCommandInvocationOutput
+	pollErr := wait.PollImmediate(5*time.Second, s.timeout, func() (bool, error) {
+		invocation, err = ssmClient.GetCommandInvocation(context.TODO(), &ssm.GetCommandInvocationInput{
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(s.instanceID),
+		})
+		if err != nil {
+			// The invocation isn't visible right after the command is sent
+			var notYet *ssmTypes.InvocationDoesNotExist
+			if errors.As(err, &notYet) {
+				return false, nil
+			}
+			return false, err
+		}
+		switch invocation.Status {
+		case ssmTypes.CommandInvocationStatusPending, ssmTypes.CommandInvocationStatusInProgress, ssmTypes.CommandInvocationStatusDelayed:
+			return false, nil
+		}
+		return true, nil
+	})
+	if pollErr != nil {
+		return nil, fmt.Errorf("SSM command %s didn't complete: %w", commandID, pollErr)
+	}
+	if invocation.Status != ssmTypes.CommandInvocationStatusSuccess {
+		return nil, fmt.Errorf("SSM command %s ended with status %s: %s", commandID, invocation.Status, strings.TrimSpace(aws.ToString(invocation.StandardErrorContent)))
+	}
+	return parseProbeOutput(aws.ToString(invocation.StandardOutputContent), endpoints), nil
+}
+
+// podVantage probes from a short lived pod running on the cluster's workers
+type podVantage struct {
+	clusterID string
+	reason    string
+	timeout   time.Duration
+}
+
+func (p *podVantage) probe(endpoints []string) (map[string]probeResult, error) {
+	_, _, clientset, err := common.GetKubeConfigAndClient(p.clusterID, p.reason)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: probePodPrefix,
+			Namespace:    probePodNamespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			NodeSelector:  map[string]string{nodeLabelKey: nodeLabelValue},
+			Containers: []corev1.Container{{
+				Name:    "probe",
+				Image:   packetCaptureImage,
+				Command: []string{"/bin/bash", "-c", probeScript(endpoints)},
+			}},
+		},
+	}
+	pods := clientset.CoreV1().Pods(probePodNamespace)
+	pod, err = pods.Create(context.TODO(), pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the probe pod: %w", err)
+	}
+	defer func() {
+		if err := pods.Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete the probe pod %s/%s: %v\n", pod.Namespace, pod.Name, err)
+		}
+	}()
+
+	pollErr := wait.PollImmediate(5*time.Second, p.timeout, func() (bool, error) {
+		current, err := pods.Get(context.TODO(), pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return current.Status.Phase == corev1.PodSucceeded || current.Status.Phase == corev1.PodFailed, nil
+	})
+	if pollErr != nil {
+		return nil, fmt.Errorf("the probe pod %s/%s didn't complete: %w", pod.Namespace, pod.Name, pollErr)
+	}
+
+	logs, err := pods.GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the logs of the probe pod: %w", err)
+	}
+	return parseProbeOutput(string(logs), endpoints), nil
+}
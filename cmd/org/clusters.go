@@ -15,6 +15,7 @@ import (
 var (
 	allClustersFlag = false
 	awsAccountID    = ""
+	clustersPages   Pagination
 	clustersCmd     = &cobra.Command{
 		Use:   "clusters",
 		Short: "get all active organization clusters",
@@ -29,6 +30,9 @@ osdctl org clusters 123456789AbcDEfGHiJklMnopQR -o json
 Retrieving all clusters for a given organizational unit regardless of status:
 osdctl org clusters 123456789AbcDEfGHiJklMnopQR --all
 
+Retrieving the second page of 100 active clusters for a given organizational unit:
+osdctl org clusters 123456789AbcDEfGHiJklMnopQR --limit 100 --page 2
+
 Retrieving all active clusters for a given AWS profile:
 osdctl org clusters --aws-profile my-aws-profile --aws-account-id 123456789
 `,
@@ -45,9 +49,11 @@ osdctl org clusters --aws-profile my-aws-profile --aws-account-id 123456789
 				status = StatusActive
 			}
 
-			clusters, err := SearchSubscriptions(orgId, status)
+			cmdutil.CheckErr(clustersPages.validate())
+			clusters, more, err := searchSubscriptionsPage(orgId, status, clustersPages)
 			cmdutil.CheckErr(err)
 			printClusters(clusters)
+			printNextPageHint(clustersPages, more)
 		},
 	}
 )
@@ -81,31 +87,32 @@ func init() {
 	)
 
 	AddOutputFlag(flags)
+	AddPaginationFlags(flags, &clustersPages)
 }
 
 func SearchSubscriptions(orgId string, status string) ([]*accountsv1.Subscription, error) {
+	clusterSubscriptions, _, err := searchSubscriptionsPage(orgId, status, Pagination{})
+	return clusterSubscriptions, err
+}
+
+func searchSubscriptionsPage(orgId string, status string, pagination Pagination) ([]*accountsv1.Subscription, bool, error) {
 	if orgId == "" && !isAWSProfileSearch() {
-		return nil, fmt.Errorf("specify either org-id or --aws-profile,--aws-account-id arguments")
+		return nil, false, fmt.Errorf("specify either org-id or --aws-profile,--aws-account-id arguments")
 	}
 
 	if orgId != "" && isAWSProfileSearch() {
-		return nil, fmt.Errorf("specify either an org id argument or --aws-profile, --aws-account-id arguments")
+		return nil, false, fmt.Errorf("specify either an org id argument or --aws-profile, --aws-account-id arguments")
 	}
 
 	if isAWSProfileSearch() {
 		orgIdFromAws, err := getOrganizationIdFromAWSProfile()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get org ID from AWS profile: %w", err)
+			return nil, false, fmt.Errorf("failed to get org ID from AWS profile: %w", err)
 		}
 		orgId = *orgIdFromAws
 	}
 
-	clusterSubscriptions, err := SearchAllSubscriptionsByOrg(orgId, status, false)
-	if err != nil {
-		return nil, err
-	}
-
-	return clusterSubscriptions, nil
+	return SearchSubscriptionsPage(orgId, status, false, pagination)
 }
 
 func getOrganizationIdFromAWSProfile() (*string, error) {
@@ -99,6 +99,52 @@ func PrintJson(data interface{}) {
 	dump.Pretty(os.Stdout, marshalledStruct)
 }
 
+// Pagination selects a page of the clusters of an organization, all of them when Limit is 0
+type Pagination struct {
+	Limit int
+	Page  int
+}
+
+// AddPaginationFlags adds the --limit and --page flags of the commands going through the clusters of an organization
+func AddPaginationFlags(flags *pflag.FlagSet, pagination *Pagination) {
+	flags.IntVar(&pagination.Limit, "limit", 0, "Only go through this many clusters of the organization, all of them when 0")
+	flags.IntVar(&pagination.Page, "page", 1, "Page of --limit clusters to go through, starting at 1")
+}
+
+func (p Pagination) validate() error {
+	if p.Limit < 0 {
+		return fmt.Errorf("--limit can't be negative")
+	}
+	if p.Page < 1 {
+		return fmt.Errorf("--page starts at 1")
+	}
+	if p.Page > 1 && p.Limit == 0 {
+		return fmt.Errorf("--page requires --limit")
+	}
+	return nil
+}
+
+// SearchSubscriptionsPage returns the subscriptions of the page of the organization, and whether more pages follow
+func SearchSubscriptionsPage(orgID string, status string, managedOnly bool, pagination Pagination) ([]*accountsv1.Subscription, bool, error) {
+	if pagination.Limit == 0 {
+		subscriptions, err := SearchAllSubscriptionsByOrg(orgID, status, managedOnly)
+		return subscriptions, false, err
+	}
+	response, err := getSubscriptions(orgID, status, managedOnly, pagination.Page, pagination.Limit)
+	if err != nil {
+		return nil, false, fmt.Errorf("encountered an error fetching subscriptions for page %v: %w", pagination.Page, err)
+	}
+	return response.Items().Slice(), response.Total() > pagination.Page*pagination.Limit, nil
+}
+
+// printNextPageHint tells how to go through the next page of clusters, on stderr so the results can be piped
+func printNextPageHint(pagination Pagination, more bool) {
+	if !more {
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "More clusters follow, run again with --limit %d --page %d for the next ones\n", pagination.Limit, pagination.Page+1)
+}
+
 func SearchAllSubscriptionsByOrg(orgID string, status string, managedOnly bool) ([]*accountsv1.Subscription, error) {
 	var clusterSubscriptions []*accountsv1.Subscription
 	requestPageSize := 100
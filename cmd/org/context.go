@@ -5,18 +5,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	pd "github.com/PagerDuty/go-pagerduty"
 	"github.com/andygrunwald/go-jira"
 	sdk "github.com/openshift-online/ocm-sdk-go"
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
 	"github.com/openshift/osdctl/cmd/servicelog"
-	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/interrupt"
 	pdProvider "github.com/openshift/osdctl/pkg/provider/pagerduty"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
@@ -26,6 +29,8 @@ import (
 
 const (
 	ServiceLogDaysSince = 30
+	// ContextMaxConcurrency is the number of clusters queried in parallel by 'org context'
+	ContextMaxConcurrency = 10
 )
 
 type ClusterInfo struct {
@@ -38,6 +43,7 @@ type ClusterInfo struct {
 	ServiceLogs           []*v1.LogEntry
 	PdAlerts              map[string][]pd.Incident
 	JiraIssues            []jira.Issue
+	JiraIssuesTotal       int
 	LimitedSupportReasons []*cmv1.LimitedSupportReason
 }
 
@@ -54,16 +60,57 @@ type clusterInfoView struct {
 	OHSS        int     `json:"ohssTickets"`
 }
 
+// contextCheckpointEntry is a line of the checkpoint of 'org context', a cluster whose data was fully collected
+type contextCheckpointEntry struct {
+	OrgID string `json:"orgId"`
+	clusterInfoView
+}
+
+// contextColumns are the columns of 'org context', wide enough for most values as the rows are printed as the
+// clusters complete
+var contextColumns = []struct {
+	title string
+	width int
+}{
+	{"DISPLAY NAME", 30}, {"CLUSTER ID", 32}, {"VERSION", 8}, {"STATUS", 15}, {"PROVIDER", 8}, {"PLAN", 5},
+	{"NODE COUNT", 10}, {"RECENT SLs", 10}, {"ACTIVE PDs", 10}, {"OHSS TICKETS", 12},
+}
+
+// contextCollection configures how the context of the clusters of an organization is collected
+type contextCollection struct {
+	pagination Pagination
+	// skip are the clusters collected already, e.g. by an interrupted run
+	skip map[string]bool
+	// progress, if set, receives the progress of the collection
+	progress io.Writer
+	// onCluster is called with each cluster once collected, with the failure of the data which couldn't be
+	// collected for it. It's never called concurrently.
+	onCluster func(info ClusterInfo, err error)
+}
+
+var (
+	contextPages      Pagination
+	contextCheckpoint string
+)
+
 var contextCmd = &cobra.Command{
 	Use:   "context orgId",
 	Short: "fetches information about the given organization",
 	Long: `Fetches information about the given organization. This data is presented as a table where each row includes the name, version, ID, cloud provider, and plan for the cluster.
-Rows will also include the number of recent service logs, active PD Alerts, Jira Issues, and limited support status for that specific cluster.`,
+Rows will also include the number of recent service logs, active PD Alerts, Jira Issues, and limited support status for that specific cluster.
+
+The rows are printed as the clusters complete, and a cluster whose data couldn't all be fetched is still printed with
+what was. Large organizations can be gone through a page of clusters at a time with --limit and --page. When
+interrupted, the clusters collected so far are saved to the --checkpoint file, or a temporary one, and skipped by the
+next run given the same checkpoint.`,
 	Example: `# Get context data for a cluster
 osdctl org context 1a2B3c4DefghIjkLMNOpQrSTUV5
 
 #Get context data for cluster in json format
-osdctl org context 1a2B3c4DefghIjkLMNOpQrSTUV5 -o json`,
+osdctl org context 1a2B3c4DefghIjkLMNOpQrSTUV5 -o json
+
+# Get context data for the first 50 clusters, keeping what was collected to resume when interrupted
+osdctl org context 1a2B3c4DefghIjkLMNOpQrSTUV5 --limit 50 --checkpoint org-context.jsonl`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		outputFormat, err := cmd.Flags().GetString("output")
@@ -73,56 +120,150 @@ osdctl org context 1a2B3c4DefghIjkLMNOpQrSTUV5 -o json`,
 		if outputFormat != "" && outputFormat != "json" {
 			return errors.New("unsupported output format, only 'json' is accepted")
 		}
+		if err := contextPages.validate(); err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		orgId := args[0]
+
+		collected, err := loadContextCheckpoint(contextCheckpoint, orgId)
+		if err != nil {
+			return err
+		}
+		var checkpoint *os.File
+		if contextCheckpoint != "" {
+			if checkpoint, err = os.OpenFile(contextCheckpoint, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600); err != nil {
+				return fmt.Errorf("failed to open the checkpoint: %w", err)
+			}
+			defer checkpoint.Close()
+		}
+
+		// The rows are streamed as the clusters complete, the JSON array is printed once they all did
+		views := make([]clusterInfoView, 0, len(collected))
+		complete := append([]clusterInfoView{}, collected...)
+		header := true
+		addView := func(view clusterInfoView) {
+			views = append(views, view)
+			if outputFormat == "json" {
+				return
+			}
+			if header {
+				fmt.Println(formatContextRow(contextColumnTitles()))
+				header = false
+			}
+			fmt.Println(formatContextRow(view.row()))
+		}
+
+		collection := contextCollection{pagination: contextPages, skip: map[string]bool{}}
+		for _, view := range collected {
+			collection.skip[view.ClusterId] = true
+			addView(view)
+		}
+		if outputFormat == "json" {
+			// The progress is updated in place, which would overwrite the streamed rows
+			collection.progress = os.Stderr
+		}
+		collection.onCluster = func(info ClusterInfo, err error) {
+			view := newClusterInfoView(info)
+			addView(view)
+			if err != nil {
+				return
+			}
+			complete = append(complete, view)
+			if checkpoint != nil {
+				if err := appendContextCheckpoint(checkpoint, orgId, view); err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "failed to save cluster %s to the checkpoint: %v\n", view.ClusterId, err)
+				}
+			}
+		}
 
-		clusterInfos, err := Context(args[0])
+		err = collectContext(ctx, orgId, collection)
+		if ctx.Err() != nil {
+			if outputFormat == "json" && len(views) > 0 {
+				_ = printContextJson(views)
+			}
+			return saveInterruptedContext(orgId, checkpoint, complete)
+		}
 		if err != nil {
 			// report error, but don't return: we should make a best-effort attempt at printing whatever we did retrieve
 			fmt.Fprintf(os.Stderr, "error fetching org context: %v\n", err)
 		}
-		if len(clusterInfos) == 0 {
+		if len(views) == 0 {
 			fmt.Println("Org has no clusters")
 			return nil
 		}
 
 		if outputFormat == "json" {
-			return printContextJson(clusterInfos)
+			return printContextJson(views)
 		}
-
-		return printContext(clusterInfos)
+		return nil
 	},
 }
 
 func init() {
 	contextCmd.Flags().StringP("output", "o", "", "output format for the results. only supported value currently is 'json'")
+	contextCmd.Flags().StringVar(&contextCheckpoint, "checkpoint", "", "File the clusters collected are saved to as they complete, the clusters it has already are skipped")
+	AddPaginationFlags(contextCmd.Flags(), &contextPages)
+	interrupt.MarkCancellable(contextCmd)
 }
 
-func printContextJson(clusterInfos []ClusterInfo) error {
-	clusterInfoViews := make([]clusterInfoView, 0, len(clusterInfos))
-	for _, clusterInfo := range clusterInfos {
-		plan := clusterInfo.Plan
-		if plan == "MOA" {
-			plan = "ROSA"
-		}
-		if plan == "MOA-HostedControlPlane" {
-			plan = "HCP"
-		}
+func newClusterInfoView(clusterInfo ClusterInfo) clusterInfoView {
+	return clusterInfoView{
+		DisplayName: clusterInfo.Name,
+		ClusterId:   clusterInfo.ID,
+		Version:     clusterInfo.Version,
+		Status:      getSupportStatusDisplayText(clusterInfo.LimitedSupportReasons),
+		Provider:    clusterInfo.CloudProvider,
+		Plan:        getPlanDisplayText(clusterInfo.Plan),
+		NodeCount:   clusterInfo.NodeCount,
+		RecentSLs:   len(clusterInfo.ServiceLogs),
+		ActivePDs:   len(clusterInfo.PdAlerts),
+		OHSS:        clusterInfo.JiraIssuesTotal,
+	}
+}
 
-		view := clusterInfoView{
-			DisplayName: clusterInfo.Name,
-			ClusterId:   clusterInfo.ID,
-			Version:     clusterInfo.Version,
-			Status:      getSupportStatusDisplayText(clusterInfo.LimitedSupportReasons),
-			Provider:    clusterInfo.CloudProvider,
-			Plan:        plan,
-			NodeCount:   clusterInfo.NodeCount,
-			RecentSLs:   len(clusterInfo.ServiceLogs),
-			ActivePDs:   len(clusterInfo.PdAlerts),
-			OHSS:        len(clusterInfo.JiraIssues),
-		}
+func (view clusterInfoView) row() []string {
+	return []string{
+		view.DisplayName,
+		view.ClusterId,
+		view.Version,
+		view.Status,
+		view.Provider,
+		view.Plan,
+		fmt.Sprintf("%v", view.NodeCount),
+		strconv.Itoa(view.RecentSLs),
+		strconv.Itoa(view.ActivePDs),
+		strconv.Itoa(view.OHSS),
+	}
+}
+
+func contextColumnTitles() []string {
+	titles := make([]string, 0, len(contextColumns))
+	for _, column := range contextColumns {
+		titles = append(titles, column.title)
+	}
+	return titles
+}
 
-		clusterInfoViews = append(clusterInfoViews, view)
+// formatContextRow pads the values to the width of their column, the longer ones shift the rest of the row
+func formatContextRow(values []string) string {
+	var row strings.Builder
+	for i, value := range values {
+		if i > 0 {
+			row.WriteString("   ")
+		}
+		if i < len(values)-1 {
+			value = fmt.Sprintf("%-*s", contextColumns[i].width, value)
+		}
+		row.WriteString(value)
 	}
+	return row.String()
+}
 
+func printContextJson(clusterInfoViews []clusterInfoView) error {
 	bytes, err := json.MarshalIndent(clusterInfoViews, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal json response: %w", err)
@@ -132,33 +273,69 @@ func printContextJson(clusterInfos []ClusterInfo) error {
 	return nil
 }
 
-func printContext(clusterInfos []ClusterInfo) error {
-	table := printer.NewTablePrinter(os.Stdout, 0, 1, 3, ' ')
-	table.AddRow([]string{"DISPLAY NAME", "CLUSTER ID", "VERSION", "STATUS", "PROVIDER", "PLAN", "NODE COUNT", "RECENT SLs", "ACTIVE PDs", "OHSS TICKETS"})
-
-	for _, clusterInfo := range clusterInfos {
-		recentSLs := len(clusterInfo.ServiceLogs)
-		activePDs := len(clusterInfo.PdAlerts)
-		ohss := len(clusterInfo.JiraIssues)
-		table.AddRow([]string{
-			clusterInfo.Name,
-			clusterInfo.ID,
-			clusterInfo.Version,
-			getSupportStatusDisplayText(clusterInfo.LimitedSupportReasons),
-			clusterInfo.CloudProvider,
-			getPlanDisplayText(clusterInfo.Plan),
-			fmt.Sprintf("%v", clusterInfo.NodeCount),
-			strconv.Itoa(recentSLs),
-			strconv.Itoa(activePDs),
-			strconv.Itoa(ohss),
-		})
+// loadContextCheckpoint returns the clusters saved to the checkpoint, none when there's no checkpoint yet. The last
+// line may have been cut by an interruption, and is ignored when it can't be parsed.
+func loadContextCheckpoint(path string, orgId string) ([]clusterInfoView, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the checkpoint: %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	var views []clusterInfoView
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var entry contextCheckpointEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("invalid line %d of the checkpoint: %w", i+1, err)
+		}
+		if entry.OrgID != orgId {
+			return nil, fmt.Errorf("the checkpoint %s is the one of organization %s, not %s", path, entry.OrgID, orgId)
+		}
+		views = append(views, entry.clusterInfoView)
+	}
+	return views, nil
+}
 
-	table.AddRow([]string{})
-	if err := table.Flush(); err != nil {
-		return fmt.Errorf("error writing data to console: %w", err)
+func appendContextCheckpoint(checkpoint io.Writer, orgId string, view clusterInfoView) error {
+	line, err := json.Marshal(contextCheckpointEntry{OrgID: orgId, clusterInfoView: view})
+	if err != nil {
+		return err
 	}
-	return nil
+	_, err = checkpoint.Write(append(line, '\n'))
+	return err
+}
+
+// saveInterruptedContext tells how to resume an interrupted run, saving what was collected to a temporary checkpoint
+// when none was given
+func saveInterruptedContext(orgId string, checkpoint *os.File, complete []clusterInfoView) error {
+	if checkpoint != nil {
+		return fmt.Errorf("interrupted, the %d clusters collected are saved to %s, run again with --checkpoint %s to resume", len(complete), checkpoint.Name(), checkpoint.Name())
+	}
+	if len(complete) == 0 {
+		return errors.New("interrupted before any cluster was collected")
+	}
+	file, err := os.CreateTemp("", fmt.Sprintf("osdctl-org-context-%s-*.jsonl", orgId))
+	if err != nil {
+		return fmt.Errorf("interrupted, and failed to save the clusters collected: %w", err)
+	}
+	defer file.Close()
+	for _, view := range complete {
+		if err := appendContextCheckpoint(file, orgId, view); err != nil {
+			return fmt.Errorf("interrupted, and failed to save the clusters collected: %w", err)
+		}
+	}
+	return fmt.Errorf("interrupted, the %d clusters collected are saved to %s, run again with --checkpoint %s to resume", len(complete), file.Name(), file.Name())
 }
 
 func getSupportStatusDisplayText(limitedSupportReasons []*cmv1.LimitedSupportReason) string {
@@ -178,101 +355,126 @@ func getPlanDisplayText(plan string) string {
 	return plan
 }
 
+// Context returns the context of the active clusters of the organization. The clusters whose data couldn't all be
+// fetched are returned with what was, and their failures returned together.
 func Context(orgId string) ([]ClusterInfo, error) {
-	clusterSubscriptions, err := SearchAllSubscriptionsByOrg(orgId, StatusActive, true)
+	var orgClustersInfo []ClusterInfo
+	err := collectContext(context.Background(), orgId, contextCollection{
+		progress: os.Stderr,
+		onCluster: func(info ClusterInfo, _ error) {
+			orgClustersInfo = append(orgClustersInfo, info)
+		},
+	})
+	return orgClustersInfo, err
+}
+
+// collectContext collects the context of the active clusters of the page of the organization. Once ctx is canceled,
+// the clusters not collected yet are left out.
+func collectContext(ctx context.Context, orgId string, collection contextCollection) error {
+	clusterSubscriptions, more, err := SearchSubscriptionsPage(orgId, StatusActive, true, collection.pagination)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch cluster subscriptions for org with ID %s: %w", orgId, err)
+		return fmt.Errorf("failed to fetch cluster subscriptions for org with ID %s: %w", orgId, err)
 	}
+	defer printNextPageHint(collection.pagination, more)
 
-	clusterSubscriptionsCount := len(clusterSubscriptions)
-	if clusterSubscriptionsCount == 0 {
-		return nil, nil
+	subscriptionsByClusterID := make(map[string]*amv1.Subscription, len(clusterSubscriptions))
+	clusterIDs := make([]string, 0, len(clusterSubscriptions))
+	for _, subscription := range clusterSubscriptions {
+		if collection.skip[subscription.ClusterID()] {
+			continue
+		}
+		subscriptionsByClusterID[subscription.ClusterID()] = subscription
+		clusterIDs = append(clusterIDs, subscription.ClusterID())
+	}
+	if len(clusterIDs) == 0 {
+		return nil
 	}
 
 	// cluster info
 	ocmClient, err := utils.CreateConnection()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create OCM client: %w", err)
+		return fmt.Errorf("failed to create OCM client: %w", err)
 	}
 	defer ocmClient.Close()
 
-	var orgClustersInfo []ClusterInfo
-
-	eg, ctx := errgroup.WithContext(context.Background())
 	var mutex sync.Mutex
-	count := 0
 
 	// Print these to stderr so the actual results can be piped to different parsers without worrying about these lines
-	_, _ = fmt.Fprintf(os.Stderr, "Fetching data for %v clusters in org %v...\n", clusterSubscriptionsCount, orgId)
-	_, _ = fmt.Fprintf(os.Stderr, "Fetched data for 0 of %v clusters...\n", clusterSubscriptionsCount)
-	for _, subscription := range clusterSubscriptions {
-		sub := subscription
-		eg.Go(func() error {
-			defer ctx.Done()
-			clusterId := sub.ClusterID()
-			cluster, getClusterErr := utils.GetCluster(ocmClient, clusterId)
-			if getClusterErr != nil {
-				return fmt.Errorf("failed to get cluster %s: %w", clusterId, getClusterErr)
-			}
+	if skipped := len(clusterSubscriptions) - len(clusterIDs); skipped > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Fetching data for %v clusters in org %v, %v were collected already...\n", len(clusterIDs), orgId, skipped)
+	} else {
+		_, _ = fmt.Fprintf(os.Stderr, "Fetching data for %v clusters in org %v...\n", len(clusterIDs), orgId)
+	}
+	fanOutErr := utils.FanOut(clusterIDs, utils.FanOutOptions{
+		MaxConcurrency: ContextMaxConcurrency,
+		Progress:       collection.progress,
+		Action:         "Fetched data",
+		Noun:           "clusters",
+	}, func(clusterId string) error {
+		if ctx.Err() != nil {
+			return errors.New("interrupted before it was collected")
+		}
+		sub := subscriptionsByClusterID[clusterId]
+		cluster, getClusterErr := utils.GetCluster(ocmClient, clusterId)
+		if getClusterErr != nil {
+			return fmt.Errorf("failed to get cluster %s: %w", clusterId, getClusterErr)
+		}
 
-			clusterInfo := ClusterInfo{
-				Name:          cluster.Name(),
-				Version:       cluster.Version().RawID(),
-				ID:            cluster.ID(),
-				CloudProvider: sub.CloudProviderID(),
-				Plan:          sub.Plan().ID(),
-			}
+		clusterInfo := ClusterInfo{
+			Name:          cluster.Name(),
+			Version:       cluster.Version().RawID(),
+			ID:            cluster.ID(),
+			CloudProvider: sub.CloudProviderID(),
+			Plan:          sub.Plan().ID(),
+		}
 
-			if metrics, ok := sub.GetMetrics(); ok {
-				clusterInfo.NodeCount = metrics[0].Nodes().Total()
-			}
+		if metrics, ok := sub.GetMetrics(); ok {
+			clusterInfo.NodeCount = metrics[0].Nodes().Total()
+		}
 
-			dataErrs, dataCtx := errgroup.WithContext(context.Background())
-			dataErrs.Go(func() error {
-				defer dataCtx.Done()
-				ci := &clusterInfo
-				return addLimitedSupportReasons(ci, ocmClient)
-			})
-
-			dataErrs.Go(func() error {
-				defer dataCtx.Done()
-				ci := &clusterInfo
-				return addServiceLogs(ci)
-			})
-
-			dataErrs.Go(func() error {
-				defer dataCtx.Done()
-				ci := &clusterInfo
-				externalId := cluster.ExternalID()
-				return addJiraIssues(ci, externalId)
-			})
-
-			dataErrs.Go(func() error {
-				defer dataCtx.Done()
-				ci := &clusterInfo
-				baseDomain := cluster.DNS().BaseDomain()
-				return addPDAlerts(ci, baseDomain)
-			})
-
-			if errs := dataErrs.Wait(); errs != nil {
-				return errs
-			}
+		dataErrs, dataCtx := errgroup.WithContext(context.Background())
+		dataErrs.Go(func() error {
+			defer dataCtx.Done()
+			ci := &clusterInfo
+			return addLimitedSupportReasons(ci, ocmClient)
+		})
 
-			mutex.Lock()
-			_, _ = fmt.Fprintf(os.Stderr, "\033[1A\033[K")
-			count++
-			_, _ = fmt.Fprintf(os.Stderr, "Fetched data for %v of %v clusters...\n", count, clusterSubscriptionsCount)
-			orgClustersInfo = append(orgClustersInfo, clusterInfo)
-			mutex.Unlock()
+		dataErrs.Go(func() error {
+			defer dataCtx.Done()
+			ci := &clusterInfo
+			return addServiceLogs(ci)
+		})
 
-			return nil
+		dataErrs.Go(func() error {
+			defer dataCtx.Done()
+			ci := &clusterInfo
+			externalId := cluster.ExternalID()
+			return addJiraIssues(ci, externalId)
 		})
-	}
 
-	if err := eg.Wait(); err != nil {
-		return orgClustersInfo, fmt.Errorf("failed to get context data: %w", err)
+		dataErrs.Go(func() error {
+			defer dataCtx.Done()
+			ci := &clusterInfo
+			baseDomain := cluster.DNS().BaseDomain()
+			return addPDAlerts(ci, baseDomain)
+		})
+
+		// The cluster is kept with the data which could be fetched, unless it was cut short by an interruption
+		errs := dataErrs.Wait()
+		if ctx.Err() != nil {
+			return errors.New("interrupted before it was collected")
+		}
+
+		mutex.Lock()
+		collection.onCluster(clusterInfo, errs)
+		mutex.Unlock()
+
+		return errs
+	})
+	if fanOutErr != nil {
+		return fmt.Errorf("failed to get context data: %w", fanOutErr)
 	}
-	return orgClustersInfo, nil
+	return nil
 }
 
 func addLimitedSupportReasons(clusterInfo *ClusterInfo, ocmClient *sdk.Connection) error {
@@ -295,11 +497,12 @@ func addServiceLogs(clusterInfo *ClusterInfo) error {
 }
 
 func addJiraIssues(clusterInfo *ClusterInfo, externalId string) error {
-	var jiraIssuesErr error
-	clusterInfo.JiraIssues, jiraIssuesErr = utils.GetJiraIssuesForCluster(clusterInfo.ID, externalId)
+	result, jiraIssuesErr := utils.GetJiraIssuesForCluster(clusterInfo.ID, externalId, utils.DefaultJiraIssueLimit)
 	if jiraIssuesErr != nil {
 		return fmt.Errorf("failed to fetch Jira issues for cluster %v: %v", clusterInfo.ID, jiraIssuesErr)
 	}
+	clusterInfo.JiraIssues = result.Issues
+	clusterInfo.JiraIssuesTotal = result.Total
 	return nil
 }
 
@@ -0,0 +1,94 @@
+package org
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadContextCheckpoint(t *testing.T) {
+	first := clusterInfoView{DisplayName: "first", ClusterId: "1a2b", Status: "Fully Supported", NodeCount: 9, RecentSLs: 2}
+	second := clusterInfoView{DisplayName: "second", ClusterId: "3c4d", Status: "Limited Support", ActivePDs: 1}
+
+	var saved bytes.Buffer
+	for _, view := range []clusterInfoView{first, second} {
+		if err := appendContextCheckpoint(&saved, "org-1", view); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		orgID     string
+		want      []clusterInfoView
+		expectErr bool
+	}{
+		{
+			name:    "saved clusters",
+			content: saved.String(),
+			orgID:   "org-1",
+			want:    []clusterInfoView{first, second},
+		},
+		{
+			name:    "last line cut by an interruption",
+			content: saved.String() + `{"orgId":"org-1","displayName":"thi`,
+			orgID:   "org-1",
+			want:    []clusterInfoView{first, second},
+		},
+		{
+			name:      "checkpoint of another organization",
+			content:   saved.String(),
+			orgID:     "org-2",
+			expectErr: true,
+		},
+		{
+			name:      "invalid line",
+			content:   "{\n" + saved.String(),
+			orgID:     "org-1",
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := loadContextCheckpoint(path, tt.orgID)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("loadContextCheckpoint() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if !tt.expectErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadContextCheckpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, err := loadContextCheckpoint(filepath.Join(t.TempDir(), "missing.jsonl"), "org-1"); err != nil || got != nil {
+		t.Errorf("loadContextCheckpoint() of a missing checkpoint = %v, %v, want no clusters", got, err)
+	}
+}
+
+func TestPaginationValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		pagination Pagination
+		expectErr  bool
+	}{
+		{name: "all clusters", pagination: Pagination{Page: 1}},
+		{name: "second page", pagination: Pagination{Limit: 50, Page: 2}},
+		{name: "page without limit", pagination: Pagination{Page: 2}, expectErr: true},
+		{name: "negative limit", pagination: Pagination{Limit: -1, Page: 1}, expectErr: true},
+		{name: "page zero", pagination: Pagination{Limit: 50}, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.pagination.validate(); (err != nil) != tt.expectErr {
+				t.Errorf("validate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -16,8 +16,10 @@ import (
 
 var (
 	labelsCmd = &cobra.Command{
-		Use:           "labels",
-		Short:         "get organization labels",
+		Use:   "labels",
+		Short: "get organization labels",
+		Long: `Get the labels of an organization, or with --subscription of a subscription. Use the add and remove
+subcommands to manage them.`,
 		Args:          cobra.ArbitraryArgs,
 		SilenceErrors: true,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -32,15 +34,20 @@ type LabelItems struct {
 }
 
 type Label struct {
-	ID    string `json:"id"`
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	ID       string `json:"id"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Internal bool   `json:"internal"`
 }
 
 func init() {
 	flags := labelsCmd.Flags()
 
 	AddOutputFlag(flags)
+	addLabelsSubscriptionFlag(flags)
+
+	labelsCmd.AddCommand(labelsAddCmd)
+	labelsCmd.AddCommand(labelsRemoveCmd)
 }
 
 func searchLabelsByOrg(cmd *cobra.Command, orgID string) error {
@@ -77,7 +84,7 @@ func getLabels(orgID string) (*sdk.Response, error) {
 func createGetLabelsRequest(ocmClient *sdk.Connection, orgID string) *sdk.Request {
 	// Create and populate the request:
 	request := ocmClient.Get()
-	labelsApiPath := organizationsAPIPath + "/" + orgID + "/labels"
+	labelsApiPath := labelsAPIPath(orgID)
 
 	err := arguments.ApplyPathArg(request, labelsApiPath)
 
@@ -0,0 +1,295 @@
+package org
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	subscriptionsAPIPath = "/api/accounts_mgmt/v1/subscriptions"
+
+	// LabelAllowlistConfigKey extends the keys that can be added without --force, '*' matches any suffix
+	LabelAllowlistConfigKey = "org_label_allowlist"
+
+	labelActionAdd    = "add"
+	labelActionUpdate = "update"
+	labelActionRemove = "remove"
+)
+
+// defaultLabelAllowlist are the feature gates and routing labels SRE manages on organizations and subscriptions
+var defaultLabelAllowlist = []string{
+	"capability.organization.*",
+	"capability.cluster.*",
+	"capability.account.*",
+	"sre.routing.*",
+}
+
+var (
+	labelsSubscription bool
+	labelsForce        bool
+	labelsInternal     bool
+
+	labelsAddCmd = &cobra.Command{
+		Use:   "add <org-id> <key>=<value>",
+		Short: "Add or update a label of an organization",
+		Long: `Add a label to an organization, or with --subscription to a subscription. An existing label with the
+same key is updated. Only the keys of the allowlist can be set unless --force is passed, the allowlist is
+extended with the '` + LabelAllowlistConfigKey + `' list of the osdctl config.`,
+		Example: `  osdctl org labels add <org-id> capability.organization.hibernate_cluster=true
+  osdctl org labels add --subscription <subscription-id> sre.routing.team=platform`,
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(addLabel(args[0], args[1]))
+		},
+	}
+
+	labelsRemoveCmd = &cobra.Command{
+		Use:   "remove <org-id> <key>",
+		Short: "Remove a label of an organization",
+		Long:  `Remove a label of an organization, or with --subscription of a subscription.`,
+		Example: `  osdctl org labels remove <org-id> capability.organization.hibernate_cluster
+  osdctl org labels remove --subscription <subscription-id> sre.routing.team`,
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(removeLabel(args[0], args[1]))
+		},
+	}
+)
+
+// labelChange is the audit record of a label modification
+type labelChange struct {
+	Time         time.Time `json:"time"`
+	User         string    `json:"user"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	Key          string    `json:"key"`
+	OldValue     string    `json:"old_value,omitempty"`
+	NewValue     string    `json:"new_value,omitempty"`
+}
+
+func init() {
+	addFlags := labelsAddCmd.Flags()
+	AddOutputFlag(addFlags)
+	addLabelsSubscriptionFlag(addFlags)
+	addFlags.BoolVar(&labelsForce, "force", false, "Set a key that isn't in the allowlist")
+	addFlags.BoolVar(&labelsInternal, "internal", true, "Make the label internal, i.e. hidden from the customer")
+
+	removeFlags := labelsRemoveCmd.Flags()
+	AddOutputFlag(removeFlags)
+	addLabelsSubscriptionFlag(removeFlags)
+}
+
+func addLabelsSubscriptionFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(&labelsSubscription, "subscription", false, "The ID is a subscription ID, manage the labels of the subscription")
+}
+
+// labelsAPIPath is the labels collection of the organization, or of the subscription with --subscription
+func labelsAPIPath(id string) string {
+	if labelsSubscription {
+		return path.Join(subscriptionsAPIPath, id, "labels")
+	}
+	return path.Join(organizationsAPIPath, id, "labels")
+}
+
+func labelsResourceType() string {
+	if labelsSubscription {
+		return "subscription"
+	}
+	return "organization"
+}
+
+// parseLabel splits a key=value argument
+func parseLabel(arg string) (string, string, error) {
+	key, value, found := strings.Cut(arg, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return "", "", fmt.Errorf("invalid label '%s', expected <key>=<value>", arg)
+	}
+	return key, value, nil
+}
+
+// labelAllowed returns whether the key matches an entry of the allowlist, entries ending with '*' match any suffix
+func labelAllowed(key string, allowlist []string) bool {
+	for _, allowed := range allowlist {
+		if prefix, isPrefix := strings.CutSuffix(allowed, "*"); isPrefix {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func labelAllowlist() []string {
+	return append(append([]string{}, defaultLabelAllowlist...), viper.GetStringSlice(LabelAllowlistConfigKey)...)
+}
+
+func addLabel(id string, arg string) error {
+	key, value, err := parseLabel(arg)
+	if err != nil {
+		return err
+	}
+	if allowlist := labelAllowlist(); !labelsForce && !labelAllowed(key, allowlist) {
+		return fmt.Errorf("label '%s' isn't in the allowlist %v, pass --force to set it anyway or add it to '%s' in the osdctl config",
+			key, allowlist, LabelAllowlistConfigKey)
+	}
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := ocmClient.Close(); err != nil {
+			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
+		}
+	}()
+
+	existing, err := findLabel(ocmClient, id, key)
+	if err != nil {
+		return err
+	}
+
+	change := newLabelChange(ocmClient, id, key)
+	change.NewValue = value
+	body, err := json.Marshal(map[string]interface{}{
+		"internal": labelsInternal,
+		"key":      key,
+		"type":     "Plain",
+		"value":    value,
+	})
+	if err != nil {
+		return err
+	}
+
+	var request *sdk.Request
+	if existing != nil {
+		if existing.Value == value {
+			fmt.Fprintf(os.Stderr, "Label '%s' of %s %s already has the value '%s'\n", key, change.ResourceType, id, value)
+			return nil
+		}
+		change.Action = labelActionUpdate
+		change.OldValue = existing.Value
+		request = ocmClient.Patch().Path(path.Join(labelsAPIPath(id), key))
+	} else {
+		change.Action = labelActionAdd
+		request = ocmClient.Post().Path(labelsAPIPath(id))
+	}
+	request.Bytes(body)
+
+	if err := sendLabelRequest(request); err != nil {
+		return fmt.Errorf("failed to %s label '%s' of %s %s: %w", change.Action, key, change.ResourceType, id, err)
+	}
+	printLabelChange(change)
+	return nil
+}
+
+func removeLabel(id string, key string) error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := ocmClient.Close(); err != nil {
+			fmt.Printf("Cannot close the ocmClient (possible memory leak): %q", err)
+		}
+	}()
+
+	existing, err := findLabel(ocmClient, id, key)
+	if err != nil {
+		return err
+	}
+	change := newLabelChange(ocmClient, id, key)
+	change.Action = labelActionRemove
+	if existing == nil {
+		return fmt.Errorf("%s %s has no label '%s'", change.ResourceType, id, key)
+	}
+	change.OldValue = existing.Value
+
+	if err := sendLabelRequest(ocmClient.Delete().Path(path.Join(labelsAPIPath(id), key))); err != nil {
+		return fmt.Errorf("failed to remove label '%s' of %s %s: %w", key, change.ResourceType, id, err)
+	}
+	printLabelChange(change)
+	return nil
+}
+
+// findLabel returns the label with the key, nil when it isn't set
+func findLabel(ocmClient *sdk.Connection, id string, key string) (*Label, error) {
+	response, err := sendRequest(createGetLabelsRequest(ocmClient, id))
+	if err != nil {
+		return nil, err
+	}
+	if response.Status() >= 400 {
+		return nil, fmt.Errorf("failed to get the labels of %s %s: %s", labelsResourceType(), id, response.String())
+	}
+	items := LabelItems{}
+	if err := json.Unmarshal(response.Bytes(), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse the labels of %s %s: %w", labelsResourceType(), id, err)
+	}
+	for i := range items.Labels {
+		if items.Labels[i].Key == key {
+			return &items.Labels[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func sendLabelRequest(request *sdk.Request) error {
+	response, err := sendRequest(request)
+	if err != nil {
+		return err
+	}
+	if response.Status() >= 400 {
+		return fmt.Errorf("%d: %s", response.Status(), response.String())
+	}
+	return nil
+}
+
+func newLabelChange(ocmClient *sdk.Connection, id string, key string) labelChange {
+	change := labelChange{
+		Time:         time.Now().UTC(),
+		ResourceType: labelsResourceType(),
+		ResourceID:   id,
+		Key:          key,
+	}
+	if account, err := ocmClient.AccountsMgmt().V1().CurrentAccount().Get().Send(); err == nil {
+		change.User = account.Body().Username()
+	}
+	return change
+}
+
+func printLabelChange(change labelChange) {
+	if IsJsonOutput() {
+		PrintJson(change)
+		return
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"TIME", "USER", "ACTION", "RESOURCE", "KEY", "OLD VALUE", "NEW VALUE"})
+	table.AddRow([]string{
+		change.Time.Format(time.RFC3339),
+		change.User,
+		change.Action,
+		change.ResourceType + "/" + change.ResourceID,
+		change.Key,
+		change.OldValue,
+		change.NewValue,
+	})
+	table.AddRow([]string{})
+	table.Flush()
+}
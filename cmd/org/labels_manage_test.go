@@ -0,0 +1,93 @@
+package org
+
+import (
+	"testing"
+)
+
+func TestParseLabel(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Arg           string
+		Key           string
+		Value         string
+		ErrorExpected bool
+	}{
+		{
+			Name:  "Key and value",
+			Arg:   "capability.organization.hibernate_cluster=true",
+			Key:   "capability.organization.hibernate_cluster",
+			Value: "true",
+		},
+		{
+			Name:  "Value containing an equal sign",
+			Arg:   "sre.routing.note=a=b",
+			Key:   "sre.routing.note",
+			Value: "a=b",
+		},
+		{
+			Name: "Empty value",
+			Arg:  "sre.routing.team=",
+			Key:  "sre.routing.team",
+		},
+		{
+			Name:          "No value",
+			Arg:           "sre.routing.team",
+			ErrorExpected: true,
+		},
+		{
+			Name:          "No key",
+			Arg:           "=true",
+			ErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			key, value, err := parseLabel(test.Arg)
+			if (err != nil) != test.ErrorExpected {
+				t.Fatalf("expected error %v, got %v", test.ErrorExpected, err)
+			}
+			if key != test.Key || value != test.Value {
+				t.Errorf("expected %s=%s, got %s=%s", test.Key, test.Value, key, value)
+			}
+		})
+	}
+}
+
+func TestLabelAllowed(t *testing.T) {
+	allowlist := []string{"capability.organization.*", "sre.owner"}
+	tests := []struct {
+		Name    string
+		Key     string
+		Allowed bool
+	}{
+		{
+			Name:    "Prefix entry",
+			Key:     "capability.organization.hibernate_cluster",
+			Allowed: true,
+		},
+		{
+			Name:    "Exact entry",
+			Key:     "sre.owner",
+			Allowed: true,
+		},
+		{
+			Name:    "Exact entry does not match a suffix",
+			Key:     "sre.owner.team",
+			Allowed: false,
+		},
+		{
+			Name:    "Unknown key",
+			Key:     "capability.cluster.autoscale",
+			Allowed: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if allowed := labelAllowed(test.Key, allowlist); allowed != test.Allowed {
+				t.Errorf("expected %v, got %v", test.Allowed, allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdReport() *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate recurring reports",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println("Error calling cmd.Help(): ", err.Error())
+				return
+			}
+		},
+	}
+
+	reportCmd.AddCommand(newCmdSchedule())
+	reportCmd.AddCommand(newCmdEmail())
+
+	return reportCmd
+}
@@ -0,0 +1,85 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{name: "every minute", expression: "* * * * *"},
+		{name: "lists ranges and steps", expression: "*/15 8-18 1,15 1-12/2 1-5"},
+		{name: "alias", expression: "@daily"},
+		{name: "sunday as 7", expression: "0 0 * * 7"},
+		{name: "too few fields", expression: "0 8 * *", wantErr: true},
+		{name: "out of range", expression: "60 * * * *", wantErr: true},
+		{name: "inverted range", expression: "0 10-8 * * *", wantErr: true},
+		{name: "invalid step", expression: "*/0 * * * *", wantErr: true},
+		{name: "not a number", expression: "a * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCron(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseCron(%s) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, 5, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expression string
+		want       time.Time
+	}{
+		{
+			name:       "every 15 minutes",
+			expression: "*/15 * * * *",
+			want:       time.Date(2024, 5, 15, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:       "daily at 8am rolls over to the next day",
+			expression: "0 8 * * *",
+			want:       time.Date(2024, 5, 16, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "mondays only",
+			expression: "0 8 * * 1",
+			want:       time.Date(2024, 5, 20, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly alias",
+			expression: "@monthly",
+			want:       time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "day of month or day of week when both are restricted",
+			expression: "0 0 20 * 5",
+			want:       time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "never matching expression",
+			expression: "0 0 31 2 *",
+			want:       time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron(tt.expression)
+			if err != nil {
+				t.Fatalf("parseCron(%s) unexpected error = %v", tt.expression, err)
+			}
+			if got := schedule.next(from); !got.Equal(tt.want) {
+				t.Errorf("next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
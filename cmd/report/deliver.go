@@ -0,0 +1,143 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/provider/email"
+	"github.com/openshift/osdctl/pkg/provider/slack"
+	"github.com/spf13/viper"
+)
+
+// deliverer stores or sends a generated report
+type deliverer interface {
+	deliver(fileName string, content []byte) error
+	String() string
+}
+
+// parseDestination turns a --deliver value into a deliverer. Supported destinations are
+// a local directory, s3://bucket/prefix, slack (using the configured incoming webhook) and
+// mailto:address[,address] (using the configured SMTP relay).
+func parseDestination(destination string, awsProfile string, awsRegion string) (deliverer, error) {
+	switch {
+	case destination == "slack":
+		return &slackDeliverer{webhookURL: viper.GetString(slack.SlackWebhookURLConfigKey)}, nil
+	case strings.HasPrefix(destination, "s3://"):
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(destination, "s3://"), "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid S3 destination '%s', expected s3://bucket[/prefix]", destination)
+		}
+		return &s3Deliverer{bucket: bucket, prefix: prefix, awsProfile: awsProfile, awsRegion: awsRegion}, nil
+	case strings.HasPrefix(destination, "mailto:"):
+		to := strings.Split(strings.TrimPrefix(destination, "mailto:"), ",")
+		if to[0] == "" {
+			return nil, fmt.Errorf("invalid email destination '%s', expected mailto:address[,address]", destination)
+		}
+		return &emailDeliverer{to: to}, nil
+	case destination == "":
+		return nil, fmt.Errorf("empty destination")
+	default:
+		return &fileDeliverer{directory: strings.TrimPrefix(destination, "file://")}, nil
+	}
+}
+
+type fileDeliverer struct {
+	directory string
+}
+
+func (d *fileDeliverer) deliver(fileName string, content []byte) error {
+	if err := os.MkdirAll(d.directory, 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	return os.WriteFile(filepath.Join(d.directory, fileName), content, 0600)
+}
+
+func (d *fileDeliverer) String() string {
+	return d.directory
+}
+
+type s3Deliverer struct {
+	bucket     string
+	prefix     string
+	awsProfile string
+	awsRegion  string
+}
+
+func (d *s3Deliverer) deliver(fileName string, content []byte) error {
+	awsClient, err := awsprovider.NewAwsClient(d.awsProfile, d.awsRegion, "")
+	if err != nil {
+		return err
+	}
+
+	key := fileName
+	if d.prefix != "" {
+		key = strings.TrimSuffix(d.prefix, "/") + "/" + fileName
+	}
+	_, err = awsClient.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(content),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload report to s3://%s/%s: %w", d.bucket, key, err)
+	}
+	return nil
+}
+
+func (d *s3Deliverer) String() string {
+	return fmt.Sprintf("s3://%s/%s", d.bucket, d.prefix)
+}
+
+type slackDeliverer struct {
+	webhookURL string
+}
+
+func (d *slackDeliverer) deliver(fileName string, content []byte) error {
+	return slack.PostWebhookMessage(d.webhookURL, slack.FormatCodeBlock(fileName, string(content)))
+}
+
+func (d *slackDeliverer) String() string {
+	return "slack"
+}
+
+type emailDeliverer struct {
+	to      []string
+	subject string
+}
+
+func (d *emailDeliverer) deliver(fileName string, content []byte) error {
+	config, err := email.ConfigFromViper()
+	if err != nil {
+		return err
+	}
+	subject := d.subject
+	if subject == "" {
+		subject = "osdctl report " + fileName
+	}
+	html, err := renderReportHTML(subject, fileName, content)
+	if err != nil {
+		return fmt.Errorf("failed to render the report: %w", err)
+	}
+	return email.Send(config, email.Message{
+		To:      d.to,
+		Subject: subject,
+		HTML:    html,
+		Text:    fmt.Sprintf("%s\n\nThe report is attached as %s.\n", subject, fileName),
+		Attachments: []email.Attachment{{
+			FileName:    fileName,
+			ContentType: mime.TypeByExtension(filepath.Ext(fileName)),
+			Content:     content,
+		}},
+	})
+}
+
+func (d *emailDeliverer) String() string {
+	return "mailto:" + strings.Join(d.to, ",")
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/provider/email"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type emailOptions struct {
+	scheduleOptions
+
+	to      []string
+	subject string
+}
+
+func newCmdEmail() *cobra.Command {
+	ops := &emailOptions{}
+	emailCmd := &cobra.Command{
+		Use:   "email",
+		Short: "Generate a report and email it as HTML",
+		Long: fmt.Sprintf(`Generate a named report once and email it, laid out as an HTML table with the full report attached, for
+the stakeholders who won't run osdctl themselves.
+
+The emails are sent through the SMTP relay configured as '%s' (host:port) from the address configured as
+'%s'. The relay is authenticated with '%s' and '%s' when they're set, over STARTTLS.
+To send a daily digest, use 'osdctl report schedule' with --deliver mailto:<address> instead.
+
+Available reports:
+%s`, email.SMTPHostConfigKey, email.SMTPFromConfigKey, email.SMTPUsernameConfigKey, email.SMTPPasswordConfigKey, describeReports()),
+		Example: `  # Email the health of the ROSA fleet to the stakeholders
+  osdctl report email --report fleet-health -q "product.id = 'rosa'" --to alice@example.com --to bob@example.com
+
+  # Email the context of an organization every weekday at 8am
+  osdctl report schedule --report org-context --org-id 1a2B3c --cron "0 8 * * 1-5" --deliver mailto:alice@example.com`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete())
+			cmdutil.CheckErr(ops.generateAndDeliver(time.Now()))
+		},
+	}
+
+	emailCmd.Flags().StringVar(&ops.report, "report", "", fmt.Sprintf("Name of the report to run, one of %v", reportNames()))
+	emailCmd.Flags().StringArrayVar(&ops.to, "to", []string{}, "Address to email the report to. Can be repeated")
+	emailCmd.Flags().StringVar(&ops.subject, "subject", "", "Subject of the email, defaults to the name of the report and the date")
+	emailCmd.Flags().StringVar(&ops.orgID, "org-id", "", "Organization ID, used by the org-context report")
+	emailCmd.Flags().StringVar(&ops.ou, "ou", "", "AWS organizational unit ID, used by the cost-summary report")
+	emailCmd.Flags().StringArrayVarP(&ops.queries, "query", "q", []string{}, "OCM search query selecting the clusters, used by the fleet-health report. Can be repeated")
+	_ = emailCmd.MarkFlagRequired("report")
+	_ = emailCmd.MarkFlagRequired("to")
+
+	return emailCmd
+}
+
+func (o *emailOptions) complete() error {
+	o.once = true
+	if err := o.scheduleOptions.complete(); err != nil {
+		return err
+	}
+	// Fail before the report is generated, which can take minutes
+	if _, err := email.ConfigFromViper(); err != nil {
+		return err
+	}
+	subject := o.subject
+	if subject == "" {
+		subject = fmt.Sprintf("%s report of %s", o.report, time.Now().UTC().Format("January 2, 2006"))
+	}
+	o.deliverers = []deliverer{&emailDeliverer{to: o.to, subject: subject}}
+	return nil
+}
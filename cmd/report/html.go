@@ -0,0 +1,154 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// reportTable is a report laid out as rows, rendered as an HTML table
+type reportTable struct {
+	Header []string
+	Rows   [][]string
+}
+
+var reportEmailTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, Helvetica, sans-serif; font-size: 14px; color: #151515;">
+<h2 style="font-weight: normal;">{{ .Title }}</h2>
+{{- if .Table }}
+<table style="border-collapse: collapse;">
+<tr>{{ range .Table.Header }}<th style="border: 1px solid #d2d2d2; padding: 4px 8px; background: #f0f0f0; text-align: left;">{{ . }}</th>{{ end }}</tr>
+{{- range .Table.Rows }}
+<tr>{{ range . }}<td style="border: 1px solid #d2d2d2; padding: 4px 8px;">{{ . }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+{{- else }}
+<pre style="font-size: 12px;">{{ .Raw }}</pre>
+{{- end }}
+<p style="color: #6a6e73; font-size: 12px;">Generated by osdctl, the full report is attached as {{ .FileName }}.</p>
+</body>
+</html>
+`))
+
+// renderReportHTML lays the report out as an HTML table when it's CSV, or JSON listing objects, and as
+// preformatted text otherwise
+func renderReportHTML(title string, fileName string, content []byte) (string, error) {
+	var table *reportTable
+	switch filepath.Ext(fileName) {
+	case ".csv":
+		table = csvTable(content)
+	case ".json":
+		table = jsonTable(content)
+	}
+
+	var b bytes.Buffer
+	err := reportEmailTemplate.Execute(&b, struct {
+		Title    string
+		FileName string
+		Table    *reportTable
+		Raw      string
+	}{title, fileName, table, string(content)})
+	return b.String(), err
+}
+
+func csvTable(content []byte) *reportTable {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil
+	}
+	return &reportTable{Header: records[0], Rows: records[1:]}
+}
+
+// jsonTable lays out a JSON list of objects with a column per key, in the order the keys first appear.
+// Nested values are shown inline.
+func jsonTable(content []byte) *reportTable {
+	var items []json.RawMessage
+	if err := json.Unmarshal(content, &items); err != nil || len(items) == 0 {
+		return nil
+	}
+	table := &reportTable{}
+	var objects []map[string]json.RawMessage
+	for _, item := range items {
+		keys, object, err := orderedKeys(item)
+		if err != nil {
+			return nil
+		}
+		for _, key := range keys {
+			if !slices.Contains(table.Header, key) {
+				table.Header = append(table.Header, key)
+			}
+		}
+		objects = append(objects, object)
+	}
+	for _, object := range objects {
+		row := make([]string, 0, len(table.Header))
+		for _, key := range table.Header {
+			row = append(row, inlineJSON(object[key]))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// orderedKeys returns the keys of a JSON object in the order they're written
+func orderedKeys(raw json.RawMessage) ([]string, map[string]json.RawMessage, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, err
+	}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, token.(string))
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+	}
+	return keys, object, nil
+}
+
+// inlineJSON shows a value on one line: strings unquoted, objects of scalars as "key: value" pairs
+func inlineJSON(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return string(raw)
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			pairs = append(pairs, fmt.Sprintf("%s: %v", key, v[key]))
+		}
+		return strings.Join(pairs, ", ")
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return string(raw)
+	}
+	return compact.String()
+}
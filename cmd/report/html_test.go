@@ -0,0 +1,52 @@
+package report
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected *reportTable
+	}{
+		{
+			name:    "objects",
+			content: `[{"name": "us-east-1", "clusters": 3, "versions": {"4.15": 1, "4.14": 2}}, {"name": "eu-west-1", "clusters": 1, "failed": 1}]`,
+			expected: &reportTable{
+				Header: []string{"name", "clusters", "versions", "failed"},
+				Rows: [][]string{
+					{"us-east-1", "3", "4.14: 2, 4.15: 1", ""},
+					{"eu-west-1", "1", "", "1"},
+				},
+			},
+		},
+		{
+			name:    "not a list",
+			content: `{"name": "us-east-1"}`,
+		},
+		{
+			name:    "list of scalars",
+			content: `["us-east-1"]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if table := jsonTable([]byte(tt.content)); !reflect.DeepEqual(table, tt.expected) {
+				t.Errorf("jsonTable() = %+v, want %+v", table, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderReportHTMLEscapes(t *testing.T) {
+	html, err := renderReportHTML("org-context report", "org-context.json", []byte(`[{"displayName": "<script>alert(1)</script>"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<script>") {
+		t.Errorf("renderReportHTML() didn't escape the report: %s", html)
+	}
+}
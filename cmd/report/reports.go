@@ -0,0 +1,62 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+)
+
+// namedReport is a report that can be scheduled. Reports are produced by running the matching
+// osdctl command, so any command can be exposed here without duplicating its logic.
+type namedReport struct {
+	description string
+	// extension of the file the report output is stored in
+	extension string
+	// args returns the osdctl arguments producing the report
+	args func(o *scheduleOptions) ([]string, error)
+}
+
+var reports = map[string]namedReport{
+	"org-context": {
+		description: "context of every cluster in an organization (requires --org-id)",
+		extension:   "json",
+		args: func(o *scheduleOptions) ([]string, error) {
+			if o.orgID == "" {
+				return nil, fmt.Errorf("the org-context report requires --org-id")
+			}
+			return []string{"org", "context", o.orgID, "--output", "json"}, nil
+		},
+	},
+	"fleet-health": {
+		description: "health of the clusters matching OCM search queries, by region (requires --query)",
+		extension:   "json",
+		args: func(o *scheduleOptions) ([]string, error) {
+			if len(o.queries) == 0 {
+				return nil, fmt.Errorf("the fleet-health report requires --query")
+			}
+			args := []string{"fleet", "status", "--output", "json"}
+			for _, query := range o.queries {
+				args = append(args, "--query", query)
+			}
+			return args, nil
+		},
+	},
+	"cost-summary": {
+		description: "month to date cost of an AWS organizational unit and its children (requires --ou)",
+		extension:   "csv",
+		args: func(o *scheduleOptions) ([]string, error) {
+			if o.ou == "" {
+				return nil, fmt.Errorf("the cost-summary report requires --ou")
+			}
+			return []string{"cost", "get", "--ou", o.ou, "--recursive", "--time", "MTD", "--csv"}, nil
+		},
+	},
+}
+
+func reportNames() []string {
+	names := make([]string, 0, len(reports))
+	for name := range reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,214 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/cmd/common"
+	"github.com/openshift/osdctl/pkg/signing"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+type scheduleOptions struct {
+	report       string
+	cron         string
+	destinations []string
+	once         bool
+	sign         string
+
+	orgID      string
+	ou         string
+	queries    []string
+	awsProfile string
+	awsRegion  string
+
+	schedule   *cronSchedule
+	deliverers []deliverer
+	signer     *signing.Signer
+	args       []string
+}
+
+func newCmdSchedule() *cobra.Command {
+	ops := &scheduleOptions{}
+	scheduleCmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Run a report on a cron schedule and deliver the results",
+		Long: fmt.Sprintf(`Run a named report on a cron schedule and deliver every result to one or more destinations.
+
+The command keeps running in the foreground and generates the report each time the cron expression
+matches, so it can be left running in a tmux session or a small pod.
+
+Available reports:
+%s
+Destinations (--deliver, can be repeated):
+  <directory>             write the report to a local directory
+  s3://bucket[/prefix]    upload the report to S3 using --aws-profile
+  slack                   post the report to the Slack webhook configured as 'slack_webhook_url'
+  mailto:address[,...]    email the report as HTML through the SMTP relay configured as 'smtp_host'
+
+With --sign, a detached signature is delivered along with every report written to a directory or S3, so
+the reports attached to compliance tickets can be verified later.`, describeReports()),
+		Example: `  # Send the context of an organization to Slack every weekday at 8am
+  osdctl report schedule --report org-context --org-id 1a2B3c --cron "0 8 * * 1-5" --deliver slack
+
+  # Store a cost summary in a local directory and S3 on the first day of each month
+  osdctl report schedule --report cost-summary --ou ou-abcd-1234 --cron @monthly --deliver ~/reports --deliver s3://my-bucket/reports
+
+  # Generate a signed report for an audit
+  osdctl report schedule --report org-context --org-id 1a2B3c --once --deliver ~/audit --sign gpg`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	scheduleCmd.Flags().StringVar(&ops.report, "report", "", fmt.Sprintf("Name of the report to run, one of %v", reportNames()))
+	scheduleCmd.Flags().StringVar(&ops.cron, "cron", "", "Cron expression (minute hour day-of-month month day-of-week) or one of @hourly, @daily, @weekly, @monthly")
+	scheduleCmd.Flags().StringArrayVar(&ops.destinations, "deliver", []string{}, "Where to deliver the report: a directory, s3://bucket/prefix, slack or mailto:address")
+	scheduleCmd.Flags().BoolVar(&ops.once, "once", false, "Generate and deliver the report once immediately, then exit")
+	scheduleCmd.Flags().StringVar(&ops.sign, "sign", "", signing.FlagUsage)
+	scheduleCmd.Flags().StringVar(&ops.orgID, "org-id", "", "Organization ID, used by the org-context report")
+	scheduleCmd.Flags().StringVar(&ops.ou, "ou", "", "AWS organizational unit ID, used by the cost-summary report")
+	scheduleCmd.Flags().StringArrayVarP(&ops.queries, "query", "q", []string{}, "OCM search query selecting the clusters, used by the fleet-health report. Can be repeated")
+	scheduleCmd.Flags().StringVarP(&ops.awsProfile, "aws-profile", "p", "", "AWS profile used for S3 delivery")
+	scheduleCmd.Flags().StringVar(&ops.awsRegion, "aws-region", common.DefaultRegion, "AWS region of the S3 bucket")
+	_ = scheduleCmd.MarkFlagRequired("report")
+	_ = scheduleCmd.MarkFlagRequired("deliver")
+
+	return scheduleCmd
+}
+
+func describeReports() string {
+	var sb strings.Builder
+	for _, name := range reportNames() {
+		sb.WriteString(fmt.Sprintf("  %-22s  %s\n", name, reports[name].description))
+	}
+	return sb.String()
+}
+
+func (o *scheduleOptions) complete() error {
+	report, ok := reports[o.report]
+	if !ok {
+		return fmt.Errorf("unknown report '%s', expected one of %v", o.report, reportNames())
+	}
+
+	var err error
+	if o.args, err = report.args(o); err != nil {
+		return err
+	}
+
+	if !o.once {
+		if o.cron == "" {
+			return fmt.Errorf("--cron is required unless --once is set")
+		}
+		if o.schedule, err = parseCron(o.cron); err != nil {
+			return err
+		}
+	}
+
+	for _, destination := range o.destinations {
+		d, err := parseDestination(destination, o.awsProfile, o.awsRegion)
+		if err != nil {
+			return err
+		}
+		if !storesFiles(d) && o.sign != "" {
+			return fmt.Errorf("the signatures can't be delivered to %s, use a directory or S3 with --sign", d)
+		}
+		o.deliverers = append(o.deliverers, d)
+	}
+
+	o.signer, err = signing.NewSigner(o.sign)
+	return err
+}
+
+// storesFiles returns true if the deliverer keeps the files it's given, and can keep their signatures
+func storesFiles(d deliverer) bool {
+	switch d.(type) {
+	case *fileDeliverer, *s3Deliverer:
+		return true
+	}
+	return false
+}
+
+func (o *scheduleOptions) run() error {
+	if o.once {
+		return o.generateAndDeliver(time.Now())
+	}
+
+	for {
+		next := o.schedule.next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("cron expression '%s' never matches", o.cron)
+		}
+		fmt.Printf("Next '%s' report at %s\n", o.report, next.Format(time.RFC1123))
+		time.Sleep(time.Until(next))
+
+		// A failed run shouldn't stop the schedule, the next one may well succeed
+		if err := o.generateAndDeliver(next); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to generate '%s' report: %v\n", o.report, err)
+		}
+	}
+}
+
+// reportArtifact is a delivered file, the report or its signature
+type reportArtifact struct {
+	fileName string
+	content  []byte
+}
+
+func (o *scheduleOptions) generateAndDeliver(at time.Time) error {
+	content, err := o.generate()
+	if err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf("%s-%s.%s", o.report, at.UTC().Format("20060102T1504Z"), reports[o.report].extension)
+	artifacts := []reportArtifact{{fileName: fileName, content: content}}
+	if o.signer != nil {
+		signatureName, signature, err := o.signer.SignBytes(fileName, content)
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, reportArtifact{fileName: signatureName, content: signature})
+	}
+
+	var failed []string
+	for _, d := range o.deliverers {
+		for _, artifact := range artifacts {
+			if err := d.deliver(artifact.fileName, artifact.content); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to deliver %s to %s: %v\n", artifact.fileName, d, err)
+				failed = append(failed, d.String())
+				break
+			}
+			fmt.Printf("Delivered %s to %s\n", artifact.fileName, d)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to deliver the report to %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// generate runs the report's osdctl command and returns its standard output
+func (o *scheduleOptions) generate() ([]byte, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the osdctl executable: %w", err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(executable, o.args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("'osdctl %s' failed: %w", strings.Join(o.args, " "), err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift/osdctl/pkg/ocmerror"
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// Run runs osdctl with the arguments, the program name excluded, and returns its exit code. It's the
+// entrypoint of the osdctl binary, and of the tools embedding osdctl. The usage, the errors and the output of
+// the commands writing to their command's streams go to stdout and stderr, the other commands still print to
+// the standard streams of the process. The commands keep global state, Run is called once per process.
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if err := osdctlConfig.EnsureConfigFile(); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	command := NewCmdRoot(genericclioptions.IOStreams{In: os.Stdin, Out: stdout, ErrOut: stderr})
+	// cobra falls back to the arguments of the process when given nil ones
+	if args == nil {
+		args = []string{}
+	}
+	command.SetArgs(args)
+	command.SetOut(stdout)
+	command.SetErr(stderr)
+
+	if err := command.Execute(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "%v\n", err)
+		if guidance := ocmerror.Guidance(err.Error()); guidance != "" {
+			_, _ = fmt.Fprint(stderr, "\n"+guidance)
+		}
+		return 1
+	}
+	return 0
+}
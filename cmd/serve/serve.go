@@ -0,0 +1,215 @@
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	azv1 "github.com/openshift-online/ocm-sdk-go/authorizations/v1"
+	"github.com/openshift/osdctl/cmd/cluster"
+	"github.com/openshift/osdctl/cmd/servicelog"
+	"github.com/openshift/osdctl/pkg/provider/slack"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// TokenEnvVar can hold the bearer token clients have to send, instead of a generated one
+const TokenEnvVar = "OSDCTL_SERVE_TOKEN"
+
+type serveOptions struct {
+	address     string
+	tokenFile   string
+	awsProfile  string
+	allowRemote bool
+
+	token string
+}
+
+func NewCmdServe() *cobra.Command {
+	ops := &serveOptions{}
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Expose the read-only data collectors over an authenticated HTTP API",
+		Long: fmt.Sprintf(`Run a long-running HTTP server exposing the read-only data collectors of osdctl, so dashboards
+and chatbots can reuse them without running osdctl for every request.
+
+Every request to the API has to send the 'Authorization: Bearer <token>' header. The token is read
+from --token-file or the %s environment variable, otherwise one is generated and printed at startup.
+
+Endpoints:
+  GET /api/v1/clusters/{id}/context[?days=30]                   same data as 'osdctl cluster context -o json'
+  GET /api/v1/clusters/{id}/health                              same data as 'osdctl cluster health'
+  GET /api/v1/clusters/{id}/servicelogs[?all=true&internal=true] same data as 'osdctl servicelog list'
+  GET /healthz                                                  liveness probe, not authenticated
+
+The OCM tokens are refreshed in the background ahead of their expiry. When that fails, the connection is
+re-established from the OCM config, so running 'ocm login' again recovers a server whose session expired.
+
+When '%s' is set in the osdctl config, the server also answers the Slack slash command
+'/osdctl context <cluster-id> [days]' on POST /slack/commands. Slack requests are verified with the
+signing secret, and only Slack users mapped to an OCM username in '%s' can run commands:
+
+  %s:
+    U012AB3CD: my-ocm-user
+
+The context is collected with the server's OCM connection, after an OCM access review confirmed the
+mapped OCM user can view the cluster, so Slack users only see the clusters their OCM user has access to.`, TokenEnvVar, slack.SlackSigningSecretConfigKey, slack.SlackUserMappingConfigKey, slack.SlackUserMappingConfigKey),
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.complete())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	serveCmd.Flags().StringVar(&ops.address, "address", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&ops.tokenFile, "token-file", "", "File containing the bearer token clients have to send")
+	serveCmd.Flags().StringVarP(&ops.awsProfile, "profile", "p", "", "AWS Profile used by the health collector")
+	serveCmd.Flags().BoolVar(&ops.allowRemote, "allow-remote", false, "Allow listening on a non-loopback address. The API exposes customer data, only do this behind TLS")
+
+	return serveCmd
+}
+
+func (o *serveOptions) complete() error {
+	host, _, err := net.SplitHostPort(o.address)
+	if err != nil {
+		return fmt.Errorf("invalid address '%s': %w", o.address, err)
+	}
+	if !o.allowRemote && !isLoopback(host) {
+		return fmt.Errorf("refusing to listen on non-loopback address '%s' without --allow-remote", o.address)
+	}
+
+	switch {
+	case o.tokenFile != "":
+		content, err := os.ReadFile(o.tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read token file: %w", err)
+		}
+		o.token = strings.TrimSpace(string(content))
+	case os.Getenv(TokenEnvVar) != "":
+		o.token = os.Getenv(TokenEnvVar)
+	default:
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("failed to generate a token: %w", err)
+		}
+		o.token = hex.EncodeToString(buf)
+		fmt.Fprintf(os.Stderr, "Generated API token: %s\n", o.token)
+	}
+	if o.token == "" {
+		return fmt.Errorf("the API token can't be empty")
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (o *serveOptions) run() error {
+	// The collectors create an OCM connection per request, keep the tokens they use fresh
+	if err := utils.NewOCMWatchdog().Start(context.Background()); err != nil {
+		return err
+	}
+
+	handler := newHandler(o.token, collectors{
+		context: func(clusterID string, days int) (interface{}, []error) {
+			data, errs := cluster.GetContextData(clusterID, days)
+			if data == nil {
+				return nil, errs
+			}
+			return data, errs
+		},
+		health: func(clusterID string) (interface{}, error) {
+			return cluster.GetClusterHealth(clusterID, o.awsProfile, false)
+		},
+		serviceLogs: func(clusterID string, allMessages bool, internalOnly bool) (interface{}, error) {
+			return servicelog.GetServiceLogsView(clusterID, allMessages, internalOnly)
+		},
+	}, o.slackCommands())
+
+	server := &http.Server{
+		Addr:              o.address,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "Listening on http://%s\n", o.address)
+	return server.ListenAndServe()
+}
+
+// authorizeClusterAccess runs an OCM access review checking the OCM user can view the cluster
+func authorizeClusterAccess(ocmUser string, clusterID string) error {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	cluster, err := utils.GetCluster(ocmClient, clusterID)
+	if err != nil {
+		return err
+	}
+	request, err := azv1.NewAccessReviewRequest().
+		AccountUsername(ocmUser).
+		Action("get").
+		ResourceType("Cluster").
+		ClusterID(cluster.ID()).
+		SubscriptionID(cluster.Subscription().ID()).
+		Build()
+	if err != nil {
+		return err
+	}
+	response, err := ocmClient.Authorizations().V1().AccessReview().Post().Request(request).Send()
+	if err != nil {
+		return fmt.Errorf("access review failed: %w", err)
+	}
+	if !response.Response().Allowed() {
+		return fmt.Errorf("access denied by OCM")
+	}
+	return nil
+}
+
+// slackCommands returns the Slack slash command handler, or nil when no signing secret is configured
+func (o *serveOptions) slackCommands() *slackCommands {
+	signingSecret := viper.GetString(slack.SlackSigningSecretConfigKey)
+	if signingSecret == "" {
+		return nil
+	}
+
+	users := viper.GetStringMapString(slack.SlackUserMappingConfigKey)
+	if len(users) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: '%s' is empty, nobody can use the Slack commands\n", slack.SlackUserMappingConfigKey)
+	}
+	// viper lowercases map keys, Slack user IDs are uppercase
+	mapping := map[string]string{}
+	for slackUser, ocmUser := range users {
+		mapping[strings.ToUpper(slackUser)] = ocmUser
+	}
+
+	return &slackCommands{
+		signingSecret: signingSecret,
+		users:         mapping,
+		authorize:     authorizeClusterAccess,
+		contextSummary: func(clusterID string, days int) (*contextSummary, error) {
+			summary, err := cluster.GetContextSummary(clusterID, days)
+			if err != nil {
+				return nil, err
+			}
+			return &contextSummary{summary: summary.Summary, links: summary.Links, errors: summary.Errors}, nil
+		},
+		respond: slack.PostWebhookMessage,
+		now:     time.Now,
+	}
+}
@@ -0,0 +1,120 @@
+package serve
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const apiPrefix = "/api/v1/clusters/"
+
+// collectors are the read-only data sources exposed by the server. They are plain functions so the
+// server doesn't depend on how the data is collected.
+type collectors struct {
+	context     func(clusterID string, days int) (interface{}, []error)
+	health      func(clusterID string) (interface{}, error)
+	serviceLogs func(clusterID string, allMessages bool, internalOnly bool) (interface{}, error)
+}
+
+// response is the envelope of every API response. Collectors like context return partial data
+// together with the errors of the sources that failed, so both are returned.
+type response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []string    `json:"errors,omitempty"`
+}
+
+type server struct {
+	token      string
+	collectors collectors
+}
+
+// newHandler returns the API handler, slackCmds is optional and only mounted when Slack is configured
+func newHandler(token string, c collectors, slackCmds *slackCommands) http.Handler {
+	s := &server{token: token, collectors: c}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.Handle(apiPrefix, s.authenticated(http.HandlerFunc(s.handleCluster)))
+	if slackCmds != nil {
+		// Slack requests are authenticated by their signature instead of the bearer token
+		mux.Handle(slackCommandsPath, slackCmds)
+	}
+	return mux
+}
+
+func (s *server) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, response{Errors: []string{"missing or invalid bearer token"}})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleCluster serves /api/v1/clusters/{id}/{context,health,servicelogs}
+func (s *server) handleCluster(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, response{Errors: []string{"only GET is supported, the API is read-only"}})
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, apiPrefix), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeJSON(w, http.StatusNotFound, response{Errors: []string{fmt.Sprintf("unknown path %s", r.URL.Path)}})
+		return
+	}
+	clusterID, resource := parts[0], parts[1]
+	query := r.URL.Query()
+
+	switch resource {
+	case "context":
+		days := 30
+		if value := query.Get("days"); value != "" {
+			var err error
+			if days, err = strconv.Atoi(value); err != nil || days < 1 {
+				writeJSON(w, http.StatusBadRequest, response{Errors: []string{"days must be a positive integer"}})
+				return
+			}
+		}
+		data, errs := s.collectors.context(clusterID, days)
+		resp := response{Data: data}
+		for _, err := range errs {
+			resp.Errors = append(resp.Errors, err.Error())
+		}
+		status := http.StatusOK
+		if data == nil {
+			status = http.StatusBadGateway
+		}
+		writeJSON(w, status, resp)
+	case "health":
+		data, err := s.collectors.health(clusterID)
+		writeResult(w, data, err)
+	case "servicelogs":
+		data, err := s.collectors.serviceLogs(clusterID, query.Get("all") == "true", query.Get("internal") == "true")
+		writeResult(w, data, err)
+	default:
+		writeJSON(w, http.StatusNotFound, response{Errors: []string{fmt.Sprintf("unknown resource %s, expected one of context, health, servicelogs", resource)}})
+	}
+}
+
+func writeResult(w http.ResponseWriter, data interface{}, err error) {
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, response{Errors: []string{err.Error()}})
+		return
+	}
+	writeJSON(w, http.StatusOK, response{Data: data})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
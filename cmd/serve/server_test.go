@@ -0,0 +1,84 @@
+package serve
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestCollectors() collectors {
+	return collectors{
+		context: func(clusterID string, days int) (interface{}, []error) {
+			return map[string]interface{}{"cluster": clusterID, "days": days}, []error{errors.New("pagerduty unavailable")}
+		},
+		health: func(clusterID string) (interface{}, error) {
+			if clusterID == "broken" {
+				return nil, errors.New("cloud credentials expired")
+			}
+			return map[string]string{"cluster": clusterID}, nil
+		},
+		serviceLogs: func(clusterID string, allMessages bool, internalOnly bool) (interface{}, error) {
+			return map[string]bool{"all": allMessages, "internal": internalOnly}, nil
+		},
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+		wantData   bool
+		wantErrors int
+	}{
+		{name: "healthz needs no token", path: "/healthz", wantStatus: http.StatusOK},
+		{name: "missing token", path: "/api/v1/clusters/abc/health", wantStatus: http.StatusUnauthorized, wantErrors: 1},
+		{name: "wrong token", path: "/api/v1/clusters/abc/health", token: "nope", wantStatus: http.StatusUnauthorized, wantErrors: 1},
+		{name: "health", path: "/api/v1/clusters/abc/health", token: "secret", wantStatus: http.StatusOK, wantData: true},
+		{name: "collector error", path: "/api/v1/clusters/broken/health", token: "secret", wantStatus: http.StatusBadGateway, wantErrors: 1},
+		{name: "partial context", path: "/api/v1/clusters/abc/context?days=7", token: "secret", wantStatus: http.StatusOK, wantData: true, wantErrors: 1},
+		{name: "invalid days", path: "/api/v1/clusters/abc/context?days=0", token: "secret", wantStatus: http.StatusBadRequest, wantErrors: 1},
+		{name: "servicelogs", path: "/api/v1/clusters/abc/servicelogs?all=true", token: "secret", wantStatus: http.StatusOK, wantData: true},
+		{name: "unknown resource", path: "/api/v1/clusters/abc/secrets", token: "secret", wantStatus: http.StatusNotFound, wantErrors: 1},
+		{name: "missing resource", path: "/api/v1/clusters/abc", token: "secret", wantStatus: http.StatusNotFound, wantErrors: 1},
+		{name: "read-only", method: http.MethodPost, path: "/api/v1/clusters/abc/health", token: "secret", wantStatus: http.StatusMethodNotAllowed, wantErrors: 1},
+	}
+
+	handler := newHandler("secret", newTestCollectors(), nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.path == "/healthz" {
+				return
+			}
+
+			var resp response
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response %q: %v", rec.Body.String(), err)
+			}
+			if (resp.Data != nil) != tt.wantData {
+				t.Errorf("data = %v, wantData %v", resp.Data, tt.wantData)
+			}
+			if len(resp.Errors) != tt.wantErrors {
+				t.Errorf("errors = %v, want %d errors", resp.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package serve
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/provider/slack"
+)
+
+const (
+	slackCommandsPath = "/slack/commands"
+
+	slackUsage = "Usage: `/osdctl context <cluster-id> [days]`"
+)
+
+// contextSummary is the short context of a cluster posted back to Slack
+type contextSummary struct {
+	summary string
+	links   map[string]string
+	errors  []error
+}
+
+// slackCommands answers Slack slash commands. Slack only waits 3 seconds for an answer, so commands
+// are acknowledged right away and their result is posted to the response URL of the command.
+type slackCommands struct {
+	signingSecret string
+	// users maps Slack user IDs to OCM usernames, only mapped users can run commands
+	users map[string]string
+
+	// authorize returns an error unless the OCM user can view the cluster. The lookups run with the server's
+	// OCM connection, so the mapped user's own access is checked first.
+	authorize      func(ocmUser string, clusterID string) error
+	contextSummary func(clusterID string, days int) (*contextSummary, error)
+	respond        func(responseURL string, text string) error
+	now            func() time.Time
+}
+
+func (s *slackCommands) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := slack.VerifyRequestSignature(s.signingSecret, r.Header, body, s.now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Rejected Slack request: %v\n", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	userID := form.Get("user_id")
+	ocmUser, ok := s.users[userID]
+	if !ok {
+		writeSlackReply(w, fmt.Sprintf("Your Slack user %s isn't mapped to an OCM user, ask the osdctl serve admin to add it to `%s`", userID, slack.SlackUserMappingConfigKey))
+		return
+	}
+
+	args := strings.Fields(form.Get("text"))
+	if len(args) == 0 || args[0] != "context" || len(args) < 2 || len(args) > 3 {
+		writeSlackReply(w, slackUsage)
+		return
+	}
+
+	clusterID := args[1]
+	days := 30
+	if len(args) == 3 {
+		if days, err = strconv.Atoi(args[2]); err != nil || days < 1 {
+			writeSlackReply(w, "days must be a positive integer. "+slackUsage)
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Slack user %s (OCM user %s) requested the context of cluster %s\n", userID, ocmUser, clusterID)
+	responseURL := form.Get("response_url")
+	go func() {
+		text := s.runContext(clusterID, days, ocmUser)
+		if err := s.respond(responseURL, text); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to answer Slack command: %v\n", err)
+		}
+	}()
+	writeSlackReply(w, fmt.Sprintf("Collecting the context of cluster %s...", clusterID))
+}
+
+func (s *slackCommands) runContext(clusterID string, days int, ocmUser string) string {
+	if err := s.authorize(ocmUser, clusterID); err != nil {
+		return fmt.Sprintf("OCM user %s can't view cluster %s: %v", ocmUser, clusterID, err)
+	}
+
+	summary, err := s.contextSummary(clusterID, days)
+	if err != nil {
+		return fmt.Sprintf("Failed to get the context of cluster %s: %v", clusterID, err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(slack.FormatCodeBlock(fmt.Sprintf("Context of %s (last %d days), requested by %s", clusterID, days, ocmUser), strings.TrimSpace(summary.summary)))
+	sb.WriteString("\n")
+
+	names := make([]string, 0, len(summary.links))
+	for name := range summary.links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if link := strings.TrimSpace(summary.links[name]); link != "" {
+			sb.WriteString(fmt.Sprintf("• <%s|%s>\n", link, name))
+		}
+	}
+
+	if len(summary.errors) > 0 {
+		sb.WriteString("_Data may be incomplete:_\n")
+		for _, err := range summary.errors {
+			sb.WriteString(fmt.Sprintf("• %v\n", err))
+		}
+	}
+	return sb.String()
+}
+
+// writeSlackReply answers a slash command with an ephemeral message, only visible to its author
+func writeSlackReply(w http.ResponseWriter, text string) {
+	writeJSON(w, http.StatusOK, map[string]string{"response_type": "ephemeral", "text": text})
+}
@@ -0,0 +1,122 @@
+package serve
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newSlackRequest(t *testing.T, secret string, at time.Time, form url.Values) *http.Request {
+	t.Helper()
+	body := form.Encode()
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, slackCommandsPath, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestSlackCommands(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name         string
+		secret       string
+		userID       string
+		text         string
+		wantStatus   int
+		wantReply    string
+		wantResponse string
+	}{
+		{name: "invalid signature", secret: "other", userID: "U1", text: "context abc", wantStatus: http.StatusUnauthorized},
+		{name: "unmapped user", secret: "s3cr3t", userID: "U2", text: "context abc", wantStatus: http.StatusOK, wantReply: "isn't mapped to an OCM user"},
+		{name: "usage", secret: "s3cr3t", userID: "U1", text: "", wantStatus: http.StatusOK, wantReply: "Usage"},
+		{name: "unknown command", secret: "s3cr3t", userID: "U1", text: "delete abc", wantStatus: http.StatusOK, wantReply: "Usage"},
+		{name: "invalid days", secret: "s3cr3t", userID: "U1", text: "context abc zero", wantStatus: http.StatusOK, wantReply: "days must be a positive integer"},
+		{name: "context", secret: "s3cr3t", userID: "U1", text: "context abc 7", wantStatus: http.StatusOK, wantReply: "Collecting the context of cluster abc",
+			wantResponse: "<https://example.com/abc|CCX dashboard>"},
+		{name: "context failure", secret: "s3cr3t", userID: "U1", text: "context broken", wantStatus: http.StatusOK, wantReply: "Collecting the context of cluster broken",
+			wantResponse: "Failed to get the context of cluster broken: cluster not found"},
+		{name: "cluster the OCM user can't view", secret: "s3cr3t", userID: "U1", text: "context other-org", wantStatus: http.StatusOK, wantReply: "Collecting the context of cluster other-org",
+			wantResponse: "OCM user sre-user can't view cluster other-org: access denied by OCM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := make(chan string, 1)
+			handler := newHandler("token", newTestCollectors(), &slackCommands{
+				signingSecret: "s3cr3t",
+				users:         map[string]string{"U1": "sre-user"},
+				authorize: func(ocmUser string, clusterID string) error {
+					if clusterID == "other-org" {
+						return errors.New("access denied by OCM")
+					}
+					return nil
+				},
+				contextSummary: func(clusterID string, days int) (*contextSummary, error) {
+					if clusterID == "other-org" {
+						t.Error("the context was collected for a cluster the OCM user can't view")
+					}
+					if clusterID == "broken" {
+						return nil, errors.New("cluster not found")
+					}
+					return &contextSummary{
+						summary: "Version  Supported?",
+						links:   map[string]string{"CCX dashboard": "https://example.com/" + clusterID},
+					}, nil
+				},
+				respond: func(responseURL string, text string) error {
+					responses <- text
+					return nil
+				},
+				now: func() time.Time { return now },
+			})
+
+			req := newSlackRequest(t, tt.secret, now, url.Values{
+				"user_id":      {tt.userID},
+				"text":         {tt.text},
+				"response_url": {"https://hooks.slack.com/commands/1"},
+			})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantReply != "" {
+				var reply map[string]string
+				if err := json.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+					t.Fatalf("failed to decode reply %q: %v", rec.Body.String(), err)
+				}
+				if !strings.Contains(reply["text"], tt.wantReply) {
+					t.Errorf("reply = %q, want it to contain %q", reply["text"], tt.wantReply)
+				}
+			}
+
+			if tt.wantResponse == "" {
+				return
+			}
+			select {
+			case response := <-responses:
+				if !strings.Contains(response, tt.wantResponse) {
+					t.Errorf("response = %q, want it to contain %q", response, tt.wantResponse)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for the delayed response")
+			}
+		})
+	}
+}
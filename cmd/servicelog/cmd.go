@@ -19,8 +19,11 @@ func NewCmdServiceLog() *cobra.Command {
 	}
 
 	// Add subcommands
-	servicelogCmd.AddCommand(listCmd)      // servicelog list
-	servicelogCmd.AddCommand(newPostCmd()) // servicelog post
+	servicelogCmd.AddCommand(listCmd)         // servicelog list
+	servicelogCmd.AddCommand(newPostCmd())    // servicelog post
+	servicelogCmd.AddCommand(newPreviewCmd()) // servicelog preview
+	servicelogCmd.AddCommand(newJobsCmd())    // servicelog jobs
+	servicelogCmd.AddCommand(newStatsCmd())   // servicelog stats
 
 	return servicelogCmd
 }
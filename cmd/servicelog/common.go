@@ -3,12 +3,15 @@ package servicelog
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
 	"github.com/openshift/osdctl/internal/servicelog"
 	"github.com/openshift/osdctl/pkg/utils"
-	"time"
 )
 
 var (
@@ -16,6 +19,11 @@ var (
 )
 
 const (
+	// serviceLogsPageSize is the number of service logs fetched per request
+	serviceLogsPageSize = 100
+	// maxServiceLogs bounds the service logs held in memory, the newest ones are kept
+	maxServiceLogs = 5000
+
 	// in case you want to see the swagger code gen, you can look at
 	// https://api.openshift.com/?urls.primaryName=Service%20logs#/default/post_api_service_logs_v1_cluster_logs
 	targetAPIPath = "/api/service_logs/v1/cluster_logs"
@@ -67,13 +75,13 @@ func validateBadResponse(body []byte) (badReply *servicelog.BadReply, err error)
 func GetServiceLogsSince(clusterID string, timeSince time.Time, allMessages bool, internalOnly bool) ([]*v1.LogEntry, error) {
 	earliestTime := timeSince
 
-	slResponse, err := FetchServiceLogs(clusterID, allMessages, internalOnly)
+	serviceLogs, err := FetchServiceLogs(clusterID, allMessages, internalOnly, earliestTime)
 	if err != nil {
 		return nil, err
 	}
 
 	var errorServiceLogs []*v1.LogEntry
-	for _, serviceLog := range slResponse.Items().Slice() {
+	for _, serviceLog := range serviceLogs.Items {
 		if serviceLog.CreatedAt().After(earliestTime) {
 			errorServiceLogs = append(errorServiceLogs, serviceLog)
 		}
@@ -82,7 +90,21 @@ func GetServiceLogsSince(clusterID string, timeSince time.Time, allMessages bool
 	return errorServiceLogs, nil
 }
 
-func FetchServiceLogs(clusterID string, allMessages bool, internalOnly bool) (*v1.ClustersClusterLogsListResponse, error) {
+// ServiceLogList is the newest service logs of a cluster, and how many match the search in total
+type ServiceLogList struct {
+	Items []*v1.LogEntry
+	Total int
+}
+
+// Truncated tells whether older service logs were left out because of maxServiceLogs
+func (l *ServiceLogList) Truncated() bool {
+	return l.Total > len(l.Items)
+}
+
+// FetchServiceLogs returns the service logs of the cluster sent after since, all of them when since is zero,
+// newest first. They are fetched page by page and at most maxServiceLogs are kept, so clusters with years of
+// service logs don't need a single huge response.
+func FetchServiceLogs(clusterID string, allMessages bool, internalOnly bool, since time.Time) (*ServiceLogList, error) {
 	// Create OCM client to talk to cluster API
 	ocmClient, err := utils.CreateConnection()
 	if err != nil {
@@ -96,40 +118,130 @@ func FetchServiceLogs(clusterID string, allMessages bool, internalOnly bool) (*v
 
 	// Use the OCM client to retrieve clusters
 	clusters := utils.GetClusters(ocmClient, []string{clusterID})
+	if len(clusters) > 1 {
+		return nil, utils.NewAmbiguousClusterError(clusterID, clusters)
+	}
 	if len(clusters) != 1 {
 		return nil, fmt.Errorf("GetClusters expected to return 1 cluster, got: %d", len(clusters))
 	}
 	cluster := clusters[0]
 
 	// Now get the SLs for the cluster
-	clusterLogsListResponse, err := sendClusterLogsListRequest(ocmClient, cluster, allMessages, internalOnly)
+	serviceLogs, err := listClusterLogs(ocmClient, cluster, serviceLogsSearch(allMessages, internalOnly, since), maxServiceLogs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch service logs for cluster %v: %w", clusterID, err)
 	}
-	return clusterLogsListResponse, nil
+	if serviceLogs.Truncated() {
+		fmt.Fprintf(os.Stderr, "Only the %d newest of the %d service logs of cluster %s were fetched, narrow them down with --days\n", len(serviceLogs.Items), serviceLogs.Total, clusterID)
+	}
+	return serviceLogs, nil
 }
 
-func sendClusterLogsListRequest(ocmClient *sdk.Connection, cluster *cmv1.Cluster, allMessages bool, internalMessages bool) (*v1.ClustersClusterLogsListResponse, error) {
-	request := ocmClient.ServiceLogs().V1().Clusters().ClusterLogs().List().
-		Parameter("cluster_id", cluster.ID()).
-		Parameter("cluster_uuid", cluster.ExternalID()).
-		Parameter("orderBy", "timestamp desc")
-
-	var searchQuery string
+// serviceLogsSearch returns the search selecting the service logs, filtered by time on the server side so only
+// the ones in the window are sent
+func serviceLogsSearch(allMessages bool, internalMessages bool, since time.Time) string {
+	var conditions []string
 	if !allMessages {
-		searchQuery = "service_name='SREManualAction'"
+		conditions = append(conditions, "service_name='SREManualAction'")
 	}
 	if internalMessages {
-		if searchQuery != "" {
-			searchQuery += " and "
+		conditions = append(conditions, "internal_only='true'")
+	}
+	if !since.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("timestamp >= '%s'", since.UTC().Format(time.RFC3339)))
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// listClusterLogs fetches the service logs matching the search newest first, one page at a time, and stops once
+// limit service logs are fetched
+func listClusterLogs(ocmClient *sdk.Connection, cluster *cmv1.Cluster, search string, limit int) (*ServiceLogList, error) {
+	serviceLogs := &ServiceLogList{}
+	for page := 1; len(serviceLogs.Items) < limit; page++ {
+		response, err := ocmClient.ServiceLogs().V1().Clusters().ClusterLogs().List().
+			Parameter("cluster_id", cluster.ID()).
+			Parameter("cluster_uuid", cluster.ExternalID()).
+			Parameter("orderBy", "timestamp desc").
+			Search(search).
+			Page(page).
+			Size(serviceLogsPageSize).
+			Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch service logs: %w", err)
+		}
+		serviceLogs.Total = response.Total()
+		serviceLogs.Items = appendServiceLogs(serviceLogs.Items, response.Items().Slice(), limit)
+		if response.Size() < serviceLogsPageSize {
+			break
 		}
-		searchQuery += "internal_only='true'"
 	}
-	request.Search(searchQuery)
+	return serviceLogs, nil
+}
 
-	response, err := request.Send()
+// CountServiceLogsSince returns how many service logs SREs sent to the cluster since the given time, and how many
+// of them are internal, from the totals of the searches rather than by fetching the service logs
+func CountServiceLogsSince(ocmClient *sdk.Connection, cluster *cmv1.Cluster, since time.Time) (total int, internal int, err error) {
+	if total, err = countClusterLogs(ocmClient, cluster, serviceLogsSearch(false, false, since)); err != nil {
+		return 0, 0, err
+	}
+	if internal, err = countClusterLogs(ocmClient, cluster, serviceLogsSearch(false, true, since)); err != nil {
+		return 0, 0, err
+	}
+	return total, internal, nil
+}
+
+// countClusterLogs returns the number of service logs matching the search, fetching a single one
+func countClusterLogs(ocmClient *sdk.Connection, cluster *cmv1.Cluster, search string) (int, error) {
+	response, err := ocmClient.ServiceLogs().V1().Clusters().ClusterLogs().List().
+		Parameter("cluster_id", cluster.ID()).
+		Parameter("cluster_uuid", cluster.ExternalID()).
+		Search(search).
+		Size(1).
+		Send()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch service logs: %w", err)
+		return 0, fmt.Errorf("failed to count service logs: %w", err)
+	}
+	return response.Total(), nil
+}
+
+// appendServiceLogs appends the page to the service logs already fetched, up to limit
+func appendServiceLogs(serviceLogs []*v1.LogEntry, page []*v1.LogEntry, limit int) []*v1.LogEntry {
+	if room := limit - len(serviceLogs); len(page) > room {
+		page = page[:room]
+	}
+	return append(serviceLogs, page...)
+}
+
+// FetchClusterEvents returns the lifecycle events OCM logged for the cluster (install, upgrade, hibernation...)
+// between since and until, newest first. Those are the service logs OCM services post themselves, as opposed to
+// the ones sent by SREs. A zero until means now, and at most limit events are returned.
+func FetchClusterEvents(ocmClient *sdk.Connection, cluster *cmv1.Cluster, since time.Time, until time.Time, limit int) ([]*v1.LogEntry, error) {
+	search := fmt.Sprintf("service_name != 'SREManualAction' and timestamp >= '%s'", since.UTC().Format(time.RFC3339))
+	if !until.IsZero() {
+		search += fmt.Sprintf(" and timestamp <= '%s'", until.UTC().Format(time.RFC3339))
+	}
+
+	var events []*v1.LogEntry
+	pageSize := 100
+	for page := 1; len(events) < limit; page++ {
+		response, err := ocmClient.ServiceLogs().V1().Clusters().ClusterLogs().List().
+			Parameter("cluster_id", cluster.ID()).
+			Parameter("cluster_uuid", cluster.ExternalID()).
+			Parameter("orderBy", "timestamp desc").
+			Search(search).
+			Page(page).
+			Size(pageSize).
+			Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch the events of cluster %v: %w", cluster.ID(), err)
+		}
+		events = append(events, response.Items().Slice()...)
+		if response.Size() < pageSize {
+			break
+		}
+	}
+	if len(events) > limit {
+		events = events[:limit]
 	}
-	return response, nil
+	return events, nil
 }
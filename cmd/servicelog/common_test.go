@@ -0,0 +1,63 @@
+package servicelog
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+func TestServiceLogsSearch(t *testing.T) {
+	since := time.Date(2024, 3, 12, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name         string
+		allMessages  bool
+		internalOnly bool
+		since        time.Time
+		expected     string
+	}{
+		{
+			name:     "SRE messages",
+			expected: "service_name='SREManualAction'",
+		},
+		{
+			name:        "all messages",
+			allMessages: true,
+		},
+		{
+			name:         "internal messages of the last days",
+			internalOnly: true,
+			since:        since,
+			expected:     "service_name='SREManualAction' and internal_only='true' and timestamp >= '2024-03-12T09:00:00Z'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if search := serviceLogsSearch(tt.allMessages, tt.internalOnly, tt.since); search != tt.expected {
+				t.Errorf("serviceLogsSearch() = %q, want %q", search, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAppendServiceLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		fetched  int
+		page     int
+		limit    int
+		expected int
+	}{
+		{name: "first page", page: 100, limit: 5000, expected: 100},
+		{name: "last page under the limit", fetched: 4900, page: 40, limit: 5000, expected: 4940},
+		{name: "page over the limit", fetched: 4950, page: 100, limit: 5000, expected: 5000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceLogs := appendServiceLogs(make([]*v1.LogEntry, tt.fetched), make([]*v1.LogEntry, tt.page), tt.limit)
+			if len(serviceLogs) != tt.expected {
+				t.Errorf("appendServiceLogs() kept %d service logs, want %d", len(serviceLogs), tt.expected)
+			}
+		})
+	}
+}
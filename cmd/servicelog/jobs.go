@@ -0,0 +1,388 @@
+package servicelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/internal/servicelog"
+	"github.com/openshift/osdctl/pkg/printer"
+	ocmutils "github.com/openshift/osdctl/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultPostRetries is how many times a send failing with a transient error is retried
+	defaultPostRetries = 3
+	postRetryBackoff   = 5 * time.Second
+)
+
+var errJobAborted = errors.New("the job was aborted")
+
+func newJobsCmd() *cobra.Command {
+	jobsCmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "List, resume and abort the bulk sends of service logs",
+		Long: `Every service log posted with 'osdctl servicelog post' is sent as a job, saved in the osdctl cache directory
+after every cluster. A job interrupted or done with failures can be resumed: only the clusters the message wasn't
+sent to are retried, and the clusters whose send was interrupted are first checked for the entry it may have created.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+	jobsCmd.AddCommand(newJobsListCmd())
+	jobsCmd.AddCommand(newJobsResumeCmd())
+	jobsCmd.AddCommand(newJobsAbortCmd())
+	return jobsCmd
+}
+
+func newJobsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the service log jobs, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := servicelog.DefaultJobStore()
+			if err != nil {
+				return err
+			}
+			jobs, err := store.List()
+			if err != nil {
+				return err
+			}
+			if len(jobs) == 0 {
+				fmt.Println("No service log jobs")
+				return nil
+			}
+			return printJobs(jobs)
+		},
+	}
+}
+
+func newJobsResumeCmd() *cobra.Command {
+	var yes bool
+	var retries int
+	resumeCmd := &cobra.Command{
+		Use:   "resume <job-id>",
+		Short: "Send the service log of a job to the clusters it wasn't sent to",
+		Long: `Send the service log of a job to the clusters it wasn't sent to, failed or interrupted. The ID can be
+shortened to any prefix matching a single job. Don't resume a job another osdctl is still sending.`,
+		Example: `  osdctl servicelog jobs resume 20240312-101500-a1b2c3`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resumeJob(args[0], retries, yes)
+		},
+	}
+	resumeCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skips all prompts.")
+	resumeCmd.Flags().IntVar(&retries, "retries", defaultPostRetries, "Number of times a send failing with a transient error is retried")
+	return resumeCmd
+}
+
+func newJobsAbortCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "abort <job-id>",
+		Short: "Stop a job, the clusters it wasn't sent to yet are skipped",
+		Long: `Stop a job: the clusters the message wasn't sent to yet are skipped, also by an osdctl still sending it,
+and the job can't be resumed anymore.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := servicelog.DefaultJobStore()
+			if err != nil {
+				return err
+			}
+			job, err := store.Load(args[0])
+			if err != nil {
+				return err
+			}
+			if job.Status == servicelog.JobCompleted || job.Status == servicelog.JobAborted {
+				return fmt.Errorf("job %s is already %s", job.ID, job.Status)
+			}
+			job.Status = servicelog.JobAborted
+			if err := store.Save(job, time.Now()); err != nil {
+				return err
+			}
+			sent, _, _ := job.Counts()
+			fmt.Printf("Aborted job %s, the message was sent to %d of its %d clusters\n", job.ID, sent, len(job.Targets))
+			return nil
+		},
+	}
+}
+
+func resumeJob(id string, retries int, yes bool) error {
+	store, err := servicelog.DefaultJobStore()
+	if err != nil {
+		return err
+	}
+	job, err := store.Load(id)
+	if err != nil {
+		return err
+	}
+	switch job.Status {
+	case servicelog.JobAborted:
+		return fmt.Errorf("job %s was aborted", job.ID)
+	case servicelog.JobCompleted:
+		fmt.Printf("Job %s is completed, the message was sent to all its clusters\n", job.ID)
+		return nil
+	}
+
+	unsent := job.Unsent()
+	log.Infof("The message of job %s will be sent to:", job.ID)
+	if err := printJobTargets(unsent); err != nil {
+		return err
+	}
+	if !yes && !ocmutils.ConfirmPrompt() {
+		return nil
+	}
+
+	ocmClient, err := ocmutils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	o := &PostCmdOptions{internalOnly: job.Message.InternalOnly, verify: job.Verify}
+	if err := o.Init(); err != nil {
+		return err
+	}
+	job.Status = servicelog.JobRunning
+	runner := newJobRunner(o, ocmClient, store, job, retries)
+	if err := runner.run(); err != nil {
+		return err
+	}
+	o.printPostOutput()
+	runner.printResumeHint()
+	return nil
+}
+
+// jobRunner sends the message of a job to its clusters, saving the job after every change of a target
+type jobRunner struct {
+	opts      *PostCmdOptions
+	ocmClient *sdk.Connection
+	// store is nil when the job isn't saved, e.g. for the internal service logs other commands post
+	store   *servicelog.JobStore
+	job     *servicelog.Job
+	retries int
+	// mutex guards the job, its targets are updated by concurrent senders
+	mutex sync.Mutex
+}
+
+func newJobRunner(opts *PostCmdOptions, ocmClient *sdk.Connection, store *servicelog.JobStore, job *servicelog.Job, retries int) *jobRunner {
+	return &jobRunner{opts: opts, ocmClient: ocmClient, store: store, job: job, retries: retries}
+}
+
+// run sends the message to the targets it wasn't sent to, the outcome of each is recorded in the options
+func (r *jobRunner) run() error {
+	if err := r.update(func() {}); err != nil {
+		return err
+	}
+	targets := make(map[string]*servicelog.JobTarget)
+	var clusterIDs []string
+	for _, target := range r.job.Unsent() {
+		targets[target.ClusterID] = target
+		clusterIDs = append(clusterIDs, target.ClusterID)
+	}
+
+	// Failures are recorded per cluster by send, so the aggregated error isn't needed here
+	_ = ocmutils.FanOut(clusterIDs, ocmutils.FanOutOptions{
+		MaxConcurrency: PostMaxConcurrency,
+		RateLimiter:    ocmutils.NewRateLimiter(PostMaxRequestsPerSecond),
+	}, func(clusterID string) error {
+		return r.send(targets[clusterID])
+	})
+
+	return r.update(r.job.Finish)
+}
+
+// send sends the message to a target, retrying the transient failures. A target already attempted is first
+// looked up for the entry of its idempotency key, so an interrupted or timed out send isn't duplicated.
+func (r *jobRunner) send(target *servicelog.JobTarget) error {
+	message := r.job.MessageFor(target)
+	var lastErr error
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * postRetryBackoff)
+		}
+		if r.aborted() {
+			r.opts.recordFailure(target.ClusterUUID, errJobAborted.Error())
+			return errJobAborted
+		}
+
+		if r.attempts(target) > 0 {
+			entry, err := r.findSentEntry(message)
+			if err != nil {
+				lastErr = fmt.Errorf("failed to check whether the message was already sent: %w", err)
+				continue
+			}
+			if entry != nil {
+				if err := r.update(func() { markSent(target, entry.ID(), entry.CreatedAt()) }); err != nil {
+					return err
+				}
+				r.opts.recordSuccess(target.ClusterUUID, fmt.Sprintf("Message was already sent as %s", entry.ID()))
+				return nil
+			}
+		}
+
+		if err := r.update(func() {
+			target.Status = servicelog.TargetSending
+			target.Attempts++
+		}); err != nil {
+			return err
+		}
+		reply, transient, err := r.post(message)
+		if err == nil {
+			return r.sent(target, message, reply)
+		}
+		lastErr = err
+		if updateErr := r.update(func() {
+			target.Status = servicelog.TargetFailed
+			target.LastError = err.Error()
+		}); updateErr != nil {
+			return updateErr
+		}
+		if !transient {
+			break
+		}
+		log.Debugf("Sending to %s failed, retrying: %v", target.ClusterID, err)
+	}
+	r.opts.recordFailure(target.ClusterUUID, lastErr.Error())
+	return lastErr
+}
+
+// post sends the message, the error is transient when the request may succeed if sent again
+func (r *jobRunner) post(message servicelog.Message) (*servicelog.GoodReply, bool, error) {
+	request, err := r.opts.createPostRequest(r.ocmClient, message)
+	if err != nil {
+		return nil, false, err
+	}
+	response, err := ocmutils.SendRequest(request)
+	if err != nil {
+		return nil, true, err
+	}
+	if status := response.Status(); status >= 400 {
+		transient := status >= 500 || status == 429
+		badReply, err := validateBadResponse(response.Bytes())
+		if err != nil {
+			return nil, transient, fmt.Errorf("%d: %w", status, err)
+		}
+		return nil, transient, fmt.Errorf("%d: %s", status, badReply.Reason)
+	}
+	reply, err := validateGoodResponse(response.Bytes(), message)
+	return reply, false, err
+}
+
+func (r *jobRunner) sent(target *servicelog.JobTarget, message servicelog.Message, reply *servicelog.GoodReply) error {
+	if err := r.update(func() { markSent(target, reply.ID, time.Now()) }); err != nil {
+		return err
+	}
+	if !r.job.Verify {
+		r.opts.recordSuccess(target.ClusterUUID, fmt.Sprintf("Message has been successfully sent to %s", target.ClusterUUID))
+		return nil
+	}
+	status, err := r.opts.verifyPosted(r.ocmClient, message, reply)
+	if err != nil {
+		r.opts.recordFailure(target.ClusterUUID, fmt.Sprintf("Message %s was sent but not verified: %v", reply.ID, err))
+		return err
+	}
+	r.opts.recordSuccess(target.ClusterUUID, status)
+	return nil
+}
+
+// findSentEntry returns the entry created by a previous attempt of the send, nil if there's none
+func (r *jobRunner) findSentEntry(message servicelog.Message) (*slv1.LogEntry, error) {
+	response, err := r.ocmClient.ServiceLogs().V1().Clusters().ClusterLogs().List().
+		Parameter("cluster_id", message.ClusterID).
+		Parameter("cluster_uuid", message.ClusterUUID).
+		Parameter("orderBy", "timestamp desc").
+		Search(fmt.Sprintf("event_stream_id = '%s' and timestamp >= '%s'", message.EventStreamID, r.job.CreatedAt.Format(time.RFC3339))).
+		Size(postVerifyPageSize).
+		Send()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range response.Items().Slice() {
+		if entry.EventStreamID() == message.EventStreamID && entry.Summary() == message.Summary && entry.Description() == message.Description {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// update changes the job and saves it
+func (r *jobRunner) update(change func()) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	change()
+	if r.store == nil {
+		return nil
+	}
+	if err := r.store.Save(r.job, time.Now()); err != nil {
+		return fmt.Errorf("failed to save job %s: %w", r.job.ID, err)
+	}
+	return nil
+}
+
+func (r *jobRunner) aborted() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.job.Status == servicelog.JobAborted
+}
+
+func (r *jobRunner) attempts(target *servicelog.JobTarget) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return target.Attempts
+}
+
+// printResumeHint tells how to send the message to the clusters it wasn't sent to
+func (r *jobRunner) printResumeHint() {
+	if r.store == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.job.Status == servicelog.JobFailed {
+		log.Infof("Retry the failed clusters with 'osdctl servicelog jobs resume %s'", r.job.ID)
+	}
+}
+
+func markSent(target *servicelog.JobTarget, entryID string, sentAt time.Time) {
+	target.Status = servicelog.TargetSent
+	target.EntryID = entryID
+	target.SentAt = sentAt.UTC()
+	target.LastError = ""
+}
+
+func printJobs(jobs []*servicelog.Job) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"ID", "CREATED", "STATUS", "SENT", "FAILED", "PENDING", "SUMMARY"})
+	for _, job := range jobs {
+		sent, failed, pending := job.Counts()
+		table.AddRow([]string{
+			job.ID,
+			job.CreatedAt.Local().Format(time.DateTime),
+			string(job.Status),
+			strconv.Itoa(sent),
+			strconv.Itoa(failed),
+			strconv.Itoa(pending),
+			job.Message.Summary,
+		})
+	}
+	return table.Flush()
+}
+
+func printJobTargets(targets []*servicelog.JobTarget) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"Name", "ID", "Status", "Attempts", "Last Error"})
+	for _, target := range targets {
+		table.AddRow([]string{target.ClusterName, target.ClusterID, string(target.Status), strconv.Itoa(target.Attempts), target.LastError})
+	}
+	table.AddRow([]string{})
+	return table.Flush()
+}
@@ -9,8 +9,14 @@ import (
 
 	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
 
+	"github.com/google/uuid"
 	"github.com/openshift-online/ocm-cli/pkg/dump"
+	"github.com/openshift/osdctl/pkg/pager"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/redact"
+	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 const (
@@ -18,14 +24,35 @@ const (
 	AllMessagesShortFlag = "A"
 	InternalFlag         = "internal"
 	InternalShortFlag    = "i"
+	DaysFlag             = "days"
 )
 
+// listTableOptions are set by --columns and --sort-by
+var listTableOptions printer.TableOptions
+
+// listPagerOptions are set by --pager and --search
+var listPagerOptions pager.Options
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list [flags] [options] cluster-identifier",
 	Short: "gets all servicelog messages for a given cluster",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		externalClusterID, err := cmd.Flags().GetString(utils.ExternalClusterIDFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get flag `--%v`, %w", utils.ExternalClusterIDFlag, err)
+		}
+		if (len(args) == 1) == (externalClusterID != "") {
+			return fmt.Errorf("provide exactly one cluster identifier or `--%v`", utils.ExternalClusterIDFlag)
+		}
+		if externalClusterID != "" {
+			if _, err := uuid.Parse(externalClusterID); err != nil {
+				return fmt.Errorf("'%s' is not a valid external cluster ID: %w", externalClusterID, err)
+			}
+			args = []string{externalClusterID}
+		}
+
 		allMessages, err := cmd.Flags().GetBool(AllMessagesFlag)
 		if err != nil {
 			return fmt.Errorf("failed to get flag `--%v`/`-%v`, %w", AllMessagesFlag, AllMessagesShortFlag, err)
@@ -36,7 +63,44 @@ var listCmd = &cobra.Command{
 			return fmt.Errorf("failed to get flag `--%v`/`-%v`, %w", InternalFlag, InternalShortFlag, err)
 		}
 
-		return ListServiceLogs(args[0], allMessages, internalOnly)
+		days, err := cmd.Flags().GetInt(DaysFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get flag `--%v`, %w", DaysFlag, err)
+		}
+		if days < 0 {
+			return fmt.Errorf("`--%v` can't be negative", DaysFlag)
+		}
+
+		listPagerOptions.Complete(cmd.Flags())
+		stopPager, err := listPagerOptions.Start()
+		if err != nil {
+			return err
+		}
+		defer stopPager()
+
+		shouldRedact := viper.GetBool(redact.RedactConfigKey)
+		if cmd.Flags().Changed(redact.RedactFlagName) {
+			if shouldRedact, err = cmd.Flags().GetBool(redact.RedactFlagName); err != nil {
+				return fmt.Errorf("failed to get flag `--%v`, %w", redact.RedactFlagName, err)
+			}
+		}
+		if shouldRedact {
+			terms, err := getCustomerTerms(args[0])
+			if err != nil {
+				return err
+			}
+			restore, err := redact.New(terms...).Stdout()
+			if err != nil {
+				return err
+			}
+			defer restore()
+		}
+
+		var since time.Time
+		if days > 0 {
+			since = time.Now().AddDate(0, 0, -days)
+		}
+		return ListServiceLogs(args[0], allMessages, internalOnly, since, listTableOptions)
 	},
 }
 
@@ -44,14 +108,26 @@ func init() {
 	// define flags
 	listCmd.Flags().BoolP(AllMessagesFlag, AllMessagesShortFlag, false, "Toggle if we should see all of the messages or only SRE-P specific ones")
 	listCmd.Flags().BoolP(InternalFlag, InternalShortFlag, false, "Toggle if we should see internal messages")
+	listCmd.Flags().Int(DaysFlag, 0, "Only list the service logs sent in the last X days, all of them by default")
+	listCmd.Flags().Bool(redact.RedactFlagName, false, redact.RedactFlagUsage)
+	listCmd.Flags().String(utils.ExternalClusterIDFlag, "", "Look the cluster up strictly by its external UUID instead of a positional identifier")
+	// The service logs are printed as JSON, unless a table is requested through these flags
+	printer.AddTableFlags(listCmd.Flags(), &listTableOptions)
+	pager.AddFlags(listCmd.Flags(), &listPagerOptions)
 }
 
-func ListServiceLogs(clusterID string, allMessages bool, internalOnly bool) error {
-	response, err := FetchServiceLogs(clusterID, allMessages, internalOnly)
+// ListServiceLogs prints the service logs of a cluster sent after since as JSON, or as a table when tableOptions
+// are set
+func ListServiceLogs(clusterID string, allMessages bool, internalOnly bool, since time.Time, tableOptions printer.TableOptions) error {
+	response, err := FetchServiceLogs(clusterID, allMessages, internalOnly, since)
 	if err != nil {
 		return fmt.Errorf("failed to fetch service logs: %w", err)
 	}
 
+	if tableOptions.IsSet() {
+		return printServiceLogTable(newLogEntryResponseView(response).Items, tableOptions)
+	}
+
 	if err = printServiceLogResponse(response); err != nil {
 		return fmt.Errorf("failed to print service logs: %w", err)
 	}
@@ -59,16 +135,53 @@ func ListServiceLogs(clusterID string, allMessages bool, internalOnly bool) erro
 	return nil
 }
 
-func printServiceLogResponse(response *slv1.ClustersClusterLogsListResponse) error {
-	entryViews := logEntryToView(response.Items().Slice())
+// getCustomerTerms returns the customer terms of the cluster to redact, as 'osdctl cluster context' does
+func getCustomerTerms(clusterKey string) ([]string, error) {
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer ocmClient.Close()
+
+	clusters := utils.GetClusters(ocmClient, []string{clusterKey})
+	if len(clusters) > 1 {
+		return nil, utils.NewAmbiguousClusterError(clusterKey, clusters)
+	}
+	if len(clusters) != 1 {
+		return nil, fmt.Errorf("GetClusters expected to return 1 cluster, got: %d", len(clusters))
+	}
+	cluster := clusters[0]
+
+	orgID, err := utils.GetOrgfromClusterID(ocmClient, *cluster)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get the organization of cluster %s, its name won't be redacted: %v\n", clusterKey, err)
+	}
+	return utils.GetCustomerTerms(ocmClient, cluster.ID(), orgID), nil
+}
+
+// GetServiceLogsView returns the service logs of a cluster in the same format as 'osdctl servicelog list'
+func GetServiceLogsView(clusterID string, allMessages bool, internalOnly bool) (*LogEntryResponseView, error) {
+	response, err := FetchServiceLogs(clusterID, allMessages, internalOnly, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return newLogEntryResponseView(response), nil
+}
+
+func newLogEntryResponseView(response *ServiceLogList) *LogEntryResponseView {
+	entryViews := logEntryToView(response.Items)
 	slices.Reverse(entryViews)
-	view := LogEntryResponseView{
+	return &LogEntryResponseView{
 		Items: entryViews,
 		Kind:  "ClusterLogList",
-		Page:  response.Page(),
-		Size:  response.Size(),
-		Total: response.Total(),
+		Page:  1,
+		Size:  len(entryViews),
+		Total: response.Total,
 	}
+}
+
+func printServiceLogResponse(response *ServiceLogList) error {
+	view := newLogEntryResponseView(response)
 
 	viewBytes, err := json.Marshal(view)
 	if err != nil {
@@ -78,6 +191,23 @@ func printServiceLogResponse(response *slv1.ClustersClusterLogsListResponse) err
 	return dump.Pretty(os.Stdout, viewBytes)
 }
 
+func printServiceLogTable(entries []*LogEntryView, tableOptions printer.TableOptions) error {
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ').WithTableOptions(tableOptions)
+	table.AddRow([]string{"ID", "CREATED", "SEVERITY", "SERVICE", "INTERNAL", "USERNAME", "SUMMARY"})
+	for _, entry := range entries {
+		table.AddRow([]string{
+			entry.ID,
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			entry.Severity,
+			entry.ServiceName,
+			fmt.Sprintf("%t", entry.InternalOnly),
+			entry.Username,
+			entry.Summary,
+		})
+	}
+	return table.Flush()
+}
+
 type LogEntryResponseView struct {
 	Items []*LogEntryView `json:"items"`
 	Kind  string          `json:"kind"`
@@ -1,14 +1,18 @@
 package servicelog
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"net/url"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/utils/strings/slices"
@@ -17,36 +21,70 @@ import (
 	"github.com/openshift-online/ocm-cli/pkg/dump"
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
 	"github.com/openshift/osdctl/internal/servicelog"
 	"github.com/openshift/osdctl/internal/utils"
+	"github.com/openshift/osdctl/pkg/guardrails"
 	"github.com/openshift/osdctl/pkg/printer"
 	ocmutils "github.com/openshift/osdctl/pkg/utils"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"golang.org/x/term"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 type PostCmdOptions struct {
-	Message         servicelog.Message
-	ClustersFile    servicelog.ClustersFile
-	Template        string
-	TemplateParams  []string
-	filterFiles     []string // Path to filter file
-	filtersFromFile string   // Contents of filterFiles
-	filterParams    []string
-	isDryRun        bool
-	skipPrompts     bool
-	clustersFile    string
-	internalOnly    bool
-	ClusterId       string
+	Message        servicelog.Message
+	ClustersFile   servicelog.ClustersFile
+	Template       string
+	TemplateParams []string
+	// TemplateChecksum is the expected sha256 of a template downloaded from a URL
+	TemplateChecksum string
+	filterFiles      []string // Path to filter file
+	filtersFromFile  string   // Contents of filterFiles
+	filterParams     []string
+	isDryRun         bool
+	skipPrompts      bool
+	clustersFile     string
+	internalOnly     bool
+	ClusterId        string
+	// verify re-fetches the service logs of each cluster after posting to check the new entry is listed
+	verify bool
+	// guardrails are only enforced when posting from the command line, not for the internal service logs
+	// other commands post
+	guardrails bool
+	// saveJob saves the send as a job which can be resumed, for the posts from the command line
+	saveJob bool
+	// retries is how many times a send failing with a transient error is retried
+	retries int
+	// checkContent validates the content of the customer-visible service logs posted from the command line
+	checkContent bool
+	// force sends the service log despite the issues found in its content
+	force bool
+	// dictionary is the word list the service log is spell checked with
+	dictionary string
 
 	// Messaged clusters
 	successfulClusters map[string]string
 	failedClusters     map[string]string
+	resultsMutex       *sync.Mutex
 }
 
-const documentationBaseURL = "https://docs.openshift.com"
+const (
+	documentationBaseURL = "https://docs.openshift.com"
+
+	// PostMaxConcurrency and PostMaxRequestsPerSecond bound bulk sends, so posting to
+	// hundreds of clusters doesn't hammer the service logs API
+	PostMaxConcurrency       = 5
+	PostMaxRequestsPerSecond = 10
+
+	// the service logs API may take a moment to list a new entry, --verify retries for that long
+	postVerifyInterval = 2 * time.Second
+	postVerifyTimeout  = 30 * time.Second
+	postVerifyPageSize = 50
+)
 
 func newPostCmd() *cobra.Command {
 	var opts = PostCmdOptions{}
@@ -72,12 +110,16 @@ func newPostCmd() *cobra.Command {
 			if len(args) > 0 {
 				opts.ClusterId = args[0]
 			}
+			opts.guardrails = true
+			opts.saveJob = true
+			opts.checkContent = true
 			return opts.Run()
 		},
 	}
 
 	// define required flags
 	postCmd.Flags().StringVarP(&opts.Template, "template", "t", "", "Message template file or URL")
+	postCmd.Flags().StringVar(&opts.TemplateChecksum, utils.TemplateChecksumFlagName, "", fmt.Sprintf("Expected sha256 of the template downloaded from a URL. Templates can also be pinned as '<sha256> <url>' entries of '%s' in the config, and signed with the keys of '%s'", utils.TemplatePinsConfigKey, utils.TemplateSigningKeysConfigKey))
 	postCmd.Flags().StringArrayVarP(&opts.TemplateParams, "param", "p", opts.TemplateParams, "Specify a key-value pair (eg. -p FOO=BAR) to set/override a parameter value in the template.")
 	postCmd.Flags().BoolVarP(&opts.isDryRun, "dry-run", "d", false, "Dry-run - print the service log about to be sent but don't send it.")
 	postCmd.Flags().StringArrayVarP(&opts.filterParams, "query", "q", []string{}, "Specify a search query (eg. -q \"name like foo\") for a bulk-post to matching clusters.")
@@ -85,6 +127,10 @@ func newPostCmd() *cobra.Command {
 	postCmd.Flags().StringArrayVarP(&opts.filterFiles, "query-file", "f", []string{}, "File containing search queries to apply. All lines in the file will be concatenated into a single query. If this flag is called multiple times, every file's search query will be combined with logical AND.")
 	postCmd.Flags().StringVarP(&opts.clustersFile, "clusters-file", "c", "", `Read a list of clusters to post the servicelog to. the format of the file is: {"clusters":["$CLUSTERID"]}`)
 	postCmd.Flags().BoolVarP(&opts.internalOnly, "internal", "i", false, "Internal only service log. Use MESSAGE for template parameter (eg. -p MESSAGE='My super secret message').")
+	postCmd.Flags().BoolVar(&opts.verify, "verify", false, "After posting, re-fetch the service logs of each cluster and check the new entry is listed with the expected severity and visibility.")
+	postCmd.Flags().IntVar(&opts.retries, "retries", defaultPostRetries, "Number of times a send failing with a transient error (timeout, 5xx, 429) is retried")
+	postCmd.Flags().BoolVar(&opts.force, "force", false, "Send the service log even though issues were found in its content (broken links, placeholders left, inconsistent severity, spelling)")
+	postCmd.Flags().StringVar(&opts.dictionary, "dictionary", "", fmt.Sprintf("Word list to spell check the service log with, e.g. /usr/share/dict/words. Defaults to '%s' of the config, the spell check is skipped without one", DictionaryConfigKey))
 
 	return postCmd
 }
@@ -94,6 +140,7 @@ func (o *PostCmdOptions) Init() error {
 	userParameterValues = []string{}
 	o.successfulClusters = make(map[string]string)
 	o.failedClusters = make(map[string]string)
+	o.resultsMutex = &sync.Mutex{}
 	return nil
 }
 
@@ -104,6 +151,19 @@ func (o *PostCmdOptions) Validate() error {
 	return nil
 }
 
+// PostInternalServiceLog posts an internal only service log to a single cluster without prompting,
+// so commands changing a cluster can leave an audit trail of what was done and why
+func PostInternalServiceLog(clusterID string, message string) error {
+	o := &PostCmdOptions{
+		ClusterId:      clusterID,
+		TemplateParams: []string{fmt.Sprintf("MESSAGE=%s", message)},
+		internalOnly:   true,
+		skipPrompts:    true,
+		retries:        defaultPostRetries,
+	}
+	return o.Run()
+}
+
 // CheckServiceLogsLastHour returns true if there were servicelogs sent in the past hour, otherwise false
 func CheckServiceLogsLastHour(clusterId string) bool {
 	timeStampToCompare := time.Now().Add(-time.Hour)
@@ -219,6 +279,21 @@ func (o *PostCmdOptions) Run() error {
 		return fmt.Errorf("cannot read generated template: %w", err)
 	}
 
+	if err := o.validateContent(); err != nil {
+		return err
+	}
+
+	if o.guardrails {
+		if err := guardrails.Enforce(guardrails.Invocation{
+			Command:     "servicelog post",
+			Fingerprint: o.fingerprint(clusters),
+			DryRun:      o.isDryRun,
+			Clusters:    len(clusters),
+		}); err != nil {
+			return err
+		}
+	}
+
 	// If this is a dry-run, don't proceed further.
 	if o.isDryRun {
 		return nil
@@ -230,28 +305,19 @@ func (o *PostCmdOptions) Run() error {
 		}
 	}
 
-	// Handler if the program terminates abruptly
-	go func() {
-		sigchan := make(chan os.Signal, 1)
-		signal.Notify(sigchan, os.Interrupt)
-		<-sigchan
-
-		// perform final cleanup actions
-		log.Error("program abruptly terminated, performing clean-up...")
-		o.cleanUp(clusters)
-		log.Fatal("servicelog post command terminated")
-	}()
-
 	// cluster type for which documentation link is provided in servicelog description
 	docClusterType := getDocClusterType(o.Message.Description)
 
-	for _, cluster := range clusters {
-		request, err := o.createPostRequest(ocmClient, cluster)
-		if err != nil {
-			o.failedClusters[cluster.ExternalID()] = err.Error()
-			continue
-		}
+	message := o.Message
+	message.InternalOnly = o.internalOnly
+	job, err := servicelog.NewJob(message, time.Now())
+	if err != nil {
+		return err
+	}
+	job.Verify = o.verify
 
+	// Confirmations have to happen sequentially, so settle them before sending in parallel
+	for _, cluster := range clusters {
 		// if servicelog description contains a documentation link, verify that
 		// documentation link matches the cluster product (rosa, dedicated)
 		if !o.skipPrompts && docClusterType != "" {
@@ -265,20 +331,99 @@ func (o *PostCmdOptions) Run() error {
 				}
 			}
 		}
+		job.AddTarget(cluster.ID(), cluster.ExternalID(), cluster.Name(), cluster.Subscription().ID())
+	}
 
-		response, err := ocmutils.SendRequest(request)
-		if err != nil {
-			o.failedClusters[cluster.ExternalID()] = err.Error()
-			continue
+	var store *servicelog.JobStore
+	if o.saveJob {
+		if store, err = servicelog.DefaultJobStore(); err != nil {
+			return err
+		}
+		if err := store.Prune(time.Now()); err != nil {
+			log.Warnf("Failed to delete the old service log jobs: %v", err)
+		}
+		log.Infof("Sending as job %s", job.ID)
+	}
+	runner := newJobRunner(o, ocmClient, store, job, o.retries)
+
+	// Handler if the program terminates abruptly
+	go func() {
+		sigchan := make(chan os.Signal, 1)
+		signal.Notify(sigchan, os.Interrupt)
+		<-sigchan
+
+		// perform final cleanup actions
+		log.Error("program abruptly terminated, performing clean-up...")
+		o.cleanUp(clusters)
+		if store != nil {
+			log.Infof("Send the message to the remaining clusters with 'osdctl servicelog jobs resume %s'", job.ID)
 		}
+		log.Fatal("servicelog post command terminated")
+	}()
 
-		o.check(response, o.Message)
+	if err := runner.run(); err != nil {
+		return err
 	}
 
 	o.printPostOutput()
+	runner.printResumeHint()
 	return nil
 }
 
+// validateContent checks the content of a customer-visible service log and fails on the issues found,
+// unless --force is set
+func (o *PostCmdOptions) validateContent() error {
+	if !o.checkContent || o.internalOnly {
+		return nil
+	}
+	options := contentCheckOptions{
+		linkClient: &http.Client{Timeout: previewLinkTimeout},
+		excludes:   []string{clusterUUIDParameter},
+	}
+	dictionary := o.dictionary
+	if dictionary == "" {
+		dictionary = viper.GetString(DictionaryConfigKey)
+	}
+	if dictionary != "" {
+		words, err := loadDictionary(dictionary)
+		if err != nil {
+			return err
+		}
+		options.dictionary = words
+	}
+
+	issues := validateContent(o.Message, options)
+	if len(issues) == 0 {
+		return nil
+	}
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"CHECK", "ISSUE"})
+	for _, issue := range issues {
+		table.AddRow([]string{issue.check, issue.message})
+	}
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if o.force {
+		log.Warnf("%d issues were found in the content of the service log, sending it anyway as --force is set", len(issues))
+		return nil
+	}
+	return fmt.Errorf("%d issues were found in the content of the service log, fix them or send it anyway with --force", len(issues))
+}
+
+// fingerprint identifies the message and the clusters it's sent to, for the dry_run_first guardrail
+func (o *PostCmdOptions) fingerprint(clusters []*v1.Cluster) string {
+	var ids []string
+	for _, cluster := range clusters {
+		ids = append(ids, cluster.ID())
+	}
+	sort.Strings(ids)
+	message, _ := json.Marshal(o.Message)
+	sum := sha256.Sum256([]byte(string(message) + strings.Join(ids, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
 // if servicelog description contains documentation link, parse and return the cluster type from the url
 func getDocClusterType(message string) string {
 
@@ -301,23 +446,69 @@ func getDocClusterType(message string) string {
 	return ""
 }
 
-func (o *PostCmdOptions) check(response *sdk.Response, clusterMessage servicelog.Message) {
-	body := response.Bytes()
-	if response.Status() < 400 {
-		_, err := validateGoodResponse(body, clusterMessage)
+// verifyPosted waits for the posted entry to be listed in the service logs of the cluster and checks its
+// severity and visibility, it returns the status to report with the ID and URL of the entry
+func (o *PostCmdOptions) verifyPosted(ocmClient *sdk.Connection, message servicelog.Message, reply *servicelog.GoodReply) (string, error) {
+	var entry *slv1.LogEntry
+	err := wait.PollImmediate(postVerifyInterval, postVerifyTimeout, func() (bool, error) {
+		response, err := ocmClient.ServiceLogs().V1().Clusters().ClusterLogs().List().
+			Parameter("cluster_id", message.ClusterID).
+			Parameter("cluster_uuid", message.ClusterUUID).
+			Parameter("orderBy", "timestamp desc").
+			Size(postVerifyPageSize).
+			Send()
 		if err != nil {
-			o.failedClusters[clusterMessage.ClusterUUID] = err.Error()
-		} else {
-			o.successfulClusters[clusterMessage.ClusterUUID] = fmt.Sprintf("Message has been successfully sent to %s", clusterMessage.ClusterUUID)
+			return false, fmt.Errorf("failed to fetch service logs: %w", err)
 		}
-	} else {
-		badReply, err := validateBadResponse(body)
-		if err != nil {
-			o.failedClusters[clusterMessage.ClusterUUID] = err.Error()
-		} else {
-			o.failedClusters[clusterMessage.ClusterUUID] = badReply.Reason
+		entry = findLogEntry(response.Items().Slice(), reply.ID)
+		return entry != nil, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return "", fmt.Errorf("the entry isn't listed in the service logs of the cluster after %s", postVerifyTimeout)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := verifyLogEntry(entry, message); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Message %s verified: %s%s", entry.ID(), ocmClient.URL(), entry.HREF()), nil
+}
+
+func findLogEntry(entries []*slv1.LogEntry, id string) *slv1.LogEntry {
+	for _, entry := range entries {
+		if entry.ID() == id {
+			return entry
 		}
 	}
+	return nil
+}
+
+// verifyLogEntry checks the listed entry has the severity and visibility of the message that was posted
+func verifyLogEntry(entry *slv1.LogEntry, message servicelog.Message) error {
+	if severity := string(entry.Severity()); severity != message.Severity {
+		return fmt.Errorf("the entry is listed with severity %q instead of %q", severity, message.Severity)
+	}
+	if entry.InternalOnly() != message.InternalOnly {
+		if message.InternalOnly {
+			return fmt.Errorf("the entry is visible to the customer but was posted as internal only")
+		}
+		return fmt.Errorf("the entry is internal only but was posted as visible to the customer")
+	}
+	return nil
+}
+
+// recordSuccess and recordFailure are safe to call from concurrent senders
+func (o *PostCmdOptions) recordSuccess(clusterUUID string, status string) {
+	o.resultsMutex.Lock()
+	defer o.resultsMutex.Unlock()
+	o.successfulClusters[clusterUUID] = status
+}
+
+func (o *PostCmdOptions) recordFailure(clusterUUID string, reason string) {
+	o.resultsMutex.Lock()
+	defer o.resultsMutex.Unlock()
+	o.failedClusters[clusterUUID] = reason
 }
 
 // parseUserParameters parse all the '-p FOO=BAR' parameters and checks for syntax errors
@@ -341,11 +532,11 @@ func (o *PostCmdOptions) parseUserParameters() {
 func (o *PostCmdOptions) accessFile(filePath string) ([]byte, error) {
 
 	if utils.IsValidUrl(filePath) {
-		urlPage, _ := url.Parse(filePath)
-		if err := utils.IsOnline(*urlPage); err != nil {
-			return nil, fmt.Errorf("host %q is not accessible", filePath)
-		}
-		return utils.CurlThis(urlPage.String())
+		return utils.FetchVerifiedTemplate(filePath, utils.TemplateVerification{
+			Checksum:    o.TemplateChecksum,
+			Pins:        viper.GetStringSlice(utils.TemplatePinsConfigKey),
+			SigningKeys: viper.GetStringSlice(utils.TemplateSigningKeysConfigKey),
+		})
 	}
 
 	filePath = filepath.Clean(filePath)
@@ -493,22 +684,14 @@ func (o *PostCmdOptions) printTemplate() (err error) {
 	return dump.Pretty(os.Stdout, exampleMessage)
 }
 
-func (o *PostCmdOptions) createPostRequest(ocmClient *sdk.Connection, cluster *v1.Cluster) (request *sdk.Request, err error) {
-	// Create and populate the request:
-	request = ocmClient.Post()
-	err = arguments.ApplyPathArg(request, targetAPIPath)
-	if err != nil {
+// createPostRequest builds the request posting the message, addressed to a cluster by the job
+func (o *PostCmdOptions) createPostRequest(ocmClient *sdk.Connection, message servicelog.Message) (*sdk.Request, error) {
+	request := ocmClient.Post()
+	if err := arguments.ApplyPathArg(request, targetAPIPath); err != nil {
 		return nil, fmt.Errorf("cannot parse API path '%s': %v", targetAPIPath, err)
 	}
 
-	o.Message.ClusterUUID = cluster.ExternalID()
-	o.Message.ClusterID = cluster.ID()
-	o.Message.InternalOnly = o.internalOnly
-	if subscription := cluster.Subscription(); subscription != nil {
-		o.Message.SubscriptionID = cluster.Subscription().ID()
-	}
-
-	messageBytes, err := json.Marshal(o.Message)
+	messageBytes, err := json.Marshal(message)
 	if err != nil {
 		return nil, fmt.Errorf("cannot marshal template to json: %v", err)
 	}
@@ -556,6 +739,8 @@ func (o *PostCmdOptions) printPostOutput() {
 
 // cleanUp performs final actions in case of program termination.
 func (o *PostCmdOptions) cleanUp(clusters []*v1.Cluster) {
+	o.resultsMutex.Lock()
+	defer o.resultsMutex.Unlock()
 	for _, cluster := range clusters {
 		if _, ok := o.successfulClusters[cluster.ExternalID()]; !ok {
 			o.failedClusters[cluster.ExternalID()] = "cannot send message due to program interruption"
@@ -0,0 +1,277 @@
+package servicelog
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openshift/osdctl/internal/servicelog"
+	"github.com/openshift/osdctl/internal/utils"
+	"github.com/openshift/osdctl/pkg/printer"
+	ocmutils "github.com/openshift/osdctl/pkg/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	clusterUUIDParameter = "${CLUSTER_UUID}"
+	previewLinkTimeout   = 10 * time.Second
+)
+
+// linkRegex matches the links of a service log, the customer's cluster UI and email turn them into hyperlinks
+var linkRegex = regexp.MustCompile(`https?://[^\s<>"'()]+`)
+
+type previewOptions struct {
+	post          PostCmdOptions
+	clusterID     string
+	htmlFile      string
+	skipLinkCheck bool
+}
+
+// linkStatus is the result of requesting a link of the service log
+type linkStatus struct {
+	status int
+	err    error
+}
+
+func (l linkStatus) ok() bool {
+	return l.err == nil && l.status == http.StatusOK
+}
+
+func (l linkStatus) String() string {
+	if l.err != nil {
+		return l.err.Error()
+	}
+	return fmt.Sprintf("%d %s", l.status, http.StatusText(l.status))
+}
+
+func newPreviewCmd() *cobra.Command {
+	ops := &previewOptions{}
+	previewCmd := &cobra.Command{
+		Use:   "preview [cluster-id]",
+		Short: "Render a service log as the customer will see it, and check its documentation links",
+		Long: `Render the subject and body of a service log template with its parameters substituted, as shown in the
+cluster UI and the notification email, without posting it. With a cluster, ${CLUSTER_UUID} is substituted and the
+documentation links are checked against the product of the cluster.
+
+Every link of the service log is requested and the command fails unless they all answer 200 OK.`,
+		Example: `  # Preview a template with its parameters
+  osdctl servicelog preview -t https://raw.githubusercontent.com/openshift/managed-notifications/master/osd/incident_resolved.json -p ALERT_NAME="alert"
+
+  # Preview it for a cluster and write the notification email as HTML
+  osdctl servicelog preview <cluster-id> -t file.json --html /tmp/preview.html`,
+		Args:              cobra.MaximumNArgs(1),
+		DisableAutoGenTag: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				ops.clusterID = args[0]
+			}
+			return ops.run()
+		},
+	}
+
+	previewCmd.Flags().StringVarP(&ops.post.Template, "template", "t", "", "Message template file or URL")
+	previewCmd.Flags().StringVar(&ops.post.TemplateChecksum, utils.TemplateChecksumFlagName, "", "Expected sha256 of the template downloaded from a URL")
+	previewCmd.Flags().StringArrayVarP(&ops.post.TemplateParams, "param", "p", []string{}, "Specify a key-value pair (eg. -p FOO=BAR) to set/override a parameter value in the template.")
+	previewCmd.Flags().StringVar(&ops.htmlFile, "html", "", "Also write the notification email, as HTML, to this file")
+	previewCmd.Flags().BoolVar(&ops.skipLinkCheck, "skip-link-check", false, "Don't request the links of the service log")
+
+	return previewCmd
+}
+
+func (o *previewOptions) run() error {
+	if err := o.post.Init(); err != nil {
+		return err
+	}
+	o.post.parseUserParameters()
+	o.post.readTemplate()
+	for k := range userParameterNames {
+		o.post.replaceFlags(userParameterNames[k], userParameterValues[k])
+	}
+	// ${CLUSTER_UUID} is left as is without a cluster, it's substituted by the service logs API
+	o.post.checkLeftovers([]string{clusterUUIDParameter})
+
+	message := o.post.Message
+	if o.clusterID != "" {
+		if err := o.substituteCluster(&message); err != nil {
+			return err
+		}
+	}
+
+	printPreview(os.Stdout, message)
+
+	if o.htmlFile != "" {
+		var email bytes.Buffer
+		if err := renderEmail(&email, message); err != nil {
+			return err
+		}
+		if err := os.WriteFile(o.htmlFile, email.Bytes(), 0600); err != nil {
+			return fmt.Errorf("failed to write the email to %s: %w", o.htmlFile, err)
+		}
+		fmt.Printf("The notification email was written to %s\n", o.htmlFile)
+	}
+
+	if o.skipLinkCheck {
+		return nil
+	}
+	links := messageLinks(message)
+	if len(links) == 0 {
+		return nil
+	}
+	client := &http.Client{Timeout: previewLinkTimeout}
+	var broken int
+	table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
+	table.AddRow([]string{"LINK", "STATUS"})
+	for _, link := range links {
+		status := checkLink(client, link)
+		if !status.ok() {
+			broken++
+		}
+		table.AddRow([]string{link, status.String()})
+	}
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if broken > 0 {
+		return fmt.Errorf("%d of the %d links of the service log don't answer 200 OK", broken, len(links))
+	}
+	return nil
+}
+
+// substituteCluster fills in the cluster the service log would be posted to, and warns when the
+// documentation links are for another product
+func (o *previewOptions) substituteCluster(message *servicelog.Message) error {
+	connection, err := ocmutils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	cluster, err := ocmutils.GetClusterAnyStatus(connection, o.clusterID)
+	if err != nil {
+		return err
+	}
+
+	message.ReplaceWithFlag(clusterUUIDParameter, cluster.ExternalID())
+	message.ClusterUUID = cluster.ExternalID()
+	message.ClusterID = cluster.ID()
+	if docClusterType := getDocClusterType(message.Description); docClusterType != "" && docClusterType != cluster.Product().ID() {
+		log.Warnf("The documentation mentioned in the servicelog is for '%s' while the product is '%s'.", docClusterType, cluster.Product().ID())
+	}
+	return nil
+}
+
+// printPreview prints the service log as shown in the cluster UI
+func printPreview(w io.Writer, message servicelog.Message) {
+	visibility := "Customer facing"
+	if message.InternalOnly {
+		visibility = "Internal only, not shown to the customer"
+	}
+	fmt.Fprintf(w, "Subject:    %s\n", message.Summary)
+	fmt.Fprintf(w, "Severity:   %s\n", message.Severity)
+	fmt.Fprintf(w, "Service:    %s\n", message.ServiceName)
+	fmt.Fprintf(w, "Visibility: %s\n", visibility)
+	if message.ClusterUUID != "" {
+		fmt.Fprintf(w, "Cluster:    %s\n", message.ClusterUUID)
+	}
+	fmt.Fprintf(w, "\n%s\n\n", message.Description)
+	if len(message.DocReferences) > 0 {
+		fmt.Fprintln(w, "Documentation:")
+		for _, reference := range message.DocReferences {
+			fmt.Fprintf(w, "  - %s\n", reference)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+var emailTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Summary }}</title>
+</head>
+<body style="font-family: sans-serif; max-width: 40em;">
+<p><strong>Subject:</strong> {{ .Summary }}</p>
+<p><strong>Severity:</strong> {{ .Severity }}</p>
+<hr>
+<p>{{ .Body }}</p>
+{{- if .DocReferences }}
+<p>Documentation:</p>
+<ul>
+{{- range .DocReferences }}
+<li><a href="{{ . }}">{{ . }}</a></li>
+{{- end }}
+</ul>
+{{- end }}
+</body>
+</html>
+`))
+
+// renderEmail writes the notification email of the service log as HTML, with its links and line breaks
+func renderEmail(w io.Writer, message servicelog.Message) error {
+	return emailTemplate.Execute(w, struct {
+		Summary       string
+		Severity      string
+		Body          template.HTML
+		DocReferences []string
+	}{
+		Summary:       message.Summary,
+		Severity:      message.Severity,
+		Body:          linkify(message.Description),
+		DocReferences: message.DocReferences,
+	})
+}
+
+// linkify escapes the text and turns its links into hyperlinks and its line breaks into <br>
+func linkify(text string) template.HTML {
+	var out strings.Builder
+	last := 0
+	for _, match := range linkRegex.FindAllStringIndex(text, -1) {
+		link := trimLink(text[match[0]:match[1]])
+		end := match[0] + len(link)
+		out.WriteString(html.EscapeString(text[last:match[0]]))
+		fmt.Fprintf(&out, `<a href="%[1]s">%[1]s</a>`, html.EscapeString(link))
+		last = end
+	}
+	out.WriteString(html.EscapeString(text[last:]))
+	return template.HTML(strings.ReplaceAll(out.String(), "\n", "<br>\n")) //#nosec G203 -- the text is escaped above
+}
+
+// trimLink removes the punctuation ending a sentence after a link
+func trimLink(link string) string {
+	return strings.TrimRight(link, ".,;:!?")
+}
+
+// messageLinks returns the distinct links of the description and the documentation references
+func messageLinks(message servicelog.Message) []string {
+	var links []string
+	seen := map[string]bool{}
+	candidates := linkRegex.FindAllString(message.Description, -1)
+	candidates = append(candidates, message.DocReferences...)
+	for _, link := range candidates {
+		link = trimLink(link)
+		if link == "" || seen[link] {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+	}
+	return links
+}
+
+// checkLink requests the link, following redirects, as the customer clicking it would
+func checkLink(client *http.Client, link string) linkStatus {
+	response, err := client.Get(link) //#nosec G107 -- the links of the service log are meant to be requested
+	if err != nil {
+		return linkStatus{err: err}
+	}
+	defer response.Body.Close()
+	return linkStatus{status: response.StatusCode}
+}
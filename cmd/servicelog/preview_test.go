@@ -0,0 +1,58 @@
+package servicelog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/osdctl/internal/servicelog"
+)
+
+func TestLinkify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "plain text is escaped",
+			text: "Nodes <worker> & masters",
+			want: "Nodes &lt;worker&gt; &amp; masters",
+		},
+		{
+			name: "links become hyperlinks without the sentence punctuation",
+			text: "See https://docs.openshift.com/dedicated/welcome/index.html.",
+			want: `See <a href="https://docs.openshift.com/dedicated/welcome/index.html">https://docs.openshift.com/dedicated/welcome/index.html</a>.`,
+		},
+		{
+			name: "line breaks are kept",
+			text: "First\nSecond",
+			want: "First<br>\nSecond",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(linkify(tt.text)); got != tt.want {
+				t.Errorf("linkify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageLinks(t *testing.T) {
+	message := servicelog.Message{
+		Description: "Read https://access.redhat.com/solutions/1, then (https://docs.openshift.com/rosa/index.html).",
+		DocReferences: []string{
+			"https://docs.openshift.com/rosa/index.html",
+			"https://access.redhat.com/solutions/2",
+		},
+	}
+	want := []string{
+		"https://access.redhat.com/solutions/1",
+		"https://docs.openshift.com/rosa/index.html",
+		"https://access.redhat.com/solutions/2",
+	}
+	if got := messageLinks(message); !reflect.DeepEqual(got, want) {
+		t.Errorf("messageLinks() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,302 @@
+package servicelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	ctUtil "github.com/openshift/osdctl/cmd/cloudtrail/pkg"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const (
+	// statsSubscriptionsPageSize is the number of subscriptions fetched per request
+	statsSubscriptionsPageSize = 100
+	// statsClusterBatchSize is the number of clusters whose service logs are searched at once, bounding the length
+	// of the search
+	statsClusterBatchSize = 50
+)
+
+type statsOptions struct {
+	orgID       string
+	since       string
+	allMessages bool
+	top         int
+	output      string
+}
+
+// serviceLogStats are the service logs sent to the clusters of an organization, counted by template, severity and
+// cluster
+type serviceLogStats struct {
+	OrgID    string    `json:"org_id"`
+	Since    time.Time `json:"since"`
+	Clusters int       `json:"clusters"`
+	Total    int       `json:"total"`
+	Errors   int       `json:"errors"`
+	// ByTemplate is keyed by the summary of the service logs, which is the title of the template they were sent from
+	ByTemplate []statsCount `json:"by_template"`
+	BySeverity []statsCount `json:"by_severity"`
+	// ByCluster is sorted by the number of error notifications first
+	ByCluster []statsCount `json:"by_cluster"`
+}
+
+// statsCount is the number of service logs of a template, severity or cluster, and how many are errors
+type statsCount struct {
+	Name   string `json:"name"`
+	Count  int    `json:"count"`
+	Errors int    `json:"errors"`
+}
+
+// statsEntry is the part of a service log the statistics are computed from
+type statsEntry struct {
+	ClusterUUID string
+	Summary     string
+	Severity    string
+}
+
+func newStatsCmd() *cobra.Command {
+	ops := &statsOptions{}
+	statsCmd := &cobra.Command{
+		Use:   "stats --org <org-id>",
+		Short: "Summarize the service logs sent to the clusters of an organization",
+		Long: `Summarize the service logs sent to the active managed clusters of an organization by template, severity
+and cluster, to find which clusters receive the most error notifications and which templates dominate.
+
+The template of a service log is identified by its summary. Only the service logs sent by SREs are counted, unless
+--all-messages is given.`,
+		Example: `  # Service logs sent to the clusters of an organization in the last 90 days
+  osdctl servicelog stats --org <org-id> --since 90d`,
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.CheckErr(ops.validate())
+			cmdutil.CheckErr(ops.run())
+		},
+	}
+
+	statsCmd.Flags().StringVar(&ops.orgID, "org", "", "ID of the organization")
+	statsCmd.Flags().StringVar(&ops.since, "since", "90d", "Only count the service logs sent in this duration, e.g. 90d or 720h")
+	statsCmd.Flags().BoolVarP(&ops.allMessages, AllMessagesFlag, AllMessagesShortFlag, false, "Count all the service logs, not only the ones sent by SREs")
+	statsCmd.Flags().IntVar(&ops.top, "top", 10, "Number of templates and clusters listed, 0 for all of them")
+	statsCmd.Flags().StringVarP(&ops.output, "output", "o", "text", "Output format, one of text or json")
+	_ = statsCmd.MarkFlagRequired("org")
+
+	return statsCmd
+}
+
+func (o *statsOptions) validate() error {
+	if o.output != "text" && o.output != "json" {
+		return fmt.Errorf("unknown output format '%s', expected text or json", o.output)
+	}
+	if o.top < 0 {
+		return fmt.Errorf("--top can't be negative")
+	}
+	return nil
+}
+
+func (o *statsOptions) run() error {
+	duration, err := ctUtil.ParseDuration(o.since)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	since := time.Now().Add(-duration).UTC()
+
+	ocmClient, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer ocmClient.Close()
+
+	clusterNames, err := fetchOrgClusterNames(ocmClient, o.orgID)
+	if err != nil {
+		return err
+	}
+	entries, err := fetchOrgServiceLogs(ocmClient, clusterNames, o.allMessages, since)
+	if err != nil {
+		return err
+	}
+
+	stats := summarizeServiceLogs(entries, clusterNames)
+	stats.OrgID, stats.Since = o.orgID, since
+	if o.output == "json" {
+		out, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	writeServiceLogStats(os.Stdout, stats, o.top)
+	return nil
+}
+
+// fetchOrgClusterNames returns the names of the active managed clusters of the organization, by external ID
+func fetchOrgClusterNames(ocmClient *sdk.Connection, orgID string) (map[string]string, error) {
+	clusterNames := map[string]string{}
+	request := ocmClient.AccountsMgmt().V1().Subscriptions().List().
+		Search(fmt.Sprintf("organization_id='%s' and status='Active' and managed=true", orgID)).
+		Size(statsSubscriptionsPageSize)
+	for page := 1; ; page++ {
+		response, err := request.Page(page).Send()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the clusters of organization %s: %w", orgID, err)
+		}
+		for _, subscription := range response.Items().Slice() {
+			if subscription.ExternalClusterID() == "" {
+				continue
+			}
+			clusterNames[subscription.ExternalClusterID()] = fmt.Sprintf("%s (%s)", subscription.DisplayName(), subscription.ClusterID())
+		}
+		if response.Size() < statsSubscriptionsPageSize {
+			break
+		}
+	}
+	if len(clusterNames) == 0 {
+		return nil, fmt.Errorf("organization %s has no active managed cluster", orgID)
+	}
+	return clusterNames, nil
+}
+
+// fetchOrgServiceLogs returns the service logs sent to the clusters since the given time, searching a batch of
+// clusters at a time
+func fetchOrgServiceLogs(ocmClient *sdk.Connection, clusterNames map[string]string, allMessages bool, since time.Time) ([]statsEntry, error) {
+	uuids := make([]string, 0, len(clusterNames))
+	for uuid := range clusterNames {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+
+	var entries []statsEntry
+	for start := 0; start < len(uuids); start += statsClusterBatchSize {
+		end := start + statsClusterBatchSize
+		if end > len(uuids) {
+			end = len(uuids)
+		}
+		search := fmt.Sprintf("cluster_uuid in ('%s') and %s", strings.Join(uuids[start:end], "','"), serviceLogsSearch(allMessages, false, since))
+		for page := 1; ; page++ {
+			response, err := ocmClient.ServiceLogs().V1().ClusterLogs().List().
+				Search(search).
+				Page(page).
+				Size(serviceLogsPageSize).
+				Send()
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch service logs: %w", err)
+			}
+			response.Items().Each(func(entry *slv1.LogEntry) bool {
+				entries = append(entries, statsEntry{ClusterUUID: entry.ClusterUUID(), Summary: entry.Summary(), Severity: string(entry.Severity())})
+				return true
+			})
+			if response.Size() < serviceLogsPageSize {
+				break
+			}
+		}
+	}
+	return entries, nil
+}
+
+// isErrorSeverity returns true for the severities of the service logs notifying the customer of an error
+func isErrorSeverity(severity string) bool {
+	switch severity {
+	case string(slv1.SeverityError), string(slv1.SeverityFatal), "Major", "Critical":
+		return true
+	}
+	return false
+}
+
+// summarizeServiceLogs counts the service logs by template, severity and cluster. Every cluster of the organization
+// is listed, including the ones which received no service log.
+func summarizeServiceLogs(entries []statsEntry, clusterNames map[string]string) *serviceLogStats {
+	stats := &serviceLogStats{Clusters: len(clusterNames), Total: len(entries)}
+	byTemplate := map[string]*statsCount{}
+	bySeverity := map[string]*statsCount{}
+	byCluster := map[string]*statsCount{}
+	for uuid, name := range clusterNames {
+		byCluster[uuid] = &statsCount{Name: name}
+	}
+
+	count := func(counts map[string]*statsCount, key string, name string, isError bool) {
+		if counts[key] == nil {
+			counts[key] = &statsCount{Name: name}
+		}
+		counts[key].Count++
+		if isError {
+			counts[key].Errors++
+		}
+	}
+	for _, entry := range entries {
+		isError := isErrorSeverity(entry.Severity)
+		if isError {
+			stats.Errors++
+		}
+		count(byTemplate, entry.Summary, entry.Summary, isError)
+		count(bySeverity, entry.Severity, entry.Severity, isError)
+		clusterName := clusterNames[entry.ClusterUUID]
+		if clusterName == "" {
+			clusterName = entry.ClusterUUID
+		}
+		count(byCluster, entry.ClusterUUID, clusterName, isError)
+	}
+
+	stats.ByTemplate = sortedStatsCounts(byTemplate, false)
+	stats.BySeverity = sortedStatsCounts(bySeverity, false)
+	stats.ByCluster = sortedStatsCounts(byCluster, true)
+	return stats
+}
+
+// sortedStatsCounts returns the counts the largest first, the ones with the most errors first when errorsFirst
+func sortedStatsCounts(counts map[string]*statsCount, errorsFirst bool) []statsCount {
+	sorted := make([]statsCount, 0, len(counts))
+	for _, count := range counts {
+		sorted = append(sorted, *count)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if errorsFirst && a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+	return sorted
+}
+
+// writeServiceLogStats prints the statistics, the top templates and clusters only unless top is 0
+func writeServiceLogStats(w io.Writer, stats *serviceLogStats, top int) {
+	fmt.Fprintf(w, "%d service logs (%d errors) sent to the %d clusters of organization %s since %s\n\n",
+		stats.Total, stats.Errors, stats.Clusters, stats.OrgID, stats.Since.Format(time.RFC3339))
+
+	writeStatsTable(w, "SEVERITY", stats.BySeverity, 0)
+	writeStatsTable(w, "TEMPLATE", stats.ByTemplate, top)
+	writeStatsTable(w, "CLUSTER", stats.ByCluster, top)
+}
+
+func writeStatsTable(w io.Writer, name string, counts []statsCount, top int) {
+	shown := counts
+	if top > 0 && len(shown) > top {
+		shown = shown[:top]
+	}
+	table := printer.NewTablePrinter(w, 20, 1, 3, ' ')
+	table.AddRow([]string{name, "COUNT", "ERRORS"})
+	for _, count := range shown {
+		table.AddRow([]string{count.Name, fmt.Sprint(count.Count), fmt.Sprint(count.Errors)})
+	}
+	if len(shown) < len(counts) {
+		table.AddRow([]string{fmt.Sprintf("... %d more, see --top", len(counts)-len(shown))})
+	}
+	// Add empty row for readability
+	table.AddRow([]string{})
+	if err := table.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing the service logs by %s: %v\n", strings.ToLower(name), err)
+	}
+}
@@ -0,0 +1,71 @@
+package servicelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeServiceLogs(t *testing.T) {
+	clusterNames := map[string]string{
+		"uuid-1": "prod (id-1)",
+		"uuid-2": "stage (id-2)",
+		"uuid-3": "dev (id-3)",
+	}
+	blocked := "Action required: review blocked egress"
+	upgrade := "Cluster upgrade scheduled"
+
+	tests := []struct {
+		name    string
+		entries []statsEntry
+		want    *serviceLogStats
+	}{
+		{
+			name: "no service logs",
+			want: &serviceLogStats{
+				Clusters:   3,
+				ByTemplate: []statsCount{},
+				BySeverity: []statsCount{},
+				ByCluster:  []statsCount{{Name: "dev (id-3)"}, {Name: "prod (id-1)"}, {Name: "stage (id-2)"}},
+			},
+		},
+		{
+			name: "clusters with the most errors first",
+			entries: []statsEntry{
+				{ClusterUUID: "uuid-1", Summary: upgrade, Severity: "Info"},
+				{ClusterUUID: "uuid-1", Summary: upgrade, Severity: "Info"},
+				{ClusterUUID: "uuid-1", Summary: upgrade, Severity: "Info"},
+				{ClusterUUID: "uuid-2", Summary: blocked, Severity: "Error"},
+				{ClusterUUID: "uuid-2", Summary: blocked, Severity: "Critical"},
+				{ClusterUUID: "uuid-gone", Summary: blocked, Severity: "Warning"},
+			},
+			want: &serviceLogStats{
+				Clusters: 3,
+				Total:    6,
+				Errors:   2,
+				ByTemplate: []statsCount{
+					{Name: blocked, Count: 3, Errors: 2},
+					{Name: upgrade, Count: 3},
+				},
+				BySeverity: []statsCount{
+					{Name: "Info", Count: 3},
+					{Name: "Critical", Count: 1, Errors: 1},
+					{Name: "Error", Count: 1, Errors: 1},
+					{Name: "Warning", Count: 1},
+				},
+				ByCluster: []statsCount{
+					{Name: "stage (id-2)", Count: 2, Errors: 2},
+					{Name: "prod (id-1)", Count: 3},
+					{Name: "uuid-gone", Count: 1},
+					{Name: "dev (id-3)"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeServiceLogs(tt.entries, clusterNames); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("summarizeServiceLogs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
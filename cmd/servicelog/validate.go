@@ -0,0 +1,175 @@
+package servicelog
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openshift/osdctl/internal/servicelog"
+	"github.com/spf13/viper"
+	"k8s.io/utils/strings/slices"
+)
+
+const (
+	// DictionaryConfigKey is the word list the customer-visible service logs are spell checked with, e.g.
+	// /usr/share/dict/words. The spell check is skipped when it's not set.
+	DictionaryConfigKey = "servicelog_dictionary"
+	// AllowedWordsConfigKey lists the words accepted by the spell check on top of the dictionary
+	AllowedWordsConfigKey = "servicelog_allowed_words"
+)
+
+// severities are the severities the service logs API accepts, from the least to the most severe
+var severities = []string{"Debug", "Info", "Warning", "Error", "Fatal", "Major", "Critical"}
+
+var (
+	placeholderRegex = regexp.MustCompile(`\${[^{}]*}`)
+	logTypeRegex     = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+	// codeRegex matches the inline code of a service log, which isn't spell checked
+	codeRegex = regexp.MustCompile("`[^`]*`")
+	wordRegex = regexp.MustCompile(`[A-Za-z]+(?:'[A-Za-z]+)*`)
+	// actionRequiredRegex matches the summaries asking the customer to act, which need a severity of
+	// Warning or more to stand out
+	actionRequiredRegex = regexp.MustCompile(`(?i)\baction required\b`)
+)
+
+// builtinWords are the product names and terms the dictionaries don't know
+var builtinWords = []string{
+	"openshift", "kubernetes", "hypershift", "rosa", "osd", "ocm", "aws", "gcp", "sts", "etcd", "kubelet",
+	"kubeconfig", "namespace", "namespaces", "ingress", "egress", "api", "apis", "url", "urls", "vpc", "vpcs",
+	"subnet", "subnets", "iam", "sre", "sres", "srep", "proxy", "webhook", "webhooks", "config", "configs",
+}
+
+// contentIssue is a problem found in the content of a service log
+type contentIssue struct {
+	check   string
+	message string
+}
+
+// contentCheckOptions selects the checks of the service log content which need more than the message
+type contentCheckOptions struct {
+	// linkClient requests the links of the message, they are not checked when it's nil
+	linkClient *http.Client
+	// dictionary is the set of known lowercase words, the message isn't spell checked when it's empty
+	dictionary map[string]bool
+	// excludes are the placeholders substituted later, e.g. ${CLUSTER_UUID}
+	excludes []string
+}
+
+// validateContent returns the issues found in the content of a customer-visible service log
+func validateContent(message servicelog.Message, options contentCheckOptions) []contentIssue {
+	var issues []contentIssue
+	issues = append(issues, checkPlaceholders(message, options.excludes)...)
+	issues = append(issues, checkSeverity(message)...)
+	if options.linkClient != nil {
+		for _, link := range messageLinks(message) {
+			if status := checkLink(options.linkClient, link); !status.ok() {
+				issues = append(issues, contentIssue{check: "link", message: fmt.Sprintf("%s: %s", link, status)})
+			}
+		}
+	}
+	if len(options.dictionary) > 0 {
+		for _, word := range misspelledWords(message, options.dictionary) {
+			issues = append(issues, contentIssue{check: "spelling", message: fmt.Sprintf("unknown word '%s'", word)})
+		}
+	}
+	return issues
+}
+
+// checkPlaceholders returns the placeholders left in any field of the message, the doc references included
+func checkPlaceholders(message servicelog.Message, excludes []string) []contentIssue {
+	fields := map[string]string{
+		"severity":        message.Severity,
+		"log_type":        message.LogType,
+		"service_name":    message.ServiceName,
+		"summary":         message.Summary,
+		"description":     message.Description,
+		"event_stream_id": message.EventStreamID,
+		"doc_references":  strings.Join(message.DocReferences, " "),
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []contentIssue
+	for _, name := range names {
+		for _, placeholder := range placeholderRegex.FindAllString(fields[name], -1) {
+			if !slices.Contains(excludes, placeholder) {
+				issues = append(issues, contentIssue{check: "placeholder", message: fmt.Sprintf("%s is left in the %s", placeholder, name)})
+			}
+		}
+	}
+	return issues
+}
+
+// checkSeverity returns the issues with the severity and the log type, and between them and the summary
+func checkSeverity(message servicelog.Message) []contentIssue {
+	var issues []contentIssue
+	rank := -1
+	for i, severity := range severities {
+		if message.Severity == severity {
+			rank = i
+		}
+	}
+	if rank < 0 {
+		issues = append(issues, contentIssue{check: "severity", message: fmt.Sprintf("unknown severity '%s', expected one of %v", message.Severity, severities)})
+	} else if actionRequiredRegex.MatchString(message.Summary) && rank < 2 {
+		issues = append(issues, contentIssue{check: "severity", message: fmt.Sprintf("the summary asks the customer to act but the severity is %s, expected Warning or more", message.Severity)})
+	}
+	if message.LogType != "" && !logTypeRegex.MatchString(message.LogType) {
+		issues = append(issues, contentIssue{check: "log type", message: fmt.Sprintf("malformed log type '%s', expected lowercase words separated by dashes, e.g. cluster-networking", message.LogType)})
+	}
+	return issues
+}
+
+// misspelledWords returns the words of the summary and the description missing from the dictionary, in
+// the order they appear. The links, placeholders, inline code, acronyms and camel-cased names are skipped.
+func misspelledWords(message servicelog.Message, dictionary map[string]bool) []string {
+	text := message.Summary + "\n" + message.Description
+	text = linkRegex.ReplaceAllString(text, " ")
+	text = placeholderRegex.ReplaceAllString(text, " ")
+	text = codeRegex.ReplaceAllString(text, " ")
+
+	var unknown []string
+	for _, word := range wordRegex.FindAllString(text, -1) {
+		word = strings.TrimSuffix(word, "'s")
+		if len(word) < 2 || strings.ToLower(word[1:]) != word[1:] {
+			continue
+		}
+		lower := strings.ToLower(word)
+		if dictionary[lower] || slices.Contains(unknown, word) {
+			continue
+		}
+		unknown = append(unknown, word)
+	}
+	return unknown
+}
+
+// loadDictionary reads the word list, one word per line, with the built-in and configured words
+func loadDictionary(path string) (map[string]bool, error) {
+	file, err := os.Open(path) //#nosec G304 -- the dictionary is chosen by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the dictionary: %w", err)
+	}
+	defer file.Close()
+
+	dictionary := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			dictionary[strings.ToLower(word)] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the dictionary: %w", err)
+	}
+	for _, word := range append(builtinWords, viper.GetStringSlice(AllowedWordsConfigKey)...) {
+		dictionary[strings.ToLower(word)] = true
+	}
+	return dictionary, nil
+}
@@ -0,0 +1,75 @@
+package servicelog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/osdctl/internal/servicelog"
+)
+
+func TestValidateContent(t *testing.T) {
+	dictionary := map[string]bool{"your": true, "cluster": true, "is": true, "ready": true, "see": true, "the": true, "and": true, "action": true, "required": true, "upgrade": true}
+
+	tests := []struct {
+		name    string
+		message servicelog.Message
+		want    []contentIssue
+	}{
+		{
+			name: "valid message",
+			message: servicelog.Message{
+				Severity:    "Info",
+				LogType:     "cluster-state-updates",
+				Summary:     "Your cluster is ready",
+				Description: "Your cluster ${CLUSTER_UUID} is ready, see `oc get nodes` and https://docs.openshift.com/rosa/index.html.",
+			},
+		},
+		{
+			name: "placeholders left",
+			message: servicelog.Message{
+				Severity:      "Info",
+				Summary:       "Your cluster is ready",
+				Description:   "See ${DOC_LINK}",
+				DocReferences: []string{"https://access.redhat.com/solutions/${KCS}"},
+			},
+			want: []contentIssue{
+				{check: "placeholder", message: "${DOC_LINK} is left in the description"},
+				{check: "placeholder", message: "${KCS} is left in the doc_references"},
+			},
+		},
+		{
+			name: "inconsistent severity and log type",
+			message: servicelog.Message{
+				Severity: "Info",
+				LogType:  "Cluster Networking",
+				Summary:  "Action required: upgrade your cluster",
+			},
+			want: []contentIssue{
+				{check: "severity", message: "the summary asks the customer to act but the severity is Info, expected Warning or more"},
+				{check: "log type", message: "malformed log type 'Cluster Networking', expected lowercase words separated by dashes, e.g. cluster-networking"},
+			},
+		},
+		{
+			name: "unknown severity and misspelled words",
+			message: servicelog.Message{
+				Severity:    "warning",
+				Summary:     "Your clustr is ready",
+				Description: "Your clustr is redy, see the OCM UI and the MachinePool.",
+			},
+			want: []contentIssue{
+				{check: "severity", message: "unknown severity 'warning', expected one of [Debug Info Warning Error Fatal Major Critical]"},
+				{check: "spelling", message: "unknown word 'clustr'"},
+				{check: "spelling", message: "unknown word 'redy'"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateContent(tt.message, contentCheckOptions{dictionary: dictionary, excludes: []string{clusterUUIDParameter}})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateContent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/openshift/osdctl/pkg/osdctlConfig"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -15,6 +16,7 @@ import (
 const (
 	ProdJumproleConfigKey   = "prod_jumprole_account_id"
 	AwsProxy                = "aws_proxy"
+	AwsProfile              = "aws_profile"
 	StageJumproleConfigKey  = "stage_jumprole_account_id"
 	PdUserToken             = "pd_user_token"
 	JiraToken               = "jira_token"
@@ -32,11 +34,29 @@ const (
 
 // NewCmdSetup implements the setup command
 func NewCmdSetup() *cobra.Command {
+	var runWizard bool
 	setupCmd := &cobra.Command{
 		Use:   "setup",
 		Short: "Setup the configuration",
-		Args:  cobra.NoArgs,
+		Long: `Setup the configuration.
+
+On the first run, or with --wizard, an interactive wizard walks through the OCM login, the creation of the
+PagerDuty and Jira tokens, and the AWS profile selection, checking each value works before saving it.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if runWizard || isFirstRun() {
+				settings := newWizard().run()
+				if len(settings) == 0 {
+					fmt.Println("\nNothing to save")
+					return nil
+				}
+				if err := osdctlConfig.WriteUserConfig(settings); err != nil {
+					return err
+				}
+				fmt.Println("\nConfiguration saved successfully")
+				return nil
+			}
+
 			keys := []string{
 				ProdJumproleConfigKey,
 				AwsProxy,
@@ -129,11 +149,12 @@ func NewCmdSetup() *cobra.Command {
 				}
 			}
 
-			// Store the value in the config file
+			// Store the value in the config file, without copying the team defaults into it
+			settings := make(map[string]interface{}, len(values))
 			for key, value := range values {
-				viper.Set(key, value)
+				settings[key] = value
 			}
-			err := viper.WriteConfig()
+			err := osdctlConfig.WriteUserConfig(settings)
 			if err != nil {
 				return err
 			}
@@ -142,9 +163,16 @@ func NewCmdSetup() *cobra.Command {
 			return nil
 		},
 	}
+	setupCmd.Flags().BoolVar(&runWizard, "wizard", false, "Run the interactive setup wizard")
 	return setupCmd
 }
 
+// isFirstRun returns true when the user config is empty
+func isFirstRun() bool {
+	settings, err := osdctlConfig.ReadUserConfig()
+	return err == nil && len(settings) == 0
+}
+
 func ValidateJiraToken(token string) (string, error) {
 	token = strings.TrimSpace(token)
 	match, err := regexp.MatchString(JiraTokenRegex, token)
@@ -0,0 +1,223 @@
+package setup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// maxAttempts is how many times the wizard asks for a value failing validation before skipping it
+const maxAttempts = 3
+
+// wizard walks new users through the configuration osdctl needs, checking each value works
+type wizard struct {
+	in     *bufio.Reader
+	out    io.Writer
+	values map[string]interface{}
+
+	// The interactions with the outside world, replaced in tests
+	openURL        func(url string) error
+	checkOCM       func() (string, error)
+	ocmLogin       func() error
+	checkPagerDuty func(token string) error
+	checkJira      func(token string) error
+	awsProfiles    func() ([]string, error)
+	checkAWS       func(profile string) (string, error)
+}
+
+// run goes through the steps and returns the values to store in the config
+func (w *wizard) run() map[string]interface{} {
+	w.values = map[string]interface{}{}
+
+	fmt.Fprintln(w.out, "This wizard sets up osdctl. Press enter to keep the value in brackets.")
+
+	w.step("OCM")
+	w.ocmStep()
+
+	w.step("PagerDuty")
+	w.tokenStep("PagerDuty user token", PdUserToken, pagerDutyTokenRegistrationURL, w.checkPagerDuty)
+
+	w.step("Jira")
+	w.tokenStep("Jira personal access token", JiraToken, jiraTokenRegistrationURL, w.checkJira)
+
+	w.step("AWS")
+	w.awsProfileStep()
+	w.valueStep(ProdJumproleConfigKey, ValidateAWSAccount)
+	w.valueStep(StageJumproleConfigKey, ValidateAWSAccount)
+	w.valueStep(AwsProxy, ValidateAWSProxy)
+
+	return w.values
+}
+
+func (w *wizard) step(name string) {
+	fmt.Fprintf(w.out, "\n== %s ==\n", name)
+}
+
+func (w *wizard) prompt(question string, defaultValue string) string {
+	if defaultValue != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	value, _ := w.in.ReadString('\n')
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func (w *wizard) confirm(question string) bool {
+	answer := strings.ToLower(w.prompt(question+" [Y/n]", ""))
+	return answer == "" || answer == "y" || answer == "yes"
+}
+
+func (w *wizard) ocmStep() {
+	user, err := w.checkOCM()
+	if err == nil {
+		fmt.Fprintf(w.out, "Logged into OCM as %s\n", user)
+		return
+	}
+	fmt.Fprintf(w.out, "Not logged into OCM: %v\n", err)
+	if !w.confirm("Log in now with 'ocm login --use-auth-code'?") {
+		fmt.Fprintln(w.out, "Skipping, most commands need an OCM login")
+		return
+	}
+	if err := w.ocmLogin(); err != nil {
+		fmt.Fprintf(w.out, "OCM login failed: %v\n", err)
+		return
+	}
+	if user, err = w.checkOCM(); err != nil {
+		fmt.Fprintf(w.out, "Still not logged into OCM: %v\n", err)
+		return
+	}
+	fmt.Fprintf(w.out, "Logged into OCM as %s\n", user)
+}
+
+// tokenStep keeps the configured token if it works, otherwise offers to open the page creating one and
+// checks the token entered against the service
+func (w *wizard) tokenStep(name string, key string, registrationURL string, check func(string) error) {
+	if current := viper.GetString(key); current != "" {
+		err := check(current)
+		if err == nil {
+			fmt.Fprintf(w.out, "The configured %s works\n", name)
+			return
+		}
+		fmt.Fprintf(w.out, "The configured %s doesn't work: %v\n", name, err)
+	}
+
+	if w.confirm(fmt.Sprintf("Open %s to create a %s?", registrationURL, name)) {
+		if err := w.openURL(registrationURL); err != nil {
+			fmt.Fprintf(w.out, "Failed to open the browser, visit %s: %v\n", registrationURL, err)
+		}
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		token := w.prompt(fmt.Sprintf("%s (empty to skip)", name), "")
+		if token == "" {
+			fmt.Fprintf(w.out, "Skipping the %s\n", name)
+			return
+		}
+		if err := check(token); err != nil {
+			fmt.Fprintf(w.out, "The %s doesn't work: %v\n", name, err)
+			continue
+		}
+		w.values[key] = token
+		return
+	}
+	fmt.Fprintf(w.out, "Skipping the %s after %d attempts\n", name, maxAttempts)
+}
+
+func (w *wizard) awsProfileStep() {
+	profiles, err := w.awsProfiles()
+	if err != nil || len(profiles) == 0 {
+		fmt.Fprintf(w.out, "No AWS profile found, configure one with 'aws configure' and run 'osdctl setup --wizard' again\n")
+		return
+	}
+
+	current := viper.GetString(AwsProfile)
+	for i, profile := range profiles {
+		fmt.Fprintf(w.out, "%3d) %s\n", i+1, profile)
+		if current == "" && profile == "default" {
+			current = profile
+		}
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		answer := w.prompt("AWS profile to use (number or name)", current)
+		profile := answer
+		if index, err := strconv.Atoi(answer); err == nil && index >= 1 && index <= len(profiles) {
+			profile = profiles[index-1]
+		}
+		if !contains(profiles, profile) {
+			fmt.Fprintf(w.out, "Unknown AWS profile '%s'\n", answer)
+			continue
+		}
+
+		if identity, err := w.checkAWS(profile); err != nil {
+			// The profile can require a login first, e.g. with SSO, so keep it anyway
+			fmt.Fprintf(w.out, "Warning: the credentials of profile %s don't work yet: %v\n", profile, err)
+		} else {
+			fmt.Fprintf(w.out, "Profile %s authenticates as %s\n", profile, identity)
+		}
+		if profile != viper.GetString(AwsProfile) {
+			w.values[AwsProfile] = profile
+		}
+		return
+	}
+}
+
+// valueStep asks for a value, until it passes the validation
+func (w *wizard) valueStep(key string, validate func(string) (string, error)) {
+	current := viper.GetString(key)
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		value := w.prompt(key, current)
+		if value == "" || value == current {
+			return
+		}
+		value, err := validate(value)
+		if err != nil {
+			fmt.Fprintf(w.out, "%v\n", err)
+			continue
+		}
+		w.values[key] = value
+		return
+	}
+	fmt.Fprintf(w.out, "Skipping %s after %d attempts\n", key, maxAttempts)
+}
+
+// parseAWSProfiles returns the profiles defined in an AWS config or credentials file. The sections of the
+// config file are named "profile <name>", except for the default profile.
+func parseAWSProfiles(content string, configFile bool) []string {
+	var profiles []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+			continue
+		}
+		section := strings.TrimSpace(line[1 : len(line)-1])
+		if configFile {
+			if name, found := strings.CutPrefix(section, "profile "); found {
+				section = strings.TrimSpace(name)
+			} else if section != "default" {
+				// e.g. [sso-session ...]
+				continue
+			}
+		}
+		profiles = append(profiles, section)
+	}
+	return profiles
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package setup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/openshift/osdctl/cmd/cluster"
+	"github.com/openshift/osdctl/pkg/provider/aws"
+	"github.com/openshift/osdctl/pkg/provider/pagerduty"
+	"github.com/openshift/osdctl/pkg/utils"
+	"github.com/pkg/browser"
+)
+
+var (
+	pagerDutyTokenRegistrationURL = cluster.PagerDutyTokenRegistrationUrl
+	jiraTokenRegistrationURL      = cluster.JiraBaseURL + cluster.JiraTokenRegistrationPath
+)
+
+// newWizard returns a wizard talking to the real services
+func newWizard() *wizard {
+	return &wizard{
+		in:             bufio.NewReader(os.Stdin),
+		out:            os.Stdout,
+		openURL:        browser.OpenURL,
+		checkOCM:       currentOCMUser,
+		ocmLogin:       ocmLogin,
+		checkPagerDuty: checkPagerDutyToken,
+		checkJira:      checkJiraToken,
+		awsProfiles:    localAWSProfiles,
+		checkAWS:       awsIdentity,
+	}
+}
+
+func currentOCMUser() (string, error) {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return "", err
+	}
+	defer connection.Close()
+
+	response, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		return "", err
+	}
+	return response.Body().Username(), nil
+}
+
+func ocmLogin() error {
+	cmd := exec.Command("ocm", "login", "--use-auth-code")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func checkPagerDutyToken(token string) error {
+	if _, err := ValidatePDToken(token); err != nil {
+		return err
+	}
+	client, err := pagerduty.NewClient().WithUserToken(token).Init()
+	if err != nil {
+		return err
+	}
+	_, err = client.GetPDServiceIDs()
+	return err
+}
+
+func checkJiraToken(token string) error {
+	client, err := utils.NewJiraClient(token)
+	if err != nil {
+		return err
+	}
+	_, _, err = client.User.GetSelf()
+	return err
+}
+
+// localAWSProfiles returns the profiles of the AWS config and credentials files
+func localAWSProfiles() ([]string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = filepath.Join(home, ".aws", "config")
+	}
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = filepath.Join(home, ".aws", "credentials")
+	}
+
+	unique := map[string]bool{}
+	for file, isConfig := range map[string]bool{configFile: true, credentialsFile: false} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, profile := range parseAWSProfiles(string(content), isConfig) {
+			unique[profile] = true
+		}
+	}
+
+	profiles := make([]string, 0, len(unique))
+	for profile := range unique {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+func awsIdentity(profile string) (string, error) {
+	cfg, err := aws.NewAwsConfig(profile, "us-east-1", "")
+	if err != nil {
+		return "", err
+	}
+	identity, err := sts.NewFromConfig(*cfg).GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("error getting caller identity: %w", err)
+	}
+	return *identity.Arn, nil
+}
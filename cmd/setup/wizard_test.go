@@ -0,0 +1,118 @@
+package setup
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+)
+
+const validPDToken = "abcdEFGHijklMNOPqrst"
+
+func newTestWizard(input string) (*wizard, *[]string) {
+	var opened []string
+	return &wizard{
+		in:  bufio.NewReader(strings.NewReader(input)),
+		out: io.Discard,
+		openURL: func(url string) error {
+			opened = append(opened, url)
+			return nil
+		},
+		checkOCM: func() (string, error) { return "jdoe", nil },
+		ocmLogin: func() error { return nil },
+		checkPagerDuty: func(token string) error {
+			if token != validPDToken {
+				return errors.New("unauthorized")
+			}
+			return nil
+		},
+		checkJira: func(token string) error {
+			if token != "jira-token" {
+				return errors.New("unauthorized")
+			}
+			return nil
+		},
+		awsProfiles: func() ([]string, error) { return []string{"default", "osd-staging"}, nil },
+		checkAWS:    func(profile string) (string, error) { return "arn:aws:iam::123456789012:user/jdoe", nil },
+	}, &opened
+}
+
+var _ = Describe("Setup wizard", func() {
+	BeforeEach(func() {
+		viper.Reset()
+	})
+	AfterEach(func() {
+		viper.Reset()
+	})
+
+	It("collects and validates the configuration", func() {
+		input := strings.Join([]string{
+			"y",          // open the PagerDuty registration page
+			"bad-token",  // rejected by PagerDuty
+			validPDToken, // accepted
+			"n",          // don't open the Jira registration page
+			"jira-token",
+			"2",            // AWS profile
+			"123",          // invalid prod jump role account
+			"123456789012", // prod jump role account
+			"",             // keep the stage jump role account
+			"http://squid.example.com:3128",
+		}, "\n") + "\n"
+
+		w, opened := newTestWizard(input)
+		values := w.run()
+
+		Expect(*opened).To(Equal([]string{pagerDutyTokenRegistrationURL}))
+		Expect(values).To(Equal(map[string]interface{}{
+			PdUserToken:           validPDToken,
+			JiraToken:             "jira-token",
+			AwsProfile:            "osd-staging",
+			ProdJumproleConfigKey: "123456789012",
+			AwsProxy:              "http://squid.example.com:3128",
+		}))
+	})
+
+	It("keeps the configured tokens which work", func() {
+		viper.Set(PdUserToken, validPDToken)
+		viper.Set(JiraToken, "jira-token")
+		viper.Set(AwsProfile, "default")
+
+		w, opened := newTestWizard("\n\n\n\n")
+		values := w.run()
+
+		Expect(*opened).To(BeEmpty())
+		Expect(values).To(BeEmpty())
+	})
+
+	It("logs into OCM when needed", func() {
+		w, _ := newTestWizard("y\n")
+		loggedIn := false
+		w.checkOCM = func() (string, error) {
+			if !loggedIn {
+				return "", errors.New("not logged in")
+			}
+			return "jdoe", nil
+		}
+		w.ocmLogin = func() error {
+			loggedIn = true
+			return nil
+		}
+		w.ocmStep()
+		Expect(loggedIn).To(BeTrue())
+	})
+})
+
+var _ = Describe("AWS profiles", func() {
+	It("parses the config file", func() {
+		content := "[default]\nregion = us-east-1\n[profile osd-staging]\nregion = us-east-1\n[sso-session rh]\nsso_region = us-east-1\n"
+		Expect(parseAWSProfiles(content, true)).To(Equal([]string{"default", "osd-staging"}))
+	})
+	It("parses the credentials file", func() {
+		content := "[default]\naws_access_key_id = a\n[osd-prod]\naws_access_key_id = b\n"
+		Expect(parseAWSProfiles(content, false)).To(Equal([]string{"default", "osd-prod"}))
+	})
+})
@@ -50,18 +50,19 @@ var secondaryCmd = &cobra.Command{
 		jql := buildJQL()
 
 		// Search jira issues
-		issues, _, err := jiraClient.Issue.Search(jql, nil)
+		// Every card of the queue is listed, not only the first page
+		result, err := utils.SearchJiraIssues(jiraClient.Issue, jql, nil, 0)
 
 		if err != nil {
 			return fmt.Errorf("error fetching JIRA issues: %w", err)
 		}
-		utils.PrintJiraIssues(issues)
+		utils.PrintJiraIssues(result.Issues)
 		return nil
 	},
 }
 
 func buildJQL() string {
-	builtjql := fmt.Sprintf("Project = \"%s\" AND Products in (%s)", DefaultProject, strings.Join(
+	builtjql := fmt.Sprintf("Project = \"%s\" AND Products in (%s)", utils.JiraProject(DefaultProject), strings.Join(
 		products,
 		",",
 	))
@@ -3,32 +3,66 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"runtime/debug"
 	"strings"
 
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift/osdctl/pkg/printer"
 	"github.com/openshift/osdctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
+// ocmSDKModule is the module of the OCM client osdctl is built with
+const ocmSDKModule = "github.com/openshift-online/ocm-sdk-go"
+
+// ocmAPI is an OCM API osdctl talks to, and the version of it osdctl is written against
+type ocmAPI struct {
+	Name    string
+	Version string
+}
+
+// ocmAPIs are the OCM APIs the commands of osdctl use
+var ocmAPIs = []ocmAPI{
+	{Name: "clusters_mgmt", Version: "v1"},
+	{Name: "accounts_mgmt", Version: "v1"},
+	{Name: "service_logs", Version: "v1"},
+	{Name: "osd_fleet_mgmt", Version: "v1"},
+}
+
 // versionResponse is necessary for the JSON version response. It uses the three
 // variables that get set during the build.
 type versionResponse struct {
 	Commit  string `json:"commit"`
 	Version string `json:"version"`
 	Latest  string `json:"latest"`
+	OCMSDK  string `json:"ocm_sdk,omitempty"`
 }
 
+// checkCompat checks the OCM APIs osdctl talks to are served in the versions it's written against
+var checkCompat bool
+
 // versionCmd is the subcommand "osdctl version" for cobra.
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Display the version",
-	Long:  "Display version of osdctl",
-	RunE:  version,
+	Long: `Display version of osdctl.
+
+With --check-compat, the OCM APIs osdctl talks to are checked to be served, by the current OCM environment, in
+the versions osdctl is written against. The command fails when one of them isn't, e.g. before rolling a new
+build out to automation.`,
+	RunE: version,
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&checkCompat, "check-compat", false, "Check the OCM APIs osdctl talks to are served in the versions it's written against")
 }
 
 // version returns the osdctl version marshalled in JSON
 func version(cmd *cobra.Command, args []string) error {
 	gitCommit := "unknown"
+	ocmSDK := ""
 
 	if info, ok := debug.ReadBuildInfo(); ok {
 		for _, setting := range info.Settings {
@@ -37,6 +71,11 @@ func version(cmd *cobra.Command, args []string) error {
 				break
 			}
 		}
+		for _, dep := range info.Deps {
+			if dep.Path == ocmSDKModule {
+				ocmSDK = dep.Version
+			}
+		}
 	}
 
 	latest, _ := utils.GetLatestVersion() // let's ignore this error, just in case we have no internet access
@@ -44,10 +83,117 @@ func version(cmd *cobra.Command, args []string) error {
 		Commit:  gitCommit,
 		Version: utils.Version,
 		Latest:  strings.TrimPrefix(latest, "v"),
+		OCMSDK:  ocmSDK,
 	}, "", "  ")
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(ver))
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(ver))
+
+	if !checkCompat {
+		return nil
+	}
+	return checkOCMCompatibility(cmd.OutOrStdout())
+}
+
+// apiCompatibility is the result of checking an OCM API
+type apiCompatibility struct {
+	ServerVersion string
+	Compatible    bool
+	Detail        string
+}
+
+// checkOCMCompatibility prints whether each OCM API osdctl talks to is served in the expected version, and
+// fails if one of them isn't
+func checkOCMCompatibility(out io.Writer) error {
+	connection, err := utils.CreateConnection()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	table := printer.NewTablePrinter(out, 20, 1, 3, ' ')
+	table.AddRow([]string{"API", "VERSION", "SERVER VERSION", "STATUS"})
+	var incompatible int
+	for _, api := range ocmAPIs {
+		result := checkAPI(connection, api)
+		status := "compatible"
+		if !result.Compatible {
+			status = "INCOMPATIBLE"
+			incompatible++
+		}
+		if result.Detail != "" {
+			status += ": " + result.Detail
+		}
+		table.AddRow([]string{api.Name, api.Version, result.ServerVersion, status})
+	}
+	_, _ = fmt.Fprintln(out)
+	if err := table.Flush(); err != nil {
+		return err
+	}
+	if incompatible > 0 {
+		return fmt.Errorf("%d of the %d OCM APIs osdctl talks to aren't served in the versions it's written against", incompatible, len(ocmAPIs))
+	}
 	return nil
 }
+
+// checkAPI checks the versions served for the API list the version osdctl uses, and gets the version of the
+// server behind it
+func checkAPI(connection *sdk.Connection, api ocmAPI) apiCompatibility {
+	result := apiCompatibility{}
+	response, err := connection.Get().Path("/api/" + api.Name).Send()
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	if response.Status() != http.StatusOK {
+		result.Detail = fmt.Sprintf("/api/%s returned %d", api.Name, response.Status())
+		return result
+	}
+	served, err := servedVersions(response.Bytes())
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	result.Compatible, result.Detail = evaluateCompatibility(api, served)
+
+	if response, err := connection.Get().Path(fmt.Sprintf("/api/%s/%s", api.Name, api.Version)).Send(); err == nil && response.Status() == http.StatusOK {
+		var metadata struct {
+			ServerVersion string `json:"server_version"`
+		}
+		if json.Unmarshal(response.Bytes(), &metadata) == nil {
+			result.ServerVersion = metadata.ServerVersion
+		}
+	}
+	return result
+}
+
+// servedVersions returns the versions listed by the metadata of an OCM API, e.g. GET /api/clusters_mgmt
+func servedVersions(body []byte) ([]string, error) {
+	var metadata struct {
+		Versions []struct {
+			ID string `json:"id"`
+		} `json:"versions"`
+	}
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse the API metadata: %w", err)
+	}
+	versions := make([]string, 0, len(metadata.Versions))
+	for _, version := range metadata.Versions {
+		versions = append(versions, version.ID)
+	}
+	return versions, nil
+}
+
+// evaluateCompatibility returns whether the version osdctl uses is served, and the detail of an incompatibility
+func evaluateCompatibility(api ocmAPI, served []string) (bool, string) {
+	for _, version := range served {
+		if version == api.Version {
+			return true, ""
+		}
+	}
+	if len(served) == 0 {
+		return false, "no version served"
+	}
+	return false, fmt.Sprintf("%s isn't served, only %s", api.Version, strings.Join(served, ", "))
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServedVersions(t *testing.T) {
+	versions, err := servedVersions([]byte(`{"kind":"API","id":"clusters_mgmt","href":"/api/clusters_mgmt","versions":[{"kind":"APIVersion","id":"v1","href":"/api/clusters_mgmt/v1"},{"kind":"APIVersion","id":"v2alpha1"}]}`))
+	if err != nil {
+		t.Fatalf("servedVersions() error = %v", err)
+	}
+	if want := []string{"v1", "v2alpha1"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("servedVersions() = %v, want %v", versions, want)
+	}
+
+	if _, err := servedVersions([]byte("<html>")); err == nil {
+		t.Errorf("servedVersions() expected an error for a response which isn't JSON")
+	}
+}
+
+func TestEvaluateCompatibility(t *testing.T) {
+	api := ocmAPI{Name: "clusters_mgmt", Version: "v1"}
+	tests := []struct {
+		name       string
+		served     []string
+		compatible bool
+		detail     string
+	}{
+		{
+			name:       "served",
+			served:     []string{"v1", "v2"},
+			compatible: true,
+		},
+		{
+			name:   "only newer versions",
+			served: []string{"v2"},
+			detail: "v1 isn't served, only v2",
+		},
+		{
+			name:   "nothing served",
+			detail: "no version served",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compatible, detail := evaluateCompatibility(api, tt.served)
+			if compatible != tt.compatible || detail != tt.detail {
+				t.Errorf("evaluateCompatibility() = %t, %q, want %t, %q", compatible, detail, tt.compatible, tt.detail)
+			}
+		})
+	}
+}
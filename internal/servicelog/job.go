@@ -0,0 +1,300 @@
+package servicelog
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JobStatus is the state of a bulk send as a whole
+type JobStatus string
+
+// TargetStatus is the state of the send to a single cluster of a job
+type TargetStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	// JobFailed jobs are done but some of their clusters failed, they can be resumed
+	JobFailed  JobStatus = "failed"
+	JobAborted JobStatus = "aborted"
+
+	TargetPending TargetStatus = "pending"
+	// TargetSending is recorded before the request is sent, so a send interrupted half-way is checked for
+	// the entry it may have created before being sent again
+	TargetSending TargetStatus = "sending"
+	TargetSent    TargetStatus = "sent"
+	TargetFailed  TargetStatus = "failed"
+
+	// JobRetention is how long the finished jobs are kept
+	JobRetention = 30 * 24 * time.Hour
+
+	jobsDirName = "servicelog-jobs"
+)
+
+// ErrJobNotFound is returned when no job has the requested ID
+var ErrJobNotFound = errors.New("job not found")
+
+// Job is a service log sent to a set of clusters, saved after every change so it can be resumed after
+// transient failures or an interruption
+type Job struct {
+	ID        string       `json:"id"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Status    JobStatus    `json:"status"`
+	Message   Message      `json:"message"`
+	Verify    bool         `json:"verify,omitempty"`
+	Targets   []*JobTarget `json:"targets"`
+}
+
+// JobTarget is a cluster of a job
+type JobTarget struct {
+	ClusterID      string `json:"cluster_id"`
+	ClusterUUID    string `json:"cluster_uuid"`
+	ClusterName    string `json:"cluster_name,omitempty"`
+	SubscriptionID string `json:"subscription_id,omitempty"`
+	// IdempotencyKey is sent as the event stream ID of the entry, the entry of a send whose outcome is
+	// unknown is looked up with it before sending again
+	IdempotencyKey string       `json:"idempotency_key"`
+	Status         TargetStatus `json:"status"`
+	Attempts       int          `json:"attempts"`
+	LastError      string       `json:"last_error,omitempty"`
+	EntryID        string       `json:"entry_id,omitempty"`
+	SentAt         time.Time    `json:"sent_at,omitempty"`
+}
+
+// NewJob returns a running job sending the message, without clusters
+func NewJob(message Message, now time.Time) (*Job, error) {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	return &Job{
+		ID:        now.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix),
+		CreatedAt: now.UTC(),
+		UpdatedAt: now.UTC(),
+		Status:    JobRunning,
+		Message:   message,
+	}, nil
+}
+
+// AddTarget adds a cluster to send the message to
+func (j *Job) AddTarget(clusterID string, clusterUUID string, clusterName string, subscriptionID string) {
+	j.Targets = append(j.Targets, &JobTarget{
+		ClusterID:      clusterID,
+		ClusterUUID:    clusterUUID,
+		ClusterName:    clusterName,
+		SubscriptionID: subscriptionID,
+		IdempotencyKey: IdempotencyKey(j.ID, clusterID),
+		Status:         TargetPending,
+	})
+}
+
+// IdempotencyKey identifies the send of a job to a cluster
+func IdempotencyKey(jobID string, clusterID string) string {
+	sum := sha256.Sum256([]byte(jobID + "/" + clusterID))
+	return "osdctl-" + hex.EncodeToString(sum[:12])
+}
+
+// MessageFor returns the message of the job addressed to the target. The event stream ID of the template
+// is kept when it has one, the idempotency key is only used when it's empty.
+func (j *Job) MessageFor(target *JobTarget) Message {
+	message := j.Message
+	message.ClusterID = target.ClusterID
+	message.ClusterUUID = target.ClusterUUID
+	message.SubscriptionID = target.SubscriptionID
+	if message.EventStreamID == "" {
+		message.EventStreamID = target.IdempotencyKey
+	}
+	return message
+}
+
+// Unsent returns the targets the message still has to be sent to
+func (j *Job) Unsent() []*JobTarget {
+	var targets []*JobTarget
+	for _, target := range j.Targets {
+		if target.Status != TargetSent {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// Counts returns the number of targets sent, failed and not attempted or interrupted
+func (j *Job) Counts() (sent int, failed int, pending int) {
+	for _, target := range j.Targets {
+		switch target.Status {
+		case TargetSent:
+			sent++
+		case TargetFailed:
+			failed++
+		default:
+			pending++
+		}
+	}
+	return sent, failed, pending
+}
+
+// Finish sets the status of the job once all its targets were processed. Aborted jobs stay aborted.
+func (j *Job) Finish() {
+	if j.Status == JobAborted {
+		return
+	}
+	if _, failed, pending := j.Counts(); failed+pending > 0 {
+		j.Status = JobFailed
+		return
+	}
+	j.Status = JobCompleted
+}
+
+// JobStore saves the jobs as a JSON file each in a directory. Saving is safe from concurrent senders.
+type JobStore struct {
+	Dir   string
+	mutex sync.Mutex
+}
+
+// DefaultJobStore returns the store in the osdctl cache directory
+func DefaultJobStore() (*JobStore, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &JobStore{Dir: filepath.Join(cacheDir, "osdctl", jobsDirName)}, nil
+}
+
+// Save writes the job. A job aborted from another osdctl process since it was loaded stays aborted, and
+// the status of the job is updated for the caller to stop sending.
+func (s *JobStore) Save(job *Job, now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if saved, err := s.load(job.ID); err == nil && saved.Status == JobAborted {
+		job.Status = JobAborted
+	}
+	job.UpdatedAt = now.UTC()
+	content, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	// Written aside and renamed, an interruption never leaves a truncated job behind
+	tmp := s.path(job.ID) + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(job.ID))
+}
+
+// Load reads a job by its ID, or by a prefix of its ID matching a single job
+func (s *JobStore) Load(id string) (*Job, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job, err := s.load(id)
+	if !errors.Is(err, ErrJobNotFound) {
+		return job, err
+	}
+	jobs, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	var matches []*Job
+	for _, job := range jobs {
+		if strings.HasPrefix(job.ID, id) {
+			matches = append(matches, job)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d jobs start with %s, use the full ID", len(matches), id)
+	}
+}
+
+// List returns the jobs, newest first. The files that can't be parsed are skipped.
+func (s *JobStore) List() ([]*Job, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.list()
+}
+
+// Prune deletes the finished jobs last updated longer than the retention ago
+func (s *JobStore) Prune(now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	jobs, err := s.list()
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Status == JobRunning || now.Sub(job.UpdatedAt) < JobRetention {
+			continue
+		}
+		if err := os.Remove(s.path(job.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JobStore) path(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
+
+func (s *JobStore) load(id string) (*Job, error) {
+	// IDs are file names, anything else can't be one
+	if id == "" || filepath.Base(id) != id {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	content, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(content, &job); err != nil {
+		return nil, fmt.Errorf("failed to parse job %s: %w", id, err)
+	}
+	return &job, nil
+}
+
+func (s *JobStore) list() ([]*Job, error) {
+	files, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*Job
+	for _, file := range files {
+		id, ok := strings.CutSuffix(file.Name(), ".json")
+		if !ok || file.IsDir() {
+			continue
+		}
+		job, err := s.load(id)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs, nil
+}
@@ -0,0 +1,126 @@
+package servicelog
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobMessageFor(t *testing.T) {
+	job := &Job{ID: "20240312-101500-a1b2c3", Message: Message{Summary: "Maintenance"}}
+	job.AddTarget("cluster-id", "cluster-uuid", "cluster-name", "subscription-id")
+	target := job.Targets[0]
+
+	message := job.MessageFor(target)
+	if message.ClusterID != "cluster-id" || message.ClusterUUID != "cluster-uuid" || message.SubscriptionID != "subscription-id" {
+		t.Errorf("MessageFor() isn't addressed to the target: %+v", message)
+	}
+	if message.EventStreamID != IdempotencyKey(job.ID, "cluster-id") {
+		t.Errorf("MessageFor() event stream ID = %q, want the idempotency key %q", message.EventStreamID, target.IdempotencyKey)
+	}
+	if job.Message.ClusterID != "" {
+		t.Errorf("MessageFor() changed the message of the job")
+	}
+
+	job.Message.EventStreamID = "template-stream"
+	if message := job.MessageFor(target); message.EventStreamID != "template-stream" {
+		t.Errorf("MessageFor() event stream ID = %q, want the one of the template", message.EventStreamID)
+	}
+}
+
+func TestJobFinish(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   JobStatus
+		targets  []TargetStatus
+		expected JobStatus
+	}{
+		{
+			name:     "all sent",
+			status:   JobRunning,
+			targets:  []TargetStatus{TargetSent, TargetSent},
+			expected: JobCompleted,
+		},
+		{
+			name:     "some failed",
+			status:   JobRunning,
+			targets:  []TargetStatus{TargetSent, TargetFailed},
+			expected: JobFailed,
+		},
+		{
+			name:     "some interrupted",
+			status:   JobRunning,
+			targets:  []TargetStatus{TargetSent, TargetSending},
+			expected: JobFailed,
+		},
+		{
+			name:     "aborted stays aborted",
+			status:   JobAborted,
+			targets:  []TargetStatus{TargetSent, TargetPending},
+			expected: JobAborted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{Status: tt.status}
+			for _, status := range tt.targets {
+				job.Targets = append(job.Targets, &JobTarget{Status: status})
+			}
+			job.Finish()
+			if job.Status != tt.expected {
+				t.Errorf("Finish() status = %s, want %s", job.Status, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJobStore(t *testing.T) {
+	store := &JobStore{Dir: t.TempDir()}
+	now := time.Date(2024, 3, 12, 10, 15, 0, 0, time.UTC)
+
+	job, err := NewJob(Message{Summary: "Maintenance"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.AddTarget("a", "uuid-a", "", "")
+	job.AddTarget("b", "uuid-b", "", "")
+	job.Targets[0].Status = TargetSent
+	if err := store.Save(job, now); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := store.Load(job.ID[:len("20240312-101500")])
+	if err != nil {
+		t.Fatalf("Load() by prefix: %v", err)
+	}
+	if unsent := loaded.Unsent(); len(unsent) != 1 || unsent[0].ClusterID != "b" {
+		t.Errorf("Load() unsent targets = %+v, want b only", unsent)
+	}
+	if _, err := store.Load("../" + job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Load() of a path = %v, want ErrJobNotFound", err)
+	}
+
+	// Aborted from another process while this one is still sending
+	loaded.Status = JobAborted
+	if err := store.Save(loaded, now); err != nil {
+		t.Fatal(err)
+	}
+	job.Targets[1].Status = TargetSent
+	if err := store.Save(job, now); err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != JobAborted {
+		t.Errorf("Save() status = %s, want the job aborted on disk to stay aborted", job.Status)
+	}
+
+	if err := store.Prune(now.Add(JobRetention + time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("List() after Prune() = %d jobs, want none", len(jobs))
+	}
+}
@@ -8,6 +8,7 @@ import (
 // Message is the base template structure
 type Message struct {
 	Severity       string   `json:"severity"`
+	LogType        string   `json:"log_type,omitempty"`
 	ServiceName    string   `json:"service_name"`
 	ClusterUUID    string   `json:"cluster_uuid,omitempty"`
 	ClusterID      string   `json:"cluster_id,omitempty"`
@@ -23,6 +24,10 @@ func (m *Message) GetSeverity() string {
 	return m.Severity
 }
 
+func (m *Message) GetLogType() string {
+	return m.LogType
+}
+
 func (m *Message) GetServiceName() string {
 	return m.ServiceName
 }
@@ -61,6 +66,7 @@ func (m *Message) GetDocReferences() []string {
 
 func (m *Message) ReplaceWithFlag(variable, value string) {
 	m.Severity = strings.ReplaceAll(m.Severity, variable, value)
+	m.LogType = strings.ReplaceAll(m.LogType, variable, value)
 	m.ServiceName = strings.ReplaceAll(m.ServiceName, variable, value)
 	m.ClusterUUID = strings.ReplaceAll(m.ClusterUUID, variable, value)
 	m.ClusterID = strings.ReplaceAll(m.ClusterID, variable, value)
@@ -74,6 +80,9 @@ func (m *Message) SearchFlag(placeholder string) (found bool) {
 	if found = strings.Contains(m.Severity, placeholder); found == true {
 		return found
 	}
+	if found = strings.Contains(m.LogType, placeholder); found == true {
+		return found
+	}
 	if found = strings.Contains(m.ServiceName, placeholder); found == true {
 		return found
 	}
@@ -100,7 +109,7 @@ func (m *Message) SearchFlag(placeholder string) (found bool) {
 
 func (m *Message) FindLeftovers() (matches []string, found bool) {
 	r := regexp.MustCompile(`\${[^{}]*}`)
-	str := m.Severity + m.ServiceName + m.ClusterUUID + m.Summary + m.Description + m.EventStreamID
+	str := m.Severity + m.LogType + m.ServiceName + m.ClusterUUID + m.Summary + m.Description + m.EventStreamID
 	matches = r.FindAllString(str, -1)
 	if len(matches) > 0 {
 		found = true
@@ -0,0 +1,153 @@
+// Package fixtures provides representative data of a cluster to the tests of the commands, so the printers
+// can be compared with golden files and the commands share the same test data. The data is deterministic:
+// the dates are relative to Now, and every call returns new objects the tests are free to modify.
+package fixtures
+
+import (
+	"time"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/andygrunwald/go-jira"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/pkg/provider/supportcase"
+	"github.com/openshift/osdctl/pkg/provider/telemetry"
+)
+
+const (
+	ClusterID         = "2a7bc3e1f4d94c1e8a6f0b5d3c2e1f40"
+	ExternalClusterID = "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"
+	ClusterName       = "fixture-cluster"
+	ClusterVersion    = "4.14.11"
+	OCMEnv            = "production"
+
+	// PagerDutyServiceID is the PagerDuty service of the cluster
+	PagerDutyServiceID = "PABC123"
+)
+
+// Now is the reference time of the fixtures
+var Now = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// ServiceLogs returns the service logs of the cluster, newest first: two upgrade notifications sent from the
+// same template, an internal service log and a limited support notification
+func ServiceLogs() []*v1.LogEntry {
+	return []*v1.LogEntry{
+		logEntry("sl-4", "Upgrade maintenance notification", "The cluster will be upgraded to 4.14.12", false, Now.Add(-2*time.Hour)),
+		logEntry("sl-3", "Internal", "Cluster hibernated\nby the customer", true, Now.AddDate(0, 0, -2)),
+		logEntry("sl-2", "Cluster is in Limited Support due to unsupported cloud provider configuration", "The cluster's security groups were modified", false, Now.AddDate(0, 0, -5)),
+		logEntry("sl-1", "Upgrade maintenance notification", "The cluster will be upgraded to 4.14.11", false, Now.AddDate(0, 0, -9)),
+	}
+}
+
+// LimitedSupportReasons returns the limited support reasons of the cluster, oldest first
+func LimitedSupportReasons() []*cmv1.LimitedSupportReason {
+	return []*cmv1.LimitedSupportReason{
+		limitedSupportReason("ls-1", "Cluster is in Limited Support due to unsupported cloud provider configuration", "The security groups of the cluster were modified", Now.AddDate(0, 0, -5)),
+	}
+}
+
+// PagerDutyIncidents returns the open incidents of the PagerDuty service of the cluster, high urgency first
+func PagerDutyIncidents() map[string][]pd.Incident {
+	return map[string][]pd.Incident{
+		PagerDutyServiceID: {
+			incident("Q1HIGH", "high", "triggered", "ClusterOperatorDown monitoring CRITICAL (1)", Now.Add(-30*time.Minute)),
+			incident("Q2LOW", "low", "acknowledged", "KubePersistentVolumeFillingUp openshift-monitoring WARNING (1)", Now.Add(-3*time.Hour)),
+		},
+	}
+}
+
+// JiraIssues returns the OHSS issues of the cluster, by key
+func JiraIssues() []jira.Issue {
+	return []jira.Issue{
+		jiraIssue("OHSS-101", "Customer reports the console is unreachable", "Story", "Major", "In Progress", Now.AddDate(0, 0, -1)),
+		jiraIssue("OHSS-99", "Cluster upgrade stuck on the machine-config operator", "Bug", "Critical", "Closed", Now.AddDate(0, 0, -20)),
+	}
+}
+
+// SupportCases returns the open Customer Portal support cases of the cluster, by case number
+func SupportCases() []supportcase.Case {
+	return []supportcase.Case{
+		{
+			CaseNumber:       "03712345",
+			Summary:          "Console unreachable after the upgrade",
+			Status:           "Waiting on Red Hat",
+			Severity:         "2 (High)",
+			Product:          "OpenShift Dedicated",
+			Version:          "4.14",
+			ClusterID:        ExternalClusterID,
+			CreatedDate:      Now.AddDate(0, 0, -1),
+			LastModifiedDate: Now.Add(-4 * time.Hour),
+		},
+	}
+}
+
+// SLOStatuses returns the error budgets of the cluster over the last 7 days: the API availability is burning
+// its budget right now, the cluster operators are fine and an SLO has no data
+func SLOStatuses() []telemetry.SLOStatus {
+	window := 7 * 24 * time.Hour
+	return []telemetry.SLOStatus{
+		telemetry.ErrorBudget(telemetry.SLO{Name: "API availability", Objective: 0.995}, 0.998, 0.99, window),
+		telemetry.ErrorBudget(telemetry.SLO{Name: "Cluster operators available", Objective: 0.99}, 0.999, 1, window),
+		{Name: "Console availability", Objective: 0.99, Window: "7d", Error: "failed to query Console availability: no data"},
+	}
+}
+
+func logEntry(id string, summary string, description string, internal bool, createdAt time.Time) *v1.LogEntry {
+	entry, err := v1.NewLogEntry().
+		ID(id).
+		ClusterID(ClusterID).
+		Summary(summary).
+		Description(description).
+		InternalOnly(internal).
+		Severity(v1.Severity("Info")).
+		ServiceName("SREManualAction").
+		CreatedAt(createdAt).
+		Timestamp(createdAt).
+		Build()
+	if err != nil {
+		panic(err)
+	}
+	return entry
+}
+
+func limitedSupportReason(id string, summary string, details string, createdAt time.Time) *cmv1.LimitedSupportReason {
+	reason, err := cmv1.NewLimitedSupportReason().
+		ID(id).
+		Summary(summary).
+		Details(details).
+		DetectionType(cmv1.DetectionTypeManual).
+		CreationTimestamp(createdAt).
+		Build()
+	if err != nil {
+		panic(err)
+	}
+	return reason
+}
+
+func incident(id string, urgency string, status string, title string, createdAt time.Time) pd.Incident {
+	return pd.Incident{
+		APIObject: pd.APIObject{
+			ID:      id,
+			HTMLURL: "https://redhat.pagerduty.com/incidents/" + id,
+		},
+		Title:     title,
+		Urgency:   urgency,
+		Status:    status,
+		CreatedAt: createdAt.Format(time.RFC3339),
+		Service:   pd.APIObject{ID: PagerDutyServiceID},
+	}
+}
+
+func jiraIssue(key string, summary string, issueType string, priority string, status string, createdAt time.Time) jira.Issue {
+	return jira.Issue{
+		Key: key,
+		Fields: &jira.IssueFields{
+			Summary:  summary,
+			Type:     jira.IssueType{Name: issueType},
+			Priority: &jira.Priority{Name: priority},
+			Status:   &jira.Status{Name: status},
+			Created:  jira.Time(createdAt),
+			Updated:  jira.Time(createdAt),
+		},
+	}
+}
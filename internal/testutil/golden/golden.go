@@ -0,0 +1,119 @@
+// Package golden compares the output of the printers with golden files kept in the testdata directory of
+// the tested package. Run the tests with -update to write the golden files from the current output, then
+// review the diff:
+//
+//	go test ./pkg/utils/... -update
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the golden files from the current output")
+
+// Path returns the golden file of the test output name, relative to the tested package
+func Path(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// Assert compares got with the golden file of name, or writes it with -update
+func Assert(t testing.TB, name string, got []byte) {
+	t.Helper()
+	path := Path(name)
+	if *update {
+		write(t, path, got)
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the golden file, run the test with -update to create it: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run the test with -update if the change is expected\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// AssertJSON compares the JSON document got with the golden file of name, ignoring the formatting and the
+// order of the fields, or writes it indented with -update
+func AssertJSON(t testing.TB, name string, got []byte) {
+	t.Helper()
+	path := Path(name)
+	if *update {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, got, "", "  "); err != nil {
+			t.Fatalf("the output isn't valid JSON: %v", err)
+		}
+		indented.WriteByte('\n')
+		write(t, path, indented.Bytes())
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the golden file, run the test with -update to create it: %v", err)
+	}
+	gotNormalized, err := normalizeJSON(got)
+	if err != nil {
+		t.Fatalf("the output isn't valid JSON: %v", err)
+	}
+	wantNormalized, err := normalizeJSON(want)
+	if err != nil {
+		t.Fatalf("%s isn't valid JSON: %v", path, err)
+	}
+	if !bytes.Equal(gotNormalized, wantNormalized) {
+		t.Errorf("output differs from %s, run the test with -update if the change is expected\ngot:\n%s\nwant:\n%s", path, gotNormalized, wantNormalized)
+	}
+}
+
+// CaptureStdout returns what print writes to os.Stdout, for the printers writing with fmt.Print*
+func CaptureStdout(t testing.TB, print func()) []byte {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = original }()
+
+	var captured bytes.Buffer
+	done := make(chan error)
+	go func() {
+		_, err := io.Copy(&captured, reader)
+		done <- err
+	}()
+
+	print()
+
+	os.Stdout = original
+	_ = writer.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	_ = reader.Close()
+	return captured.Bytes()
+}
+
+// normalizeJSON re-encodes the document indented with sorted keys
+func normalizeJSON(document []byte) ([]byte, error) {
+	var value interface{}
+	if err := json.Unmarshal(document, &value); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(value, "", "  ")
+}
+
+func write(t testing.TB, path string, content []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,25 @@
+package golden
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAssert(t *testing.T) {
+	Assert(t, "assert", []byte("NAME   STATUS\nfoo    ready\n"))
+}
+
+func TestAssertJSON(t *testing.T) {
+	// the fields are compared regardless of their order and of the formatting
+	AssertJSON(t, "assert_json", []byte(`{"status":"ready","name":"foo","tags":["a","b"]}`))
+}
+
+func TestCaptureStdout(t *testing.T) {
+	got := CaptureStdout(t, func() {
+		fmt.Println("first line")
+		fmt.Print("second line\n")
+	})
+	if want := "first line\nsecond line\n"; string(got) != want {
+		t.Errorf("CaptureStdout() = %q, want %q", got, want)
+	}
+}
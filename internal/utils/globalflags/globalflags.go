@@ -2,6 +2,8 @@ package globalflags
 
 import (
 	awsSdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/openshift/osdctl/pkg/guardrails"
+	"github.com/openshift/osdctl/pkg/httpdebug"
 	"github.com/openshift/osdctl/pkg/provider/aws"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -12,6 +14,8 @@ type GlobalOptions struct {
 	Output           string
 	SkipVersionCheck bool
 	NoAwsProxy       bool
+	DebugHTTP        string
+	ChangeRecord     string
 }
 
 // AddGlobalFlags adds the Global Flags to the root command
@@ -19,6 +23,9 @@ func AddGlobalFlags(cmd *cobra.Command, opts *GlobalOptions) {
 	cmd.PersistentFlags().StringVarP(&opts.Output, "output", "o", "", "Valid formats are ['', 'json', 'yaml', 'env']")
 	cmd.PersistentFlags().BoolVarP(&opts.SkipVersionCheck, "skip-version-check", "S", false, "skip checking to see if this is the most recent release")
 	cmd.PersistentFlags().BoolVar(&opts.NoAwsProxy, aws.NoProxyFlag, false, "Don't use the configured `aws_proxy` value")
+	cmd.PersistentFlags().StringVar(&opts.DebugHTTP, httpdebug.FlagName, httpdebug.ModeOff, httpdebug.FlagUsage)
+	cmd.PersistentFlags().Lookup(httpdebug.FlagName).NoOptDefVal = httpdebug.ModeBasic
+	cmd.PersistentFlags().StringVar(&opts.ChangeRecord, guardrails.ChangeRecordFlag, "", guardrails.ChangeRecordUsage)
 }
 
 // GetFlags adds the kubeFlags we care about and adds the flags from the provided command
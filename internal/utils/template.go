@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// TemplateChecksumFlagName is the flag pinning the sha256 of a template for one command
+	TemplateChecksumFlagName = "template-sha256"
+	// TemplatePinsConfigKey lists the pinned templates as "<sha256> <url>" entries, like sha256sum prints them
+	TemplatePinsConfigKey = "template_pins"
+	// TemplateSigningKeysConfigKey lists the base64 ed25519 public keys trusted to sign the templates
+	TemplateSigningKeysConfigKey = "template_signing_keys"
+
+	// templateSignatureSuffix is appended to the URL of a template to download its base64 ed25519 signature
+	templateSignatureSuffix = ".sig"
+)
+
+// TemplateVerification is how the templates downloaded from URLs are verified
+type TemplateVerification struct {
+	// Checksum is the expected sha256 of the template, it takes precedence over the pins
+	Checksum string
+	// Pins are "<sha256> <url>" entries
+	Pins []string
+	// SigningKeys are base64 ed25519 public keys, when set the template must be signed by one of them
+	SigningKeys []string
+}
+
+// FetchVerifiedTemplate downloads the template at the URL and checks it against the expected checksum, if
+// any, and its signature when signing keys are configured
+func FetchVerifiedTemplate(templateURL string, verification TemplateVerification) ([]byte, error) {
+	content, err := fetchURL(templateURL)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := verification.Checksum
+	if checksum == "" {
+		if checksum, err = PinnedChecksum(verification.Pins, templateURL); err != nil {
+			return nil, err
+		}
+	}
+	if checksum != "" {
+		if err := VerifyChecksum(content, checksum); err != nil {
+			return nil, fmt.Errorf("template %s: %w", templateURL, err)
+		}
+	}
+
+	if len(verification.SigningKeys) > 0 {
+		signature, err := fetchURL(templateURL + templateSignatureSuffix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download the signature of template %s: %w", templateURL, err)
+		}
+		if err := VerifySignature(content, signature, verification.SigningKeys); err != nil {
+			return nil, fmt.Errorf("template %s: %w", templateURL, err)
+		}
+	}
+	return content, nil
+}
+
+func fetchURL(rawURL string) ([]byte, error) {
+	urlPage, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := IsOnline(*urlPage); err != nil {
+		return nil, fmt.Errorf("host %q is not accessible", rawURL)
+	}
+	return CurlThis(urlPage.String())
+}
+
+// PinnedChecksum returns the sha256 pinned for the URL, empty when it isn't pinned
+func PinnedChecksum(pins []string, templateURL string) (string, error) {
+	for _, pin := range pins {
+		fields := strings.Fields(pin)
+		if len(fields) != 2 {
+			return "", fmt.Errorf("invalid %s entry '%s', expected '<sha256> <url>'", TemplatePinsConfigKey, pin)
+		}
+		if fields[1] == templateURL {
+			return fields[0], nil
+		}
+	}
+	return "", nil
+}
+
+// VerifyChecksum checks that the sha256 of the content is the expected hex digest
+func VerifyChecksum(content []byte, expected string) error {
+	sum := sha256.Sum256(content)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf("sha256 mismatch: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// VerifySignature checks that the base64 ed25519 signature of the content was made by one of the keys
+func VerifySignature(content []byte, signature []byte, keys []string) error {
+	decodedSignature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil || len(decodedSignature) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid signature, expected a base64 ed25519 signature")
+	}
+	for _, key := range keys {
+		decodedKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+		if err != nil || len(decodedKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid %s entry '%s', expected a base64 ed25519 public key", TemplateSigningKeysConfigKey, key)
+		}
+		if ed25519.Verify(decodedKey, content, decodedSignature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the signature doesn't match any of the %s", TemplateSigningKeysConfigKey)
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchVerifiedTemplate(t *testing.T) {
+	template := []byte(`{"severity": "Info", "summary": "Maintenance"}`)
+	sum := sha256.Sum256(template)
+	checksum := hex.EncodeToString(sum[:])
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingKey := base64.StdEncoding.EncodeToString(publicKey)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, template))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/signed.json", "/unsigned.json":
+			_, _ = w.Write(template)
+		case "/signed.json.sig":
+			_, _ = w.Write([]byte(signature + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	signedURL := server.URL + "/signed.json"
+	unsignedURL := server.URL + "/unsigned.json"
+
+	tests := []struct {
+		name         string
+		url          string
+		verification TemplateVerification
+		wantErr      bool
+	}{
+		{
+			name: "no verification",
+			url:  unsignedURL,
+		},
+		{
+			name:         "matching checksum",
+			url:          unsignedURL,
+			verification: TemplateVerification{Checksum: checksum},
+		},
+		{
+			name:         "mismatching checksum",
+			url:          unsignedURL,
+			verification: TemplateVerification{Checksum: "0000"},
+			wantErr:      true,
+		},
+		{
+			name:         "pinned template",
+			url:          unsignedURL,
+			verification: TemplateVerification{Pins: []string{"0000 " + signedURL, checksum + "  " + unsignedURL}},
+		},
+		{
+			name:         "tampered pinned template",
+			url:          signedURL,
+			verification: TemplateVerification{Pins: []string{"0000 " + signedURL}},
+			wantErr:      true,
+		},
+		{
+			name:         "invalid pin",
+			url:          unsignedURL,
+			verification: TemplateVerification{Pins: []string{checksum}},
+			wantErr:      true,
+		},
+		{
+			name:         "signed template",
+			url:          signedURL,
+			verification: TemplateVerification{SigningKeys: []string{base64.StdEncoding.EncodeToString(otherKey), signingKey}},
+		},
+		{
+			name:         "signed by an untrusted key",
+			url:          signedURL,
+			verification: TemplateVerification{SigningKeys: []string{base64.StdEncoding.EncodeToString(otherKey)}},
+			wantErr:      true,
+		},
+		{
+			name:         "missing signature",
+			url:          unsignedURL,
+			verification: TemplateVerification{SigningKeys: []string{signingKey}},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := FetchVerifiedTemplate(tt.url, tt.verification)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchVerifiedTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(content) != string(template) {
+				t.Errorf("FetchVerifiedTemplate() = %s, want %s", content, template)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Package bookmark saves named bookmarks of clusters for the user, so a cluster can be given by its bookmark
+// wherever osdctl takes a cluster ID
+package bookmark
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/utils"
+)
+
+const fileName = "bookmarks.json"
+
+var nameRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Bookmark is a name given to a cluster. The cluster is saved by its internal ID, its name is kept for display.
+type Bookmark struct {
+	Name        string    `json:"name"`
+	ClusterID   string    `json:"cluster_id"`
+	ClusterName string    `json:"cluster_name,omitempty"`
+	Added       time.Time `json:"added"`
+}
+
+// File is where the bookmarks are saved
+func File() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "osdctl", fileName), nil
+}
+
+// Read returns the bookmarks of the file sorted by name, none when the file doesn't exist
+func Read(path string) ([]Bookmark, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var bookmarks []Bookmark
+	if err := json.Unmarshal(content, &bookmarks); err != nil {
+		return nil, fmt.Errorf("failed to parse the bookmarks %s: %w", path, err)
+	}
+	sort.Slice(bookmarks, func(i, j int) bool {
+		return bookmarks[i].Name < bookmarks[j].Name
+	})
+	return bookmarks, nil
+}
+
+// Write saves the bookmarks to the file, replacing it
+func Write(path string, bookmarks []Bookmark) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if bookmarks == nil {
+		bookmarks = []Bookmark{}
+	}
+	content, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o600)
+}
+
+// ValidateName checks the name can be told apart from the cluster IDs, so it's never mistaken for one
+func ValidateName(name string) error {
+	if !nameRE.MatchString(name) {
+		return fmt.Errorf("invalid bookmark name '%s', use letters, digits, '.', '_' and '-'", name)
+	}
+	if kind := utils.ClassifyClusterIdentifier(name); kind != utils.ClusterIdentifierName {
+		return fmt.Errorf("invalid bookmark name '%s', it looks like a cluster %s", name, kind)
+	}
+	return nil
+}
+
+// Add returns the bookmarks with the new one, which replaces the bookmark of the same name only when replace is set
+func Add(bookmarks []Bookmark, bookmark Bookmark, replace bool) ([]Bookmark, error) {
+	if err := ValidateName(bookmark.Name); err != nil {
+		return nil, err
+	}
+	for i, existing := range bookmarks {
+		if existing.Name != bookmark.Name {
+			continue
+		}
+		if !replace {
+			return nil, fmt.Errorf("bookmark '%s' already exists for cluster %s, pass --force to replace it", bookmark.Name, existing.ClusterID)
+		}
+		bookmarks[i] = bookmark
+		return bookmarks, nil
+	}
+	bookmarks = append(bookmarks, bookmark)
+	sort.Slice(bookmarks, func(i, j int) bool {
+		return bookmarks[i].Name < bookmarks[j].Name
+	})
+	return bookmarks, nil
+}
+
+// Remove returns the bookmarks without the named one
+func Remove(bookmarks []Bookmark, name string) ([]Bookmark, error) {
+	for i, bookmark := range bookmarks {
+		if bookmark.Name == name {
+			return append(bookmarks[:i], bookmarks[i+1:]...), nil
+		}
+	}
+	return nil, fmt.Errorf("no bookmark named '%s'", name)
+}
+
+// Lookup returns the bookmark of the name
+func Lookup(bookmarks []Bookmark, name string) (Bookmark, bool) {
+	for _, bookmark := range bookmarks {
+		if bookmark.Name == name {
+			return bookmark, true
+		}
+	}
+	return Bookmark{}, false
+}
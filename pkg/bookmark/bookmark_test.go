@@ -0,0 +1,109 @@
+package bookmark
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "prod-payments"},
+		{name: "customer.v2_eu"},
+		{name: "", wantErr: true},
+		{name: "-payments", wantErr: true},
+		{name: "prod payments", wantErr: true},
+		{name: "1a2b3c4d5e6f7g8h9i0j1k2l3m4n5o6p", wantErr: true},
+		{name: "5ed2e8f6-3f1e-4c4e-9d1a-0b1c2d3e4f5a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateName(tt.name); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	existing := []Bookmark{{Name: "prod-payments", ClusterID: "old"}}
+	tests := []struct {
+		name     string
+		bookmark Bookmark
+		replace  bool
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "new bookmark sorted by name",
+			bookmark: Bookmark{Name: "dev-payments", ClusterID: "new"},
+			expected: []string{"dev-payments=new", "prod-payments=old"},
+		},
+		{
+			name:     "existing bookmark",
+			bookmark: Bookmark{Name: "prod-payments", ClusterID: "new"},
+			wantErr:  true,
+		},
+		{
+			name:     "replaced bookmark",
+			bookmark: Bookmark{Name: "prod-payments", ClusterID: "new"},
+			replace:  true,
+			expected: []string{"prod-payments=new"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookmarks, err := Add(append([]Bookmark{}, existing...), tt.bookmark, tt.replace)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, b := range bookmarks {
+				names = append(names, b.Name+"="+b.ClusterID)
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Add() = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "osdctl", fileName)
+	bookmarks, err := Read(path)
+	if err != nil || bookmarks != nil {
+		t.Fatalf("Read() of a missing file = %v, %v, want no bookmark", bookmarks, err)
+	}
+
+	added := time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC)
+	written := []Bookmark{
+		{Name: "staging", ClusterID: "b", Added: added},
+		{Name: "prod", ClusterID: "a", ClusterName: "payments", Added: added},
+	}
+	if err := Write(path, written); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	bookmarks, err = Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	expected := []Bookmark{written[1], written[0]}
+	if !reflect.DeepEqual(bookmarks, expected) {
+		t.Errorf("Read() = %+v, want %+v", bookmarks, expected)
+	}
+
+	bookmarks, err = Remove(bookmarks, "prod")
+	if err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, ok := Lookup(bookmarks, "prod"); ok {
+		t.Errorf("Lookup() found the removed bookmark")
+	}
+	if b, ok := Lookup(bookmarks, "staging"); !ok || b.ClusterID != "b" {
+		t.Errorf("Lookup() = %+v, %t, want cluster b", b, ok)
+	}
+}
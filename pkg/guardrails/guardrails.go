@@ -0,0 +1,249 @@
+// Package guardrails enforces the team policy against common production mistakes, e.g. sending a
+// service log without looking at a dry-run of it first
+package guardrails
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// ConfigKey holds the policy, usually set in the team config
+	ConfigKey = "guardrails"
+	// ChangeRecordFlag is the global flag referencing the change record of an operation
+	ChangeRecordFlag  = "change-record"
+	ChangeRecordUsage = "Change record (e.g. a Jira key) covering the operation, required by the guardrails for bulk operations"
+
+	defaultDryRunWindow = time.Hour
+	dryRunsFileName     = "dry-runs.json"
+)
+
+// Policy is the set of guardrails of a team, e.g.
+//
+//	guardrails:
+//	  dry_run_first:
+//	    commands: ["servicelog post"]
+//	    within: 1h
+//	  bulk:
+//	    commands: ["servicelog post"]
+//	    max_clusters: 10
+//	  production_reason:
+//	    commands: ["servicelog post"]
+type Policy struct {
+	// DryRunFirst requires the same operation to have been dry-run recently
+	DryRunFirst *DryRunFirstRule `mapstructure:"dry_run_first"`
+	// Bulk requires a change record for operations over more than MaxClusters clusters
+	Bulk *BulkRule `mapstructure:"bulk"`
+	// ProductionReason requires the --reason flag of the commands when OCM points to production
+	ProductionReason *ProductionReasonRule `mapstructure:"production_reason"`
+}
+
+type DryRunFirstRule struct {
+	Commands []string      `mapstructure:"commands"`
+	Within   time.Duration `mapstructure:"within"`
+}
+
+type BulkRule struct {
+	Commands    []string `mapstructure:"commands"`
+	MaxClusters int      `mapstructure:"max_clusters"`
+}
+
+type ProductionReasonRule struct {
+	Commands []string `mapstructure:"commands"`
+}
+
+// Invocation describes an operation a command is about to make
+type Invocation struct {
+	// Command is the path of the command without the root, e.g. "servicelog post"
+	Command string
+	// Fingerprint identifies the operation, a dry-run only counts for the same fingerprint
+	Fingerprint string
+	DryRun      bool
+	// Clusters is the number of clusters the operation applies to
+	Clusters     int
+	ChangeRecord string
+	Reason       string
+	Production   bool
+}
+
+// Violation is returned when an operation breaks a guardrail
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("blocked by the %s guardrail: %s", v.Rule, v.Message)
+}
+
+// DryRuns records when operations were dry-run, by command and fingerprint
+type DryRuns map[string]time.Time
+
+func dryRunKey(command string, fingerprint string) string {
+	return command + "|" + fingerprint
+}
+
+// LoadPolicy reads the policy from the configuration, an empty policy enforces nothing
+func LoadPolicy() (Policy, error) {
+	policy := Policy{}
+	if !viper.IsSet(ConfigKey) {
+		return policy, nil
+	}
+	if err := viper.UnmarshalKey(ConfigKey, &policy); err != nil {
+		return policy, fmt.Errorf("invalid %s policy: %w", ConfigKey, err)
+	}
+	return policy, nil
+}
+
+func matches(commands []string, command string) bool {
+	for _, c := range commands {
+		if strings.TrimSpace(c) == command {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns a Violation if the invocation breaks a guardrail of the policy. Dry-runs never do.
+func Check(policy Policy, invocation Invocation, dryRuns DryRuns, now time.Time) error {
+	if invocation.DryRun {
+		return nil
+	}
+	if err := checkDryRunFirst(policy.DryRunFirst, invocation, dryRuns, now); err != nil {
+		return err
+	}
+	if err := checkBulk(policy.Bulk, invocation); err != nil {
+		return err
+	}
+	return checkProductionReason(policy.ProductionReason, invocation)
+}
+
+func checkDryRunFirst(rule *DryRunFirstRule, invocation Invocation, dryRuns DryRuns, now time.Time) error {
+	if rule == nil || !matches(rule.Commands, invocation.Command) {
+		return nil
+	}
+	within := rule.Within
+	if within <= 0 {
+		within = defaultDryRunWindow
+	}
+	last, ok := dryRuns[dryRunKey(invocation.Command, invocation.Fingerprint)]
+	if !ok || now.Sub(last) > within {
+		return &Violation{Rule: "dry_run_first", Message: fmt.Sprintf("run the same '%s' with --dry-run first, at most %s before", invocation.Command, within)}
+	}
+	return nil
+}
+
+func checkBulk(rule *BulkRule, invocation Invocation) error {
+	if rule == nil || !matches(rule.Commands, invocation.Command) {
+		return nil
+	}
+	if rule.MaxClusters > 0 && invocation.Clusters > rule.MaxClusters && invocation.ChangeRecord == "" {
+		return &Violation{Rule: "bulk", Message: fmt.Sprintf("the operation applies to %d clusters, more than %d require --%s", invocation.Clusters, rule.MaxClusters, ChangeRecordFlag)}
+	}
+	return nil
+}
+
+func checkProductionReason(rule *ProductionReasonRule, invocation Invocation) error {
+	if rule == nil || !matches(rule.Commands, invocation.Command) {
+		return nil
+	}
+	if invocation.Production && strings.TrimSpace(invocation.Reason) == "" {
+		return &Violation{Rule: "production_reason", Message: fmt.Sprintf("'%s' requires --reason on production clusters", invocation.Command)}
+	}
+	return nil
+}
+
+// EnforceProductionReason checks the reason given to a command against the production_reason guardrail.
+// isProduction, which may need to reach OCM, is only called when the guardrail applies to the command.
+func EnforceProductionReason(command string, reason string, isProduction func() (bool, error)) error {
+	policy, err := LoadPolicy()
+	if err != nil {
+		return err
+	}
+	rule := policy.ProductionReason
+	if rule == nil || !matches(rule.Commands, command) || strings.TrimSpace(reason) != "" {
+		return nil
+	}
+	production, err := isProduction()
+	if err != nil {
+		return fmt.Errorf("failed to check the production_reason guardrail: %w", err)
+	}
+	return checkProductionReason(rule, Invocation{Command: command, Reason: reason, Production: production})
+}
+
+// Enforce checks the invocation against the configured policy, and records it when it's a dry-run so the
+// actual operation can follow
+func Enforce(invocation Invocation) error {
+	policy, err := LoadPolicy()
+	if err != nil {
+		return err
+	}
+	if invocation.ChangeRecord == "" {
+		invocation.ChangeRecord = viper.GetString(ChangeRecordFlag)
+	}
+
+	path, err := dryRunsFile()
+	if err != nil {
+		return err
+	}
+	dryRuns, err := readDryRuns(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if invocation.DryRun {
+		if policy.DryRunFirst == nil || !matches(policy.DryRunFirst.Commands, invocation.Command) {
+			return nil
+		}
+		dryRuns[dryRunKey(invocation.Command, invocation.Fingerprint)] = now
+		return writeDryRuns(path, dryRuns, now)
+	}
+	return Check(policy, invocation, dryRuns, now)
+}
+
+func dryRunsFile() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "osdctl", dryRunsFileName), nil
+}
+
+func readDryRuns(path string) (DryRuns, error) {
+	dryRuns := DryRuns{}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return dryRuns, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// A corrupted file only means the dry-runs have to be made again
+	_ = json.Unmarshal(content, &dryRuns)
+	return dryRuns, nil
+}
+
+// writeDryRuns saves the dry-runs, dropping those too old to matter anymore
+func writeDryRuns(path string, dryRuns DryRuns, now time.Time) error {
+	for key, when := range dryRuns {
+		if now.Sub(when) > 24*time.Hour {
+			delete(dryRuns, key)
+		}
+	}
+	content, err := json.Marshal(dryRuns)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o600)
+}
@@ -0,0 +1,93 @@
+package guardrails
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := Policy{
+		DryRunFirst:      &DryRunFirstRule{Commands: []string{"servicelog post"}, Within: time.Hour},
+		Bulk:             &BulkRule{Commands: []string{"servicelog post"}, MaxClusters: 10},
+		ProductionReason: &ProductionReasonRule{Commands: []string{"cluster break-glass"}},
+	}
+	dryRuns := DryRuns{
+		dryRunKey("servicelog post", "recent"): now.Add(-30 * time.Minute),
+		dryRunKey("servicelog post", "old"):    now.Add(-2 * time.Hour),
+	}
+
+	tests := []struct {
+		name       string
+		invocation Invocation
+		wantRule   string
+	}{
+		{name: "dry-run", invocation: Invocation{Command: "servicelog post", Fingerprint: "new", DryRun: true}},
+		{name: "recent dry-run", invocation: Invocation{Command: "servicelog post", Fingerprint: "recent", Clusters: 1}},
+		{name: "no dry-run", invocation: Invocation{Command: "servicelog post", Fingerprint: "new", Clusters: 1}, wantRule: "dry_run_first"},
+		{name: "old dry-run", invocation: Invocation{Command: "servicelog post", Fingerprint: "old", Clusters: 1}, wantRule: "dry_run_first"},
+		{name: "bulk without change record", invocation: Invocation{Command: "servicelog post", Fingerprint: "recent", Clusters: 11}, wantRule: "bulk"},
+		{name: "bulk with change record", invocation: Invocation{Command: "servicelog post", Fingerprint: "recent", Clusters: 11, ChangeRecord: "CHG-1"}},
+		{name: "production without reason", invocation: Invocation{Command: "cluster break-glass", Production: true}, wantRule: "production_reason"},
+		{name: "production with reason", invocation: Invocation{Command: "cluster break-glass", Production: true, Reason: "OHSS-1"}},
+		{name: "stage without reason", invocation: Invocation{Command: "cluster break-glass"}},
+		{name: "other command", invocation: Invocation{Command: "cluster context", Clusters: 100, Production: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(policy, tt.invocation, dryRuns, now)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Errorf("Check() unexpected error %v", err)
+				}
+				return
+			}
+			var violation *Violation
+			if !errors.As(err, &violation) || violation.Rule != tt.wantRule {
+				t.Errorf("Check() = %v, want a violation of %s", err, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	defer viper.Reset()
+
+	policy, err := LoadPolicy()
+	if err != nil || policy.DryRunFirst != nil || policy.Bulk != nil || policy.ProductionReason != nil {
+		t.Fatalf("LoadPolicy() without config = %+v, %v", policy, err)
+	}
+
+	config := `
+dry_run_first:
+  commands: ["servicelog post"]
+  within: 30m
+bulk:
+  commands: ["servicelog post"]
+  max_clusters: 5
+`
+	settings := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(config), &settings); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(ConfigKey, settings)
+
+	policy, err = LoadPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.DryRunFirst == nil || policy.DryRunFirst.Within != 30*time.Minute || policy.DryRunFirst.Commands[0] != "servicelog post" {
+		t.Errorf("unexpected dry_run_first rule %+v", policy.DryRunFirst)
+	}
+	if policy.Bulk == nil || policy.Bulk.MaxClusters != 5 {
+		t.Errorf("unexpected bulk rule %+v", policy.Bulk)
+	}
+	if policy.ProductionReason != nil {
+		t.Errorf("unexpected production_reason rule %+v", policy.ProductionReason)
+	}
+}
@@ -0,0 +1,223 @@
+// Package history records locally which osdctl commands were run against which clusters, so the next
+// engineer picking up an incident on the same machine, or the same engineer later on, can see the prior actions
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DisabledConfigKey turns off the recording of the commands
+	DisabledConfigKey = "history_disabled"
+
+	fileName = "history.jsonl"
+	// retention is how long the entries are kept, older ones are dropped when the file is compacted
+	retention = 90 * 24 * time.Hour
+	// compactSize is the size over which the file is compacted when an entry is recorded
+	compactSize = 4 << 20
+)
+
+// ClusterIDFlags are the flags the commands take the cluster ID with
+var ClusterIDFlags = []string{"cluster-id", "cluster"}
+
+// profileFlags are the flags the commands take the AWS profile with
+var profileFlags = []string{"aws-profile", "profile"}
+
+// Entry is a command run against a cluster. Only the names of the flags are recorded, not their values,
+// as some are credentials; the reason is kept as it tells why the command was run.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	ClusterID string    `json:"cluster_id"`
+	Command   string    `json:"command"`
+	Args      []string  `json:"args,omitempty"`
+	Flags     []string  `json:"flags,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Profile   string    `json:"profile,omitempty"`
+	User      string    `json:"user,omitempty"`
+}
+
+// Record saves the invocation of the command when it targets a cluster. Failing to record never fails
+// the command, the errors are returned for the caller to report.
+func Record(cmd *cobra.Command, args []string) error {
+	if viper.GetBool(DisabledConfigKey) {
+		return nil
+	}
+	entry, ok := NewEntry(cmd, args, time.Now())
+	if !ok {
+		return nil
+	}
+	path, err := File()
+	if err != nil {
+		return err
+	}
+	return appendEntry(path, entry, time.Now())
+}
+
+// NewEntry returns the entry of the invocation, false when the command doesn't target a cluster
+func NewEntry(cmd *cobra.Command, args []string, now time.Time) (Entry, bool) {
+	clusterID := clusterIDOf(cmd, args)
+	if clusterID == "" {
+		return Entry{}, false
+	}
+
+	entry := Entry{
+		Time:      now.UTC(),
+		ClusterID: clusterID,
+		Command:   strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Args:      args,
+		Profile:   os.Getenv("AWS_PROFILE"),
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		entry.Flags = append(entry.Flags, "--"+flag.Name)
+		switch {
+		case flag.Name == "reason":
+			entry.Reason = flag.Value.String()
+		case contains(profileFlags, flag.Name):
+			entry.Profile = flag.Value.String()
+		}
+	})
+	if current, err := user.Current(); err == nil {
+		entry.User = current.Username
+	}
+	return entry, true
+}
+
+// clusterIDOf returns the cluster the command targets, from its cluster ID flag or from its cluster argument
+func clusterIDOf(cmd *cobra.Command, args []string) string {
+	for _, name := range ClusterIDFlags {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Value.String() != "" {
+			return flag.Value.String()
+		}
+	}
+	if position := ClusterArgPosition(cmd); position >= 0 && position < len(args) {
+		return args[position]
+	}
+	return ""
+}
+
+// ClusterArgPosition returns the position of the positional argument the usage of the command names after
+// a cluster, e.g. 0 for "storage <cluster-id>", -1 when the command takes no such argument
+func ClusterArgPosition(cmd *cobra.Command) int {
+	position := 0
+	for _, field := range strings.Fields(cmd.Use)[1:] {
+		if !strings.HasPrefix(field, "<") && !strings.HasPrefix(field, "[") {
+			continue
+		}
+		if strings.Contains(strings.ToLower(field), "cluster") {
+			return position
+		}
+		position++
+	}
+	return -1
+}
+
+// File is where the history is saved
+func File() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "osdctl", fileName), nil
+}
+
+func appendEntry(path string, entry Entry, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > compactSize {
+		if err := compact(path, now); err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// compact drops the entries older than the retention
+func compact(path string, now time.Time) error {
+	entries, err := Read(path)
+	if err != nil {
+		return err
+	}
+	var lines []byte
+	for _, entry := range entries {
+		if now.Sub(entry.Time) > retention {
+			continue
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+	return os.WriteFile(path, lines, 0o600)
+}
+
+// Read returns the entries of the history file, oldest first. The lines that can't be parsed are skipped.
+func Read(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Filter returns the entries of any of the cluster IDs, all of them when none is given, recorded after since
+func Filter(entries []Entry, clusterIDs []string, since time.Time) []Entry {
+	var filtered []Entry
+	for _, entry := range entries {
+		if entry.Time.Before(since) {
+			continue
+		}
+		if len(clusterIDs) > 0 && !contains(clusterIDs, entry.ClusterID) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newTestCommand(use string) *cobra.Command {
+	root := &cobra.Command{Use: "osdctl"}
+	cluster := &cobra.Command{Use: "cluster"}
+	cmd := &cobra.Command{Use: use, Run: func(cmd *cobra.Command, args []string) {}}
+	cmd.Flags().String("cluster-id", "", "")
+	cmd.Flags().String("reason", "", "")
+	cmd.Flags().String("aws-profile", "", "")
+	cmd.Flags().String("token", "", "")
+	root.AddCommand(cluster)
+	cluster.AddCommand(cmd)
+	return cmd
+}
+
+func TestNewEntry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		use       string
+		args      []string
+		flags     map[string]string
+		wantOK    bool
+		wantEntry Entry
+	}{
+		{
+			name:   "Cluster ID argument",
+			use:    "storage <cluster-id>",
+			args:   []string{"abc"},
+			flags:  map[string]string{"reason": "OHSS-1", "token": "secret"},
+			wantOK: true,
+			wantEntry: Entry{
+				Time: now, ClusterID: "abc", Command: "cluster storage", Args: []string{"abc"},
+				Flags: []string{"--reason", "--token"}, Reason: "OHSS-1",
+			},
+		},
+		{
+			name:   "Cluster ID after another argument",
+			use:    "drain-node <node> <cluster-id>",
+			args:   []string{"node-1", "abc"},
+			wantOK: true,
+			wantEntry: Entry{
+				Time: now, ClusterID: "abc", Command: "cluster drain-node", Args: []string{"node-1", "abc"},
+			},
+		},
+		{
+			name:   "Cluster ID flag",
+			use:    "resize",
+			flags:  map[string]string{"cluster-id": "def", "aws-profile": "rhcontrol"},
+			wantOK: true,
+			wantEntry: Entry{
+				Time: now, ClusterID: "def", Command: "cluster resize",
+				Flags: []string{"--aws-profile", "--cluster-id"}, Profile: "rhcontrol",
+			},
+		},
+		{
+			name:   "No cluster",
+			use:    "labels <org-id>",
+			args:   []string{"org"},
+			wantOK: false,
+		},
+		{
+			name:   "Missing cluster argument",
+			use:    "storage <cluster-id>",
+			wantOK: false,
+		},
+	}
+
+	t.Setenv("AWS_PROFILE", "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTestCommand(tt.use)
+			for name, value := range tt.flags {
+				if err := cmd.Flags().Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			entry, ok := NewEntry(cmd, tt.args, now)
+			if ok != tt.wantOK {
+				t.Fatalf("NewEntry() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			entry.User = ""
+			if !reflect.DeepEqual(entry, tt.wantEntry) {
+				t.Errorf("NewEntry() = %+v, want %+v", entry, tt.wantEntry)
+			}
+		})
+	}
+}
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "osdctl", fileName)
+	now := time.Now().UTC().Truncate(time.Second)
+	old := Entry{Time: now.Add(-2 * retention), ClusterID: "abc", Command: "cluster context"}
+	recent := Entry{Time: now, ClusterID: "abc", Command: "cluster storage"}
+	other := Entry{Time: now, ClusterID: "def", Command: "cluster storage"}
+
+	for _, entry := range []Entry{old, recent, other} {
+		if err := appendEntry(path, entry, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Filter(entries, []string{"abc"}, time.Time{}); !reflect.DeepEqual(got, []Entry{old, recent}) {
+		t.Errorf("Filter() = %+v", got)
+	}
+	if got := Filter(entries, nil, now.Add(-time.Hour)); !reflect.DeepEqual(got, []Entry{recent, other}) {
+		t.Errorf("Filter() = %+v", got)
+	}
+
+	if err := compact(path, now); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(entries, []Entry{recent, other}) {
+		t.Errorf("after compact = %+v", entries)
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	entries, err := Read(filepath.Join(t.TempDir(), fileName))
+	if err != nil || entries != nil {
+		t.Errorf("Read() = %v, %v", entries, err)
+	}
+}
@@ -0,0 +1,190 @@
+package httpdebug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/redact"
+)
+
+const (
+	FlagName  = "debug-http"
+	FlagUsage = "Log the outbound OCM, PagerDuty and Jira HTTP requests to stderr. Use --debug-http=full to include headers and bodies, secrets are always redacted"
+
+	// ModeOff, ModeBasic and ModeFull are the values of --debug-http, --debug-http alone means ModeBasic
+	ModeOff   = ""
+	ModeBasic = "basic"
+	ModeFull  = "full"
+
+	// maxBodyLength keeps huge list responses from flooding the terminal
+	maxBodyLength = 16 * 1024
+)
+
+var (
+	mode           = ModeOff
+	out  io.Writer = os.Stderr
+)
+
+// SetMode enables the request tracing, it must be called before the clients are built
+func SetMode(value string) error {
+	switch value {
+	case ModeOff, ModeBasic, ModeFull:
+		mode = value
+		return nil
+	case "true":
+		mode = ModeBasic
+		return nil
+	case "false":
+		mode = ModeOff
+		return nil
+	}
+	return fmt.Errorf("invalid --%s value '%s', expected %s or %s", FlagName, value, ModeBasic, ModeFull)
+}
+
+// Enabled returns true if the requests are traced
+func Enabled() bool {
+	return mode != ModeOff
+}
+
+// Wrap returns a transport logging every request sent through next, or next itself when tracing is off
+func Wrap(next http.RoundTripper) http.RoundTripper {
+	if !Enabled() {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next, full: mode == ModeFull, out: out}
+}
+
+type transport struct {
+	next http.RoundTripper
+	full bool
+	out  io.Writer
+
+	mu       sync.Mutex
+	lastKey  string
+	attempts int
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+	attempt := t.attempt(key)
+
+	line := fmt.Sprintf("[http] --> %s %s", req.Method, RedactURL(req.URL))
+	if attempt > 1 {
+		line += fmt.Sprintf(" (retry %d)", attempt-1)
+	}
+	t.println(line)
+	if t.full {
+		t.printHeaders("[http] -->", req.Header)
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := io.ReadAll(req.Body)
+			_ = req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			t.printBody("[http] -->", body)
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.println(fmt.Sprintf("[http] <-- %s %s failed after %s: %v", req.Method, RedactURL(req.URL), latency, err))
+		return resp, err
+	}
+
+	t.println(fmt.Sprintf("[http] <-- %s %s %s (%s)", resp.Status, req.Method, RedactURL(req.URL), latency))
+	if t.full {
+		t.printHeaders("[http] <--", resp.Header)
+		if resp.Body != nil {
+			body, err := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			t.printBody("[http] <--", body)
+		}
+	}
+	if resp.StatusCode < 400 {
+		t.done(key)
+	}
+	return resp, nil
+}
+
+// attempt counts the consecutive sends of the same request, which is how the OCM SDK and
+// go-pagerduty retry
+func (t *transport) attempt(key string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if key != t.lastKey {
+		t.lastKey = key
+		t.attempts = 0
+	}
+	t.attempts++
+	return t.attempts
+}
+
+func (t *transport) done(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if key == t.lastKey {
+		t.lastKey = ""
+		t.attempts = 0
+	}
+}
+
+func (t *transport) println(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Every line goes through the redaction, the error messages may quote the credentials too
+	fmt.Fprintln(t.out, redact.Secrets(line))
+}
+
+func (t *transport) printHeaders(prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if redact.IsSecretName(name) {
+			value = redact.RedactedSecret
+		}
+		t.println(fmt.Sprintf("%s %s: %s", prefix, name, value))
+	}
+}
+
+func (t *transport) printBody(prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	text := RedactBody(string(body))
+	if len(text) > maxBodyLength {
+		text = fmt.Sprintf("%s... (%d bytes truncated)", text[:maxBodyLength], len(text)-maxBodyLength)
+	}
+	t.println(fmt.Sprintf("%s %s", prefix, text))
+}
+
+// RedactURL returns the URL with the credentials in its query or user info masked
+func RedactURL(u *url.URL) string {
+	return redact.URL(u)
+}
+
+// RedactBody masks the credentials found in JSON or form encoded bodies, e.g. OAuth token exchanges
+func RedactBody(body string) string {
+	return redact.Secrets(body)
+}
@@ -0,0 +1,114 @@
+// Package interrupt lets the long-running commands stop cleanly on Ctrl-C: the first interrupt cancels the context
+// of the command and aborts its in-flight HTTP requests, so it can print what it collected so far. The next one
+// kills osdctl as usual.
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// Annotation marks the commands which stop by themselves once their context is canceled
+const Annotation = "osdctl.openshift.io/cancellable"
+
+var (
+	mu sync.Mutex
+	// interrupted is canceled on the first interrupt of a cancellable command
+	interrupted = context.Background()
+)
+
+// MarkCancellable declares the command stops by itself once its context is canceled
+func MarkCancellable(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[Annotation] = "true"
+	return cmd
+}
+
+// Cancellable returns true if the command stops by itself once its context is canceled
+func Cancellable(cmd *cobra.Command) bool {
+	return cmd.Annotations[Annotation] == "true"
+}
+
+// Handle cancels the context of the command on the first interrupt when it's cancellable, and restores the default
+// behavior for the next one. The other commands are killed by the first interrupt, as they'd ignore it otherwise.
+func Handle(cmd *cobra.Command) {
+	if !Cancellable(cmd) {
+		return
+	}
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+		_, _ = fmt.Fprintln(os.Stderr, "\nInterrupted, stopping. Interrupt again to quit right away")
+	}()
+
+	mu.Lock()
+	interrupted = ctx
+	mu.Unlock()
+	cmd.SetContext(ctx)
+}
+
+func current() context.Context {
+	mu.Lock()
+	defer mu.Unlock()
+	return interrupted
+}
+
+// Wrap returns a transport aborting the requests sent through next once the command is interrupted
+func Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	interrupted := current()
+	if interrupted.Done() == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(interrupted, cancel)
+	release := func() {
+		stop()
+		cancel()
+	}
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+		return resp, err
+	}
+	// The body is read after RoundTrip returns, the request is released once it's closed
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,62 @@
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestHandle(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	plain := &cobra.Command{Use: "plain"}
+	plain.SetContext(context.Background())
+	Handle(plain)
+	if plain.Context() != context.Background() {
+		t.Errorf("Handle() replaced the context of a command which isn't cancellable")
+	}
+
+	cmd := MarkCancellable(&cobra.Command{Use: "context"})
+	cmd.SetContext(context.Background())
+	Handle(cmd)
+
+	client := &http.Client{Transport: Wrap(nil)}
+	failed := make(chan error, 1)
+	go func() {
+		_, err := client.Get(server.URL)
+		failed <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("failed to interrupt the test: %v", err)
+	}
+
+	select {
+	case <-cmd.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the context of the command wasn't canceled by the interrupt")
+	}
+	select {
+	case err := <-failed:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("the in-flight request failed with %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the in-flight request wasn't aborted by the interrupt")
+	}
+}
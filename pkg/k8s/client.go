@@ -4,8 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	bplogin "github.com/openshift/backplane-cli/cmd/ocm-backplane/login"
-	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
 	bputils "github.com/openshift/backplane-cli/pkg/utils"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -33,7 +31,7 @@ func (b *lazyClientInitializer) initialize(s *LazyClient) {
 	}
 	if len(s.userName) > 0 || len(s.elevationReasons) > 0 {
 		if len(s.userName) == 0 {
-			s.userName = "backplane-cluster-admin"
+			s.userName = BackplaneClusterAdmin
 		}
 		impersonationConfig := rest.ImpersonationConfig{
 			UserName: s.userName,
@@ -136,31 +134,14 @@ func (s *LazyClient) getClient() client.Client {
 }
 
 func New(clusterID string, options client.Options) (client.Client, error) {
-	bp, err := bpconfig.GetBackplaneConfiguration()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load backplane-cli config: %v", err)
-	}
-
-	cfg, err := bplogin.GetRestConfig(bp, clusterID)
-	if err != nil {
-		return nil, err
-	}
-
-	return client.New(cfg, options)
+	return NewClientFactory().Client(clusterID, options)
 }
 
 func NewAsBackplaneClusterAdmin(clusterID string, options client.Options, elevationReasons ...string) (client.Client, error) {
-	bp, err := bpconfig.GetBackplaneConfiguration()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load backplane-cli config: %v", err)
-	}
-
-	cfg, err := bplogin.GetRestConfigAsUser(bp, clusterID, "backplane-cluster-admin", elevationReasons...)
-	if err != nil {
-		return nil, err
-	}
-
-	return client.New(cfg, options)
+	return NewClientFactory().
+		WithImpersonation(BackplaneClusterAdmin).
+		WithElevationReasons(elevationReasons...).
+		Client(clusterID, options)
 }
 
 func GetCurrentCluster() (string, error) {
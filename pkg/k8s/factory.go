@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"fmt"
+
+	bplogin "github.com/openshift/backplane-cli/cmd/ocm-backplane/login"
+	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const BackplaneClusterAdmin = "backplane-cluster-admin"
+
+// The backplane and client constructors are variables so tests can inspect the configuration
+// ClientFactory asks for without a backplane login.
+var (
+	getBackplaneConfiguration = bpconfig.GetBackplaneConfiguration
+	getRestConfig             = bplogin.GetRestConfig
+	getRestConfigAsUser       = bplogin.GetRestConfigAsUser
+	newClient                 = client.New
+)
+
+// ClientFactory builds kubernetes clients for any cluster through backplane, without
+// requiring the caller to have run 'ocm backplane login' beforehand.
+//
+//	c, err := k8s.NewClientFactory().
+//		WithElevationReasons(reason, "Listing PVCs with osdctl").
+//		WithDryRun(dryRun).
+//		Client(clusterID, client.Options{Scheme: scheme})
+type ClientFactory struct {
+	elevationReasons  []string
+	impersonateUser   string
+	impersonateGroups []string
+	dryRun            bool
+}
+
+func NewClientFactory() *ClientFactory {
+	return &ClientFactory{}
+}
+
+// WithElevationReasons makes the factory elevate to backplane-cluster-admin (unless another user
+// is impersonated) and attach the given reasons to every request. Reasons of repeated calls are
+// merged.
+func (f *ClientFactory) WithElevationReasons(elevationReasons ...string) *ClientFactory {
+	f.elevationReasons = append(f.elevationReasons, elevationReasons...)
+	return f
+}
+
+// WithImpersonation makes requests on behalf of the given user and groups
+func (f *ClientFactory) WithImpersonation(userName string, groups ...string) *ClientFactory {
+	f.impersonateUser = userName
+	f.impersonateGroups = groups
+	return f
+}
+
+// WithDryRun makes clients returned by Client submit every mutating request with DryRun=All
+func (f *ClientFactory) WithDryRun(dryRun bool) *ClientFactory {
+	f.dryRun = dryRun
+	return f
+}
+
+// RestConfig returns a rest.Config pointing to the backplane proxy of the given cluster
+func (f *ClientFactory) RestConfig(clusterID string) (*rest.Config, error) {
+	bp, err := getBackplaneConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backplane-cli config: %v", err)
+	}
+
+	userName := f.impersonateUser
+	if userName == "" && len(f.elevationReasons) > 0 {
+		userName = BackplaneClusterAdmin
+	}
+
+	var cfg *rest.Config
+	if userName == "" {
+		cfg, err = getRestConfig(bp, clusterID)
+	} else {
+		cfg, err = getRestConfigAsUser(bp, clusterID, userName, f.elevationReasons...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backplane rest config for cluster %s: %w", clusterID, err)
+	}
+
+	if len(f.impersonateGroups) > 0 {
+		cfg.Impersonate.Groups = f.impersonateGroups
+	}
+
+	return cfg, nil
+}
+
+// Client returns a controller-runtime client for the given cluster
+func (f *ClientFactory) Client(clusterID string, options client.Options) (client.Client, error) {
+	cfg, err := f.RestConfig(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newClient(cfg, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.dryRun {
+		return client.NewDryRunClient(c), nil
+	}
+	return c, nil
+}
+
+// Clientset returns a typed clientset for the given cluster, needed for subresources such as
+// pod exec or logs. Clientsets don't support dry-run, callers have to honor it themselves.
+func (f *ClientFactory) Clientset(clusterID string) (*kubernetes.Clientset, error) {
+	cfg, err := f.RestConfig(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(cfg)
+}
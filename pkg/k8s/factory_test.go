@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// stubBackplane replaces the backplane calls with ones which record the requested user and
+// reasons in the returned rest.Config, and restores them when the test finishes.
+func stubBackplane(t *testing.T) {
+	origGetBackplaneConfiguration := getBackplaneConfiguration
+	origGetRestConfig := getRestConfig
+	origGetRestConfigAsUser := getRestConfigAsUser
+	t.Cleanup(func() {
+		getBackplaneConfiguration = origGetBackplaneConfiguration
+		getRestConfig = origGetRestConfig
+		getRestConfigAsUser = origGetRestConfigAsUser
+	})
+
+	getBackplaneConfiguration = func() (bpconfig.BackplaneConfiguration, error) {
+		return bpconfig.BackplaneConfiguration{URL: "https://backplane.example.com"}, nil
+	}
+	getRestConfig = func(bp bpconfig.BackplaneConfiguration, clusterID string) (*rest.Config, error) {
+		return &rest.Config{Host: bp.URL + "/backplane/cluster/" + clusterID}, nil
+	}
+	getRestConfigAsUser = func(bp bpconfig.BackplaneConfiguration, clusterID, username string, elevationReasons ...string) (*rest.Config, error) {
+		cfg := &rest.Config{Host: bp.URL + "/backplane/cluster/" + clusterID}
+		cfg.Impersonate.UserName = username
+		if len(elevationReasons) > 0 {
+			cfg.Impersonate.Extra = map[string][]string{"reason": elevationReasons}
+		}
+		return cfg, nil
+	}
+}
+
+func TestClientFactoryRestConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+	stubBackplane(t)
+	testCases := []struct {
+		title           string
+		factory         *ClientFactory
+		expectedUser    string
+		expectedGroups  []string
+		expectedReasons []string
+	}{
+		{
+			title:   "no options uses the backplane login user",
+			factory: NewClientFactory(),
+		},
+		{
+			title:           "elevation reasons impersonate backplane-cluster-admin",
+			factory:         NewClientFactory().WithElevationReasons("OHSS-1234"),
+			expectedUser:    BackplaneClusterAdmin,
+			expectedReasons: []string{"OHSS-1234"},
+		},
+		{
+			title:           "elevation reasons of repeated calls are merged",
+			factory:         NewClientFactory().WithElevationReasons("OHSS-1234").WithElevationReasons("Listing PVCs with osdctl"),
+			expectedUser:    BackplaneClusterAdmin,
+			expectedReasons: []string{"OHSS-1234", "Listing PVCs with osdctl"},
+		},
+		{
+			title:          "impersonated user and groups",
+			factory:        NewClientFactory().WithImpersonation("jdoe", "dedicated-admins", "system:authenticated"),
+			expectedUser:   "jdoe",
+			expectedGroups: []string{"dedicated-admins", "system:authenticated"},
+		},
+		{
+			title:           "impersonated user takes precedence over backplane-cluster-admin",
+			factory:         NewClientFactory().WithElevationReasons("OHSS-1234").WithImpersonation("jdoe"),
+			expectedUser:    "jdoe",
+			expectedReasons: []string{"OHSS-1234"},
+		},
+		{
+			title:   "dry-run doesn't change the rest config",
+			factory: NewClientFactory().WithDryRun(true),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			cfg, err := tc.factory.RestConfig("abc123")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cfg.Host).To(Equal("https://backplane.example.com/backplane/cluster/abc123"))
+			g.Expect(cfg.Impersonate.UserName).To(Equal(tc.expectedUser))
+			g.Expect(cfg.Impersonate.Groups).To(Equal(tc.expectedGroups))
+			g.Expect(cfg.Impersonate.Extra["reason"]).To(Equal(tc.expectedReasons))
+		})
+	}
+}
+
+func TestClientFactoryClientDryRun(t *testing.T) {
+	g := NewGomegaWithT(t)
+	stubBackplane(t)
+	origNewClient := newClient
+	t.Cleanup(func() { newClient = origNewClient })
+
+	testCases := []struct {
+		title   string
+		dryRun  bool
+		created bool
+	}{
+		{
+			title:   "without dry-run requests are submitted",
+			dryRun:  false,
+			created: true,
+		},
+		{
+			title:   "dry-run requests aren't persisted",
+			dryRun:  true,
+			created: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			newClient = func(*rest.Config, client.Options) (client.Client, error) {
+				return fakeClient, nil
+			}
+
+			c, err := NewClientFactory().WithDryRun(tc.dryRun).Client("abc123", client.Options{Scheme: scheme.Scheme})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+			g.Expect(c.Create(context.TODO(), namespace)).To(Succeed())
+
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: "foo"}, &v1.Namespace{})
+			if tc.created {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package links collects the URLs commands print about a cluster, so they can be opened in a browser
+package links
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kinds of links, used to pick the links to open
+const (
+	KindSplunk    = "splunk"
+	KindPagerDuty = "pd"
+	KindOHSS      = "ohss"
+	KindCCX       = "ccx"
+	KindJira      = "jira"
+	KindDynatrace = "dynatrace"
+	KindCase      = "case"
+
+	// SelectAll picks every link
+	SelectAll = "all"
+	// SelectInteractive asks which links to pick
+	SelectInteractive = "ask"
+)
+
+var kinds = []string{KindCase, KindCCX, KindDynatrace, KindJira, KindOHSS, KindPagerDuty, KindSplunk}
+
+// Kinds returns the known kinds of links
+func Kinds() []string {
+	return append([]string{}, kinds...)
+}
+
+// Link is a named URL of a given kind
+type Link struct {
+	Kind string
+	Name string
+	URL  string
+}
+
+// Registry collects links, it's safe to add links from concurrent collectors
+type Registry struct {
+	mu    sync.Mutex
+	links []Link
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add registers a link, the name is what's shown to the user
+func (r *Registry) Add(kind string, name string, url string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.links = append(r.links, Link{Kind: kind, Name: name, URL: strings.TrimSpace(url)})
+}
+
+// Links returns the registered links sorted by kind and name
+func (r *Registry) Links(kinds ...string) []Link {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wanted := map[string]bool{}
+	for _, kind := range kinds {
+		wanted[kind] = true
+	}
+
+	var result []Link
+	for _, link := range r.links {
+		if len(kinds) == 0 || wanted[link.Kind] {
+			result = append(result, link)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// ValidateSelection checks the selection only contains known kinds, "all" or "ask"
+func ValidateSelection(selection []string) error {
+	for _, kind := range selection {
+		if kind == SelectAll || kind == SelectInteractive {
+			continue
+		}
+		known := false
+		for _, k := range kinds {
+			known = known || k == kind
+		}
+		if !known {
+			return fmt.Errorf("unknown kind of link '%s', expected one of %v, '%s' or '%s'", kind, kinds, SelectAll, SelectInteractive)
+		}
+	}
+	return nil
+}
+
+// Select returns the links of the selected kinds which have a URL. Selecting "ask" lists the links on
+// out and reads the numbers of the links to pick from in.
+func (r *Registry) Select(selection []string, in io.Reader, out io.Writer) ([]Link, error) {
+	for _, kind := range selection {
+		switch kind {
+		case SelectAll:
+			return withURL(r.Links()), nil
+		case SelectInteractive:
+			return promptSelection(withURL(r.Links()), in, out)
+		}
+	}
+	return withURL(r.Links(selection...)), nil
+}
+
+func withURL(links []Link) []Link {
+	var result []Link
+	for _, link := range links {
+		if link.URL != "" {
+			result = append(result, link)
+		}
+	}
+	return result
+}
+
+func promptSelection(links []Link, in io.Reader, out io.Writer) ([]Link, error) {
+	if len(links) == 0 {
+		return nil, nil
+	}
+	for i, link := range links {
+		fmt.Fprintf(out, "%3d) [%s] %s\n", i+1, link.Kind, link.Name)
+	}
+	fmt.Fprint(out, "Links to open (e.g. 1,3): ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	var selected []Link
+	for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' }) {
+		index, err := strconv.Atoi(field)
+		if err != nil || index < 1 || index > len(links) {
+			return nil, fmt.Errorf("invalid selection '%s', expected numbers between 1 and %d", field, len(links))
+		}
+		selected = append(selected, links[index-1])
+	}
+	return selected, nil
+}
+
+// Open opens each link with the given function, e.g. browser.OpenURL, and returns the links it failed to open
+func Open(links []Link, open func(url string) error) []error {
+	var errs []error
+	for _, link := range links {
+		if err := open(link.URL); err != nil {
+			errs = append(errs, fmt.Errorf("failed to open %s: %w", link.Name, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,97 @@
+package links
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestRegistry() *Registry {
+	r := NewRegistry()
+	r.Add(KindSplunk, "Splunk Audit Logs", "https://splunk.example.com\n\n")
+	r.Add(KindPagerDuty, "PagerDuty Service P2", "https://pd.example.com/P2")
+	r.Add(KindPagerDuty, "PagerDuty Service P1", "https://pd.example.com/P1")
+	r.Add(KindCCX, "CCX dashboard", "")
+	return r
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection []string
+		input     string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "by kind",
+			selection: []string{KindSplunk, KindPagerDuty},
+			want:      []string{"PagerDuty Service P1", "PagerDuty Service P2", "Splunk Audit Logs"},
+		},
+		{
+			name:      "links without URL are skipped",
+			selection: []string{KindCCX},
+		},
+		{
+			name:      "all",
+			selection: []string{SelectAll},
+			want:      []string{"PagerDuty Service P1", "PagerDuty Service P2", "Splunk Audit Logs"},
+		},
+		{
+			name:      "interactive",
+			selection: []string{SelectInteractive},
+			input:     "3, 1\n",
+			want:      []string{"Splunk Audit Logs", "PagerDuty Service P1"},
+		},
+		{
+			name:      "interactive out of range",
+			selection: []string{SelectInteractive},
+			input:     "4\n",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := newTestRegistry().Select(tt.selection, strings.NewReader(tt.input), &bytes.Buffer{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, link := range selected {
+				names = append(names, link.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("Select() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSelection(t *testing.T) {
+	if err := ValidateSelection([]string{KindSplunk, KindPagerDuty, SelectAll}); err != nil {
+		t.Errorf("ValidateSelection() error = %v", err)
+	}
+	if err := ValidateSelection([]string{"kibana"}); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	var opened []string
+	errs := Open(newTestRegistry().Links(KindSplunk, KindPagerDuty), func(url string) error {
+		if strings.HasSuffix(url, "P2") {
+			return errors.New("no browser")
+		}
+		opened = append(opened, url)
+		return nil
+	})
+	if want := []string{"https://pd.example.com/P1", "https://splunk.example.com"}; !reflect.DeepEqual(opened, want) {
+		t.Errorf("opened %v, want %v", opened, want)
+	}
+	if len(errs) != 1 {
+		t.Errorf("got errors %v, want 1", errs)
+	}
+}
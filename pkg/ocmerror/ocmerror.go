@@ -0,0 +1,192 @@
+// Package ocmerror explains the error codes OCM returns, e.g. CLUSTERS-MGMT-400 or the OCM3xxx provisioning
+// errors, with what to do about them
+package ocmerror
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// GuidanceConfigKey completes or overrides the built-in explanations in the osdctl config, by code:
+//
+//	ocm_error_guidance:
+//	  OCM3055:
+//	    summary: The installer couldn't reach the AWS API
+//	    guidance: Check the egress of the cluster's VPC
+//	    sop: https://example.com/sop.md
+const GuidanceConfigKey = "ocm_error_guidance"
+
+// Explanation tells what an error code means and what to do about it
+type Explanation struct {
+	Code     string `json:"code"`
+	Summary  string `json:"summary"`
+	Guidance string `json:"guidance,omitempty"`
+	SOP      string `json:"sop,omitempty"`
+}
+
+var (
+	// codeRE matches the codes of the OCM APIs, e.g. CLUSTERS-MGMT-400, and the provisioning codes, e.g. OCM3001
+	codeRE = regexp.MustCompile(`\b(?:[A-Z]+-)+[0-9]{2,4}\b|\bOCM[0-9]{4}\b`)
+	// statusRE splits an API code into its service and the HTTP status it's named after
+	statusRE = regexp.MustCompile(`^((?:[A-Z]+-)+)([0-9]{3})$`)
+	// provisioningRE matches the codes of the installation failures
+	provisioningRE = regexp.MustCompile(`^OCM3[0-9]{3}$`)
+)
+
+// builtinExplanations are keyed by the HTTP status the API codes are named after, e.g. CLUSTERS-MGMT-404
+var builtinExplanations = map[string]Explanation{
+	"400": {
+		Summary:  "OCM rejected the request as invalid",
+		Guidance: "The reason following the code names the invalid field or value. Check the flags and the values passed, and that the cluster supports the operation (e.g. its product, version or state).",
+	},
+	"401": {
+		Summary:  "OCM didn't authenticate the request",
+		Guidance: "The OCM token expired or is missing. Log in again with 'ocm login' and check 'ocm whoami'.",
+	},
+	"403": {
+		Summary:  "The OCM account isn't allowed to do this",
+		Guidance: "Check the roles of your account with 'ocm whoami' and that you're logged in to the right OCM environment. Some operations need an elevated role granted on request.",
+	},
+	"404": {
+		Summary:  "OCM has no such object",
+		Guidance: "The cluster, subscription or other object doesn't exist in this OCM environment. It may have been deleted, or it lives in another environment: check 'ocm config get url'.",
+	},
+	"409": {
+		Summary:  "The request conflicts with the current state of the object",
+		Guidance: "The object already exists, or another operation on it is in progress. Wait for it to complete, re-read the object and retry.",
+	},
+	"429": {
+		Summary:  "OCM is rate limiting the requests",
+		Guidance: "Too many requests were sent. Wait a minute before retrying, and lower the concurrency of fleet-wide commands.",
+	},
+	"500": {
+		Summary:  "OCM failed to process the request",
+		Guidance: "This is a server side error, retry later. If it persists, report it to the OCM team with the operation identifier of the error.",
+	},
+	"502": {
+		Summary:  "OCM or one of its dependencies is unavailable",
+		Guidance: "Retry later. If it persists, check the status of the OCM services.",
+	},
+	"503": {
+		Summary:  "OCM is unavailable",
+		Guidance: "Retry later. If it persists, check the status of the OCM services.",
+	},
+}
+
+// provisioningExplanation explains the OCM3xxx codes OCM sets on the clusters which failed to install
+var provisioningExplanation = Explanation{
+	Summary:  "The installation of the cluster failed",
+	Guidance: "The provision error message of the cluster tells which step failed. Check the install logs with 'osdctl cluster wait-for <cluster-id> --hive' for the ClusterDeployment conditions, and the cloud account of the customer for the quotas, permissions and network requirements.",
+}
+
+// Explain returns the explanation of the code: the one of the osdctl config if any, else the built-in one for
+// its family
+func Explain(code string) (Explanation, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if explanation, ok := configuredExplanations()[code]; ok {
+		return explanation, true
+	}
+	if matches := statusRE.FindStringSubmatch(code); matches != nil {
+		if explanation, ok := builtinExplanations[matches[2]]; ok {
+			explanation.Code = code
+			return explanation, true
+		}
+	}
+	if provisioningRE.MatchString(code) {
+		explanation := provisioningExplanation
+		explanation.Code = code
+		return explanation, true
+	}
+	return Explanation{}, false
+}
+
+// Known returns the configured explanations, followed by the built-in ones of the API codes by HTTP status
+func Known() []Explanation {
+	var explanations []Explanation
+	for _, explanation := range configuredExplanations() {
+		explanations = append(explanations, explanation)
+	}
+	sort.Slice(explanations, func(i, j int) bool {
+		return explanations[i].Code < explanations[j].Code
+	})
+
+	statuses := make([]string, 0, len(builtinExplanations))
+	for status := range builtinExplanations {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		explanation := builtinExplanations[status]
+		explanation.Code = "<SERVICE>-" + status
+		explanations = append(explanations, explanation)
+	}
+	explanation := provisioningExplanation
+	explanation.Code = "OCM3xxx"
+	return append(explanations, explanation)
+}
+
+// Codes returns the error codes found in the message, in order and without duplicates
+func Codes(message string) []string {
+	var codes []string
+	for _, code := range codeRE.FindAllString(message, -1) {
+		if !contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// Guidance returns the explanations of the codes found in the message, ready to be printed after it, or
+// nothing when the message has no known code
+func Guidance(message string) string {
+	var b strings.Builder
+	for _, code := range Codes(message) {
+		explanation, ok := Explain(code)
+		if !ok {
+			continue
+		}
+		b.WriteString(Format(explanation))
+	}
+	return b.String()
+}
+
+// Format lays the explanation out on a few lines
+func Format(explanation Explanation) string {
+	text := fmt.Sprintf("%s: %s\n", explanation.Code, explanation.Summary)
+	if explanation.Guidance != "" {
+		text += fmt.Sprintf("  %s\n", explanation.Guidance)
+	}
+	if explanation.SOP != "" {
+		text += fmt.Sprintf("  SOP: %s\n", explanation.SOP)
+	}
+	return text
+}
+
+func configuredExplanations() map[string]Explanation {
+	explanations := map[string]Explanation{}
+	for code, value := range viper.GetStringMap(GuidanceConfigKey) {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		explanation := Explanation{Code: strings.ToUpper(code)}
+		explanation.Summary, _ = fields["summary"].(string)
+		explanation.Guidance, _ = fields["guidance"].(string)
+		explanation.SOP, _ = fields["sop"].(string)
+		explanations[explanation.Code] = explanation
+	}
+	return explanations
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
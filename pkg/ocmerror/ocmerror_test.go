@@ -0,0 +1,96 @@
+package ocmerror
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected []string
+	}{
+		{
+			name:     "API error",
+			message:  "error: can't retrieve cluster 'abc': status is 404, identifier is '404', code is 'CLUSTERS-MGMT-404' and operation identifier is 'f4e2': Cluster 'abc' not found",
+			expected: []string{"CLUSTERS-MGMT-404"},
+		},
+		{
+			name:     "provision error, repeated",
+			message:  "the cluster is in the error state, provision error OCM3055 (OCM3055)",
+			expected: []string{"OCM3055"},
+		},
+		{
+			name:    "no code",
+			message: "failed to access the AWS account: ExpiredToken",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if codes := Codes(tt.message); !reflect.DeepEqual(codes, tt.expected) {
+				t.Errorf("Codes() = %v, want %v", codes, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	viper.Set(GuidanceConfigKey, map[string]interface{}{
+		"ocm3055": map[string]interface{}{
+			"summary": "The installer couldn't reach the AWS API",
+			"sop":     "https://example.com/sop.md",
+		},
+	})
+	defer viper.Set(GuidanceConfigKey, nil)
+
+	tests := []struct {
+		name     string
+		code     string
+		expected Explanation
+		found    bool
+	}{
+		{
+			name:     "configured",
+			code:     "OCM3055",
+			expected: Explanation{Code: "OCM3055", Summary: "The installer couldn't reach the AWS API", SOP: "https://example.com/sop.md"},
+			found:    true,
+		},
+		{
+			name:     "provisioning family",
+			code:     "OCM3001",
+			expected: Explanation{Code: "OCM3001", Summary: provisioningExplanation.Summary, Guidance: provisioningExplanation.Guidance},
+			found:    true,
+		},
+		{
+			name:     "API code by HTTP status",
+			code:     "acct-mgmt-403",
+			expected: Explanation{Code: "ACCT-MGMT-403", Summary: builtinExplanations["403"].Summary, Guidance: builtinExplanations["403"].Guidance},
+			found:    true,
+		},
+		{
+			name: "unknown status",
+			code: "CLUSTERS-MGMT-418",
+		},
+		{
+			name: "unknown code",
+			code: "SHA-256",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation, found := Explain(tt.code)
+			if found != tt.found || !reflect.DeepEqual(explanation, tt.expected) {
+				t.Errorf("Explain() = %+v, %t, want %+v, %t", explanation, found, tt.expected, tt.found)
+			}
+		})
+	}
+
+	guidance := Guidance("code is 'CLUSTERS-MGMT-404', see SHA-256 and OCM3055")
+	if !strings.HasPrefix(guidance, "CLUSTERS-MGMT-404: ") || !strings.Contains(guidance, "  SOP: https://example.com/sop.md\n") {
+		t.Errorf("Guidance() = %q, want the explanations of CLUSTERS-MGMT-404 and OCM3055", guidance)
+	}
+}
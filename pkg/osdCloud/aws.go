@@ -128,24 +128,17 @@ func GenerateJumpRoleCredentials(client aws.Client, region, sessionName string)
 		return nil, err
 	}
 
-	jumpRoleKey := ProdJumproleConfigKey
 	conn, err := utils.CreateConnection()
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
-	currentEnv := utils.GetCurrentOCMEnv(conn)
-	if currentEnv == "stage" || currentEnv == "integration" {
-		jumpRoleKey = StageJumproleConfigKey
+	jumproleAccountID, err := GetJumpRoleAccountID(conn)
+	if err != nil {
+		return nil, err
 	}
 
-	if !viper.IsSet(jumpRoleKey) {
-		return nil, fmt.Errorf("key %s is not set in config file", jumpRoleKey)
-	}
 	// Assume jump role
-	// This will be different between stage and prod. There's probably a better way to do this that isn't hardcoding
-	jumproleAccountID := viper.GetString(jumpRoleKey)
-
 	jumpRoleArn := aws.GenerateRoleARN(jumproleAccountID, RhTechnicalSupportAccess)
 	jumpAssumeRoleOutput, err := sreCcsAccessRoleClient.AssumeRole(
 		&sts.AssumeRoleInput{
@@ -161,6 +154,21 @@ func GenerateJumpRoleCredentials(client aws.Client, region, sessionName string)
 
 }
 
+// GetJumpRoleAccountID returns the ID of the AWS account of the jump role for the current OCM environment
+func GetJumpRoleAccountID(conn *sdk.Connection) (string, error) {
+	jumpRoleKey := ProdJumproleConfigKey
+	currentEnv := utils.GetCurrentOCMEnv(conn)
+	if currentEnv == "stage" || currentEnv == "integration" {
+		jumpRoleKey = StageJumproleConfigKey
+	}
+
+	if !viper.IsSet(jumpRoleKey) {
+		return "", fmt.Errorf("key %s is not set in config file", jumpRoleKey)
+	}
+	// This will be different between stage and prod. There's probably a better way to do this that isn't hardcoding
+	return viper.GetString(jumpRoleKey), nil
+}
+
 // GenerateRoleSessionName Uses the current IAM ARN to generate a role name. This should end up being RH-SRE-$kerberosID
 func GenerateRoleSessionName(client aws.Client) (string, error) {
 
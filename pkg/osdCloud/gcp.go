@@ -68,21 +68,30 @@ func NewGcpCluster(ocmClient *sdk.Connection, clusterId string) (ClusterHealthCl
 	}, nil
 }
 
-func (g *GcpCluster) Login() error {
-	clusterResources, err := g.OcmClient.ClustersMgmt().V1().Clusters().Cluster(g.ClusterId).Resources().Live().Get().Send()
+// GetGCPProjectID returns the ID of the GCP project of the cluster, from its live gcp_project_claim resource
+func GetGCPProjectID(ocmClient *sdk.Connection, clusterId string) (string, error) {
+	clusterResources, err := ocmClient.ClustersMgmt().V1().Clusters().Cluster(clusterId).Resources().Live().Get().Send()
 	if err != nil {
-		return err
+		return "", err
 	}
 	projectClaimRaw, found := clusterResources.Body().Resources()["gcp_project_claim"]
 	if !found {
-		return fmt.Errorf("The gcp_project_claim was not found in the ocm resource")
+		return "", fmt.Errorf("The gcp_project_claim was not found in the ocm resource")
 	}
 	projectClaim, err := ParseGcpProjectClaim(projectClaimRaw)
 	if err != nil {
 		log.Printf("Unmarshalling GCP projectClaim failed: %v\n", err)
+		return "", err
+	}
+	return projectClaim.Spec.GcpProjectID, nil
+}
+
+func (g *GcpCluster) Login() error {
+	projectID, err := GetGCPProjectID(g.OcmClient, g.ClusterId)
+	if err != nil {
 		return err
 	}
-	g.ProjectId = projectClaim.Spec.GcpProjectID
+	g.ProjectId = projectID
 	g.Zones = g.Cluster.Nodes().AvailabilityZones()
 	if g.ProjectId == "" || len(g.Zones) == 0 {
 		return fmt.Errorf("ProjectID or Zones empty - aborting")
@@ -2,7 +2,9 @@ package osdctlConfig
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/viper"
 )
@@ -11,15 +13,22 @@ const (
 	ConfigFileName = "osdctl"
 )
 
-func EnsureConfigFile() error {
+// UserConfigFile returns the path of the user's config file
+func UserConfigFile() (string, error) {
 	configHomePath, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return configHomePath + "/.config/" + ConfigFileName, nil
+}
+
+func EnsureConfigFile() error {
+	configFilePath, err := UserConfigFile()
 	if err != nil {
 		return err
 	}
-	configFileDir := configHomePath + "/.config/"
-	configFilePath := configFileDir + ConfigFileName
 	if _, err := os.Stat(configFilePath); errors.Is(err, os.ErrNotExist) {
-		err = os.MkdirAll(configFileDir, 0755)
+		err = os.MkdirAll(filepath.Dir(configFilePath), 0755)
 		if err != nil {
 			return err
 		}
@@ -35,5 +44,53 @@ func EnsureConfigFile() error {
 	if err := viper.ReadInConfig(); err != nil {
 		return err
 	}
+
+	// The team config is optional, a broken one shouldn't prevent osdctl from working
+	if err := LoadTeamConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load the team config, continuing without it: %v\n", err)
+	}
 	return nil
 }
+
+// ReadUserConfig returns the settings of the user's config file only, without the team defaults
+func ReadUserConfig() (map[string]interface{}, error) {
+	configFilePath, err := UserConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	return readConfigFile(configFilePath)
+}
+
+// WriteUserConfig sets the given values in the user's config file, leaving its other settings untouched.
+// Unlike viper.WriteConfig, the team defaults aren't copied into the user's config file.
+func WriteUserConfig(values map[string]interface{}) error {
+	configFilePath, err := UserConfigFile()
+	if err != nil {
+		return err
+	}
+	return writeConfigFile(configFilePath, values)
+}
+
+func readConfigFile(path string) (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return v.AllSettings(), nil
+}
+
+func writeConfigFile(path string, values map[string]interface{}) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	for key, value := range values {
+		v.Set(key, value)
+		viper.Set(key, value)
+	}
+	return v.WriteConfigAs(path)
+}
@@ -0,0 +1,32 @@
+package osdctlConfig
+
+import "strings"
+
+// secretKeySuffixes identify the config keys holding credentials, e.g. pd_user_token or slack_signing_secret
+var secretKeySuffixes = []string{"token", "secret", "password", "api_key"}
+
+// IsSecretKey returns true if the config key holds a credential
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutSecrets returns a copy of the settings without the credentials, nested settings included
+func WithoutSecrets(settings map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if IsSecretKey(key) {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = WithoutSecrets(nested)
+		}
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+package osdctlConfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// TeamConfigEnvVar overrides the location of the team config
+	TeamConfigEnvVar = "OSDCTL_TEAM_CONFIG"
+	// TeamConfigKey is the user config key holding the location of the team config
+	TeamConfigKey = "team_config"
+	// DefaultTeamConfigPath is used when no team config location is configured and the file exists
+	DefaultTeamConfigPath = "/etc/osdctl/config"
+
+	// teamConfigCacheTTL is how long a remote team config is used before being fetched again
+	teamConfigCacheTTL = 24 * time.Hour
+)
+
+// teamConfigSource is the location the team config was loaded from, if any
+var teamConfigSource string
+
+// TeamConfigSource returns the location the team config was loaded from, empty when there is none
+func TeamConfigSource() string {
+	return teamConfigSource
+}
+
+// LoadTeamConfig layers the read-only team config under the user config: its settings are viper defaults,
+// so any value from the user config, the environment or a flag takes precedence.
+//
+// The team config is a yaml file like the user config, either a local path or an http(s) URL, e.g. the raw
+// URL of a file in a git repository. Remote configs are cached for a day.
+func LoadTeamConfig() error {
+	source := teamConfigLocation()
+	if source == "" {
+		return nil
+	}
+
+	content, err := readTeamConfig(source)
+	if err != nil {
+		return err
+	}
+
+	settings := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &settings); err != nil {
+		return fmt.Errorf("failed to parse team config %s: %w", source, err)
+	}
+	for key, value := range settings {
+		viper.SetDefault(key, value)
+	}
+	teamConfigSource = source
+	return nil
+}
+
+func teamConfigLocation() string {
+	if source := os.Getenv(TeamConfigEnvVar); source != "" {
+		return source
+	}
+	if source := viper.GetString(TeamConfigKey); source != "" {
+		return source
+	}
+	if _, err := os.Stat(DefaultTeamConfigPath); err == nil {
+		return DefaultTeamConfigPath
+	}
+	return ""
+}
+
+func isRemote(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+func readTeamConfig(source string) ([]byte, error) {
+	if !isRemote(source) {
+		return os.ReadFile(source)
+	}
+
+	cacheFile, err := teamConfigCacheFile()
+	if err != nil {
+		return nil, err
+	}
+	return readCachedRemoteConfig(source, cacheFile, time.Now())
+}
+
+func teamConfigCacheFile() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, ConfigFileName, "team-config.yaml"), nil
+}
+
+// readCachedRemoteConfig returns the cached copy of the remote config while it's fresh, and fetches it
+// otherwise. A stale copy is still used when the remote config can't be fetched, e.g. off VPN.
+func readCachedRemoteConfig(url string, cacheFile string, now time.Time) ([]byte, error) {
+	cached, cacheErr := os.ReadFile(cacheFile)
+	if cacheErr == nil {
+		if info, err := os.Stat(cacheFile); err == nil && now.Sub(info.ModTime()) < teamConfigCacheTTL {
+			return cached, nil
+		}
+	}
+
+	content, err := fetchRemoteConfig(url)
+	if err != nil {
+		if cacheErr == nil {
+			fmt.Fprintf(os.Stderr, "Failed to refresh the team config, using the cached copy: %v\n", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(cacheFile, content, 0600); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func fetchRemoteConfig(url string) ([]byte, error) {
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch team config %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch team config %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// RefreshTeamConfig drops the cached copy of a remote team config, so it's fetched on the next run
+func RefreshTeamConfig() error {
+	cacheFile, err := teamConfigCacheFile()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(cacheFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package osdctlConfig
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadTeamConfig(t *testing.T) {
+	dir := t.TempDir()
+	teamConfig := filepath.Join(dir, "team")
+	if err := os.WriteFile(teamConfig, []byte("team_ids:\n- PTEAM\njira_jql: project = OHSS\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	viper.Reset()
+	defer viper.Reset()
+	t.Setenv(TeamConfigEnvVar, teamConfig)
+	viper.Set("jira_jql", "project = MINE")
+
+	if err := LoadTeamConfig(); err != nil {
+		t.Fatalf("LoadTeamConfig() error = %v", err)
+	}
+	if got := viper.GetStringSlice("team_ids"); !reflect.DeepEqual(got, []string{"PTEAM"}) {
+		t.Errorf("team_ids = %v, want the team value", got)
+	}
+	if got := viper.GetString("jira_jql"); got != "project = MINE" {
+		t.Errorf("jira_jql = %v, want the user value to take precedence", got)
+	}
+	if TeamConfigSource() != teamConfig {
+		t.Errorf("TeamConfigSource() = %v, want %v", TeamConfigSource(), teamConfig)
+	}
+}
+
+func TestReadCachedRemoteConfig(t *testing.T) {
+	requests := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("team_ids: [PTEAM]\n"))
+	}))
+	defer server.Close()
+
+	cacheFile := filepath.Join(t.TempDir(), "osdctl", "team-config.yaml")
+	now := time.Now()
+
+	content, err := readCachedRemoteConfig(server.URL, cacheFile, now)
+	if err != nil || string(content) != "team_ids: [PTEAM]\n" || requests != 1 {
+		t.Fatalf("first read: content %q, error %v, %d requests", content, err, requests)
+	}
+
+	// A fresh cache isn't fetched again
+	if _, err := readCachedRemoteConfig(server.URL, cacheFile, now.Add(time.Hour)); err != nil || requests != 1 {
+		t.Fatalf("cached read: error %v, %d requests", err, requests)
+	}
+
+	// A stale cache is used when the config can't be fetched
+	status = http.StatusInternalServerError
+	content, err = readCachedRemoteConfig(server.URL, cacheFile, now.Add(2*teamConfigCacheTTL))
+	if err != nil || string(content) != "team_ids: [PTEAM]\n" || requests != 2 {
+		t.Fatalf("stale read: content %q, error %v, %d requests", content, err, requests)
+	}
+
+	// Without a cache, the error is returned
+	if _, err := readCachedRemoteConfig(server.URL, filepath.Join(t.TempDir(), "missing"), now); err == nil {
+		t.Fatal("expected an error without a cached copy")
+	}
+}
+
+func TestWithoutSecrets(t *testing.T) {
+	settings := map[string]interface{}{
+		"pd_user_token":            "abc",
+		"jira_token":               "def",
+		"prod_jumprole_account_id": "123456789012",
+		"context_presets": map[string]interface{}{
+			"handoff":    map[string]interface{}{"days": 7},
+			"api_key":    "ghi",
+			"team_token": "jkl",
+		},
+	}
+	want := map[string]interface{}{
+		"prod_jumprole_account_id": "123456789012",
+		"context_presets": map[string]interface{}{
+			"handoff": map[string]interface{}{"days": 7},
+		},
+	}
+	if got := WithoutSecrets(settings); !reflect.DeepEqual(got, want) {
+		t.Errorf("WithoutSecrets() = %v, want %v", got, want)
+	}
+	if _, ok := settings["pd_user_token"]; !ok {
+		t.Error("WithoutSecrets() modified its input")
+	}
+}
+
+func TestWriteConfigFile(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	path := filepath.Join(t.TempDir(), "osdctl")
+	if err := os.WriteFile(path, []byte("jira_token: abc\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetDefault("team_ids", []string{"PTEAM"})
+
+	if err := writeConfigFile(path, map[string]interface{}{"pd_user_token": "def"}); err != nil {
+		t.Fatalf("writeConfigFile() error = %v", err)
+	}
+	got, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"jira_token": "abc", "pd_user_token": "def"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("config file = %v, want %v without the team defaults", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+// Package pager pages the output of the commands through less, or $PAGER, so long outputs such as hundreds of
+// CloudTrail events or service logs can be searched with '/' and the matches jumped between with n and N,
+// across all the sections, without re-running the command with grep
+package pager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+const (
+	// PagerConfigKey makes commands supporting --pager page their output by default
+	PagerConfigKey = "pager"
+	// CommandConfigKey is the pager command, it takes precedence over $PAGER
+	CommandConfigKey = "pager_command"
+
+	PagerFlagName   = "pager"
+	PagerFlagUsage  = "Page the output through less, or the `pager_command` config value or $PAGER, to search it with '/' and jump between the matches with n/N. Defaults to the `pager` config value"
+	SearchFlagName  = "search"
+	SearchFlagUsage = "With --pager, open the output at the first match of this pattern, with every match highlighted"
+
+	defaultPager = "less"
+)
+
+// Options are the paging flags of a command
+type Options struct {
+	Enabled bool
+	Search  string
+}
+
+// AddFlags adds --pager and --search to the flags of a command
+func AddFlags(flags *pflag.FlagSet, options *Options) {
+	flags.BoolVar(&options.Enabled, PagerFlagName, false, PagerFlagUsage)
+	flags.StringVar(&options.Search, SearchFlagName, "", SearchFlagUsage)
+}
+
+// Complete applies the pager config value when --pager wasn't passed
+func (o *Options) Complete(flags *pflag.FlagSet) {
+	if !flags.Changed(PagerFlagName) {
+		o.Enabled = viper.GetBool(PagerConfigKey)
+	}
+}
+
+// Start redirects everything written to os.Stdout to the pager until the returned function is called, which
+// waits for the pager to be quit. Nothing is paged when it isn't enabled or stdout isn't a terminal.
+func (o *Options) Start() (stop func(), err error) {
+	noop := func() {}
+	if !o.Enabled || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return noop, nil
+	}
+
+	command := Command(viper.GetString(CommandConfigKey), os.Getenv("PAGER"), o.Search)
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return noop, err
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = reader
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		_ = reader.Close()
+		_ = writer.Close()
+		return noop, fmt.Errorf("failed to start the pager %s: %w", command[0], err)
+	}
+
+	original := os.Stdout
+	os.Stdout = writer
+	return func() {
+		_ = writer.Close()
+		_ = cmd.Wait()
+		_ = reader.Close()
+		os.Stdout = original
+	}, nil
+}
+
+// Command returns the pager command line: the configured command, else $PAGER, else less. less is told to
+// keep the colors, search case-insensitively and open at the first match of the search, if any.
+func Command(configured string, env string, search string) []string {
+	command := strings.Fields(configured)
+	if len(command) == 0 {
+		command = strings.Fields(env)
+	}
+	if len(command) == 0 {
+		command = []string{defaultPager}
+	}
+	if filepath.Base(command[0]) == defaultPager {
+		command = append(command, "-R", "-i")
+		if search != "" {
+			command = append(command, "+/"+search)
+		}
+	}
+	return command
+}
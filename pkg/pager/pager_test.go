@@ -0,0 +1,52 @@
+package pager
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		env        string
+		search     string
+		want       []string
+	}{
+		{
+			name: "defaults to less",
+			want: []string{"less", "-R", "-i"},
+		},
+		{
+			name:   "less opens at the first match",
+			search: "AccessDenied",
+			want:   []string{"less", "-R", "-i", "+/AccessDenied"},
+		},
+		{
+			name:   "PAGER is used when not configured",
+			env:    "most -s",
+			search: "AccessDenied",
+			want:   []string{"most", "-s"},
+		},
+		{
+			name:       "configured command takes precedence over PAGER",
+			configured: "/usr/bin/less -S",
+			env:        "more",
+			want:       []string{"/usr/bin/less", "-S", "-R", "-i"},
+		},
+		{
+			name:       "blank configured command is ignored",
+			configured: "  ",
+			env:        "more",
+			want:       []string{"more"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Command(tt.configured, tt.env, tt.search); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Command() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
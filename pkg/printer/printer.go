@@ -13,21 +13,47 @@ import (
 // printer use to output something on screen with table format.
 type printer struct {
 	w *tabwriter.Writer
+
+	// rows are buffered until Flush when options are set, to select columns and sort them
+	options *TableOptions
+	rows    [][]string
 }
 
 // NewTablePrinter creates a printer instance, and uses to format output with table.
 func NewTablePrinter(o io.Writer, minWidth, tabWidth, padding int, padChar byte) *printer {
 	w := tabwriter.NewWriter(o, minWidth, tabWidth, padding, padChar, 0)
-	return &printer{w}
+	return &printer{w: w}
+}
+
+// WithTableOptions selects the columns and sorts the rows following o. The first row added is the header.
+func (p *printer) WithTableOptions(o TableOptions) *printer {
+	if o.IsSet() {
+		p.options = &o
+	}
+	return p
 }
 
 // AddRow adds a row of data.
 func (p *printer) AddRow(row []string) {
+	if p.options != nil {
+		p.rows = append(p.rows, row)
+		return
+	}
 	fmt.Fprintln(p.w, strings.Join(row, "\t"))
 }
 
 // Flush outputs all rows on screen.
 func (p *printer) Flush() error {
+	if p.options != nil {
+		rows, err := p.options.Apply(p.rows)
+		if err != nil {
+			return err
+		}
+		p.rows = nil
+		for _, row := range rows {
+			fmt.Fprintln(p.w, strings.Join(row, "\t"))
+		}
+	}
 	return p.w.Flush()
 }
 
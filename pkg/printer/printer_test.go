@@ -51,3 +51,82 @@ foo1                foo2                foo3
 		})
 	}
 }
+
+func TestTableOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rows := [][]string{
+		{"Urgency", "Title", "Count"},
+		{"low", "beta", "10"},
+		{"high", "Alpha", "9"},
+		{"high", "alpha", "100"},
+		{},
+	}
+
+	testCases := []struct {
+		title   string
+		options TableOptions
+		output  [][]string
+		err     bool
+	}{
+		{
+			title:   "no options",
+			options: TableOptions{},
+			output:  rows,
+		},
+		{
+			title:   "sort case-insensitively then by bytes",
+			options: TableOptions{SortBy: "title"},
+			output:  [][]string{rows[0], rows[2], rows[3], rows[1], {}},
+		},
+		{
+			title:   "sort numbers numerically",
+			options: TableOptions{SortBy: "Count"},
+			output:  [][]string{rows[0], rows[2], rows[1], rows[3], {}},
+		},
+		{
+			title:   "sort descending keeps equal rows stable",
+			options: TableOptions{SortBy: "-urgency"},
+			output:  [][]string{rows[0], rows[1], rows[2], rows[3], {}},
+		},
+		{
+			title:   "select and reorder columns",
+			options: TableOptions{Columns: []string{"count", "URGENCY"}},
+			output:  [][]string{{"Count", "Urgency"}, {"10", "low"}, {"9", "high"}, {"100", "high"}, {}},
+		},
+		{
+			title:   "unknown column",
+			options: TableOptions{Columns: []string{"created"}},
+			err:     true,
+		},
+		{
+			title:   "unknown sort column",
+			options: TableOptions{SortBy: "created"},
+			err:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			output, err := tc.options.Apply(rows)
+			if tc.err {
+				g.Expect(err).Should(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(output).Should(Equal(tc.output))
+		})
+	}
+}
+
+func TestTablePrinterWithOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	buf := &bytes.Buffer{}
+	p := NewTablePrinter(buf, 20, 1, 3, ' ').WithTableOptions(TableOptions{Columns: []string{"name"}, SortBy: "name"})
+	p.AddRow([]string{"ID", "Name"})
+	p.AddRow([]string{"2", "foo"})
+	p.AddRow([]string{"1", "bar"})
+	g.Expect(p.Flush()).ShouldNot(HaveOccurred())
+	g.Expect(buf.String()).Should(Equal("Name\nbar\nfoo\n"))
+}
@@ -0,0 +1,127 @@
+package printer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	ColumnsFlagName = "columns"
+	SortByFlagName  = "sort-by"
+)
+
+// TableOptions selects the columns of a table and the order of its rows. The first row added to a
+// printer using them is the header, columns are referred to by their header.
+type TableOptions struct {
+	Columns []string
+	SortBy  string
+}
+
+// AddTableFlags adds the --columns and --sort-by flags filling o
+func AddTableFlags(flags *pflag.FlagSet, o *TableOptions) {
+	flags.StringSliceVar(&o.Columns, ColumnsFlagName, nil, "Comma separated list of the table columns to print, in order")
+	flags.StringVar(&o.SortBy, SortByFlagName, "", "Table column to sort the rows by, prefix it with '-' to sort in descending order")
+}
+
+// IsSet returns whether the options change the table at all
+func (o TableOptions) IsSet() bool {
+	return len(o.Columns) > 0 || o.SortBy != ""
+}
+
+// normalizeColumn lets "created-at" or "created_at" refer to a "Created At" header
+func normalizeColumn(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Apply returns the rows with only the selected columns, sorted. rows[0] is the header. Empty rows,
+// which are used for spacing, are kept at the end.
+func (o TableOptions) Apply(rows [][]string) ([][]string, error) {
+	if !o.IsSet() || len(rows) == 0 {
+		return rows, nil
+	}
+
+	header := rows[0]
+	columnIndex := map[string]int{}
+	for i, name := range header {
+		columnIndex[normalizeColumn(name)] = i
+	}
+	lookup := func(name string) (int, error) {
+		i, ok := columnIndex[normalizeColumn(name)]
+		if !ok {
+			return 0, fmt.Errorf("unknown column '%s', expected one of [%s]", name, strings.Join(header, ", "))
+		}
+		return i, nil
+	}
+
+	var data, spacing [][]string
+	for _, row := range rows[1:] {
+		if len(row) == 0 {
+			spacing = append(spacing, row)
+			continue
+		}
+		data = append(data, row)
+	}
+
+	if o.SortBy != "" {
+		descending := strings.HasPrefix(o.SortBy, "-")
+		sortIndex, err := lookup(strings.TrimPrefix(o.SortBy, "-"))
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(data, func(i, j int) bool {
+			a, b := cell(data[i], sortIndex), cell(data[j], sortIndex)
+			if descending {
+				return compareCells(b, a) < 0
+			}
+			return compareCells(a, b) < 0
+		})
+	}
+
+	result := append([][]string{header}, data...)
+	if len(o.Columns) > 0 {
+		indexes := make([]int, 0, len(o.Columns))
+		for _, column := range o.Columns {
+			i, err := lookup(column)
+			if err != nil {
+				return nil, err
+			}
+			indexes = append(indexes, i)
+		}
+		for r, row := range result {
+			selected := make([]string, 0, len(indexes))
+			for _, i := range indexes {
+				selected = append(selected, cell(row, i))
+			}
+			result[r] = selected
+		}
+	}
+	return append(result, spacing...), nil
+}
+
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// compareCells compares numbers numerically and everything else case-insensitively, falling back
+// to a byte comparison so the order never depends on the locale
+func compareCells(a, b string) int {
+	numberA, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	numberB, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil && numberA != numberB {
+		if numberA < numberB {
+			return -1
+		}
+		return 1
+	}
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
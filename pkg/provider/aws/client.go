@@ -20,11 +20,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/guardduty"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/securityhub"
 	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/spf13/viper"
@@ -41,6 +43,8 @@ type ClientInput struct {
 const (
 	ProxyConfigKey = "aws_proxy"
 	NoProxyFlag    = "skip-aws-proxy-check"
+	// ProfileConfigKey is the AWS profile used when none is passed and AWS_PROFILE isn't set
+	ProfileConfigKey = "aws_profile"
 )
 
 // TODO: Add more methods when needed
@@ -55,12 +59,15 @@ type Client interface {
 	DeleteBucket(*s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error)
 	ListObjects(*s3.ListObjectsInput) (*s3.ListObjectsOutput, error)
 	DeleteObjects(*s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error)
+	PutObject(*s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	GetBucketEncryption(*s3.GetBucketEncryptionInput) (*s3.GetBucketEncryptionOutput, error)
 
 	//iam
 	CreateAccessKey(*iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error)
 	DeleteAccessKey(*iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error)
 	ListAccessKeys(*iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error)
 	GetUser(*iam.GetUserInput) (*iam.GetUserOutput, error)
+	GetRole(*iam.GetRoleInput) (*iam.GetRoleOutput, error)
 	CreateUser(*iam.CreateUserInput) (*iam.CreateUserOutput, error)
 	ListUsers(*iam.ListUsersInput) (*iam.ListUsersOutput, error)
 	AttachUserPolicy(*iam.AttachUserPolicyInput) (*iam.AttachUserPolicyOutput, error)
@@ -84,10 +91,12 @@ type Client interface {
 	DeleteUser(*iam.DeleteUserInput) (*iam.DeleteUserOutput, error)
 
 	//ec2
+	DescribeAddresses(*ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error)
 	DescribeInstances(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
 	DescribeRouteTables(*ec2.DescribeRouteTablesInput) (*ec2.DescribeRouteTablesOutput, error)
 	DescribeSubnets(*ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error)
 	DescribeVpcs(*ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error)
+	DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
 	DescribeVpcEndpoints(*ec2.DescribeVpcEndpointsInput) (*ec2.DescribeVpcEndpointsOutput, error)
 	DescribeVpcEndpointConnections(*ec2.DescribeVpcEndpointConnectionsInput) (*ec2.DescribeVpcEndpointConnectionsOutput, error)
 	DescribeVpcEndpointServices(*ec2.DescribeVpcEndpointServicesInput) (*ec2.DescribeVpcEndpointServicesOutput, error)
@@ -132,6 +141,14 @@ type Client interface {
 	DescribeTags(input *elasticloadbalancing.DescribeTagsInput) (*elasticloadbalancing.DescribeTagsOutput, error)
 	DescribeV2LoadBalancers(input *elasticloadbalancingv2.DescribeLoadBalancersInput) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
 	DescribeV2Tags(input *elasticloadbalancingv2.DescribeTagsInput) (*elasticloadbalancingv2.DescribeTagsOutput, error)
+
+	// GuardDuty
+	ListDetectors(input *guardduty.ListDetectorsInput) (*guardduty.ListDetectorsOutput, error)
+	ListFindings(input *guardduty.ListFindingsInput) (*guardduty.ListFindingsOutput, error)
+	GetFindings(input *guardduty.GetFindingsInput) (*guardduty.GetFindingsOutput, error)
+
+	// Security Hub
+	GetSecurityHubFindings(input *securityhub.GetFindingsInput) (*securityhub.GetFindingsOutput, error)
 }
 
 type AwsClient struct {
@@ -147,6 +164,8 @@ type AwsClient struct {
 	route53Client       route53.Client
 	elbClient           elasticloadbalancing.Client
 	elbv2Client         elasticloadbalancingv2.Client
+	guardDutyClient     guardduty.Client
+	securityHubClient   securityhub.Client
 }
 
 func addProxyConfigToSessionOptConfig(config *aws.Config) {
@@ -180,6 +199,10 @@ func NewAwsConfig(profile, region, configFile string) (*aws.Config, error) {
 	var cfg aws.Config
 	var err error
 
+	if profile == "" && os.Getenv("AWS_PROFILE") == "" {
+		profile = viper.GetString(ProfileConfigKey)
+	}
+
 	// only set config file if it is not empty
 	if configFile != "" {
 		absCfgPath, err := filepath.Abs(configFile)
@@ -223,6 +246,8 @@ func NewAwsClient(profile, region, configFile string) (Client, error) {
 		route53Client:       *route53.NewFromConfig(*cfg),
 		elbClient:           *elasticloadbalancing.NewFromConfig(*cfg),
 		elbv2Client:         *elasticloadbalancingv2.NewFromConfig(*cfg),
+		guardDutyClient:     *guardduty.NewFromConfig(*cfg),
+		securityHubClient:   *securityhub.NewFromConfig(*cfg),
 	}
 
 	// Validate the creds
@@ -258,6 +283,8 @@ func NewAwsClientWithInput(input *ClientInput) (Client, error) {
 		route53Client:       *route53.NewFromConfig(cfg),
 		elbClient:           *elasticloadbalancing.NewFromConfig(cfg),
 		elbv2Client:         *elasticloadbalancingv2.NewFromConfig(cfg),
+		guardDutyClient:     *guardduty.NewFromConfig(cfg),
+		securityHubClient:   *securityhub.NewFromConfig(cfg),
 	}, nil
 }
 
@@ -289,6 +316,14 @@ func (c *AwsClient) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjec
 	return c.s3Client.DeleteObjects(context.TODO(), input)
 }
 
+func (c *AwsClient) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	return c.s3Client.PutObject(context.TODO(), input)
+}
+
+func (c *AwsClient) GetBucketEncryption(input *s3.GetBucketEncryptionInput) (*s3.GetBucketEncryptionOutput, error) {
+	return c.s3Client.GetBucketEncryption(context.TODO(), input)
+}
+
 func (c *AwsClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
 	return c.iamClient.CreateAccessKey(context.TODO(), input)
 }
@@ -305,6 +340,10 @@ func (c *AwsClient) GetUser(input *iam.GetUserInput) (*iam.GetUserOutput, error)
 	return c.iamClient.GetUser(context.TODO(), input)
 }
 
+func (c *AwsClient) GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+	return c.iamClient.GetRole(context.TODO(), input)
+}
+
 func (c *AwsClient) CreateUser(input *iam.CreateUserInput) (*iam.CreateUserOutput, error) {
 	return c.iamClient.CreateUser(context.TODO(), input)
 }
@@ -479,10 +518,18 @@ func (c *AwsClient) DescribeSubnets(input *ec2.DescribeSubnetsInput) (*ec2.Descr
 	return c.ec2Client.DescribeSubnets(context.TODO(), input)
 }
 
+func (c *AwsClient) DescribeAddresses(input *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+	return c.ec2Client.DescribeAddresses(context.TODO(), input)
+}
+
 func (c *AwsClient) DescribeVpcs(input *ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error) {
 	return c.ec2Client.DescribeVpcs(context.TODO(), input)
 }
 
+func (c *AwsClient) DescribeVolumes(input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	return c.ec2Client.DescribeVolumes(context.TODO(), input)
+}
+
 func (c *AwsClient) DescribeVpcEndpoints(input *ec2.DescribeVpcEndpointsInput) (*ec2.DescribeVpcEndpointsOutput, error) {
 	return c.ec2Client.DescribeVpcEndpoints(context.TODO(), input)
 }
@@ -534,3 +581,19 @@ func (c *AwsClient) DescribeV2LoadBalancers(input *elasticloadbalancingv2.Descri
 func (c *AwsClient) DescribeV2Tags(input *elasticloadbalancingv2.DescribeTagsInput) (*elasticloadbalancingv2.DescribeTagsOutput, error) {
 	return c.elbv2Client.DescribeTags(context.TODO(), input)
 }
+
+func (c *AwsClient) ListDetectors(input *guardduty.ListDetectorsInput) (*guardduty.ListDetectorsOutput, error) {
+	return c.guardDutyClient.ListDetectors(context.TODO(), input)
+}
+
+func (c *AwsClient) ListFindings(input *guardduty.ListFindingsInput) (*guardduty.ListFindingsOutput, error) {
+	return c.guardDutyClient.ListFindings(context.TODO(), input)
+}
+
+func (c *AwsClient) GetFindings(input *guardduty.GetFindingsInput) (*guardduty.GetFindingsOutput, error) {
+	return c.guardDutyClient.GetFindings(context.TODO(), input)
+}
+
+func (c *AwsClient) GetSecurityHubFindings(input *securityhub.GetFindingsInput) (*securityhub.GetFindingsOutput, error) {
+	return c.securityHubClient.GetFindings(context.TODO(), input)
+}
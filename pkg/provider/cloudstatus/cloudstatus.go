@@ -0,0 +1,262 @@
+package cloudstatus
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultAWSStatusURL = "https://status.aws.amazon.com/rss"
+	DefaultGCPStatusURL = "https://status.cloud.google.com/incidents.json"
+
+	// DefaultLookback is how far back provider status updates are considered relevant
+	DefaultLookback = 24 * time.Hour
+)
+
+// awsRegionalServices are the AWS services an OSD/ROSA cluster depends on, whose status feeds are per region
+var awsRegionalServices = []string{"ec2", "elasticloadbalancing", "s3", "autoscaling"}
+
+// awsGlobalServices are the AWS services an OSD/ROSA cluster depends on, whose status feeds are global
+var awsGlobalServices = []string{"route53", "iam"}
+
+// Event is a status update published by a cloud provider for a service
+type Event struct {
+	Provider  string    `json:"provider"`
+	Service   string    `json:"service"`
+	Region    string    `json:"region"`
+	Title     string    `json:"title"`
+	Details   string    `json:"details"`
+	URL       string    `json:"url"`
+	Published time.Time `json:"published"`
+	Resolved  bool      `json:"resolved"`
+}
+
+type client struct {
+	httpClient   *http.Client
+	awsStatusURL string
+	gcpStatusURL string
+	lookback     time.Duration
+	now          func() time.Time
+}
+
+func NewClient() *client {
+	return &client{
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		awsStatusURL: DefaultAWSStatusURL,
+		gcpStatusURL: DefaultGCPStatusURL,
+		lookback:     DefaultLookback,
+		now:          time.Now,
+	}
+}
+
+func (c *client) WithAWSStatusURL(url string) *client {
+	c.awsStatusURL = url
+	return c
+}
+
+func (c *client) WithGCPStatusURL(url string) *client {
+	c.gcpStatusURL = url
+	return c
+}
+
+func (c *client) WithLookback(lookback time.Duration) *client {
+	c.lookback = lookback
+	return c
+}
+
+// GetEvents returns the recent status events of the given cloud provider ("aws" or "gcp") affecting the region,
+// sorted with the most recent first. The AWS events are read from the public status RSS feeds, not from the AWS
+// Health API, which needs credentials for the customer's account. When some feeds can't be read, the events of the
+// others are returned along with the error.
+func (c *client) GetEvents(provider string, region string) ([]*Event, error) {
+	var events []*Event
+	var err error
+	switch strings.ToLower(provider) {
+	case "aws":
+		events, err = c.getAWSEvents(region)
+	case "gcp":
+		events, err = c.getGCPEvents(region)
+	default:
+		return nil, fmt.Errorf("unsupported cloud provider %q", provider)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Published.After(events[j].Published)
+	})
+	return events, err
+}
+
+type rssFeed struct {
+	Channel struct {
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+			PubDate     string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func (c *client) getAWSEvents(region string) ([]*Event, error) {
+	feeds := map[string]string{}
+	for _, service := range awsRegionalServices {
+		feeds[fmt.Sprintf("%s-%s", service, region)] = service
+	}
+	for _, service := range awsGlobalServices {
+		feeds[service] = service
+	}
+	names := make([]string, 0, len(feeds))
+	for feed := range feeds {
+		names = append(names, feed)
+	}
+	sort.Strings(names)
+
+	var events []*Event
+	var errs []error
+	for _, feed := range names {
+		service := feeds[feed]
+		body, err := c.get(fmt.Sprintf("%s/%s.rss", c.awsStatusURL, feed))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if body == nil {
+			continue
+		}
+
+		var rss rssFeed
+		if err := xml.Unmarshal(body, &rss); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse AWS status feed %s: %w", feed, err))
+			continue
+		}
+
+		for _, item := range rss.Channel.Items {
+			published, err := time.Parse(time.RFC1123, item.PubDate)
+			if err != nil {
+				published, err = time.Parse(time.RFC1123Z, item.PubDate)
+				if err != nil {
+					continue
+				}
+			}
+			if published.Before(c.now().Add(-c.lookback)) {
+				continue
+			}
+			events = append(events, &Event{
+				Provider:  "aws",
+				Service:   service,
+				Region:    region,
+				Title:     strings.TrimSpace(item.Title),
+				Details:   strings.TrimSpace(item.Description),
+				URL:       item.Link,
+				Published: published,
+				Resolved:  strings.Contains(strings.ToUpper(item.Title), "RESOLVED"),
+			})
+		}
+	}
+	return events, errors.Join(errs...)
+}
+
+type gcpIncident struct {
+	ID           string        `json:"id"`
+	Begin        time.Time     `json:"begin"`
+	End          *time.Time    `json:"end"`
+	ExternalDesc string        `json:"external_desc"`
+	URI          string        `json:"uri"`
+	Locations    []gcpLocation `json:"currently_affected_locations"`
+	// PreviousLocations are the locations of resolved incidents, whose currently affected locations are empty
+	PreviousLocations []gcpLocation `json:"previously_affected_locations"`
+	Products          []struct {
+		Title string `json:"title"`
+	} `json:"affected_products"`
+	MostRecentUpdate struct {
+		Text string    `json:"text"`
+		When time.Time `json:"when"`
+	} `json:"most_recent_update"`
+}
+
+type gcpLocation struct {
+	ID string `json:"id"`
+}
+
+func (c *client) getGCPEvents(region string) ([]*Event, error) {
+	body, err := c.get(c.gcpStatusURL)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, nil
+	}
+
+	var incidents []gcpIncident
+	if err := json.Unmarshal(body, &incidents); err != nil {
+		return nil, fmt.Errorf("failed to parse GCP status feed: %w", err)
+	}
+
+	var events []*Event
+	for _, incident := range incidents {
+		resolved := incident.End != nil && !incident.End.IsZero()
+		if resolved && incident.End.Before(c.now().Add(-c.lookback)) {
+			continue
+		}
+		if !gcpIncidentAffects(incident, region) {
+			continue
+		}
+
+		var products []string
+		for _, product := range incident.Products {
+			products = append(products, product.Title)
+		}
+
+		published := incident.MostRecentUpdate.When
+		if published.IsZero() {
+			published = incident.Begin
+		}
+		events = append(events, &Event{
+			Provider:  "gcp",
+			Service:   strings.Join(products, ", "),
+			Region:    region,
+			Title:     strings.TrimSpace(incident.ExternalDesc),
+			Details:   strings.TrimSpace(incident.MostRecentUpdate.Text),
+			URL:       "https://status.cloud.google.com/" + strings.TrimPrefix(incident.URI, "/"),
+			Published: published,
+			Resolved:  resolved,
+		})
+	}
+	return events, nil
+}
+
+// gcpIncidentAffects returns true if the incident affects or affected the region, or is global
+func gcpIncidentAffects(incident gcpIncident, region string) bool {
+	for _, locations := range [][]gcpLocation{incident.Locations, incident.PreviousLocations} {
+		for _, location := range locations {
+			if location.ID == region || location.ID == "global" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *client) get(url string) ([]byte, error) {
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	// AWS doesn't publish a feed for services that aren't available in a region, treat it as no events
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d querying %s", resp.StatusCode, url)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,123 @@
+package cloudstatus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestServer(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func rss(items ...string) string {
+	out := `<?xml version="1.0"?><rss version="2.0"><channel>`
+	for _, item := range items {
+		out += item
+	}
+	return out + `</channel></rss>`
+}
+
+func TestGetEventsAWS(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/rss/ec2-us-east-1.rss": rss(
+			`<item><title>Increased API error rates</title><pubDate>Sat, 01 Jun 2024 10:00:00 GMT</pubDate><description>Investigating</description></item>`,
+			`<item><title>[RESOLVED] Old issue</title><pubDate>Mon, 20 May 2024 10:00:00 GMT</pubDate></item>`,
+		),
+		"/rss/route53.rss": rss(
+			`<item><title>[RESOLVED] DNS propagation delays</title><pubDate>Sat, 01 Jun 2024 11:00:00 GMT</pubDate></item>`,
+		),
+	})
+
+	c := NewClient().WithAWSStatusURL(server.URL + "/rss")
+	c.now = func() time.Time { return testNow }
+
+	events, err := c.GetEvents("aws", "us-east-1")
+	if err != nil {
+		t.Fatalf("GetEvents() unexpected error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("GetEvents() returned %d events, want 2: %+v", len(events), events)
+	}
+	if events[0].Service != "route53" || !events[0].Resolved {
+		t.Errorf("GetEvents() first event = %+v, want the resolved route53 event", events[0])
+	}
+	if events[1].Service != "ec2" || events[1].Resolved {
+		t.Errorf("GetEvents() second event = %+v, want the ongoing ec2 event", events[1])
+	}
+}
+
+func TestGetEventsAWSFailingFeed(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/rss/ec2-us-east-1.rss": rss(
+			`<item><title>Increased API error rates</title><pubDate>Sat, 01 Jun 2024 10:00:00 GMT</pubDate></item>`,
+		),
+		"/rss/s3-us-east-1.rss": `not a feed`,
+	})
+
+	c := NewClient().WithAWSStatusURL(server.URL + "/rss")
+	c.now = func() time.Time { return testNow }
+
+	events, err := c.GetEvents("aws", "us-east-1")
+	if err == nil {
+		t.Errorf("GetEvents() expected an error for the s3 feed")
+	}
+	if len(events) != 1 || events[0].Service != "ec2" {
+		t.Errorf("GetEvents() = %+v, want the ec2 event of the feeds which could be read", events)
+	}
+}
+
+func TestGetEventsGCP(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/incidents.json": `[
+			{"id": "1", "begin": "2024-06-01T09:00:00Z", "external_desc": "Compute Engine outage",
+			 "uri": "incidents/1", "currently_affected_locations": [{"id": "us-central1"}],
+			 "affected_products": [{"title": "Compute Engine"}],
+			 "most_recent_update": {"text": "Mitigating", "when": "2024-06-01T10:00:00Z"}},
+			{"id": "2", "begin": "2024-06-01T09:00:00Z", "external_desc": "Other region",
+			 "currently_affected_locations": [{"id": "europe-west1"}]},
+			{"id": "3", "begin": "2024-01-01T09:00:00Z", "end": "2024-01-01T10:00:00Z", "external_desc": "Old and resolved"},
+			{"id": "4", "begin": "2024-06-01T08:00:00Z", "end": "2024-06-01T09:00:00Z", "external_desc": "Resolved in the region",
+			 "currently_affected_locations": [], "previously_affected_locations": [{"id": "us-central1"}]},
+			{"id": "5", "begin": "2024-06-01T08:00:00Z", "end": "2024-06-01T09:00:00Z", "external_desc": "Resolved in another region",
+			 "currently_affected_locations": [], "previously_affected_locations": [{"id": "asia-east1"}]}
+		]`,
+	})
+
+	c := NewClient().WithGCPStatusURL(server.URL + "/incidents.json")
+	c.now = func() time.Time { return testNow }
+
+	events, err := c.GetEvents("gcp", "us-central1")
+	if err != nil {
+		t.Fatalf("GetEvents() unexpected error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("GetEvents() returned %d events, want 2: %+v", len(events), events)
+	}
+	if events[0].Title != "Compute Engine outage" || events[0].Service != "Compute Engine" || events[0].Resolved {
+		t.Errorf("GetEvents() first event = %+v, want the ongoing Compute Engine outage", events[0])
+	}
+	if events[1].Title != "Resolved in the region" || !events[1].Resolved {
+		t.Errorf("GetEvents() second event = %+v, want the incident resolved in the region", events[1])
+	}
+}
+
+func TestGetEventsUnsupportedProvider(t *testing.T) {
+	if _, err := NewClient().GetEvents("azure", "eastus"); err == nil {
+		t.Errorf("GetEvents() expected an error for an unsupported provider")
+	}
+}
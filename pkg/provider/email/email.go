@@ -0,0 +1,191 @@
+// Package email sends HTML emails through the SMTP relay configured in the osdctl config
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// SMTPHostConfigKey is the host:port of the SMTP relay, e.g. smtp.corp.example.com:587
+	SMTPHostConfigKey = "smtp_host"
+	// SMTPFromConfigKey is the address the emails are sent from
+	SMTPFromConfigKey = "smtp_from"
+	// SMTPUsernameConfigKey and SMTPPasswordConfigKey authenticate to the relay, which is used anonymously
+	// when no username is set
+	SMTPUsernameConfigKey = "smtp_username"
+	SMTPPasswordConfigKey = "smtp_password"
+
+	// lineLength is the length of the base64 lines, RFC 2045 allows at most 76 characters
+	lineLength = 76
+)
+
+// Config is the SMTP relay emails are sent through
+type Config struct {
+	Host     string
+	From     string
+	Username string
+	Password string
+}
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// Message is an email with an HTML body, and its plain text alternative for the clients not showing HTML
+type Message struct {
+	To          []string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []Attachment
+}
+
+// ConfigFromViper returns the SMTP relay of the osdctl config
+func ConfigFromViper() (Config, error) {
+	config := Config{
+		Host:     viper.GetString(SMTPHostConfigKey),
+		From:     viper.GetString(SMTPFromConfigKey),
+		Username: viper.GetString(SMTPUsernameConfigKey),
+		Password: viper.GetString(SMTPPasswordConfigKey),
+	}
+	if config.Host == "" || config.From == "" {
+		return config, fmt.Errorf("no SMTP relay configured, set `%s` (host:port) and `%s` in the osdctl config", SMTPHostConfigKey, SMTPFromConfigKey)
+	}
+	if _, _, err := net.SplitHostPort(config.Host); err != nil {
+		return config, fmt.Errorf("invalid %s '%s', expected host:port: %w", SMTPHostConfigKey, config.Host, err)
+	}
+	return config, nil
+}
+
+// Send sends the message through the relay. The connection is upgraded with STARTTLS when the relay supports
+// it, and the credentials are only sent over TLS.
+func Send(config Config, message Message) error {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return fmt.Errorf("invalid %s '%s': %w", SMTPFromConfigKey, config.From, err)
+	}
+	recipients, err := parseAddresses(message.To)
+	if err != nil {
+		return err
+	}
+	content, err := Build(from, recipients, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if config.Username != "" {
+		host, _, _ := net.SplitHostPort(config.Host)
+		auth = smtp.PlainAuth("", config.Username, config.Password, host)
+	}
+	to := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		to = append(to, recipient.Address)
+	}
+	if err := smtp.SendMail(config.Host, auth, from.Address, to, content); err != nil {
+		return fmt.Errorf("failed to send the email through %s: %w", config.Host, err)
+	}
+	return nil
+}
+
+func parseAddresses(addresses []string) ([]*mail.Address, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no recipient")
+	}
+	recipients := make([]*mail.Address, 0, len(addresses))
+	for _, address := range addresses {
+		recipient, err := mail.ParseAddress(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient '%s': %w", address, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
+
+// Build returns the MIME content of the message: the text and HTML alternatives, followed by the attachments
+func Build(from *mail.Address, to []*mail.Address, message Message, now time.Time) ([]byte, error) {
+	if strings.ContainsAny(message.Subject, "\r\n") {
+		return nil, fmt.Errorf("the subject can't span several lines")
+	}
+	mixed, err := boundary()
+	if err != nil {
+		return nil, err
+	}
+	alternative, err := boundary()
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]string, 0, len(to))
+	for _, recipient := range to {
+		recipients = append(recipients, recipient.String())
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mixed)
+
+	fmt.Fprintf(&b, "--%s\r\n", mixed)
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", alternative)
+	writePart(&b, alternative, "text/plain; charset=utf-8", "", []byte(message.Text))
+	writePart(&b, alternative, "text/html; charset=utf-8", "", []byte(message.HTML))
+	fmt.Fprintf(&b, "--%s--\r\n", alternative)
+
+	for _, attachment := range message.Attachments {
+		if strings.ContainsAny(attachment.FileName, "\"\r\n") {
+			return nil, fmt.Errorf("invalid attachment name '%s'", attachment.FileName)
+		}
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		writePart(&b, mixed, contentType, attachment.FileName, attachment.Content)
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", mixed)
+	return b.Bytes(), nil
+}
+
+// writePart writes a base64 encoded part, as an attachment when it has a file name
+func writePart(b *bytes.Buffer, boundary string, contentType string, fileName string, content []byte) {
+	fmt.Fprintf(b, "--%s\r\n", boundary)
+	fmt.Fprintf(b, "Content-Type: %s\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	if fileName != "" {
+		fmt.Fprintf(b, "Content-Disposition: attachment; filename=%q\r\n", fileName)
+	}
+	b.WriteString("\r\n")
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > lineLength {
+		b.WriteString(encoded[:lineLength] + "\r\n")
+		encoded = encoded[lineLength:]
+	}
+	b.WriteString(encoded + "\r\n")
+}
+
+func boundary() (string, error) {
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return "osdctl-" + hex.EncodeToString(random), nil
+}
@@ -0,0 +1,78 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuild(t *testing.T) {
+	from := &mail.Address{Name: "osdctl", Address: "osdctl@example.com"}
+	to := []*mail.Address{{Address: "alice@example.com"}, {Address: "bob@example.com"}}
+	message := Message{
+		Subject: "fleet-health report of March 12, 2024 – ROSA",
+		HTML:    "<p>report</p>",
+		Text:    "report",
+		Attachments: []Attachment{{
+			FileName:    "fleet-health.json",
+			ContentType: "application/json",
+			Content:     []byte(strings.Repeat(`{"name": "us-east-1"}`, 10)),
+		}},
+	}
+
+	content, err := Build(from, to, message, time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := mail.ReadMessage(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != message.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, message.Subject)
+	}
+	if got := parsed.Header.Get("To"); got != "<alice@example.com>, <bob@example.com>" {
+		t.Errorf("To = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %s (%v), want multipart/mixed", mediaType, err)
+	}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, strings.Split(part.Header.Get("Content-Type"), ";")[0])
+		if part.FileName() == "fleet-health.json" {
+			attachment, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(attachment, message.Attachments[0].Content) {
+				t.Errorf("attachment = %q, want %q", attachment, message.Attachments[0].Content)
+			}
+		}
+	}
+	if strings.Join(types, ",") != "multipart/alternative,application/json" {
+		t.Errorf("parts = %v, want the alternatives then the attachment", types)
+	}
+
+	message.Subject = "report\r\nBcc: eve@example.com"
+	if _, err := Build(from, to, message, time.Now()); err == nil {
+		t.Errorf("Build() accepted a subject injecting a header")
+	}
+}
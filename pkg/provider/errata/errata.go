@@ -0,0 +1,228 @@
+package errata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultUpgradeGraphURL = "https://api.openshift.com/api/upgrades_info/v1/graph"
+	DefaultSecurityDataURL = "https://access.redhat.com/hydra/rest/securitydata"
+)
+
+// severities from the least to the most severe, as used by the Red Hat security data API
+var severities = []string{"low", "moderate", "important", "critical"}
+
+// Advisory is a security advisory (RHSA) shipped with an OpenShift release
+type Advisory struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// FixedIn is the first release of the stream shipping the advisory
+	FixedIn string `json:"fixed_in"`
+	CVEs    []*CVE `json:"cves"`
+}
+
+// CVE is a vulnerability fixed by an advisory
+type CVE struct {
+	ID          string  `json:"id"`
+	Severity    string  `json:"severity"`
+	CVSS3Score  float64 `json:"cvss3_score"`
+	Description string  `json:"description"`
+	PublicDate  string  `json:"public_date"`
+}
+
+type client struct {
+	httpClient      *http.Client
+	upgradeGraphURL string
+	securityDataURL string
+	arch            string
+}
+
+func NewClient() *client {
+	return &client{
+		httpClient:      &http.Client{Timeout: 30 * time.Second},
+		upgradeGraphURL: DefaultUpgradeGraphURL,
+		securityDataURL: DefaultSecurityDataURL,
+		arch:            "amd64",
+	}
+}
+
+func (c *client) WithUpgradeGraphURL(url string) *client {
+	c.upgradeGraphURL = url
+	return c
+}
+
+func (c *client) WithSecurityDataURL(url string) *client {
+	c.securityDataURL = url
+	return c
+}
+
+func (c *client) WithArch(arch string) *client {
+	c.arch = arch
+	return c
+}
+
+type upgradeGraph struct {
+	Nodes []struct {
+		Version  string            `json:"version"`
+		Metadata map[string]string `json:"metadata"`
+	} `json:"nodes"`
+}
+
+// GetMissingAdvisories returns the security advisories shipped by the releases of the channel newer than
+// version in the same minor stream, i.e. the advisories a cluster running version hasn't applied yet.
+// Advisories are sorted by release, oldest first.
+func (c *client) GetMissingAdvisories(channel string, version string) ([]*Advisory, error) {
+	current, err := parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"channel": {channel}, "arch": {c.arch}}
+	var graph upgradeGraph
+	if err := c.getJSON(c.upgradeGraphURL+"?"+query.Encode(), &graph); err != nil {
+		return nil, fmt.Errorf("failed to get the upgrade graph of channel %s: %w", channel, err)
+	}
+
+	var advisories []*Advisory
+	for _, node := range graph.Nodes {
+		release, err := parseVersion(node.Version)
+		if err != nil || release[0] != current[0] || release[1] != current[1] || release[2] <= current[2] {
+			continue
+		}
+		errataURL := node.Metadata["url"]
+		id := path.Base(errataURL)
+		if !strings.HasPrefix(id, "RHSA-") {
+			// Bug fix (RHBA) and enhancement (RHEA) advisories don't fix CVEs
+			continue
+		}
+		advisories = append(advisories, &Advisory{ID: id, URL: errataURL, FixedIn: node.Version})
+	}
+
+	sort.Slice(advisories, func(i, j int) bool {
+		return CompareVersions(advisories[i].FixedIn, advisories[j].FixedIn) < 0
+	})
+
+	for _, advisory := range advisories {
+		if advisory.CVEs, err = c.getAdvisoryCVEs(advisory.ID); err != nil {
+			return nil, err
+		}
+	}
+	return advisories, nil
+}
+
+type securityDataCVE struct {
+	CVE                 string `json:"CVE"`
+	Severity            string `json:"severity"`
+	CVSS3Score          string `json:"cvss3_score"`
+	BugzillaDescription string `json:"bugzilla_description"`
+	PublicDate          string `json:"public_date"`
+}
+
+func (c *client) getAdvisoryCVEs(advisoryID string) ([]*CVE, error) {
+	var response []securityDataCVE
+	if err := c.getJSON(fmt.Sprintf("%s/cve.json?%s", c.securityDataURL, url.Values{"advisory": {advisoryID}}.Encode()), &response); err != nil {
+		return nil, fmt.Errorf("failed to get the CVEs of %s: %w", advisoryID, err)
+	}
+
+	cves := make([]*CVE, 0, len(response))
+	for _, cve := range response {
+		score, _ := strconv.ParseFloat(cve.CVSS3Score, 64)
+		cves = append(cves, &CVE{
+			ID:          cve.CVE,
+			Severity:    strings.ToLower(cve.Severity),
+			CVSS3Score:  score,
+			Description: cve.BugzillaDescription,
+			PublicDate:  cve.PublicDate,
+		})
+	}
+	sort.SliceStable(cves, func(i, j int) bool {
+		if SeverityRank(cves[i].Severity) != SeverityRank(cves[j].Severity) {
+			return SeverityRank(cves[i].Severity) > SeverityRank(cves[j].Severity)
+		}
+		return cves[i].CVSS3Score > cves[j].CVSS3Score
+	})
+	return cves, nil
+}
+
+func (c *client) getJSON(url string, target interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned %s: %s", url, resp.Status, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// SeverityRank orders severities, unknown severities rank below "low"
+func SeverityRank(severity string) int {
+	for i, s := range severities {
+		if strings.EqualFold(s, severity) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// IsValidSeverity returns whether severity is one of the Red Hat severities
+func IsValidSeverity(severity string) bool {
+	return SeverityRank(severity) > 0
+}
+
+// Severities returns the Red Hat severities, from the least to the most severe
+func Severities() []string {
+	return append([]string{}, severities...)
+}
+
+// parseVersion parses the major, minor and patch numbers of an OpenShift version like 4.14.10
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+	// Ignore pre-release and build metadata, e.g. 4.15.0-rc.1
+	core, _, _ := strings.Cut(strings.TrimPrefix(version, "openshift-v"), "-")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return parsed, fmt.Errorf("invalid version '%s'", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, fmt.Errorf("invalid version '%s'", version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+// CompareVersions compares two OpenShift versions, invalid versions sort first
+func CompareVersions(a string, b string) int {
+	versionA, _ := parseVersion(a)
+	versionB, _ := parseVersion(b)
+	for i := range versionA {
+		if versionA[i] != versionB[i] {
+			if versionA[i] < versionB[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package errata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetMissingAdvisories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/graph":
+			if r.URL.Query().Get("channel") != "stable-4.14" {
+				t.Errorf("unexpected channel %s", r.URL.Query().Get("channel"))
+			}
+			_, _ = w.Write([]byte(`{"nodes":[
+				{"version":"4.14.8","metadata":{"url":"https://access.redhat.com/errata/RHSA-2024:0001"}},
+				{"version":"4.14.10","metadata":{"url":"https://access.redhat.com/errata/RHSA-2024:0010"}},
+				{"version":"4.14.12","metadata":{"url":"https://access.redhat.com/errata/RHBA-2024:0012"}},
+				{"version":"4.14.11","metadata":{"url":"https://access.redhat.com/errata/RHSA-2024:0011"}},
+				{"version":"4.15.0","metadata":{"url":"https://access.redhat.com/errata/RHSA-2024:0100"}}
+			]}`))
+		case "/securitydata/cve.json":
+			cves := map[string][]securityDataCVE{
+				"RHSA-2024:0010": {
+					{CVE: "CVE-2024-1", Severity: "moderate", CVSS3Score: "5.3"},
+					{CVE: "CVE-2024-2", Severity: "important", CVSS3Score: "7.5"},
+				},
+				"RHSA-2024:0011": {
+					{CVE: "CVE-2024-3", Severity: "critical", CVSS3Score: "9.8"},
+				},
+			}
+			_ = json.NewEncoder(w).Encode(cves[r.URL.Query().Get("advisory")])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	advisories, err := NewClient().
+		WithUpgradeGraphURL(server.URL+"/graph").
+		WithSecurityDataURL(server.URL+"/securitydata").
+		GetMissingAdvisories("stable-4.14", "4.14.9")
+	if err != nil {
+		t.Fatalf("GetMissingAdvisories() error = %v", err)
+	}
+
+	// 4.14.8 is already applied, RHBA fixes no CVE and 4.15.0 is another stream
+	if len(advisories) != 2 {
+		t.Fatalf("got %d advisories, want 2", len(advisories))
+	}
+	if advisories[0].ID != "RHSA-2024:0010" || advisories[0].FixedIn != "4.14.10" || advisories[1].ID != "RHSA-2024:0011" {
+		t.Errorf("unexpected advisories or order: %s (%s), %s", advisories[0].ID, advisories[0].FixedIn, advisories[1].ID)
+	}
+	if len(advisories[0].CVEs) != 2 || advisories[0].CVEs[0].ID != "CVE-2024-2" || advisories[0].CVEs[0].CVSS3Score != 7.5 {
+		t.Errorf("CVEs should be sorted by severity: %+v", advisories[0].CVEs)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "4.14.9", b: "4.14.10", want: -1},
+		{a: "4.15.0", b: "4.14.10", want: 1},
+		{a: "4.14.10", b: "4.14.10", want: 0},
+		{a: "4.15.0-rc.1", b: "4.15.0", want: 0},
+		{a: "openshift-v4.14.3", b: "4.14.2", want: 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if !(SeverityRank("Critical") > SeverityRank("important") && SeverityRank("important") > SeverityRank("moderate") &&
+		SeverityRank("moderate") > SeverityRank("low") && SeverityRank("low") > SeverityRank("unknown")) {
+		t.Error("severities are not ranked from critical to low")
+	}
+}
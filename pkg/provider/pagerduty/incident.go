@@ -0,0 +1,92 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// IncidentDetails is an incident with the service it was raised on and the details of its alerts, which tell the
+// cluster it's about
+type IncidentDetails struct {
+	ID          string
+	Title       string
+	ServiceID   string
+	ServiceName string
+	// AlertDetails are the custom details of each alert of the incident, the values which aren't strings are
+	// kept as JSON
+	AlertDetails []map[string]string
+}
+
+// GetIncidentDetails returns the incident and the custom details of its alerts
+func (c *client) GetIncidentDetails(incidentID string) (*IncidentDetails, error) {
+	var incident struct {
+		Incident struct {
+			ID      string `json:"id"`
+			Title   string `json:"title"`
+			Service struct {
+				ID      string `json:"id"`
+				Summary string `json:"summary"`
+			} `json:"service"`
+		} `json:"incident"`
+	}
+	if err := c.getJSON(fmt.Sprintf("/incidents/%s", incidentID), &incident); err != nil {
+		return nil, fmt.Errorf("failed to get incident %s: %w", incidentID, err)
+	}
+
+	var alerts struct {
+		Alerts []struct {
+			Body struct {
+				Details    json.RawMessage `json:"details"`
+				CEFDetails struct {
+					Details json.RawMessage `json:"details"`
+				} `json:"cef_details"`
+			} `json:"body"`
+		} `json:"alerts"`
+	}
+	if err := c.getJSON(fmt.Sprintf("/incidents/%s/alerts", incidentID), &alerts); err != nil {
+		return nil, fmt.Errorf("failed to get the alerts of incident %s: %w", incidentID, err)
+	}
+
+	details := &IncidentDetails{
+		ID:          incident.Incident.ID,
+		Title:       incident.Incident.Title,
+		ServiceID:   incident.Incident.Service.ID,
+		ServiceName: incident.Incident.Service.Summary,
+	}
+	for _, alert := range alerts.Alerts {
+		fields := flattenAlertDetails(alert.Body.CEFDetails.Details)
+		// The details of the event take precedence over the ones PagerDuty derived from them
+		for key, value := range flattenAlertDetails(alert.Body.Details) {
+			fields[key] = value
+		}
+		details.AlertDetails = append(details.AlertDetails, fields)
+	}
+	return details, nil
+}
+
+// flattenAlertDetails returns the details of an alert by key. Details sent as plain text are kept under "details".
+func flattenAlertDetails(raw json.RawMessage) map[string]string {
+	fields := map[string]string{}
+	if len(raw) == 0 {
+		return fields
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if text != "" {
+			fields["details"] = text
+		}
+		return fields
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return fields
+	}
+	for key, value := range object {
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			text = string(value)
+		}
+		fields[key] = text
+	}
+	return fields
+}
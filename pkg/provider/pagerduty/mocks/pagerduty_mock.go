@@ -35,6 +35,51 @@ func (m *MockpdClientInterface) EXPECT() *MockpdClientInterfaceMockRecorder {
 	return m.recorder
 }
 
+// CreateIncidentNoteWithContext mocks base method.
+func (m *MockpdClientInterface) CreateIncidentNoteWithContext(arg0 context.Context, arg1 string, arg2 go_pagerduty.IncidentNote) (*go_pagerduty.IncidentNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncidentNoteWithContext", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*go_pagerduty.IncidentNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIncidentNoteWithContext indicates an expected call of CreateIncidentNoteWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) CreateIncidentNoteWithContext(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentNoteWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).CreateIncidentNoteWithContext), arg0, arg1, arg2)
+}
+
+// GetCurrentUserWithContext mocks base method.
+func (m *MockpdClientInterface) GetCurrentUserWithContext(arg0 context.Context, arg1 go_pagerduty.GetCurrentUserOptions) (*go_pagerduty.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentUserWithContext", arg0, arg1)
+	ret0, _ := ret[0].(*go_pagerduty.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentUserWithContext indicates an expected call of GetCurrentUserWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) GetCurrentUserWithContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUserWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).GetCurrentUserWithContext), arg0, arg1)
+}
+
+// ListEscalationPoliciesWithContext mocks base method.
+func (m *MockpdClientInterface) ListEscalationPoliciesWithContext(arg0 context.Context, arg1 go_pagerduty.ListEscalationPoliciesOptions) (*go_pagerduty.ListEscalationPoliciesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEscalationPoliciesWithContext", arg0, arg1)
+	ret0, _ := ret[0].(*go_pagerduty.ListEscalationPoliciesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEscalationPoliciesWithContext indicates an expected call of ListEscalationPoliciesWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) ListEscalationPoliciesWithContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEscalationPoliciesWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListEscalationPoliciesWithContext), arg0, arg1)
+}
+
 // ListIncidentsWithContext mocks base method.
 func (m *MockpdClientInterface) ListIncidentsWithContext(arg0 context.Context, arg1 go_pagerduty.ListIncidentsOptions) (*go_pagerduty.ListIncidentsResponse, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +95,21 @@ func (mr *MockpdClientInterfaceMockRecorder) ListIncidentsWithContext(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentsWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListIncidentsWithContext), arg0, arg1)
 }
 
+// ListOnCallsWithContext mocks base method.
+func (m *MockpdClientInterface) ListOnCallsWithContext(arg0 context.Context, arg1 go_pagerduty.ListOnCallOptions) (*go_pagerduty.ListOnCallsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOnCallsWithContext", arg0, arg1)
+	ret0, _ := ret[0].(*go_pagerduty.ListOnCallsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOnCallsWithContext indicates an expected call of ListOnCallsWithContext.
+func (mr *MockpdClientInterfaceMockRecorder) ListOnCallsWithContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOnCallsWithContext", reflect.TypeOf((*MockpdClientInterface)(nil).ListOnCallsWithContext), arg0, arg1)
+}
+
 // ListServicesWithContext mocks base method.
 func (m *MockpdClientInterface) ListServicesWithContext(arg0 context.Context, arg1 go_pagerduty.ListServiceOptions) (*go_pagerduty.ListServiceResponse, error) {
 	m.ctrl.T.Helper()
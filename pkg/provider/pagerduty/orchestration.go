@@ -0,0 +1,342 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+)
+
+// APIURL is the REST API of PagerDuty
+const APIURL = "https://api.pagerduty.com"
+
+// ServiceOrchestration is the event orchestration of a service: the rules PagerDuty applies to the events sent
+// to the service before they create incidents
+type ServiceOrchestration struct {
+	Active   bool                   `json:"-"`
+	Sets     []OrchestrationRuleSet `json:"sets"`
+	CatchAll struct {
+		Actions OrchestrationActions `json:"actions"`
+	} `json:"catch_all"`
+}
+
+// OrchestrationRuleSet is a set of rules, the first rule matching an event applies
+type OrchestrationRuleSet struct {
+	ID    string              `json:"id"`
+	Rules []OrchestrationRule `json:"rules"`
+}
+
+type OrchestrationRule struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	Disabled bool   `json:"disabled"`
+	// Conditions match when any of them matches, a rule without conditions matches every event
+	Conditions []struct {
+		Expression string `json:"expression"`
+	} `json:"conditions"`
+	Actions OrchestrationActions `json:"actions"`
+}
+
+// OrchestrationActions are the actions of a rule affecting how the event pages, the others are left out
+type OrchestrationActions struct {
+	// RouteTo is the ID of the rule set evaluated next
+	RouteTo     string `json:"route_to,omitempty"`
+	Suppress    bool   `json:"suppress,omitempty"`
+	Suspend     *uint  `json:"suspend,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	EventAction string `json:"event_action,omitempty"`
+}
+
+// OrchestrationMatch is a rule an event matched
+type OrchestrationMatch struct {
+	Set       string
+	Rule      string
+	Condition string
+}
+
+// OrchestrationResult explains what an orchestration does to an event
+type OrchestrationResult struct {
+	// Matches are the rules matched, in the order they were evaluated
+	Matches []OrchestrationMatch
+	// CatchAll is set when the last rule set evaluated had no matching rule
+	CatchAll bool
+	Actions  OrchestrationActions
+	// Undetermined are the conditions which couldn't be evaluated, they were assumed not to match
+	Undetermined []string
+	// UnknownFields are the fields tested by the undetermined conditions which the event doesn't have
+	UnknownFields []string
+}
+
+// unknownFieldError is returned when a condition tests a field of the event which wasn't given
+type unknownFieldError struct {
+	field string
+}
+
+func (e *unknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %s", e.field)
+}
+
+// GetClusterServices returns the services named after the base domain, with their escalation policies and
+// teams, whatever team they belong to
+func (c *client) GetClusterServices() ([]pd.Service, error) {
+	response, err := c.pdclient.ListServicesWithContext(context.TODO(), pd.ListServiceOptions{
+		Query:    c.baseDomain,
+		Includes: []string{"escalation_policies", "teams"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to ListServicesWithContext: %w", err)
+	}
+	return response.Services, nil
+}
+
+// GetServiceOrchestration returns the event orchestration of the service and whether it's active. The
+// orchestrations are read as plain JSON, only the rules and the actions which affect paging are kept.
+func (c *client) GetServiceOrchestration(serviceID string) (*ServiceOrchestration, error) {
+	var path struct {
+		OrchestrationPath ServiceOrchestration `json:"orchestration_path"`
+	}
+	if err := c.getJSON(fmt.Sprintf("/event_orchestrations/services/%s", serviceID), &path); err != nil {
+		return nil, fmt.Errorf("failed to get the event orchestration of service %s: %w", serviceID, err)
+	}
+	var active struct {
+		Active bool `json:"active"`
+	}
+	if err := c.getJSON(fmt.Sprintf("/event_orchestrations/services/%s/active", serviceID), &active); err != nil {
+		return nil, fmt.Errorf("failed to get the status of the event orchestration of service %s: %w", serviceID, err)
+	}
+	path.OrchestrationPath.Active = active.Active
+	return &path.OrchestrationPath, nil
+}
+
+func (c *client) getJSON(path string, v interface{}) error {
+	if c.httpClient == nil {
+		return fmt.Errorf("the PagerDuty client isn't initialized")
+	}
+	apiURL := c.apiURL
+	if apiURL == "" {
+		apiURL = APIURL
+	}
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Content-Type", "application/json")
+	if c.userToken != "" {
+		req.Header.Set("Authorization", "Token token="+c.userToken)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.oauthToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Evaluate runs the event through the rule sets, starting with the first one. The event is given as its
+// fields, e.g. "event.summary", as PagerDuty's conditions reference them.
+func (o *ServiceOrchestration) Evaluate(event map[string]string) OrchestrationResult {
+	result := OrchestrationResult{}
+	if len(o.Sets) == 0 {
+		result.CatchAll = true
+		result.Actions = o.CatchAll.Actions
+		return result
+	}
+	sets := map[string]OrchestrationRuleSet{}
+	for _, set := range o.Sets {
+		sets[set.ID] = set
+	}
+
+	visited := map[string]bool{}
+	setID := o.Sets[0].ID
+	for {
+		set, ok := sets[setID]
+		if !ok || visited[setID] {
+			return result
+		}
+		visited[setID] = true
+
+		rule, condition, matched := firstMatchingRule(set, event, &result)
+		if !matched {
+			result.CatchAll = true
+			result.Actions = mergeActions(result.Actions, o.CatchAll.Actions)
+			return result
+		}
+		label := rule.Label
+		if label == "" {
+			label = rule.ID
+		}
+		result.Matches = append(result.Matches, OrchestrationMatch{Set: setID, Rule: label, Condition: condition})
+		result.Actions = mergeActions(result.Actions, rule.Actions)
+		if rule.Actions.RouteTo == "" {
+			return result
+		}
+		setID = rule.Actions.RouteTo
+	}
+}
+
+func firstMatchingRule(set OrchestrationRuleSet, event map[string]string, result *OrchestrationResult) (OrchestrationRule, string, bool) {
+	for _, rule := range set.Rules {
+		if rule.Disabled {
+			continue
+		}
+		if len(rule.Conditions) == 0 {
+			return rule, "every event", true
+		}
+		for _, condition := range rule.Conditions {
+			matched, err := EvaluateCondition(condition.Expression, event)
+			if err != nil {
+				result.Undetermined = append(result.Undetermined, fmt.Sprintf("%s: %v", condition.Expression, err))
+				var unknown *unknownFieldError
+				if errors.As(err, &unknown) && !slices.Contains(result.UnknownFields, unknown.field) {
+					result.UnknownFields = append(result.UnknownFields, unknown.field)
+				}
+				continue
+			}
+			if matched {
+				return rule, condition.Expression, true
+			}
+		}
+	}
+	return OrchestrationRule{}, "", false
+}
+
+// mergeActions applies the actions of a later rule over those of the previous ones
+func mergeActions(actions OrchestrationActions, next OrchestrationActions) OrchestrationActions {
+	actions.RouteTo = next.RouteTo
+	actions.Suppress = actions.Suppress || next.Suppress
+	if next.Suspend != nil {
+		actions.Suspend = next.Suspend
+	}
+	if next.Priority != "" {
+		actions.Priority = next.Priority
+	}
+	if next.Severity != "" {
+		actions.Severity = next.Severity
+	}
+	if next.EventAction != "" {
+		actions.EventAction = next.EventAction
+	}
+	return actions
+}
+
+// EvaluateCondition evaluates the common forms of the PagerDuty Condition Language: comparisons joined by
+// 'and' and 'or', without parentheses, where a comparison is '[not] <field> matches [part|regex] <value>' or
+// '[not] <field> exists'. Comparisons are case-sensitive, as in PagerDuty.
+func EvaluateCondition(expression string, event map[string]string) (bool, error) {
+	if strings.ContainsAny(outsideQuotes(expression), "()") {
+		return false, fmt.Errorf("parentheses aren't supported")
+	}
+	for _, alternative := range splitOutsideQuotes(expression, " or ") {
+		matched := true
+		for _, comparison := range splitOutsideQuotes(alternative, " and ") {
+			ok, err := evaluateComparison(strings.TrimSpace(comparison), event)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func evaluateComparison(comparison string, event map[string]string) (bool, error) {
+	negate := false
+	if rest, found := strings.CutPrefix(comparison, "not "); found {
+		negate = true
+		comparison = strings.TrimSpace(rest)
+	}
+
+	if field, found := strings.CutSuffix(comparison, " exists"); found {
+		_, ok := event[strings.TrimSpace(field)]
+		return ok != negate, nil
+	}
+
+	field, operand, found := strings.Cut(comparison, " matches ")
+	if !found {
+		return false, fmt.Errorf("unsupported comparison '%s'", comparison)
+	}
+	field = strings.TrimSpace(field)
+	value, ok := event[field]
+	if !ok {
+		return false, &unknownFieldError{field: field}
+	}
+
+	operand = strings.TrimSpace(operand)
+	var matched bool
+	switch {
+	case strings.HasPrefix(operand, "part "):
+		matched = strings.Contains(value, unquote(strings.TrimPrefix(operand, "part ")))
+	case strings.HasPrefix(operand, "regex "):
+		re, err := regexp.Compile(unquote(strings.TrimPrefix(operand, "regex ")))
+		if err != nil {
+			return false, fmt.Errorf("invalid regex: %w", err)
+		}
+		matched = re.MatchString(value)
+	default:
+		matched = value == unquote(operand)
+	}
+	return matched != negate, nil
+}
+
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// outsideQuotes returns the expression with the quoted values blanked out, byte for byte
+func outsideQuotes(expression string) string {
+	blanked := []byte(expression)
+	var quote byte
+	for i := range blanked {
+		switch {
+		case quote != 0:
+			if blanked[i] == quote {
+				quote = 0
+			}
+			blanked[i] = ' '
+		case blanked[i] == '\'' || blanked[i] == '"':
+			quote = blanked[i]
+		}
+	}
+	return string(blanked)
+}
+
+// splitOutsideQuotes splits the expression on the separator where it isn't part of a quoted value
+func splitOutsideQuotes(expression string, separator string) []string {
+	blanked := outsideQuotes(expression)
+	var parts []string
+	for {
+		index := strings.Index(blanked, separator)
+		if index < 0 {
+			return append(parts, expression)
+		}
+		parts = append(parts, expression[:index])
+		expression = expression[index+len(separator):]
+		blanked = blanked[index+len(separator):]
+	}
+}
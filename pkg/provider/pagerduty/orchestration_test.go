@@ -0,0 +1,98 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	event := map[string]string{
+		"event.summary":  "KubeAPIDown CRITICAL (1)",
+		"event.severity": "critical",
+	}
+	tests := []struct {
+		name       string
+		expression string
+		expected   bool
+		wantErr    bool
+	}{
+		{name: "matches", expression: "event.severity matches 'critical'", expected: true},
+		{name: "matches part", expression: "event.summary matches part 'KubeAPI'", expected: true},
+		{name: "case sensitive", expression: "event.summary matches part 'kubeapi'", expected: false},
+		{name: "regex", expression: `event.summary matches regex '^Kube.*Down'`, expected: true},
+		{name: "not", expression: "not event.summary matches part 'Etcd'", expected: true},
+		{name: "exists", expression: "event.severity exists", expected: true},
+		{name: "and", expression: "event.severity matches 'critical' and event.summary matches part 'Etcd'", expected: false},
+		{name: "or", expression: "event.summary matches part 'Etcd' or event.summary matches part 'KubeAPI'", expected: true},
+		{name: "quoted separator", expression: "event.summary matches part 'a or b'", expected: false},
+		{name: "unknown field", expression: "event.source matches 'cluster'", wantErr: true},
+		{name: "parentheses", expression: "(event.severity matches 'critical')", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := EvaluateCondition(tt.expression, event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if matched != tt.expected {
+				t.Errorf("EvaluateCondition() = %v, want %v", matched, tt.expected)
+			}
+		})
+	}
+}
+
+func TestServiceOrchestrationEvaluate(t *testing.T) {
+	var orchestration ServiceOrchestration
+	err := json.Unmarshal([]byte(`{
+		"sets": [
+			{"id": "start", "rules": [
+				{"label": "disabled", "disabled": true, "conditions": [], "actions": {"suppress": true}},
+				{"label": "source", "conditions": [{"expression": "event.source matches 'x'"}], "actions": {"suppress": true}},
+				{"label": "kube", "conditions": [{"expression": "event.summary matches part 'Kube'"}], "actions": {"route_to": "kube", "priority": "P1"}}
+			]},
+			{"id": "kube", "rules": [
+				{"label": "warnings", "conditions": [{"expression": "event.severity matches 'warning'"}], "actions": {"suppress": true}}
+			]}
+		],
+		"catch_all": {"actions": {"severity": "error"}}
+	}`), &orchestration)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		event    map[string]string
+		expected OrchestrationResult
+	}{
+		{
+			name:  "routed then suppressed",
+			event: map[string]string{"event.summary": "KubePodCrashLooping WARNING (1)", "event.severity": "warning", "event.source": "y"},
+			expected: OrchestrationResult{
+				Matches: []OrchestrationMatch{
+					{Set: "start", Rule: "kube", Condition: "event.summary matches part 'Kube'"},
+					{Set: "kube", Rule: "warnings", Condition: "event.severity matches 'warning'"},
+				},
+				Actions: OrchestrationActions{Suppress: true, Priority: "P1"},
+			},
+		},
+		{
+			name:  "catch-all",
+			event: map[string]string{"event.summary": "etcdDown CRITICAL (1)", "event.severity": "critical"},
+			expected: OrchestrationResult{
+				CatchAll:      true,
+				Actions:       OrchestrationActions{Severity: "error"},
+				Undetermined:  []string{"event.source matches 'x': unknown field event.source"},
+				UnknownFields: []string{"event.source"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := orchestration.Evaluate(tt.event); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Evaluate() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
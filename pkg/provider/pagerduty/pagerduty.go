@@ -3,36 +3,58 @@ package pagerduty
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"os"
 	"sort"
 	"strings"
 	"time"
 
 	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/osdctl/pkg/httpdebug"
+	"github.com/openshift/osdctl/pkg/interrupt"
+	"github.com/openshift/osdctl/pkg/rawdump"
+	"github.com/openshift/osdctl/pkg/utils"
 )
 
 const (
 	PagerDutyUserTokenConfigKey  = "pd_user_token"
 	PagerDutyOauthTokenConfigKey = "pd_oauth_token"
 	PagerDutyTeamIDsKey          = "team_ids"
+	PagerDutyUserEmailConfigKey  = "pd_user_email"
+
+	// MaxPageSize is the largest page of results the PagerDuty API returns
+	MaxPageSize = 100
+	// DefaultIncidentLimit caps the incidents listed by a query, so a pathological service can't make a
+	// command page for minutes
+	DefaultIncidentLimit = 1000
 )
 
 type IncidentOccurrenceTracker struct {
-	IncidentName   string
-	Count          int
-	LastOccurrence string
+	IncidentName   string `json:"incident_name"`
+	Count          int    `json:"count"`
+	LastOccurrence string `json:"last_occurrence"`
 }
 
 type pdClientInterface interface {
 	ListIncidentsWithContext(context.Context, pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error)
 	ListServicesWithContext(context.Context, pd.ListServiceOptions) (*pd.ListServiceResponse, error)
+	CreateIncidentNoteWithContext(context.Context, string, pd.IncidentNote) (*pd.IncidentNote, error)
+	GetCurrentUserWithContext(context.Context, pd.GetCurrentUserOptions) (*pd.User, error)
+	ListEscalationPoliciesWithContext(context.Context, pd.ListEscalationPoliciesOptions) (*pd.ListEscalationPoliciesResponse, error)
+	ListOnCallsWithContext(context.Context, pd.ListOnCallOptions) (*pd.ListOnCallsResponse, error)
 }
 
 type client struct {
-	pdclient   pdClientInterface
-	baseDomain string
-	teamIds    []string
-	userToken  string
-	oauthToken string
+	pdclient      pdClientInterface
+	baseDomain    string
+	teamIds       []string
+	userToken     string
+	oauthToken    string
+	incidentLimit int
+
+	// httpClient and apiURL serve the API calls the pd client doesn't cover
+	httpClient *http.Client
+	apiURL     string
 }
 
 func NewClient() *client {
@@ -59,6 +81,12 @@ func (c *client) WithOauthToken(token string) *client {
 	return c
 }
 
+// WithIncidentLimit caps the number of incidents listed by each query, DefaultIncidentLimit when not positive
+func (c *client) WithIncidentLimit(limit int) *client {
+	c.incidentLimit = limit
+	return c
+}
+
 func (c *client) Init() (*client, error) {
 	err := c.buildClient()
 	return c, err
@@ -68,17 +96,20 @@ func (c *client) buildClient() error {
 	// Leave both here to keep some backwards compatibility
 	// I'm not sure what the difference is, but if both are provided let's just
 	// default to using the User Token over the oauth token
-	if c.userToken != "" {
-		c.pdclient = pd.NewClient(c.userToken)
-		return nil
+	var pdClient *pd.Client
+	switch {
+	case c.userToken != "":
+		pdClient = pd.NewClient(c.userToken)
+	case c.oauthToken != "":
+		pdClient = pd.NewOAuthClient(c.oauthToken)
+	default:
+		return fmt.Errorf("Could not build PagerDuty Client - No configured tokens")
 	}
 
-	if c.oauthToken != "" {
-		c.pdclient = pd.NewOAuthClient(c.oauthToken)
-		return nil
-	}
-
-	return fmt.Errorf("Could not build PagerDuty Client - No configured tokens")
+	c.httpClient = &http.Client{Transport: interrupt.Wrap(httpdebug.Wrap(rawdump.Wrap(utils.LimitPagerDuty(http.DefaultTransport))))}
+	pdClient.HTTPClient = c.httpClient
+	c.pdclient = pdClient
+	return nil
 }
 
 func (c *client) GetPDServiceIDs() ([]string, error) {
@@ -96,68 +127,130 @@ func (c *client) GetPDServiceIDs() ([]string, error) {
 	return serviceIDS, nil
 }
 
-func (c *client) GetFiringAlertsForCluster(pdServiceIDs []string) (map[string][]pd.Incident, error) {
-	incidents := map[string][]pd.Incident{}
+// AddIncidentNote posts a note to the given incident. from is the email of the PagerDuty user the note is
+// attributed to, PagerDuty requires it when the client authenticates with an account level token.
+func (c *client) AddIncidentNote(incidentID string, content string, from string) error {
+	note := pd.IncidentNote{Content: content}
+	note.User.Summary = from
+	_, err := c.pdclient.CreateIncidentNoteWithContext(context.TODO(), incidentID, note)
+	if err != nil {
+		return fmt.Errorf("failed to add note to incident %s: %w", incidentID, err)
+	}
+	return nil
+}
 
-	var incidentLimit uint = 25
-	var incidentListOffset uint = 0
-	for _, pdServiceID := range pdServiceIDs {
-		for {
-			listIncidentsResponse, err := c.pdclient.ListIncidentsWithContext(
-				context.TODO(),
-				pd.ListIncidentsOptions{
-					ServiceIDs: []string{pdServiceID},
-					Statuses:   []string{"triggered", "acknowledged"},
-					SortBy:     "urgency:DESC",
-					Limit:      incidentLimit,
-					Offset:     incidentListOffset,
-				},
-			)
-			if err != nil {
-				return nil, err
-			}
+// GetCurrentUser returns the PagerDuty user the token belongs to
+func (c *client) GetCurrentUser() (*pd.User, error) {
+	user, err := c.pdclient.GetCurrentUserWithContext(context.TODO(), pd.GetCurrentUserOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the current PagerDuty user: %w", err)
+	}
+	return user, nil
+}
 
-			incidents[pdServiceID] = append(incidents[pdServiceID], listIncidentsResponse.Incidents...)
+// GetIncidents returns the incidents with the given statuses, most urgent first. When mine is set, only the
+// incidents assigned to the current user are returned, otherwise the incidents of the configured teams.
+func (c *client) GetIncidents(statuses []string, mine bool) ([]pd.Incident, error) {
+	options := pd.ListIncidentsOptions{
+		Statuses: statuses,
+		SortBy:   "urgency:DESC",
+	}
+	if mine {
+		user, err := c.GetCurrentUser()
+		if err != nil {
+			return nil, err
+		}
+		options.UserIDs = []string{user.ID}
+	} else {
+		options.TeamIDs = c.teamIds
+	}
 
-			if !listIncidentsResponse.More {
-				break
-			}
-			incidentListOffset += incidentLimit
+	return c.listIncidents(options, "the incidents")
+}
+
+// GetTeamIncidents returns the incidents of the configured teams created in the given window, whatever their status
+func (c *client) GetTeamIncidents(since time.Time, until time.Time) ([]pd.Incident, error) {
+	options := pd.ListIncidentsOptions{
+		TeamIDs:  c.teamIds,
+		Statuses: []string{"triggered", "acknowledged", "resolved"},
+		Since:    since.UTC().Format(time.RFC3339),
+		Until:    until.UTC().Format(time.RFC3339),
+		SortBy:   "created_at:asc",
+	}
+	return c.listIncidents(options, "the incidents of the teams")
+}
+
+// GetOnCalls returns the on-call shifts of the escalation policies of the configured teams overlapping the
+// given window, with the details of the users on call
+func (c *client) GetOnCalls(since time.Time, until time.Time) ([]pd.OnCall, error) {
+	var policyIDs []string
+	policyOptions := pd.ListEscalationPoliciesOptions{TeamIDs: c.teamIds, Limit: 25}
+	for {
+		response, err := c.pdclient.ListEscalationPoliciesWithContext(context.TODO(), policyOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list escalation policies: %w", err)
+		}
+		for _, policy := range response.EscalationPolicies {
+			policyIDs = append(policyIDs, policy.ID)
+		}
+		if !response.More {
+			break
+		}
+		policyOptions.Offset += policyOptions.Limit
+	}
+	if len(policyIDs) == 0 {
+		return nil, nil
+	}
+
+	var onCalls []pd.OnCall
+	onCallOptions := pd.ListOnCallOptions{
+		EscalationPolicyIDs: policyIDs,
+		Includes:            []string{"users"},
+		Since:               since.UTC().Format(time.RFC3339),
+		Until:               until.UTC().Format(time.RFC3339),
+		Limit:               100,
+	}
+	for {
+		response, err := c.pdclient.ListOnCallsWithContext(context.TODO(), onCallOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list on-calls: %w", err)
+		}
+		onCalls = append(onCalls, response.OnCalls...)
+		if !response.More {
+			return onCalls, nil
 		}
+		onCallOptions.Offset += onCallOptions.Limit
+	}
+}
+
+func (c *client) GetFiringAlertsForCluster(pdServiceIDs []string) (map[string][]pd.Incident, error) {
+	incidents := map[string][]pd.Incident{}
+	for _, pdServiceID := range pdServiceIDs {
+		serviceIncidents, err := c.listIncidents(pd.ListIncidentsOptions{
+			ServiceIDs: []string{pdServiceID},
+			Statuses:   []string{"triggered", "acknowledged"},
+			SortBy:     "urgency:DESC",
+		}, fmt.Sprintf("the firing alerts of service %s", pdServiceID))
+		if err != nil {
+			return nil, err
+		}
+		incidents[pdServiceID] = serviceIncidents
 	}
 	return incidents, nil
 }
 
 func (c *client) GetHistoricalAlertsForCluster(pdServiceIDs []string) (map[string][]*IncidentOccurrenceTracker, error) {
 
-	var currentOffset uint
-	var limit uint = 100
-	var incidents []pd.Incident
-	var ctx = context.TODO()
 	incidentMap := map[string][]*IncidentOccurrenceTracker{}
 
 	for _, pdServiceID := range pdServiceIDs {
-		for currentOffset = 0; true; currentOffset += limit {
-			liResponse, err := c.pdclient.ListIncidentsWithContext(
-				ctx,
-				pd.ListIncidentsOptions{
-					ServiceIDs: []string{pdServiceID},
-					Statuses:   []string{"resolved", "triggered", "acknowledged"},
-					Offset:     currentOffset,
-					Limit:      limit,
-					SortBy:     "created_at:desc",
-				},
-			)
-
-			if err != nil {
-				return nil, err
-			}
-
-			if len(liResponse.Incidents) == 0 {
-				break
-			}
-
-			incidents = append(incidents, liResponse.Incidents...)
+		incidents, err := c.listIncidents(pd.ListIncidentsOptions{
+			ServiceIDs: []string{pdServiceID},
+			Statuses:   []string{"resolved", "triggered", "acknowledged"},
+			SortBy:     "created_at:desc",
+		}, fmt.Sprintf("the historical alerts of service %s", pdServiceID))
+		if err != nil {
+			return nil, err
 		}
 
 		incidentCounter := make(map[string]*IncidentOccurrenceTracker)
@@ -210,3 +303,41 @@ func (c *client) GetHistoricalAlertsForCluster(pdServiceIDs []string) (map[strin
 	return incidentMap, nil
 
 }
+
+// pageSize returns the size of the pages to query up to limit incidents, the largest page when it fits
+func pageSize(limit int) uint {
+	if limit < MaxPageSize {
+		return uint(limit)
+	}
+	return MaxPageSize
+}
+
+// listIncidents pages through the incidents matching the options, up to the incident limit of the client.
+// A warning naming what was listed is printed when incidents are left out.
+func (c *client) listIncidents(options pd.ListIncidentsOptions, what string) ([]pd.Incident, error) {
+	limit := c.incidentLimit
+	if limit <= 0 {
+		limit = DefaultIncidentLimit
+	}
+	options.Limit = pageSize(limit)
+	options.Offset = 0
+
+	var incidents []pd.Incident
+	for {
+		response, err := c.pdclient.ListIncidentsWithContext(context.TODO(), options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list incidents: %w", err)
+		}
+		incidents = append(incidents, response.Incidents...)
+		if len(incidents) >= limit {
+			if response.More || len(incidents) > limit {
+				fmt.Fprintf(os.Stderr, "Warning: only the first %d incidents of %s were listed, raise the limit to list more\n", limit, what)
+			}
+			return incidents[:limit], nil
+		}
+		if !response.More {
+			return incidents, nil
+		}
+		options.Offset += options.Limit
+	}
+}
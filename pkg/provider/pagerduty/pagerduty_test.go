@@ -90,6 +90,79 @@ var _ = Describe("Tests the Pagerduty Provider", func() {
 			})
 		})
 
+		Context("AddIncidentNote", func() {
+			It("Posts the note to the incident as the given user", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				note := pd.IncidentNote{Content: "some context"}
+				note.User.Summary = "user@example.com"
+				m.EXPECT().CreateIncidentNoteWithContext(gomock.Any(), "Q1234", note).Return(&note, nil)
+				pdProvider.pdclient = m
+				Expect(pdProvider.AddIncidentNote("Q1234", "some context", "user@example.com")).To(Succeed())
+			})
+			It("Returns an error from the pd client if there's an error with the request", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().CreateIncidentNoteWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("Some Error"))
+				pdProvider.pdclient = m
+				err := pdProvider.AddIncidentNote("Q1234", "some context", "")
+				Expect(err).To(Not(BeNil()))
+				Expect(err.Error()).To(ContainSubstring("failed to add note to incident Q1234"))
+			})
+		})
+
+		Context("GetIncidents", func() {
+			It("Filters the incidents assigned to the current user", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(&pd.User{APIObject: pd.APIObject{ID: "PUSER"}}, nil)
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ interface{}, options pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error) {
+						Expect(options.UserIDs).To(Equal([]string{"PUSER"}))
+						Expect(options.TeamIDs).To(BeEmpty())
+						return &pd.ListIncidentsResponse{Incidents: []pd.Incident{generateIncident()}}, nil
+					})
+				pdProvider.WithTeamIdList([]string{"PTEAM"}).pdclient = m
+				incidents, err := pdProvider.GetIncidents([]string{"triggered"}, true)
+				Expect(err).To(BeNil())
+				Expect(incidents).To(HaveLen(1))
+			})
+			It("Lists the incidents of the teams across pages", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ interface{}, options pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error) {
+						Expect(options.TeamIDs).To(Equal([]string{"PTEAM"}))
+						Expect(options.Offset).To(BeZero())
+						return &pd.ListIncidentsResponse{Incidents: []pd.Incident{generateIncident()}, APIListObject: pd.APIListObject{More: true}}, nil
+					})
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ interface{}, options pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error) {
+						Expect(options.Offset).To(BeEquivalentTo(MaxPageSize))
+						return &pd.ListIncidentsResponse{Incidents: []pd.Incident{generateIncident()}}, nil
+					})
+				pdProvider.WithTeamIdList([]string{"PTEAM"}).pdclient = m
+				incidents, err := pdProvider.GetIncidents([]string{"triggered"}, false)
+				Expect(err).To(BeNil())
+				Expect(incidents).To(HaveLen(2))
+			})
+			It("Stops listing at the incident limit", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().ListIncidentsWithContext(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ interface{}, options pd.ListIncidentsOptions) (*pd.ListIncidentsResponse, error) {
+						Expect(options.Limit).To(BeEquivalentTo(2))
+						return &pd.ListIncidentsResponse{Incidents: []pd.Incident{generateIncident(), generateIncident()}, APIListObject: pd.APIListObject{More: true}}, nil
+					})
+				pdProvider.WithIncidentLimit(2).pdclient = m
+				incidents, err := pdProvider.GetIncidents([]string{"triggered"}, false)
+				Expect(err).To(BeNil())
+				Expect(incidents).To(HaveLen(2))
+			})
+			It("Returns an error when the current user can't be resolved", func() {
+				m := pdMock.NewMockpdClientInterface(ctrl)
+				m.EXPECT().GetCurrentUserWithContext(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("Some Error"))
+				pdProvider.pdclient = m
+				_, err := pdProvider.GetIncidents(nil, true)
+				Expect(err).To(Not(BeNil()))
+			})
+		})
+
 		Context("GetFiringAlertsForCluster", func() {
 			var emptyIncResponse, singleIncResponse, multipleIncResponse, multiplePageIncResponse *pd.ListIncidentsResponse
 
@@ -0,0 +1,52 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	SlackSigningSecretConfigKey = "slack_signing_secret"
+	// SlackUserMappingConfigKey maps Slack user IDs to the OCM usernames allowed to use the slash commands
+	SlackUserMappingConfigKey = "slack_user_mapping"
+
+	signatureVersion = "v0"
+	// maxRequestAge rejects replayed requests, as recommended by Slack
+	maxRequestAge = 5 * time.Minute
+)
+
+// VerifyRequestSignature checks the X-Slack-Signature header of a request sent by Slack against the
+// app's signing secret. See https://api.slack.com/authentication/verifying-requests-from-slack
+func VerifyRequestSignature(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	if signingSecret == "" {
+		return fmt.Errorf("no Slack signing secret configured, set `%s` in the osdctl config", SlackSigningSecretConfigKey)
+	}
+
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp '%s'", timestamp)
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return fmt.Errorf("request timestamp is too old")
+	}
+
+	signature := header.Get("X-Slack-Signature")
+	if !hmac.Equal([]byte(signature), []byte(sign(signingSecret, timestamp, body))) {
+		return fmt.Errorf("invalid request signature")
+	}
+	return nil
+}
+
+func sign(signingSecret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(signatureVersion + ":" + timestamp + ":"))
+	mac.Write(body)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
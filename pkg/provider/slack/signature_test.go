@@ -0,0 +1,44 @@
+package slack
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyRequestSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("command=%2Fosdctl&text=context+abc")
+
+	signedHeader := func(secret string, at time.Time) http.Header {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		header := http.Header{}
+		header.Set("X-Slack-Request-Timestamp", timestamp)
+		header.Set("X-Slack-Signature", sign(secret, timestamp, body))
+		return header
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  http.Header
+		wantErr bool
+	}{
+		{name: "valid", secret: "s3cr3t", header: signedHeader("s3cr3t", now)},
+		{name: "recent", secret: "s3cr3t", header: signedHeader("s3cr3t", now.Add(-time.Minute))},
+		{name: "wrong secret", secret: "s3cr3t", header: signedHeader("other", now), wantErr: true},
+		{name: "replayed", secret: "s3cr3t", header: signedHeader("s3cr3t", now.Add(-10*time.Minute)), wantErr: true},
+		{name: "missing headers", secret: "s3cr3t", header: http.Header{}, wantErr: true},
+		{name: "no secret configured", secret: "", header: signedHeader("", now), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyRequestSignature(tt.secret, tt.header, body, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyRequestSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
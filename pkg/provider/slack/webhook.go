@@ -0,0 +1,56 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	SlackWebhookURLConfigKey = "slack_webhook_url"
+
+	// maxMessageLength keeps messages well below Slack's limit so code blocks aren't cut by Slack itself
+	maxMessageLength = 3500
+)
+
+type webhookMessage struct {
+	Text string `json:"text"`
+}
+
+// PostWebhookMessage sends text to a Slack incoming webhook
+func PostWebhookMessage(webhookURL string, text string) error {
+	if webhookURL == "" {
+		return fmt.Errorf("no Slack webhook URL configured, set `%s` in the osdctl config", SlackWebhookURLConfigKey)
+	}
+
+	body, err := json.Marshal(webhookMessage{Text: text})
+	if err != nil {
+		return err
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post Slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("slack webhook returned %s: %s", resp.Status, string(respBody))
+	}
+	return nil
+}
+
+// FormatCodeBlock wraps content in a Slack code block under a title, truncating it if needed
+func FormatCodeBlock(title string, content string) string {
+	truncated := ""
+	if len(content) > maxMessageLength {
+		content = content[:maxMessageLength]
+		truncated = "\n_(truncated)_"
+	}
+	return fmt.Sprintf("*%s*\n```\n%s\n```%s", title, content, truncated)
+}
@@ -0,0 +1,197 @@
+// Package supportcase reads the support cases of the Red Hat Customer Portal through the Case Management API
+package supportcase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	DefaultAPIURL   = "https://api.access.redhat.com/support/v1"
+	DefaultTokenURL = "https://sso.redhat.com/auth/realms/redhat-external/protocol/openid-connect/token"
+
+	// OfflineTokenConfigKey is the Red Hat API offline token, generated at https://access.redhat.com/management/api
+	OfflineTokenConfigKey = "rh_offline_token"
+	OfflineTokenEnvVar    = "RH_OFFLINE_TOKEN"
+	OfflineTokenURL       = "https://access.redhat.com/management/api"
+
+	caseURLFormat = "https://access.redhat.com/support/cases/#/case/%s"
+	tokenClientID = "rhsm-api"
+	maxResults    = 100
+)
+
+// Case is a Customer Portal support case
+type Case struct {
+	CaseNumber       string    `json:"caseNumber"`
+	Summary          string    `json:"summary"`
+	Status           string    `json:"status"`
+	Severity         string    `json:"severity"`
+	Product          string    `json:"product"`
+	Version          string    `json:"version"`
+	ClusterID        string    `json:"openshiftClusterID"`
+	CreatedDate      time.Time `json:"createdDate"`
+	LastModifiedDate time.Time `json:"lastModifiedDate"`
+}
+
+// URL returns the link to the case in the Customer Portal
+func (c Case) URL() string {
+	return fmt.Sprintf(caseURLFormat, c.CaseNumber)
+}
+
+type client struct {
+	httpClient   *http.Client
+	apiURL       string
+	tokenURL     string
+	offlineToken string
+}
+
+// NewClient returns a Case Management API client authenticating with the offline token of
+// RH_OFFLINE_TOKEN, or rh_offline_token in the config
+func NewClient() *client {
+	offlineToken := os.Getenv(OfflineTokenEnvVar)
+	if offlineToken == "" {
+		offlineToken = viper.GetString(OfflineTokenConfigKey)
+	}
+	return &client{
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		apiURL:       DefaultAPIURL,
+		tokenURL:     DefaultTokenURL,
+		offlineToken: offlineToken,
+	}
+}
+
+// Configured returns true when the offline token is set, so the cases can be looked up
+func Configured() bool {
+	return NewClient().offlineToken != ""
+}
+
+func (c *client) WithAPIURL(url string) *client {
+	c.apiURL = url
+	return c
+}
+
+func (c *client) WithTokenURL(url string) *client {
+	c.tokenURL = url
+	return c
+}
+
+func (c *client) WithOfflineToken(token string) *client {
+	c.offlineToken = token
+	return c
+}
+
+// Init checks the client is configured
+func (c *client) Init() (*client, error) {
+	if c.offlineToken == "" {
+		return nil, fmt.Errorf("the Red Hat API offline token is not defined, set %s or '%s' in the config, it can be generated at %s", OfflineTokenEnvVar, OfflineTokenConfigKey, OfflineTokenURL)
+	}
+	return c, nil
+}
+
+type caseFilter struct {
+	AccountNumber string `json:"accountNumber"`
+	IncludeClosed bool   `json:"includeClosed"`
+	MaxResults    int    `json:"maxResults"`
+}
+
+type caseList struct {
+	Cases []Case `json:"cases"`
+}
+
+// GetOpenCases returns the open support cases of the customer account, by case number
+func (c *client) GetOpenCases(accountNumber string) ([]Case, error) {
+	if accountNumber == "" {
+		return nil, fmt.Errorf("the organization has no customer account number")
+	}
+	accessToken, err := c.accessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(caseFilter{AccountNumber: accountNumber, MaxResults: maxResults})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(http.MethodPost, c.apiURL+"/cases/filter", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	var list caseList
+	if err := c.do(request, &list); err != nil {
+		return nil, fmt.Errorf("failed to list the support cases of account %s: %w", accountNumber, err)
+	}
+	sort.SliceStable(list.Cases, func(i, j int) bool {
+		return list.Cases[i].CaseNumber < list.Cases[j].CaseNumber
+	})
+	return list.Cases, nil
+}
+
+// ForCluster returns the cases opened for the cluster, given any of its identifiers
+func ForCluster(cases []Case, clusterIDs ...string) []Case {
+	var matching []Case
+	for _, supportCase := range cases {
+		for _, id := range clusterIDs {
+			if id != "" && strings.EqualFold(strings.TrimSpace(supportCase.ClusterID), id) {
+				matching = append(matching, supportCase)
+				break
+			}
+		}
+	}
+	return matching
+}
+
+// accessToken exchanges the offline token for an access token
+func (c *client) accessToken() (string, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {tokenClientID},
+		"refresh_token": {c.offlineToken},
+	}
+	request, err := http.NewRequest(http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := c.do(request, &token); err != nil {
+		return "", fmt.Errorf("failed to exchange the Red Hat API offline token, it may have expired (generate a new one at %s): %w", OfflineTokenURL, err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("no access token was returned for the Red Hat API offline token")
+	}
+	return token.AccessToken, nil
+}
+
+func (c *client) do(request *http.Request, result interface{}) error {
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,90 @@
+package supportcase
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetOpenCases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if r.FormValue("refresh_token") != "offline" || r.FormValue("grant_type") != "refresh_token" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"access"}`))
+		case "/cases/filter":
+			if r.Header.Get("Authorization") != "Bearer access" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var filter caseFilter
+			if err := json.NewDecoder(r.Body).Decode(&filter); err != nil || filter.AccountNumber != "1234" || filter.IncludeClosed {
+				t.Errorf("unexpected filter %+v (%v)", filter, err)
+			}
+			_, _ = w.Write([]byte(`{"cases":[
+				{"caseNumber":"03900002","summary":"Upgrade stuck","status":"Waiting on Red Hat","severity":"2 (High)","openshiftClusterID":"abc-123"},
+				{"caseNumber":"03900001","summary":"Quota","status":"Waiting on Customer","severity":"4 (Low)"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cases, err := NewClient().
+		WithAPIURL(server.URL).
+		WithTokenURL(server.URL + "/token").
+		WithOfflineToken("offline").
+		GetOpenCases("1234")
+	if err != nil {
+		t.Fatalf("GetOpenCases() error = %v", err)
+	}
+	if len(cases) != 2 || cases[0].CaseNumber != "03900001" || cases[1].ClusterID != "abc-123" {
+		t.Errorf("unexpected cases or order: %+v", cases)
+	}
+
+	_, err = NewClient().
+		WithAPIURL(server.URL).
+		WithTokenURL(server.URL + "/token").
+		WithOfflineToken("expired").
+		GetOpenCases("1234")
+	if err == nil {
+		t.Errorf("GetOpenCases() with an invalid offline token should fail")
+	}
+}
+
+func TestForCluster(t *testing.T) {
+	cases := []Case{
+		{CaseNumber: "1", ClusterID: "ABC-123"},
+		{CaseNumber: "2"},
+		{CaseNumber: "3", ClusterID: "2abcdefg"},
+	}
+	tests := []struct {
+		name       string
+		clusterIDs []string
+		want       []string
+	}{
+		{name: "matches the external ID case-insensitively", clusterIDs: []string{"2abcdefg", "abc-123"}, want: []string{"1", "3"}},
+		{name: "empty identifiers don't match cases without a cluster", clusterIDs: []string{""}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range ForCluster(cases, tt.clusterIDs...) {
+				got = append(got, c.CaseNumber)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ForCluster() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ForCluster() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
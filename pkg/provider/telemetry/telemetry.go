@@ -0,0 +1,294 @@
+// Package telemetry reads the availability SLIs of the clusters from the centralized telemetry, through the
+// Prometheus API of Observatorium (Thanos), and turns them into error budgets
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/httpdebug"
+	"github.com/openshift/osdctl/pkg/interrupt"
+	"github.com/spf13/viper"
+)
+
+const (
+	DefaultURL = "https://observatorium.api.openshift.com/api/metrics/v1/telemeter"
+
+	// URLConfigKey is the Prometheus API of the telemetry, TokenConfigKey the bearer token to query it
+	URLConfigKey   = "telemetry_url"
+	TokenConfigKey = "telemetry_token"
+	TokenEnvVar    = "TELEMETRY_TOKEN"
+	// SLOsConfigKey overrides the default SLOs, e.g.
+	//
+	//	telemetry_slos:
+	//	  - name: API availability
+	//	    objective: 0.995
+	//	    query: <PromQL returning the ratio of good events, with {{.ClusterID}} and {{.Window}}>
+	SLOsConfigKey = "telemetry_slos"
+
+	// SLOPeriod is the period the error budgets are defined over
+	SLOPeriod = 30 * 24 * time.Hour
+	// CurrentWindow is the window of the current burn rate, which tells whether an incident is eating the
+	// error budget right now
+	CurrentWindow = time.Hour
+)
+
+// SLO is an availability objective, its query returns the ratio of good events of the cluster over the window
+type SLO struct {
+	Name      string  `json:"name" mapstructure:"name"`
+	Objective float64 `json:"objective" mapstructure:"objective"`
+	Query     string  `json:"query" mapstructure:"query"`
+}
+
+// DefaultSLOs are the availability SLIs recorded by the telemetry of every cluster
+var DefaultSLOs = []SLO{
+	{
+		Name:      "API availability",
+		Objective: 0.995,
+		Query:     `1 - (sum(sum_over_time(code:apiserver_request_total:rate:sum{_id="{{.ClusterID}}",code=~"5.."}[{{.Window}}])) or vector(0)) / sum(sum_over_time(code:apiserver_request_total:rate:sum{_id="{{.ClusterID}}"}[{{.Window}}]))`,
+	},
+	{
+		Name:      "Cluster operators available",
+		Objective: 0.99,
+		Query:     `avg_over_time(avg(cluster_operator_up{_id="{{.ClusterID}}"})[{{.Window}}:5m])`,
+	},
+}
+
+// SLOStatus is the SLI of an SLO over a window, and the error budget it burned
+type SLOStatus struct {
+	Name      string  `json:"name"`
+	Objective float64 `json:"objective"`
+	Window    string  `json:"window"`
+	// SLI is the ratio of good events over the window
+	SLI float64 `json:"sli"`
+	// BurnRate is how fast the error budget burned over the window, 1 burns it exactly in the SLO period
+	BurnRate float64 `json:"burn_rate"`
+	// BudgetRemaining is the ratio of the error budget of the SLO period left after the window
+	BudgetRemaining float64 `json:"budget_remaining"`
+	// CurrentBurnRate is the burn rate over the last CurrentWindow
+	CurrentBurnRate float64 `json:"current_burn_rate"`
+	// Error is set when the SLI couldn't be queried
+	Error string `json:"error,omitempty"`
+}
+
+// Impacting returns true when the error budget is currently burning faster than the SLO allows
+func (s SLOStatus) Impacting() bool {
+	return s.Error == "" && s.CurrentBurnRate > 1
+}
+
+type client struct {
+	httpClient *http.Client
+	url        string
+	token      string
+}
+
+// NewClient returns a client of the telemetry, authenticating with the token of TELEMETRY_TOKEN, or
+// telemetry_token in the config
+func NewClient() *client {
+	token := os.Getenv(TokenEnvVar)
+	if token == "" {
+		token = viper.GetString(TokenConfigKey)
+	}
+	apiURL := viper.GetString(URLConfigKey)
+	if apiURL == "" {
+		apiURL = DefaultURL
+	}
+	return &client{
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: interrupt.Wrap(httpdebug.Wrap(http.DefaultTransport))},
+		url:        strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+	}
+}
+
+// Configured returns true when the token is set, so the telemetry can be queried
+func Configured() bool {
+	return NewClient().token != ""
+}
+
+func (c *client) WithURL(url string) *client {
+	c.url = strings.TrimSuffix(url, "/")
+	return c
+}
+
+func (c *client) WithToken(token string) *client {
+	c.token = token
+	return c
+}
+
+// Init checks the client is configured
+func (c *client) Init() (*client, error) {
+	if c.token == "" {
+		return nil, fmt.Errorf("the telemetry token is not defined, set %s or '%s' in the config", TokenEnvVar, TokenConfigKey)
+	}
+	return c, nil
+}
+
+// ConfiguredSLOs returns the SLOs of the config, or the default ones
+func ConfiguredSLOs() ([]SLO, error) {
+	if !viper.IsSet(SLOsConfigKey) {
+		return DefaultSLOs, nil
+	}
+	var slos []SLO
+	if err := viper.UnmarshalKey(SLOsConfigKey, &slos); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SLOsConfigKey, err)
+	}
+	for _, slo := range slos {
+		if slo.Name == "" || slo.Query == "" || slo.Objective <= 0 || slo.Objective >= 1 {
+			return nil, fmt.Errorf("invalid SLO %+v in %s, a name, a query and an objective between 0 and 1 are required", slo, SLOsConfigKey)
+		}
+	}
+	return slos, nil
+}
+
+// GetSLOStatuses returns the statuses of the SLOs of the cluster, identified by its external ID in the
+// telemetry, over the window. The SLOs which can't be queried have their error set.
+func (c *client) GetSLOStatuses(slos []SLO, externalClusterID string, window time.Duration, at time.Time) []SLOStatus {
+	statuses := make([]SLOStatus, 0, len(slos))
+	for _, slo := range slos {
+		status := SLOStatus{Name: slo.Name, Objective: slo.Objective, Window: formatWindow(window)}
+		sli, err := c.querySLI(slo, externalClusterID, window, at)
+		if err != nil {
+			status.Error = err.Error()
+			statuses = append(statuses, status)
+			continue
+		}
+		currentSLI, err := c.querySLI(slo, externalClusterID, CurrentWindow, at)
+		if err != nil {
+			status.Error = err.Error()
+			statuses = append(statuses, status)
+			continue
+		}
+		statuses = append(statuses, ErrorBudget(slo, sli, currentSLI, window))
+	}
+	return statuses
+}
+
+// ErrorBudget returns the status of the SLO given its SLI over the window and over the last CurrentWindow
+func ErrorBudget(slo SLO, sli float64, currentSLI float64, window time.Duration) SLOStatus {
+	budget := 1 - slo.Objective
+	burnRate := (1 - sli) / budget
+	return SLOStatus{
+		Name:            slo.Name,
+		Objective:       slo.Objective,
+		Window:          formatWindow(window),
+		SLI:             sli,
+		BurnRate:        burnRate,
+		BudgetRemaining: 1 - burnRate*float64(window)/float64(SLOPeriod),
+		CurrentBurnRate: (1 - currentSLI) / budget,
+	}
+}
+
+type queryTemplateData struct {
+	ClusterID string
+	Window    string
+}
+
+func (c *client) querySLI(slo SLO, externalClusterID string, window time.Duration, at time.Time) (float64, error) {
+	tmpl, err := template.New(slo.Name).Parse(slo.Query)
+	if err != nil {
+		return 0, fmt.Errorf("invalid query of %s: %w", slo.Name, err)
+	}
+	var query bytes.Buffer
+	if err := tmpl.Execute(&query, queryTemplateData{ClusterID: externalClusterID, Window: formatWindow(window)}); err != nil {
+		return 0, fmt.Errorf("invalid query of %s: %w", slo.Name, err)
+	}
+	value, err := c.Query(query.String(), at)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", slo.Name, err)
+	}
+	return value, nil
+}
+
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Query runs an instant query returning a single value
+func (c *client) Query(query string, at time.Time) (float64, error) {
+	form := url.Values{
+		"query": {query},
+		"time":  {strconv.FormatInt(at.Unix(), 10)},
+	}
+	request, err := http.NewRequest(http.MethodPost, c.url+"/api/v1/query", strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Authorization", "Bearer "+c.token)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, err
+	}
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+		return 0, fmt.Errorf("unauthorized (%d), the telemetry token may have expired", response.StatusCode)
+	}
+
+	var result queryResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("unexpected response %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("%s: %s", result.ErrorType, result.Error)
+	}
+	if result.Data.ResultType != "vector" || len(result.Data.Result) == 0 {
+		return 0, fmt.Errorf("no data")
+	}
+	if len(result.Data.Result) > 1 {
+		return 0, fmt.Errorf("the query returned %d series, expected one", len(result.Data.Result))
+	}
+	return parseSampleValue(result.Data.Result[0].Value)
+}
+
+// parseSampleValue reads the value of a [timestamp, "value"] sample
+func parseSampleValue(sample []interface{}) (float64, error) {
+	if len(sample) != 2 {
+		return 0, fmt.Errorf("invalid sample %v", sample)
+	}
+	raw, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid sample value %v", sample[1])
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) {
+		return 0, fmt.Errorf("no data")
+	}
+	return value, nil
+}
+
+// formatWindow formats the window as a PromQL duration, e.g. 7d or 1h
+func formatWindow(window time.Duration) string {
+	if window%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", window/(24*time.Hour))
+	}
+	if window%time.Hour == 0 {
+		return fmt.Sprintf("%dh", window/time.Hour)
+	}
+	return fmt.Sprintf("%dm", window/time.Minute)
+}
@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestGetSLOStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		query := r.FormValue("query")
+		switch {
+		case strings.Contains(query, "missing"):
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		case strings.Contains(query, `_id="abc-123"`) && strings.Contains(query, "[7d]"):
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1709294400,"0.998"]}]}}`))
+		case strings.Contains(query, `_id="abc-123"`) && strings.Contains(query, "[1h]"):
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1709294400,"0.9"]}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unexpected query"}`))
+		}
+	}))
+	defer server.Close()
+
+	slos := []SLO{
+		{Name: "API availability", Objective: 0.99, Query: `avg_over_time(up{_id="{{.ClusterID}}"}[{{.Window}}])`},
+		{Name: "Missing", Objective: 0.99, Query: `missing{_id="{{.ClusterID}}"}`},
+	}
+	statuses := NewClient().WithURL(server.URL).WithToken("token").GetSLOStatuses(slos, "abc-123", 7*24*time.Hour, time.Unix(1709294400, 0))
+	if len(statuses) != 2 {
+		t.Fatalf("GetSLOStatuses() returned %d statuses, want 2", len(statuses))
+	}
+
+	api := statuses[0]
+	if api.Error != "" || api.SLI != 0.998 || api.Window != "7d" {
+		t.Errorf("unexpected API availability status %+v", api)
+	}
+	if !approximately(api.BurnRate, 0.2) || !approximately(api.CurrentBurnRate, 10) || !api.Impacting() {
+		t.Errorf("unexpected burn rates %+v", api)
+	}
+	if statuses[1].Error == "" || statuses[1].Impacting() {
+		t.Errorf("an SLO without data should have an error, got %+v", statuses[1])
+	}
+}
+
+func TestErrorBudget(t *testing.T) {
+	tests := []struct {
+		name          string
+		sli           float64
+		window        time.Duration
+		wantBurnRate  float64
+		wantRemaining float64
+	}{
+		{name: "no error", sli: 1, window: 7 * 24 * time.Hour, wantBurnRate: 0, wantRemaining: 1},
+		{name: "burning at the sustainable rate for the whole period", sli: 0.995, window: SLOPeriod, wantBurnRate: 1, wantRemaining: 0},
+		{name: "burning twice too fast for a week", sli: 0.99, window: 7 * 24 * time.Hour, wantBurnRate: 2, wantRemaining: 1 - 2*7.0/30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ErrorBudget(SLO{Name: "API", Objective: 0.995}, tt.sli, tt.sli, tt.window)
+			if !approximately(got.BurnRate, tt.wantBurnRate) || !approximately(got.BudgetRemaining, tt.wantRemaining) {
+				t.Errorf("ErrorBudget() = %+v, want a burn rate of %v and %v of the budget left", got, tt.wantBurnRate, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestConfiguredSLOs(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	slos, err := ConfiguredSLOs()
+	if err != nil || len(slos) != len(DefaultSLOs) {
+		t.Errorf("ConfiguredSLOs() = %v, %v, want the default SLOs", slos, err)
+	}
+
+	viper.Set(SLOsConfigKey, []map[string]interface{}{{"name": "Console", "objective": 0.99, "query": "console_up"}})
+	slos, err = ConfiguredSLOs()
+	if err != nil || len(slos) != 1 || slos[0].Name != "Console" || slos[0].Objective != 0.99 {
+		t.Errorf("ConfiguredSLOs() = %v, %v, want the configured SLO", slos, err)
+	}
+
+	viper.Set(SLOsConfigKey, []map[string]interface{}{{"name": "Console", "objective": 99, "query": "console_up"}})
+	if _, err := ConfiguredSLOs(); err == nil {
+		t.Error("ConfiguredSLOs() accepted an objective above 1")
+	}
+}
+
+func approximately(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
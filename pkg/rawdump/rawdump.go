@@ -0,0 +1,176 @@
+// Package rawdump saves the unmodified responses of the APIs osdctl calls, grouped by the collector which
+// made the call, to debug the differences between what osdctl shows and what the APIs return
+package rawdump
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/osdctl/pkg/redact"
+)
+
+const (
+	FlagName  = "save-raw"
+	FlagUsage = "Directory to write the unmodified OCM, PagerDuty, Jira and CloudTrail responses to, one directory per collector, along with the rendered output"
+
+	// IndexFile lists the saved responses in the order they were received
+	IndexFile = "index.jsonl"
+	// OtherCollector holds the responses no collector claims
+	OtherCollector = "other"
+)
+
+// Classifier returns the collector a request was made by, empty when it can't tell
+type Classifier func(req *http.Request) string
+
+// IndexEntry describes a saved response. URLs are redacted, the saved bodies are not: the responses of the
+// APIs osdctl calls don't carry credentials, but they do carry customer data and must be handled as such.
+type IndexEntry struct {
+	Sequence  int       `json:"sequence"`
+	Time      time.Time `json:"time"`
+	Collector string    `json:"collector"`
+	Method    string    `json:"method,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	Status    int       `json:"status,omitempty"`
+	File      string    `json:"file"`
+}
+
+var (
+	mu       sync.Mutex
+	dir      string
+	classify Classifier
+	sequence int
+
+	unsafeNameRE = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// Enable starts saving the responses to the directory. It must be called before the clients are built.
+func Enable(path string, classifier Classifier) error {
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return fmt.Errorf("failed to create the --%s directory: %w", FlagName, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	dir, classify, sequence = path, classifier, 0
+	return nil
+}
+
+// Enabled returns true if the responses are saved
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return dir != ""
+}
+
+// Dir returns the directory the responses are saved to, empty when they aren't
+func Dir() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return dir
+}
+
+// Wrap returns a transport saving every response received through next, or next itself when saving is off
+func Wrap(next http.RoundTripper) http.RoundTripper {
+	mu.Lock()
+	defer mu.Unlock()
+	if dir == "" {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next, classify: classify}
+}
+
+type transport struct {
+	next     http.RoundTripper
+	classify Classifier
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	collector := OtherCollector
+	if t.classify != nil {
+		if name := t.classify(req); name != "" {
+			collector = name
+		}
+	}
+	entry := IndexEntry{Collector: collector, Method: req.Method, URL: redact.URL(req.URL), Status: resp.StatusCode}
+	if err := save(entry, req.Method+" "+req.URL.Path, body); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save the raw response of %s %s: %v\n", req.Method, entry.URL, err)
+	}
+	return resp, nil
+}
+
+// WriteJSON saves data the collector got from an SDK which doesn't go through the wrapped transports, e.g.
+// the CloudTrail events of the AWS SDK, as JSON. It's a no-op when saving is off.
+func WriteJSON(collector string, name string, v interface{}) error {
+	if !Enabled() {
+		return nil
+	}
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return save(IndexEntry{Collector: collector}, name, content)
+}
+
+func save(entry IndexEntry, name string, content []byte) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if dir == "" {
+		return nil
+	}
+
+	entry.Collector = fileName(entry.Collector)
+	sequence++
+	entry.Sequence = sequence
+	entry.Time = time.Now().UTC()
+	entry.File = filepath.Join(entry.Collector, fmt.Sprintf("%03d-%s.json", sequence, fileName(name)))
+	if err := os.MkdirAll(filepath.Join(dir, entry.Collector), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, entry.File), content, 0o600); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	index, err := os.OpenFile(filepath.Join(dir, IndexFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	_, err = index.Write(append(line, '\n'))
+	return err
+}
+
+// fileName turns e.g. "GET /api/service_logs/v1/cluster_logs" into "GET-api-service_logs-v1-cluster_logs",
+// leading dots are trimmed so a name can't point out of the directory
+func fileName(name string) string {
+	name = strings.Trim(unsafeNameRE.ReplaceAllString(name, "-"), "-.")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return name
+}
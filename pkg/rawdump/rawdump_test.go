@@ -0,0 +1,88 @@
+package rawdump
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"kind": "ClusterList", "items": []}`))
+	}))
+	defer server.Close()
+
+	if Wrap(http.DefaultTransport) != http.DefaultTransport {
+		t.Errorf("Wrap() changed the transport while saving is off")
+	}
+
+	dir := t.TempDir()
+	if err := Enable(dir, func(req *http.Request) string {
+		if req.URL.Path == "/api/clusters_mgmt/v1/clusters" {
+			return "../clusters"
+		}
+		return ""
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { dir = "" }()
+
+	client := &http.Client{Transport: Wrap(http.DefaultTransport)}
+	for _, path := range []string{"/api/clusters_mgmt/v1/clusters?access_token=secret", "/other"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != `{"kind": "ClusterList", "items": []}` {
+			t.Errorf("Wrap() changed the response body to %q", body)
+		}
+	}
+	if err := WriteJSON("cloudtrail_events", "LookupEvents", map[string]string{"a": "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := os.Open(filepath.Join(dir, IndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+	var files []string
+	scanner := bufio.NewScanner(index)
+	for scanner.Scan() {
+		var entry IndexEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(entry.URL, "secret") {
+			t.Errorf("the index holds the URL unredacted: %s", entry.URL)
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.File)); err != nil {
+			t.Errorf("the saved response is missing: %v", err)
+		}
+		files = append(files, entry.File)
+	}
+	expected := []string{
+		filepath.Join("clusters", "001-GET-api-clusters_mgmt-v1-clusters.json"),
+		filepath.Join(OtherCollector, "002-GET-other.json"),
+		filepath.Join("cloudtrail_events", "003-LookupEvents.json"),
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("index = %v, want %v", files, expected)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("index[%d] = %s, want %s", i, files[i], expected[i])
+		}
+	}
+}
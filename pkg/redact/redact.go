@@ -0,0 +1,101 @@
+package redact
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// RedactConfigKey makes commands supporting --redact redact their output by default
+	RedactConfigKey = "redact"
+	RedactFlagName  = "redact"
+	RedactFlagUsage = "Mask customer email addresses, account names and IP addresses in the output, e.g. to paste it into a public channel. Defaults to the `redact` config value"
+
+	redactedEmail   = "<redacted-email>"
+	redactedIP      = "<redacted-ip>"
+	redactedAccount = "<redacted-account>"
+	redactedTerm    = "<redacted>"
+)
+
+var (
+	emailRE = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	ipv4RE  = regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])\b`)
+	// Only full, non-abbreviated addresses or ones containing "::" with at least two groups, to avoid matching timestamps
+	ipv6RE = regexp.MustCompile(`\b(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}\b|\b(?:[0-9a-fA-F]{1,4}:){1,6}:(?:[0-9a-fA-F]{1,4}:){0,5}[0-9a-fA-F]{1,4}\b`)
+	// AWS account IDs are 12 digits
+	awsAccountRE = regexp.MustCompile(`\b[0-9]{12}\b`)
+)
+
+// Redactor masks customer PII in text. Emails, IP addresses and AWS account IDs are always masked,
+// additional terms such as usernames or organization names can be provided by the caller.
+type Redactor struct {
+	terms []string
+}
+
+func New(terms ...string) *Redactor {
+	r := &Redactor{}
+	for _, term := range terms {
+		if strings.TrimSpace(term) != "" {
+			r.terms = append(r.terms, term)
+		}
+	}
+	// Replace longer terms first so a term contained in another one doesn't leave parts of it behind
+	sort.Slice(r.terms, func(i, j int) bool { return len(r.terms[i]) > len(r.terms[j]) })
+	return r
+}
+
+// Redact returns s with all PII masked
+func (r *Redactor) Redact(s string) string {
+	s = emailRE.ReplaceAllString(s, redactedEmail)
+	for _, term := range r.terms {
+		s = regexp.MustCompile(`(?i)`+regexp.QuoteMeta(term)).ReplaceAllString(s, redactedTerm)
+	}
+	s = ipv4RE.ReplaceAllString(s, redactedIP)
+	s = ipv6RE.ReplaceAllString(s, redactedIP)
+	s = awsAccountRE.ReplaceAllString(s, redactedAccount)
+	return s
+}
+
+// Stdout redirects everything written to os.Stdout through the redactor until the returned function
+// is called. Commands print with fmt.Print* all over the place, so this is the only way to make sure
+// nothing slips through. The returned function must be called to flush the output.
+func (r *Redactor) Stdout() (restore func(), err error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to redirect stdout to redact it: %w", err)
+	}
+
+	original := os.Stdout
+	os.Stdout = writer
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.copy(original, reader)
+	}()
+
+	return func() {
+		_ = writer.Close()
+		<-done
+		_ = reader.Close()
+		os.Stdout = original
+	}, nil
+}
+
+func (r *Redactor) copy(dst io.Writer, src io.Reader) {
+	buffered := bufio.NewReader(src)
+	for {
+		line, err := buffered.ReadString('\n')
+		if line != "" {
+			_, _ = io.WriteString(dst, r.Redact(line))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
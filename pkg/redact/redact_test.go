@@ -0,0 +1,86 @@
+package redact
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []string
+		input string
+		want  string
+	}{
+		{
+			name:  "email",
+			input: "Cluster owned by jane.doe+osd@example.com.",
+			want:  "Cluster owned by <redacted-email>.",
+		},
+		{
+			name:  "ipv4",
+			input: "API server at 10.0.12.5:6443 is unreachable",
+			want:  "API server at <redacted-ip>:6443 is unreachable",
+		},
+		{
+			name:  "ipv6",
+			input: "node address 2001:db8::8a2e:370:7334",
+			want:  "node address <redacted-ip>",
+		},
+		{
+			name:  "aws account",
+			input: "arn:aws:iam::123456789012:role/ManagedOpenShift-Support",
+			want:  "arn:aws:iam::<redacted-account>:role/ManagedOpenShift-Support",
+		},
+		{
+			name:  "terms are case insensitive",
+			terms: []string{"Acme Corp", "jdoe"},
+			input: "Organization: ACME CORP, creator: jdoe",
+			want:  "Organization: <redacted>, creator: <redacted>",
+		},
+		{
+			name:  "versions and timestamps are left alone",
+			input: "4.14.12 upgraded at 2024-05-01T10:00:00Z",
+			want:  "4.14.12 upgraded at 2024-05-01T10:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.terms...).Redact(tt.input); got != tt.want {
+				t.Errorf("Redact() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdout(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := os.Stdout
+	os.Stdout = file
+	defer func() { os.Stdout = original }()
+
+	restore, err := New().Stdout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("contact admin@example.com")
+	fmt.Print("no trailing newline 192.168.0.1")
+	restore()
+
+	if os.Stdout != file {
+		t.Errorf("Stdout() restore didn't put back the original stdout")
+	}
+
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "contact <redacted-email>\nno trailing newline <redacted-ip>"
+	if string(content) != want {
+		t.Errorf("Stdout() wrote %q, want %q", string(content), want)
+	}
+}
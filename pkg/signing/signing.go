@@ -0,0 +1,139 @@
+// Package signing signs the artifacts written by osdctl, such as the reports and the exports attached to
+// compliance tickets, so their integrity can be verified later. The signatures are detached: they're
+// written next to the artifacts by cosign (sigstore) or gpg, which must be installed.
+package signing
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	MethodCosign = "cosign"
+	MethodGPG    = "gpg"
+
+	// CosignKeyConfigKey is the cosign private key (a path or a KMS URI), cosign signs keyless through
+	// the sigstore OIDC flow when it isn't set
+	CosignKeyConfigKey = "signing_cosign_key"
+	// GPGKeyConfigKey is the ID of the gpg key, the default key of gpg is used when it isn't set
+	GPGKeyConfigKey = "signing_gpg_key"
+
+	cosignBundleSuffix = ".sigstore.json"
+	gpgSignatureSuffix = ".asc"
+)
+
+// FlagUsage is the usage of the --sign flags of the commands writing artifacts
+var FlagUsage = fmt.Sprintf("Sign the written files with '%s' (keyless unless %s is set in the config) or '%s' (with %s, or the default key)", MethodCosign, CosignKeyConfigKey, MethodGPG, GPGKeyConfigKey)
+
+// Signer writes the detached signatures of files
+type Signer struct {
+	Method string
+	// Key is the cosign key or the gpg key ID, empty to sign keyless or with the default gpg key
+	Key string
+
+	run func(name string, args ...string) error
+}
+
+// NewSigner returns the signer of the method, nil when the method is empty so the files aren't signed
+func NewSigner(method string) (*Signer, error) {
+	var key string
+	switch method {
+	case "":
+		return nil, nil
+	case MethodCosign:
+		key = viper.GetString(CosignKeyConfigKey)
+	case MethodGPG:
+		key = viper.GetString(GPGKeyConfigKey)
+	default:
+		return nil, fmt.Errorf("invalid signing method '%s', expected '%s' or '%s'", method, MethodCosign, MethodGPG)
+	}
+	if _, err := exec.LookPath(method); err != nil {
+		return nil, fmt.Errorf("%s is required to sign the files: %w", method, err)
+	}
+	return &Signer{Method: method, Key: key, run: runCommand}, nil
+}
+
+// SignatureFile returns the path of the signature of the file
+func (s *Signer) SignatureFile(path string) string {
+	if s.Method == MethodCosign {
+		return path + cosignBundleSuffix
+	}
+	return path + gpgSignatureSuffix
+}
+
+// SignFile writes the signature of the file next to it and returns its path
+func (s *Signer) SignFile(path string) (string, error) {
+	signature := s.SignatureFile(path)
+	if err := s.run(s.Method, s.signArgs(path, signature)...); err != nil {
+		return "", fmt.Errorf("failed to sign %s with %s: %w", path, s.Method, err)
+	}
+	return signature, nil
+}
+
+// SignBytes returns the name and the content of the signature of an artifact not written to a local file,
+// e.g. a report uploaded to S3
+func (s *Signer) SignBytes(name string, content []byte) (string, []byte, error) {
+	dir, err := os.MkdirTemp("", "osdctl-signing-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(name))
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return "", nil, err
+	}
+	signature, err := s.SignFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	signatureContent, err := os.ReadFile(signature)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.SignatureFile(name), signatureContent, nil
+}
+
+// VerifyCommand returns the command verifying the signature of the file
+func (s *Signer) VerifyCommand(path string) string {
+	signature := s.SignatureFile(path)
+	if s.Method == MethodGPG {
+		return fmt.Sprintf("gpg --verify %s %s", signature, path)
+	}
+	if s.Key != "" {
+		return fmt.Sprintf("cosign verify-blob --key <public-key> --bundle %s %s", signature, path)
+	}
+	return fmt.Sprintf("cosign verify-blob --certificate-identity <signer-email> --certificate-oidc-issuer <issuer> --bundle %s %s", signature, path)
+}
+
+func (s *Signer) signArgs(path string, signature string) []string {
+	if s.Method == MethodGPG {
+		args := []string{"--batch", "--yes", "--armor", "--detach-sign", "--output", signature}
+		if s.Key != "" {
+			args = append(args, "--local-user", s.Key)
+		}
+		return append(args, path)
+	}
+	args := []string{"sign-blob", "--yes", "--bundle", signature}
+	if s.Key != "" {
+		args = append(args, "--key", s.Key)
+	}
+	return append(args, path)
+}
+
+// runCommand runs the signing tool, which may prompt for a passphrase or open the OIDC flow
+func runCommand(name string, args ...string) error {
+	cmd := exec.Command(name, args...) //#nosec G204 -- the arguments are built by osdctl
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("'%s %s' failed: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package signing
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSignFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		signer        Signer
+		wantArgs      []string
+		wantSignature string
+	}{
+		{
+			name:          "cosign keyless",
+			signer:        Signer{Method: MethodCosign},
+			wantArgs:      []string{"cosign", "sign-blob", "--yes", "--bundle", "report.csv.sigstore.json", "report.csv"},
+			wantSignature: "report.csv.sigstore.json",
+		},
+		{
+			name:          "cosign with a key",
+			signer:        Signer{Method: MethodCosign, Key: "awskms:///alias/osdctl"},
+			wantArgs:      []string{"cosign", "sign-blob", "--yes", "--bundle", "report.csv.sigstore.json", "--key", "awskms:///alias/osdctl", "report.csv"},
+			wantSignature: "report.csv.sigstore.json",
+		},
+		{
+			name:          "gpg with the default key",
+			signer:        Signer{Method: MethodGPG},
+			wantArgs:      []string{"gpg", "--batch", "--yes", "--armor", "--detach-sign", "--output", "report.csv.asc", "report.csv"},
+			wantSignature: "report.csv.asc",
+		},
+		{
+			name:          "gpg with a key",
+			signer:        Signer{Method: MethodGPG, Key: "sre@example.com"},
+			wantArgs:      []string{"gpg", "--batch", "--yes", "--armor", "--detach-sign", "--output", "report.csv.asc", "--local-user", "sre@example.com", "report.csv"},
+			wantSignature: "report.csv.asc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			signer := tt.signer
+			signer.run = func(name string, args ...string) error {
+				got = append([]string{name}, args...)
+				return nil
+			}
+			signature, err := signer.SignFile("report.csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("SignFile() ran %v, want %v", got, tt.wantArgs)
+			}
+			if signature != tt.wantSignature {
+				t.Errorf("SignFile() = %s, want %s", signature, tt.wantSignature)
+			}
+		})
+	}
+}
+
+func TestSignBytes(t *testing.T) {
+	signer := Signer{Method: MethodGPG}
+	signer.run = func(name string, args ...string) error {
+		// the fake signature is the content of the signed file
+		content, err := os.ReadFile(args[len(args)-1])
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(args[len(args)-2], append([]byte("signed:"), content...), 0600)
+	}
+
+	name, content, err := signer.SignBytes("reports/org-context.txt", []byte("report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "reports/org-context.txt.asc" {
+		t.Errorf("SignBytes() name = %s, want reports/org-context.txt.asc", name)
+	}
+	if string(content) != "signed:report" {
+		t.Errorf("SignBytes() content = %q, want %q", content, "signed:report")
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	signer, err := NewSigner("")
+	if signer != nil || err != nil {
+		t.Errorf("NewSigner(\"\") = %v, %v, want no signer", signer, err)
+	}
+	if _, err := NewSigner("pgp"); err == nil || !strings.Contains(err.Error(), "invalid signing method") {
+		t.Errorf("NewSigner(\"pgp\") error = %v, want an invalid method", err)
+	}
+
+	// the tool must be installed
+	t.Setenv("PATH", filepath.Join(t.TempDir(), "empty"))
+	if _, err := NewSigner(MethodCosign); err == nil {
+		t.Error("NewSigner(\"cosign\") succeeded without cosign in the PATH")
+	}
+}
@@ -0,0 +1,246 @@
+// Package tracing records the duration of the steps of a command as OpenTelemetry spans, exported in the
+// OTLP/JSON format to a file or to an OTLP/HTTP collector so slow runs can be analyzed.
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EndpointFlagName  = "trace-endpoint"
+	EndpointConfigKey = "trace_endpoint"
+	FileFlagName      = "trace-file"
+
+	serviceName = "osdctl"
+	scopeName   = "github.com/openshift/osdctl"
+	// OTLP span kinds and status codes
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+var (
+	mu     sync.Mutex
+	active *trace
+)
+
+type trace struct {
+	id         string
+	root       *Span
+	spans      []*Span
+	attributes map[string]string
+}
+
+// Span is a timed step of a command. The nil spans returned when no trace was started are no-ops.
+type Span struct {
+	traceID    string
+	id         string
+	parentID   string
+	name       string
+	start      time.Time
+	end        time.Time
+	attributes map[string]string
+	err        error
+}
+
+// Start starts a trace whose root span is named after the command, the spans started afterwards are its
+// children. The attributes describe the whole run, e.g. the cluster ID.
+func Start(name string, attributes map[string]string) *Span {
+	mu.Lock()
+	defer mu.Unlock()
+	active = &trace{id: randomID(16), attributes: attributes}
+	active.root = newSpan(active, name, "")
+	return active.root
+}
+
+// StartSpan starts a child span of the root span of the trace, nil when no trace was started
+func StartSpan(name string) *Span {
+	mu.Lock()
+	defer mu.Unlock()
+	if active == nil {
+		return nil
+	}
+	return newSpan(active, name, active.root.id)
+}
+
+func newSpan(t *trace, name string, parentID string) *Span {
+	span := &Span{traceID: t.id, id: randomID(8), parentID: parentID, name: name, start: time.Now(), attributes: map[string]string{}}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+// SetAttribute records a key/value pair on the span
+func (s *Span) SetAttribute(key string, value string) {
+	if s == nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	s.attributes[key] = value
+}
+
+// End ends the span, with an error status if err isn't nil
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	s.end = time.Now()
+	s.err = err
+}
+
+// Export ends the trace and writes it to the file and/or sends it to the OTLP/HTTP endpoint, e.g.
+// http://localhost:4318. The trace is dropped afterwards.
+func Export(endpoint string, file string) error {
+	mu.Lock()
+	t := active
+	active = nil
+	var traces otlpTraces
+	if t != nil {
+		traces = t.otlp()
+	}
+	mu.Unlock()
+	if t == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(traces)
+	if err != nil {
+		return err
+	}
+	if file != "" {
+		if err := os.WriteFile(file, body, 0600); err != nil {
+			return fmt.Errorf("failed to write the trace: %w", err)
+		}
+	}
+	if endpoint != "" {
+		url := strings.TrimSuffix(endpoint, "/")
+		if !strings.HasSuffix(url, "/v1/traces") {
+			url += "/v1/traces"
+		}
+		client := http.Client{Timeout: 10 * time.Second}
+		response, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to send the trace to %s: %w", url, err)
+		}
+		defer response.Body.Close()
+		if response.StatusCode/100 != 2 {
+			return fmt.Errorf("failed to send the trace to %s: %s", url, response.Status)
+		}
+	}
+	return nil
+}
+
+func randomID(length int) string {
+	id := make([]byte, length)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+// otlpTraces is an ExportTraceServiceRequest of the OTLP/JSON protocol
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// otlpAttributes converts the attributes, sorted by key so the output is stable
+func otlpAttributes(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	converted := make([]otlpAttribute, 0, len(keys))
+	for _, key := range keys {
+		attribute := otlpAttribute{Key: key}
+		attribute.Value.StringValue = attributes[key]
+		converted = append(converted, attribute)
+	}
+	return converted
+}
+
+// otlp converts the trace, the spans still running are ended now
+func (t *trace) otlp() otlpTraces {
+	now := time.Now()
+	spans := make([]otlpSpan, 0, len(t.spans))
+	for _, span := range t.spans {
+		end := span.end
+		if end.IsZero() {
+			end = now
+		}
+		converted := otlpSpan{
+			TraceID:           span.traceID,
+			SpanID:            span.id,
+			ParentSpanID:      span.parentID,
+			Name:              span.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.attributes),
+			Status:            otlpStatus{Code: statusCodeOK},
+		}
+		if span.err != nil {
+			converted.Status = otlpStatus{Code: statusCodeError, Message: span.err.Error()}
+		}
+		spans = append(spans, converted)
+	}
+
+	resource := map[string]string{"service.name": serviceName}
+	for key, value := range t.attributes {
+		resource[key] = value
+	}
+	scopeSpans := otlpScopeSpans{Spans: spans}
+	scopeSpans.Scope.Name = scopeName
+	resourceSpans := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{scopeSpans}}
+	resourceSpans.Resource.Attributes = otlpAttributes(resource)
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{resourceSpans}}
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartSpanWithoutTrace(t *testing.T) {
+	span := StartSpan("collector")
+	if span != nil {
+		t.Fatalf("StartSpan() = %v, want nil without a trace", span)
+	}
+	// Nil spans are no-ops
+	span.SetAttribute("key", "value")
+	span.End(nil)
+	if err := Export("", filepath.Join(t.TempDir(), "trace.json")); err != nil {
+		t.Errorf("Export() error = %v", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	root := Start("cluster context", map[string]string{"cluster.id": "abc"})
+	StartSpan("Service Logs").End(nil)
+	failed := StartSpan("Jira Issues")
+	failed.SetAttribute("attempts", "2")
+	failed.End(errors.New("unauthorized"))
+	root.End(nil)
+
+	file := filepath.Join(t.TempDir(), "trace.json")
+	if err := Export(server.URL, file); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	written, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(written) != string(received) {
+		t.Errorf("the trace sent to the endpoint differs from the trace file")
+	}
+
+	var traces otlpTraces
+	if err := json.Unmarshal(written, &traces); err != nil {
+		t.Fatalf("invalid trace: %v", err)
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	for _, span := range spans[1:] {
+		if span.ParentSpanID != spans[0].SpanID || span.TraceID != spans[0].TraceID {
+			t.Errorf("span %s isn't a child of the root span", span.Name)
+		}
+	}
+	if spans[2].Status.Code != statusCodeError || spans[2].Status.Message != "unauthorized" {
+		t.Errorf("status = %+v, want an error", spans[2].Status)
+	}
+	if len(spans[2].Attributes) != 1 || spans[2].Attributes[0].Key != "attempts" {
+		t.Errorf("attributes = %+v, want attempts", spans[2].Attributes)
+	}
+	resource := traces.ResourceSpans[0].Resource.Attributes
+	if len(resource) != 2 || resource[0].Key != "cluster.id" || resource[1].Value.StringValue != serviceName {
+		t.Errorf("resource attributes = %+v", resource)
+	}
+
+	// The trace is dropped once exported
+	if StartSpan("late") != nil {
+		t.Errorf("StartSpan() after Export() should be nil")
+	}
+}
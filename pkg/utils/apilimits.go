@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// PagerDutyMaxRPSConfigKey, JiraMaxConcurrentConfigKey and OCMMaxRPSConfigKey bound the requests osdctl sends to
+	// the external APIs, across every client of the process. A value <= 0 lifts the limit.
+	PagerDutyMaxRPSConfigKey   = "pd_max_rps"
+	JiraMaxConcurrentConfigKey = "jira_max_concurrent"
+	OCMMaxRPSConfigKey         = "ocm_max_rps"
+
+	// DefaultPagerDutyMaxRPS stays well under the 960 requests per minute PagerDuty allows a token
+	DefaultPagerDutyMaxRPS = 10
+	// DefaultJiraMaxConcurrent keeps the fleet commands from tripping the Jira abuse protection
+	DefaultJiraMaxConcurrent = 4
+	DefaultOCMMaxRPS         = 20
+)
+
+var (
+	pagerDutyLimit = &apiLimit{configKey: PagerDutyMaxRPSConfigKey, defaultValue: DefaultPagerDutyMaxRPS}
+	jiraLimit      = &apiLimit{configKey: JiraMaxConcurrentConfigKey, defaultValue: DefaultJiraMaxConcurrent, concurrency: true}
+	ocmLimit       = &apiLimit{configKey: OCMMaxRPSConfigKey, defaultValue: DefaultOCMMaxRPS}
+)
+
+// LimitPagerDuty returns a transport spacing out the PagerDuty requests sent through next to pd_max_rps
+func LimitPagerDuty(next http.RoundTripper) http.RoundTripper {
+	return pagerDutyLimit.wrap(next)
+}
+
+// LimitJira returns a transport running at most jira_max_concurrent Jira requests sent through next at a time
+func LimitJira(next http.RoundTripper) http.RoundTripper {
+	return jiraLimit.wrap(next)
+}
+
+// LimitOCM returns a transport spacing out the OCM requests sent through next to ocm_max_rps
+func LimitOCM(next http.RoundTripper) http.RoundTripper {
+	return ocmLimit.wrap(next)
+}
+
+// apiLimit is the limit of an external API, shared by all the transports wrapped for it. It's read from the config
+// the first time a transport is wrapped, once the config is loaded.
+type apiLimit struct {
+	configKey    string
+	defaultValue float64
+	// concurrency is true when the value is a number of requests in flight rather than requests per second
+	concurrency bool
+
+	once    sync.Once
+	limiter *RateLimiter
+	slots   chan struct{}
+}
+
+func (l *apiLimit) load() {
+	value := l.defaultValue
+	if viper.IsSet(l.configKey) {
+		value = viper.GetFloat64(l.configKey)
+	}
+	if value <= 0 {
+		return
+	}
+	if l.concurrency {
+		l.slots = make(chan struct{}, max(int(value), 1))
+		return
+	}
+	l.limiter = NewRateLimiter(value)
+}
+
+func (l *apiLimit) wrap(next http.RoundTripper) http.RoundTripper {
+	l.once.Do(l.load)
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if l.limiter == nil && l.slots == nil {
+		return next
+	}
+	return &limitedTransport{next: next, limiter: l.limiter, slots: l.slots}
+}
+
+type limitedTransport struct {
+	next    http.RoundTripper
+	limiter *RateLimiter
+	slots   chan struct{}
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		defer func() { <-t.slots }()
+	}
+	t.limiter.Wait()
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAPILimitConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	next := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	limit := &apiLimit{configKey: "test_max_concurrent", defaultValue: 2, concurrency: true}
+	transport := limit.wrap(next)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Errorf("RoundTrip() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestAPILimitConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      interface{}
+		concurrency bool
+		wantLimited bool
+	}{
+		{
+			name:        "default applies when unset",
+			wantLimited: true,
+		},
+		{
+			name:        "zero lifts the limit",
+			config:      0,
+			wantLimited: false,
+		},
+		{
+			name:        "negative concurrency lifts the limit",
+			config:      -1,
+			concurrency: true,
+			wantLimited: false,
+		},
+		{
+			name:        "configured rate",
+			config:      "2.5",
+			wantLimited: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			if tt.config != nil {
+				viper.Set("test_limit", tt.config)
+			}
+
+			next := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			})
+			limit := &apiLimit{configKey: "test_limit", defaultValue: 5, concurrency: tt.concurrency}
+			_, limited := limit.wrap(next).(*limitedTransport)
+			if limited != tt.wantLimited {
+				t.Errorf("expected limited = %v, got %v", tt.wantLimited, limited)
+			}
+		})
+	}
+}
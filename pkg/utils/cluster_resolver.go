@@ -0,0 +1,256 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// ExternalClusterIDFlag is the name of the flag commands expose to look a cluster up strictly by its external UUID.
+// Every lookup already resolves a positional UUID by external ID only (see GetCluster and GetClusterAnyStatus), the
+// flag is for the commands where the positional identifier is optional.
+const ExternalClusterIDFlag = "external-cluster-id"
+
+// ClusterIdentifierType describes which OCM field a cluster identifier refers to
+type ClusterIdentifierType string
+
+const (
+	ClusterIdentifierInternalID     ClusterIdentifierType = "internal ID"
+	ClusterIdentifierExternalID     ClusterIdentifierType = "external ID"
+	ClusterIdentifierSubscriptionID ClusterIdentifierType = "subscription ID"
+	ClusterIdentifierName           ClusterIdentifierType = "name"
+)
+
+var (
+	internalClusterIDRE = regexp.MustCompile(`^[0-9a-z]{32}$`)
+	subscriptionIDRE    = regexp.MustCompile(`^[0-9a-zA-Z]{27}$`)
+)
+
+// ClassifyClusterIdentifier guesses the kind of a cluster identifier from its format:
+// internal IDs are 32 lowercase alphanumeric characters, external IDs are UUIDs and
+// subscription IDs are 27 character KSUIDs. Anything else is treated as a name.
+func ClassifyClusterIdentifier(key string) ClusterIdentifierType {
+	if internalClusterIDRE.MatchString(key) {
+		return ClusterIdentifierInternalID
+	}
+	if _, err := uuid.Parse(key); err == nil {
+		return ClusterIdentifierExternalID
+	}
+	if subscriptionIDRE.MatchString(key) {
+		return ClusterIdentifierSubscriptionID
+	}
+	return ClusterIdentifierName
+}
+
+// AmbiguousClusterError is returned when an identifier matches more than one cluster.
+// Its message lists every candidate so the user can retry with an unambiguous identifier.
+type AmbiguousClusterError struct {
+	Key        string
+	Candidates []ClusterCandidate
+}
+
+// ClusterCandidate is a cluster that matched an ambiguous identifier
+type ClusterCandidate struct {
+	ID             string
+	ExternalID     string
+	Name           string
+	SubscriptionID string
+	State          string
+}
+
+func (e *AmbiguousClusterError) Error() string {
+	lines := []string{fmt.Sprintf("there are %d clusters matching '%s', use one of the following IDs instead:", len(e.Candidates), e.Key)}
+	for _, c := range e.Candidates {
+		lines = append(lines, fmt.Sprintf("\tid: %s  external id: %s  subscription id: %s  name: %s  state: %s", c.ID, c.ExternalID, c.SubscriptionID, c.Name, c.State))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NewAmbiguousClusterError builds an AmbiguousClusterError from the clusters that matched key
+func NewAmbiguousClusterError(key string, clusters []*cmv1.Cluster) error {
+	candidates := make([]ClusterCandidate, 0, len(clusters))
+	for _, cluster := range clusters {
+		candidates = append(candidates, ClusterCandidate{
+			ID:             cluster.ID(),
+			ExternalID:     cluster.ExternalID(),
+			Name:           cluster.Name(),
+			SubscriptionID: cluster.Subscription().ID(),
+			State:          string(cluster.State()),
+		})
+	}
+	return &AmbiguousClusterError{Key: key, Candidates: candidates}
+}
+
+func newAmbiguousSubscriptionError(key string, subscriptions []*amv1.Subscription) error {
+	candidates := make([]ClusterCandidate, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		candidates = append(candidates, ClusterCandidate{
+			ID:             sub.ClusterID(),
+			ExternalID:     sub.ExternalClusterID(),
+			Name:           sub.DisplayName(),
+			SubscriptionID: sub.ID(),
+			State:          sub.Status(),
+		})
+	}
+	return &AmbiguousClusterError{Key: key, Candidates: candidates}
+}
+
+// PreferExactMatches narrows down clusters matched by a name pattern to the ones whose name
+// is exactly key. If none of them match exactly, clusters is returned unchanged.
+func PreferExactMatches(key string, clusters []*cmv1.Cluster) []*cmv1.Cluster {
+	if len(clusters) < 2 {
+		return clusters
+	}
+	var exact []*cmv1.Cluster
+	for _, cluster := range clusters {
+		if cluster.Name() == key || cluster.ID() == key || cluster.ExternalID() == key {
+			exact = append(exact, cluster)
+		}
+	}
+	if len(exact) == 0 {
+		return clusters
+	}
+	return exact
+}
+
+// GetCluster Function allows to get a single cluster with any identifier (displayname, ID, external ID or subscription ID).
+// The identifier is looked up by the field its format suggests first, falling back to a lookup by name,
+// and exact matches always win over partial ones. If the identifier is ambiguous an *AmbiguousClusterError
+// listing the candidates is returned.
+func GetCluster(connection *sdk.Connection, key string) (*cmv1.Cluster, error) {
+	var (
+		cluster *cmv1.Cluster
+		err     error
+	)
+	switch ClassifyClusterIdentifier(key) {
+	case ClusterIdentifierInternalID:
+		cluster, err = getClusterByInternalID(connection, key)
+	case ClusterIdentifierExternalID:
+		cluster, err = GetClusterByExternalID(connection, key)
+	case ClusterIdentifierSubscriptionID:
+		cluster, err = getClusterBySubscriptionID(connection, key)
+	}
+	if err != nil || cluster != nil {
+		return cluster, err
+	}
+
+	return getClusterByName(connection, key)
+}
+
+// GetClusterByExternalID returns the cluster with the given external UUID, without falling back
+// to a lookup by name. Clusters that don't report metrics lack the external ID in the accounts
+// management service, so the clusters management service is checked as well.
+func GetClusterByExternalID(connection *sdk.Connection, externalID string) (*cmv1.Cluster, error) {
+	if _, err := uuid.Parse(externalID); err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid external cluster ID: %v", externalID, err)
+	}
+
+	clusters, err := searchClusters(connection, fmt.Sprintf("external_id = '%s'", externalID))
+	if err != nil {
+		return nil, err
+	}
+	switch len(clusters) {
+	case 0:
+	case 1:
+		return clusters[0], nil
+	default:
+		return nil, NewAmbiguousClusterError(externalID, clusters)
+	}
+
+	subscriptions, err := searchSubscriptions(connection, fmt.Sprintf("external_cluster_id = '%s'", externalID))
+	if err != nil {
+		return nil, err
+	}
+	switch len(subscriptions) {
+	case 0:
+		return nil, fmt.Errorf("there are no subscriptions or clusters with external ID '%s'", externalID)
+	case 1:
+		return getClusterByInternalID(connection, subscriptions[0].ClusterID())
+	default:
+		return nil, newAmbiguousSubscriptionError(externalID, subscriptions)
+	}
+}
+
+// getClusterByInternalID returns nil without error if the cluster doesn't exist,
+// so the caller can fall back to another lookup
+func getClusterByInternalID(connection *sdk.Connection, id string) (*cmv1.Cluster, error) {
+	response, err := connection.ClustersMgmt().V1().Clusters().Cluster(id).Get().Send()
+	if err != nil {
+		if response != nil && response.Status() == 404 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("can't retrieve cluster '%s': %v", id, err)
+	}
+	return response.Body(), nil
+}
+
+func getClusterBySubscriptionID(connection *sdk.Connection, id string) (*cmv1.Cluster, error) {
+	response, err := connection.AccountsMgmt().V1().Subscriptions().Subscription(id).Get().Send()
+	if err != nil {
+		if response != nil && response.Status() == 404 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("can't retrieve subscription '%s': %v", id, err)
+	}
+	clusterID, ok := response.Body().GetClusterID()
+	if !ok || clusterID == "" {
+		return nil, fmt.Errorf("subscription '%s' is not associated with a cluster", id)
+	}
+	return getClusterByInternalID(connection, clusterID)
+}
+
+func getClusterByName(connection *sdk.Connection, name string) (*cmv1.Cluster, error) {
+	subscriptions, err := searchSubscriptions(connection, fmt.Sprintf("display_name = '%s' and status in ('Reserved', 'Active')", name))
+	if err != nil {
+		return nil, err
+	}
+	switch len(subscriptions) {
+	case 0:
+	case 1:
+		cluster, err := getClusterByInternalID(connection, subscriptions[0].ClusterID())
+		if err != nil || cluster != nil {
+			return cluster, err
+		}
+	default:
+		return nil, newAmbiguousSubscriptionError(name, subscriptions)
+	}
+
+	// If we are here then no subscription matches the passed key. It may still be possible that
+	// the cluster exists but it is not reporting metrics, so it will not have the display name
+	// in the accounts management service. To find those clusters we need to check
+	// directly in the clusters management service.
+	clusters, err := searchClusters(connection, fmt.Sprintf(ClusterServiceClusterSearch, name, name, name))
+	if err != nil {
+		return nil, err
+	}
+	clusters = PreferExactMatches(name, clusters)
+	switch len(clusters) {
+	case 0:
+		return nil, fmt.Errorf("there are no subscriptions or clusters with identifier or name '%s'", name)
+	case 1:
+		return clusters[0], nil
+	default:
+		return nil, NewAmbiguousClusterError(name, clusters)
+	}
+}
+
+func searchClusters(connection *sdk.Connection, search string) ([]*cmv1.Cluster, error) {
+	response, err := connection.ClustersMgmt().V1().Clusters().List().Search(search).Size(100).Send()
+	if err != nil {
+		return nil, fmt.Errorf("can't retrieve clusters matching \"%s\": %v", search, err)
+	}
+	return response.Items().Slice(), nil
+}
+
+func searchSubscriptions(connection *sdk.Connection, search string) ([]*amv1.Subscription, error) {
+	response, err := connection.AccountsMgmt().V1().Subscriptions().List().Search(search).Size(100).Send()
+	if err != nil {
+		return nil, fmt.Errorf("can't retrieve subscriptions matching \"%s\": %v", search, err)
+	}
+	return response.Items().Slice(), nil
+}
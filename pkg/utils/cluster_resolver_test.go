@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestClassifyClusterIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want ClusterIdentifierType
+	}{
+		{
+			name: "internal ID",
+			key:  "261kalm3uob0vegg1c7h9o7r5k9t64ji",
+			want: ClusterIdentifierInternalID,
+		},
+		{
+			name: "external ID",
+			key:  "c1f562af-fb22-42c5-aa07-6848e1eeee9c",
+			want: ClusterIdentifierExternalID,
+		},
+		{
+			name: "subscription ID",
+			key:  "2aBcDeFgHiJkLmNoPqRsTuVwXyZ",
+			want: ClusterIdentifierSubscriptionID,
+		},
+		{
+			name: "name",
+			key:  "my-cluster",
+			want: ClusterIdentifierName,
+		},
+		{
+			name: "internal ID with upper case is a name",
+			key:  "261kalm3uob0vegg1c7h9o7r5k9t64jI",
+			want: ClusterIdentifierName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyClusterIdentifier(tt.key); got != tt.want {
+				t.Errorf("ClassifyClusterIdentifier(%s) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestCluster(t *testing.T, id string, name string) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID(id).Name(name).Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+	return cluster
+}
+
+func TestPreferExactMatches(t *testing.T) {
+	exact := newTestCluster(t, "1", "prod")
+	partial := newTestCluster(t, "2", "prod-eu")
+	other := newTestCluster(t, "3", "prod-us")
+
+	tests := []struct {
+		name     string
+		key      string
+		clusters []*cmv1.Cluster
+		wantIDs  []string
+	}{
+		{
+			name:     "exact match wins over partial matches",
+			key:      "prod",
+			clusters: []*cmv1.Cluster{partial, exact, other},
+			wantIDs:  []string{"1"},
+		},
+		{
+			name:     "no exact match keeps all candidates",
+			key:      "prod-%",
+			clusters: []*cmv1.Cluster{partial, other},
+			wantIDs:  []string{"2", "3"},
+		},
+		{
+			name:     "single cluster is returned as is",
+			key:      "something",
+			clusters: []*cmv1.Cluster{partial},
+			wantIDs:  []string{"2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PreferExactMatches(tt.key, tt.clusters)
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("PreferExactMatches() returned %d clusters, want %d", len(got), len(tt.wantIDs))
+			}
+			for i, cluster := range got {
+				if cluster.ID() != tt.wantIDs[i] {
+					t.Errorf("PreferExactMatches()[%d] = %s, want %s", i, cluster.ID(), tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAmbiguousClusterError(t *testing.T) {
+	err := NewAmbiguousClusterError("prod", []*cmv1.Cluster{
+		newTestCluster(t, "1", "prod"),
+		newTestCluster(t, "2", "prod"),
+	})
+
+	var ambiguousErr *AmbiguousClusterError
+	if !errors.As(err, &ambiguousErr) {
+		t.Fatalf("NewAmbiguousClusterError() = %T, want *AmbiguousClusterError", err)
+	}
+	if len(ambiguousErr.Candidates) != 2 {
+		t.Errorf("NewAmbiguousClusterError() has %d candidates, want 2", len(ambiguousErr.Candidates))
+	}
+	for _, id := range []string{"id: 1", "id: 2"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("AmbiguousClusterError.Error() = %q, want it to list %q", err.Error(), id)
+		}
+	}
+}
@@ -4,18 +4,22 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/openshift/osdctl/pkg/tracing"
 )
 
 type DelayTracker struct {
 	verbose bool
 	action  string
 	start   time.Time
+	span    *tracing.Span
 }
 
 func StartDelayTracker(verbose bool, action string) *DelayTracker {
 	dt := DelayTracker{
 		verbose: verbose,
 		action:  action,
+		span:    tracing.StartSpan(action),
 	}
 	if dt.verbose {
 		dt.start = time.Now()
@@ -25,6 +29,7 @@ func StartDelayTracker(verbose bool, action string) *DelayTracker {
 }
 
 func (dt *DelayTracker) End() {
+	dt.span.End(nil)
 	if dt.verbose {
 		fmt.Fprintf(os.Stderr, "Got %s within %s\n", dt.action, time.Since(dt.start))
 	}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const defaultFanOutConcurrency = 10
+
+// FanOutOptions configures how FanOut spreads work over a set of keys (usually cluster IDs)
+type FanOutOptions struct {
+	// MaxConcurrency bounds the number of keys processed at the same time. Defaults to 10.
+	MaxConcurrency int
+	// RateLimiter, if set, is waited on before each key is processed
+	RateLimiter *RateLimiter
+	// Progress, if set, receives a "<Action> for X of Y <Noun>..." line after each key completes
+	Progress io.Writer
+	// Action and Noun are used to build the progress message, e.g. "Fetched data" and "clusters"
+	Action string
+	Noun   string
+}
+
+// FanOutError aggregates the failures of a FanOut run. Keys that succeeded are not included.
+type FanOutError struct {
+	Errors map[string]error
+}
+
+func (e *FanOutError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("%s: %v", key, e.Errors[key]))
+	}
+	return fmt.Sprintf("%d of the requested operations failed:\n\t%s", len(keys), strings.Join(messages, "\n\t"))
+}
+
+// FanOut runs fn once for each key with bounded parallelism. Unlike an errgroup, a failing key does not
+// stop the others: every key is processed and the failures are returned together as a *FanOutError,
+// so that callers can print whatever partial results they managed to collect.
+// fn is responsible for storing its own results; it may be called concurrently.
+func FanOut(keys []string, opts FanOutOptions, fn func(key string) error) error {
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = defaultFanOutConcurrency
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		completed int
+		failures  = map[string]error{}
+		semaphore = make(chan struct{}, maxConcurrency)
+	)
+
+	opts.printProgress(0, len(keys), false)
+	for _, key := range keys {
+		key := key
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			opts.RateLimiter.Wait()
+			err := fn(key)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				failures[key] = err
+			}
+			completed++
+			opts.printProgress(completed, len(keys), true)
+		}()
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return &FanOutError{Errors: failures}
+	}
+	return nil
+}
+
+func (opts FanOutOptions) printProgress(completed int, total int, overwrite bool) {
+	if opts.Progress == nil {
+		return
+	}
+	if overwrite {
+		// Move up one line and clear it, so the counter is updated in place
+		_, _ = fmt.Fprintf(opts.Progress, "\033[1A\033[K")
+	}
+	_, _ = fmt.Fprintf(opts.Progress, "%s for %v of %v %s...\n", opts.Action, completed, total, opts.Noun)
+}
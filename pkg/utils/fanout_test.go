@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFanOut(t *testing.T) {
+	tests := []struct {
+		name           string
+		keys           []string
+		failingKeys    map[string]bool
+		maxConcurrency int
+		wantFailures   []string
+	}{
+		{
+			name: "No keys",
+		},
+		{
+			name:           "All keys succeed",
+			keys:           []string{"a", "b", "c", "d"},
+			maxConcurrency: 2,
+		},
+		{
+			name:           "Partial failures are aggregated and other keys still run",
+			keys:           []string{"a", "b", "c", "d"},
+			failingKeys:    map[string]bool{"b": true, "d": true},
+			maxConcurrency: 1,
+			wantFailures:   []string{"b", "d"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mutex sync.Mutex
+			processed := map[string]bool{}
+			var running, maxRunning int32
+
+			err := FanOut(tt.keys, FanOutOptions{MaxConcurrency: tt.maxConcurrency}, func(key string) error {
+				current := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					seen := atomic.LoadInt32(&maxRunning)
+					if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+
+				mutex.Lock()
+				processed[key] = true
+				mutex.Unlock()
+
+				if tt.failingKeys[key] {
+					return errors.New("boom")
+				}
+				return nil
+			})
+
+			if len(processed) != len(tt.keys) {
+				t.Errorf("FanOut() processed %d keys, want %d", len(processed), len(tt.keys))
+			}
+			if tt.maxConcurrency > 0 && int(maxRunning) > tt.maxConcurrency {
+				t.Errorf("FanOut() ran %d keys concurrently, want at most %d", maxRunning, tt.maxConcurrency)
+			}
+
+			if len(tt.wantFailures) == 0 {
+				if err != nil {
+					t.Errorf("FanOut() unexpected error = %v", err)
+				}
+				return
+			}
+
+			var fanOutErr *FanOutError
+			if !errors.As(err, &fanOutErr) {
+				t.Fatalf("FanOut() error = %v, want a *FanOutError", err)
+			}
+			if len(fanOutErr.Errors) != len(tt.wantFailures) {
+				t.Errorf("FanOut() got %d failures, want %d", len(fanOutErr.Errors), len(tt.wantFailures))
+			}
+			for _, key := range tt.wantFailures {
+				if _, ok := fanOutErr.Errors[key]; !ok {
+					t.Errorf("FanOut() missing failure for key %s", key)
+				}
+			}
+		})
+	}
+}
+
+func TestFanOutProgress(t *testing.T) {
+	progress := &bytes.Buffer{}
+	err := FanOut([]string{"a", "b"}, FanOutOptions{Progress: progress, Action: "Fetched data", Noun: "clusters"}, func(string) error { return nil })
+	if err != nil {
+		t.Fatalf("FanOut() unexpected error = %v", err)
+	}
+
+	if !strings.Contains(progress.String(), "Fetched data for 2 of 2 clusters...") {
+		t.Errorf("FanOut() progress output = %q, want final progress line", progress.String())
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(100)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		limiter.Wait()
+	}
+	// The first call is immediate, the following four are spaced 10ms apart
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("RateLimiter.Wait() returned after %s, want at least 40ms", elapsed)
+	}
+
+	var unlimited *RateLimiter
+	start = time.Now()
+	for i := 0; i < 100; i++ {
+		unlimited.Wait()
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("nil RateLimiter.Wait() took %s, want no delay", elapsed)
+	}
+}
@@ -2,81 +2,246 @@ package utils
 
 import (
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/andygrunwald/go-jira"
+	"github.com/openshift/osdctl/pkg/httpdebug"
+	"github.com/openshift/osdctl/pkg/interrupt"
+	"github.com/openshift/osdctl/pkg/rawdump"
 	"github.com/spf13/viper"
 )
 
 const (
 	JiraTokenConfigKey = "jira_token"
 	JiraBaseURL        = "https://issues.redhat.com"
+	// JiraBaseURLConfigKey points osdctl to another Jira instance, e.g. a Jira Cloud site
+	JiraBaseURLConfigKey  = "jira_base_url"
+	JiraAuthConfigKey     = "jira_auth"
+	JiraUsernameConfigKey = "jira_username"
+	// JiraProjectsConfigKey maps the default projects to the projects of the team
+	JiraProjectsConfigKey = "jira_projects"
+
+	JiraOHSSProject              = "OHSS"
+	JiraSupportExceptionsProject = "Support Exceptions"
+
+	JiraAuthPAT   = "pat"
+	JiraAuthBasic = "basic"
+	JiraAuthOAuth = "oauth"
+	// JiraTeamMembersConfigKey lists the Jira usernames or emails of the SREs, to tell their comments apart
+	JiraTeamMembersConfigKey = "jira_team_members"
+
+	jiraCommentTimeLayout  = "2006-01-02T15:04:05.000-0700"
+	jiraCommentSnippetSize = 100
+
+	// DefaultJiraIssueLimit is the number of issues fetched by default, the page size Jira defaults to
+	DefaultJiraIssueLimit = 50
+	// jiraSearchPageSize is the page size requested, Jira lowers it to its own maximum if needed
+	jiraSearchPageSize = 100
 )
 
-// GetJiraClient creates a jira client that connects to
-// https://issues.redhat.com. To work, the jiraToken needs to be set in the
-// config
+// GetJiraClient creates a jira client for the configured Jira instance, https://issues.redhat.com by
+// default. The token is read from JIRA_API_TOKEN, or jira_token in the config.
 func GetJiraClient() (*jira.Client, error) {
-	var jiratoken string
+	jiratoken := jiraToken()
+	if jiratoken == "" {
+		return nil, fmt.Errorf("JIRA token is not defined.")
+	}
+
+	return NewJiraClient(jiratoken)
+}
 
-	if !viper.IsSet(JiraTokenConfigKey) {
+// JiraTokenConfigured returns true when a Jira token is set by JIRA_API_TOKEN or jira_token in the config
+func JiraTokenConfigured() bool {
+	return jiraToken() != ""
+}
+
+func jiraToken() string {
+	jiratoken := os.Getenv("JIRA_API_TOKEN")
+	if jiratoken == "" {
 		jiratoken = viper.GetString(JiraTokenConfigKey)
 	}
+	return jiratoken
+}
 
-	if os.Getenv("JIRA_API_TOKEN") != "" {
-		jiratoken = os.Getenv("JIRA_API_TOKEN")
+// NewJiraClient creates a jira client for the configured Jira instance, authenticating with the token as
+// set by jira_auth:
+//   - pat (default): the token is a personal access token, for Jira Data Center
+//   - basic: the token is an API token of jira_username, for Jira Cloud
+//   - oauth: the token is an OAuth 2.0 access token
+func NewJiraClient(token string) (*jira.Client, error) {
+	username := os.Getenv("JIRA_USERNAME")
+	if username == "" {
+		username = viper.GetString(JiraUsernameConfigKey)
 	}
+	httpClient, err := jiraHTTPClient(viper.GetString(JiraAuthConfigKey), username, token)
+	if err != nil {
+		return nil, err
+	}
+	return jira.NewClient(httpClient, GetJiraBaseURL())
+}
 
-	if jiratoken == "" {
-		return nil, fmt.Errorf("JIRA token is not defined.")
+func jiraHTTPClient(auth string, username string, token string) (*http.Client, error) {
+	transport := interrupt.Wrap(httpdebug.Wrap(rawdump.Wrap(LimitJira(http.DefaultTransport))))
+	switch auth {
+	case "", JiraAuthPAT:
+		tp := jira.PATAuthTransport{Token: token, Transport: transport}
+		return tp.Client(), nil
+	case JiraAuthBasic:
+		if username == "" {
+			return nil, fmt.Errorf("%s is required for the %s Jira authentication", JiraUsernameConfigKey, JiraAuthBasic)
+		}
+		tp := jira.BasicAuthTransport{Username: username, Password: token, Transport: transport}
+		return tp.Client(), nil
+	case JiraAuthOAuth:
+		tp := jira.BearerAuthTransport{Token: token, Transport: transport}
+		return tp.Client(), nil
 	}
+	return nil, fmt.Errorf("unsupported %s '%s', valid values are %s, %s and %s", JiraAuthConfigKey, auth, JiraAuthPAT, JiraAuthBasic, JiraAuthOAuth)
+}
+
+// GetJiraBaseURL returns the URL of the Jira instance, set by JIRA_BASE_URL or jira_base_url in the config
+func GetJiraBaseURL() string {
+	baseURL := os.Getenv("JIRA_BASE_URL")
+	if baseURL == "" {
+		baseURL = viper.GetString(JiraBaseURLConfigKey)
+	}
+	if baseURL == "" {
+		return JiraBaseURL
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// JiraBrowseURL returns the link to an issue
+func JiraBrowseURL(key string) string {
+	return fmt.Sprintf("%s/browse/%s", GetJiraBaseURL(), key)
+}
+
+// JiraProject returns the project to use in place of a default project (key or name), as overridden by
+// jira_projects in the config, e.g. `jira_projects: {OHSS: SUPPORT}`
+func JiraProject(project string) string {
+	// viper lowercases the keys of maps
+	if override := viper.GetStringMapString(JiraProjectsConfigKey)[strings.ToLower(project)]; override != "" {
+		return override
+	}
+	return project
+}
+
+// JiraSearchResult holds the issues fetched by a search and the number of issues matching it, which is
+// larger when the search was limited
+type JiraSearchResult struct {
+	Issues []jira.Issue
+	Total  int
+}
+
+// Truncated returns true when only part of the matching issues were fetched
+func (r JiraSearchResult) Truncated() bool {
+	return len(r.Issues) < r.Total
+}
 
-	tp := jira.PATAuthTransport{
-		Token: jiratoken,
+// jiraIssueSearcher is the search of the Jira issue service
+type jiraIssueSearcher interface {
+	Search(jql string, options *jira.SearchOptions) ([]jira.Issue, *jira.Response, error)
+}
+
+// SearchJiraIssues pages through the results of the query until limit issues are fetched, or all of
+// them when limit isn't positive. The fields and the expansion of the options apply to every page.
+func SearchJiraIssues(searcher jiraIssueSearcher, jql string, options *jira.SearchOptions, limit int) (JiraSearchResult, error) {
+	var result JiraSearchResult
+	for {
+		pageSize := jiraSearchPageSize
+		if limit > 0 && limit-len(result.Issues) < pageSize {
+			pageSize = limit - len(result.Issues)
+		}
+		pageOptions := jira.SearchOptions{StartAt: len(result.Issues), MaxResults: pageSize}
+		if options != nil {
+			pageOptions.Fields = options.Fields
+			pageOptions.Expand = options.Expand
+			pageOptions.ValidateQuery = options.ValidateQuery
+		}
+
+		issues, response, err := searcher.Search(jql, &pageOptions)
+		if err != nil {
+			return result, err
+		}
+		result.Issues = append(result.Issues, issues...)
+		result.Total = len(result.Issues)
+		if response != nil && response.Total > result.Total {
+			result.Total = response.Total
+		}
+		if len(issues) == 0 || len(result.Issues) >= result.Total || (limit > 0 && len(result.Issues) >= limit) {
+			return result, nil
+		}
 	}
-	return jira.NewClient(tp.Client(), JiraBaseURL)
 }
 
-func GetJiraIssuesForCluster(clusterID string, externalClusterID string) ([]jira.Issue, error) {
+// GetJiraIssuesForCluster returns the OHSS issues of the cluster, newest first, up to limit issues or
+// all of them when limit isn't positive
+func GetJiraIssuesForCluster(clusterID string, externalClusterID string, limit int) (JiraSearchResult, error) {
 	jiraClient, err := GetJiraClient()
 	if err != nil {
-		return nil, fmt.Errorf("error connecting to jira: %v", err)
+		return JiraSearchResult{}, fmt.Errorf("error connecting to jira: %v", err)
 	}
 
-	jql := fmt.Sprintf(
-		`(project = "OpenShift Hosted SRE Support" AND "Cluster ID" ~ "%s") 
-		OR (project = "OpenShift Hosted SRE Support" AND "Cluster ID" ~ "%s") 
-		ORDER BY created DESC`,
-		externalClusterID,
-		clusterID,
-	)
+	// The comments are needed for the digest of each card
+	result, err := SearchJiraIssues(jiraClient.Issue, jiraClusterIssuesJQL(clusterID, externalClusterID), &jira.SearchOptions{Fields: []string{"*navigable", "comment"}}, limit)
+	if err != nil {
+		return JiraSearchResult{}, fmt.Errorf("failed to search for jira issues: %w\n", err)
+	}
+
+	return result, nil
+}
+
+// CountJiraIssuesForCluster returns the number of OHSS issues of the cluster, from the total of a search
+// fetching a single issue without its fields
+func CountJiraIssuesForCluster(clusterID string, externalClusterID string) (int, error) {
+	jiraClient, err := GetJiraClient()
+	if err != nil {
+		return 0, fmt.Errorf("error connecting to jira: %v", err)
+	}
 
-	issues, _, err := jiraClient.Issue.Search(jql, nil)
+	result, err := SearchJiraIssues(jiraClient.Issue, jiraClusterIssuesJQL(clusterID, externalClusterID), &jira.SearchOptions{Fields: []string{"key"}}, 1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search for jira issues: %w\n", err)
+		return 0, fmt.Errorf("failed to count jira issues: %w", err)
 	}
 
-	return issues, nil
+	return result.Total, nil
+}
+
+func jiraClusterIssuesJQL(clusterID string, externalClusterID string) string {
+	return fmt.Sprintf(
+		`(project = "%[1]s" AND "Cluster ID" ~ "%[2]s") 
+		OR (project = "%[1]s" AND "Cluster ID" ~ "%[3]s") 
+		ORDER BY created DESC`,
+		JiraProject(JiraOHSSProject),
+		externalClusterID,
+		clusterID,
+	)
 }
 
-func GetJiraSupportExceptionsForOrg(organizationID string) ([]jira.Issue, error) {
+// GetJiraSupportExceptionsForOrg returns the approved support exceptions of the organization, up to
+// limit issues or all of them when limit isn't positive
+func GetJiraSupportExceptionsForOrg(organizationID string, limit int) (JiraSearchResult, error) {
 	jiraClient, err := GetJiraClient()
 	if err != nil {
-		return nil, fmt.Errorf("error connecting to jira: %v", err)
+		return JiraSearchResult{}, fmt.Errorf("error connecting to jira: %v", err)
 	}
 
 	jql := fmt.Sprintf(
-		`project = "Support Exceptions" AND type = Story AND Status = Approved AND
-		 Resolution = Unresolved AND ("Customer Name" ~ "%[1]s" OR "Organization ID" ~ "%[1]s")`,
+		`project = "%[1]s" AND type = Story AND Status = Approved AND
+		 Resolution = Unresolved AND ("Customer Name" ~ "%[2]s" OR "Organization ID" ~ "%[2]s")`,
+		JiraProject(JiraSupportExceptionsProject),
 		organizationID,
 	)
 
-	issues, _, err := jiraClient.Issue.Search(jql, nil)
+	result, err := SearchJiraIssues(jiraClient.Issue, jql, nil, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search for jira issues %w", err)
+		return JiraSearchResult{}, fmt.Errorf("failed to search for jira issues %w", err)
 	}
 
-	return issues, nil
+	return result, nil
 }
 
 func CreateIssue(
@@ -108,3 +273,89 @@ func CreateIssue(
 
 	return createdIssue, nil
 }
+
+// JiraCommentDigest summarizes the comments of a card, to tell whether it waits on SRE or on the customer
+type JiraCommentDigest struct {
+	Total int
+	// SinceSRE is the number of comments after the last one made by an SRE, all of them if none was
+	SinceSRE      int
+	LatestAuthor  string
+	LatestCreated time.Time
+	LatestSnippet string
+	LatestBySRE   bool
+}
+
+// WaitingOn returns who is expected to answer the card next
+func (d JiraCommentDigest) WaitingOn() string {
+	switch {
+	case d.Total == 0:
+		return "SRE"
+	case d.LatestBySRE:
+		return "customer"
+	}
+	return "SRE"
+}
+
+// DigestJiraComments builds the comment digest of the issue. A comment is considered made by an SRE if
+// its author is the assignee of the card or one of sreMembers, or if its visibility is restricted.
+func DigestJiraComments(issue jira.Issue, sreMembers []string) JiraCommentDigest {
+	digest := JiraCommentDigest{}
+	if issue.Fields == nil || issue.Fields.Comments == nil || len(issue.Fields.Comments.Comments) == 0 {
+		return digest
+	}
+
+	isSRE := func(comment *jira.Comment) bool {
+		if comment.Visibility.Type != "" {
+			return true
+		}
+		if assignee := issue.Fields.Assignee; assignee != nil && assignee.Name != "" && assignee.Name == comment.Author.Name {
+			return true
+		}
+		for _, member := range sreMembers {
+			if strings.EqualFold(member, comment.Author.Name) || strings.EqualFold(member, comment.Author.EmailAddress) {
+				return true
+			}
+		}
+		return false
+	}
+
+	comments := issue.Fields.Comments.Comments
+	digest.Total = len(comments)
+	for _, comment := range comments {
+		if comment == nil {
+			continue
+		}
+		if isSRE(comment) {
+			digest.SinceSRE = 0
+		} else {
+			digest.SinceSRE++
+		}
+	}
+
+	latest := comments[len(comments)-1]
+	if latest != nil {
+		digest.LatestAuthor = latest.Author.DisplayName
+		if digest.LatestAuthor == "" {
+			digest.LatestAuthor = latest.Author.Name
+		}
+		digest.LatestCreated, _ = time.Parse(jiraCommentTimeLayout, latest.Created)
+		digest.LatestSnippet = commentSnippet(latest.Body)
+		digest.LatestBySRE = isSRE(latest)
+	}
+	return digest
+}
+
+// commentSnippet returns the first non empty line of the comment, shortened
+func commentSnippet(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > jiraCommentSnippetSize {
+			return string(runes[:jiraCommentSnippetSize]) + "..."
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/spf13/viper"
+)
+
+func jiraComment(author string, body string, created string) *jira.Comment {
+	return &jira.Comment{Author: jira.User{Name: author, DisplayName: strings.ToUpper(author)}, Body: body, Created: created}
+}
+
+func TestDigestJiraComments(t *testing.T) {
+	sreMembers := []string{"sre1"}
+
+	tests := []struct {
+		name         string
+		comments     []*jira.Comment
+		wantSinceSRE int
+		wantWaiting  string
+		wantAuthor   string
+		wantSnippet  string
+	}{
+		{
+			name:        "no comments",
+			wantWaiting: "SRE",
+		},
+		{
+			name: "SRE answered last",
+			comments: []*jira.Comment{
+				jiraComment("customer", "It's broken", "2024-03-01T10:00:00.000+0000"),
+				jiraComment("sre1", "\n\nWe are looking into it\nmore details", "2024-03-01T11:00:00.000+0000"),
+			},
+			wantSinceSRE: 0,
+			wantWaiting:  "customer",
+			wantAuthor:   "SRE1",
+			wantSnippet:  "We are looking into it",
+		},
+		{
+			name: "customer replied twice",
+			comments: []*jira.Comment{
+				jiraComment("assignee", "Can you share the logs?", "2024-03-01T10:00:00.000+0000"),
+				jiraComment("customer", "Here they are", "2024-03-01T11:00:00.000+0000"),
+				jiraComment("customer", strings.Repeat("a", 150), "2024-03-01T12:00:00.000+0000"),
+			},
+			wantSinceSRE: 2,
+			wantWaiting:  "SRE",
+			wantAuthor:   "CUSTOMER",
+			wantSnippet:  strings.Repeat("a", 100) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := jira.Issue{Fields: &jira.IssueFields{
+				Assignee: &jira.User{Name: "assignee"},
+				Comments: &jira.Comments{Comments: tt.comments},
+			}}
+			digest := DigestJiraComments(issue, sreMembers)
+
+			if digest.Total != len(tt.comments) {
+				t.Errorf("Total = %d, want %d", digest.Total, len(tt.comments))
+			}
+			if digest.SinceSRE != tt.wantSinceSRE {
+				t.Errorf("SinceSRE = %d, want %d", digest.SinceSRE, tt.wantSinceSRE)
+			}
+			if digest.WaitingOn() != tt.wantWaiting {
+				t.Errorf("WaitingOn() = %s, want %s", digest.WaitingOn(), tt.wantWaiting)
+			}
+			if digest.LatestAuthor != tt.wantAuthor {
+				t.Errorf("LatestAuthor = %s, want %s", digest.LatestAuthor, tt.wantAuthor)
+			}
+			if digest.LatestSnippet != tt.wantSnippet {
+				t.Errorf("LatestSnippet = %s, want %s", digest.LatestSnippet, tt.wantSnippet)
+			}
+			if len(tt.comments) > 0 && digest.LatestCreated.IsZero() {
+				t.Errorf("LatestCreated wasn't parsed")
+			}
+		})
+	}
+}
+
+func TestJiraHTTPClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     string
+		username string
+		want     string
+		wantErr  bool
+	}{
+		{name: "default to PAT", want: "Bearer token"},
+		{name: "PAT", auth: JiraAuthPAT, want: "Bearer token"},
+		{name: "OAuth", auth: JiraAuthOAuth, want: "Bearer token"},
+		{name: "basic", auth: JiraAuthBasic, username: "me@example.com", want: "Basic bWVAZXhhbXBsZS5jb206dG9rZW4="},
+		{name: "basic without username", auth: JiraAuthBasic, wantErr: true},
+		{name: "unknown", auth: "kerberos", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+			}))
+			defer server.Close()
+
+			client, err := jiraHTTPClient(tt.auth, tt.username, "token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("jiraHTTPClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			response, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = response.Body.Close()
+			if got != tt.want {
+				t.Errorf("Authorization = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJiraConfigOverrides(t *testing.T) {
+	t.Setenv("JIRA_BASE_URL", "")
+	defer viper.Reset()
+
+	if got := JiraBrowseURL("OHSS-1"); got != "https://issues.redhat.com/browse/OHSS-1" {
+		t.Errorf("JiraBrowseURL() = %s", got)
+	}
+	if got := JiraProject(JiraOHSSProject); got != JiraOHSSProject {
+		t.Errorf("JiraProject() = %s, want %s", got, JiraOHSSProject)
+	}
+
+	viper.Set(JiraBaseURLConfigKey, "https://example.atlassian.net/")
+	viper.Set(JiraProjectsConfigKey, map[string]string{"ohss": "SUP", "support exceptions": "EXC"})
+	if got := JiraBrowseURL("SUP-1"); got != "https://example.atlassian.net/browse/SUP-1" {
+		t.Errorf("JiraBrowseURL() = %s", got)
+	}
+	if got := JiraProject(JiraOHSSProject); got != "SUP" {
+		t.Errorf("JiraProject() = %s, want SUP", got)
+	}
+	if got := JiraProject(JiraSupportExceptionsProject); got != "EXC" {
+		t.Errorf("JiraProject() = %s, want EXC", got)
+	}
+	if got := JiraProject("OSD"); got != "OSD" {
+		t.Errorf("JiraProject() = %s, want OSD", got)
+	}
+}
+
+// pagedSearcher serves total issues, at most maxResults of them per page like a Jira instance capping the page size
+type pagedSearcher struct {
+	total      int
+	maxResults int
+	requests   []jira.SearchOptions
+}
+
+func (s *pagedSearcher) Search(jql string, options *jira.SearchOptions) ([]jira.Issue, *jira.Response, error) {
+	s.requests = append(s.requests, *options)
+	size := options.MaxResults
+	if size > s.maxResults {
+		size = s.maxResults
+	}
+	var issues []jira.Issue
+	for i := options.StartAt; i < options.StartAt+size && i < s.total; i++ {
+		issues = append(issues, jira.Issue{Key: fmt.Sprintf("OHSS-%d", i+1)})
+	}
+	return issues, &jira.Response{StartAt: options.StartAt, MaxResults: size, Total: s.total}, nil
+}
+
+func TestSearchJiraIssues(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int
+		maxResults   int
+		limit        int
+		wantIssues   int
+		wantRequests int
+		wantTrunc    bool
+	}{
+		{name: "single page", total: 12, maxResults: 100, limit: 50, wantIssues: 12, wantRequests: 1},
+		{name: "limited", total: 312, maxResults: 100, limit: 50, wantIssues: 50, wantRequests: 1, wantTrunc: true},
+		{name: "all", total: 312, maxResults: 100, limit: 0, wantIssues: 312, wantRequests: 4},
+		{name: "page size capped by the server", total: 120, maxResults: 50, limit: 0, wantIssues: 120, wantRequests: 3},
+		{name: "limit over several pages", total: 312, maxResults: 100, limit: 150, wantIssues: 150, wantRequests: 2, wantTrunc: true},
+		{name: "no issues", total: 0, maxResults: 100, limit: 0, wantIssues: 0, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := &pagedSearcher{total: tt.total, maxResults: tt.maxResults}
+			result, err := SearchJiraIssues(searcher, "project = OHSS", &jira.SearchOptions{Fields: []string{"comment"}}, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Issues) != tt.wantIssues || result.Total != tt.total || result.Truncated() != tt.wantTrunc {
+				t.Errorf("SearchJiraIssues() = %d issues of %d, truncated %v", len(result.Issues), result.Total, result.Truncated())
+			}
+			if len(searcher.requests) != tt.wantRequests {
+				t.Errorf("SearchJiraIssues() sent %d requests, want %d", len(searcher.requests), tt.wantRequests)
+			}
+			for i, issue := range result.Issues {
+				if issue.Key != fmt.Sprintf("OHSS-%d", i+1) {
+					t.Fatalf("issue %d is %s, the pages overlap or skip issues", i, issue.Key)
+				}
+			}
+			if searcher.requests[0].Fields[0] != "comment" {
+				t.Errorf("the fields of the options were not requested")
+			}
+		})
+	}
+}
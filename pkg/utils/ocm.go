@@ -5,15 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
-	"github.com/google/uuid"
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/osdctl/pkg/httpdebug"
+	"github.com/openshift/osdctl/pkg/interrupt"
+	"github.com/openshift/osdctl/pkg/rawdump"
+	"github.com/openshift/osdctl/pkg/redact"
 )
 
 const ClusterServiceClusterSearch = "id = '%s' or name = '%s' or external_id = '%s'"
@@ -60,35 +63,50 @@ type Config struct {
 }
 
 // GetClusterAnyStatus returns an OCM cluster object given an OCM connection and cluster id
-// (internal id, external id, and name all supported).
+// (internal id, external id, and name all supported). External UUIDs are resolved like GetCluster does,
+// including the clusters which only report their external ID to the accounts management service.
 func GetClusterAnyStatus(conn *sdk.Connection, clusterId string) (*cmv1.Cluster, error) {
+	if ClassifyClusterIdentifier(clusterId) == ClusterIdentifierExternalID {
+		return GetClusterByExternalID(conn, clusterId)
+	}
+
 	// identifier in the accounts management service. To find those clusters we need to check
 	// directly in the clusters management service.
 	clustersSearch := fmt.Sprintf(ClusterServiceClusterSearch, clusterId, clusterId, clusterId)
-	clustersListResponse, err := conn.ClustersMgmt().V1().Clusters().List().Search(clustersSearch).Size(1).Send()
+	clusters, err := searchClusters(conn, clustersSearch)
 	if err != nil {
-		return nil, fmt.Errorf("can't retrieve clusters for clusterId '%s': %v", clusterId, err)
+		return nil, err
 	}
 
 	// If there is exactly one cluster matching then return it:
-	clustersTotal := clustersListResponse.Total()
-	if clustersTotal == 1 {
-		return clustersListResponse.Items().Slice()[0], nil
+	clusters = PreferExactMatches(clusterId, clusters)
+	switch len(clusters) {
+	case 0:
+		return nil, fmt.Errorf("there are no clusters with identifier or name '%s'", clusterId)
+	case 1:
+		return clusters[0], nil
+	default:
+		return nil, NewAmbiguousClusterError(clusterId, clusters)
 	}
-
-	return nil, fmt.Errorf("there are %d clusters with identifier or name '%s', expected 1", clustersTotal, clusterId)
 }
 
+// GetClusters returns the clusters matching any of the given identifiers. When a single identifier is
+// given and it matches several clusters by name, only the exact matches are returned.
 func GetClusters(ocmClient *sdk.Connection, clusterIds []string) []*cmv1.Cluster {
+	queries := make([]string, len(clusterIds))
 	for i, id := range clusterIds {
-		clusterIds[i] = GenerateQuery(id)
+		queries[i] = GenerateQuery(id)
 	}
 
-	clusters, err := ApplyFilters(ocmClient, []string{strings.Join(clusterIds, " or ")})
+	clusters, err := ApplyFilters(ocmClient, []string{strings.Join(queries, " or ")})
 	if err != nil {
 		log.Fatalf("error while retrieving cluster(s) from ocm: %[1]s", err)
 	}
 
+	if len(clusterIds) == 1 {
+		clusters = PreferExactMatches(clusterIds[0], clusters)
+	}
+
 	return clusters
 }
 
@@ -111,6 +129,16 @@ func GetOrgfromClusterID(ocmClient *sdk.Connection, cluster cmv1.Cluster) (strin
 	return respSlice[0].OrganizationID(), nil
 }
 
+// GetOrgAccountNumber returns the customer account number (EBS account ID) of an organization, which the
+// Customer Portal support cases are filed under
+func GetOrgAccountNumber(ocmClient *sdk.Connection, orgID string) (string, error) {
+	response, err := ocmClient.AccountsMgmt().V1().Organizations().Organization(orgID).Get().Send()
+	if err != nil {
+		return "", fmt.Errorf("failed to get organization %s: %w", orgID, err)
+	}
+	return response.Body().EbsAccountID(), nil
+}
+
 // ApplyFilters retrieves clusters in OCM which match the filters given
 func ApplyFilters(ocmClient *sdk.Connection, filters []string) ([]*cmv1.Cluster, error) {
 	if len(filters) < 1 {
@@ -146,11 +174,14 @@ func ApplyFilters(ocmClient *sdk.Connection, filters []string) ([]*cmv1.Cluster,
 // GenerateQuery returns an OCM search query to retrieve all clusters matching an expression (ie- "foo%")
 func GenerateQuery(clusterIdentifier string) string {
 	// Based on the format of the clusterIdentifier, we can know what it is, so we can simplify ocm query and make it quicker
-	if regexp.MustCompile(`^[0-9a-z]{32}$`).MatchString(clusterIdentifier) {
+	switch ClassifyClusterIdentifier(clusterIdentifier) {
+	case ClusterIdentifierInternalID:
 		return strings.TrimSpace(fmt.Sprintf("(id = '%[1]s')", clusterIdentifier))
-	} else if _, err := uuid.Parse(clusterIdentifier); err == nil {
+	case ClusterIdentifierExternalID:
 		return strings.TrimSpace(fmt.Sprintf("(external_id = '%[1]s')", clusterIdentifier))
-	} else {
+	case ClusterIdentifierSubscriptionID:
+		return strings.TrimSpace(fmt.Sprintf("(subscription.id = '%[1]s' or display_name = '%[1]s')", clusterIdentifier))
+	default:
 		return strings.TrimSpace(fmt.Sprintf("(display_name like '%[1]s')", clusterIdentifier))
 	}
 }
@@ -270,6 +301,14 @@ func CreateConnection() (*sdk.Connection, error) {
 		return nil, errors.New(ocmConfigError)
 	}
 
+	// Long-running modes share the tokens kept fresh by the OCM watchdog
+	if access, refresh := watchdogTokens(); access != "" {
+		config.AccessToken = access
+		config.RefreshToken = refresh
+	}
+
+	// The tokens must not show up in the debug output, e.g. in the errors of the token exchanges
+	redact.RegisterSecrets(config.AccessToken, config.RefreshToken, config.ClientSecret, config.Password)
 	connectionBuilder.Tokens(config.AccessToken, config.RefreshToken)
 
 	if config.URL == "" {
@@ -285,6 +324,10 @@ func CreateConnection() (*sdk.Connection, error) {
 
 	connectionBuilder.Client(config.ClientID, config.ClientSecret)
 
+	connectionBuilder.TransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+		return interrupt.Wrap(httpdebug.Wrap(rawdump.Wrap(LimitOCM(next))))
+	})
+
 	connection, err := connectionBuilder.Build()
 
 	if err != nil {
@@ -165,6 +165,11 @@ func TestGenerateQuery(t *testing.T) {
 			clusterIdentifier: "c1f562af-fb22-42c5-aa07-6848e1eeee9c",
 			want:              "(external_id = 'c1f562af-fb22-42c5-aa07-6848e1eeee9c')",
 		},
+		{
+			name:              "valid subscription ID",
+			clusterIdentifier: "2aBcDeFgHiJkLmNoPqRsTuVwXyZ",
+			want:              "(subscription.id = '2aBcDeFgHiJkLmNoPqRsTuVwXyZ' or display_name = '2aBcDeFgHiJkLmNoPqRsTuVwXyZ')",
+		},
 		{
 			name:              "valid display name",
 			clusterIdentifier: "hs-mc-773jpgko0",
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// OCMWatchdogInterval is how often the watchdog checks the OCM tokens. The access tokens are valid
+	// for 15 minutes, they're refreshed when they expire within OCMWatchdogRefreshBefore.
+	OCMWatchdogInterval      = time.Minute
+	OCMWatchdogRefreshBefore = 5 * time.Minute
+
+	ocmWatchdogAttempts      = 5
+	ocmWatchdogRetryDelay    = 2 * time.Second
+	ocmWatchdogMaxRetryDelay = time.Minute
+)
+
+// ocmTokenSource is the part of the OCM connection the watchdog uses, *sdk.Connection implements it
+type ocmTokenSource interface {
+	TokensContext(ctx context.Context, expiresIn ...time.Duration) (access string, refresh string, err error)
+	Close() error
+}
+
+// OCMWatchdog keeps the OCM tokens of long-running modes, such as 'osdctl serve', fresh. The tokens are
+// refreshed ahead of their expiry and shared with the connections created afterwards by CreateConnection,
+// so the session doesn't silently start returning authentication errors. When the refresh fails the
+// connection is re-established from the OCM config and environment, e.g. after an 'ocm login', with
+// jittered exponential retries.
+type OCMWatchdog struct {
+	// Interval is how often the tokens are checked
+	Interval time.Duration
+	// RefreshBefore is how long before their expiry the tokens are refreshed
+	RefreshBefore time.Duration
+
+	connect func() (ocmTokenSource, error)
+	sleep   func(ctx context.Context, d time.Duration) error
+
+	mutex   sync.RWMutex
+	access  string
+	refresh string
+	lastErr error
+}
+
+var (
+	activeOCMWatchdogMutex sync.RWMutex
+	activeOCMWatchdog      *OCMWatchdog
+)
+
+// NewOCMWatchdog returns a watchdog checking the OCM tokens every OCMWatchdogInterval
+func NewOCMWatchdog() *OCMWatchdog {
+	return &OCMWatchdog{
+		Interval:      OCMWatchdogInterval,
+		RefreshBefore: OCMWatchdogRefreshBefore,
+		connect: func() (ocmTokenSource, error) {
+			return CreateConnection()
+		},
+		sleep: sleepContext,
+	}
+}
+
+// Start refreshes the tokens once, so a session starting with invalid credentials fails right away, then
+// keeps them fresh in the background until the context is done. The connections created by
+// CreateConnection use the refreshed tokens from then on.
+func (w *OCMWatchdog) Start(ctx context.Context) error {
+	if err := w.check(ctx); err != nil {
+		return err
+	}
+	activeOCMWatchdogMutex.Lock()
+	activeOCMWatchdog = w
+	activeOCMWatchdogMutex.Unlock()
+
+	go func() {
+		defer func() {
+			activeOCMWatchdogMutex.Lock()
+			if activeOCMWatchdog == w {
+				activeOCMWatchdog = nil
+			}
+			activeOCMWatchdogMutex.Unlock()
+		}()
+		for {
+			if err := w.sleep(ctx, wait.Jitter(w.Interval, 0.2)); err != nil {
+				return
+			}
+			if err := w.check(ctx); err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Warning: the OCM tokens couldn't be refreshed, the OCM requests will fail until they are: %v\n", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Err returns the error of the last check of the tokens, nil when they're fresh
+func (w *OCMWatchdog) Err() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.lastErr
+}
+
+// check refreshes the tokens, retrying with a jittered exponential delay. After a failed attempt the
+// tokens are reloaded from the OCM config and environment instead of using the last refreshed ones.
+func (w *OCMWatchdog) check(ctx context.Context) error {
+	delay := ocmWatchdogRetryDelay
+	var err error
+	for attempt := 1; attempt <= ocmWatchdogAttempts; attempt++ {
+		if err = w.refreshTokens(ctx); err == nil {
+			break
+		}
+		w.setTokens("", "")
+		if attempt == ocmWatchdogAttempts {
+			break
+		}
+		if sleepErr := w.sleep(ctx, wait.Jitter(delay, 1)); sleepErr != nil {
+			err = sleepErr
+			break
+		}
+		delay *= 2
+		if delay > ocmWatchdogMaxRetryDelay {
+			delay = ocmWatchdogMaxRetryDelay
+		}
+	}
+
+	w.mutex.Lock()
+	w.lastErr = err
+	w.mutex.Unlock()
+	return err
+}
+
+// refreshTokens connects with the current tokens and refreshes them when they expire soon
+func (w *OCMWatchdog) refreshTokens(ctx context.Context) error {
+	connection, err := w.connect()
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	access, refresh, err := connection.TokensContext(ctx, w.RefreshBefore)
+	if err != nil {
+		return fmt.Errorf("failed to refresh the OCM tokens: %w", err)
+	}
+	w.setTokens(access, refresh)
+	return nil
+}
+
+func (w *OCMWatchdog) setTokens(access string, refresh string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.access = access
+	w.refresh = refresh
+}
+
+func (w *OCMWatchdog) tokens() (access string, refresh string) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.access, w.refresh
+}
+
+// watchdogTokens returns the tokens refreshed by the running watchdog, if any
+func watchdogTokens() (access string, refresh string) {
+	activeOCMWatchdogMutex.RLock()
+	defer activeOCMWatchdogMutex.RUnlock()
+	if activeOCMWatchdog == nil {
+		return "", ""
+	}
+	return activeOCMWatchdog.tokens()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
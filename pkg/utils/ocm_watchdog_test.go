@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeTokenSource struct {
+	access  string
+	refresh string
+	err     error
+}
+
+func (f *fakeTokenSource) TokensContext(ctx context.Context, expiresIn ...time.Duration) (string, string, error) {
+	return f.access, f.refresh, f.err
+}
+
+func (f *fakeTokenSource) Close() error {
+	return nil
+}
+
+func TestOCMWatchdogCheck(t *testing.T) {
+	refreshErr := errors.New("invalid_grant")
+	tests := []struct {
+		name        string
+		results     []error
+		wantErr     bool
+		wantAccess  string
+		wantSleeps  int
+		wantConnect int
+	}{
+		{
+			name:        "fresh tokens are shared",
+			results:     []error{nil},
+			wantAccess:  "access",
+			wantConnect: 1,
+		},
+		{
+			name:        "retries until the connection is re-established",
+			results:     []error{refreshErr, refreshErr, nil},
+			wantAccess:  "access",
+			wantSleeps:  2,
+			wantConnect: 3,
+		},
+		{
+			name:        "gives up after the last attempt and drops the tokens",
+			results:     []error{refreshErr, refreshErr, refreshErr, refreshErr, refreshErr},
+			wantErr:     true,
+			wantSleeps:  ocmWatchdogAttempts - 1,
+			wantConnect: ocmWatchdogAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var connects, sleeps int
+			w := NewOCMWatchdog()
+			w.setTokens("stale", "stale")
+			w.connect = func() (ocmTokenSource, error) {
+				err := tt.results[connects]
+				connects++
+				return &fakeTokenSource{access: "access", refresh: "refresh", err: err}, nil
+			}
+			w.sleep = func(ctx context.Context, d time.Duration) error {
+				sleeps++
+				return nil
+			}
+
+			err := w.check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (w.Err() != nil) != tt.wantErr {
+				t.Errorf("Err() = %v, wantErr %v", w.Err(), tt.wantErr)
+			}
+			if access, _ := w.tokens(); access != tt.wantAccess {
+				t.Errorf("access token = %q, want %q", access, tt.wantAccess)
+			}
+			if connects != tt.wantConnect {
+				t.Errorf("connected %d times, want %d", connects, tt.wantConnect)
+			}
+			if sleeps != tt.wantSleeps {
+				t.Errorf("slept %d times, want %d", sleeps, tt.wantSleeps)
+			}
+		})
+	}
+}
+
+func TestOCMWatchdogCheckCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := NewOCMWatchdog()
+	w.connect = func() (ocmTokenSource, error) {
+		return nil, errors.New("connection refused")
+	}
+	if err := w.check(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("check() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -9,8 +9,10 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
 	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/viper"
 	"math"
 	"os"
+	"sort"
 	"strings"
 	"time"
 )
@@ -19,7 +21,53 @@ const (
 	delimiter = ">> "
 )
 
-func PrintServiceLogs(serviceLogs []*v1.LogEntry, verbose bool, sinceDays int) {
+// ServiceLogGroup is a set of service logs sent from the same template, they share their summary
+type ServiceLogGroup struct {
+	Summary string
+	Count   int
+	Latest  *v1.LogEntry
+}
+
+// serviceLogSummary returns the summary of a service log, or the first line of its description for
+// internal service logs since those all share the same summary
+func serviceLogSummary(serviceLog *v1.LogEntry) string {
+	if serviceLog.InternalOnly() {
+		internalServiceLogLines := strings.Split(serviceLog.Description(), "\n")
+		if len(internalServiceLogLines) > 0 {
+			// if the description is "", Split returns []
+			return fmt.Sprintf("INT %s", internalServiceLogLines[0])
+		}
+	}
+	return serviceLog.Summary()
+}
+
+// GroupServiceLogs groups the service logs by summary, the groups are ordered by their latest service log,
+// newest first
+func GroupServiceLogs(serviceLogs []*v1.LogEntry) []ServiceLogGroup {
+	var groups []ServiceLogGroup
+	index := map[string]int{}
+	for _, serviceLog := range serviceLogs {
+		summary := serviceLogSummary(serviceLog)
+		i, ok := index[summary]
+		if !ok {
+			index[summary] = len(groups)
+			groups = append(groups, ServiceLogGroup{Summary: summary, Count: 1, Latest: serviceLog})
+			continue
+		}
+		groups[i].Count++
+		if serviceLog.CreatedAt().After(groups[i].Latest.CreatedAt()) {
+			groups[i].Latest = serviceLog
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Latest.CreatedAt().After(groups[j].Latest.CreatedAt())
+	})
+	return groups
+}
+
+// PrintServiceLogs prints the summaries of the service logs. Unless ungrouped is set, service logs sent from
+// the same template are printed once, with their count and the date of the most recent one.
+func PrintServiceLogs(serviceLogs []*v1.LogEntry, verbose bool, ungrouped bool, sinceDays int) {
 	var name = fmt.Sprintf("Service Logs in the past %v days", sinceDays)
 	fmt.Println(delimiter + name)
 
@@ -31,28 +79,27 @@ func PrintServiceLogs(serviceLogs []*v1.LogEntry, verbose bool, sinceDays int) {
 		_ = dump.Pretty(os.Stdout, marshalledSLs)
 	} else if len(serviceLogs) == 0 {
 		fmt.Println("None")
-	} else {
+	} else if ungrouped {
 		// Non-verbose only prints the summaries
 		for i, errorServiceLog := range serviceLogs {
-			var serviceLogSummary string
-			if errorServiceLog.InternalOnly() {
-				internalServiceLogLines := strings.Split(errorServiceLog.Description(), "\n")
-				if len(internalServiceLogLines) > 0 {
-					// if the description is "", Split returns []
-					serviceLogSummary = fmt.Sprintf("INT %s", internalServiceLogLines[0])
-				} else {
-					serviceLogSummary = errorServiceLog.Summary()
-				}
-			} else {
-				serviceLogSummary = errorServiceLog.Summary()
-			}
-			serviceLogSummaryAbbreviated := serviceLogSummary[:int(math.Min(40, float64(len(serviceLogSummary))))]
+			summary := serviceLogSummary(errorServiceLog)
+			serviceLogSummaryAbbreviated := summary[:int(math.Min(40, float64(len(summary))))]
 			fmt.Printf("%d. %s (%s)\n", i, serviceLogSummaryAbbreviated, errorServiceLog.CreatedAt().Format(time.RFC3339))
 		}
+	} else {
+		for i, group := range GroupServiceLogs(serviceLogs) {
+			serviceLogSummaryAbbreviated := group.Summary[:int(math.Min(40, float64(len(group.Summary))))]
+			count := ""
+			if group.Count > 1 {
+				count = fmt.Sprintf(" \u00d7%d", group.Count)
+			}
+			fmt.Printf("%d. %s%s (%s)\n", i, serviceLogSummaryAbbreviated, count, group.Latest.CreatedAt().Format(time.RFC3339))
+		}
 	}
 }
 
-func PrintPDAlerts(incidents map[string][]pd.Incident, serviceIDs []string) {
+// PrintPDAlerts prints the incidents of each PagerDuty service. wide adds the status and the URL of the incidents.
+func PrintPDAlerts(incidents map[string][]pd.Incident, serviceIDs []string, tableOptions printer.TableOptions, wide bool) {
 	var name = "PagerDuty Alerts"
 	fmt.Println(delimiter + name)
 
@@ -65,10 +112,18 @@ func PrintPDAlerts(incidents map[string][]pd.Incident, serviceIDs []string) {
 		fmt.Printf("Service: https://redhat.pagerduty.com/service-directory/%s\n", ID)
 
 		tableHasContent := false
-		table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ')
-		table.AddRow([]string{"Urgency", "Title", "Created At"})
+		table := printer.NewTablePrinter(os.Stdout, 20, 1, 3, ' ').WithTableOptions(tableOptions)
+		if wide {
+			table.AddRow([]string{"Urgency", "ID", "Status", "Title", "Created At", "URL"})
+		} else {
+			table.AddRow([]string{"Urgency", "ID", "Title", "Created At"})
+		}
 		for _, incident := range incidents[ID] {
-			table.AddRow([]string{incident.Urgency, incident.Title, incident.CreatedAt})
+			if wide {
+				table.AddRow([]string{incident.Urgency, incident.ID, incident.Status, incident.Title, incident.CreatedAt, incident.HTMLURL})
+			} else {
+				table.AddRow([]string{incident.Urgency, incident.ID, incident.Title, incident.CreatedAt})
+			}
 			tableHasContent = true
 		}
 		if tableHasContent {
@@ -87,9 +142,14 @@ func PrintJiraIssues(issues []jira.Issue) {
 	var name = "OHSS Issues"
 	fmt.Println(delimiter + name)
 
+	sreMembers := viper.GetStringSlice(JiraTeamMembersConfigKey)
 	for _, i := range issues {
-		fmt.Printf("[%s|%s/browse/%s](%s/%s): %+v\n", i.Key, JiraBaseURL, i.Key, i.Fields.Type.Name, i.Fields.Priority.Name, i.Fields.Summary)
+		fmt.Printf("[%s|%s](%s/%s): %+v\n", i.Key, JiraBrowseURL(i.Key), i.Fields.Type.Name, i.Fields.Priority.Name, i.Fields.Summary)
 		fmt.Printf("- Created: %s\tStatus: %s\n", time.Time(i.Fields.Created).Format("2006-01-02 15:04"), i.Fields.Status.Name)
+		if digest := DigestJiraComments(i, sreMembers); digest.Total > 0 {
+			fmt.Printf("- Comments: %d, %d since the last SRE update, waiting on %s\n", digest.Total, digest.SinceSRE, digest.WaitingOn())
+			fmt.Printf("- Latest: %s (%s): %s\n", digest.LatestAuthor, digest.LatestCreated.Format("2006-01-02 15:04"), digest.LatestSnippet)
+		}
 	}
 
 	if len(issues) == 0 {
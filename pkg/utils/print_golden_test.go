@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+
+	pd "github.com/PagerDuty/go-pagerduty"
+	"github.com/andygrunwald/go-jira"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/osdctl/internal/testutil/fixtures"
+	"github.com/openshift/osdctl/internal/testutil/golden"
+	"github.com/openshift/osdctl/pkg/printer"
+	"github.com/spf13/viper"
+)
+
+func TestPrintServiceLogsGolden(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceLogs []*v1.LogEntry
+		ungrouped   bool
+	}{
+		{name: "service_logs_grouped", serviceLogs: fixtures.ServiceLogs()},
+		{name: "service_logs_ungrouped", serviceLogs: fixtures.ServiceLogs(), ungrouped: true},
+		{name: "service_logs_none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := golden.CaptureStdout(t, func() {
+				PrintServiceLogs(tt.serviceLogs, false, tt.ungrouped, 30)
+			})
+			golden.Assert(t, tt.name, got)
+		})
+	}
+}
+
+func TestPrintPDAlertsGolden(t *testing.T) {
+	tests := []struct {
+		name       string
+		incidents  map[string][]pd.Incident
+		serviceIDs []string
+		wide       bool
+	}{
+		{name: "pd_alerts", incidents: fixtures.PagerDutyIncidents(), serviceIDs: []string{fixtures.PagerDutyServiceID}},
+		{name: "pd_alerts_wide", incidents: fixtures.PagerDutyIncidents(), serviceIDs: []string{fixtures.PagerDutyServiceID}, wide: true},
+		{name: "pd_alerts_none", serviceIDs: []string{fixtures.PagerDutyServiceID}},
+		{name: "pd_alerts_no_service"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := golden.CaptureStdout(t, func() {
+				PrintPDAlerts(tt.incidents, tt.serviceIDs, printer.TableOptions{}, tt.wide)
+			})
+			golden.Assert(t, tt.name, got)
+		})
+	}
+}
+
+func TestPrintJiraIssuesGolden(t *testing.T) {
+	t.Setenv("JIRA_BASE_URL", "")
+	viper.Reset()
+	defer viper.Reset()
+
+	tests := []struct {
+		name   string
+		issues []jira.Issue
+	}{
+		{name: "jira_issues", issues: fixtures.JiraIssues()},
+		{name: "jira_issues_none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := golden.CaptureStdout(t, func() {
+				PrintJiraIssues(tt.issues)
+			})
+			golden.Assert(t, tt.name, got)
+		})
+	}
+}
+
+func TestPrintLimitedSupportReasonsGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		reasons []*cmv1.LimitedSupportReason
+	}{
+		{name: "limited_support", reasons: fixtures.LimitedSupportReasons()},
+		{name: "limited_support_none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := golden.CaptureStdout(t, func() {
+				PrintLimitedSupportReasons(tt.reasons)
+			})
+			golden.Assert(t, tt.name, got)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+func serviceLog(t *testing.T, summary string, description string, internal bool, daysAgo int) *v1.LogEntry {
+	entry, err := v1.NewLogEntry().
+		Summary(summary).
+		Description(description).
+		InternalOnly(internal).
+		CreatedAt(time.Now().AddDate(0, 0, -daysAgo)).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func TestGroupServiceLogs(t *testing.T) {
+	serviceLogs := []*v1.LogEntry{
+		serviceLog(t, "Upgrade maintenance notification", "4.14.1", false, 9),
+		serviceLog(t, "Cluster is in Limited Support", "", false, 5),
+		serviceLog(t, "Upgrade maintenance notification", "4.14.2", false, 2),
+		serviceLog(t, "Internal", "Cluster hibernated\nby the customer", true, 3),
+		serviceLog(t, "Internal", "Cluster hibernated\nagain", true, 1),
+		serviceLog(t, "Upgrade maintenance notification", "4.14.3", false, 7),
+	}
+
+	groups := GroupServiceLogs(serviceLogs)
+
+	var got []string
+	var counts []int
+	for _, group := range groups {
+		got = append(got, group.Summary)
+		counts = append(counts, group.Count)
+	}
+	if want := []string{"INT Cluster hibernated", "Upgrade maintenance notification", "Cluster is in Limited Support"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupServiceLogs() summaries = %v, want %v", got, want)
+	}
+	if want := []int{2, 3, 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("GroupServiceLogs() counts = %v, want %v", counts, want)
+	}
+	if groups[1].Latest != serviceLogs[2] {
+		t.Errorf("expected the latest upgrade notification to be kept, got %s", groups[1].Latest.Description())
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces out calls to an external API so that no more than
+// the configured number of requests per second are started.
+// A nil *RateLimiter or a limiter created with rps <= 0 never blocks.
+type RateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing up to rps calls per second
+func NewRateLimiter(rps float64) *RateLimiter {
+	if rps <= 0 {
+		return &RateLimiter{}
+	}
+	return &RateLimiter{interval: time.Duration(float64(time.Second) / rps)}
+}
+
+// Wait blocks until the caller is allowed to issue its next request
+func (r *RateLimiter) Wait() {
+	if r == nil || r.interval == 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	wait := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.mutex.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
@@ -46,98 +46,6 @@ func GetCurrentOCMEnv(connection *sdk.Connection) string {
 	return currentEnv
 }
 
-// GetCluster Function allows to get a single cluster with any identifier (displayname, ID, or external ID)
-func GetCluster(connection *sdk.Connection, key string) (cluster *cmv1.Cluster, err error) {
-	// Prepare the resources that we will be using:
-	subsResource := connection.AccountsMgmt().V1().Subscriptions()
-	clustersResource := connection.ClustersMgmt().V1().Clusters()
-
-	// Try to find a matching subscription:
-	subsSearch := fmt.Sprintf(
-		"(display_name = '%s' or cluster_id = '%s' or external_cluster_id = '%s') and "+
-			"status in ('Reserved', 'Active')",
-		key, key, key,
-	)
-	subsListResponse, err := subsResource.List().
-		Search(subsSearch).
-		Size(1).
-		Send()
-	if err != nil {
-		err = fmt.Errorf("Can't retrieve subscription for key '%s': %v", key, err)
-		return
-	}
-
-	// If there is exactly one matching subscription then return the corresponding cluster:
-	subsTotal := subsListResponse.Total()
-	if subsTotal == 1 {
-		id, ok := subsListResponse.Items().Slice()[0].GetClusterID()
-		if ok {
-			var clusterGetResponse *cmv1.ClusterGetResponse
-			clusterGetResponse, err = clustersResource.Cluster(id).Get().
-				Send()
-			if err != nil {
-				err = fmt.Errorf(
-					"Can't retrieve cluster for key '%s': %v",
-					key, err,
-				)
-				return
-			}
-			cluster = clusterGetResponse.Body()
-			return
-		}
-	}
-
-	// If there are multiple subscriptions that match the cluster then we should report it as
-	// an error:
-	if subsTotal > 1 {
-		err = fmt.Errorf(
-			"There are %d subscriptions with cluster identifier or name '%s'",
-			subsTotal, key,
-		)
-		return
-	}
-
-	// If we are here then no subscription matches the passed key. It may still be possible that
-	// the cluster exists but it is not reporting metrics, so it will not have the external
-	// identifier in the accounts management service. To find those clusters we need to check
-	// directly in the clusters management service.
-	clustersSearch := fmt.Sprintf(
-		"id = '%s' or name = '%s' or external_id = '%s'",
-		key, key, key,
-	)
-	clustersListResponse, err := clustersResource.List().
-		Search(clustersSearch).
-		Size(1).
-		Send()
-	if err != nil {
-		err = fmt.Errorf("Can't retrieve clusters for key '%s': %v", key, err)
-		return
-	}
-
-	// If there is exactly one cluster matching then return it:
-	clustersTotal := clustersListResponse.Total()
-	if clustersTotal == 1 {
-		cluster = clustersListResponse.Items().Slice()[0]
-		return
-	}
-
-	// If there are multiple matching clusters then we should report it as an error:
-	if clustersTotal > 1 {
-		err = fmt.Errorf(
-			"There are %d clusters with identifier or name '%s'",
-			clustersTotal, key,
-		)
-		return
-	}
-
-	// If we are here then there are no subscriptions or clusters matching the passed key:
-	err = fmt.Errorf(
-		"There are no subscriptions or clusters with identifier or name '%s'",
-		key,
-	)
-	return
-}
-
 func GetClusterLimitedSupportReasons(connection *sdk.Connection, clusterID string) ([]*cmv1.LimitedSupportReason, error) {
 	limitedSupportReasons, err := connection.ClustersMgmt().V1().
 		Clusters().
@@ -152,6 +60,22 @@ func GetClusterLimitedSupportReasons(connection *sdk.Connection, clusterID strin
 	return limitedSupportReasons.Items().Slice(), nil
 }
 
+// GetCustomerTerms returns the customer's organization name and the cluster creator's username, which
+// show up in the cluster description and service logs and need to be redacted on top of emails and IP addresses
+func GetCustomerTerms(ocmClient *sdk.Connection, clusterID string, organizationID string) []string {
+	var terms []string
+	if organizationID != "" {
+		response, err := ocmClient.AccountsMgmt().V1().Organizations().Organization(organizationID).Get().Send()
+		if err == nil {
+			terms = append(terms, response.Body().Name())
+		}
+	}
+	if subscription, err := GetSubscription(ocmClient, clusterID); err == nil {
+		terms = append(terms, subscription.Creator().Username())
+	}
+	return terms
+}
+
 // GetSubscription Function allows to get a single subscription with any identifier (displayname, ID, internal or external ID)
 func GetSubscription(connection *sdk.Connection, key string) (subscription *amv1.Subscription, err error) {
 	// Prepare the resources that we will be using: